@@ -178,31 +178,30 @@ var WorkflowService_RequestCancelWorkflowExecution_Helper = struct {
 	IsException func(error) bool
 
 	// WrapResponse returns the result struct for RequestCancelWorkflowExecution
-	// given the error returned by it. The provided error may
-	// be nil if RequestCancelWorkflowExecution did not fail.
+	// given its return value and error.
 	//
-	// This allows mapping errors returned by RequestCancelWorkflowExecution into a
-	// serializable result struct. WrapResponse returns a
-	// non-nil error if the provided error cannot be thrown by
-	// RequestCancelWorkflowExecution
+	// This allows mapping values and errors returned by
+	// RequestCancelWorkflowExecution into a serializable result struct.
+	// WrapResponse returns a non-nil error if the provided
+	// error cannot be thrown by RequestCancelWorkflowExecution
 	//
-	//   err := RequestCancelWorkflowExecution(args)
-	//   result, err := WorkflowService_RequestCancelWorkflowExecution_Helper.WrapResponse(err)
+	//   value, err := RequestCancelWorkflowExecution(args)
+	//   result, err := WorkflowService_RequestCancelWorkflowExecution_Helper.WrapResponse(value, err)
 	//   if err != nil {
 	//     return fmt.Errorf("unexpected error from RequestCancelWorkflowExecution: %v", err)
 	//   }
 	//   serialize(result)
-	WrapResponse func(error) (*WorkflowService_RequestCancelWorkflowExecution_Result, error)
+	WrapResponse func(*shared.RequestCancelWorkflowExecutionResponse, error) (*WorkflowService_RequestCancelWorkflowExecution_Result, error)
 
 	// UnwrapResponse takes the result struct for RequestCancelWorkflowExecution
-	// and returns the erorr returned by it (if any).
+	// and returns the value or error returned by it.
 	//
 	// The error is non-nil only if RequestCancelWorkflowExecution threw an
 	// exception.
 	//
 	//   result := deserialize(bytes)
-	//   err := WorkflowService_RequestCancelWorkflowExecution_Helper.UnwrapResponse(result)
-	UnwrapResponse func(*WorkflowService_RequestCancelWorkflowExecution_Result) error
+	//   value, err := WorkflowService_RequestCancelWorkflowExecution_Helper.UnwrapResponse(result)
+	UnwrapResponse func(*WorkflowService_RequestCancelWorkflowExecution_Result) (*shared.RequestCancelWorkflowExecutionResponse, error)
 }{}
 
 func init() {
@@ -231,9 +230,9 @@ func init() {
 		}
 	}
 
-	WorkflowService_RequestCancelWorkflowExecution_Helper.WrapResponse = func(err error) (*WorkflowService_RequestCancelWorkflowExecution_Result, error) {
+	WorkflowService_RequestCancelWorkflowExecution_Helper.WrapResponse = func(success *shared.RequestCancelWorkflowExecutionResponse, err error) (*WorkflowService_RequestCancelWorkflowExecution_Result, error) {
 		if err == nil {
-			return &WorkflowService_RequestCancelWorkflowExecution_Result{}, nil
+			return &WorkflowService_RequestCancelWorkflowExecution_Result{Success: success}, nil
 		}
 
 		switch e := err.(type) {
@@ -266,7 +265,7 @@ func init() {
 
 		return nil, err
 	}
-	WorkflowService_RequestCancelWorkflowExecution_Helper.UnwrapResponse = func(result *WorkflowService_RequestCancelWorkflowExecution_Result) (err error) {
+	WorkflowService_RequestCancelWorkflowExecution_Helper.UnwrapResponse = func(result *WorkflowService_RequestCancelWorkflowExecution_Result) (success *shared.RequestCancelWorkflowExecutionResponse, err error) {
 		if result.BadRequestError != nil {
 			err = result.BadRequestError
 			return
@@ -287,6 +286,13 @@ func init() {
 			err = result.ServiceBusyError
 			return
 		}
+
+		if result.Success != nil {
+			success = result.Success
+			return
+		}
+
+		err = errors.New("expected a non-void result")
 		return
 	}
 
@@ -295,12 +301,16 @@ func init() {
 // WorkflowService_RequestCancelWorkflowExecution_Result represents the result of a WorkflowService.RequestCancelWorkflowExecution function call.
 //
 // The result of a RequestCancelWorkflowExecution execution is sent and received over the wire as this struct.
+//
+// Success is set only if the function did not throw an exception.
 type WorkflowService_RequestCancelWorkflowExecution_Result struct {
-	BadRequestError                   *shared.BadRequestError                   `json:"badRequestError,omitempty"`
-	InternalServiceError              *shared.InternalServiceError              `json:"internalServiceError,omitempty"`
-	EntityNotExistError               *shared.EntityNotExistsError              `json:"entityNotExistError,omitempty"`
-	CancellationAlreadyRequestedError *shared.CancellationAlreadyRequestedError `json:"cancellationAlreadyRequestedError,omitempty"`
-	ServiceBusyError                  *shared.ServiceBusyError                  `json:"serviceBusyError,omitempty"`
+	// Value returned by RequestCancelWorkflowExecution after a successful execution.
+	Success                           *shared.RequestCancelWorkflowExecutionResponse `json:"success,omitempty"`
+	BadRequestError                   *shared.BadRequestError                        `json:"badRequestError,omitempty"`
+	InternalServiceError              *shared.InternalServiceError                   `json:"internalServiceError,omitempty"`
+	EntityNotExistError               *shared.EntityNotExistsError                   `json:"entityNotExistError,omitempty"`
+	CancellationAlreadyRequestedError *shared.CancellationAlreadyRequestedError      `json:"cancellationAlreadyRequestedError,omitempty"`
+	ServiceBusyError                  *shared.ServiceBusyError                       `json:"serviceBusyError,omitempty"`
 }
 
 // ToWire translates a WorkflowService_RequestCancelWorkflowExecution_Result struct into a Thrift-level intermediate
@@ -320,12 +330,20 @@ type WorkflowService_RequestCancelWorkflowExecution_Result struct {
 //   }
 func (v *WorkflowService_RequestCancelWorkflowExecution_Result) ToWire() (wire.Value, error) {
 	var (
-		fields [5]wire.Field
+		fields [6]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
 	)
 
+	if v.Success != nil {
+		w, err = v.Success.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 0, Value: w}
+		i++
+	}
 	if v.BadRequestError != nil {
 		w, err = v.BadRequestError.ToWire()
 		if err != nil {
@@ -367,8 +385,8 @@ func (v *WorkflowService_RequestCancelWorkflowExecution_Result) ToWire() (wire.V
 		i++
 	}
 
-	if i > 1 {
-		return wire.Value{}, fmt.Errorf("WorkflowService_RequestCancelWorkflowExecution_Result should have at most one field: got %v fields", i)
+	if i != 1 {
+		return wire.Value{}, fmt.Errorf("WorkflowService_RequestCancelWorkflowExecution_Result should have exactly one field: got %v fields", i)
 	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
@@ -380,6 +398,12 @@ func _CancellationAlreadyRequestedError_Read(w wire.Value) (*shared.Cancellation
 	return &v, err
 }
 
+func _RequestCancelWorkflowExecutionResponse_Read(w wire.Value) (*shared.RequestCancelWorkflowExecutionResponse, error) {
+	var v shared.RequestCancelWorkflowExecutionResponse
+	err := v.FromWire(w)
+	return &v, err
+}
+
 // FromWire deserializes a WorkflowService_RequestCancelWorkflowExecution_Result struct from its Thrift-level
 // representation. The Thrift-level representation may be obtained
 // from a ThriftRW protocol implementation.
@@ -402,6 +426,14 @@ func (v *WorkflowService_RequestCancelWorkflowExecution_Result) FromWire(w wire.
 
 	for _, field := range w.GetStruct().Fields {
 		switch field.ID {
+		case 0:
+			if field.Value.Type() == wire.TStruct {
+				v.Success, err = _RequestCancelWorkflowExecutionResponse_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
+			}
 		case 1:
 			if field.Value.Type() == wire.TStruct {
 				v.BadRequestError, err = _BadRequestError_Read(field.Value)
@@ -446,6 +478,9 @@ func (v *WorkflowService_RequestCancelWorkflowExecution_Result) FromWire(w wire.
 	}
 
 	count := 0
+	if v.Success != nil {
+		count++
+	}
 	if v.BadRequestError != nil {
 		count++
 	}
@@ -461,8 +496,8 @@ func (v *WorkflowService_RequestCancelWorkflowExecution_Result) FromWire(w wire.
 	if v.ServiceBusyError != nil {
 		count++
 	}
-	if count > 1 {
-		return fmt.Errorf("WorkflowService_RequestCancelWorkflowExecution_Result should have at most one field: got %v fields", count)
+	if count != 1 {
+		return fmt.Errorf("WorkflowService_RequestCancelWorkflowExecution_Result should have exactly one field: got %v fields", count)
 	}
 
 	return nil
@@ -475,8 +510,12 @@ func (v *WorkflowService_RequestCancelWorkflowExecution_Result) String() string
 		return "<nil>"
 	}
 
-	var fields [5]string
+	var fields [6]string
 	i := 0
+	if v.Success != nil {
+		fields[i] = fmt.Sprintf("Success: %v", v.Success)
+		i++
+	}
 	if v.BadRequestError != nil {
 		fields[i] = fmt.Sprintf("BadRequestError: %v", v.BadRequestError)
 		i++
@@ -506,6 +545,9 @@ func (v *WorkflowService_RequestCancelWorkflowExecution_Result) String() string
 //
 // This function performs a deep comparison.
 func (v *WorkflowService_RequestCancelWorkflowExecution_Result) Equals(rhs *WorkflowService_RequestCancelWorkflowExecution_Result) bool {
+	if !((v.Success == nil && rhs.Success == nil) || (v.Success != nil && rhs.Success != nil && v.Success.Equals(rhs.Success))) {
+		return false
+	}
 	if !((v.BadRequestError == nil && rhs.BadRequestError == nil) || (v.BadRequestError != nil && rhs.BadRequestError != nil && v.BadRequestError.Equals(rhs.BadRequestError))) {
 		return false
 	}
@@ -178,31 +178,30 @@ var WorkflowService_RespondDecisionTaskCompleted_Helper = struct {
 	IsException func(error) bool
 
 	// WrapResponse returns the result struct for RespondDecisionTaskCompleted
-	// given the error returned by it. The provided error may
-	// be nil if RespondDecisionTaskCompleted did not fail.
+	// given its return value and error.
 	//
-	// This allows mapping errors returned by RespondDecisionTaskCompleted into a
-	// serializable result struct. WrapResponse returns a
-	// non-nil error if the provided error cannot be thrown by
-	// RespondDecisionTaskCompleted
+	// This allows mapping values and errors returned by
+	// RespondDecisionTaskCompleted into a serializable result struct.
+	// WrapResponse returns a non-nil error if the provided
+	// error cannot be thrown by RespondDecisionTaskCompleted
 	//
-	//   err := RespondDecisionTaskCompleted(args)
-	//   result, err := WorkflowService_RespondDecisionTaskCompleted_Helper.WrapResponse(err)
+	//   value, err := RespondDecisionTaskCompleted(args)
+	//   result, err := WorkflowService_RespondDecisionTaskCompleted_Helper.WrapResponse(value, err)
 	//   if err != nil {
 	//     return fmt.Errorf("unexpected error from RespondDecisionTaskCompleted: %v", err)
 	//   }
 	//   serialize(result)
-	WrapResponse func(error) (*WorkflowService_RespondDecisionTaskCompleted_Result, error)
+	WrapResponse func(*shared.RespondDecisionTaskCompletedResponse, error) (*WorkflowService_RespondDecisionTaskCompleted_Result, error)
 
 	// UnwrapResponse takes the result struct for RespondDecisionTaskCompleted
-	// and returns the erorr returned by it (if any).
+	// and returns the value or error returned by it.
 	//
 	// The error is non-nil only if RespondDecisionTaskCompleted threw an
 	// exception.
 	//
 	//   result := deserialize(bytes)
-	//   err := WorkflowService_RespondDecisionTaskCompleted_Helper.UnwrapResponse(result)
-	UnwrapResponse func(*WorkflowService_RespondDecisionTaskCompleted_Result) error
+	//   value, err := WorkflowService_RespondDecisionTaskCompleted_Helper.UnwrapResponse(result)
+	UnwrapResponse func(*WorkflowService_RespondDecisionTaskCompleted_Result) (*shared.RespondDecisionTaskCompletedResponse, error)
 }{}
 
 func init() {
@@ -227,9 +226,9 @@ func init() {
 		}
 	}
 
-	WorkflowService_RespondDecisionTaskCompleted_Helper.WrapResponse = func(err error) (*WorkflowService_RespondDecisionTaskCompleted_Result, error) {
+	WorkflowService_RespondDecisionTaskCompleted_Helper.WrapResponse = func(success *shared.RespondDecisionTaskCompletedResponse, err error) (*WorkflowService_RespondDecisionTaskCompleted_Result, error) {
 		if err == nil {
-			return &WorkflowService_RespondDecisionTaskCompleted_Result{}, nil
+			return &WorkflowService_RespondDecisionTaskCompleted_Result{Success: success}, nil
 		}
 
 		switch e := err.(type) {
@@ -252,7 +251,7 @@ func init() {
 
 		return nil, err
 	}
-	WorkflowService_RespondDecisionTaskCompleted_Helper.UnwrapResponse = func(result *WorkflowService_RespondDecisionTaskCompleted_Result) (err error) {
+	WorkflowService_RespondDecisionTaskCompleted_Helper.UnwrapResponse = func(result *WorkflowService_RespondDecisionTaskCompleted_Result) (success *shared.RespondDecisionTaskCompletedResponse, err error) {
 		if result.BadRequestError != nil {
 			err = result.BadRequestError
 			return
@@ -265,6 +264,13 @@ func init() {
 			err = result.EntityNotExistError
 			return
 		}
+
+		if result.Success != nil {
+			success = result.Success
+			return
+		}
+
+		err = errors.New("expected a non-void result")
 		return
 	}
 
@@ -273,10 +279,14 @@ func init() {
 // WorkflowService_RespondDecisionTaskCompleted_Result represents the result of a WorkflowService.RespondDecisionTaskCompleted function call.
 //
 // The result of a RespondDecisionTaskCompleted execution is sent and received over the wire as this struct.
+//
+// Success is set only if the function did not throw an exception.
 type WorkflowService_RespondDecisionTaskCompleted_Result struct {
-	BadRequestError      *shared.BadRequestError      `json:"badRequestError,omitempty"`
-	InternalServiceError *shared.InternalServiceError `json:"internalServiceError,omitempty"`
-	EntityNotExistError  *shared.EntityNotExistsError `json:"entityNotExistError,omitempty"`
+	// Value returned by RespondDecisionTaskCompleted after a successful execution.
+	Success              *shared.RespondDecisionTaskCompletedResponse `json:"success,omitempty"`
+	BadRequestError      *shared.BadRequestError                      `json:"badRequestError,omitempty"`
+	InternalServiceError *shared.InternalServiceError                 `json:"internalServiceError,omitempty"`
+	EntityNotExistError  *shared.EntityNotExistsError                 `json:"entityNotExistError,omitempty"`
 }
 
 // ToWire translates a WorkflowService_RespondDecisionTaskCompleted_Result struct into a Thrift-level intermediate
@@ -296,12 +306,20 @@ type WorkflowService_RespondDecisionTaskCompleted_Result struct {
 //   }
 func (v *WorkflowService_RespondDecisionTaskCompleted_Result) ToWire() (wire.Value, error) {
 	var (
-		fields [3]wire.Field
+		fields [4]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
 	)
 
+	if v.Success != nil {
+		w, err = v.Success.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 0, Value: w}
+		i++
+	}
 	if v.BadRequestError != nil {
 		w, err = v.BadRequestError.ToWire()
 		if err != nil {
@@ -327,13 +345,19 @@ func (v *WorkflowService_RespondDecisionTaskCompleted_Result) ToWire() (wire.Val
 		i++
 	}
 
-	if i > 1 {
-		return wire.Value{}, fmt.Errorf("WorkflowService_RespondDecisionTaskCompleted_Result should have at most one field: got %v fields", i)
+	if i != 1 {
+		return wire.Value{}, fmt.Errorf("WorkflowService_RespondDecisionTaskCompleted_Result should have exactly one field: got %v fields", i)
 	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
 
+func _RespondDecisionTaskCompletedResponse_Read(w wire.Value) (*shared.RespondDecisionTaskCompletedResponse, error) {
+	var v shared.RespondDecisionTaskCompletedResponse
+	err := v.FromWire(w)
+	return &v, err
+}
+
 // FromWire deserializes a WorkflowService_RespondDecisionTaskCompleted_Result struct from its Thrift-level
 // representation. The Thrift-level representation may be obtained
 // from a ThriftRW protocol implementation.
@@ -356,6 +380,14 @@ func (v *WorkflowService_RespondDecisionTaskCompleted_Result) FromWire(w wire.Va
 
 	for _, field := range w.GetStruct().Fields {
 		switch field.ID {
+		case 0:
+			if field.Value.Type() == wire.TStruct {
+				v.Success, err = _RespondDecisionTaskCompletedResponse_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
+			}
 		case 1:
 			if field.Value.Type() == wire.TStruct {
 				v.BadRequestError, err = _BadRequestError_Read(field.Value)
@@ -384,6 +416,9 @@ func (v *WorkflowService_RespondDecisionTaskCompleted_Result) FromWire(w wire.Va
 	}
 
 	count := 0
+	if v.Success != nil {
+		count++
+	}
 	if v.BadRequestError != nil {
 		count++
 	}
@@ -393,8 +428,8 @@ func (v *WorkflowService_RespondDecisionTaskCompleted_Result) FromWire(w wire.Va
 	if v.EntityNotExistError != nil {
 		count++
 	}
-	if count > 1 {
-		return fmt.Errorf("WorkflowService_RespondDecisionTaskCompleted_Result should have at most one field: got %v fields", count)
+	if count != 1 {
+		return fmt.Errorf("WorkflowService_RespondDecisionTaskCompleted_Result should have exactly one field: got %v fields", count)
 	}
 
 	return nil
@@ -407,8 +442,12 @@ func (v *WorkflowService_RespondDecisionTaskCompleted_Result) String() string {
 		return "<nil>"
 	}
 
-	var fields [3]string
+	var fields [4]string
 	i := 0
+	if v.Success != nil {
+		fields[i] = fmt.Sprintf("Success: %v", v.Success)
+		i++
+	}
 	if v.BadRequestError != nil {
 		fields[i] = fmt.Sprintf("BadRequestError: %v", v.BadRequestError)
 		i++
@@ -430,6 +469,9 @@ func (v *WorkflowService_RespondDecisionTaskCompleted_Result) String() string {
 //
 // This function performs a deep comparison.
 func (v *WorkflowService_RespondDecisionTaskCompleted_Result) Equals(rhs *WorkflowService_RespondDecisionTaskCompleted_Result) bool {
+	if !((v.Success == nil && rhs.Success == nil) || (v.Success != nil && rhs.Success != nil && v.Success.Equals(rhs.Success))) {
+		return false
+	}
 	if !((v.BadRequestError == nil && rhs.BadRequestError == nil) || (v.BadRequestError != nil && rhs.BadRequestError != nil && v.BadRequestError.Equals(rhs.BadRequestError))) {
 		return false
 	}
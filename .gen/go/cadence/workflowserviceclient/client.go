@@ -118,7 +118,7 @@ type Interface interface {
 		ctx context.Context,
 		CancelRequest *shared.RequestCancelWorkflowExecutionRequest,
 		opts ...yarpc.CallOption,
-	) error
+	) (*shared.RequestCancelWorkflowExecutionResponse, error)
 
 	RespondActivityTaskCanceled(
 		ctx context.Context,
@@ -160,7 +160,7 @@ type Interface interface {
 		ctx context.Context,
 		CompleteRequest *shared.RespondDecisionTaskCompletedRequest,
 		opts ...yarpc.CallOption,
-	) error
+	) (*shared.RespondDecisionTaskCompletedResponse, error)
 
 	RespondDecisionTaskFailed(
 		ctx context.Context,
@@ -532,7 +532,7 @@ func (c client) RequestCancelWorkflowExecution(
 	ctx context.Context,
 	_CancelRequest *shared.RequestCancelWorkflowExecutionRequest,
 	opts ...yarpc.CallOption,
-) (err error) {
+) (success *shared.RequestCancelWorkflowExecutionResponse, err error) {
 
 	args := cadence.WorkflowService_RequestCancelWorkflowExecution_Helper.Args(_CancelRequest)
 
@@ -547,7 +547,7 @@ func (c client) RequestCancelWorkflowExecution(
 		return
 	}
 
-	err = cadence.WorkflowService_RequestCancelWorkflowExecution_Helper.UnwrapResponse(&result)
+	success, err = cadence.WorkflowService_RequestCancelWorkflowExecution_Helper.UnwrapResponse(&result)
 	return
 }
 
@@ -693,7 +693,7 @@ func (c client) RespondDecisionTaskCompleted(
 	ctx context.Context,
 	_CompleteRequest *shared.RespondDecisionTaskCompletedRequest,
 	opts ...yarpc.CallOption,
-) (err error) {
+) (success *shared.RespondDecisionTaskCompletedResponse, err error) {
 
 	args := cadence.WorkflowService_RespondDecisionTaskCompleted_Helper.Args(_CompleteRequest)
 
@@ -708,7 +708,7 @@ func (c client) RespondDecisionTaskCompleted(
 		return
 	}
 
-	err = cadence.WorkflowService_RespondDecisionTaskCompleted_Helper.UnwrapResponse(&result)
+	success, err = cadence.WorkflowService_RespondDecisionTaskCompleted_Helper.UnwrapResponse(&result)
 	return
 }
 
@@ -102,7 +102,7 @@ type Interface interface {
 	RequestCancelWorkflowExecution(
 		ctx context.Context,
 		CancelRequest *shared.RequestCancelWorkflowExecutionRequest,
-	) error
+	) (*shared.RequestCancelWorkflowExecutionResponse, error)
 
 	RespondActivityTaskCanceled(
 		ctx context.Context,
@@ -137,7 +137,7 @@ type Interface interface {
 	RespondDecisionTaskCompleted(
 		ctx context.Context,
 		CompleteRequest *shared.RespondDecisionTaskCompletedRequest,
-	) error
+	) (*shared.RespondDecisionTaskCompletedResponse, error)
 
 	RespondDecisionTaskFailed(
 		ctx context.Context,
@@ -336,7 +336,7 @@ func New(impl Interface, opts ...thrift.RegisterOption) []transport.Procedure {
 					Type:  transport.Unary,
 					Unary: thrift.UnaryHandler(h.RequestCancelWorkflowExecution),
 				},
-				Signature:    "RequestCancelWorkflowExecution(CancelRequest *shared.RequestCancelWorkflowExecutionRequest)",
+				Signature:    "RequestCancelWorkflowExecution(CancelRequest *shared.RequestCancelWorkflowExecutionRequest) (*shared.RequestCancelWorkflowExecutionResponse)",
 				ThriftModule: cadence.ThriftModule,
 			},
 
@@ -413,7 +413,7 @@ func New(impl Interface, opts ...thrift.RegisterOption) []transport.Procedure {
 					Type:  transport.Unary,
 					Unary: thrift.UnaryHandler(h.RespondDecisionTaskCompleted),
 				},
-				Signature:    "RespondDecisionTaskCompleted(CompleteRequest *shared.RespondDecisionTaskCompletedRequest)",
+				Signature:    "RespondDecisionTaskCompleted(CompleteRequest *shared.RespondDecisionTaskCompletedRequest) (*shared.RespondDecisionTaskCompletedResponse)",
 				ThriftModule: cadence.ThriftModule,
 			},
 
@@ -756,10 +756,10 @@ func (h handler) RequestCancelWorkflowExecution(ctx context.Context, body wire.V
 		return thrift.Response{}, err
 	}
 
-	err := h.impl.RequestCancelWorkflowExecution(ctx, args.CancelRequest)
+	success, err := h.impl.RequestCancelWorkflowExecution(ctx, args.CancelRequest)
 
 	hadError := err != nil
-	result, err := cadence.WorkflowService_RequestCancelWorkflowExecution_Helper.WrapResponse(err)
+	result, err := cadence.WorkflowService_RequestCancelWorkflowExecution_Helper.WrapResponse(success, err)
 
 	var response thrift.Response
 	if err == nil {
@@ -889,10 +889,10 @@ func (h handler) RespondDecisionTaskCompleted(ctx context.Context, body wire.Val
 		return thrift.Response{}, err
 	}
 
-	err := h.impl.RespondDecisionTaskCompleted(ctx, args.CompleteRequest)
+	success, err := h.impl.RespondDecisionTaskCompleted(ctx, args.CompleteRequest)
 
 	hadError := err != nil
-	result, err := cadence.WorkflowService_RespondDecisionTaskCompleted_Helper.WrapResponse(err)
+	result, err := cadence.WorkflowService_RespondDecisionTaskCompleted_Helper.WrapResponse(success, err)
 
 	var response thrift.Response
 	if err == nil {
@@ -497,7 +497,7 @@ func (m *MockClient) RequestCancelWorkflowExecution(
 	ctx context.Context,
 	_CancelRequest *shared.RequestCancelWorkflowExecutionRequest,
 	opts ...yarpc.CallOption,
-) (err error) {
+) (success *shared.RequestCancelWorkflowExecutionResponse, err error) {
 
 	args := []interface{}{ctx, _CancelRequest}
 	for _, o := range opts {
@@ -505,6 +505,8 @@ func (m *MockClient) RequestCancelWorkflowExecution(
 	}
 	i := 0
 	ret := m.ctrl.Call(m, "RequestCancelWorkflowExecution", args...)
+	success, _ = ret[i].(*shared.RequestCancelWorkflowExecutionResponse)
+	i++
 	err, _ = ret[i].(error)
 	return
 }
@@ -714,7 +716,7 @@ func (m *MockClient) RespondDecisionTaskCompleted(
 	ctx context.Context,
 	_CompleteRequest *shared.RespondDecisionTaskCompletedRequest,
 	opts ...yarpc.CallOption,
-) (err error) {
+) (success *shared.RespondDecisionTaskCompletedResponse, err error) {
 
 	args := []interface{}{ctx, _CompleteRequest}
 	for _, o := range opts {
@@ -722,6 +724,8 @@ func (m *MockClient) RespondDecisionTaskCompleted(
 	}
 	i := 0
 	ret := m.ctrl.Call(m, "RespondDecisionTaskCompleted", args...)
+	success, _ = ret[i].(*shared.RespondDecisionTaskCompletedResponse)
+	i++
 	err, _ = ret[i].(error)
 	return
 }
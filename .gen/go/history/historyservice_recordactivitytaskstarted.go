@@ -225,6 +225,8 @@ func init() {
 			return true
 		case *ShardOwnershipLostError:
 			return true
+		case *shared.DomainNotActiveError:
+			return true
 		default:
 			return false
 		}
@@ -261,6 +263,11 @@ func init() {
 				return nil, errors.New("WrapResponse received non-nil error type with nil value for HistoryService_RecordActivityTaskStarted_Result.ShardOwnershipLostError")
 			}
 			return &HistoryService_RecordActivityTaskStarted_Result{ShardOwnershipLostError: e}, nil
+		case *shared.DomainNotActiveError:
+			if e == nil {
+				return nil, errors.New("WrapResponse received non-nil error type with nil value for HistoryService_RecordActivityTaskStarted_Result.DomainNotActiveError")
+			}
+			return &HistoryService_RecordActivityTaskStarted_Result{DomainNotActiveError: e}, nil
 		}
 
 		return nil, err
@@ -286,6 +293,10 @@ func init() {
 			err = result.ShardOwnershipLostError
 			return
 		}
+		if result.DomainNotActiveError != nil {
+			err = result.DomainNotActiveError
+			return
+		}
 
 		if result.Success != nil {
 			success = result.Success
@@ -311,6 +322,7 @@ type HistoryService_RecordActivityTaskStarted_Result struct {
 	EventAlreadyStartedError *EventAlreadyStartedError          `json:"eventAlreadyStartedError,omitempty"`
 	EntityNotExistError      *shared.EntityNotExistsError       `json:"entityNotExistError,omitempty"`
 	ShardOwnershipLostError  *ShardOwnershipLostError           `json:"shardOwnershipLostError,omitempty"`
+	DomainNotActiveError     *shared.DomainNotActiveError       `json:"domainNotActiveError,omitempty"`
 }
 
 // ToWire translates a HistoryService_RecordActivityTaskStarted_Result struct into a Thrift-level intermediate
@@ -330,7 +342,7 @@ type HistoryService_RecordActivityTaskStarted_Result struct {
 //   }
 func (v *HistoryService_RecordActivityTaskStarted_Result) ToWire() (wire.Value, error) {
 	var (
-		fields [6]wire.Field
+		fields [7]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -384,6 +396,14 @@ func (v *HistoryService_RecordActivityTaskStarted_Result) ToWire() (wire.Value,
 		fields[i] = wire.Field{ID: 5, Value: w}
 		i++
 	}
+	if v.DomainNotActiveError != nil {
+		w, err = v.DomainNotActiveError.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 6, Value: w}
+		i++
+	}
 
 	if i != 1 {
 		return wire.Value{}, fmt.Errorf("HistoryService_RecordActivityTaskStarted_Result should have exactly one field: got %v fields", i)
@@ -473,6 +493,14 @@ func (v *HistoryService_RecordActivityTaskStarted_Result) FromWire(w wire.Value)
 					return err
 				}
 
+			}
+		case 6:
+			if field.Value.Type() == wire.TStruct {
+				v.DomainNotActiveError, err = _DomainNotActiveError_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -496,6 +524,9 @@ func (v *HistoryService_RecordActivityTaskStarted_Result) FromWire(w wire.Value)
 	if v.ShardOwnershipLostError != nil {
 		count++
 	}
+	if v.DomainNotActiveError != nil {
+		count++
+	}
 	if count != 1 {
 		return fmt.Errorf("HistoryService_RecordActivityTaskStarted_Result should have exactly one field: got %v fields", count)
 	}
@@ -510,7 +541,7 @@ func (v *HistoryService_RecordActivityTaskStarted_Result) String() string {
 		return "<nil>"
 	}
 
-	var fields [6]string
+	var fields [7]string
 	i := 0
 	if v.Success != nil {
 		fields[i] = fmt.Sprintf("Success: %v", v.Success)
@@ -536,6 +567,10 @@ func (v *HistoryService_RecordActivityTaskStarted_Result) String() string {
 		fields[i] = fmt.Sprintf("ShardOwnershipLostError: %v", v.ShardOwnershipLostError)
 		i++
 	}
+	if v.DomainNotActiveError != nil {
+		fields[i] = fmt.Sprintf("DomainNotActiveError: %v", v.DomainNotActiveError)
+		i++
+	}
 
 	return fmt.Sprintf("HistoryService_RecordActivityTaskStarted_Result{%v}", strings.Join(fields[:i], ", "))
 }
@@ -563,6 +598,9 @@ func (v *HistoryService_RecordActivityTaskStarted_Result) Equals(rhs *HistorySer
 	if !((v.ShardOwnershipLostError == nil && rhs.ShardOwnershipLostError == nil) || (v.ShardOwnershipLostError != nil && rhs.ShardOwnershipLostError != nil && v.ShardOwnershipLostError.Equals(rhs.ShardOwnershipLostError))) {
 		return false
 	}
+	if !((v.DomainNotActiveError == nil && rhs.DomainNotActiveError == nil) || (v.DomainNotActiveError != nil && rhs.DomainNotActiveError != nil && v.DomainNotActiveError.Equals(rhs.DomainNotActiveError))) {
+		return false
+	}
 
 	return true
 }
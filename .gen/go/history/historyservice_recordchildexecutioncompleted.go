@@ -224,6 +224,8 @@ func init() {
 			return true
 		case *ShardOwnershipLostError:
 			return true
+		case *shared.DomainNotActiveError:
+			return true
 		default:
 			return false
 		}
@@ -255,6 +257,11 @@ func init() {
 				return nil, errors.New("WrapResponse received non-nil error type with nil value for HistoryService_RecordChildExecutionCompleted_Result.ShardOwnershipLostError")
 			}
 			return &HistoryService_RecordChildExecutionCompleted_Result{ShardOwnershipLostError: e}, nil
+		case *shared.DomainNotActiveError:
+			if e == nil {
+				return nil, errors.New("WrapResponse received non-nil error type with nil value for HistoryService_RecordChildExecutionCompleted_Result.DomainNotActiveError")
+			}
+			return &HistoryService_RecordChildExecutionCompleted_Result{DomainNotActiveError: e}, nil
 		}
 
 		return nil, err
@@ -276,6 +283,10 @@ func init() {
 			err = result.ShardOwnershipLostError
 			return
 		}
+		if result.DomainNotActiveError != nil {
+			err = result.DomainNotActiveError
+			return
+		}
 		return
 	}
 
@@ -289,6 +300,7 @@ type HistoryService_RecordChildExecutionCompleted_Result struct {
 	InternalServiceError    *shared.InternalServiceError `json:"internalServiceError,omitempty"`
 	EntityNotExistError     *shared.EntityNotExistsError `json:"entityNotExistError,omitempty"`
 	ShardOwnershipLostError *ShardOwnershipLostError     `json:"shardOwnershipLostError,omitempty"`
+	DomainNotActiveError    *shared.DomainNotActiveError `json:"domainNotActiveError,omitempty"`
 }
 
 // ToWire translates a HistoryService_RecordChildExecutionCompleted_Result struct into a Thrift-level intermediate
@@ -308,7 +320,7 @@ type HistoryService_RecordChildExecutionCompleted_Result struct {
 //   }
 func (v *HistoryService_RecordChildExecutionCompleted_Result) ToWire() (wire.Value, error) {
 	var (
-		fields [4]wire.Field
+		fields [5]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -346,6 +358,14 @@ func (v *HistoryService_RecordChildExecutionCompleted_Result) ToWire() (wire.Val
 		fields[i] = wire.Field{ID: 4, Value: w}
 		i++
 	}
+	if v.DomainNotActiveError != nil {
+		w, err = v.DomainNotActiveError.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 5, Value: w}
+		i++
+	}
 
 	if i > 1 {
 		return wire.Value{}, fmt.Errorf("HistoryService_RecordChildExecutionCompleted_Result should have at most one field: got %v fields", i)
@@ -407,6 +427,14 @@ func (v *HistoryService_RecordChildExecutionCompleted_Result) FromWire(w wire.Va
 					return err
 				}
 
+			}
+		case 5:
+			if field.Value.Type() == wire.TStruct {
+				v.DomainNotActiveError, err = _DomainNotActiveError_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -424,6 +452,9 @@ func (v *HistoryService_RecordChildExecutionCompleted_Result) FromWire(w wire.Va
 	if v.ShardOwnershipLostError != nil {
 		count++
 	}
+	if v.DomainNotActiveError != nil {
+		count++
+	}
 	if count > 1 {
 		return fmt.Errorf("HistoryService_RecordChildExecutionCompleted_Result should have at most one field: got %v fields", count)
 	}
@@ -438,7 +469,7 @@ func (v *HistoryService_RecordChildExecutionCompleted_Result) String() string {
 		return "<nil>"
 	}
 
-	var fields [4]string
+	var fields [5]string
 	i := 0
 	if v.BadRequestError != nil {
 		fields[i] = fmt.Sprintf("BadRequestError: %v", v.BadRequestError)
@@ -456,6 +487,10 @@ func (v *HistoryService_RecordChildExecutionCompleted_Result) String() string {
 		fields[i] = fmt.Sprintf("ShardOwnershipLostError: %v", v.ShardOwnershipLostError)
 		i++
 	}
+	if v.DomainNotActiveError != nil {
+		fields[i] = fmt.Sprintf("DomainNotActiveError: %v", v.DomainNotActiveError)
+		i++
+	}
 
 	return fmt.Sprintf("HistoryService_RecordChildExecutionCompleted_Result{%v}", strings.Join(fields[:i], ", "))
 }
@@ -477,6 +512,9 @@ func (v *HistoryService_RecordChildExecutionCompleted_Result) Equals(rhs *Histor
 	if !((v.ShardOwnershipLostError == nil && rhs.ShardOwnershipLostError == nil) || (v.ShardOwnershipLostError != nil && rhs.ShardOwnershipLostError != nil && v.ShardOwnershipLostError.Equals(rhs.ShardOwnershipLostError))) {
 		return false
 	}
+	if !((v.DomainNotActiveError == nil && rhs.DomainNotActiveError == nil) || (v.DomainNotActiveError != nil && rhs.DomainNotActiveError != nil && v.DomainNotActiveError.Equals(rhs.DomainNotActiveError))) {
+		return false
+	}
 
 	return true
 }
@@ -178,31 +178,30 @@ var HistoryService_RequestCancelWorkflowExecution_Helper = struct {
 	IsException func(error) bool
 
 	// WrapResponse returns the result struct for RequestCancelWorkflowExecution
-	// given the error returned by it. The provided error may
-	// be nil if RequestCancelWorkflowExecution did not fail.
+	// given its return value and error.
 	//
-	// This allows mapping errors returned by RequestCancelWorkflowExecution into a
-	// serializable result struct. WrapResponse returns a
-	// non-nil error if the provided error cannot be thrown by
-	// RequestCancelWorkflowExecution
+	// This allows mapping values and errors returned by
+	// RequestCancelWorkflowExecution into a serializable result struct.
+	// WrapResponse returns a non-nil error if the provided
+	// error cannot be thrown by RequestCancelWorkflowExecution
 	//
-	//   err := RequestCancelWorkflowExecution(args)
-	//   result, err := HistoryService_RequestCancelWorkflowExecution_Helper.WrapResponse(err)
+	//   value, err := RequestCancelWorkflowExecution(args)
+	//   result, err := HistoryService_RequestCancelWorkflowExecution_Helper.WrapResponse(value, err)
 	//   if err != nil {
 	//     return fmt.Errorf("unexpected error from RequestCancelWorkflowExecution: %v", err)
 	//   }
 	//   serialize(result)
-	WrapResponse func(error) (*HistoryService_RequestCancelWorkflowExecution_Result, error)
+	WrapResponse func(*shared.RequestCancelWorkflowExecutionResponse, error) (*HistoryService_RequestCancelWorkflowExecution_Result, error)
 
 	// UnwrapResponse takes the result struct for RequestCancelWorkflowExecution
-	// and returns the erorr returned by it (if any).
+	// and returns the value or error returned by it.
 	//
 	// The error is non-nil only if RequestCancelWorkflowExecution threw an
 	// exception.
 	//
 	//   result := deserialize(bytes)
-	//   err := HistoryService_RequestCancelWorkflowExecution_Helper.UnwrapResponse(result)
-	UnwrapResponse func(*HistoryService_RequestCancelWorkflowExecution_Result) error
+	//   value, err := HistoryService_RequestCancelWorkflowExecution_Helper.UnwrapResponse(result)
+	UnwrapResponse func(*HistoryService_RequestCancelWorkflowExecution_Result) (*shared.RequestCancelWorkflowExecutionResponse, error)
 }{}
 
 func init() {
@@ -226,14 +225,16 @@ func init() {
 			return true
 		case *shared.CancellationAlreadyRequestedError:
 			return true
+		case *shared.DomainNotActiveError:
+			return true
 		default:
 			return false
 		}
 	}
 
-	HistoryService_RequestCancelWorkflowExecution_Helper.WrapResponse = func(err error) (*HistoryService_RequestCancelWorkflowExecution_Result, error) {
+	HistoryService_RequestCancelWorkflowExecution_Helper.WrapResponse = func(success *shared.RequestCancelWorkflowExecutionResponse, err error) (*HistoryService_RequestCancelWorkflowExecution_Result, error) {
 		if err == nil {
-			return &HistoryService_RequestCancelWorkflowExecution_Result{}, nil
+			return &HistoryService_RequestCancelWorkflowExecution_Result{Success: success}, nil
 		}
 
 		switch e := err.(type) {
@@ -262,11 +263,16 @@ func init() {
 				return nil, errors.New("WrapResponse received non-nil error type with nil value for HistoryService_RequestCancelWorkflowExecution_Result.CancellationAlreadyRequestedError")
 			}
 			return &HistoryService_RequestCancelWorkflowExecution_Result{CancellationAlreadyRequestedError: e}, nil
+		case *shared.DomainNotActiveError:
+			if e == nil {
+				return nil, errors.New("WrapResponse received non-nil error type with nil value for HistoryService_RequestCancelWorkflowExecution_Result.DomainNotActiveError")
+			}
+			return &HistoryService_RequestCancelWorkflowExecution_Result{DomainNotActiveError: e}, nil
 		}
 
 		return nil, err
 	}
-	HistoryService_RequestCancelWorkflowExecution_Helper.UnwrapResponse = func(result *HistoryService_RequestCancelWorkflowExecution_Result) (err error) {
+	HistoryService_RequestCancelWorkflowExecution_Helper.UnwrapResponse = func(result *HistoryService_RequestCancelWorkflowExecution_Result) (success *shared.RequestCancelWorkflowExecutionResponse, err error) {
 		if result.BadRequestError != nil {
 			err = result.BadRequestError
 			return
@@ -287,6 +293,17 @@ func init() {
 			err = result.CancellationAlreadyRequestedError
 			return
 		}
+		if result.DomainNotActiveError != nil {
+			err = result.DomainNotActiveError
+			return
+		}
+
+		if result.Success != nil {
+			success = result.Success
+			return
+		}
+
+		err = errors.New("expected a non-void result")
 		return
 	}
 
@@ -295,12 +312,17 @@ func init() {
 // HistoryService_RequestCancelWorkflowExecution_Result represents the result of a HistoryService.RequestCancelWorkflowExecution function call.
 //
 // The result of a RequestCancelWorkflowExecution execution is sent and received over the wire as this struct.
+//
+// Success is set only if the function did not throw an exception.
 type HistoryService_RequestCancelWorkflowExecution_Result struct {
-	BadRequestError                   *shared.BadRequestError                   `json:"badRequestError,omitempty"`
-	InternalServiceError              *shared.InternalServiceError              `json:"internalServiceError,omitempty"`
-	EntityNotExistError               *shared.EntityNotExistsError              `json:"entityNotExistError,omitempty"`
-	ShardOwnershipLostError           *ShardOwnershipLostError                  `json:"shardOwnershipLostError,omitempty"`
-	CancellationAlreadyRequestedError *shared.CancellationAlreadyRequestedError `json:"cancellationAlreadyRequestedError,omitempty"`
+	// Value returned by RequestCancelWorkflowExecution after a successful execution.
+	Success                           *shared.RequestCancelWorkflowExecutionResponse `json:"success,omitempty"`
+	BadRequestError                   *shared.BadRequestError                        `json:"badRequestError,omitempty"`
+	InternalServiceError              *shared.InternalServiceError                   `json:"internalServiceError,omitempty"`
+	EntityNotExistError               *shared.EntityNotExistsError                   `json:"entityNotExistError,omitempty"`
+	ShardOwnershipLostError           *ShardOwnershipLostError                       `json:"shardOwnershipLostError,omitempty"`
+	CancellationAlreadyRequestedError *shared.CancellationAlreadyRequestedError      `json:"cancellationAlreadyRequestedError,omitempty"`
+	DomainNotActiveError              *shared.DomainNotActiveError                   `json:"domainNotActiveError,omitempty"`
 }
 
 // ToWire translates a HistoryService_RequestCancelWorkflowExecution_Result struct into a Thrift-level intermediate
@@ -320,12 +342,20 @@ type HistoryService_RequestCancelWorkflowExecution_Result struct {
 //   }
 func (v *HistoryService_RequestCancelWorkflowExecution_Result) ToWire() (wire.Value, error) {
 	var (
-		fields [5]wire.Field
+		fields [7]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
 	)
 
+	if v.Success != nil {
+		w, err = v.Success.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 0, Value: w}
+		i++
+	}
 	if v.BadRequestError != nil {
 		w, err = v.BadRequestError.ToWire()
 		if err != nil {
@@ -366,9 +396,17 @@ func (v *HistoryService_RequestCancelWorkflowExecution_Result) ToWire() (wire.Va
 		fields[i] = wire.Field{ID: 5, Value: w}
 		i++
 	}
+	if v.DomainNotActiveError != nil {
+		w, err = v.DomainNotActiveError.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 6, Value: w}
+		i++
+	}
 
-	if i > 1 {
-		return wire.Value{}, fmt.Errorf("HistoryService_RequestCancelWorkflowExecution_Result should have at most one field: got %v fields", i)
+	if i != 1 {
+		return wire.Value{}, fmt.Errorf("HistoryService_RequestCancelWorkflowExecution_Result should have exactly one field: got %v fields", i)
 	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
@@ -380,6 +418,12 @@ func _CancellationAlreadyRequestedError_Read(w wire.Value) (*shared.Cancellation
 	return &v, err
 }
 
+func _RequestCancelWorkflowExecutionResponse_Read(w wire.Value) (*shared.RequestCancelWorkflowExecutionResponse, error) {
+	var v shared.RequestCancelWorkflowExecutionResponse
+	err := v.FromWire(w)
+	return &v, err
+}
+
 // FromWire deserializes a HistoryService_RequestCancelWorkflowExecution_Result struct from its Thrift-level
 // representation. The Thrift-level representation may be obtained
 // from a ThriftRW protocol implementation.
@@ -402,6 +446,14 @@ func (v *HistoryService_RequestCancelWorkflowExecution_Result) FromWire(w wire.V
 
 	for _, field := range w.GetStruct().Fields {
 		switch field.ID {
+		case 0:
+			if field.Value.Type() == wire.TStruct {
+				v.Success, err = _RequestCancelWorkflowExecutionResponse_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
+			}
 		case 1:
 			if field.Value.Type() == wire.TStruct {
 				v.BadRequestError, err = _BadRequestError_Read(field.Value)
@@ -441,11 +493,22 @@ func (v *HistoryService_RequestCancelWorkflowExecution_Result) FromWire(w wire.V
 					return err
 				}
 
+			}
+		case 6:
+			if field.Value.Type() == wire.TStruct {
+				v.DomainNotActiveError, err = _DomainNotActiveError_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
 
 	count := 0
+	if v.Success != nil {
+		count++
+	}
 	if v.BadRequestError != nil {
 		count++
 	}
@@ -461,8 +524,11 @@ func (v *HistoryService_RequestCancelWorkflowExecution_Result) FromWire(w wire.V
 	if v.CancellationAlreadyRequestedError != nil {
 		count++
 	}
-	if count > 1 {
-		return fmt.Errorf("HistoryService_RequestCancelWorkflowExecution_Result should have at most one field: got %v fields", count)
+	if v.DomainNotActiveError != nil {
+		count++
+	}
+	if count != 1 {
+		return fmt.Errorf("HistoryService_RequestCancelWorkflowExecution_Result should have exactly one field: got %v fields", count)
 	}
 
 	return nil
@@ -475,8 +541,12 @@ func (v *HistoryService_RequestCancelWorkflowExecution_Result) String() string {
 		return "<nil>"
 	}
 
-	var fields [5]string
+	var fields [7]string
 	i := 0
+	if v.Success != nil {
+		fields[i] = fmt.Sprintf("Success: %v", v.Success)
+		i++
+	}
 	if v.BadRequestError != nil {
 		fields[i] = fmt.Sprintf("BadRequestError: %v", v.BadRequestError)
 		i++
@@ -497,6 +567,10 @@ func (v *HistoryService_RequestCancelWorkflowExecution_Result) String() string {
 		fields[i] = fmt.Sprintf("CancellationAlreadyRequestedError: %v", v.CancellationAlreadyRequestedError)
 		i++
 	}
+	if v.DomainNotActiveError != nil {
+		fields[i] = fmt.Sprintf("DomainNotActiveError: %v", v.DomainNotActiveError)
+		i++
+	}
 
 	return fmt.Sprintf("HistoryService_RequestCancelWorkflowExecution_Result{%v}", strings.Join(fields[:i], ", "))
 }
@@ -506,6 +580,9 @@ func (v *HistoryService_RequestCancelWorkflowExecution_Result) String() string {
 //
 // This function performs a deep comparison.
 func (v *HistoryService_RequestCancelWorkflowExecution_Result) Equals(rhs *HistoryService_RequestCancelWorkflowExecution_Result) bool {
+	if !((v.Success == nil && rhs.Success == nil) || (v.Success != nil && rhs.Success != nil && v.Success.Equals(rhs.Success))) {
+		return false
+	}
 	if !((v.BadRequestError == nil && rhs.BadRequestError == nil) || (v.BadRequestError != nil && rhs.BadRequestError != nil && v.BadRequestError.Equals(rhs.BadRequestError))) {
 		return false
 	}
@@ -521,6 +598,9 @@ func (v *HistoryService_RequestCancelWorkflowExecution_Result) Equals(rhs *Histo
 	if !((v.CancellationAlreadyRequestedError == nil && rhs.CancellationAlreadyRequestedError == nil) || (v.CancellationAlreadyRequestedError != nil && rhs.CancellationAlreadyRequestedError != nil && v.CancellationAlreadyRequestedError.Equals(rhs.CancellationAlreadyRequestedError))) {
 		return false
 	}
+	if !((v.DomainNotActiveError == nil && rhs.DomainNotActiveError == nil) || (v.DomainNotActiveError != nil && rhs.DomainNotActiveError != nil && v.DomainNotActiveError.Equals(rhs.DomainNotActiveError))) {
+		return false
+	}
 
 	return true
 }
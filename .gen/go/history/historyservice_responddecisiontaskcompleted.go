@@ -178,31 +178,30 @@ var HistoryService_RespondDecisionTaskCompleted_Helper = struct {
 	IsException func(error) bool
 
 	// WrapResponse returns the result struct for RespondDecisionTaskCompleted
-	// given the error returned by it. The provided error may
-	// be nil if RespondDecisionTaskCompleted did not fail.
+	// given its return value and error.
 	//
-	// This allows mapping errors returned by RespondDecisionTaskCompleted into a
-	// serializable result struct. WrapResponse returns a
-	// non-nil error if the provided error cannot be thrown by
-	// RespondDecisionTaskCompleted
+	// This allows mapping values and errors returned by
+	// RespondDecisionTaskCompleted into a serializable result struct.
+	// WrapResponse returns a non-nil error if the provided
+	// error cannot be thrown by RespondDecisionTaskCompleted
 	//
-	//   err := RespondDecisionTaskCompleted(args)
-	//   result, err := HistoryService_RespondDecisionTaskCompleted_Helper.WrapResponse(err)
+	//   value, err := RespondDecisionTaskCompleted(args)
+	//   result, err := HistoryService_RespondDecisionTaskCompleted_Helper.WrapResponse(value, err)
 	//   if err != nil {
 	//     return fmt.Errorf("unexpected error from RespondDecisionTaskCompleted: %v", err)
 	//   }
 	//   serialize(result)
-	WrapResponse func(error) (*HistoryService_RespondDecisionTaskCompleted_Result, error)
+	WrapResponse func(*RespondDecisionTaskCompletedResponse, error) (*HistoryService_RespondDecisionTaskCompleted_Result, error)
 
 	// UnwrapResponse takes the result struct for RespondDecisionTaskCompleted
-	// and returns the erorr returned by it (if any).
+	// and returns the value or error returned by it.
 	//
 	// The error is non-nil only if RespondDecisionTaskCompleted threw an
 	// exception.
 	//
 	//   result := deserialize(bytes)
-	//   err := HistoryService_RespondDecisionTaskCompleted_Helper.UnwrapResponse(result)
-	UnwrapResponse func(*HistoryService_RespondDecisionTaskCompleted_Result) error
+	//   value, err := HistoryService_RespondDecisionTaskCompleted_Helper.UnwrapResponse(result)
+	UnwrapResponse func(*HistoryService_RespondDecisionTaskCompleted_Result) (*RespondDecisionTaskCompletedResponse, error)
 }{}
 
 func init() {
@@ -224,14 +223,16 @@ func init() {
 			return true
 		case *ShardOwnershipLostError:
 			return true
+		case *shared.DomainNotActiveError:
+			return true
 		default:
 			return false
 		}
 	}
 
-	HistoryService_RespondDecisionTaskCompleted_Helper.WrapResponse = func(err error) (*HistoryService_RespondDecisionTaskCompleted_Result, error) {
+	HistoryService_RespondDecisionTaskCompleted_Helper.WrapResponse = func(success *RespondDecisionTaskCompletedResponse, err error) (*HistoryService_RespondDecisionTaskCompleted_Result, error) {
 		if err == nil {
-			return &HistoryService_RespondDecisionTaskCompleted_Result{}, nil
+			return &HistoryService_RespondDecisionTaskCompleted_Result{Success: success}, nil
 		}
 
 		switch e := err.(type) {
@@ -255,11 +256,16 @@ func init() {
 				return nil, errors.New("WrapResponse received non-nil error type with nil value for HistoryService_RespondDecisionTaskCompleted_Result.ShardOwnershipLostError")
 			}
 			return &HistoryService_RespondDecisionTaskCompleted_Result{ShardOwnershipLostError: e}, nil
+		case *shared.DomainNotActiveError:
+			if e == nil {
+				return nil, errors.New("WrapResponse received non-nil error type with nil value for HistoryService_RespondDecisionTaskCompleted_Result.DomainNotActiveError")
+			}
+			return &HistoryService_RespondDecisionTaskCompleted_Result{DomainNotActiveError: e}, nil
 		}
 
 		return nil, err
 	}
-	HistoryService_RespondDecisionTaskCompleted_Helper.UnwrapResponse = func(result *HistoryService_RespondDecisionTaskCompleted_Result) (err error) {
+	HistoryService_RespondDecisionTaskCompleted_Helper.UnwrapResponse = func(result *HistoryService_RespondDecisionTaskCompleted_Result) (success *RespondDecisionTaskCompletedResponse, err error) {
 		if result.BadRequestError != nil {
 			err = result.BadRequestError
 			return
@@ -276,6 +282,17 @@ func init() {
 			err = result.ShardOwnershipLostError
 			return
 		}
+		if result.DomainNotActiveError != nil {
+			err = result.DomainNotActiveError
+			return
+		}
+
+		if result.Success != nil {
+			success = result.Success
+			return
+		}
+
+		err = errors.New("expected a non-void result")
 		return
 	}
 
@@ -284,11 +301,16 @@ func init() {
 // HistoryService_RespondDecisionTaskCompleted_Result represents the result of a HistoryService.RespondDecisionTaskCompleted function call.
 //
 // The result of a RespondDecisionTaskCompleted execution is sent and received over the wire as this struct.
+//
+// Success is set only if the function did not throw an exception.
 type HistoryService_RespondDecisionTaskCompleted_Result struct {
-	BadRequestError         *shared.BadRequestError      `json:"badRequestError,omitempty"`
-	InternalServiceError    *shared.InternalServiceError `json:"internalServiceError,omitempty"`
-	EntityNotExistError     *shared.EntityNotExistsError `json:"entityNotExistError,omitempty"`
-	ShardOwnershipLostError *ShardOwnershipLostError     `json:"shardOwnershipLostError,omitempty"`
+	// Value returned by RespondDecisionTaskCompleted after a successful execution.
+	Success                 *RespondDecisionTaskCompletedResponse `json:"success,omitempty"`
+	BadRequestError         *shared.BadRequestError               `json:"badRequestError,omitempty"`
+	InternalServiceError    *shared.InternalServiceError          `json:"internalServiceError,omitempty"`
+	EntityNotExistError     *shared.EntityNotExistsError          `json:"entityNotExistError,omitempty"`
+	ShardOwnershipLostError *ShardOwnershipLostError              `json:"shardOwnershipLostError,omitempty"`
+	DomainNotActiveError    *shared.DomainNotActiveError          `json:"domainNotActiveError,omitempty"`
 }
 
 // ToWire translates a HistoryService_RespondDecisionTaskCompleted_Result struct into a Thrift-level intermediate
@@ -308,12 +330,20 @@ type HistoryService_RespondDecisionTaskCompleted_Result struct {
 //   }
 func (v *HistoryService_RespondDecisionTaskCompleted_Result) ToWire() (wire.Value, error) {
 	var (
-		fields [4]wire.Field
+		fields [6]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
 	)
 
+	if v.Success != nil {
+		w, err = v.Success.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 0, Value: w}
+		i++
+	}
 	if v.BadRequestError != nil {
 		w, err = v.BadRequestError.ToWire()
 		if err != nil {
@@ -346,14 +376,28 @@ func (v *HistoryService_RespondDecisionTaskCompleted_Result) ToWire() (wire.Valu
 		fields[i] = wire.Field{ID: 4, Value: w}
 		i++
 	}
+	if v.DomainNotActiveError != nil {
+		w, err = v.DomainNotActiveError.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 5, Value: w}
+		i++
+	}
 
-	if i > 1 {
-		return wire.Value{}, fmt.Errorf("HistoryService_RespondDecisionTaskCompleted_Result should have at most one field: got %v fields", i)
+	if i != 1 {
+		return wire.Value{}, fmt.Errorf("HistoryService_RespondDecisionTaskCompleted_Result should have exactly one field: got %v fields", i)
 	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
 
+func _RespondDecisionTaskCompletedResponse_Read(w wire.Value) (*RespondDecisionTaskCompletedResponse, error) {
+	var v RespondDecisionTaskCompletedResponse
+	err := v.FromWire(w)
+	return &v, err
+}
+
 // FromWire deserializes a HistoryService_RespondDecisionTaskCompleted_Result struct from its Thrift-level
 // representation. The Thrift-level representation may be obtained
 // from a ThriftRW protocol implementation.
@@ -376,6 +420,14 @@ func (v *HistoryService_RespondDecisionTaskCompleted_Result) FromWire(w wire.Val
 
 	for _, field := range w.GetStruct().Fields {
 		switch field.ID {
+		case 0:
+			if field.Value.Type() == wire.TStruct {
+				v.Success, err = _RespondDecisionTaskCompletedResponse_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
+			}
 		case 1:
 			if field.Value.Type() == wire.TStruct {
 				v.BadRequestError, err = _BadRequestError_Read(field.Value)
@@ -407,11 +459,22 @@ func (v *HistoryService_RespondDecisionTaskCompleted_Result) FromWire(w wire.Val
 					return err
 				}
 
+			}
+		case 5:
+			if field.Value.Type() == wire.TStruct {
+				v.DomainNotActiveError, err = _DomainNotActiveError_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
 
 	count := 0
+	if v.Success != nil {
+		count++
+	}
 	if v.BadRequestError != nil {
 		count++
 	}
@@ -424,8 +487,11 @@ func (v *HistoryService_RespondDecisionTaskCompleted_Result) FromWire(w wire.Val
 	if v.ShardOwnershipLostError != nil {
 		count++
 	}
-	if count > 1 {
-		return fmt.Errorf("HistoryService_RespondDecisionTaskCompleted_Result should have at most one field: got %v fields", count)
+	if v.DomainNotActiveError != nil {
+		count++
+	}
+	if count != 1 {
+		return fmt.Errorf("HistoryService_RespondDecisionTaskCompleted_Result should have exactly one field: got %v fields", count)
 	}
 
 	return nil
@@ -438,8 +504,12 @@ func (v *HistoryService_RespondDecisionTaskCompleted_Result) String() string {
 		return "<nil>"
 	}
 
-	var fields [4]string
+	var fields [6]string
 	i := 0
+	if v.Success != nil {
+		fields[i] = fmt.Sprintf("Success: %v", v.Success)
+		i++
+	}
 	if v.BadRequestError != nil {
 		fields[i] = fmt.Sprintf("BadRequestError: %v", v.BadRequestError)
 		i++
@@ -456,6 +526,10 @@ func (v *HistoryService_RespondDecisionTaskCompleted_Result) String() string {
 		fields[i] = fmt.Sprintf("ShardOwnershipLostError: %v", v.ShardOwnershipLostError)
 		i++
 	}
+	if v.DomainNotActiveError != nil {
+		fields[i] = fmt.Sprintf("DomainNotActiveError: %v", v.DomainNotActiveError)
+		i++
+	}
 
 	return fmt.Sprintf("HistoryService_RespondDecisionTaskCompleted_Result{%v}", strings.Join(fields[:i], ", "))
 }
@@ -465,6 +539,9 @@ func (v *HistoryService_RespondDecisionTaskCompleted_Result) String() string {
 //
 // This function performs a deep comparison.
 func (v *HistoryService_RespondDecisionTaskCompleted_Result) Equals(rhs *HistoryService_RespondDecisionTaskCompleted_Result) bool {
+	if !((v.Success == nil && rhs.Success == nil) || (v.Success != nil && rhs.Success != nil && v.Success.Equals(rhs.Success))) {
+		return false
+	}
 	if !((v.BadRequestError == nil && rhs.BadRequestError == nil) || (v.BadRequestError != nil && rhs.BadRequestError != nil && v.BadRequestError.Equals(rhs.BadRequestError))) {
 		return false
 	}
@@ -477,6 +554,9 @@ func (v *HistoryService_RespondDecisionTaskCompleted_Result) Equals(rhs *History
 	if !((v.ShardOwnershipLostError == nil && rhs.ShardOwnershipLostError == nil) || (v.ShardOwnershipLostError != nil && rhs.ShardOwnershipLostError != nil && v.ShardOwnershipLostError.Equals(rhs.ShardOwnershipLostError))) {
 		return false
 	}
+	if !((v.DomainNotActiveError == nil && rhs.DomainNotActiveError == nil) || (v.DomainNotActiveError != nil && rhs.DomainNotActiveError != nil && v.DomainNotActiveError.Equals(rhs.DomainNotActiveError))) {
+		return false
+	}
 
 	return true
 }
@@ -88,7 +88,7 @@ type Interface interface {
 		ctx context.Context,
 		CancelRequest *history.RequestCancelWorkflowExecutionRequest,
 		opts ...yarpc.CallOption,
-	) error
+	) (*shared.RequestCancelWorkflowExecutionResponse, error)
 
 	ResetStickyTaskList(
 		ctx context.Context,
@@ -118,7 +118,7 @@ type Interface interface {
 		ctx context.Context,
 		CompleteRequest *history.RespondDecisionTaskCompletedRequest,
 		opts ...yarpc.CallOption,
-	) error
+	) (*history.RespondDecisionTaskCompletedResponse, error)
 
 	RespondDecisionTaskFailed(
 		ctx context.Context,
@@ -369,7 +369,7 @@ func (c client) RequestCancelWorkflowExecution(
 	ctx context.Context,
 	_CancelRequest *history.RequestCancelWorkflowExecutionRequest,
 	opts ...yarpc.CallOption,
-) (err error) {
+) (success *shared.RequestCancelWorkflowExecutionResponse, err error) {
 
 	args := history.HistoryService_RequestCancelWorkflowExecution_Helper.Args(_CancelRequest)
 
@@ -384,7 +384,7 @@ func (c client) RequestCancelWorkflowExecution(
 		return
 	}
 
-	err = history.HistoryService_RequestCancelWorkflowExecution_Helper.UnwrapResponse(&result)
+	success, err = history.HistoryService_RequestCancelWorkflowExecution_Helper.UnwrapResponse(&result)
 	return
 }
 
@@ -484,7 +484,7 @@ func (c client) RespondDecisionTaskCompleted(
 	ctx context.Context,
 	_CompleteRequest *history.RespondDecisionTaskCompletedRequest,
 	opts ...yarpc.CallOption,
-) (err error) {
+) (success *history.RespondDecisionTaskCompletedResponse, err error) {
 
 	args := history.HistoryService_RespondDecisionTaskCompleted_Helper.Args(_CompleteRequest)
 
@@ -499,7 +499,7 @@ func (c client) RespondDecisionTaskCompleted(
 		return
 	}
 
-	err = history.HistoryService_RespondDecisionTaskCompleted_Helper.UnwrapResponse(&result)
+	success, err = history.HistoryService_RespondDecisionTaskCompleted_Helper.UnwrapResponse(&result)
 	return
 }
 
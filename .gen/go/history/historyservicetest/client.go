@@ -331,7 +331,7 @@ func (m *MockClient) RequestCancelWorkflowExecution(
 	ctx context.Context,
 	_CancelRequest *history.RequestCancelWorkflowExecutionRequest,
 	opts ...yarpc.CallOption,
-) (err error) {
+) (success *shared.RequestCancelWorkflowExecutionResponse, err error) {
 
 	args := []interface{}{ctx, _CancelRequest}
 	for _, o := range opts {
@@ -339,6 +339,8 @@ func (m *MockClient) RequestCancelWorkflowExecution(
 	}
 	i := 0
 	ret := m.ctrl.Call(m, "RequestCancelWorkflowExecution", args...)
+	success, _ = ret[i].(*shared.RequestCancelWorkflowExecutionResponse)
+	i++
 	err, _ = ret[i].(error)
 	return
 }
@@ -488,7 +490,7 @@ func (m *MockClient) RespondDecisionTaskCompleted(
 	ctx context.Context,
 	_CompleteRequest *history.RespondDecisionTaskCompletedRequest,
 	opts ...yarpc.CallOption,
-) (err error) {
+) (success *history.RespondDecisionTaskCompletedResponse, err error) {
 
 	args := []interface{}{ctx, _CompleteRequest}
 	for _, o := range opts {
@@ -496,6 +498,8 @@ func (m *MockClient) RespondDecisionTaskCompleted(
 	}
 	i := 0
 	ret := m.ctrl.Call(m, "RespondDecisionTaskCompleted", args...)
+	success, _ = ret[i].(*history.RespondDecisionTaskCompletedResponse)
+	i++
 	err, _ = ret[i].(error)
 	return
 }
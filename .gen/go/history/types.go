@@ -24,16 +24,23 @@
 package history
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/uber/cadence/.gen/go/shared"
 	"go.uber.org/thriftrw/wire"
+	"math"
+	"strconv"
 	"strings"
 )
 
 type DescribeWorkflowExecutionRequest struct {
-	DomainUUID *string                                  `json:"domainUUID,omitempty"`
-	Request    *shared.DescribeWorkflowExecutionRequest `json:"request,omitempty"`
+	DomainUUID          *string                                  `json:"domainUUID,omitempty"`
+	Request             *shared.DescribeWorkflowExecutionRequest `json:"request,omitempty"`
+	Verbose             *bool                                    `json:"verbose,omitempty"`
+	ConsistencyLevel    *ReadConsistencyLevel                    `json:"consistencyLevel,omitempty"`
+	OnlySafeResetPoints *bool                                    `json:"onlySafeResetPoints,omitempty"`
 }
 
 // ToWire translates a DescribeWorkflowExecutionRequest struct into a Thrift-level intermediate
@@ -53,7 +60,7 @@ type DescribeWorkflowExecutionRequest struct {
 //   }
 func (v *DescribeWorkflowExecutionRequest) ToWire() (wire.Value, error) {
 	var (
-		fields [2]wire.Field
+		fields [5]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -75,6 +82,30 @@ func (v *DescribeWorkflowExecutionRequest) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 20, Value: w}
 		i++
 	}
+	if v.Verbose != nil {
+		w, err = wire.NewValueBool(*(v.Verbose)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 30, Value: w}
+		i++
+	}
+	if v.ConsistencyLevel != nil {
+		w, err = v.ConsistencyLevel.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 40, Value: w}
+		i++
+	}
+	if v.OnlySafeResetPoints != nil {
+		w, err = wire.NewValueBool(*(v.OnlySafeResetPoints)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 50, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -124,6 +155,36 @@ func (v *DescribeWorkflowExecutionRequest) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 30:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.Verbose = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 40:
+			if field.Value.Type() == wire.TI32 {
+				var x ReadConsistencyLevel
+				x, err = _ReadConsistencyLevel_Read(field.Value)
+				v.ConsistencyLevel = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 50:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.OnlySafeResetPoints = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -138,7 +199,7 @@ func (v *DescribeWorkflowExecutionRequest) String() string {
 		return "<nil>"
 	}
 
-	var fields [2]string
+	var fields [5]string
 	i := 0
 	if v.DomainUUID != nil {
 		fields[i] = fmt.Sprintf("DomainUUID: %v", *(v.DomainUUID))
@@ -148,6 +209,18 @@ func (v *DescribeWorkflowExecutionRequest) String() string {
 		fields[i] = fmt.Sprintf("Request: %v", v.Request)
 		i++
 	}
+	if v.Verbose != nil {
+		fields[i] = fmt.Sprintf("Verbose: %v", *(v.Verbose))
+		i++
+	}
+	if v.ConsistencyLevel != nil {
+		fields[i] = fmt.Sprintf("ConsistencyLevel: %v", *(v.ConsistencyLevel))
+		i++
+	}
+	if v.OnlySafeResetPoints != nil {
+		fields[i] = fmt.Sprintf("OnlySafeResetPoints: %v", *(v.OnlySafeResetPoints))
+		i++
+	}
 
 	return fmt.Sprintf("DescribeWorkflowExecutionRequest{%v}", strings.Join(fields[:i], ", "))
 }
@@ -173,6 +246,15 @@ func (v *DescribeWorkflowExecutionRequest) Equals(rhs *DescribeWorkflowExecution
 	if !((v.Request == nil && rhs.Request == nil) || (v.Request != nil && rhs.Request != nil && v.Request.Equals(rhs.Request))) {
 		return false
 	}
+	if !_Bool_EqualsPtr(v.Verbose, rhs.Verbose) {
+		return false
+	}
+	if !_ReadConsistencyLevel_EqualsPtr(v.ConsistencyLevel, rhs.ConsistencyLevel) {
+		return false
+	}
+	if !_Bool_EqualsPtr(v.OnlySafeResetPoints, rhs.OnlySafeResetPoints) {
+		return false
+	}
 
 	return true
 }
@@ -187,6 +269,36 @@ func (v *DescribeWorkflowExecutionRequest) GetDomainUUID() (o string) {
 	return
 }
 
+// GetVerbose returns the value of Verbose if it is set or its
+// zero value if it is unset.
+func (v *DescribeWorkflowExecutionRequest) GetVerbose() (o bool) {
+	if v.Verbose != nil {
+		return *v.Verbose
+	}
+
+	return
+}
+
+// GetConsistencyLevel returns the value of ConsistencyLevel if it is set or its
+// zero value if it is unset.
+func (v *DescribeWorkflowExecutionRequest) GetConsistencyLevel() (o ReadConsistencyLevel) {
+	if v.ConsistencyLevel != nil {
+		return *v.ConsistencyLevel
+	}
+
+	return
+}
+
+// GetOnlySafeResetPoints returns the value of OnlySafeResetPoints if it is set or its
+// zero value if it is unset.
+func (v *DescribeWorkflowExecutionRequest) GetOnlySafeResetPoints() (o bool) {
+	if v.OnlySafeResetPoints != nil {
+		return *v.OnlySafeResetPoints
+	}
+
+	return
+}
+
 type EventAlreadyStartedError struct {
 	Message string `json:"message,required"`
 }
@@ -297,10 +409,149 @@ func (v *EventAlreadyStartedError) Error() string {
 	return v.String()
 }
 
+type ReadConsistencyLevel int32
+
+const (
+	ReadConsistencyLevelEventual ReadConsistencyLevel = 0
+	ReadConsistencyLevelStrong   ReadConsistencyLevel = 1
+)
+
+// ReadConsistencyLevel_Values returns all recognized values of ReadConsistencyLevel.
+func ReadConsistencyLevel_Values() []ReadConsistencyLevel {
+	return []ReadConsistencyLevel{
+		ReadConsistencyLevelEventual,
+		ReadConsistencyLevelStrong,
+	}
+}
+
+// UnmarshalText tries to decode ReadConsistencyLevel from a byte slice
+// containing its name.
+//
+//   var v ReadConsistencyLevel
+//   err := v.UnmarshalText([]byte("EVENTUAL"))
+func (v *ReadConsistencyLevel) UnmarshalText(value []byte) error {
+	switch string(value) {
+	case "EVENTUAL":
+		*v = ReadConsistencyLevelEventual
+		return nil
+	case "STRONG":
+		*v = ReadConsistencyLevelStrong
+		return nil
+	default:
+		return fmt.Errorf("unknown enum value %q for %q", value, "ReadConsistencyLevel")
+	}
+}
+
+// Ptr returns a pointer to this enum value.
+func (v ReadConsistencyLevel) Ptr() *ReadConsistencyLevel {
+	return &v
+}
+
+// ToWire translates ReadConsistencyLevel into a Thrift-level intermediate
+// representation. This intermediate representation may be serialized
+// into bytes using a ThriftRW protocol implementation.
+//
+// Enums are represented as 32-bit integers over the wire.
+func (v ReadConsistencyLevel) ToWire() (wire.Value, error) {
+	return wire.NewValueI32(int32(v)), nil
+}
+
+// FromWire deserializes ReadConsistencyLevel from its Thrift-level
+// representation.
+//
+//   x, err := binaryProtocol.Decode(reader, wire.TI32)
+//   if err != nil {
+//     return ReadConsistencyLevel(0), err
+//   }
+//
+//   var v ReadConsistencyLevel
+//   if err := v.FromWire(x); err != nil {
+//     return ReadConsistencyLevel(0), err
+//   }
+//   return v, nil
+func (v *ReadConsistencyLevel) FromWire(w wire.Value) error {
+	*v = (ReadConsistencyLevel)(w.GetI32())
+	return nil
+}
+
+// String returns a readable string representation of ReadConsistencyLevel.
+func (v ReadConsistencyLevel) String() string {
+	w := int32(v)
+	switch w {
+	case 0:
+		return "EVENTUAL"
+	case 1:
+		return "STRONG"
+	}
+	return fmt.Sprintf("ReadConsistencyLevel(%d)", w)
+}
+
+// Equals returns true if this ReadConsistencyLevel value matches the provided
+// value.
+func (v ReadConsistencyLevel) Equals(rhs ReadConsistencyLevel) bool {
+	return v == rhs
+}
+
+// MarshalJSON serializes ReadConsistencyLevel into JSON.
+//
+// If the enum value is recognized, its name is returned. Otherwise,
+// its integer value is returned.
+//
+// This implements json.Marshaler.
+func (v ReadConsistencyLevel) MarshalJSON() ([]byte, error) {
+	switch int32(v) {
+	case 0:
+		return ([]byte)("\"EVENTUAL\""), nil
+	case 1:
+		return ([]byte)("\"STRONG\""), nil
+	}
+	return ([]byte)(strconv.FormatInt(int64(v), 10)), nil
+}
+
+// UnmarshalJSON attempts to decode ReadConsistencyLevel from its JSON
+// representation.
+//
+// This implementation supports both, numeric and string inputs. If a
+// string is provided, it must be a known enum name.
+//
+// This implements json.Unmarshaler.
+func (v *ReadConsistencyLevel) UnmarshalJSON(text []byte) error {
+	d := json.NewDecoder(bytes.NewReader(text))
+	d.UseNumber()
+	t, err := d.Token()
+	if err != nil {
+		return err
+	}
+
+	switch w := t.(type) {
+	case json.Number:
+		x, err := w.Int64()
+		if err != nil {
+			return err
+		}
+		if x > math.MaxInt32 {
+			return fmt.Errorf("enum overflow from JSON %q for %q", text, "ReadConsistencyLevel")
+		}
+		if x < math.MinInt32 {
+			return fmt.Errorf("enum underflow from JSON %q for %q", text, "ReadConsistencyLevel")
+		}
+		*v = (ReadConsistencyLevel)(x)
+		return nil
+	case string:
+		return v.UnmarshalText([]byte(w))
+	default:
+		return fmt.Errorf("invalid JSON value %q (%T) to unmarshal into %q", t, t, "ReadConsistencyLevel")
+	}
+}
+
 type GetMutableStateRequest struct {
 	DomainUUID          *string                   `json:"domainUUID,omitempty"`
 	Execution           *shared.WorkflowExecution `json:"execution,omitempty"`
 	ExpectedNextEventId *int64                    `json:"expectedNextEventId,omitempty"`
+	WaitTimeoutSeconds  *int32                    `json:"waitTimeoutSeconds,omitempty"`
+	BypassCache         *bool                     `json:"bypassCache,omitempty"`
+	RefreshCache        *bool                     `json:"refreshCache,omitempty"`
+	ConsistencyLevel    *ReadConsistencyLevel     `json:"consistencyLevel,omitempty"`
 }
 
 // ToWire translates a GetMutableStateRequest struct into a Thrift-level intermediate
@@ -320,7 +571,7 @@ type GetMutableStateRequest struct {
 //   }
 func (v *GetMutableStateRequest) ToWire() (wire.Value, error) {
 	var (
-		fields [3]wire.Field
+		fields [7]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -350,6 +601,38 @@ func (v *GetMutableStateRequest) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 30, Value: w}
 		i++
 	}
+	if v.WaitTimeoutSeconds != nil {
+		w, err = wire.NewValueI32(*(v.WaitTimeoutSeconds)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 40, Value: w}
+		i++
+	}
+	if v.BypassCache != nil {
+		w, err = wire.NewValueBool(*(v.BypassCache)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 50, Value: w}
+		i++
+	}
+	if v.RefreshCache != nil {
+		w, err = wire.NewValueBool(*(v.RefreshCache)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 60, Value: w}
+		i++
+	}
+	if v.ConsistencyLevel != nil {
+		w, err = v.ConsistencyLevel.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 70, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -360,6 +643,12 @@ func _WorkflowExecution_Read(w wire.Value) (*shared.WorkflowExecution, error) {
 	return &v, err
 }
 
+func _ReadConsistencyLevel_Read(w wire.Value) (ReadConsistencyLevel, error) {
+	var v ReadConsistencyLevel
+	err := v.FromWire(w)
+	return v, err
+}
+
 // FromWire deserializes a GetMutableStateRequest struct from its Thrift-level
 // representation. The Thrift-level representation may be obtained
 // from a ThriftRW protocol implementation.
@@ -409,6 +698,46 @@ func (v *GetMutableStateRequest) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 40:
+			if field.Value.Type() == wire.TI32 {
+				var x int32
+				x, err = field.Value.GetI32(), error(nil)
+				v.WaitTimeoutSeconds = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 50:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.BypassCache = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 60:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.RefreshCache = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 70:
+			if field.Value.Type() == wire.TI32 {
+				var x ReadConsistencyLevel
+				x, err = _ReadConsistencyLevel_Read(field.Value)
+				v.ConsistencyLevel = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -423,7 +752,7 @@ func (v *GetMutableStateRequest) String() string {
 		return "<nil>"
 	}
 
-	var fields [3]string
+	var fields [7]string
 	i := 0
 	if v.DomainUUID != nil {
 		fields[i] = fmt.Sprintf("DomainUUID: %v", *(v.DomainUUID))
@@ -437,6 +766,22 @@ func (v *GetMutableStateRequest) String() string {
 		fields[i] = fmt.Sprintf("ExpectedNextEventId: %v", *(v.ExpectedNextEventId))
 		i++
 	}
+	if v.WaitTimeoutSeconds != nil {
+		fields[i] = fmt.Sprintf("WaitTimeoutSeconds: %v", *(v.WaitTimeoutSeconds))
+		i++
+	}
+	if v.BypassCache != nil {
+		fields[i] = fmt.Sprintf("BypassCache: %v", *(v.BypassCache))
+		i++
+	}
+	if v.RefreshCache != nil {
+		fields[i] = fmt.Sprintf("RefreshCache: %v", *(v.RefreshCache))
+		i++
+	}
+	if v.ConsistencyLevel != nil {
+		fields[i] = fmt.Sprintf("ConsistencyLevel: %v", *(v.ConsistencyLevel))
+		i++
+	}
 
 	return fmt.Sprintf("GetMutableStateRequest{%v}", strings.Join(fields[:i], ", "))
 }
@@ -451,6 +796,16 @@ func _I64_EqualsPtr(lhs, rhs *int64) bool {
 	return lhs == nil && rhs == nil
 }
 
+func _ReadConsistencyLevel_EqualsPtr(lhs, rhs *ReadConsistencyLevel) bool {
+	if lhs != nil && rhs != nil {
+
+		x := *lhs
+		y := *rhs
+		return x.Equals(y)
+	}
+	return lhs == nil && rhs == nil
+}
+
 // Equals returns true if all the fields of this GetMutableStateRequest match the
 // provided GetMutableStateRequest.
 //
@@ -465,6 +820,18 @@ func (v *GetMutableStateRequest) Equals(rhs *GetMutableStateRequest) bool {
 	if !_I64_EqualsPtr(v.ExpectedNextEventId, rhs.ExpectedNextEventId) {
 		return false
 	}
+	if !_I32_EqualsPtr(v.WaitTimeoutSeconds, rhs.WaitTimeoutSeconds) {
+		return false
+	}
+	if !_Bool_EqualsPtr(v.BypassCache, rhs.BypassCache) {
+		return false
+	}
+	if !_Bool_EqualsPtr(v.RefreshCache, rhs.RefreshCache) {
+		return false
+	}
+	if !_ReadConsistencyLevel_EqualsPtr(v.ConsistencyLevel, rhs.ConsistencyLevel) {
+		return false
+	}
 
 	return true
 }
@@ -489,6 +856,46 @@ func (v *GetMutableStateRequest) GetExpectedNextEventId() (o int64) {
 	return
 }
 
+// GetWaitTimeoutSeconds returns the value of WaitTimeoutSeconds if it is set or its
+// zero value if it is unset.
+func (v *GetMutableStateRequest) GetWaitTimeoutSeconds() (o int32) {
+	if v.WaitTimeoutSeconds != nil {
+		return *v.WaitTimeoutSeconds
+	}
+
+	return
+}
+
+// GetBypassCache returns the value of BypassCache if it is set or its
+// zero value if it is unset.
+func (v *GetMutableStateRequest) GetBypassCache() (o bool) {
+	if v.BypassCache != nil {
+		return *v.BypassCache
+	}
+
+	return
+}
+
+// GetRefreshCache returns the value of RefreshCache if it is set or its
+// zero value if it is unset.
+func (v *GetMutableStateRequest) GetRefreshCache() (o bool) {
+	if v.RefreshCache != nil {
+		return *v.RefreshCache
+	}
+
+	return
+}
+
+// GetConsistencyLevel returns the value of ConsistencyLevel if it is set or its
+// zero value if it is unset.
+func (v *GetMutableStateRequest) GetConsistencyLevel() (o ReadConsistencyLevel) {
+	if v.ConsistencyLevel != nil {
+		return *v.ConsistencyLevel
+	}
+
+	return
+}
+
 type GetMutableStateResponse struct {
 	Execution                            *shared.WorkflowExecution `json:"execution,omitempty"`
 	WorkflowType                         *shared.WorkflowType      `json:"workflowType,omitempty"`
@@ -501,6 +908,14 @@ type GetMutableStateResponse struct {
 	ClientImpl                           *string                   `json:"clientImpl,omitempty"`
 	IsWorkflowRunning                    *bool                     `json:"isWorkflowRunning,omitempty"`
 	StickyTaskListScheduleToStartTimeout *int32                    `json:"stickyTaskListScheduleToStartTimeout,omitempty"`
+	PersistedNextEventId                 *int64                    `json:"persistedNextEventId,omitempty"`
+	StaleCacheDetected                   *bool                     `json:"staleCacheDetected,omitempty"`
+	StaleCacheCorrected                  *bool                     `json:"staleCacheCorrected,omitempty"`
+	StartTime                            *int64                    `json:"startTime,omitempty"`
+	CloseTime                            *int64                    `json:"closeTime,omitempty"`
+	Generation                           *int64                    `json:"generation,omitempty"`
+	LastSignalSequenceNumber             *int64                    `json:"lastSignalSequenceNumber,omitempty"`
+	HasBufferedEvents                    *bool                     `json:"hasBufferedEvents,omitempty"`
 }
 
 // ToWire translates a GetMutableStateResponse struct into a Thrift-level intermediate
@@ -520,7 +935,7 @@ type GetMutableStateResponse struct {
 //   }
 func (v *GetMutableStateResponse) ToWire() (wire.Value, error) {
 	var (
-		fields [11]wire.Field
+		fields [19]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -614,6 +1029,70 @@ func (v *GetMutableStateResponse) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 110, Value: w}
 		i++
 	}
+	if v.PersistedNextEventId != nil {
+		w, err = wire.NewValueI64(*(v.PersistedNextEventId)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 120, Value: w}
+		i++
+	}
+	if v.StaleCacheDetected != nil {
+		w, err = wire.NewValueBool(*(v.StaleCacheDetected)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 130, Value: w}
+		i++
+	}
+	if v.StaleCacheCorrected != nil {
+		w, err = wire.NewValueBool(*(v.StaleCacheCorrected)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 140, Value: w}
+		i++
+	}
+	if v.StartTime != nil {
+		w, err = wire.NewValueI64(*(v.StartTime)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 150, Value: w}
+		i++
+	}
+	if v.CloseTime != nil {
+		w, err = wire.NewValueI64(*(v.CloseTime)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 160, Value: w}
+		i++
+	}
+	if v.Generation != nil {
+		w, err = wire.NewValueI64(*(v.Generation)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 170, Value: w}
+		i++
+	}
+	if v.LastSignalSequenceNumber != nil {
+		w, err = wire.NewValueI64(*(v.LastSignalSequenceNumber)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 180, Value: w}
+		i++
+	}
+	if v.HasBufferedEvents != nil {
+		w, err = wire.NewValueBool(*(v.HasBufferedEvents)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 190, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -734,21 +1213,101 @@ func (v *GetMutableStateResponse) FromWire(w wire.Value) error {
 				}
 
 			}
-		case 100:
+		case 100:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.IsWorkflowRunning = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 110:
+			if field.Value.Type() == wire.TI32 {
+				var x int32
+				x, err = field.Value.GetI32(), error(nil)
+				v.StickyTaskListScheduleToStartTimeout = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 120:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.PersistedNextEventId = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 130:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.StaleCacheDetected = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 140:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.StaleCacheCorrected = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 150:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.StartTime = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 160:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.CloseTime = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 170:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.Generation = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 180:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.LastSignalSequenceNumber = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 190:
 			if field.Value.Type() == wire.TBool {
 				var x bool
 				x, err = field.Value.GetBool(), error(nil)
-				v.IsWorkflowRunning = &x
-				if err != nil {
-					return err
-				}
-
-			}
-		case 110:
-			if field.Value.Type() == wire.TI32 {
-				var x int32
-				x, err = field.Value.GetI32(), error(nil)
-				v.StickyTaskListScheduleToStartTimeout = &x
+				v.HasBufferedEvents = &x
 				if err != nil {
 					return err
 				}
@@ -767,7 +1326,7 @@ func (v *GetMutableStateResponse) String() string {
 		return "<nil>"
 	}
 
-	var fields [11]string
+	var fields [19]string
 	i := 0
 	if v.Execution != nil {
 		fields[i] = fmt.Sprintf("Execution: %v", v.Execution)
@@ -813,6 +1372,38 @@ func (v *GetMutableStateResponse) String() string {
 		fields[i] = fmt.Sprintf("StickyTaskListScheduleToStartTimeout: %v", *(v.StickyTaskListScheduleToStartTimeout))
 		i++
 	}
+	if v.PersistedNextEventId != nil {
+		fields[i] = fmt.Sprintf("PersistedNextEventId: %v", *(v.PersistedNextEventId))
+		i++
+	}
+	if v.StaleCacheDetected != nil {
+		fields[i] = fmt.Sprintf("StaleCacheDetected: %v", *(v.StaleCacheDetected))
+		i++
+	}
+	if v.StaleCacheCorrected != nil {
+		fields[i] = fmt.Sprintf("StaleCacheCorrected: %v", *(v.StaleCacheCorrected))
+		i++
+	}
+	if v.StartTime != nil {
+		fields[i] = fmt.Sprintf("StartTime: %v", *(v.StartTime))
+		i++
+	}
+	if v.CloseTime != nil {
+		fields[i] = fmt.Sprintf("CloseTime: %v", *(v.CloseTime))
+		i++
+	}
+	if v.Generation != nil {
+		fields[i] = fmt.Sprintf("Generation: %v", *(v.Generation))
+		i++
+	}
+	if v.LastSignalSequenceNumber != nil {
+		fields[i] = fmt.Sprintf("LastSignalSequenceNumber: %v", *(v.LastSignalSequenceNumber))
+		i++
+	}
+	if v.HasBufferedEvents != nil {
+		fields[i] = fmt.Sprintf("HasBufferedEvents: %v", *(v.HasBufferedEvents))
+		i++
+	}
 
 	return fmt.Sprintf("GetMutableStateResponse{%v}", strings.Join(fields[:i], ", "))
 }
@@ -875,6 +1466,30 @@ func (v *GetMutableStateResponse) Equals(rhs *GetMutableStateResponse) bool {
 	if !_I32_EqualsPtr(v.StickyTaskListScheduleToStartTimeout, rhs.StickyTaskListScheduleToStartTimeout) {
 		return false
 	}
+	if !_I64_EqualsPtr(v.PersistedNextEventId, rhs.PersistedNextEventId) {
+		return false
+	}
+	if !_Bool_EqualsPtr(v.StaleCacheDetected, rhs.StaleCacheDetected) {
+		return false
+	}
+	if !_Bool_EqualsPtr(v.StaleCacheCorrected, rhs.StaleCacheCorrected) {
+		return false
+	}
+	if !_I64_EqualsPtr(v.StartTime, rhs.StartTime) {
+		return false
+	}
+	if !_I64_EqualsPtr(v.CloseTime, rhs.CloseTime) {
+		return false
+	}
+	if !_I64_EqualsPtr(v.Generation, rhs.Generation) {
+		return false
+	}
+	if !_I64_EqualsPtr(v.LastSignalSequenceNumber, rhs.LastSignalSequenceNumber) {
+		return false
+	}
+	if !_Bool_EqualsPtr(v.HasBufferedEvents, rhs.HasBufferedEvents) {
+		return false
+	}
 
 	return true
 }
@@ -949,11 +1564,92 @@ func (v *GetMutableStateResponse) GetStickyTaskListScheduleToStartTimeout() (o i
 	return
 }
 
+// GetPersistedNextEventId returns the value of PersistedNextEventId if it is set or its
+// zero value if it is unset.
+func (v *GetMutableStateResponse) GetPersistedNextEventId() (o int64) {
+	if v.PersistedNextEventId != nil {
+		return *v.PersistedNextEventId
+	}
+
+	return
+}
+
+// GetStaleCacheDetected returns the value of StaleCacheDetected if it is set or its
+// zero value if it is unset.
+func (v *GetMutableStateResponse) GetStaleCacheDetected() (o bool) {
+	if v.StaleCacheDetected != nil {
+		return *v.StaleCacheDetected
+	}
+
+	return
+}
+
+// GetStaleCacheCorrected returns the value of StaleCacheCorrected if it is set or its
+// zero value if it is unset.
+func (v *GetMutableStateResponse) GetStaleCacheCorrected() (o bool) {
+	if v.StaleCacheCorrected != nil {
+		return *v.StaleCacheCorrected
+	}
+
+	return
+}
+
+// GetStartTime returns the value of StartTime if it is set or its
+// zero value if it is unset.
+func (v *GetMutableStateResponse) GetStartTime() (o int64) {
+	if v.StartTime != nil {
+		return *v.StartTime
+	}
+
+	return
+}
+
+// GetCloseTime returns the value of CloseTime if it is set or its
+// zero value if it is unset.
+func (v *GetMutableStateResponse) GetCloseTime() (o int64) {
+	if v.CloseTime != nil {
+		return *v.CloseTime
+	}
+
+	return
+}
+
+// GetGeneration returns the value of Generation if it is set or its
+// zero value if it is unset.
+func (v *GetMutableStateResponse) GetGeneration() (o int64) {
+	if v.Generation != nil {
+		return *v.Generation
+	}
+
+	return
+}
+
+// GetLastSignalSequenceNumber returns the value of LastSignalSequenceNumber if it is set or its
+// zero value if it is unset.
+func (v *GetMutableStateResponse) GetLastSignalSequenceNumber() (o int64) {
+	if v.LastSignalSequenceNumber != nil {
+		return *v.LastSignalSequenceNumber
+	}
+
+	return
+}
+
+// GetHasBufferedEvents returns the value of HasBufferedEvents if it is set or its
+// zero value if it is unset.
+func (v *GetMutableStateResponse) GetHasBufferedEvents() (o bool) {
+	if v.HasBufferedEvents != nil {
+		return *v.HasBufferedEvents
+	}
+
+	return
+}
+
 type ParentExecutionInfo struct {
 	DomainUUID  *string                   `json:"domainUUID,omitempty"`
 	Domain      *string                   `json:"domain,omitempty"`
 	Execution   *shared.WorkflowExecution `json:"execution,omitempty"`
 	InitiatedId *int64                    `json:"initiatedId,omitempty"`
+	Depth       *int32                    `json:"depth,omitempty"`
 }
 
 // ToWire translates a ParentExecutionInfo struct into a Thrift-level intermediate
@@ -973,7 +1669,7 @@ type ParentExecutionInfo struct {
 //   }
 func (v *ParentExecutionInfo) ToWire() (wire.Value, error) {
 	var (
-		fields [4]wire.Field
+		fields [5]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -1011,6 +1707,14 @@ func (v *ParentExecutionInfo) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 30, Value: w}
 		i++
 	}
+	if v.Depth != nil {
+		w, err = wire.NewValueI32(*(v.Depth)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 40, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -1074,6 +1778,16 @@ func (v *ParentExecutionInfo) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 40:
+			if field.Value.Type() == wire.TI32 {
+				var x int32
+				x, err = field.Value.GetI32(), error(nil)
+				v.Depth = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -1088,7 +1802,7 @@ func (v *ParentExecutionInfo) String() string {
 		return "<nil>"
 	}
 
-	var fields [4]string
+	var fields [5]string
 	i := 0
 	if v.DomainUUID != nil {
 		fields[i] = fmt.Sprintf("DomainUUID: %v", *(v.DomainUUID))
@@ -1106,6 +1820,10 @@ func (v *ParentExecutionInfo) String() string {
 		fields[i] = fmt.Sprintf("InitiatedId: %v", *(v.InitiatedId))
 		i++
 	}
+	if v.Depth != nil {
+		fields[i] = fmt.Sprintf("Depth: %v", *(v.Depth))
+		i++
+	}
 
 	return fmt.Sprintf("ParentExecutionInfo{%v}", strings.Join(fields[:i], ", "))
 }
@@ -1127,6 +1845,9 @@ func (v *ParentExecutionInfo) Equals(rhs *ParentExecutionInfo) bool {
 	if !_I64_EqualsPtr(v.InitiatedId, rhs.InitiatedId) {
 		return false
 	}
+	if !_I32_EqualsPtr(v.Depth, rhs.Depth) {
+		return false
+	}
 
 	return true
 }
@@ -1161,6 +1882,16 @@ func (v *ParentExecutionInfo) GetInitiatedId() (o int64) {
 	return
 }
 
+// GetDepth returns the value of Depth if it is set or its
+// zero value if it is unset.
+func (v *ParentExecutionInfo) GetDepth() (o int32) {
+	if v.Depth != nil {
+		return *v.Depth
+	}
+
+	return
+}
+
 type RecordActivityTaskHeartbeatRequest struct {
 	DomainUUID       *string                                    `json:"domainUUID,omitempty"`
 	HeartbeatRequest *shared.RecordActivityTaskHeartbeatRequest `json:"heartbeatRequest,omitempty"`
@@ -3377,8 +4108,156 @@ func (v *ResetStickyTaskListRequest) FromWire(w wire.Value) error {
 
 			}
 		case 20:
-			if field.Value.Type() == wire.TStruct {
-				v.Execution, err = _WorkflowExecution_Read(field.Value)
+			if field.Value.Type() == wire.TStruct {
+				v.Execution, err = _WorkflowExecution_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable string representation of a ResetStickyTaskListRequest
+// struct.
+func (v *ResetStickyTaskListRequest) String() string {
+	if v == nil {
+		return "<nil>"
+	}
+
+	var fields [2]string
+	i := 0
+	if v.DomainUUID != nil {
+		fields[i] = fmt.Sprintf("DomainUUID: %v", *(v.DomainUUID))
+		i++
+	}
+	if v.Execution != nil {
+		fields[i] = fmt.Sprintf("Execution: %v", v.Execution)
+		i++
+	}
+
+	return fmt.Sprintf("ResetStickyTaskListRequest{%v}", strings.Join(fields[:i], ", "))
+}
+
+// Equals returns true if all the fields of this ResetStickyTaskListRequest match the
+// provided ResetStickyTaskListRequest.
+//
+// This function performs a deep comparison.
+func (v *ResetStickyTaskListRequest) Equals(rhs *ResetStickyTaskListRequest) bool {
+	if !_String_EqualsPtr(v.DomainUUID, rhs.DomainUUID) {
+		return false
+	}
+	if !((v.Execution == nil && rhs.Execution == nil) || (v.Execution != nil && rhs.Execution != nil && v.Execution.Equals(rhs.Execution))) {
+		return false
+	}
+
+	return true
+}
+
+// GetDomainUUID returns the value of DomainUUID if it is set or its
+// zero value if it is unset.
+func (v *ResetStickyTaskListRequest) GetDomainUUID() (o string) {
+	if v.DomainUUID != nil {
+		return *v.DomainUUID
+	}
+
+	return
+}
+
+type ResetStickyTaskListResponse struct {
+	StickinessCleared *bool                             `json:"stickinessCleared,omitempty"`
+	SkippedReason     *ResetStickyTaskListSkippedReason `json:"skippedReason,omitempty"`
+}
+
+// ToWire translates a ResetStickyTaskListResponse struct into a Thrift-level intermediate
+// representation. This intermediate representation may be serialized
+// into bytes using a ThriftRW protocol implementation.
+//
+// An error is returned if the struct or any of its fields failed to
+// validate.
+//
+//   x, err := v.ToWire()
+//   if err != nil {
+//     return err
+//   }
+//
+//   if err := binaryProtocol.Encode(x, writer); err != nil {
+//     return err
+//   }
+func (v *ResetStickyTaskListResponse) ToWire() (wire.Value, error) {
+	var (
+		fields [2]wire.Field
+		i      int = 0
+		w      wire.Value
+		err    error
+	)
+
+	if v.StickinessCleared != nil {
+		w, err = wire.NewValueBool(*(v.StickinessCleared)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 10, Value: w}
+		i++
+	}
+	if v.SkippedReason != nil {
+		w, err = v.SkippedReason.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 20, Value: w}
+		i++
+	}
+
+	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
+}
+
+func _ResetStickyTaskListSkippedReason_Read(w wire.Value) (ResetStickyTaskListSkippedReason, error) {
+	var v ResetStickyTaskListSkippedReason
+	err := v.FromWire(w)
+	return v, err
+}
+
+// FromWire deserializes a ResetStickyTaskListResponse struct from its Thrift-level
+// representation. The Thrift-level representation may be obtained
+// from a ThriftRW protocol implementation.
+//
+// An error is returned if we were unable to build a ResetStickyTaskListResponse struct
+// from the provided intermediate representation.
+//
+//   x, err := binaryProtocol.Decode(reader, wire.TStruct)
+//   if err != nil {
+//     return nil, err
+//   }
+//
+//   var v ResetStickyTaskListResponse
+//   if err := v.FromWire(x); err != nil {
+//     return nil, err
+//   }
+//   return &v, nil
+func (v *ResetStickyTaskListResponse) FromWire(w wire.Value) error {
+	var err error
+
+	for _, field := range w.GetStruct().Fields {
+		switch field.ID {
+		case 10:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.StickinessCleared = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 20:
+			if field.Value.Type() == wire.TI32 {
+				var x ResetStickyTaskListSkippedReason
+				x, err = _ResetStickyTaskListSkippedReason_Read(field.Value)
+				v.SkippedReason = &x
 				if err != nil {
 					return err
 				}
@@ -3390,126 +4269,205 @@ func (v *ResetStickyTaskListRequest) FromWire(w wire.Value) error {
 	return nil
 }
 
-// String returns a readable string representation of a ResetStickyTaskListRequest
+// String returns a readable string representation of a ResetStickyTaskListResponse
 // struct.
-func (v *ResetStickyTaskListRequest) String() string {
+func (v *ResetStickyTaskListResponse) String() string {
 	if v == nil {
 		return "<nil>"
 	}
 
 	var fields [2]string
 	i := 0
-	if v.DomainUUID != nil {
-		fields[i] = fmt.Sprintf("DomainUUID: %v", *(v.DomainUUID))
+	if v.StickinessCleared != nil {
+		fields[i] = fmt.Sprintf("StickinessCleared: %v", *(v.StickinessCleared))
 		i++
 	}
-	if v.Execution != nil {
-		fields[i] = fmt.Sprintf("Execution: %v", v.Execution)
+	if v.SkippedReason != nil {
+		fields[i] = fmt.Sprintf("SkippedReason: %v", *(v.SkippedReason))
 		i++
 	}
 
-	return fmt.Sprintf("ResetStickyTaskListRequest{%v}", strings.Join(fields[:i], ", "))
+	return fmt.Sprintf("ResetStickyTaskListResponse{%v}", strings.Join(fields[:i], ", "))
 }
 
-// Equals returns true if all the fields of this ResetStickyTaskListRequest match the
-// provided ResetStickyTaskListRequest.
+func _ResetStickyTaskListSkippedReason_EqualsPtr(lhs, rhs *ResetStickyTaskListSkippedReason) bool {
+	if lhs != nil && rhs != nil {
+
+		x := *lhs
+		y := *rhs
+		return x.Equals(y)
+	}
+	return lhs == nil && rhs == nil
+}
+
+// Equals returns true if all the fields of this ResetStickyTaskListResponse match the
+// provided ResetStickyTaskListResponse.
 //
 // This function performs a deep comparison.
-func (v *ResetStickyTaskListRequest) Equals(rhs *ResetStickyTaskListRequest) bool {
-	if !_String_EqualsPtr(v.DomainUUID, rhs.DomainUUID) {
+func (v *ResetStickyTaskListResponse) Equals(rhs *ResetStickyTaskListResponse) bool {
+	if !_Bool_EqualsPtr(v.StickinessCleared, rhs.StickinessCleared) {
 		return false
 	}
-	if !((v.Execution == nil && rhs.Execution == nil) || (v.Execution != nil && rhs.Execution != nil && v.Execution.Equals(rhs.Execution))) {
+	if !_ResetStickyTaskListSkippedReason_EqualsPtr(v.SkippedReason, rhs.SkippedReason) {
 		return false
 	}
 
 	return true
 }
 
-// GetDomainUUID returns the value of DomainUUID if it is set or its
+// GetStickinessCleared returns the value of StickinessCleared if it is set or its
 // zero value if it is unset.
-func (v *ResetStickyTaskListRequest) GetDomainUUID() (o string) {
-	if v.DomainUUID != nil {
-		return *v.DomainUUID
+func (v *ResetStickyTaskListResponse) GetStickinessCleared() (o bool) {
+	if v.StickinessCleared != nil {
+		return *v.StickinessCleared
 	}
 
 	return
 }
 
-type ResetStickyTaskListResponse struct {
+// GetSkippedReason returns the value of SkippedReason if it is set or its
+// zero value if it is unset.
+func (v *ResetStickyTaskListResponse) GetSkippedReason() (o ResetStickyTaskListSkippedReason) {
+	if v.SkippedReason != nil {
+		return *v.SkippedReason
+	}
+
+	return
 }
 
-// ToWire translates a ResetStickyTaskListResponse struct into a Thrift-level intermediate
-// representation. This intermediate representation may be serialized
-// into bytes using a ThriftRW protocol implementation.
-//
-// An error is returned if the struct or any of its fields failed to
-// validate.
-//
-//   x, err := v.ToWire()
-//   if err != nil {
-//     return err
-//   }
+type ResetStickyTaskListSkippedReason int32
+
+const (
+	ResetStickyTaskListSkippedReasonWorkflowNotRunning ResetStickyTaskListSkippedReason = 0
+	ResetStickyTaskListSkippedReasonNotSticky          ResetStickyTaskListSkippedReason = 1
+)
+
+// ResetStickyTaskListSkippedReason_Values returns all recognized values of ResetStickyTaskListSkippedReason.
+func ResetStickyTaskListSkippedReason_Values() []ResetStickyTaskListSkippedReason {
+	return []ResetStickyTaskListSkippedReason{
+		ResetStickyTaskListSkippedReasonWorkflowNotRunning,
+		ResetStickyTaskListSkippedReasonNotSticky,
+	}
+}
+
+// UnmarshalText tries to decode ResetStickyTaskListSkippedReason from a byte slice
+// containing its name.
 //
-//   if err := binaryProtocol.Encode(x, writer); err != nil {
-//     return err
-//   }
-func (v *ResetStickyTaskListResponse) ToWire() (wire.Value, error) {
-	var (
-		fields [0]wire.Field
-		i      int = 0
-	)
+//   var v ResetStickyTaskListSkippedReason
+//   err := v.UnmarshalText([]byte("WORKFLOW_NOT_RUNNING"))
+func (v *ResetStickyTaskListSkippedReason) UnmarshalText(value []byte) error {
+	switch string(value) {
+	case "WORKFLOW_NOT_RUNNING":
+		*v = ResetStickyTaskListSkippedReasonWorkflowNotRunning
+		return nil
+	case "NOT_STICKY":
+		*v = ResetStickyTaskListSkippedReasonNotSticky
+		return nil
+	default:
+		return fmt.Errorf("unknown enum value %q for %q", value, "ResetStickyTaskListSkippedReason")
+	}
+}
 
-	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
+// Ptr returns a pointer to this enum value.
+func (v ResetStickyTaskListSkippedReason) Ptr() *ResetStickyTaskListSkippedReason {
+	return &v
 }
 
-// FromWire deserializes a ResetStickyTaskListResponse struct from its Thrift-level
-// representation. The Thrift-level representation may be obtained
-// from a ThriftRW protocol implementation.
+// ToWire translates ResetStickyTaskListSkippedReason into a Thrift-level intermediate
+// representation. This intermediate representation may be serialized
+// into bytes using a ThriftRW protocol implementation.
 //
-// An error is returned if we were unable to build a ResetStickyTaskListResponse struct
-// from the provided intermediate representation.
+// Enums are represented as 32-bit integers over the wire.
+func (v ResetStickyTaskListSkippedReason) ToWire() (wire.Value, error) {
+	return wire.NewValueI32(int32(v)), nil
+}
+
+// FromWire deserializes ResetStickyTaskListSkippedReason from its Thrift-level
+// representation.
 //
-//   x, err := binaryProtocol.Decode(reader, wire.TStruct)
+//   x, err := binaryProtocol.Decode(reader, wire.TI32)
 //   if err != nil {
-//     return nil, err
+//     return ResetStickyTaskListSkippedReason(0), err
 //   }
 //
-//   var v ResetStickyTaskListResponse
+//   var v ResetStickyTaskListSkippedReason
 //   if err := v.FromWire(x); err != nil {
-//     return nil, err
+//     return ResetStickyTaskListSkippedReason(0), err
 //   }
-//   return &v, nil
-func (v *ResetStickyTaskListResponse) FromWire(w wire.Value) error {
-
-	for _, field := range w.GetStruct().Fields {
-		switch field.ID {
-		}
-	}
-
+//   return v, nil
+func (v *ResetStickyTaskListSkippedReason) FromWire(w wire.Value) error {
+	*v = (ResetStickyTaskListSkippedReason)(w.GetI32())
 	return nil
 }
 
-// String returns a readable string representation of a ResetStickyTaskListResponse
-// struct.
-func (v *ResetStickyTaskListResponse) String() string {
-	if v == nil {
-		return "<nil>"
+// String returns a readable string representation of ResetStickyTaskListSkippedReason.
+func (v ResetStickyTaskListSkippedReason) String() string {
+	w := int32(v)
+	switch w {
+	case 0:
+		return "WORKFLOW_NOT_RUNNING"
+	case 1:
+		return "NOT_STICKY"
 	}
+	return fmt.Sprintf("ResetStickyTaskListSkippedReason(%d)", w)
+}
 
-	var fields [0]string
-	i := 0
+// Equals returns true if this ResetStickyTaskListSkippedReason value matches the provided
+// value.
+func (v ResetStickyTaskListSkippedReason) Equals(rhs ResetStickyTaskListSkippedReason) bool {
+	return v == rhs
+}
 
-	return fmt.Sprintf("ResetStickyTaskListResponse{%v}", strings.Join(fields[:i], ", "))
+// MarshalJSON serializes ResetStickyTaskListSkippedReason into JSON.
+//
+// If the enum value is recognized, its name is returned. Otherwise,
+// its integer value is returned.
+//
+// This implements json.Marshaler.
+func (v ResetStickyTaskListSkippedReason) MarshalJSON() ([]byte, error) {
+	switch int32(v) {
+	case 0:
+		return ([]byte)("\"WORKFLOW_NOT_RUNNING\""), nil
+	case 1:
+		return ([]byte)("\"NOT_STICKY\""), nil
+	}
+	return ([]byte)(strconv.FormatInt(int64(v), 10)), nil
 }
 
-// Equals returns true if all the fields of this ResetStickyTaskListResponse match the
-// provided ResetStickyTaskListResponse.
+// UnmarshalJSON attempts to decode ResetStickyTaskListSkippedReason from its JSON
+// representation.
 //
-// This function performs a deep comparison.
-func (v *ResetStickyTaskListResponse) Equals(rhs *ResetStickyTaskListResponse) bool {
+// This implementation supports both, numeric and string inputs. If a
+// string is provided, it must be a known enum name.
+//
+// This implements json.Unmarshaler.
+func (v *ResetStickyTaskListSkippedReason) UnmarshalJSON(text []byte) error {
+	d := json.NewDecoder(bytes.NewReader(text))
+	d.UseNumber()
+	t, err := d.Token()
+	if err != nil {
+		return err
+	}
 
-	return true
+	switch w := t.(type) {
+	case json.Number:
+		x, err := w.Int64()
+		if err != nil {
+			return err
+		}
+		if x > math.MaxInt32 {
+			return fmt.Errorf("enum overflow from JSON %q for %q", text, "ResetStickyTaskListSkippedReason")
+		}
+		if x < math.MinInt32 {
+			return fmt.Errorf("enum underflow from JSON %q for %q", text, "ResetStickyTaskListSkippedReason")
+		}
+		*v = (ResetStickyTaskListSkippedReason)(x)
+		return nil
+	case string:
+		return v.UnmarshalText([]byte(w))
+	default:
+		return fmt.Errorf("invalid JSON value %q (%T) to unmarshal into %q", t, t, "ResetStickyTaskListSkippedReason")
+	}
 }
 
 type RespondActivityTaskCanceledRequest struct {
@@ -4096,6 +5054,220 @@ func (v *RespondDecisionTaskCompletedRequest) GetDomainUUID() (o string) {
 	return
 }
 
+type RespondDecisionTaskCompletedResponse struct {
+	StartedResponse           *RecordDecisionTaskStartedResponse `json:"startedResponse,omitempty"`
+	ActivityScheduledEventIds map[string]int64                   `json:"activityScheduledEventIds,omitempty"`
+}
+
+type _Map_String_I64_MapItemList map[string]int64
+
+func (m _Map_String_I64_MapItemList) ForEach(f func(wire.MapItem) error) error {
+	for k, v := range m {
+		kw, err := wire.NewValueString(k), error(nil)
+		if err != nil {
+			return err
+		}
+
+		vw, err := wire.NewValueI64(v), error(nil)
+		if err != nil {
+			return err
+		}
+		err = f(wire.MapItem{Key: kw, Value: vw})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m _Map_String_I64_MapItemList) Size() int {
+	return len(m)
+}
+
+func (_Map_String_I64_MapItemList) KeyType() wire.Type {
+	return wire.TBinary
+}
+
+func (_Map_String_I64_MapItemList) ValueType() wire.Type {
+	return wire.TI64
+}
+
+func (_Map_String_I64_MapItemList) Close() {}
+
+// ToWire translates a RespondDecisionTaskCompletedResponse struct into a Thrift-level intermediate
+// representation. This intermediate representation may be serialized
+// into bytes using a ThriftRW protocol implementation.
+//
+// An error is returned if the struct or any of its fields failed to
+// validate.
+//
+//   x, err := v.ToWire()
+//   if err != nil {
+//     return err
+//   }
+//
+//   if err := binaryProtocol.Encode(x, writer); err != nil {
+//     return err
+//   }
+func (v *RespondDecisionTaskCompletedResponse) ToWire() (wire.Value, error) {
+	var (
+		fields [2]wire.Field
+		i      int = 0
+		w      wire.Value
+		err    error
+	)
+
+	if v.StartedResponse != nil {
+		w, err = v.StartedResponse.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 10, Value: w}
+		i++
+	}
+	if v.ActivityScheduledEventIds != nil {
+		w, err = wire.NewValueMap(_Map_String_I64_MapItemList(v.ActivityScheduledEventIds)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 20, Value: w}
+		i++
+	}
+
+	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
+}
+
+func _RecordDecisionTaskStartedResponse_Read(w wire.Value) (*RecordDecisionTaskStartedResponse, error) {
+	var v RecordDecisionTaskStartedResponse
+	err := v.FromWire(w)
+	return &v, err
+}
+
+func _Map_String_I64_Read(m wire.MapItemList) (map[string]int64, error) {
+	if m.KeyType() != wire.TBinary {
+		return nil, nil
+	}
+
+	if m.ValueType() != wire.TI64 {
+		return nil, nil
+	}
+
+	o := make(map[string]int64, m.Size())
+	err := m.ForEach(func(x wire.MapItem) error {
+		k, err := x.Key.GetString(), error(nil)
+		if err != nil {
+			return err
+		}
+
+		v, err := x.Value.GetI64(), error(nil)
+		if err != nil {
+			return err
+		}
+
+		o[k] = v
+		return nil
+	})
+	m.Close()
+	return o, err
+}
+
+// FromWire deserializes a RespondDecisionTaskCompletedResponse struct from its Thrift-level
+// representation. The Thrift-level representation may be obtained
+// from a ThriftRW protocol implementation.
+//
+// An error is returned if we were unable to build a RespondDecisionTaskCompletedResponse struct
+// from the provided intermediate representation.
+//
+//   x, err := binaryProtocol.Decode(reader, wire.TStruct)
+//   if err != nil {
+//     return nil, err
+//   }
+//
+//   var v RespondDecisionTaskCompletedResponse
+//   if err := v.FromWire(x); err != nil {
+//     return nil, err
+//   }
+//   return &v, nil
+func (v *RespondDecisionTaskCompletedResponse) FromWire(w wire.Value) error {
+	var err error
+
+	for _, field := range w.GetStruct().Fields {
+		switch field.ID {
+		case 10:
+			if field.Value.Type() == wire.TStruct {
+				v.StartedResponse, err = _RecordDecisionTaskStartedResponse_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
+			}
+		case 20:
+			if field.Value.Type() == wire.TMap {
+				v.ActivityScheduledEventIds, err = _Map_String_I64_Read(field.Value.GetMap())
+				if err != nil {
+					return err
+				}
+
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable string representation of a RespondDecisionTaskCompletedResponse
+// struct.
+func (v *RespondDecisionTaskCompletedResponse) String() string {
+	if v == nil {
+		return "<nil>"
+	}
+
+	var fields [2]string
+	i := 0
+	if v.StartedResponse != nil {
+		fields[i] = fmt.Sprintf("StartedResponse: %v", v.StartedResponse)
+		i++
+	}
+	if v.ActivityScheduledEventIds != nil {
+		fields[i] = fmt.Sprintf("ActivityScheduledEventIds: %v", v.ActivityScheduledEventIds)
+		i++
+	}
+
+	return fmt.Sprintf("RespondDecisionTaskCompletedResponse{%v}", strings.Join(fields[:i], ", "))
+}
+
+func _Map_String_I64_Equals(lhs, rhs map[string]int64) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+
+	for lk, lv := range lhs {
+		rv, ok := rhs[lk]
+		if !ok {
+			return false
+		}
+		if !(lv == rv) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equals returns true if all the fields of this RespondDecisionTaskCompletedResponse match the
+// provided RespondDecisionTaskCompletedResponse.
+//
+// This function performs a deep comparison.
+func (v *RespondDecisionTaskCompletedResponse) Equals(rhs *RespondDecisionTaskCompletedResponse) bool {
+	if !((v.StartedResponse == nil && rhs.StartedResponse == nil) || (v.StartedResponse != nil && rhs.StartedResponse != nil && v.StartedResponse.Equals(rhs.StartedResponse))) {
+		return false
+	}
+	if !((v.ActivityScheduledEventIds == nil && rhs.ActivityScheduledEventIds == nil) || (v.ActivityScheduledEventIds != nil && rhs.ActivityScheduledEventIds != nil && _Map_String_I64_Equals(v.ActivityScheduledEventIds, rhs.ActivityScheduledEventIds))) {
+		return false
+	}
+
+	return true
+}
+
 type RespondDecisionTaskFailedRequest struct {
 	DomainUUID    *string                                  `json:"domainUUID,omitempty"`
 	FailedRequest *shared.RespondDecisionTaskFailedRequest `json:"failedRequest,omitempty"`
@@ -34,6 +34,281 @@ import (
 	"strings"
 )
 
+type ActivityCheckpoint struct {
+	SequenceNumber *int64 `json:"sequenceNumber,omitempty"`
+	Payload        []byte `json:"payload,omitempty"`
+}
+
+// ToWire translates a ActivityCheckpoint struct into a Thrift-level intermediate
+// representation. This intermediate representation may be serialized
+// into bytes using a ThriftRW protocol implementation.
+//
+// An error is returned if the struct or any of its fields failed to
+// validate.
+//
+//   x, err := v.ToWire()
+//   if err != nil {
+//     return err
+//   }
+//
+//   if err := binaryProtocol.Encode(x, writer); err != nil {
+//     return err
+//   }
+func (v *ActivityCheckpoint) ToWire() (wire.Value, error) {
+	var (
+		fields [2]wire.Field
+		i      int = 0
+		w      wire.Value
+		err    error
+	)
+
+	if v.SequenceNumber != nil {
+		w, err = wire.NewValueI64(*(v.SequenceNumber)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 10, Value: w}
+		i++
+	}
+	if v.Payload != nil {
+		w, err = wire.NewValueBinary(v.Payload), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 20, Value: w}
+		i++
+	}
+
+	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
+}
+
+// FromWire deserializes a ActivityCheckpoint struct from its Thrift-level
+// representation. The Thrift-level representation may be obtained
+// from a ThriftRW protocol implementation.
+//
+// An error is returned if we were unable to build a ActivityCheckpoint struct
+// from the provided intermediate representation.
+//
+//   x, err := binaryProtocol.Decode(reader, wire.TStruct)
+//   if err != nil {
+//     return nil, err
+//   }
+//
+//   var v ActivityCheckpoint
+//   if err := v.FromWire(x); err != nil {
+//     return nil, err
+//   }
+//   return &v, nil
+func (v *ActivityCheckpoint) FromWire(w wire.Value) error {
+	var err error
+
+	for _, field := range w.GetStruct().Fields {
+		switch field.ID {
+		case 10:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.SequenceNumber = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 20:
+			if field.Value.Type() == wire.TBinary {
+				v.Payload, err = field.Value.GetBinary(), error(nil)
+				if err != nil {
+					return err
+				}
+
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable string representation of a ActivityCheckpoint
+// struct.
+func (v *ActivityCheckpoint) String() string {
+	if v == nil {
+		return "<nil>"
+	}
+
+	var fields [2]string
+	i := 0
+	if v.SequenceNumber != nil {
+		fields[i] = fmt.Sprintf("SequenceNumber: %v", *(v.SequenceNumber))
+		i++
+	}
+	if v.Payload != nil {
+		fields[i] = fmt.Sprintf("Payload: %v", v.Payload)
+		i++
+	}
+
+	return fmt.Sprintf("ActivityCheckpoint{%v}", strings.Join(fields[:i], ", "))
+}
+
+// Equals returns true if all the fields of this ActivityCheckpoint match the
+// provided ActivityCheckpoint.
+//
+// This function performs a deep comparison.
+func (v *ActivityCheckpoint) Equals(rhs *ActivityCheckpoint) bool {
+	if !_I64_EqualsPtr(v.SequenceNumber, rhs.SequenceNumber) {
+		return false
+	}
+	if !((v.Payload == nil && rhs.Payload == nil) || (v.Payload != nil && rhs.Payload != nil && bytes.Equal(v.Payload, rhs.Payload))) {
+		return false
+	}
+
+	return true
+}
+
+// GetSequenceNumber returns the value of SequenceNumber if it is set or its
+// zero value if it is unset.
+func (v *ActivityCheckpoint) GetSequenceNumber() (o int64) {
+	if v.SequenceNumber != nil {
+		return *v.SequenceNumber
+	}
+
+	return
+}
+
+type ActivityFailureType int32
+
+const (
+	ActivityFailureTypeRetryable ActivityFailureType = 0
+	ActivityFailureTypeTerminal  ActivityFailureType = 1
+)
+
+// ActivityFailureType_Values returns all recognized values of ActivityFailureType.
+func ActivityFailureType_Values() []ActivityFailureType {
+	return []ActivityFailureType{
+		ActivityFailureTypeRetryable,
+		ActivityFailureTypeTerminal,
+	}
+}
+
+// UnmarshalText tries to decode ActivityFailureType from a byte slice
+// containing its name.
+//
+//   var v ActivityFailureType
+//   err := v.UnmarshalText([]byte("RETRYABLE"))
+func (v *ActivityFailureType) UnmarshalText(value []byte) error {
+	switch string(value) {
+	case "RETRYABLE":
+		*v = ActivityFailureTypeRetryable
+		return nil
+	case "TERMINAL":
+		*v = ActivityFailureTypeTerminal
+		return nil
+	default:
+		return fmt.Errorf("unknown enum value %q for %q", value, "ActivityFailureType")
+	}
+}
+
+// Ptr returns a pointer to this enum value.
+func (v ActivityFailureType) Ptr() *ActivityFailureType {
+	return &v
+}
+
+// ToWire translates ActivityFailureType into a Thrift-level intermediate
+// representation. This intermediate representation may be serialized
+// into bytes using a ThriftRW protocol implementation.
+//
+// Enums are represented as 32-bit integers over the wire.
+func (v ActivityFailureType) ToWire() (wire.Value, error) {
+	return wire.NewValueI32(int32(v)), nil
+}
+
+// FromWire deserializes ActivityFailureType from its Thrift-level
+// representation.
+//
+//   x, err := binaryProtocol.Decode(reader, wire.TI32)
+//   if err != nil {
+//     return ActivityFailureType(0), err
+//   }
+//
+//   var v ActivityFailureType
+//   if err := v.FromWire(x); err != nil {
+//     return ActivityFailureType(0), err
+//   }
+//   return v, nil
+func (v *ActivityFailureType) FromWire(w wire.Value) error {
+	*v = (ActivityFailureType)(w.GetI32())
+	return nil
+}
+
+// String returns a readable string representation of ActivityFailureType.
+func (v ActivityFailureType) String() string {
+	w := int32(v)
+	switch w {
+	case 0:
+		return "RETRYABLE"
+	case 1:
+		return "TERMINAL"
+	}
+	return fmt.Sprintf("ActivityFailureType(%d)", w)
+}
+
+// Equals returns true if this ActivityFailureType value matches the provided
+// value.
+func (v ActivityFailureType) Equals(rhs ActivityFailureType) bool {
+	return v == rhs
+}
+
+// MarshalJSON serializes ActivityFailureType into JSON.
+//
+// If the enum value is recognized, its name is returned. Otherwise,
+// its integer value is returned.
+//
+// This implements json.Marshaler.
+func (v ActivityFailureType) MarshalJSON() ([]byte, error) {
+	switch int32(v) {
+	case 0:
+		return ([]byte)("\"RETRYABLE\""), nil
+	case 1:
+		return ([]byte)("\"TERMINAL\""), nil
+	}
+	return ([]byte)(strconv.FormatInt(int64(v), 10)), nil
+}
+
+// UnmarshalJSON attempts to decode ActivityFailureType from its JSON
+// representation.
+//
+// This implementation supports both, numeric and string inputs. If a
+// string is provided, it must be a known enum name.
+//
+// This implements json.Unmarshaler.
+func (v *ActivityFailureType) UnmarshalJSON(text []byte) error {
+	d := json.NewDecoder(bytes.NewReader(text))
+	d.UseNumber()
+	t, err := d.Token()
+	if err != nil {
+		return err
+	}
+
+	switch w := t.(type) {
+	case json.Number:
+		x, err := w.Int64()
+		if err != nil {
+			return err
+		}
+		if x > math.MaxInt32 {
+			return fmt.Errorf("enum overflow from JSON %q for %q", text, "ActivityFailureType")
+		}
+		if x < math.MinInt32 {
+			return fmt.Errorf("enum underflow from JSON %q for %q", text, "ActivityFailureType")
+		}
+		*v = (ActivityFailureType)(x)
+		return nil
+	case string:
+		return v.UnmarshalText([]byte(w))
+	default:
+		return fmt.Errorf("invalid JSON value %q (%T) to unmarshal into %q", t, t, "ActivityFailureType")
+	}
+}
+
 type ActivityTaskCancelRequestedEventAttributes struct {
 	ActivityId                   *string `json:"activityId,omitempty"`
 	DecisionTaskCompletedEventId *int64  `json:"decisionTaskCompletedEventId,omitempty"`
@@ -667,11 +942,12 @@ func (v *ActivityTaskCompletedEventAttributes) GetIdentity() (o string) {
 }
 
 type ActivityTaskFailedEventAttributes struct {
-	Reason           *string `json:"reason,omitempty"`
-	Details          []byte  `json:"details,omitempty"`
-	ScheduledEventId *int64  `json:"scheduledEventId,omitempty"`
-	StartedEventId   *int64  `json:"startedEventId,omitempty"`
-	Identity         *string `json:"identity,omitempty"`
+	Reason           *string              `json:"reason,omitempty"`
+	Details          []byte               `json:"details,omitempty"`
+	ScheduledEventId *int64               `json:"scheduledEventId,omitempty"`
+	StartedEventId   *int64               `json:"startedEventId,omitempty"`
+	Identity         *string              `json:"identity,omitempty"`
+	FailureType      *ActivityFailureType `json:"failureType,omitempty"`
 }
 
 // ToWire translates a ActivityTaskFailedEventAttributes struct into a Thrift-level intermediate
@@ -691,7 +967,7 @@ type ActivityTaskFailedEventAttributes struct {
 //   }
 func (v *ActivityTaskFailedEventAttributes) ToWire() (wire.Value, error) {
 	var (
-		fields [5]wire.Field
+		fields [6]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -737,10 +1013,24 @@ func (v *ActivityTaskFailedEventAttributes) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 50, Value: w}
 		i++
 	}
+	if v.FailureType != nil {
+		w, err = v.FailureType.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 60, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
 
+func _ActivityFailureType_Read(w wire.Value) (ActivityFailureType, error) {
+	var v ActivityFailureType
+	err := v.FromWire(w)
+	return v, err
+}
+
 // FromWire deserializes a ActivityTaskFailedEventAttributes struct from its Thrift-level
 // representation. The Thrift-level representation may be obtained
 // from a ThriftRW protocol implementation.
@@ -810,6 +1100,16 @@ func (v *ActivityTaskFailedEventAttributes) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 60:
+			if field.Value.Type() == wire.TI32 {
+				var x ActivityFailureType
+				x, err = _ActivityFailureType_Read(field.Value)
+				v.FailureType = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -824,7 +1124,7 @@ func (v *ActivityTaskFailedEventAttributes) String() string {
 		return "<nil>"
 	}
 
-	var fields [5]string
+	var fields [6]string
 	i := 0
 	if v.Reason != nil {
 		fields[i] = fmt.Sprintf("Reason: %v", *(v.Reason))
@@ -846,10 +1146,24 @@ func (v *ActivityTaskFailedEventAttributes) String() string {
 		fields[i] = fmt.Sprintf("Identity: %v", *(v.Identity))
 		i++
 	}
+	if v.FailureType != nil {
+		fields[i] = fmt.Sprintf("FailureType: %v", *(v.FailureType))
+		i++
+	}
 
 	return fmt.Sprintf("ActivityTaskFailedEventAttributes{%v}", strings.Join(fields[:i], ", "))
 }
 
+func _ActivityFailureType_EqualsPtr(lhs, rhs *ActivityFailureType) bool {
+	if lhs != nil && rhs != nil {
+
+		x := *lhs
+		y := *rhs
+		return x.Equals(y)
+	}
+	return lhs == nil && rhs == nil
+}
+
 // Equals returns true if all the fields of this ActivityTaskFailedEventAttributes match the
 // provided ActivityTaskFailedEventAttributes.
 //
@@ -870,6 +1184,9 @@ func (v *ActivityTaskFailedEventAttributes) Equals(rhs *ActivityTaskFailedEventA
 	if !_String_EqualsPtr(v.Identity, rhs.Identity) {
 		return false
 	}
+	if !_ActivityFailureType_EqualsPtr(v.FailureType, rhs.FailureType) {
+		return false
+	}
 
 	return true
 }
@@ -914,6 +1231,16 @@ func (v *ActivityTaskFailedEventAttributes) GetIdentity() (o string) {
 	return
 }
 
+// GetFailureType returns the value of FailureType if it is set or its
+// zero value if it is unset.
+func (v *ActivityTaskFailedEventAttributes) GetFailureType() (o ActivityFailureType) {
+	if v.FailureType != nil {
+		return *v.FailureType
+	}
+
+	return
+}
+
 type ActivityTaskScheduledEventAttributes struct {
 	ActivityId                    *string       `json:"activityId,omitempty"`
 	ActivityType                  *ActivityType `json:"activityType,omitempty"`
@@ -925,6 +1252,8 @@ type ActivityTaskScheduledEventAttributes struct {
 	StartToCloseTimeoutSeconds    *int32        `json:"startToCloseTimeoutSeconds,omitempty"`
 	HeartbeatTimeoutSeconds       *int32        `json:"heartbeatTimeoutSeconds,omitempty"`
 	DecisionTaskCompletedEventId  *int64        `json:"decisionTaskCompletedEventId,omitempty"`
+	Priority                      *int32        `json:"priority,omitempty"`
+	DispatchDeadlineSeconds       *int32        `json:"dispatchDeadlineSeconds,omitempty"`
 }
 
 // ToWire translates a ActivityTaskScheduledEventAttributes struct into a Thrift-level intermediate
@@ -944,7 +1273,7 @@ type ActivityTaskScheduledEventAttributes struct {
 //   }
 func (v *ActivityTaskScheduledEventAttributes) ToWire() (wire.Value, error) {
 	var (
-		fields [10]wire.Field
+		fields [12]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -1030,17 +1359,33 @@ func (v *ActivityTaskScheduledEventAttributes) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 90, Value: w}
 		i++
 	}
-
-	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
-}
-
-func _ActivityType_Read(w wire.Value) (*ActivityType, error) {
-	var v ActivityType
-	err := v.FromWire(w)
-	return &v, err
-}
-
-func _TaskList_Read(w wire.Value) (*TaskList, error) {
+	if v.Priority != nil {
+		w, err = wire.NewValueI32(*(v.Priority)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 100, Value: w}
+		i++
+	}
+	if v.DispatchDeadlineSeconds != nil {
+		w, err = wire.NewValueI32(*(v.DispatchDeadlineSeconds)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 110, Value: w}
+		i++
+	}
+
+	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
+}
+
+func _ActivityType_Read(w wire.Value) (*ActivityType, error) {
+	var v ActivityType
+	err := v.FromWire(w)
+	return &v, err
+}
+
+func _TaskList_Read(w wire.Value) (*TaskList, error) {
 	var v TaskList
 	err := v.FromWire(w)
 	return &v, err
@@ -1161,6 +1506,26 @@ func (v *ActivityTaskScheduledEventAttributes) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 100:
+			if field.Value.Type() == wire.TI32 {
+				var x int32
+				x, err = field.Value.GetI32(), error(nil)
+				v.Priority = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 110:
+			if field.Value.Type() == wire.TI32 {
+				var x int32
+				x, err = field.Value.GetI32(), error(nil)
+				v.DispatchDeadlineSeconds = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -1175,7 +1540,7 @@ func (v *ActivityTaskScheduledEventAttributes) String() string {
 		return "<nil>"
 	}
 
-	var fields [10]string
+	var fields [12]string
 	i := 0
 	if v.ActivityId != nil {
 		fields[i] = fmt.Sprintf("ActivityId: %v", *(v.ActivityId))
@@ -1217,6 +1582,14 @@ func (v *ActivityTaskScheduledEventAttributes) String() string {
 		fields[i] = fmt.Sprintf("DecisionTaskCompletedEventId: %v", *(v.DecisionTaskCompletedEventId))
 		i++
 	}
+	if v.Priority != nil {
+		fields[i] = fmt.Sprintf("Priority: %v", *(v.Priority))
+		i++
+	}
+	if v.DispatchDeadlineSeconds != nil {
+		fields[i] = fmt.Sprintf("DispatchDeadlineSeconds: %v", *(v.DispatchDeadlineSeconds))
+		i++
+	}
 
 	return fmt.Sprintf("ActivityTaskScheduledEventAttributes{%v}", strings.Join(fields[:i], ", "))
 }
@@ -1266,6 +1639,12 @@ func (v *ActivityTaskScheduledEventAttributes) Equals(rhs *ActivityTaskScheduled
 	if !_I64_EqualsPtr(v.DecisionTaskCompletedEventId, rhs.DecisionTaskCompletedEventId) {
 		return false
 	}
+	if !_I32_EqualsPtr(v.Priority, rhs.Priority) {
+		return false
+	}
+	if !_I32_EqualsPtr(v.DispatchDeadlineSeconds, rhs.DispatchDeadlineSeconds) {
+		return false
+	}
 
 	return true
 }
@@ -1340,6 +1719,26 @@ func (v *ActivityTaskScheduledEventAttributes) GetDecisionTaskCompletedEventId()
 	return
 }
 
+// GetPriority returns the value of Priority if it is set or its
+// zero value if it is unset.
+func (v *ActivityTaskScheduledEventAttributes) GetPriority() (o int32) {
+	if v.Priority != nil {
+		return *v.Priority
+	}
+
+	return
+}
+
+// GetDispatchDeadlineSeconds returns the value of DispatchDeadlineSeconds if it is set or its
+// zero value if it is unset.
+func (v *ActivityTaskScheduledEventAttributes) GetDispatchDeadlineSeconds() (o int32) {
+	if v.DispatchDeadlineSeconds != nil {
+		return *v.DispatchDeadlineSeconds
+	}
+
+	return
+}
+
 type ActivityTaskStartedEventAttributes struct {
 	ScheduledEventId *int64  `json:"scheduledEventId,omitempty"`
 	Identity         *string `json:"identity,omitempty"`
@@ -1982,6 +2381,206 @@ func (v *BadRequestError) Error() string {
 	return v.String()
 }
 
+type BatchedSignal struct {
+	SignalName *string `json:"signalName,omitempty"`
+	Input      []byte  `json:"input,omitempty"`
+	Control    []byte  `json:"control,omitempty"`
+	RequestId  *string `json:"requestId,omitempty"`
+}
+
+// ToWire translates a BatchedSignal struct into a Thrift-level intermediate
+// representation. This intermediate representation may be serialized
+// into bytes using a ThriftRW protocol implementation.
+//
+// An error is returned if the struct or any of its fields failed to
+// validate.
+//
+//   x, err := v.ToWire()
+//   if err != nil {
+//     return err
+//   }
+//
+//   if err := binaryProtocol.Encode(x, writer); err != nil {
+//     return err
+//   }
+func (v *BatchedSignal) ToWire() (wire.Value, error) {
+	var (
+		fields [4]wire.Field
+		i      int = 0
+		w      wire.Value
+		err    error
+	)
+
+	if v.SignalName != nil {
+		w, err = wire.NewValueString(*(v.SignalName)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 10, Value: w}
+		i++
+	}
+	if v.Input != nil {
+		w, err = wire.NewValueBinary(v.Input), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 20, Value: w}
+		i++
+	}
+	if v.Control != nil {
+		w, err = wire.NewValueBinary(v.Control), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 30, Value: w}
+		i++
+	}
+	if v.RequestId != nil {
+		w, err = wire.NewValueString(*(v.RequestId)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 40, Value: w}
+		i++
+	}
+
+	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
+}
+
+// FromWire deserializes a BatchedSignal struct from its Thrift-level
+// representation. The Thrift-level representation may be obtained
+// from a ThriftRW protocol implementation.
+//
+// An error is returned if we were unable to build a BatchedSignal struct
+// from the provided intermediate representation.
+//
+//   x, err := binaryProtocol.Decode(reader, wire.TStruct)
+//   if err != nil {
+//     return nil, err
+//   }
+//
+//   var v BatchedSignal
+//   if err := v.FromWire(x); err != nil {
+//     return nil, err
+//   }
+//   return &v, nil
+func (v *BatchedSignal) FromWire(w wire.Value) error {
+	var err error
+
+	for _, field := range w.GetStruct().Fields {
+		switch field.ID {
+		case 10:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.SignalName = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 20:
+			if field.Value.Type() == wire.TBinary {
+				v.Input, err = field.Value.GetBinary(), error(nil)
+				if err != nil {
+					return err
+				}
+
+			}
+		case 30:
+			if field.Value.Type() == wire.TBinary {
+				v.Control, err = field.Value.GetBinary(), error(nil)
+				if err != nil {
+					return err
+				}
+
+			}
+		case 40:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.RequestId = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable string representation of a BatchedSignal
+// struct.
+func (v *BatchedSignal) String() string {
+	if v == nil {
+		return "<nil>"
+	}
+
+	var fields [4]string
+	i := 0
+	if v.SignalName != nil {
+		fields[i] = fmt.Sprintf("SignalName: %v", *(v.SignalName))
+		i++
+	}
+	if v.Input != nil {
+		fields[i] = fmt.Sprintf("Input: %v", v.Input)
+		i++
+	}
+	if v.Control != nil {
+		fields[i] = fmt.Sprintf("Control: %v", v.Control)
+		i++
+	}
+	if v.RequestId != nil {
+		fields[i] = fmt.Sprintf("RequestId: %v", *(v.RequestId))
+		i++
+	}
+
+	return fmt.Sprintf("BatchedSignal{%v}", strings.Join(fields[:i], ", "))
+}
+
+// Equals returns true if all the fields of this BatchedSignal match the
+// provided BatchedSignal.
+//
+// This function performs a deep comparison.
+func (v *BatchedSignal) Equals(rhs *BatchedSignal) bool {
+	if !_String_EqualsPtr(v.SignalName, rhs.SignalName) {
+		return false
+	}
+	if !((v.Input == nil && rhs.Input == nil) || (v.Input != nil && rhs.Input != nil && bytes.Equal(v.Input, rhs.Input))) {
+		return false
+	}
+	if !((v.Control == nil && rhs.Control == nil) || (v.Control != nil && rhs.Control != nil && bytes.Equal(v.Control, rhs.Control))) {
+		return false
+	}
+	if !_String_EqualsPtr(v.RequestId, rhs.RequestId) {
+		return false
+	}
+
+	return true
+}
+
+// GetSignalName returns the value of SignalName if it is set or its
+// zero value if it is unset.
+func (v *BatchedSignal) GetSignalName() (o string) {
+	if v.SignalName != nil {
+		return *v.SignalName
+	}
+
+	return
+}
+
+// GetRequestId returns the value of RequestId if it is set or its
+// zero value if it is unset.
+func (v *BatchedSignal) GetRequestId() (o string) {
+	if v.RequestId != nil {
+		return *v.RequestId
+	}
+
+	return
+}
+
 type CancelExternalWorkflowExecutionFailedCause int32
 
 const (
@@ -3342,12 +3941,14 @@ type ChildWorkflowExecutionFailedCause int32
 
 const (
 	ChildWorkflowExecutionFailedCauseWorkflowAlreadyRunning ChildWorkflowExecutionFailedCause = 0
+	ChildWorkflowExecutionFailedCauseCanceledBeforeStart    ChildWorkflowExecutionFailedCause = 1
 )
 
 // ChildWorkflowExecutionFailedCause_Values returns all recognized values of ChildWorkflowExecutionFailedCause.
 func ChildWorkflowExecutionFailedCause_Values() []ChildWorkflowExecutionFailedCause {
 	return []ChildWorkflowExecutionFailedCause{
 		ChildWorkflowExecutionFailedCauseWorkflowAlreadyRunning,
+		ChildWorkflowExecutionFailedCauseCanceledBeforeStart,
 	}
 }
 
@@ -3361,6 +3962,9 @@ func (v *ChildWorkflowExecutionFailedCause) UnmarshalText(value []byte) error {
 	case "WORKFLOW_ALREADY_RUNNING":
 		*v = ChildWorkflowExecutionFailedCauseWorkflowAlreadyRunning
 		return nil
+	case "CANCELED_BEFORE_START":
+		*v = ChildWorkflowExecutionFailedCauseCanceledBeforeStart
+		return nil
 	default:
 		return fmt.Errorf("unknown enum value %q for %q", value, "ChildWorkflowExecutionFailedCause")
 	}
@@ -3404,6 +4008,8 @@ func (v ChildWorkflowExecutionFailedCause) String() string {
 	switch w {
 	case 0:
 		return "WORKFLOW_ALREADY_RUNNING"
+	case 1:
+		return "CANCELED_BEFORE_START"
 	}
 	return fmt.Sprintf("ChildWorkflowExecutionFailedCause(%d)", w)
 }
@@ -3424,6 +4030,8 @@ func (v ChildWorkflowExecutionFailedCause) MarshalJSON() ([]byte, error) {
 	switch int32(v) {
 	case 0:
 		return ([]byte)("\"WORKFLOW_ALREADY_RUNNING\""), nil
+	case 1:
+		return ([]byte)("\"CANCELED_BEFORE_START\""), nil
 	}
 	return ([]byte)(strconv.FormatInt(int64(v), 10)), nil
 }
@@ -4694,6 +5302,7 @@ type ContinueAsNewWorkflowExecutionDecisionAttributes struct {
 	Input                               []byte        `json:"input,omitempty"`
 	ExecutionStartToCloseTimeoutSeconds *int32        `json:"executionStartToCloseTimeoutSeconds,omitempty"`
 	TaskStartToCloseTimeoutSeconds      *int32        `json:"taskStartToCloseTimeoutSeconds,omitempty"`
+	CarrySignalMetadata                 *bool         `json:"carrySignalMetadata,omitempty"`
 }
 
 // ToWire translates a ContinueAsNewWorkflowExecutionDecisionAttributes struct into a Thrift-level intermediate
@@ -4713,7 +5322,7 @@ type ContinueAsNewWorkflowExecutionDecisionAttributes struct {
 //   }
 func (v *ContinueAsNewWorkflowExecutionDecisionAttributes) ToWire() (wire.Value, error) {
 	var (
-		fields [5]wire.Field
+		fields [6]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -4759,6 +5368,14 @@ func (v *ContinueAsNewWorkflowExecutionDecisionAttributes) ToWire() (wire.Value,
 		fields[i] = wire.Field{ID: 50, Value: w}
 		i++
 	}
+	if v.CarrySignalMetadata != nil {
+		w, err = wire.NewValueBool(*(v.CarrySignalMetadata)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 60, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -4828,6 +5445,16 @@ func (v *ContinueAsNewWorkflowExecutionDecisionAttributes) FromWire(w wire.Value
 					return err
 				}
 
+			}
+		case 60:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.CarrySignalMetadata = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -4842,7 +5469,7 @@ func (v *ContinueAsNewWorkflowExecutionDecisionAttributes) String() string {
 		return "<nil>"
 	}
 
-	var fields [5]string
+	var fields [6]string
 	i := 0
 	if v.WorkflowType != nil {
 		fields[i] = fmt.Sprintf("WorkflowType: %v", v.WorkflowType)
@@ -4864,6 +5491,10 @@ func (v *ContinueAsNewWorkflowExecutionDecisionAttributes) String() string {
 		fields[i] = fmt.Sprintf("TaskStartToCloseTimeoutSeconds: %v", *(v.TaskStartToCloseTimeoutSeconds))
 		i++
 	}
+	if v.CarrySignalMetadata != nil {
+		fields[i] = fmt.Sprintf("CarrySignalMetadata: %v", *(v.CarrySignalMetadata))
+		i++
+	}
 
 	return fmt.Sprintf("ContinueAsNewWorkflowExecutionDecisionAttributes{%v}", strings.Join(fields[:i], ", "))
 }
@@ -4888,6 +5519,9 @@ func (v *ContinueAsNewWorkflowExecutionDecisionAttributes) Equals(rhs *ContinueA
 	if !_I32_EqualsPtr(v.TaskStartToCloseTimeoutSeconds, rhs.TaskStartToCloseTimeoutSeconds) {
 		return false
 	}
+	if !_Bool_EqualsPtr(v.CarrySignalMetadata, rhs.CarrySignalMetadata) {
+		return false
+	}
 
 	return true
 }
@@ -4912,6 +5546,16 @@ func (v *ContinueAsNewWorkflowExecutionDecisionAttributes) GetTaskStartToCloseTi
 	return
 }
 
+// GetCarrySignalMetadata returns the value of CarrySignalMetadata if it is set or its
+// zero value if it is unset.
+func (v *ContinueAsNewWorkflowExecutionDecisionAttributes) GetCarrySignalMetadata() (o bool) {
+	if v.CarrySignalMetadata != nil {
+		return *v.CarrySignalMetadata
+	}
+
+	return
+}
+
 type Decision struct {
 	DecisionType                                             *DecisionType                                             `json:"decisionType,omitempty"`
 	ScheduleActivityTaskDecisionAttributes                   *ScheduleActivityTaskDecisionAttributes                   `json:"scheduleActivityTaskDecisionAttributes,omitempty"`
@@ -5635,6 +6279,10 @@ const (
 	DecisionTaskFailedCauseWorkflowWorkerUnhandledFailure                      DecisionTaskFailedCause = 13
 	DecisionTaskFailedCauseBadSignalWorkflowExecutionAttributes                DecisionTaskFailedCause = 14
 	DecisionTaskFailedCauseBadStartChildExecutionAttributes                    DecisionTaskFailedCause = 15
+	DecisionTaskFailedCauseChildWorkflowDepthExceeded                          DecisionTaskFailedCause = 16
+	DecisionTaskFailedCauseForceFailedByOperator                               DecisionTaskFailedCause = 17
+	DecisionTaskFailedCauseShardOverloaded                                     DecisionTaskFailedCause = 18
+	DecisionTaskFailedCauseDecisionTypeDisabled                                DecisionTaskFailedCause = 19
 )
 
 // DecisionTaskFailedCause_Values returns all recognized values of DecisionTaskFailedCause.
@@ -5656,6 +6304,10 @@ func DecisionTaskFailedCause_Values() []DecisionTaskFailedCause {
 		DecisionTaskFailedCauseWorkflowWorkerUnhandledFailure,
 		DecisionTaskFailedCauseBadSignalWorkflowExecutionAttributes,
 		DecisionTaskFailedCauseBadStartChildExecutionAttributes,
+		DecisionTaskFailedCauseChildWorkflowDepthExceeded,
+		DecisionTaskFailedCauseForceFailedByOperator,
+		DecisionTaskFailedCauseShardOverloaded,
+		DecisionTaskFailedCauseDecisionTypeDisabled,
 	}
 }
 
@@ -5714,6 +6366,18 @@ func (v *DecisionTaskFailedCause) UnmarshalText(value []byte) error {
 	case "BAD_START_CHILD_EXECUTION_ATTRIBUTES":
 		*v = DecisionTaskFailedCauseBadStartChildExecutionAttributes
 		return nil
+	case "CHILD_WORKFLOW_DEPTH_EXCEEDED":
+		*v = DecisionTaskFailedCauseChildWorkflowDepthExceeded
+		return nil
+	case "FORCE_FAILED_BY_OPERATOR":
+		*v = DecisionTaskFailedCauseForceFailedByOperator
+		return nil
+	case "SHARD_OVERLOADED":
+		*v = DecisionTaskFailedCauseShardOverloaded
+		return nil
+	case "DECISION_TYPE_DISABLED":
+		*v = DecisionTaskFailedCauseDecisionTypeDisabled
+		return nil
 	default:
 		return fmt.Errorf("unknown enum value %q for %q", value, "DecisionTaskFailedCause")
 	}
@@ -5787,6 +6451,14 @@ func (v DecisionTaskFailedCause) String() string {
 		return "BAD_SIGNAL_WORKFLOW_EXECUTION_ATTRIBUTES"
 	case 15:
 		return "BAD_START_CHILD_EXECUTION_ATTRIBUTES"
+	case 16:
+		return "CHILD_WORKFLOW_DEPTH_EXCEEDED"
+	case 17:
+		return "FORCE_FAILED_BY_OPERATOR"
+	case 18:
+		return "SHARD_OVERLOADED"
+	case 19:
+		return "DECISION_TYPE_DISABLED"
 	}
 	return fmt.Sprintf("DecisionTaskFailedCause(%d)", w)
 }
@@ -5837,6 +6509,14 @@ func (v DecisionTaskFailedCause) MarshalJSON() ([]byte, error) {
 		return ([]byte)("\"BAD_SIGNAL_WORKFLOW_EXECUTION_ATTRIBUTES\""), nil
 	case 15:
 		return ([]byte)("\"BAD_START_CHILD_EXECUTION_ATTRIBUTES\""), nil
+	case 16:
+		return ([]byte)("\"CHILD_WORKFLOW_DEPTH_EXCEEDED\""), nil
+	case 17:
+		return ([]byte)("\"FORCE_FAILED_BY_OPERATOR\""), nil
+	case 18:
+		return ([]byte)("\"SHARD_OVERLOADED\""), nil
+	case 19:
+		return ([]byte)("\"DECISION_TYPE_DISABLED\""), nil
 	}
 	return ([]byte)(strconv.FormatInt(int64(v), 10)), nil
 }
@@ -7598,36 +8278,12 @@ type DescribeTaskListResponse struct {
 	Pollers []*PollerInfo `json:"pollers,omitempty"`
 }
 
-type _List_PollerInfo_ValueList []*PollerInfo
-
-func (v _List_PollerInfo_ValueList) ForEach(f func(wire.Value) error) error {
-	for i, x := range v {
-		if x == nil {
-			return fmt.Errorf("invalid [%v]: value is nil", i)
-		}
-		w, err := x.ToWire()
-		if err != nil {
-			return err
-		}
-		err = f(w)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (v _List_PollerInfo_ValueList) Size() int {
-	return len(v)
-}
-
-func (_List_PollerInfo_ValueList) ValueType() wire.Type {
-	return wire.TStruct
+type RequestCancelWorkflowExecutionResponse struct {
+	CancelRequestId        *string `json:"cancelRequestId,omitempty"`
+	CancelRequestedEventId *int64  `json:"cancelRequestedEventId,omitempty"`
 }
 
-func (_List_PollerInfo_ValueList) Close() {}
-
-// ToWire translates a DescribeTaskListResponse struct into a Thrift-level intermediate
+// ToWire translates a RequestCancelWorkflowExecutionResponse struct into a Thrift-level intermediate
 // representation. This intermediate representation may be serialized
 // into bytes using a ThriftRW protocol implementation.
 //
@@ -7642,55 +8298,39 @@ func (_List_PollerInfo_ValueList) Close() {}
 //   if err := binaryProtocol.Encode(x, writer); err != nil {
 //     return err
 //   }
-func (v *DescribeTaskListResponse) ToWire() (wire.Value, error) {
+func (v *RequestCancelWorkflowExecutionResponse) ToWire() (wire.Value, error) {
 	var (
-		fields [1]wire.Field
+		fields [2]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
 	)
 
-	if v.Pollers != nil {
-		w, err = wire.NewValueList(_List_PollerInfo_ValueList(v.Pollers)), error(nil)
+	if v.CancelRequestId != nil {
+		w, err = wire.NewValueString(*(v.CancelRequestId)), error(nil)
 		if err != nil {
 			return w, err
 		}
 		fields[i] = wire.Field{ID: 10, Value: w}
 		i++
 	}
-
-	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
-}
-
-func _PollerInfo_Read(w wire.Value) (*PollerInfo, error) {
-	var v PollerInfo
-	err := v.FromWire(w)
-	return &v, err
-}
-
-func _List_PollerInfo_Read(l wire.ValueList) ([]*PollerInfo, error) {
-	if l.ValueType() != wire.TStruct {
-		return nil, nil
-	}
-
-	o := make([]*PollerInfo, 0, l.Size())
-	err := l.ForEach(func(x wire.Value) error {
-		i, err := _PollerInfo_Read(x)
+	if v.CancelRequestedEventId != nil {
+		w, err = wire.NewValueI64(*(v.CancelRequestedEventId)), error(nil)
 		if err != nil {
-			return err
+			return w, err
 		}
-		o = append(o, i)
-		return nil
-	})
-	l.Close()
-	return o, err
+		fields[i] = wire.Field{ID: 20, Value: w}
+		i++
+	}
+
+	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
 
-// FromWire deserializes a DescribeTaskListResponse struct from its Thrift-level
+// FromWire deserializes a RequestCancelWorkflowExecutionResponse struct from its Thrift-level
 // representation. The Thrift-level representation may be obtained
 // from a ThriftRW protocol implementation.
 //
-// An error is returned if we were unable to build a DescribeTaskListResponse struct
+// An error is returned if we were unable to build a RequestCancelWorkflowExecutionResponse struct
 // from the provided intermediate representation.
 //
 //   x, err := binaryProtocol.Decode(reader, wire.TStruct)
@@ -7698,19 +8338,31 @@ func _List_PollerInfo_Read(l wire.ValueList) ([]*PollerInfo, error) {
 //     return nil, err
 //   }
 //
-//   var v DescribeTaskListResponse
+//   var v RequestCancelWorkflowExecutionResponse
 //   if err := v.FromWire(x); err != nil {
 //     return nil, err
 //   }
 //   return &v, nil
-func (v *DescribeTaskListResponse) FromWire(w wire.Value) error {
+func (v *RequestCancelWorkflowExecutionResponse) FromWire(w wire.Value) error {
 	var err error
 
 	for _, field := range w.GetStruct().Fields {
 		switch field.ID {
 		case 10:
-			if field.Value.Type() == wire.TList {
-				v.Pollers, err = _List_PollerInfo_Read(field.Value.GetList())
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.CancelRequestId = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 20:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.CancelRequestedEventId = &x
 				if err != nil {
 					return err
 				}
@@ -7722,56 +8374,71 @@ func (v *DescribeTaskListResponse) FromWire(w wire.Value) error {
 	return nil
 }
 
-// String returns a readable string representation of a DescribeTaskListResponse
+// String returns a readable string representation of a RequestCancelWorkflowExecutionResponse
 // struct.
-func (v *DescribeTaskListResponse) String() string {
+func (v *RequestCancelWorkflowExecutionResponse) String() string {
 	if v == nil {
 		return "<nil>"
 	}
 
-	var fields [1]string
+	var fields [2]string
 	i := 0
-	if v.Pollers != nil {
-		fields[i] = fmt.Sprintf("Pollers: %v", v.Pollers)
+	if v.CancelRequestId != nil {
+		fields[i] = fmt.Sprintf("CancelRequestId: %v", *(v.CancelRequestId))
+		i++
+	}
+	if v.CancelRequestedEventId != nil {
+		fields[i] = fmt.Sprintf("CancelRequestedEventId: %v", *(v.CancelRequestedEventId))
 		i++
 	}
 
-	return fmt.Sprintf("DescribeTaskListResponse{%v}", strings.Join(fields[:i], ", "))
+	return fmt.Sprintf("RequestCancelWorkflowExecutionResponse{%v}", strings.Join(fields[:i], ", "))
 }
 
-func _List_PollerInfo_Equals(lhs, rhs []*PollerInfo) bool {
-	if len(lhs) != len(rhs) {
+// Equals returns true if all the fields of this RequestCancelWorkflowExecutionResponse match the
+// provided RequestCancelWorkflowExecutionResponse.
+//
+// This function performs a deep comparison.
+func (v *RequestCancelWorkflowExecutionResponse) Equals(rhs *RequestCancelWorkflowExecutionResponse) bool {
+	if !_String_EqualsPtr(v.CancelRequestId, rhs.CancelRequestId) {
 		return false
 	}
-
-	for i, lv := range lhs {
-		rv := rhs[i]
-		if !lv.Equals(rv) {
-			return false
-		}
+	if !_I64_EqualsPtr(v.CancelRequestedEventId, rhs.CancelRequestedEventId) {
+		return false
 	}
 
 	return true
 }
 
-// Equals returns true if all the fields of this DescribeTaskListResponse match the
-// provided DescribeTaskListResponse.
-//
-// This function performs a deep comparison.
-func (v *DescribeTaskListResponse) Equals(rhs *DescribeTaskListResponse) bool {
-	if !((v.Pollers == nil && rhs.Pollers == nil) || (v.Pollers != nil && rhs.Pollers != nil && _List_PollerInfo_Equals(v.Pollers, rhs.Pollers))) {
-		return false
+// GetCancelRequestId returns the value of CancelRequestId if it is set or its
+// zero value if it is unset.
+func (v *RequestCancelWorkflowExecutionResponse) GetCancelRequestId() (o string) {
+	if v.CancelRequestId != nil {
+		return *v.CancelRequestId
 	}
 
-	return true
+	return
 }
 
-type DescribeWorkflowExecutionRequest struct {
-	Domain    *string            `json:"domain,omitempty"`
-	Execution *WorkflowExecution `json:"execution,omitempty"`
+// GetCancelRequestedEventId returns the value of CancelRequestedEventId if it is set or its
+// zero value if it is unset.
+func (v *RequestCancelWorkflowExecutionResponse) GetCancelRequestedEventId() (o int64) {
+	if v.CancelRequestedEventId != nil {
+		return *v.CancelRequestedEventId
+	}
+
+	return
 }
 
-// ToWire translates a DescribeWorkflowExecutionRequest struct into a Thrift-level intermediate
+type ResetPoint struct {
+	EventId        *int64  `json:"eventId,omitempty"`
+	BinaryChecksum *string `json:"binaryChecksum,omitempty"`
+	Resettable     *bool   `json:"resettable,omitempty"`
+	CreatedTime    *int64  `json:"createdTime,omitempty"`
+	Safe           *bool   `json:"safe,omitempty"`
+}
+
+// ToWire translates a ResetPoint struct into a Thrift-level intermediate
 // representation. This intermediate representation may be serialized
 // into bytes using a ThriftRW protocol implementation.
 //
@@ -7786,39 +8453,63 @@ type DescribeWorkflowExecutionRequest struct {
 //   if err := binaryProtocol.Encode(x, writer); err != nil {
 //     return err
 //   }
-func (v *DescribeWorkflowExecutionRequest) ToWire() (wire.Value, error) {
+func (v *ResetPoint) ToWire() (wire.Value, error) {
 	var (
-		fields [2]wire.Field
+		fields [5]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
 	)
 
-	if v.Domain != nil {
-		w, err = wire.NewValueString(*(v.Domain)), error(nil)
+	if v.EventId != nil {
+		w, err = wire.NewValueI64(*(v.EventId)), error(nil)
 		if err != nil {
 			return w, err
 		}
 		fields[i] = wire.Field{ID: 10, Value: w}
 		i++
 	}
-	if v.Execution != nil {
-		w, err = v.Execution.ToWire()
+	if v.BinaryChecksum != nil {
+		w, err = wire.NewValueString(*(v.BinaryChecksum)), error(nil)
 		if err != nil {
 			return w, err
 		}
 		fields[i] = wire.Field{ID: 20, Value: w}
 		i++
 	}
+	if v.Resettable != nil {
+		w, err = wire.NewValueBool(*(v.Resettable)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 30, Value: w}
+		i++
+	}
+	if v.CreatedTime != nil {
+		w, err = wire.NewValueI64(*(v.CreatedTime)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 40, Value: w}
+		i++
+	}
+	if v.Safe != nil {
+		w, err = wire.NewValueBool(*(v.Safe)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 50, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
 
-// FromWire deserializes a DescribeWorkflowExecutionRequest struct from its Thrift-level
+// FromWire deserializes a ResetPoint struct from its Thrift-level
 // representation. The Thrift-level representation may be obtained
 // from a ThriftRW protocol implementation.
 //
-// An error is returned if we were unable to build a DescribeWorkflowExecutionRequest struct
+// An error is returned if we were unable to build a ResetPoint struct
 // from the provided intermediate representation.
 //
 //   x, err := binaryProtocol.Decode(reader, wire.TStruct)
@@ -7826,29 +8517,61 @@ func (v *DescribeWorkflowExecutionRequest) ToWire() (wire.Value, error) {
 //     return nil, err
 //   }
 //
-//   var v DescribeWorkflowExecutionRequest
+//   var v ResetPoint
 //   if err := v.FromWire(x); err != nil {
 //     return nil, err
 //   }
 //   return &v, nil
-func (v *DescribeWorkflowExecutionRequest) FromWire(w wire.Value) error {
+func (v *ResetPoint) FromWire(w wire.Value) error {
 	var err error
 
 	for _, field := range w.GetStruct().Fields {
 		switch field.ID {
 		case 10:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.EventId = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 20:
 			if field.Value.Type() == wire.TBinary {
 				var x string
 				x, err = field.Value.GetString(), error(nil)
-				v.Domain = &x
+				v.BinaryChecksum = &x
 				if err != nil {
 					return err
 				}
 
 			}
-		case 20:
-			if field.Value.Type() == wire.TStruct {
-				v.Execution, err = _WorkflowExecution_Read(field.Value)
+		case 30:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.Resettable = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 40:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.CreatedTime = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 50:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.Safe = &x
 				if err != nil {
 					return err
 				}
@@ -7860,48 +8583,631 @@ func (v *DescribeWorkflowExecutionRequest) FromWire(w wire.Value) error {
 	return nil
 }
 
-// String returns a readable string representation of a DescribeWorkflowExecutionRequest
+// String returns a readable string representation of a ResetPoint
 // struct.
-func (v *DescribeWorkflowExecutionRequest) String() string {
+func (v *ResetPoint) String() string {
 	if v == nil {
 		return "<nil>"
 	}
 
-	var fields [2]string
+	var fields [5]string
 	i := 0
-	if v.Domain != nil {
-		fields[i] = fmt.Sprintf("Domain: %v", *(v.Domain))
+	if v.EventId != nil {
+		fields[i] = fmt.Sprintf("EventId: %v", *(v.EventId))
 		i++
 	}
-	if v.Execution != nil {
-		fields[i] = fmt.Sprintf("Execution: %v", v.Execution)
+	if v.BinaryChecksum != nil {
+		fields[i] = fmt.Sprintf("BinaryChecksum: %v", *(v.BinaryChecksum))
+		i++
+	}
+	if v.Resettable != nil {
+		fields[i] = fmt.Sprintf("Resettable: %v", *(v.Resettable))
+		i++
+	}
+	if v.CreatedTime != nil {
+		fields[i] = fmt.Sprintf("CreatedTime: %v", *(v.CreatedTime))
+		i++
+	}
+	if v.Safe != nil {
+		fields[i] = fmt.Sprintf("Safe: %v", *(v.Safe))
 		i++
 	}
 
-	return fmt.Sprintf("DescribeWorkflowExecutionRequest{%v}", strings.Join(fields[:i], ", "))
+	return fmt.Sprintf("ResetPoint{%v}", strings.Join(fields[:i], ", "))
 }
 
-// Equals returns true if all the fields of this DescribeWorkflowExecutionRequest match the
-// provided DescribeWorkflowExecutionRequest.
+// Equals returns true if all the fields of this ResetPoint match the
+// provided ResetPoint.
 //
 // This function performs a deep comparison.
-func (v *DescribeWorkflowExecutionRequest) Equals(rhs *DescribeWorkflowExecutionRequest) bool {
-	if !_String_EqualsPtr(v.Domain, rhs.Domain) {
+func (v *ResetPoint) Equals(rhs *ResetPoint) bool {
+	if !_I64_EqualsPtr(v.EventId, rhs.EventId) {
 		return false
 	}
-	if !((v.Execution == nil && rhs.Execution == nil) || (v.Execution != nil && rhs.Execution != nil && v.Execution.Equals(rhs.Execution))) {
+	if !_String_EqualsPtr(v.BinaryChecksum, rhs.BinaryChecksum) {
+		return false
+	}
+	if !_Bool_EqualsPtr(v.Resettable, rhs.Resettable) {
+		return false
+	}
+	if !_I64_EqualsPtr(v.CreatedTime, rhs.CreatedTime) {
+		return false
+	}
+	if !_Bool_EqualsPtr(v.Safe, rhs.Safe) {
 		return false
 	}
 
 	return true
 }
 
-// GetDomain returns the value of Domain if it is set or its
+// GetEventId returns the value of EventId if it is set or its
 // zero value if it is unset.
-func (v *DescribeWorkflowExecutionRequest) GetDomain() (o string) {
-	if v.Domain != nil {
-		return *v.Domain
-	}
+func (v *ResetPoint) GetEventId() (o int64) {
+	if v.EventId != nil {
+		return *v.EventId
+	}
+
+	return
+}
+
+// GetBinaryChecksum returns the value of BinaryChecksum if it is set or its
+// zero value if it is unset.
+func (v *ResetPoint) GetBinaryChecksum() (o string) {
+	if v.BinaryChecksum != nil {
+		return *v.BinaryChecksum
+	}
+
+	return
+}
+
+// GetResettable returns the value of Resettable if it is set or its
+// zero value if it is unset.
+func (v *ResetPoint) GetResettable() (o bool) {
+	if v.Resettable != nil {
+		return *v.Resettable
+	}
+
+	return
+}
+
+// GetCreatedTime returns the value of CreatedTime if it is set or its
+// zero value if it is unset.
+func (v *ResetPoint) GetCreatedTime() (o int64) {
+	if v.CreatedTime != nil {
+		return *v.CreatedTime
+	}
+
+	return
+}
+
+// GetSafe returns the value of Safe if it is set or its
+// zero value if it is unset.
+func (v *ResetPoint) GetSafe() (o bool) {
+	if v.Safe != nil {
+		return *v.Safe
+	}
+
+	return
+}
+
+type RespondDecisionTaskCompletedResponse struct {
+	DecisionTask              *PollForDecisionTaskResponse `json:"decisionTask,omitempty"`
+	ActivityScheduledEventIds map[string]int64             `json:"activityScheduledEventIds,omitempty"`
+}
+
+type _Map_String_I64_MapItemList map[string]int64
+
+func (m _Map_String_I64_MapItemList) ForEach(f func(wire.MapItem) error) error {
+	for k, v := range m {
+		kw, err := wire.NewValueString(k), error(nil)
+		if err != nil {
+			return err
+		}
+
+		vw, err := wire.NewValueI64(v), error(nil)
+		if err != nil {
+			return err
+		}
+		err = f(wire.MapItem{Key: kw, Value: vw})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m _Map_String_I64_MapItemList) Size() int {
+	return len(m)
+}
+
+func (_Map_String_I64_MapItemList) KeyType() wire.Type {
+	return wire.TBinary
+}
+
+func (_Map_String_I64_MapItemList) ValueType() wire.Type {
+	return wire.TI64
+}
+
+func (_Map_String_I64_MapItemList) Close() {}
+
+// ToWire translates a RespondDecisionTaskCompletedResponse struct into a Thrift-level intermediate
+// representation. This intermediate representation may be serialized
+// into bytes using a ThriftRW protocol implementation.
+//
+// An error is returned if the struct or any of its fields failed to
+// validate.
+//
+//   x, err := v.ToWire()
+//   if err != nil {
+//     return err
+//   }
+//
+//   if err := binaryProtocol.Encode(x, writer); err != nil {
+//     return err
+//   }
+func (v *RespondDecisionTaskCompletedResponse) ToWire() (wire.Value, error) {
+	var (
+		fields [2]wire.Field
+		i      int = 0
+		w      wire.Value
+		err    error
+	)
+
+	if v.DecisionTask != nil {
+		w, err = v.DecisionTask.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 10, Value: w}
+		i++
+	}
+	if v.ActivityScheduledEventIds != nil {
+		w, err = wire.NewValueMap(_Map_String_I64_MapItemList(v.ActivityScheduledEventIds)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 20, Value: w}
+		i++
+	}
+
+	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
+}
+
+func _PollForDecisionTaskResponse_Read(w wire.Value) (*PollForDecisionTaskResponse, error) {
+	var v PollForDecisionTaskResponse
+	err := v.FromWire(w)
+	return &v, err
+}
+
+func _Map_String_I64_Read(m wire.MapItemList) (map[string]int64, error) {
+	if m.KeyType() != wire.TBinary {
+		return nil, nil
+	}
+
+	if m.ValueType() != wire.TI64 {
+		return nil, nil
+	}
+
+	o := make(map[string]int64, m.Size())
+	err := m.ForEach(func(x wire.MapItem) error {
+		k, err := x.Key.GetString(), error(nil)
+		if err != nil {
+			return err
+		}
+
+		v, err := x.Value.GetI64(), error(nil)
+		if err != nil {
+			return err
+		}
+
+		o[k] = v
+		return nil
+	})
+	m.Close()
+	return o, err
+}
+
+// FromWire deserializes a RespondDecisionTaskCompletedResponse struct from its Thrift-level
+// representation. The Thrift-level representation may be obtained
+// from a ThriftRW protocol implementation.
+//
+// An error is returned if we were unable to build a RespondDecisionTaskCompletedResponse struct
+// from the provided intermediate representation.
+//
+//   x, err := binaryProtocol.Decode(reader, wire.TStruct)
+//   if err != nil {
+//     return nil, err
+//   }
+//
+//   var v RespondDecisionTaskCompletedResponse
+//   if err := v.FromWire(x); err != nil {
+//     return nil, err
+//   }
+//   return &v, nil
+func (v *RespondDecisionTaskCompletedResponse) FromWire(w wire.Value) error {
+	var err error
+
+	for _, field := range w.GetStruct().Fields {
+		switch field.ID {
+		case 10:
+			if field.Value.Type() == wire.TStruct {
+				v.DecisionTask, err = _PollForDecisionTaskResponse_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
+			}
+		case 20:
+			if field.Value.Type() == wire.TMap {
+				v.ActivityScheduledEventIds, err = _Map_String_I64_Read(field.Value.GetMap())
+				if err != nil {
+					return err
+				}
+
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable string representation of a RespondDecisionTaskCompletedResponse
+// struct.
+func (v *RespondDecisionTaskCompletedResponse) String() string {
+	if v == nil {
+		return "<nil>"
+	}
+
+	var fields [2]string
+	i := 0
+	if v.DecisionTask != nil {
+		fields[i] = fmt.Sprintf("DecisionTask: %v", v.DecisionTask)
+		i++
+	}
+	if v.ActivityScheduledEventIds != nil {
+		fields[i] = fmt.Sprintf("ActivityScheduledEventIds: %v", v.ActivityScheduledEventIds)
+		i++
+	}
+
+	return fmt.Sprintf("RespondDecisionTaskCompletedResponse{%v}", strings.Join(fields[:i], ", "))
+}
+
+func _Map_String_I64_Equals(lhs, rhs map[string]int64) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+
+	for lk, lv := range lhs {
+		rv, ok := rhs[lk]
+		if !ok {
+			return false
+		}
+		if !(lv == rv) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equals returns true if all the fields of this RespondDecisionTaskCompletedResponse match the
+// provided RespondDecisionTaskCompletedResponse.
+//
+// This function performs a deep comparison.
+func (v *RespondDecisionTaskCompletedResponse) Equals(rhs *RespondDecisionTaskCompletedResponse) bool {
+	if !((v.DecisionTask == nil && rhs.DecisionTask == nil) || (v.DecisionTask != nil && rhs.DecisionTask != nil && v.DecisionTask.Equals(rhs.DecisionTask))) {
+		return false
+	}
+	if !((v.ActivityScheduledEventIds == nil && rhs.ActivityScheduledEventIds == nil) || (v.ActivityScheduledEventIds != nil && rhs.ActivityScheduledEventIds != nil && _Map_String_I64_Equals(v.ActivityScheduledEventIds, rhs.ActivityScheduledEventIds))) {
+		return false
+	}
+
+	return true
+}
+
+type _List_PollerInfo_ValueList []*PollerInfo
+
+func (v _List_PollerInfo_ValueList) ForEach(f func(wire.Value) error) error {
+	for i, x := range v {
+		if x == nil {
+			return fmt.Errorf("invalid [%v]: value is nil", i)
+		}
+		w, err := x.ToWire()
+		if err != nil {
+			return err
+		}
+		err = f(w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v _List_PollerInfo_ValueList) Size() int {
+	return len(v)
+}
+
+func (_List_PollerInfo_ValueList) ValueType() wire.Type {
+	return wire.TStruct
+}
+
+func (_List_PollerInfo_ValueList) Close() {}
+
+// ToWire translates a DescribeTaskListResponse struct into a Thrift-level intermediate
+// representation. This intermediate representation may be serialized
+// into bytes using a ThriftRW protocol implementation.
+//
+// An error is returned if the struct or any of its fields failed to
+// validate.
+//
+//   x, err := v.ToWire()
+//   if err != nil {
+//     return err
+//   }
+//
+//   if err := binaryProtocol.Encode(x, writer); err != nil {
+//     return err
+//   }
+func (v *DescribeTaskListResponse) ToWire() (wire.Value, error) {
+	var (
+		fields [1]wire.Field
+		i      int = 0
+		w      wire.Value
+		err    error
+	)
+
+	if v.Pollers != nil {
+		w, err = wire.NewValueList(_List_PollerInfo_ValueList(v.Pollers)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 10, Value: w}
+		i++
+	}
+
+	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
+}
+
+func _PollerInfo_Read(w wire.Value) (*PollerInfo, error) {
+	var v PollerInfo
+	err := v.FromWire(w)
+	return &v, err
+}
+
+func _List_PollerInfo_Read(l wire.ValueList) ([]*PollerInfo, error) {
+	if l.ValueType() != wire.TStruct {
+		return nil, nil
+	}
+
+	o := make([]*PollerInfo, 0, l.Size())
+	err := l.ForEach(func(x wire.Value) error {
+		i, err := _PollerInfo_Read(x)
+		if err != nil {
+			return err
+		}
+		o = append(o, i)
+		return nil
+	})
+	l.Close()
+	return o, err
+}
+
+// FromWire deserializes a DescribeTaskListResponse struct from its Thrift-level
+// representation. The Thrift-level representation may be obtained
+// from a ThriftRW protocol implementation.
+//
+// An error is returned if we were unable to build a DescribeTaskListResponse struct
+// from the provided intermediate representation.
+//
+//   x, err := binaryProtocol.Decode(reader, wire.TStruct)
+//   if err != nil {
+//     return nil, err
+//   }
+//
+//   var v DescribeTaskListResponse
+//   if err := v.FromWire(x); err != nil {
+//     return nil, err
+//   }
+//   return &v, nil
+func (v *DescribeTaskListResponse) FromWire(w wire.Value) error {
+	var err error
+
+	for _, field := range w.GetStruct().Fields {
+		switch field.ID {
+		case 10:
+			if field.Value.Type() == wire.TList {
+				v.Pollers, err = _List_PollerInfo_Read(field.Value.GetList())
+				if err != nil {
+					return err
+				}
+
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable string representation of a DescribeTaskListResponse
+// struct.
+func (v *DescribeTaskListResponse) String() string {
+	if v == nil {
+		return "<nil>"
+	}
+
+	var fields [1]string
+	i := 0
+	if v.Pollers != nil {
+		fields[i] = fmt.Sprintf("Pollers: %v", v.Pollers)
+		i++
+	}
+
+	return fmt.Sprintf("DescribeTaskListResponse{%v}", strings.Join(fields[:i], ", "))
+}
+
+func _List_PollerInfo_Equals(lhs, rhs []*PollerInfo) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+
+	for i, lv := range lhs {
+		rv := rhs[i]
+		if !lv.Equals(rv) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Equals returns true if all the fields of this DescribeTaskListResponse match the
+// provided DescribeTaskListResponse.
+//
+// This function performs a deep comparison.
+func (v *DescribeTaskListResponse) Equals(rhs *DescribeTaskListResponse) bool {
+	if !((v.Pollers == nil && rhs.Pollers == nil) || (v.Pollers != nil && rhs.Pollers != nil && _List_PollerInfo_Equals(v.Pollers, rhs.Pollers))) {
+		return false
+	}
+
+	return true
+}
+
+type DescribeWorkflowExecutionRequest struct {
+	Domain    *string            `json:"domain,omitempty"`
+	Execution *WorkflowExecution `json:"execution,omitempty"`
+}
+
+// ToWire translates a DescribeWorkflowExecutionRequest struct into a Thrift-level intermediate
+// representation. This intermediate representation may be serialized
+// into bytes using a ThriftRW protocol implementation.
+//
+// An error is returned if the struct or any of its fields failed to
+// validate.
+//
+//   x, err := v.ToWire()
+//   if err != nil {
+//     return err
+//   }
+//
+//   if err := binaryProtocol.Encode(x, writer); err != nil {
+//     return err
+//   }
+func (v *DescribeWorkflowExecutionRequest) ToWire() (wire.Value, error) {
+	var (
+		fields [2]wire.Field
+		i      int = 0
+		w      wire.Value
+		err    error
+	)
+
+	if v.Domain != nil {
+		w, err = wire.NewValueString(*(v.Domain)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 10, Value: w}
+		i++
+	}
+	if v.Execution != nil {
+		w, err = v.Execution.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 20, Value: w}
+		i++
+	}
+
+	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
+}
+
+// FromWire deserializes a DescribeWorkflowExecutionRequest struct from its Thrift-level
+// representation. The Thrift-level representation may be obtained
+// from a ThriftRW protocol implementation.
+//
+// An error is returned if we were unable to build a DescribeWorkflowExecutionRequest struct
+// from the provided intermediate representation.
+//
+//   x, err := binaryProtocol.Decode(reader, wire.TStruct)
+//   if err != nil {
+//     return nil, err
+//   }
+//
+//   var v DescribeWorkflowExecutionRequest
+//   if err := v.FromWire(x); err != nil {
+//     return nil, err
+//   }
+//   return &v, nil
+func (v *DescribeWorkflowExecutionRequest) FromWire(w wire.Value) error {
+	var err error
+
+	for _, field := range w.GetStruct().Fields {
+		switch field.ID {
+		case 10:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.Domain = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 20:
+			if field.Value.Type() == wire.TStruct {
+				v.Execution, err = _WorkflowExecution_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable string representation of a DescribeWorkflowExecutionRequest
+// struct.
+func (v *DescribeWorkflowExecutionRequest) String() string {
+	if v == nil {
+		return "<nil>"
+	}
+
+	var fields [2]string
+	i := 0
+	if v.Domain != nil {
+		fields[i] = fmt.Sprintf("Domain: %v", *(v.Domain))
+		i++
+	}
+	if v.Execution != nil {
+		fields[i] = fmt.Sprintf("Execution: %v", v.Execution)
+		i++
+	}
+
+	return fmt.Sprintf("DescribeWorkflowExecutionRequest{%v}", strings.Join(fields[:i], ", "))
+}
+
+// Equals returns true if all the fields of this DescribeWorkflowExecutionRequest match the
+// provided DescribeWorkflowExecutionRequest.
+//
+// This function performs a deep comparison.
+func (v *DescribeWorkflowExecutionRequest) Equals(rhs *DescribeWorkflowExecutionRequest) bool {
+	if !_String_EqualsPtr(v.Domain, rhs.Domain) {
+		return false
+	}
+	if !((v.Execution == nil && rhs.Execution == nil) || (v.Execution != nil && rhs.Execution != nil && v.Execution.Equals(rhs.Execution))) {
+		return false
+	}
+
+	return true
+}
+
+// GetDomain returns the value of Domain if it is set or its
+// zero value if it is unset.
+func (v *DescribeWorkflowExecutionRequest) GetDomain() (o string) {
+	if v.Domain != nil {
+		return *v.Domain
+	}
 
 	return
 }
@@ -7910,6 +9216,7 @@ type DescribeWorkflowExecutionResponse struct {
 	ExecutionConfiguration *WorkflowExecutionConfiguration `json:"executionConfiguration,omitempty"`
 	WorkflowExecutionInfo  *WorkflowExecutionInfo          `json:"workflowExecutionInfo,omitempty"`
 	PendingActivities      []*PendingActivityInfo          `json:"pendingActivities,omitempty"`
+	RawExecutionInfo       *string                         `json:"rawExecutionInfo,omitempty"`
 }
 
 type _List_PendingActivityInfo_ValueList []*PendingActivityInfo
@@ -7958,7 +9265,7 @@ func (_List_PendingActivityInfo_ValueList) Close() {}
 //   }
 func (v *DescribeWorkflowExecutionResponse) ToWire() (wire.Value, error) {
 	var (
-		fields [3]wire.Field
+		fields [4]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -7988,6 +9295,14 @@ func (v *DescribeWorkflowExecutionResponse) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 30, Value: w}
 		i++
 	}
+	if v.RawExecutionInfo != nil {
+		w, err = wire.NewValueString(*(v.RawExecutionInfo)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 40, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -8073,6 +9388,16 @@ func (v *DescribeWorkflowExecutionResponse) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 40:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.RawExecutionInfo = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -8087,7 +9412,7 @@ func (v *DescribeWorkflowExecutionResponse) String() string {
 		return "<nil>"
 	}
 
-	var fields [3]string
+	var fields [4]string
 	i := 0
 	if v.ExecutionConfiguration != nil {
 		fields[i] = fmt.Sprintf("ExecutionConfiguration: %v", v.ExecutionConfiguration)
@@ -8101,6 +9426,10 @@ func (v *DescribeWorkflowExecutionResponse) String() string {
 		fields[i] = fmt.Sprintf("PendingActivities: %v", v.PendingActivities)
 		i++
 	}
+	if v.RawExecutionInfo != nil {
+		fields[i] = fmt.Sprintf("RawExecutionInfo: %v", *(v.RawExecutionInfo))
+		i++
+	}
 
 	return fmt.Sprintf("DescribeWorkflowExecutionResponse{%v}", strings.Join(fields[:i], ", "))
 }
@@ -8134,10 +9463,23 @@ func (v *DescribeWorkflowExecutionResponse) Equals(rhs *DescribeWorkflowExecutio
 	if !((v.PendingActivities == nil && rhs.PendingActivities == nil) || (v.PendingActivities != nil && rhs.PendingActivities != nil && _List_PendingActivityInfo_Equals(v.PendingActivities, rhs.PendingActivities))) {
 		return false
 	}
+	if !_String_EqualsPtr(v.RawExecutionInfo, rhs.RawExecutionInfo) {
+		return false
+	}
 
 	return true
 }
 
+// GetRawExecutionInfo returns the value of RawExecutionInfo if it is set or its
+// zero value if it is unset.
+func (v *DescribeWorkflowExecutionResponse) GetRawExecutionInfo() (o string) {
+	if v.RawExecutionInfo != nil {
+		return *v.RawExecutionInfo
+	}
+
+	return
+}
+
 type DomainAlreadyExistsError struct {
 	Message string `json:"message,required"`
 }
@@ -13667,6 +15009,8 @@ type PendingActivityInfo struct {
 	State                  *PendingActivityState `json:"state,omitempty"`
 	HeartbeatDetails       []byte                `json:"heartbeatDetails,omitempty"`
 	LastHeartbeatTimestamp *int64                `json:"lastHeartbeatTimestamp,omitempty"`
+	StartedWorkerIdentity  *string               `json:"startedWorkerIdentity,omitempty"`
+	AcceptedCancel         *bool                 `json:"acceptedCancel,omitempty"`
 }
 
 // ToWire translates a PendingActivityInfo struct into a Thrift-level intermediate
@@ -13686,7 +15030,7 @@ type PendingActivityInfo struct {
 //   }
 func (v *PendingActivityInfo) ToWire() (wire.Value, error) {
 	var (
-		fields [5]wire.Field
+		fields [7]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -13732,6 +15076,22 @@ func (v *PendingActivityInfo) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 50, Value: w}
 		i++
 	}
+	if v.StartedWorkerIdentity != nil {
+		w, err = wire.NewValueString(*(v.StartedWorkerIdentity)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 60, Value: w}
+		i++
+	}
+	if v.AcceptedCancel != nil {
+		w, err = wire.NewValueBool(*(v.AcceptedCancel)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 70, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -13809,6 +15169,26 @@ func (v *PendingActivityInfo) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 60:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.StartedWorkerIdentity = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 70:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.AcceptedCancel = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -13823,7 +15203,7 @@ func (v *PendingActivityInfo) String() string {
 		return "<nil>"
 	}
 
-	var fields [5]string
+	var fields [7]string
 	i := 0
 	if v.ActivityID != nil {
 		fields[i] = fmt.Sprintf("ActivityID: %v", *(v.ActivityID))
@@ -13845,6 +15225,14 @@ func (v *PendingActivityInfo) String() string {
 		fields[i] = fmt.Sprintf("LastHeartbeatTimestamp: %v", *(v.LastHeartbeatTimestamp))
 		i++
 	}
+	if v.StartedWorkerIdentity != nil {
+		fields[i] = fmt.Sprintf("StartedWorkerIdentity: %v", *(v.StartedWorkerIdentity))
+		i++
+	}
+	if v.AcceptedCancel != nil {
+		fields[i] = fmt.Sprintf("AcceptedCancel: %v", *(v.AcceptedCancel))
+		i++
+	}
 
 	return fmt.Sprintf("PendingActivityInfo{%v}", strings.Join(fields[:i], ", "))
 }
@@ -13879,6 +15267,12 @@ func (v *PendingActivityInfo) Equals(rhs *PendingActivityInfo) bool {
 	if !_I64_EqualsPtr(v.LastHeartbeatTimestamp, rhs.LastHeartbeatTimestamp) {
 		return false
 	}
+	if !_String_EqualsPtr(v.StartedWorkerIdentity, rhs.StartedWorkerIdentity) {
+		return false
+	}
+	if !_Bool_EqualsPtr(v.AcceptedCancel, rhs.AcceptedCancel) {
+		return false
+	}
 
 	return true
 }
@@ -13913,6 +15307,26 @@ func (v *PendingActivityInfo) GetLastHeartbeatTimestamp() (o int64) {
 	return
 }
 
+// GetStartedWorkerIdentity returns the value of StartedWorkerIdentity if it is set or its
+// zero value if it is unset.
+func (v *PendingActivityInfo) GetStartedWorkerIdentity() (o string) {
+	if v.StartedWorkerIdentity != nil {
+		return *v.StartedWorkerIdentity
+	}
+
+	return
+}
+
+// GetAcceptedCancel returns the value of AcceptedCancel if it is set or its
+// zero value if it is unset.
+func (v *PendingActivityInfo) GetAcceptedCancel() (o bool) {
+	if v.AcceptedCancel != nil {
+		return *v.AcceptedCancel
+	}
+
+	return
+}
+
 type PendingActivityState int32
 
 const (
@@ -15871,12 +17285,13 @@ func (v *QueryWorkflowResponse) Equals(rhs *QueryWorkflowResponse) bool {
 }
 
 type RecordActivityTaskHeartbeatByIDRequest struct {
-	Domain     *string `json:"domain,omitempty"`
-	WorkflowID *string `json:"workflowID,omitempty"`
-	RunID      *string `json:"runID,omitempty"`
-	ActivityID *string `json:"activityID,omitempty"`
-	Details    []byte  `json:"details,omitempty"`
-	Identity   *string `json:"identity,omitempty"`
+	Domain     *string             `json:"domain,omitempty"`
+	WorkflowID *string             `json:"workflowID,omitempty"`
+	RunID      *string             `json:"runID,omitempty"`
+	ActivityID *string             `json:"activityID,omitempty"`
+	Details    []byte              `json:"details,omitempty"`
+	Identity   *string             `json:"identity,omitempty"`
+	Checkpoint *ActivityCheckpoint `json:"checkpoint,omitempty"`
 }
 
 // ToWire translates a RecordActivityTaskHeartbeatByIDRequest struct into a Thrift-level intermediate
@@ -15896,7 +17311,7 @@ type RecordActivityTaskHeartbeatByIDRequest struct {
 //   }
 func (v *RecordActivityTaskHeartbeatByIDRequest) ToWire() (wire.Value, error) {
 	var (
-		fields [6]wire.Field
+		fields [7]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -15950,6 +17365,14 @@ func (v *RecordActivityTaskHeartbeatByIDRequest) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 60, Value: w}
 		i++
 	}
+	if v.Checkpoint != nil {
+		w, err = v.Checkpoint.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 70, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -16033,6 +17456,14 @@ func (v *RecordActivityTaskHeartbeatByIDRequest) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 70:
+			if field.Value.Type() == wire.TStruct {
+				v.Checkpoint, err = _ActivityCheckpoint_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -16047,7 +17478,7 @@ func (v *RecordActivityTaskHeartbeatByIDRequest) String() string {
 		return "<nil>"
 	}
 
-	var fields [6]string
+	var fields [7]string
 	i := 0
 	if v.Domain != nil {
 		fields[i] = fmt.Sprintf("Domain: %v", *(v.Domain))
@@ -16073,6 +17504,10 @@ func (v *RecordActivityTaskHeartbeatByIDRequest) String() string {
 		fields[i] = fmt.Sprintf("Identity: %v", *(v.Identity))
 		i++
 	}
+	if v.Checkpoint != nil {
+		fields[i] = fmt.Sprintf("Checkpoint: %v", v.Checkpoint)
+		i++
+	}
 
 	return fmt.Sprintf("RecordActivityTaskHeartbeatByIDRequest{%v}", strings.Join(fields[:i], ", "))
 }
@@ -16100,6 +17535,9 @@ func (v *RecordActivityTaskHeartbeatByIDRequest) Equals(rhs *RecordActivityTaskH
 	if !_String_EqualsPtr(v.Identity, rhs.Identity) {
 		return false
 	}
+	if !((v.Checkpoint == nil && rhs.Checkpoint == nil) || (v.Checkpoint != nil && rhs.Checkpoint != nil && v.Checkpoint.Equals(rhs.Checkpoint))) {
+		return false
+	}
 
 	return true
 }
@@ -16155,9 +17593,11 @@ func (v *RecordActivityTaskHeartbeatByIDRequest) GetIdentity() (o string) {
 }
 
 type RecordActivityTaskHeartbeatRequest struct {
-	TaskToken []byte  `json:"taskToken,omitempty"`
-	Details   []byte  `json:"details,omitempty"`
-	Identity  *string `json:"identity,omitempty"`
+	TaskToken    []byte              `json:"taskToken,omitempty"`
+	Details      []byte              `json:"details,omitempty"`
+	Identity     *string             `json:"identity,omitempty"`
+	AcceptCancel *bool               `json:"acceptCancel,omitempty"`
+	Checkpoint   *ActivityCheckpoint `json:"checkpoint,omitempty"`
 }
 
 // ToWire translates a RecordActivityTaskHeartbeatRequest struct into a Thrift-level intermediate
@@ -16177,7 +17617,7 @@ type RecordActivityTaskHeartbeatRequest struct {
 //   }
 func (v *RecordActivityTaskHeartbeatRequest) ToWire() (wire.Value, error) {
 	var (
-		fields [3]wire.Field
+		fields [5]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -16207,10 +17647,32 @@ func (v *RecordActivityTaskHeartbeatRequest) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 30, Value: w}
 		i++
 	}
+	if v.AcceptCancel != nil {
+		w, err = wire.NewValueBool(*(v.AcceptCancel)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 40, Value: w}
+		i++
+	}
+	if v.Checkpoint != nil {
+		w, err = v.Checkpoint.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 50, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
 
+func _ActivityCheckpoint_Read(w wire.Value) (*ActivityCheckpoint, error) {
+	var v ActivityCheckpoint
+	err := v.FromWire(w)
+	return &v, err
+}
+
 // FromWire deserializes a RecordActivityTaskHeartbeatRequest struct from its Thrift-level
 // representation. The Thrift-level representation may be obtained
 // from a ThriftRW protocol implementation.
@@ -16258,6 +17720,24 @@ func (v *RecordActivityTaskHeartbeatRequest) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 40:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.AcceptCancel = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 50:
+			if field.Value.Type() == wire.TStruct {
+				v.Checkpoint, err = _ActivityCheckpoint_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -16272,7 +17752,7 @@ func (v *RecordActivityTaskHeartbeatRequest) String() string {
 		return "<nil>"
 	}
 
-	var fields [3]string
+	var fields [5]string
 	i := 0
 	if v.TaskToken != nil {
 		fields[i] = fmt.Sprintf("TaskToken: %v", v.TaskToken)
@@ -16286,6 +17766,14 @@ func (v *RecordActivityTaskHeartbeatRequest) String() string {
 		fields[i] = fmt.Sprintf("Identity: %v", *(v.Identity))
 		i++
 	}
+	if v.AcceptCancel != nil {
+		fields[i] = fmt.Sprintf("AcceptCancel: %v", *(v.AcceptCancel))
+		i++
+	}
+	if v.Checkpoint != nil {
+		fields[i] = fmt.Sprintf("Checkpoint: %v", v.Checkpoint)
+		i++
+	}
 
 	return fmt.Sprintf("RecordActivityTaskHeartbeatRequest{%v}", strings.Join(fields[:i], ", "))
 }
@@ -16304,6 +17792,12 @@ func (v *RecordActivityTaskHeartbeatRequest) Equals(rhs *RecordActivityTaskHeart
 	if !_String_EqualsPtr(v.Identity, rhs.Identity) {
 		return false
 	}
+	if !_Bool_EqualsPtr(v.AcceptCancel, rhs.AcceptCancel) {
+		return false
+	}
+	if !((v.Checkpoint == nil && rhs.Checkpoint == nil) || (v.Checkpoint != nil && rhs.Checkpoint != nil && v.Checkpoint.Equals(rhs.Checkpoint))) {
+		return false
+	}
 
 	return true
 }
@@ -16318,8 +17812,19 @@ func (v *RecordActivityTaskHeartbeatRequest) GetIdentity() (o string) {
 	return
 }
 
+// GetAcceptCancel returns the value of AcceptCancel if it is set or its
+// zero value if it is unset.
+func (v *RecordActivityTaskHeartbeatRequest) GetAcceptCancel() (o bool) {
+	if v.AcceptCancel != nil {
+		return *v.AcceptCancel
+	}
+
+	return
+}
+
 type RecordActivityTaskHeartbeatResponse struct {
-	CancelRequested *bool `json:"cancelRequested,omitempty"`
+	CancelRequested *bool               `json:"cancelRequested,omitempty"`
+	LastCheckpoint  *ActivityCheckpoint `json:"lastCheckpoint,omitempty"`
 }
 
 // ToWire translates a RecordActivityTaskHeartbeatResponse struct into a Thrift-level intermediate
@@ -16339,7 +17844,7 @@ type RecordActivityTaskHeartbeatResponse struct {
 //   }
 func (v *RecordActivityTaskHeartbeatResponse) ToWire() (wire.Value, error) {
 	var (
-		fields [1]wire.Field
+		fields [2]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -16353,6 +17858,14 @@ func (v *RecordActivityTaskHeartbeatResponse) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 10, Value: w}
 		i++
 	}
+	if v.LastCheckpoint != nil {
+		w, err = v.LastCheckpoint.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 20, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -16388,6 +17901,14 @@ func (v *RecordActivityTaskHeartbeatResponse) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 20:
+			if field.Value.Type() == wire.TStruct {
+				v.LastCheckpoint, err = _ActivityCheckpoint_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -16402,12 +17923,16 @@ func (v *RecordActivityTaskHeartbeatResponse) String() string {
 		return "<nil>"
 	}
 
-	var fields [1]string
+	var fields [2]string
 	i := 0
 	if v.CancelRequested != nil {
 		fields[i] = fmt.Sprintf("CancelRequested: %v", *(v.CancelRequested))
 		i++
 	}
+	if v.LastCheckpoint != nil {
+		fields[i] = fmt.Sprintf("LastCheckpoint: %v", v.LastCheckpoint)
+		i++
+	}
 
 	return fmt.Sprintf("RecordActivityTaskHeartbeatResponse{%v}", strings.Join(fields[:i], ", "))
 }
@@ -16420,6 +17945,9 @@ func (v *RecordActivityTaskHeartbeatResponse) Equals(rhs *RecordActivityTaskHear
 	if !_Bool_EqualsPtr(v.CancelRequested, rhs.CancelRequested) {
 		return false
 	}
+	if !((v.LastCheckpoint == nil && rhs.LastCheckpoint == nil) || (v.LastCheckpoint != nil && rhs.LastCheckpoint != nil && v.LastCheckpoint.Equals(rhs.LastCheckpoint))) {
+		return false
+	}
 
 	return true
 }
@@ -17975,6 +19503,8 @@ type RequestCancelWorkflowExecutionRequest struct {
 	WorkflowExecution *WorkflowExecution `json:"workflowExecution,omitempty"`
 	Identity          *string            `json:"identity,omitempty"`
 	RequestId         *string            `json:"requestId,omitempty"`
+	ExpectedRunId     *string            `json:"expectedRunId,omitempty"`
+	Idempotent        *bool              `json:"idempotent,omitempty"`
 }
 
 // ToWire translates a RequestCancelWorkflowExecutionRequest struct into a Thrift-level intermediate
@@ -17994,7 +19524,7 @@ type RequestCancelWorkflowExecutionRequest struct {
 //   }
 func (v *RequestCancelWorkflowExecutionRequest) ToWire() (wire.Value, error) {
 	var (
-		fields [4]wire.Field
+		fields [6]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -18032,6 +19562,22 @@ func (v *RequestCancelWorkflowExecutionRequest) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 40, Value: w}
 		i++
 	}
+	if v.ExpectedRunId != nil {
+		w, err = wire.NewValueString(*(v.ExpectedRunId)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 50, Value: w}
+		i++
+	}
+	if v.Idempotent != nil {
+		w, err = wire.NewValueBool(*(v.Idempotent)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 60, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -18095,6 +19641,26 @@ func (v *RequestCancelWorkflowExecutionRequest) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 50:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.ExpectedRunId = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 60:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.Idempotent = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -18109,7 +19675,7 @@ func (v *RequestCancelWorkflowExecutionRequest) String() string {
 		return "<nil>"
 	}
 
-	var fields [4]string
+	var fields [6]string
 	i := 0
 	if v.Domain != nil {
 		fields[i] = fmt.Sprintf("Domain: %v", *(v.Domain))
@@ -18127,6 +19693,14 @@ func (v *RequestCancelWorkflowExecutionRequest) String() string {
 		fields[i] = fmt.Sprintf("RequestId: %v", *(v.RequestId))
 		i++
 	}
+	if v.ExpectedRunId != nil {
+		fields[i] = fmt.Sprintf("ExpectedRunId: %v", *(v.ExpectedRunId))
+		i++
+	}
+	if v.Idempotent != nil {
+		fields[i] = fmt.Sprintf("Idempotent: %v", *(v.Idempotent))
+		i++
+	}
 
 	return fmt.Sprintf("RequestCancelWorkflowExecutionRequest{%v}", strings.Join(fields[:i], ", "))
 }
@@ -18148,6 +19722,12 @@ func (v *RequestCancelWorkflowExecutionRequest) Equals(rhs *RequestCancelWorkflo
 	if !_String_EqualsPtr(v.RequestId, rhs.RequestId) {
 		return false
 	}
+	if !_String_EqualsPtr(v.ExpectedRunId, rhs.ExpectedRunId) {
+		return false
+	}
+	if !_Bool_EqualsPtr(v.Idempotent, rhs.Idempotent) {
+		return false
+	}
 
 	return true
 }
@@ -18182,6 +19762,26 @@ func (v *RequestCancelWorkflowExecutionRequest) GetRequestId() (o string) {
 	return
 }
 
+// GetExpectedRunId returns the value of ExpectedRunId if it is set or its
+// zero value if it is unset.
+func (v *RequestCancelWorkflowExecutionRequest) GetExpectedRunId() (o string) {
+	if v.ExpectedRunId != nil {
+		return *v.ExpectedRunId
+	}
+
+	return
+}
+
+// GetIdempotent returns the value of Idempotent if it is set or its
+// zero value if it is unset.
+func (v *RequestCancelWorkflowExecutionRequest) GetIdempotent() (o bool) {
+	if v.Idempotent != nil {
+		return *v.Idempotent
+	}
+
+	return
+}
+
 type RespondActivityTaskCanceledByIDRequest struct {
 	Domain     *string `json:"domain,omitempty"`
 	WorkflowID *string `json:"workflowID,omitempty"`
@@ -18637,6 +20237,7 @@ type RespondActivityTaskCompletedByIDRequest struct {
 	ActivityID *string `json:"activityID,omitempty"`
 	Result     []byte  `json:"result,omitempty"`
 	Identity   *string `json:"identity,omitempty"`
+	RequestId  *string `json:"requestId,omitempty"`
 }
 
 // ToWire translates a RespondActivityTaskCompletedByIDRequest struct into a Thrift-level intermediate
@@ -18656,7 +20257,7 @@ type RespondActivityTaskCompletedByIDRequest struct {
 //   }
 func (v *RespondActivityTaskCompletedByIDRequest) ToWire() (wire.Value, error) {
 	var (
-		fields [6]wire.Field
+		fields [7]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -18710,6 +20311,14 @@ func (v *RespondActivityTaskCompletedByIDRequest) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 60, Value: w}
 		i++
 	}
+	if v.RequestId != nil {
+		w, err = wire.NewValueString(*(v.RequestId)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 70, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -18793,6 +20402,16 @@ func (v *RespondActivityTaskCompletedByIDRequest) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 70:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.RequestId = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -18807,7 +20426,7 @@ func (v *RespondActivityTaskCompletedByIDRequest) String() string {
 		return "<nil>"
 	}
 
-	var fields [6]string
+	var fields [7]string
 	i := 0
 	if v.Domain != nil {
 		fields[i] = fmt.Sprintf("Domain: %v", *(v.Domain))
@@ -18833,6 +20452,10 @@ func (v *RespondActivityTaskCompletedByIDRequest) String() string {
 		fields[i] = fmt.Sprintf("Identity: %v", *(v.Identity))
 		i++
 	}
+	if v.RequestId != nil {
+		fields[i] = fmt.Sprintf("RequestId: %v", *(v.RequestId))
+		i++
+	}
 
 	return fmt.Sprintf("RespondActivityTaskCompletedByIDRequest{%v}", strings.Join(fields[:i], ", "))
 }
@@ -18860,6 +20483,9 @@ func (v *RespondActivityTaskCompletedByIDRequest) Equals(rhs *RespondActivityTas
 	if !_String_EqualsPtr(v.Identity, rhs.Identity) {
 		return false
 	}
+	if !_String_EqualsPtr(v.RequestId, rhs.RequestId) {
+		return false
+	}
 
 	return true
 }
@@ -18914,10 +20540,21 @@ func (v *RespondActivityTaskCompletedByIDRequest) GetIdentity() (o string) {
 	return
 }
 
+// GetRequestId returns the value of RequestId if it is set or its
+// zero value if it is unset.
+func (v *RespondActivityTaskCompletedByIDRequest) GetRequestId() (o string) {
+	if v.RequestId != nil {
+		return *v.RequestId
+	}
+
+	return
+}
+
 type RespondActivityTaskCompletedRequest struct {
 	TaskToken []byte  `json:"taskToken,omitempty"`
 	Result    []byte  `json:"result,omitempty"`
 	Identity  *string `json:"identity,omitempty"`
+	RequestId *string `json:"requestId,omitempty"`
 }
 
 // ToWire translates a RespondActivityTaskCompletedRequest struct into a Thrift-level intermediate
@@ -18937,7 +20574,7 @@ type RespondActivityTaskCompletedRequest struct {
 //   }
 func (v *RespondActivityTaskCompletedRequest) ToWire() (wire.Value, error) {
 	var (
-		fields [3]wire.Field
+		fields [4]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -18967,6 +20604,14 @@ func (v *RespondActivityTaskCompletedRequest) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 30, Value: w}
 		i++
 	}
+	if v.RequestId != nil {
+		w, err = wire.NewValueString(*(v.RequestId)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 40, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -19018,6 +20663,16 @@ func (v *RespondActivityTaskCompletedRequest) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 40:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.RequestId = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -19032,7 +20687,7 @@ func (v *RespondActivityTaskCompletedRequest) String() string {
 		return "<nil>"
 	}
 
-	var fields [3]string
+	var fields [4]string
 	i := 0
 	if v.TaskToken != nil {
 		fields[i] = fmt.Sprintf("TaskToken: %v", v.TaskToken)
@@ -19046,6 +20701,10 @@ func (v *RespondActivityTaskCompletedRequest) String() string {
 		fields[i] = fmt.Sprintf("Identity: %v", *(v.Identity))
 		i++
 	}
+	if v.RequestId != nil {
+		fields[i] = fmt.Sprintf("RequestId: %v", *(v.RequestId))
+		i++
+	}
 
 	return fmt.Sprintf("RespondActivityTaskCompletedRequest{%v}", strings.Join(fields[:i], ", "))
 }
@@ -19064,6 +20723,9 @@ func (v *RespondActivityTaskCompletedRequest) Equals(rhs *RespondActivityTaskCom
 	if !_String_EqualsPtr(v.Identity, rhs.Identity) {
 		return false
 	}
+	if !_String_EqualsPtr(v.RequestId, rhs.RequestId) {
+		return false
+	}
 
 	return true
 }
@@ -19078,14 +20740,25 @@ func (v *RespondActivityTaskCompletedRequest) GetIdentity() (o string) {
 	return
 }
 
+// GetRequestId returns the value of RequestId if it is set or its
+// zero value if it is unset.
+func (v *RespondActivityTaskCompletedRequest) GetRequestId() (o string) {
+	if v.RequestId != nil {
+		return *v.RequestId
+	}
+
+	return
+}
+
 type RespondActivityTaskFailedByIDRequest struct {
-	Domain     *string `json:"domain,omitempty"`
-	WorkflowID *string `json:"workflowID,omitempty"`
-	RunID      *string `json:"runID,omitempty"`
-	ActivityID *string `json:"activityID,omitempty"`
-	Reason     *string `json:"reason,omitempty"`
-	Details    []byte  `json:"details,omitempty"`
-	Identity   *string `json:"identity,omitempty"`
+	Domain      *string              `json:"domain,omitempty"`
+	WorkflowID  *string              `json:"workflowID,omitempty"`
+	RunID       *string              `json:"runID,omitempty"`
+	ActivityID  *string              `json:"activityID,omitempty"`
+	Reason      *string              `json:"reason,omitempty"`
+	Details     []byte               `json:"details,omitempty"`
+	Identity    *string              `json:"identity,omitempty"`
+	FailureType *ActivityFailureType `json:"failureType,omitempty"`
 }
 
 // ToWire translates a RespondActivityTaskFailedByIDRequest struct into a Thrift-level intermediate
@@ -19105,7 +20778,7 @@ type RespondActivityTaskFailedByIDRequest struct {
 //   }
 func (v *RespondActivityTaskFailedByIDRequest) ToWire() (wire.Value, error) {
 	var (
-		fields [7]wire.Field
+		fields [8]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -19167,6 +20840,14 @@ func (v *RespondActivityTaskFailedByIDRequest) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 70, Value: w}
 		i++
 	}
+	if v.FailureType != nil {
+		w, err = v.FailureType.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 80, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -19260,6 +20941,16 @@ func (v *RespondActivityTaskFailedByIDRequest) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 80:
+			if field.Value.Type() == wire.TI32 {
+				var x ActivityFailureType
+				x, err = _ActivityFailureType_Read(field.Value)
+				v.FailureType = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -19274,7 +20965,7 @@ func (v *RespondActivityTaskFailedByIDRequest) String() string {
 		return "<nil>"
 	}
 
-	var fields [7]string
+	var fields [8]string
 	i := 0
 	if v.Domain != nil {
 		fields[i] = fmt.Sprintf("Domain: %v", *(v.Domain))
@@ -19304,6 +20995,10 @@ func (v *RespondActivityTaskFailedByIDRequest) String() string {
 		fields[i] = fmt.Sprintf("Identity: %v", *(v.Identity))
 		i++
 	}
+	if v.FailureType != nil {
+		fields[i] = fmt.Sprintf("FailureType: %v", *(v.FailureType))
+		i++
+	}
 
 	return fmt.Sprintf("RespondActivityTaskFailedByIDRequest{%v}", strings.Join(fields[:i], ", "))
 }
@@ -19334,6 +21029,9 @@ func (v *RespondActivityTaskFailedByIDRequest) Equals(rhs *RespondActivityTaskFa
 	if !_String_EqualsPtr(v.Identity, rhs.Identity) {
 		return false
 	}
+	if !_ActivityFailureType_EqualsPtr(v.FailureType, rhs.FailureType) {
+		return false
+	}
 
 	return true
 }
@@ -19398,11 +21096,22 @@ func (v *RespondActivityTaskFailedByIDRequest) GetIdentity() (o string) {
 	return
 }
 
+// GetFailureType returns the value of FailureType if it is set or its
+// zero value if it is unset.
+func (v *RespondActivityTaskFailedByIDRequest) GetFailureType() (o ActivityFailureType) {
+	if v.FailureType != nil {
+		return *v.FailureType
+	}
+
+	return
+}
+
 type RespondActivityTaskFailedRequest struct {
-	TaskToken []byte  `json:"taskToken,omitempty"`
-	Reason    *string `json:"reason,omitempty"`
-	Details   []byte  `json:"details,omitempty"`
-	Identity  *string `json:"identity,omitempty"`
+	TaskToken   []byte               `json:"taskToken,omitempty"`
+	Reason      *string              `json:"reason,omitempty"`
+	Details     []byte               `json:"details,omitempty"`
+	Identity    *string              `json:"identity,omitempty"`
+	FailureType *ActivityFailureType `json:"failureType,omitempty"`
 }
 
 // ToWire translates a RespondActivityTaskFailedRequest struct into a Thrift-level intermediate
@@ -19422,7 +21131,7 @@ type RespondActivityTaskFailedRequest struct {
 //   }
 func (v *RespondActivityTaskFailedRequest) ToWire() (wire.Value, error) {
 	var (
-		fields [4]wire.Field
+		fields [5]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -19460,6 +21169,14 @@ func (v *RespondActivityTaskFailedRequest) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 40, Value: w}
 		i++
 	}
+	if v.FailureType != nil {
+		w, err = v.FailureType.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 50, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -19521,6 +21238,16 @@ func (v *RespondActivityTaskFailedRequest) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 50:
+			if field.Value.Type() == wire.TI32 {
+				var x ActivityFailureType
+				x, err = _ActivityFailureType_Read(field.Value)
+				v.FailureType = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -19535,7 +21262,7 @@ func (v *RespondActivityTaskFailedRequest) String() string {
 		return "<nil>"
 	}
 
-	var fields [4]string
+	var fields [5]string
 	i := 0
 	if v.TaskToken != nil {
 		fields[i] = fmt.Sprintf("TaskToken: %v", v.TaskToken)
@@ -19553,6 +21280,10 @@ func (v *RespondActivityTaskFailedRequest) String() string {
 		fields[i] = fmt.Sprintf("Identity: %v", *(v.Identity))
 		i++
 	}
+	if v.FailureType != nil {
+		fields[i] = fmt.Sprintf("FailureType: %v", *(v.FailureType))
+		i++
+	}
 
 	return fmt.Sprintf("RespondActivityTaskFailedRequest{%v}", strings.Join(fields[:i], ", "))
 }
@@ -19574,6 +21305,9 @@ func (v *RespondActivityTaskFailedRequest) Equals(rhs *RespondActivityTaskFailed
 	if !_String_EqualsPtr(v.Identity, rhs.Identity) {
 		return false
 	}
+	if !_ActivityFailureType_EqualsPtr(v.FailureType, rhs.FailureType) {
+		return false
+	}
 
 	return true
 }
@@ -19598,12 +21332,23 @@ func (v *RespondActivityTaskFailedRequest) GetIdentity() (o string) {
 	return
 }
 
+// GetFailureType returns the value of FailureType if it is set or its
+// zero value if it is unset.
+func (v *RespondActivityTaskFailedRequest) GetFailureType() (o ActivityFailureType) {
+	if v.FailureType != nil {
+		return *v.FailureType
+	}
+
+	return
+}
+
 type RespondDecisionTaskCompletedRequest struct {
-	TaskToken        []byte                     `json:"taskToken,omitempty"`
-	Decisions        []*Decision                `json:"decisions,omitempty"`
-	ExecutionContext []byte                     `json:"executionContext,omitempty"`
-	Identity         *string                    `json:"identity,omitempty"`
-	StickyAttributes *StickyExecutionAttributes `json:"stickyAttributes,omitempty"`
+	TaskToken             []byte                     `json:"taskToken,omitempty"`
+	Decisions             []*Decision                `json:"decisions,omitempty"`
+	ExecutionContext      []byte                     `json:"executionContext,omitempty"`
+	Identity              *string                    `json:"identity,omitempty"`
+	StickyAttributes      *StickyExecutionAttributes `json:"stickyAttributes,omitempty"`
+	ReturnNewDecisionTask *bool                      `json:"returnNewDecisionTask,omitempty"`
 }
 
 type _List_Decision_ValueList []*Decision
@@ -19652,7 +21397,7 @@ func (_List_Decision_ValueList) Close() {}
 //   }
 func (v *RespondDecisionTaskCompletedRequest) ToWire() (wire.Value, error) {
 	var (
-		fields [5]wire.Field
+		fields [6]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -19698,6 +21443,14 @@ func (v *RespondDecisionTaskCompletedRequest) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 50, Value: w}
 		i++
 	}
+	if v.ReturnNewDecisionTask != nil {
+		w, err = wire.NewValueBool(*(v.ReturnNewDecisionTask)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 60, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -19795,6 +21548,16 @@ func (v *RespondDecisionTaskCompletedRequest) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 60:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.ReturnNewDecisionTask = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -19809,7 +21572,7 @@ func (v *RespondDecisionTaskCompletedRequest) String() string {
 		return "<nil>"
 	}
 
-	var fields [5]string
+	var fields [6]string
 	i := 0
 	if v.TaskToken != nil {
 		fields[i] = fmt.Sprintf("TaskToken: %v", v.TaskToken)
@@ -19831,6 +21594,10 @@ func (v *RespondDecisionTaskCompletedRequest) String() string {
 		fields[i] = fmt.Sprintf("StickyAttributes: %v", v.StickyAttributes)
 		i++
 	}
+	if v.ReturnNewDecisionTask != nil {
+		fields[i] = fmt.Sprintf("ReturnNewDecisionTask: %v", *(v.ReturnNewDecisionTask))
+		i++
+	}
 
 	return fmt.Sprintf("RespondDecisionTaskCompletedRequest{%v}", strings.Join(fields[:i], ", "))
 }
@@ -19870,6 +21637,9 @@ func (v *RespondDecisionTaskCompletedRequest) Equals(rhs *RespondDecisionTaskCom
 	if !((v.StickyAttributes == nil && rhs.StickyAttributes == nil) || (v.StickyAttributes != nil && rhs.StickyAttributes != nil && v.StickyAttributes.Equals(rhs.StickyAttributes))) {
 		return false
 	}
+	if !_Bool_EqualsPtr(v.ReturnNewDecisionTask, rhs.ReturnNewDecisionTask) {
+		return false
+	}
 
 	return true
 }
@@ -19884,6 +21654,16 @@ func (v *RespondDecisionTaskCompletedRequest) GetIdentity() (o string) {
 	return
 }
 
+// GetReturnNewDecisionTask returns the value of ReturnNewDecisionTask if it is set or its
+// zero value if it is unset.
+func (v *RespondDecisionTaskCompletedRequest) GetReturnNewDecisionTask() (o bool) {
+	if v.ReturnNewDecisionTask != nil {
+		return *v.ReturnNewDecisionTask
+	}
+
+	return
+}
+
 type RespondDecisionTaskFailedRequest struct {
 	TaskToken []byte                   `json:"taskToken,omitempty"`
 	Cause     *DecisionTaskFailedCause `json:"cause,omitempty"`
@@ -20310,6 +22090,8 @@ type ScheduleActivityTaskDecisionAttributes struct {
 	ScheduleToStartTimeoutSeconds *int32        `json:"scheduleToStartTimeoutSeconds,omitempty"`
 	StartToCloseTimeoutSeconds    *int32        `json:"startToCloseTimeoutSeconds,omitempty"`
 	HeartbeatTimeoutSeconds       *int32        `json:"heartbeatTimeoutSeconds,omitempty"`
+	Priority                      *int32        `json:"priority,omitempty"`
+	DispatchDeadlineSeconds       *int32        `json:"dispatchDeadlineSeconds,omitempty"`
 }
 
 // ToWire translates a ScheduleActivityTaskDecisionAttributes struct into a Thrift-level intermediate
@@ -20329,7 +22111,7 @@ type ScheduleActivityTaskDecisionAttributes struct {
 //   }
 func (v *ScheduleActivityTaskDecisionAttributes) ToWire() (wire.Value, error) {
 	var (
-		fields [9]wire.Field
+		fields [11]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -20407,6 +22189,22 @@ func (v *ScheduleActivityTaskDecisionAttributes) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 60, Value: w}
 		i++
 	}
+	if v.Priority != nil {
+		w, err = wire.NewValueI32(*(v.Priority)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 70, Value: w}
+		i++
+	}
+	if v.DispatchDeadlineSeconds != nil {
+		w, err = wire.NewValueI32(*(v.DispatchDeadlineSeconds)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 80, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -20516,6 +22314,26 @@ func (v *ScheduleActivityTaskDecisionAttributes) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 70:
+			if field.Value.Type() == wire.TI32 {
+				var x int32
+				x, err = field.Value.GetI32(), error(nil)
+				v.Priority = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 80:
+			if field.Value.Type() == wire.TI32 {
+				var x int32
+				x, err = field.Value.GetI32(), error(nil)
+				v.DispatchDeadlineSeconds = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -20530,7 +22348,7 @@ func (v *ScheduleActivityTaskDecisionAttributes) String() string {
 		return "<nil>"
 	}
 
-	var fields [9]string
+	var fields [11]string
 	i := 0
 	if v.ActivityId != nil {
 		fields[i] = fmt.Sprintf("ActivityId: %v", *(v.ActivityId))
@@ -20568,6 +22386,14 @@ func (v *ScheduleActivityTaskDecisionAttributes) String() string {
 		fields[i] = fmt.Sprintf("HeartbeatTimeoutSeconds: %v", *(v.HeartbeatTimeoutSeconds))
 		i++
 	}
+	if v.Priority != nil {
+		fields[i] = fmt.Sprintf("Priority: %v", *(v.Priority))
+		i++
+	}
+	if v.DispatchDeadlineSeconds != nil {
+		fields[i] = fmt.Sprintf("DispatchDeadlineSeconds: %v", *(v.DispatchDeadlineSeconds))
+		i++
+	}
 
 	return fmt.Sprintf("ScheduleActivityTaskDecisionAttributes{%v}", strings.Join(fields[:i], ", "))
 }
@@ -20604,6 +22430,12 @@ func (v *ScheduleActivityTaskDecisionAttributes) Equals(rhs *ScheduleActivityTas
 	if !_I32_EqualsPtr(v.HeartbeatTimeoutSeconds, rhs.HeartbeatTimeoutSeconds) {
 		return false
 	}
+	if !_I32_EqualsPtr(v.Priority, rhs.Priority) {
+		return false
+	}
+	if !_I32_EqualsPtr(v.DispatchDeadlineSeconds, rhs.DispatchDeadlineSeconds) {
+		return false
+	}
 
 	return true
 }
@@ -20668,6 +22500,26 @@ func (v *ScheduleActivityTaskDecisionAttributes) GetHeartbeatTimeoutSeconds() (o
 	return
 }
 
+// GetPriority returns the value of Priority if it is set or its
+// zero value if it is unset.
+func (v *ScheduleActivityTaskDecisionAttributes) GetPriority() (o int32) {
+	if v.Priority != nil {
+		return *v.Priority
+	}
+
+	return
+}
+
+// GetDispatchDeadlineSeconds returns the value of DispatchDeadlineSeconds if it is set or its
+// zero value if it is unset.
+func (v *ScheduleActivityTaskDecisionAttributes) GetDispatchDeadlineSeconds() (o int32) {
+	if v.DispatchDeadlineSeconds != nil {
+		return *v.DispatchDeadlineSeconds
+	}
+
+	return
+}
+
 type ServiceBusyError struct {
 	Message string `json:"message,required"`
 }
@@ -21762,8 +23614,39 @@ type SignalWithStartWorkflowExecutionRequest struct {
 	SignalName                          *string                `json:"signalName,omitempty"`
 	SignalInput                         []byte                 `json:"signalInput,omitempty"`
 	Control                             []byte                 `json:"control,omitempty"`
+	Signals                             []*BatchedSignal       `json:"signals,omitempty"`
+	KeyId                               *string                `json:"keyId,omitempty"`
+}
+
+type _List_BatchedSignal_ValueList []*BatchedSignal
+
+func (v _List_BatchedSignal_ValueList) ForEach(f func(wire.Value) error) error {
+	for i, x := range v {
+		if x == nil {
+			return fmt.Errorf("invalid [%v]: value is nil", i)
+		}
+		w, err := x.ToWire()
+		if err != nil {
+			return err
+		}
+		err = f(w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v _List_BatchedSignal_ValueList) Size() int {
+	return len(v)
 }
 
+func (_List_BatchedSignal_ValueList) ValueType() wire.Type {
+	return wire.TStruct
+}
+
+func (_List_BatchedSignal_ValueList) Close() {}
+
 // ToWire translates a SignalWithStartWorkflowExecutionRequest struct into a Thrift-level intermediate
 // representation. This intermediate representation may be serialized
 // into bytes using a ThriftRW protocol implementation.
@@ -21781,7 +23664,7 @@ type SignalWithStartWorkflowExecutionRequest struct {
 //   }
 func (v *SignalWithStartWorkflowExecutionRequest) ToWire() (wire.Value, error) {
 	var (
-		fields [13]wire.Field
+		fields [15]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -21891,6 +23774,22 @@ func (v *SignalWithStartWorkflowExecutionRequest) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 130, Value: w}
 		i++
 	}
+	if v.Signals != nil {
+		w, err = wire.NewValueList(_List_BatchedSignal_ValueList(v.Signals)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 140, Value: w}
+		i++
+	}
+	if v.KeyId != nil {
+		w, err = wire.NewValueString(*(v.KeyId)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 150, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -21901,6 +23800,30 @@ func _WorkflowIdReusePolicy_Read(w wire.Value) (WorkflowIdReusePolicy, error) {
 	return v, err
 }
 
+func _BatchedSignal_Read(w wire.Value) (*BatchedSignal, error) {
+	var v BatchedSignal
+	err := v.FromWire(w)
+	return &v, err
+}
+
+func _List_BatchedSignal_Read(l wire.ValueList) ([]*BatchedSignal, error) {
+	if l.ValueType() != wire.TStruct {
+		return nil, nil
+	}
+
+	o := make([]*BatchedSignal, 0, l.Size())
+	err := l.ForEach(func(x wire.Value) error {
+		i, err := _BatchedSignal_Read(x)
+		if err != nil {
+			return err
+		}
+		o = append(o, i)
+		return nil
+	})
+	l.Close()
+	return o, err
+}
+
 // FromWire deserializes a SignalWithStartWorkflowExecutionRequest struct from its Thrift-level
 // representation. The Thrift-level representation may be obtained
 // from a ThriftRW protocol implementation.
@@ -22042,6 +23965,24 @@ func (v *SignalWithStartWorkflowExecutionRequest) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 140:
+			if field.Value.Type() == wire.TList {
+				v.Signals, err = _List_BatchedSignal_Read(field.Value.GetList())
+				if err != nil {
+					return err
+				}
+
+			}
+		case 150:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.KeyId = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -22056,7 +23997,7 @@ func (v *SignalWithStartWorkflowExecutionRequest) String() string {
 		return "<nil>"
 	}
 
-	var fields [13]string
+	var fields [15]string
 	i := 0
 	if v.Domain != nil {
 		fields[i] = fmt.Sprintf("Domain: %v", *(v.Domain))
@@ -22110,6 +24051,14 @@ func (v *SignalWithStartWorkflowExecutionRequest) String() string {
 		fields[i] = fmt.Sprintf("Control: %v", v.Control)
 		i++
 	}
+	if v.Signals != nil {
+		fields[i] = fmt.Sprintf("Signals: %v", v.Signals)
+		i++
+	}
+	if v.KeyId != nil {
+		fields[i] = fmt.Sprintf("KeyId: %v", *(v.KeyId))
+		i++
+	}
 
 	return fmt.Sprintf("SignalWithStartWorkflowExecutionRequest{%v}", strings.Join(fields[:i], ", "))
 }
@@ -22124,6 +24073,21 @@ func _WorkflowIdReusePolicy_EqualsPtr(lhs, rhs *WorkflowIdReusePolicy) bool {
 	return lhs == nil && rhs == nil
 }
 
+func _List_BatchedSignal_Equals(lhs, rhs []*BatchedSignal) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+
+	for i, lv := range lhs {
+		rv := rhs[i]
+		if !lv.Equals(rv) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Equals returns true if all the fields of this SignalWithStartWorkflowExecutionRequest match the
 // provided SignalWithStartWorkflowExecutionRequest.
 //
@@ -22168,6 +24132,12 @@ func (v *SignalWithStartWorkflowExecutionRequest) Equals(rhs *SignalWithStartWor
 	if !((v.Control == nil && rhs.Control == nil) || (v.Control != nil && rhs.Control != nil && bytes.Equal(v.Control, rhs.Control))) {
 		return false
 	}
+	if !((v.Signals == nil && rhs.Signals == nil) || (v.Signals != nil && rhs.Signals != nil && _List_BatchedSignal_Equals(v.Signals, rhs.Signals))) {
+		return false
+	}
+	if !_String_EqualsPtr(v.KeyId, rhs.KeyId) {
+		return false
+	}
 
 	return true
 }
@@ -22252,6 +24222,16 @@ func (v *SignalWithStartWorkflowExecutionRequest) GetSignalName() (o string) {
 	return
 }
 
+// GetKeyId returns the value of KeyId if it is set or its
+// zero value if it is unset.
+func (v *SignalWithStartWorkflowExecutionRequest) GetKeyId() (o string) {
+	if v.KeyId != nil {
+		return *v.KeyId
+	}
+
+	return
+}
+
 type SignalWorkflowExecutionRequest struct {
 	Domain            *string            `json:"domain,omitempty"`
 	WorkflowExecution *WorkflowExecution `json:"workflowExecution,omitempty"`
@@ -24024,6 +26004,7 @@ type StartWorkflowExecutionRequest struct {
 	RequestId                           *string                `json:"requestId,omitempty"`
 	WorkflowIdReusePolicy               *WorkflowIdReusePolicy `json:"workflowIdReusePolicy,omitempty"`
 	ChildPolicy                         *ChildPolicy           `json:"childPolicy,omitempty"`
+	KeyId                               *string                `json:"keyId,omitempty"`
 }
 
 // ToWire translates a StartWorkflowExecutionRequest struct into a Thrift-level intermediate
@@ -24043,7 +26024,7 @@ type StartWorkflowExecutionRequest struct {
 //   }
 func (v *StartWorkflowExecutionRequest) ToWire() (wire.Value, error) {
 	var (
-		fields [11]wire.Field
+		fields [12]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -24137,6 +26118,14 @@ func (v *StartWorkflowExecutionRequest) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 110, Value: w}
 		i++
 	}
+	if v.KeyId != nil {
+		w, err = wire.NewValueString(*(v.KeyId)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 120, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -24266,6 +26255,16 @@ func (v *StartWorkflowExecutionRequest) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 120:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.KeyId = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -24280,7 +26279,7 @@ func (v *StartWorkflowExecutionRequest) String() string {
 		return "<nil>"
 	}
 
-	var fields [11]string
+	var fields [12]string
 	i := 0
 	if v.Domain != nil {
 		fields[i] = fmt.Sprintf("Domain: %v", *(v.Domain))
@@ -24326,6 +26325,10 @@ func (v *StartWorkflowExecutionRequest) String() string {
 		fields[i] = fmt.Sprintf("ChildPolicy: %v", *(v.ChildPolicy))
 		i++
 	}
+	if v.KeyId != nil {
+		fields[i] = fmt.Sprintf("KeyId: %v", *(v.KeyId))
+		i++
+	}
 
 	return fmt.Sprintf("StartWorkflowExecutionRequest{%v}", strings.Join(fields[:i], ", "))
 }
@@ -24368,6 +26371,9 @@ func (v *StartWorkflowExecutionRequest) Equals(rhs *StartWorkflowExecutionReques
 	if !_ChildPolicy_EqualsPtr(v.ChildPolicy, rhs.ChildPolicy) {
 		return false
 	}
+	if !_String_EqualsPtr(v.KeyId, rhs.KeyId) {
+		return false
+	}
 
 	return true
 }
@@ -24452,6 +26458,16 @@ func (v *StartWorkflowExecutionRequest) GetChildPolicy() (o ChildPolicy) {
 	return
 }
 
+// GetKeyId returns the value of KeyId if it is set or its
+// zero value if it is unset.
+func (v *StartWorkflowExecutionRequest) GetKeyId() (o string) {
+	if v.KeyId != nil {
+		return *v.KeyId
+	}
+
+	return
+}
+
 type StartWorkflowExecutionResponse struct {
 	RunId *string `json:"runId,omitempty"`
 }
@@ -25273,11 +27289,13 @@ func (v *TaskListType) UnmarshalJSON(text []byte) error {
 }
 
 type TerminateWorkflowExecutionRequest struct {
-	Domain            *string            `json:"domain,omitempty"`
-	WorkflowExecution *WorkflowExecution `json:"workflowExecution,omitempty"`
-	Reason            *string            `json:"reason,omitempty"`
-	Details           []byte             `json:"details,omitempty"`
-	Identity          *string            `json:"identity,omitempty"`
+	Domain                 *string            `json:"domain,omitempty"`
+	WorkflowExecution      *WorkflowExecution `json:"workflowExecution,omitempty"`
+	Reason                 *string            `json:"reason,omitempty"`
+	Details                []byte             `json:"details,omitempty"`
+	Identity               *string            `json:"identity,omitempty"`
+	ExpectedRunId          *string            `json:"expectedRunId,omitempty"`
+	HonorDoNotTerminateTag *bool              `json:"honorDoNotTerminateTag,omitempty"`
 }
 
 // ToWire translates a TerminateWorkflowExecutionRequest struct into a Thrift-level intermediate
@@ -25297,7 +27315,7 @@ type TerminateWorkflowExecutionRequest struct {
 //   }
 func (v *TerminateWorkflowExecutionRequest) ToWire() (wire.Value, error) {
 	var (
-		fields [5]wire.Field
+		fields [7]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -25343,6 +27361,22 @@ func (v *TerminateWorkflowExecutionRequest) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 50, Value: w}
 		i++
 	}
+	if v.ExpectedRunId != nil {
+		w, err = wire.NewValueString(*(v.ExpectedRunId)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 60, Value: w}
+		i++
+	}
+	if v.HonorDoNotTerminateTag != nil {
+		w, err = wire.NewValueBool(*(v.HonorDoNotTerminateTag)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 70, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -25414,6 +27448,26 @@ func (v *TerminateWorkflowExecutionRequest) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 60:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.ExpectedRunId = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 70:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.HonorDoNotTerminateTag = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -25428,7 +27482,7 @@ func (v *TerminateWorkflowExecutionRequest) String() string {
 		return "<nil>"
 	}
 
-	var fields [5]string
+	var fields [7]string
 	i := 0
 	if v.Domain != nil {
 		fields[i] = fmt.Sprintf("Domain: %v", *(v.Domain))
@@ -25450,6 +27504,14 @@ func (v *TerminateWorkflowExecutionRequest) String() string {
 		fields[i] = fmt.Sprintf("Identity: %v", *(v.Identity))
 		i++
 	}
+	if v.ExpectedRunId != nil {
+		fields[i] = fmt.Sprintf("ExpectedRunId: %v", *(v.ExpectedRunId))
+		i++
+	}
+	if v.HonorDoNotTerminateTag != nil {
+		fields[i] = fmt.Sprintf("HonorDoNotTerminateTag: %v", *(v.HonorDoNotTerminateTag))
+		i++
+	}
 
 	return fmt.Sprintf("TerminateWorkflowExecutionRequest{%v}", strings.Join(fields[:i], ", "))
 }
@@ -25474,6 +27536,12 @@ func (v *TerminateWorkflowExecutionRequest) Equals(rhs *TerminateWorkflowExecuti
 	if !_String_EqualsPtr(v.Identity, rhs.Identity) {
 		return false
 	}
+	if !_String_EqualsPtr(v.ExpectedRunId, rhs.ExpectedRunId) {
+		return false
+	}
+	if !_Bool_EqualsPtr(v.HonorDoNotTerminateTag, rhs.HonorDoNotTerminateTag) {
+		return false
+	}
 
 	return true
 }
@@ -25508,6 +27576,26 @@ func (v *TerminateWorkflowExecutionRequest) GetIdentity() (o string) {
 	return
 }
 
+// GetExpectedRunId returns the value of ExpectedRunId if it is set or its
+// zero value if it is unset.
+func (v *TerminateWorkflowExecutionRequest) GetExpectedRunId() (o string) {
+	if v.ExpectedRunId != nil {
+		return *v.ExpectedRunId
+	}
+
+	return
+}
+
+// GetHonorDoNotTerminateTag returns the value of HonorDoNotTerminateTag if it is set or its
+// zero value if it is unset.
+func (v *TerminateWorkflowExecutionRequest) GetHonorDoNotTerminateTag() (o bool) {
+	if v.HonorDoNotTerminateTag != nil {
+		return *v.HonorDoNotTerminateTag
+	}
+
+	return
+}
+
 type TimeoutType int32
 
 const (
@@ -27076,9 +29164,11 @@ func (v *WorkflowExecution) GetRunId() (o string) {
 }
 
 type WorkflowExecutionAlreadyStartedError struct {
-	Message        *string `json:"message,omitempty"`
-	StartRequestId *string `json:"startRequestId,omitempty"`
-	RunId          *string `json:"runId,omitempty"`
+	Message        *string                       `json:"message,omitempty"`
+	StartRequestId *string                       `json:"startRequestId,omitempty"`
+	RunId          *string                       `json:"runId,omitempty"`
+	Running        *bool                         `json:"running,omitempty"`
+	CloseStatus    *WorkflowExecutionCloseStatus `json:"closeStatus,omitempty"`
 }
 
 // ToWire translates a WorkflowExecutionAlreadyStartedError struct into a Thrift-level intermediate
@@ -27098,7 +29188,7 @@ type WorkflowExecutionAlreadyStartedError struct {
 //   }
 func (v *WorkflowExecutionAlreadyStartedError) ToWire() (wire.Value, error) {
 	var (
-		fields [3]wire.Field
+		fields [5]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -27128,6 +29218,22 @@ func (v *WorkflowExecutionAlreadyStartedError) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 30, Value: w}
 		i++
 	}
+	if v.Running != nil {
+		w, err = wire.NewValueBool(*(v.Running)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 40, Value: w}
+		i++
+	}
+	if v.CloseStatus != nil {
+		w, err = v.CloseStatus.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 50, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -27183,6 +29289,26 @@ func (v *WorkflowExecutionAlreadyStartedError) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 40:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.Running = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 50:
+			if field.Value.Type() == wire.TI32 {
+				var x WorkflowExecutionCloseStatus
+				x, err = _WorkflowExecutionCloseStatus_Read(field.Value)
+				v.CloseStatus = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -27197,7 +29323,7 @@ func (v *WorkflowExecutionAlreadyStartedError) String() string {
 		return "<nil>"
 	}
 
-	var fields [3]string
+	var fields [5]string
 	i := 0
 	if v.Message != nil {
 		fields[i] = fmt.Sprintf("Message: %v", *(v.Message))
@@ -27211,6 +29337,14 @@ func (v *WorkflowExecutionAlreadyStartedError) String() string {
 		fields[i] = fmt.Sprintf("RunId: %v", *(v.RunId))
 		i++
 	}
+	if v.Running != nil {
+		fields[i] = fmt.Sprintf("Running: %v", *(v.Running))
+		i++
+	}
+	if v.CloseStatus != nil {
+		fields[i] = fmt.Sprintf("CloseStatus: %v", *(v.CloseStatus))
+		i++
+	}
 
 	return fmt.Sprintf("WorkflowExecutionAlreadyStartedError{%v}", strings.Join(fields[:i], ", "))
 }
@@ -27229,6 +29363,12 @@ func (v *WorkflowExecutionAlreadyStartedError) Equals(rhs *WorkflowExecutionAlre
 	if !_String_EqualsPtr(v.RunId, rhs.RunId) {
 		return false
 	}
+	if !_Bool_EqualsPtr(v.Running, rhs.Running) {
+		return false
+	}
+	if !_WorkflowExecutionCloseStatus_EqualsPtr(v.CloseStatus, rhs.CloseStatus) {
+		return false
+	}
 
 	return true
 }
@@ -27263,6 +29403,26 @@ func (v *WorkflowExecutionAlreadyStartedError) GetRunId() (o string) {
 	return
 }
 
+// GetRunning returns the value of Running if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionAlreadyStartedError) GetRunning() (o bool) {
+	if v.Running != nil {
+		return *v.Running
+	}
+
+	return
+}
+
+// GetCloseStatus returns the value of CloseStatus if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionAlreadyStartedError) GetCloseStatus() (o WorkflowExecutionCloseStatus) {
+	if v.CloseStatus != nil {
+		return *v.CloseStatus
+	}
+
+	return
+}
+
 func (v *WorkflowExecutionAlreadyStartedError) Error() string {
 	return v.String()
 }
@@ -27935,6 +30095,8 @@ type WorkflowExecutionConfiguration struct {
 	ExecutionStartToCloseTimeoutSeconds *int32       `json:"executionStartToCloseTimeoutSeconds,omitempty"`
 	TaskStartToCloseTimeoutSeconds      *int32       `json:"taskStartToCloseTimeoutSeconds,omitempty"`
 	ChildPolicy                         *ChildPolicy `json:"childPolicy,omitempty"`
+	StickyScheduleToStartTimeoutSeconds *int32       `json:"stickyScheduleToStartTimeoutSeconds,omitempty"`
+	NormalScheduleToStartTimeoutSeconds *int32       `json:"normalScheduleToStartTimeoutSeconds,omitempty"`
 }
 
 // ToWire translates a WorkflowExecutionConfiguration struct into a Thrift-level intermediate
@@ -27954,7 +30116,7 @@ type WorkflowExecutionConfiguration struct {
 //   }
 func (v *WorkflowExecutionConfiguration) ToWire() (wire.Value, error) {
 	var (
-		fields [4]wire.Field
+		fields [6]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -27976,20 +30138,36 @@ func (v *WorkflowExecutionConfiguration) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 20, Value: w}
 		i++
 	}
-	if v.TaskStartToCloseTimeoutSeconds != nil {
-		w, err = wire.NewValueI32(*(v.TaskStartToCloseTimeoutSeconds)), error(nil)
+	if v.TaskStartToCloseTimeoutSeconds != nil {
+		w, err = wire.NewValueI32(*(v.TaskStartToCloseTimeoutSeconds)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 30, Value: w}
+		i++
+	}
+	if v.ChildPolicy != nil {
+		w, err = v.ChildPolicy.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 40, Value: w}
+		i++
+	}
+	if v.StickyScheduleToStartTimeoutSeconds != nil {
+		w, err = wire.NewValueI32(*(v.StickyScheduleToStartTimeoutSeconds)), error(nil)
 		if err != nil {
 			return w, err
 		}
-		fields[i] = wire.Field{ID: 30, Value: w}
+		fields[i] = wire.Field{ID: 50, Value: w}
 		i++
 	}
-	if v.ChildPolicy != nil {
-		w, err = v.ChildPolicy.ToWire()
+	if v.NormalScheduleToStartTimeoutSeconds != nil {
+		w, err = wire.NewValueI32(*(v.NormalScheduleToStartTimeoutSeconds)), error(nil)
 		if err != nil {
 			return w, err
 		}
-		fields[i] = wire.Field{ID: 40, Value: w}
+		fields[i] = wire.Field{ID: 60, Value: w}
 		i++
 	}
 
@@ -28055,6 +30233,26 @@ func (v *WorkflowExecutionConfiguration) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 50:
+			if field.Value.Type() == wire.TI32 {
+				var x int32
+				x, err = field.Value.GetI32(), error(nil)
+				v.StickyScheduleToStartTimeoutSeconds = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 60:
+			if field.Value.Type() == wire.TI32 {
+				var x int32
+				x, err = field.Value.GetI32(), error(nil)
+				v.NormalScheduleToStartTimeoutSeconds = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -28069,7 +30267,7 @@ func (v *WorkflowExecutionConfiguration) String() string {
 		return "<nil>"
 	}
 
-	var fields [4]string
+	var fields [6]string
 	i := 0
 	if v.TaskList != nil {
 		fields[i] = fmt.Sprintf("TaskList: %v", v.TaskList)
@@ -28087,6 +30285,14 @@ func (v *WorkflowExecutionConfiguration) String() string {
 		fields[i] = fmt.Sprintf("ChildPolicy: %v", *(v.ChildPolicy))
 		i++
 	}
+	if v.StickyScheduleToStartTimeoutSeconds != nil {
+		fields[i] = fmt.Sprintf("StickyScheduleToStartTimeoutSeconds: %v", *(v.StickyScheduleToStartTimeoutSeconds))
+		i++
+	}
+	if v.NormalScheduleToStartTimeoutSeconds != nil {
+		fields[i] = fmt.Sprintf("NormalScheduleToStartTimeoutSeconds: %v", *(v.NormalScheduleToStartTimeoutSeconds))
+		i++
+	}
 
 	return fmt.Sprintf("WorkflowExecutionConfiguration{%v}", strings.Join(fields[:i], ", "))
 }
@@ -28108,6 +30314,12 @@ func (v *WorkflowExecutionConfiguration) Equals(rhs *WorkflowExecutionConfigurat
 	if !_ChildPolicy_EqualsPtr(v.ChildPolicy, rhs.ChildPolicy) {
 		return false
 	}
+	if !_I32_EqualsPtr(v.StickyScheduleToStartTimeoutSeconds, rhs.StickyScheduleToStartTimeoutSeconds) {
+		return false
+	}
+	if !_I32_EqualsPtr(v.NormalScheduleToStartTimeoutSeconds, rhs.NormalScheduleToStartTimeoutSeconds) {
+		return false
+	}
 
 	return true
 }
@@ -28142,6 +30354,26 @@ func (v *WorkflowExecutionConfiguration) GetChildPolicy() (o ChildPolicy) {
 	return
 }
 
+// GetStickyScheduleToStartTimeoutSeconds returns the value of StickyScheduleToStartTimeoutSeconds if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionConfiguration) GetStickyScheduleToStartTimeoutSeconds() (o int32) {
+	if v.StickyScheduleToStartTimeoutSeconds != nil {
+		return *v.StickyScheduleToStartTimeoutSeconds
+	}
+
+	return
+}
+
+// GetNormalScheduleToStartTimeoutSeconds returns the value of NormalScheduleToStartTimeoutSeconds if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionConfiguration) GetNormalScheduleToStartTimeoutSeconds() (o int32) {
+	if v.NormalScheduleToStartTimeoutSeconds != nil {
+		return *v.NormalScheduleToStartTimeoutSeconds
+	}
+
+	return
+}
+
 type WorkflowExecutionContinuedAsNewEventAttributes struct {
 	NewExecutionRunId                   *string       `json:"newExecutionRunId,omitempty"`
 	WorkflowType                        *WorkflowType `json:"workflowType,omitempty"`
@@ -28731,14 +30963,89 @@ func (v *WorkflowExecutionFilter) GetWorkflowId() (o string) {
 }
 
 type WorkflowExecutionInfo struct {
-	Execution     *WorkflowExecution            `json:"execution,omitempty"`
-	Type          *WorkflowType                 `json:"type,omitempty"`
-	StartTime     *int64                        `json:"startTime,omitempty"`
-	CloseTime     *int64                        `json:"closeTime,omitempty"`
-	CloseStatus   *WorkflowExecutionCloseStatus `json:"closeStatus,omitempty"`
-	HistoryLength *int64                        `json:"historyLength,omitempty"`
+	Execution           *WorkflowExecution            `json:"execution,omitempty"`
+	Type                *WorkflowType                 `json:"type,omitempty"`
+	StartTime           *int64                        `json:"startTime,omitempty"`
+	CloseTime           *int64                        `json:"closeTime,omitempty"`
+	CloseStatus         *WorkflowExecutionCloseStatus `json:"closeStatus,omitempty"`
+	HistoryLength       *int64                        `json:"historyLength,omitempty"`
+	Depth               *int32                        `json:"depth,omitempty"`
+	SignalCount         *int64                        `json:"signalCount,omitempty"`
+	LastSignalName      *string                       `json:"lastSignalName,omitempty"`
+	LastSignalTimestamp *int64                        `json:"lastSignalTimestamp,omitempty"`
+	BinaryChecksum      *string                       `json:"binaryChecksum,omitempty"`
+	Paused              *bool                         `json:"paused,omitempty"`
+	DecisionAttempt     *int64                        `json:"decisionAttempt,omitempty"`
+	MaxDecisionAttempts *int32                        `json:"maxDecisionAttempts,omitempty"`
+	ResetPoints         []*ResetPoint                 `json:"resetPoints,omitempty"`
+	OperatorTags        map[string]string             `json:"operatorTags,omitempty"`
+	Generation          *int64                        `json:"generation,omitempty"`
+}
+
+type _List_ResetPoint_ValueList []*ResetPoint
+
+func (v _List_ResetPoint_ValueList) ForEach(f func(wire.Value) error) error {
+	for i, x := range v {
+		if x == nil {
+			return fmt.Errorf("invalid [%v]: value is nil", i)
+		}
+		w, err := x.ToWire()
+		if err != nil {
+			return err
+		}
+		err = f(w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v _List_ResetPoint_ValueList) Size() int {
+	return len(v)
+}
+
+func (_List_ResetPoint_ValueList) ValueType() wire.Type {
+	return wire.TStruct
+}
+
+func (_List_ResetPoint_ValueList) Close() {}
+
+type _Map_String_String_MapItemList map[string]string
+
+func (m _Map_String_String_MapItemList) ForEach(f func(wire.MapItem) error) error {
+	for k, v := range m {
+		kw, err := wire.NewValueString(k), error(nil)
+		if err != nil {
+			return err
+		}
+
+		vw, err := wire.NewValueString(v), error(nil)
+		if err != nil {
+			return err
+		}
+		err = f(wire.MapItem{Key: kw, Value: vw})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m _Map_String_String_MapItemList) Size() int {
+	return len(m)
+}
+
+func (_Map_String_String_MapItemList) KeyType() wire.Type {
+	return wire.TBinary
+}
+
+func (_Map_String_String_MapItemList) ValueType() wire.Type {
+	return wire.TBinary
 }
 
+func (_Map_String_String_MapItemList) Close() {}
+
 // ToWire translates a WorkflowExecutionInfo struct into a Thrift-level intermediate
 // representation. This intermediate representation may be serialized
 // into bytes using a ThriftRW protocol implementation.
@@ -28756,7 +31063,7 @@ type WorkflowExecutionInfo struct {
 //   }
 func (v *WorkflowExecutionInfo) ToWire() (wire.Value, error) {
 	var (
-		fields [6]wire.Field
+		fields [17]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -28810,10 +31117,150 @@ func (v *WorkflowExecutionInfo) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 60, Value: w}
 		i++
 	}
+	if v.Depth != nil {
+		w, err = wire.NewValueI32(*(v.Depth)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 70, Value: w}
+		i++
+	}
+	if v.SignalCount != nil {
+		w, err = wire.NewValueI64(*(v.SignalCount)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 80, Value: w}
+		i++
+	}
+	if v.LastSignalName != nil {
+		w, err = wire.NewValueString(*(v.LastSignalName)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 90, Value: w}
+		i++
+	}
+	if v.LastSignalTimestamp != nil {
+		w, err = wire.NewValueI64(*(v.LastSignalTimestamp)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 100, Value: w}
+		i++
+	}
+	if v.BinaryChecksum != nil {
+		w, err = wire.NewValueString(*(v.BinaryChecksum)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 110, Value: w}
+		i++
+	}
+	if v.Paused != nil {
+		w, err = wire.NewValueBool(*(v.Paused)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 120, Value: w}
+		i++
+	}
+	if v.DecisionAttempt != nil {
+		w, err = wire.NewValueI64(*(v.DecisionAttempt)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 130, Value: w}
+		i++
+	}
+	if v.MaxDecisionAttempts != nil {
+		w, err = wire.NewValueI32(*(v.MaxDecisionAttempts)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 140, Value: w}
+		i++
+	}
+	if v.ResetPoints != nil {
+		w, err = wire.NewValueList(_List_ResetPoint_ValueList(v.ResetPoints)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 150, Value: w}
+		i++
+	}
+	if v.OperatorTags != nil {
+		w, err = wire.NewValueMap(_Map_String_String_MapItemList(v.OperatorTags)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 160, Value: w}
+		i++
+	}
+	if v.Generation != nil {
+		w, err = wire.NewValueI64(*(v.Generation)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 170, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
 
+func _ResetPoint_Read(w wire.Value) (*ResetPoint, error) {
+	var v ResetPoint
+	err := v.FromWire(w)
+	return &v, err
+}
+
+func _List_ResetPoint_Read(l wire.ValueList) ([]*ResetPoint, error) {
+	if l.ValueType() != wire.TStruct {
+		return nil, nil
+	}
+
+	o := make([]*ResetPoint, 0, l.Size())
+	err := l.ForEach(func(x wire.Value) error {
+		i, err := _ResetPoint_Read(x)
+		if err != nil {
+			return err
+		}
+		o = append(o, i)
+		return nil
+	})
+	l.Close()
+	return o, err
+}
+
+func _Map_String_String_Read(m wire.MapItemList) (map[string]string, error) {
+	if m.KeyType() != wire.TBinary {
+		return nil, nil
+	}
+
+	if m.ValueType() != wire.TBinary {
+		return nil, nil
+	}
+
+	o := make(map[string]string, m.Size())
+	err := m.ForEach(func(x wire.MapItem) error {
+		k, err := x.Key.GetString(), error(nil)
+		if err != nil {
+			return err
+		}
+
+		v, err := x.Value.GetString(), error(nil)
+		if err != nil {
+			return err
+		}
+
+		o[k] = v
+		return nil
+	})
+	m.Close()
+	return o, err
+}
+
 // FromWire deserializes a WorkflowExecutionInfo struct from its Thrift-level
 // representation. The Thrift-level representation may be obtained
 // from a ThriftRW protocol implementation.
@@ -28834,59 +31281,165 @@ func (v *WorkflowExecutionInfo) ToWire() (wire.Value, error) {
 func (v *WorkflowExecutionInfo) FromWire(w wire.Value) error {
 	var err error
 
-	for _, field := range w.GetStruct().Fields {
-		switch field.ID {
-		case 10:
-			if field.Value.Type() == wire.TStruct {
-				v.Execution, err = _WorkflowExecution_Read(field.Value)
+	for _, field := range w.GetStruct().Fields {
+		switch field.ID {
+		case 10:
+			if field.Value.Type() == wire.TStruct {
+				v.Execution, err = _WorkflowExecution_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
+			}
+		case 20:
+			if field.Value.Type() == wire.TStruct {
+				v.Type, err = _WorkflowType_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
+			}
+		case 30:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.StartTime = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 40:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.CloseTime = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 50:
+			if field.Value.Type() == wire.TI32 {
+				var x WorkflowExecutionCloseStatus
+				x, err = _WorkflowExecutionCloseStatus_Read(field.Value)
+				v.CloseStatus = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 60:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.HistoryLength = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 70:
+			if field.Value.Type() == wire.TI32 {
+				var x int32
+				x, err = field.Value.GetI32(), error(nil)
+				v.Depth = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 80:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.SignalCount = &x
 				if err != nil {
 					return err
 				}
 
 			}
-		case 20:
-			if field.Value.Type() == wire.TStruct {
-				v.Type, err = _WorkflowType_Read(field.Value)
+		case 90:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.LastSignalName = &x
 				if err != nil {
 					return err
 				}
 
 			}
-		case 30:
+		case 100:
 			if field.Value.Type() == wire.TI64 {
 				var x int64
 				x, err = field.Value.GetI64(), error(nil)
-				v.StartTime = &x
+				v.LastSignalTimestamp = &x
 				if err != nil {
 					return err
 				}
 
 			}
-		case 40:
+		case 110:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.BinaryChecksum = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 120:
+			if field.Value.Type() == wire.TBool {
+				var x bool
+				x, err = field.Value.GetBool(), error(nil)
+				v.Paused = &x
+				if err != nil {
+					return err
+				}
+
+			}
+		case 130:
 			if field.Value.Type() == wire.TI64 {
 				var x int64
 				x, err = field.Value.GetI64(), error(nil)
-				v.CloseTime = &x
+				v.DecisionAttempt = &x
 				if err != nil {
 					return err
 				}
 
 			}
-		case 50:
+		case 140:
 			if field.Value.Type() == wire.TI32 {
-				var x WorkflowExecutionCloseStatus
-				x, err = _WorkflowExecutionCloseStatus_Read(field.Value)
-				v.CloseStatus = &x
+				var x int32
+				x, err = field.Value.GetI32(), error(nil)
+				v.MaxDecisionAttempts = &x
 				if err != nil {
 					return err
 				}
 
 			}
-		case 60:
+		case 150:
+			if field.Value.Type() == wire.TList {
+				v.ResetPoints, err = _List_ResetPoint_Read(field.Value.GetList())
+				if err != nil {
+					return err
+				}
+
+			}
+		case 160:
+			if field.Value.Type() == wire.TMap {
+				v.OperatorTags, err = _Map_String_String_Read(field.Value.GetMap())
+				if err != nil {
+					return err
+				}
+
+			}
+		case 170:
 			if field.Value.Type() == wire.TI64 {
 				var x int64
 				x, err = field.Value.GetI64(), error(nil)
-				v.HistoryLength = &x
+				v.Generation = &x
 				if err != nil {
 					return err
 				}
@@ -28905,7 +31458,7 @@ func (v *WorkflowExecutionInfo) String() string {
 		return "<nil>"
 	}
 
-	var fields [6]string
+	var fields [17]string
 	i := 0
 	if v.Execution != nil {
 		fields[i] = fmt.Sprintf("Execution: %v", v.Execution)
@@ -28931,10 +31484,86 @@ func (v *WorkflowExecutionInfo) String() string {
 		fields[i] = fmt.Sprintf("HistoryLength: %v", *(v.HistoryLength))
 		i++
 	}
+	if v.Depth != nil {
+		fields[i] = fmt.Sprintf("Depth: %v", *(v.Depth))
+		i++
+	}
+	if v.SignalCount != nil {
+		fields[i] = fmt.Sprintf("SignalCount: %v", *(v.SignalCount))
+		i++
+	}
+	if v.LastSignalName != nil {
+		fields[i] = fmt.Sprintf("LastSignalName: %v", *(v.LastSignalName))
+		i++
+	}
+	if v.LastSignalTimestamp != nil {
+		fields[i] = fmt.Sprintf("LastSignalTimestamp: %v", *(v.LastSignalTimestamp))
+		i++
+	}
+	if v.BinaryChecksum != nil {
+		fields[i] = fmt.Sprintf("BinaryChecksum: %v", *(v.BinaryChecksum))
+		i++
+	}
+	if v.Paused != nil {
+		fields[i] = fmt.Sprintf("Paused: %v", *(v.Paused))
+		i++
+	}
+	if v.DecisionAttempt != nil {
+		fields[i] = fmt.Sprintf("DecisionAttempt: %v", *(v.DecisionAttempt))
+		i++
+	}
+	if v.MaxDecisionAttempts != nil {
+		fields[i] = fmt.Sprintf("MaxDecisionAttempts: %v", *(v.MaxDecisionAttempts))
+		i++
+	}
+	if v.ResetPoints != nil {
+		fields[i] = fmt.Sprintf("ResetPoints: %v", v.ResetPoints)
+		i++
+	}
+	if v.OperatorTags != nil {
+		fields[i] = fmt.Sprintf("OperatorTags: %v", v.OperatorTags)
+		i++
+	}
+	if v.Generation != nil {
+		fields[i] = fmt.Sprintf("Generation: %v", *(v.Generation))
+		i++
+	}
 
 	return fmt.Sprintf("WorkflowExecutionInfo{%v}", strings.Join(fields[:i], ", "))
 }
 
+func _List_ResetPoint_Equals(lhs, rhs []*ResetPoint) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+
+	for i, lv := range lhs {
+		rv := rhs[i]
+		if !lv.Equals(rv) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func _Map_String_String_Equals(lhs, rhs map[string]string) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+
+	for lk, lv := range lhs {
+		rv, ok := rhs[lk]
+		if !ok {
+			return false
+		}
+		if !(lv == rv) {
+			return false
+		}
+	}
+	return true
+}
+
 // Equals returns true if all the fields of this WorkflowExecutionInfo match the
 // provided WorkflowExecutionInfo.
 //
@@ -28958,6 +31587,39 @@ func (v *WorkflowExecutionInfo) Equals(rhs *WorkflowExecutionInfo) bool {
 	if !_I64_EqualsPtr(v.HistoryLength, rhs.HistoryLength) {
 		return false
 	}
+	if !_I32_EqualsPtr(v.Depth, rhs.Depth) {
+		return false
+	}
+	if !_I64_EqualsPtr(v.SignalCount, rhs.SignalCount) {
+		return false
+	}
+	if !_String_EqualsPtr(v.LastSignalName, rhs.LastSignalName) {
+		return false
+	}
+	if !_I64_EqualsPtr(v.LastSignalTimestamp, rhs.LastSignalTimestamp) {
+		return false
+	}
+	if !_String_EqualsPtr(v.BinaryChecksum, rhs.BinaryChecksum) {
+		return false
+	}
+	if !_Bool_EqualsPtr(v.Paused, rhs.Paused) {
+		return false
+	}
+	if !_I64_EqualsPtr(v.DecisionAttempt, rhs.DecisionAttempt) {
+		return false
+	}
+	if !_I32_EqualsPtr(v.MaxDecisionAttempts, rhs.MaxDecisionAttempts) {
+		return false
+	}
+	if !((v.ResetPoints == nil && rhs.ResetPoints == nil) || (v.ResetPoints != nil && rhs.ResetPoints != nil && _List_ResetPoint_Equals(v.ResetPoints, rhs.ResetPoints))) {
+		return false
+	}
+	if !((v.OperatorTags == nil && rhs.OperatorTags == nil) || (v.OperatorTags != nil && rhs.OperatorTags != nil && _Map_String_String_Equals(v.OperatorTags, rhs.OperatorTags))) {
+		return false
+	}
+	if !_I64_EqualsPtr(v.Generation, rhs.Generation) {
+		return false
+	}
 
 	return true
 }
@@ -29002,10 +31664,101 @@ func (v *WorkflowExecutionInfo) GetHistoryLength() (o int64) {
 	return
 }
 
+// GetDepth returns the value of Depth if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionInfo) GetDepth() (o int32) {
+	if v.Depth != nil {
+		return *v.Depth
+	}
+
+	return
+}
+
+// GetSignalCount returns the value of SignalCount if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionInfo) GetSignalCount() (o int64) {
+	if v.SignalCount != nil {
+		return *v.SignalCount
+	}
+
+	return
+}
+
+// GetLastSignalName returns the value of LastSignalName if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionInfo) GetLastSignalName() (o string) {
+	if v.LastSignalName != nil {
+		return *v.LastSignalName
+	}
+
+	return
+}
+
+// GetLastSignalTimestamp returns the value of LastSignalTimestamp if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionInfo) GetLastSignalTimestamp() (o int64) {
+	if v.LastSignalTimestamp != nil {
+		return *v.LastSignalTimestamp
+	}
+
+	return
+}
+
+// GetBinaryChecksum returns the value of BinaryChecksum if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionInfo) GetBinaryChecksum() (o string) {
+	if v.BinaryChecksum != nil {
+		return *v.BinaryChecksum
+	}
+
+	return
+}
+
+// GetPaused returns the value of Paused if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionInfo) GetPaused() (o bool) {
+	if v.Paused != nil {
+		return *v.Paused
+	}
+
+	return
+}
+
+// GetDecisionAttempt returns the value of DecisionAttempt if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionInfo) GetDecisionAttempt() (o int64) {
+	if v.DecisionAttempt != nil {
+		return *v.DecisionAttempt
+	}
+
+	return
+}
+
+// GetMaxDecisionAttempts returns the value of MaxDecisionAttempts if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionInfo) GetMaxDecisionAttempts() (o int32) {
+	if v.MaxDecisionAttempts != nil {
+		return *v.MaxDecisionAttempts
+	}
+
+	return
+}
+
+// GetGeneration returns the value of Generation if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionInfo) GetGeneration() (o int64) {
+	if v.Generation != nil {
+		return *v.Generation
+	}
+
+	return
+}
+
 type WorkflowExecutionSignaledEventAttributes struct {
-	SignalName *string `json:"signalName,omitempty"`
-	Input      []byte  `json:"input,omitempty"`
-	Identity   *string `json:"identity,omitempty"`
+	SignalName     *string `json:"signalName,omitempty"`
+	Input          []byte  `json:"input,omitempty"`
+	Identity       *string `json:"identity,omitempty"`
+	SequenceNumber *int64  `json:"sequenceNumber,omitempty"`
 }
 
 // ToWire translates a WorkflowExecutionSignaledEventAttributes struct into a Thrift-level intermediate
@@ -29025,7 +31778,7 @@ type WorkflowExecutionSignaledEventAttributes struct {
 //   }
 func (v *WorkflowExecutionSignaledEventAttributes) ToWire() (wire.Value, error) {
 	var (
-		fields [3]wire.Field
+		fields [4]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -29055,6 +31808,14 @@ func (v *WorkflowExecutionSignaledEventAttributes) ToWire() (wire.Value, error)
 		fields[i] = wire.Field{ID: 30, Value: w}
 		i++
 	}
+	if v.SequenceNumber != nil {
+		w, err = wire.NewValueI64(*(v.SequenceNumber)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 40, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -29108,6 +31869,16 @@ func (v *WorkflowExecutionSignaledEventAttributes) FromWire(w wire.Value) error
 					return err
 				}
 
+			}
+		case 40:
+			if field.Value.Type() == wire.TI64 {
+				var x int64
+				x, err = field.Value.GetI64(), error(nil)
+				v.SequenceNumber = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -29122,7 +31893,7 @@ func (v *WorkflowExecutionSignaledEventAttributes) String() string {
 		return "<nil>"
 	}
 
-	var fields [3]string
+	var fields [4]string
 	i := 0
 	if v.SignalName != nil {
 		fields[i] = fmt.Sprintf("SignalName: %v", *(v.SignalName))
@@ -29136,6 +31907,10 @@ func (v *WorkflowExecutionSignaledEventAttributes) String() string {
 		fields[i] = fmt.Sprintf("Identity: %v", *(v.Identity))
 		i++
 	}
+	if v.SequenceNumber != nil {
+		fields[i] = fmt.Sprintf("SequenceNumber: %v", *(v.SequenceNumber))
+		i++
+	}
 
 	return fmt.Sprintf("WorkflowExecutionSignaledEventAttributes{%v}", strings.Join(fields[:i], ", "))
 }
@@ -29154,6 +31929,9 @@ func (v *WorkflowExecutionSignaledEventAttributes) Equals(rhs *WorkflowExecution
 	if !_String_EqualsPtr(v.Identity, rhs.Identity) {
 		return false
 	}
+	if !_I64_EqualsPtr(v.SequenceNumber, rhs.SequenceNumber) {
+		return false
+	}
 
 	return true
 }
@@ -29178,6 +31956,16 @@ func (v *WorkflowExecutionSignaledEventAttributes) GetIdentity() (o string) {
 	return
 }
 
+// GetSequenceNumber returns the value of SequenceNumber if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionSignaledEventAttributes) GetSequenceNumber() (o int64) {
+	if v.SequenceNumber != nil {
+		return *v.SequenceNumber
+	}
+
+	return
+}
+
 type WorkflowExecutionStartedEventAttributes struct {
 	WorkflowType                        *WorkflowType      `json:"workflowType,omitempty"`
 	ParentWorkflowDomain                *string            `json:"parentWorkflowDomain,omitempty"`
@@ -29190,6 +31978,7 @@ type WorkflowExecutionStartedEventAttributes struct {
 	ChildPolicy                         *ChildPolicy       `json:"childPolicy,omitempty"`
 	ContinuedExecutionRunId             *string            `json:"continuedExecutionRunId,omitempty"`
 	Identity                            *string            `json:"identity,omitempty"`
+	KeyId                               *string            `json:"keyId,omitempty"`
 }
 
 // ToWire translates a WorkflowExecutionStartedEventAttributes struct into a Thrift-level intermediate
@@ -29209,7 +31998,7 @@ type WorkflowExecutionStartedEventAttributes struct {
 //   }
 func (v *WorkflowExecutionStartedEventAttributes) ToWire() (wire.Value, error) {
 	var (
-		fields [11]wire.Field
+		fields [12]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -29303,6 +32092,14 @@ func (v *WorkflowExecutionStartedEventAttributes) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 60, Value: w}
 		i++
 	}
+	if v.KeyId != nil {
+		w, err = wire.NewValueString(*(v.KeyId)), error(nil)
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 70, Value: w}
+		i++
+	}
 
 	return wire.NewValueStruct(wire.Struct{Fields: fields[:i]}), nil
 }
@@ -29430,6 +32227,16 @@ func (v *WorkflowExecutionStartedEventAttributes) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 70:
+			if field.Value.Type() == wire.TBinary {
+				var x string
+				x, err = field.Value.GetString(), error(nil)
+				v.KeyId = &x
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -29444,7 +32251,7 @@ func (v *WorkflowExecutionStartedEventAttributes) String() string {
 		return "<nil>"
 	}
 
-	var fields [11]string
+	var fields [12]string
 	i := 0
 	if v.WorkflowType != nil {
 		fields[i] = fmt.Sprintf("WorkflowType: %v", v.WorkflowType)
@@ -29490,6 +32297,10 @@ func (v *WorkflowExecutionStartedEventAttributes) String() string {
 		fields[i] = fmt.Sprintf("Identity: %v", *(v.Identity))
 		i++
 	}
+	if v.KeyId != nil {
+		fields[i] = fmt.Sprintf("KeyId: %v", *(v.KeyId))
+		i++
+	}
 
 	return fmt.Sprintf("WorkflowExecutionStartedEventAttributes{%v}", strings.Join(fields[:i], ", "))
 }
@@ -29532,6 +32343,9 @@ func (v *WorkflowExecutionStartedEventAttributes) Equals(rhs *WorkflowExecutionS
 	if !_String_EqualsPtr(v.Identity, rhs.Identity) {
 		return false
 	}
+	if !_String_EqualsPtr(v.KeyId, rhs.KeyId) {
+		return false
+	}
 
 	return true
 }
@@ -29606,6 +32420,16 @@ func (v *WorkflowExecutionStartedEventAttributes) GetIdentity() (o string) {
 	return
 }
 
+// GetKeyId returns the value of KeyId if it is set or its
+// zero value if it is unset.
+func (v *WorkflowExecutionStartedEventAttributes) GetKeyId() (o string) {
+	if v.KeyId != nil {
+		return *v.KeyId
+	}
+
+	return
+}
+
 type WorkflowExecutionTerminatedEventAttributes struct {
 	Reason   *string `json:"reason,omitempty"`
 	Details  []byte  `json:"details,omitempty"`
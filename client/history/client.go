@@ -35,6 +35,9 @@ import (
 
 var _ Client = (*clientImpl)(nil)
 
+// DefaultTimeout is the timeout of the calls made through the history client
+const DefaultTimeout = time.Second * 30
+
 type clientImpl struct {
 	resolver        membership.ServiceResolver
 	tokenSerializer common.TaskTokenSerializer
@@ -209,23 +212,29 @@ func (c *clientImpl) RecordActivityTaskStarted(
 func (c *clientImpl) RespondDecisionTaskCompleted(
 	ctx context.Context,
 	request *h.RespondDecisionTaskCompletedRequest,
-	opts ...yarpc.CallOption) error {
+	opts ...yarpc.CallOption) (*h.RespondDecisionTaskCompletedResponse, error) {
 	taskToken, err := c.tokenSerializer.Deserialize(request.CompleteRequest.TaskToken)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	client, err := c.getHostForRequest(taskToken.WorkflowID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	opts = common.AggregateYarpcOptions(ctx, opts...)
+	var response *h.RespondDecisionTaskCompletedResponse
 	op := func(ctx context.Context, client historyserviceclient.Interface) error {
+		var err error
 		ctx, cancel := c.createContext(ctx)
 		defer cancel()
-		return client.RespondDecisionTaskCompleted(ctx, request, opts...)
+		response, err = client.RespondDecisionTaskCompleted(ctx, request, opts...)
+		return err
 	}
 	err = c.executeWithRedirect(ctx, client, op)
-	return err
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 func (c *clientImpl) RespondDecisionTaskFailed(
@@ -347,18 +356,25 @@ func (c *clientImpl) RecordActivityTaskHeartbeat(
 func (c *clientImpl) RequestCancelWorkflowExecution(
 	ctx context.Context,
 	request *h.RequestCancelWorkflowExecutionRequest,
-	opts ...yarpc.CallOption) error {
+	opts ...yarpc.CallOption) (*workflow.RequestCancelWorkflowExecutionResponse, error) {
 	client, err := c.getHostForRequest(*request.CancelRequest.WorkflowExecution.WorkflowId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	opts = common.AggregateYarpcOptions(ctx, opts...)
+	var response *workflow.RequestCancelWorkflowExecutionResponse
 	op := func(ctx context.Context, client historyserviceclient.Interface) error {
+		var err error
 		ctx, cancel := c.createContext(ctx)
 		defer cancel()
-		return client.RequestCancelWorkflowExecution(ctx, request, opts...)
+		response, err = client.RequestCancelWorkflowExecution(ctx, request, opts...)
+		return err
 	}
-	return c.executeWithRedirect(ctx, client, op)
+	err = c.executeWithRedirect(ctx, client, op)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 func (c *clientImpl) SignalWorkflowExecution(
@@ -507,7 +523,7 @@ func (c *clientImpl) getHostForRequest(workflowID string) (historyserviceclient.
 
 func (c *clientImpl) createContext(parent context.Context) (context.Context, context.CancelFunc) {
 	// TODO: make timeout configurable
-	timeout := time.Second * 30
+	timeout := DefaultTimeout
 	if parent == nil {
 		return context.WithTimeout(context.Background(), timeout)
 	}
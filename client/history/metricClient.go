@@ -149,18 +149,18 @@ func (c *metricClient) RecordActivityTaskStarted(
 func (c *metricClient) RespondDecisionTaskCompleted(
 	context context.Context,
 	request *h.RespondDecisionTaskCompletedRequest,
-	opts ...yarpc.CallOption) error {
+	opts ...yarpc.CallOption) (*h.RespondDecisionTaskCompletedResponse, error) {
 	c.metricsClient.IncCounter(metrics.HistoryClientRespondDecisionTaskCompletedScope, metrics.CadenceRequests)
 
 	sw := c.metricsClient.StartTimer(metrics.HistoryClientRespondDecisionTaskCompletedScope, metrics.CadenceLatency)
-	err := c.client.RespondDecisionTaskCompleted(context, request, opts...)
+	resp, err := c.client.RespondDecisionTaskCompleted(context, request, opts...)
 	sw.Stop()
 
 	if err != nil {
 		c.metricsClient.IncCounter(metrics.HistoryClientRespondDecisionTaskCompletedScope, metrics.HistoryClientFailures)
 	}
 
-	return err
+	return resp, err
 }
 
 func (c *metricClient) RespondDecisionTaskFailed(
@@ -251,18 +251,18 @@ func (c *metricClient) RecordActivityTaskHeartbeat(
 func (c *metricClient) RequestCancelWorkflowExecution(
 	context context.Context,
 	request *h.RequestCancelWorkflowExecutionRequest,
-	opts ...yarpc.CallOption) error {
+	opts ...yarpc.CallOption) (*shared.RequestCancelWorkflowExecutionResponse, error) {
 	c.metricsClient.IncCounter(metrics.HistoryClientRequestCancelWorkflowExecutionScope, metrics.CadenceRequests)
 
 	sw := c.metricsClient.StartTimer(metrics.HistoryClientRequestCancelWorkflowExecutionScope, metrics.CadenceLatency)
-	err := c.client.RequestCancelWorkflowExecution(context, request, opts...)
+	resp, err := c.client.RequestCancelWorkflowExecution(context, request, opts...)
 	sw.Stop()
 
 	if err != nil {
 		c.metricsClient.IncCounter(metrics.HistoryClientRequestCancelWorkflowExecutionScope, metrics.HistoryClientFailures)
 	}
 
-	return err
+	return resp, err
 }
 
 func (c *metricClient) SignalWorkflowExecution(
@@ -221,6 +221,10 @@ func (c *lru) Release(key interface{}) {
 	defer c.mut.Unlock()
 
 	elt := c.byKey[key]
+	if elt == nil {
+		// The element was deleted while it was pinned
+		return
+	}
 	entry := elt.Value.(*entryImpl)
 	entry.refCount--
 }
@@ -281,8 +285,9 @@ func (c *lru) putInternal(key interface{}, value interface{}, allowUpdate bool)
 
 		if oldest.refCount > 0 {
 			// Cache is full with pinned elements
-			// revert the insert and return
-			c.deleteInternal(c.byAccess.Front())
+			// revert the insert and return, the new value was never handed out so it is not reported as removed
+			c.byAccess.Remove(c.byAccess.Front())
+			delete(c.byKey, key)
 			return nil, ErrCacheFull
 		}
 
@@ -231,6 +231,34 @@ func TestRemovedFuncWithTTL_Pin(t *testing.T) {
 	}
 }
 
+func TestRemovedFunc_Pin_CacheFull(t *testing.T) {
+	ch := make(chan interface{}, 1)
+	cache := New(2, &Options{
+		Pin: true,
+		RemovedFunc: func(i interface{}) {
+			ch <- i
+		},
+	})
+
+	_, err := cache.PutIfNotExist("A", "Alpha")
+	assert.NoError(t, err)
+	_, err = cache.PutIfNotExist("B", "Beta")
+	assert.Equal(t, ErrCacheFull, err)
+	assert.Nil(t, cache.Get("B"))
+
+	// The rejected value was never in the cache, so it must not be reported as removed
+	select {
+	case i := <-ch:
+		t.Errorf("RemovedFunc called with %v", i)
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	// Releasing an element which was deleted while pinned is a no-op
+	cache.Delete("A")
+	cache.Release("A")
+	assert.Equal(t, "Alpha", <-ch)
+}
+
 func TestIterator(t *testing.T) {
 	expected := map[string]string{
 		"A": "Alpha",
@@ -39,6 +39,14 @@ const (
 	MultipleCompletionDecisionsEventID = 2040
 	DuplicateTransferTaskEventID       = 2050
 	DecisionFailedEventID              = 2060
+	StaleMutableStateReloadEventID     = 2070
+	StickyTimeoutClampedEventID        = 2080
+	DecisionForceFailedEventID         = 2090
+	UpdateConflictsExceededEventID     = 2095
+	WorkflowForceDeletedEventID        = 2096
+	BufferedEventsDiscardedEventID     = 2097
+	SignalDroppedEventID               = 2098
+	InvalidStickyTaskListEventID       = 2099
 
 	// Transfer Queue Processor events
 	TransferQueueProcessorStarting         = 2100
@@ -273,6 +273,93 @@ func LogDecisionFailedEvent(lg bark.Logger, domainID, workflowID, runID string,
 	}).Info("Failing the decision.")
 }
 
+// LogStaleMutableStateReloadEvent is used to log reloads of cached mutable state detected to be stale by history APIs
+func LogStaleMutableStateReloadEvent(lg bark.Logger, domainID, workflowID, runID, api string) {
+	lg.WithFields(bark.Fields{
+		TagWorkflowEventID:     StaleMutableStateReloadEventID,
+		TagDomainID:            domainID,
+		TagWorkflowExecutionID: workflowID,
+		TagWorkflowRunID:       runID,
+		TagHistoryAPI:          api,
+	}).Info("Reloading stale mutable state.")
+}
+
+// LogStickyTimeoutClampedEvent is used to log a sticky schedule to start timeout requested by a worker which was
+// clamped to the configured range
+func LogStickyTimeoutClampedEvent(lg bark.Logger, domainID, workflowID, runID string, requested, clamped int32) {
+	lg.WithFields(bark.Fields{
+		TagWorkflowEventID:     StickyTimeoutClampedEventID,
+		TagDomainID:            domainID,
+		TagWorkflowExecutionID: workflowID,
+		TagWorkflowRunID:       runID,
+	}).Infof("Clamping sticky schedule to start timeout.  Requested: %v, Clamped: %v", requested, clamped)
+}
+
+// LogDecisionForceFailedEvent is used to log decisions failed on request of an operator
+func LogDecisionForceFailedEvent(lg bark.Logger, domainID, workflowID, runID, identity string) {
+	lg.WithFields(bark.Fields{
+		TagWorkflowEventID:     DecisionForceFailedEventID,
+		TagDomainID:            domainID,
+		TagWorkflowExecutionID: workflowID,
+		TagWorkflowRunID:       runID,
+	}).Infof("Decision task force failed by operator: %v", identity)
+}
+
+// LogUpdateConflictsExceededEvent is used to log updates of a workflow execution which gave up after conflicting on
+// every attempt
+func LogUpdateConflictsExceededEvent(lg bark.Logger, domainID, workflowID, runID, lastConflict string) {
+	lg.WithFields(bark.Fields{
+		TagWorkflowEventID:     UpdateConflictsExceededEventID,
+		TagDomainID:            domainID,
+		TagWorkflowExecutionID: workflowID,
+		TagWorkflowRunID:       runID,
+	}).Warnf("Giving up update of workflow execution after repeated conflicts.  Last conflict: %v", lastConflict)
+}
+
+// LogWorkflowForceDeletedEvent is used to log workflow executions deleted by an operator
+func LogWorkflowForceDeletedEvent(lg bark.Logger, domainID, workflowID, runID, identity string, wasRunning bool) {
+	lg.WithFields(bark.Fields{
+		TagWorkflowEventID:     WorkflowForceDeletedEventID,
+		TagDomainID:            domainID,
+		TagWorkflowExecutionID: workflowID,
+		TagWorkflowRunID:       runID,
+	}).Warnf("Workflow execution force deleted by operator: %v, running: %v", identity, wasRunning)
+}
+
+// LogBufferedEventsDiscardedEvent is used to log buffered events which are discarded because the workflow execution
+// closed while a decision was in flight
+func LogBufferedEventsDiscardedEvent(lg bark.Logger, domainID, workflowID, runID string, count int,
+	eventTypes map[string]int) {
+	lg.WithFields(bark.Fields{
+		TagWorkflowEventID:     BufferedEventsDiscardedEventID,
+		TagDomainID:            domainID,
+		TagWorkflowExecutionID: workflowID,
+		TagWorkflowRunID:       runID,
+	}).Warnf("Discarding buffered events on workflow close.  Count: %v, Types: %v", count, eventTypes)
+}
+
+// LogSignalDroppedEvent is used to log a signal which was never delivered because the workflow execution closed
+// while it was buffered
+func LogSignalDroppedEvent(lg bark.Logger, domainID, workflowID, runID, signalName, identity string) {
+	lg.WithFields(bark.Fields{
+		TagWorkflowEventID:     SignalDroppedEventID,
+		TagDomainID:            domainID,
+		TagWorkflowExecutionID: workflowID,
+		TagWorkflowRunID:       runID,
+	}).Warnf("Signal dropped on workflow close: %v, identity: %v", signalName, identity)
+}
+
+// LogInvalidStickyTaskListEvent is used to log a sticky task list set by a worker which was rejected, disabling
+// stickiness for the workflow
+func LogInvalidStickyTaskListEvent(lg bark.Logger, domainID, workflowID, runID, reason string) {
+	lg.WithFields(bark.Fields{
+		TagWorkflowEventID:     InvalidStickyTaskListEventID,
+		TagDomainID:            domainID,
+		TagWorkflowExecutionID: workflowID,
+		TagWorkflowRunID:       runID,
+	}).Warnf("Invalid sticky task list, disabling stickiness: %v", reason)
+}
+
 //
 // Matching service logging methods
 //
@@ -47,6 +47,7 @@ const (
 	TagConsumerName         = "consumer-name"
 	TagPartition            = "partition"
 	TagOffset               = "offset"
+	TagHistoryAPI           = "history-api"
 
 	// workflow logging tag values
 	// TagWorkflowComponent Values
@@ -108,6 +109,7 @@ const (
 	TagValueStoreOperationGetWorkflowExecution    = "get-wf-execution"
 	TagValueStoreOperationUpdateWorkflowExecution = "update-wf-execution"
 	TagValueStoreOperationDeleteWorkflowExecution = "delete-wf-execution"
+	TagValueStoreOperationAppendHistoryEvents     = "append-history-events"
 	TagValueStoreOperationUpdateShard             = "update-shard"
 	TagValueStoreOperationCreateTask              = "create-task"
 	TagValueStoreOperationUpdateTaskList          = "update-task-list"
@@ -67,6 +67,18 @@ const (
 	OperationTagName = "operation"
 	// ShardTagName is temporary until we can get all metric data removed for the service
 	ShardTagName = "shard"
+	// DomainTagName is used by metrics which are broken down per domain
+	DomainTagName = "domain"
+	// CloseStatusTagName is used by metrics which are broken down per workflow close status
+	CloseStatusTagName = "close_status"
+	// TaskListTagName is used by metrics which are broken down per task list
+	TaskListTagName = "tasklist"
+	// ClusterTagName is used by metrics which are broken down per cluster
+	ClusterTagName = "cluster"
+	// WorkflowTypeTagName is used by metrics which are broken down per workflow type
+	WorkflowTypeTagName = "workflow_type"
+	// ConflictCauseTagName is used by metrics which are broken down per likely cause of an update conflict
+	ConflictCauseTagName = "conflict_cause"
 )
 
 // This package should hold all the metrics and tags for cadence
@@ -126,8 +138,14 @@ const (
 	PersistenceUpdateWorkflowExecutionScope
 	// PersistenceDeleteWorkflowExecutionScope tracks DeleteWorkflowExecution calls made by service to persistence layer
 	PersistenceDeleteWorkflowExecutionScope
+	// PersistenceDeleteCurrentWorkflowExecutionScope tracks DeleteCurrentWorkflowExecution calls made by service to persistence layer
+	PersistenceDeleteCurrentWorkflowExecutionScope
+	// PersistenceUpdateCurrentWorkflowExecutionScope tracks UpdateCurrentWorkflowExecution calls made by service to persistence layer
+	PersistenceUpdateCurrentWorkflowExecutionScope
 	// PersistenceGetCurrentExecutionScope tracks GetCurrentExecution calls made by service to persistence layer
 	PersistenceGetCurrentExecutionScope
+	// PersistenceListOpenExecutionsScope tracks ListOpenExecutions calls made by service to persistence layer
+	PersistenceListOpenExecutionsScope
 	// PersistenceGetTransferTasksScope tracks GetTransferTasks calls made by service to persistence layer
 	PersistenceGetTransferTasksScope
 	// PersistenceGetReplicationTasksScope tracks GetReplicationTasks calls made by service to persistence layer
@@ -154,6 +172,8 @@ const (
 	PersistenceAppendHistoryEventsScope
 	// PersistenceGetWorkflowExecutionHistoryScope tracks GetWorkflowExecutionHistory calls made by service to persistence layer
 	PersistenceGetWorkflowExecutionHistoryScope
+	// PersistenceGetWorkflowExecutionRawHistoryScope tracks GetWorkflowExecutionRawHistory calls made by service to persistence layer
+	PersistenceGetWorkflowExecutionRawHistoryScope
 	// PersistenceDeleteWorkflowExecutionHistoryScope tracks DeleteWorkflowExecutionHistory calls made by service to persistence layer
 	PersistenceDeleteWorkflowExecutionHistoryScope
 	// PersistenceCreateDomainScope tracks CreateDomain calls made by service to persistence layer
@@ -378,12 +398,62 @@ const (
 	TimerTaskWorkflowTimeoutScope
 	// TimerTaskDeleteHistoryEvent is the scope used by metric emitted by timer queue processor for processing history event cleanup
 	TimerTaskDeleteHistoryEvent
+	// TimerTaskDecisionBackoffScope is the scope used by metric emitted by timer queue processor for dispatching decisions after backoff
+	TimerTaskDecisionBackoffScope
 	// HistoryEventNotificationScope is the scope used by shard history event nitification
 	HistoryEventNotificationScope
 	// ReplicatorQueueProcessorScope is the scope used by all metric emitted by replicator queue processor
 	ReplicatorQueueProcessorScope
 	// ReplicatorTaskHistoryScope is the scope used for history task processing by replicator queue processor
 	ReplicatorTaskHistoryScope
+	// HistoryWorkflowUpdateScope is the scope used by workflow updates applied by history engine
+	HistoryWorkflowUpdateScope
+	// HistoryResetStickyTaskListByTypeScope tracks ResetStickyTaskListByWorkflowType API calls received by service
+	HistoryResetStickyTaskListByTypeScope
+	// HistoryGetShardStatsScope tracks GetShardStats API calls received by service
+	HistoryGetShardStatsScope
+	// HistoryCacheScope is the scope used by the workflow execution cache of a shard
+	HistoryCacheScope
+	// HistoryGetWorkflowExecutionRawHistoryScope tracks GetWorkflowExecutionRawHistory API calls received by service
+	HistoryGetWorkflowExecutionRawHistoryScope
+	// HistoryForceFailDecisionTaskScope tracks ForceFailDecisionTask API calls received by service
+	HistoryForceFailDecisionTaskScope
+	// HistorySetWorkflowExecutionPausedScope tracks SetWorkflowExecutionPaused API calls received by service
+	HistorySetWorkflowExecutionPausedScope
+	// HistoryGetQueueLagScope tracks GetQueueLag API calls received by service
+	HistoryGetQueueLagScope
+	// HistoryForceDeleteWorkflowExecutionScope tracks ForceDeleteWorkflowExecution API calls received by service
+	HistoryForceDeleteWorkflowExecutionScope
+	// HistoryRedriveTransferTasksScope tracks RedriveTransferTasks API calls received by service
+	HistoryRedriveTransferTasksScope
+	// HistoryPauseTimerProcessingScope tracks PauseTimerProcessing API calls received by service
+	HistoryPauseTimerProcessingScope
+	// HistoryResumeTimerProcessingScope tracks ResumeTimerProcessing API calls received by service
+	HistoryResumeTimerProcessingScope
+	// HistorySetWorkflowExecutionOperatorTagsScope tracks SetWorkflowExecutionOperatorTags API calls received by service
+	HistorySetWorkflowExecutionOperatorTagsScope
+	// HistoryGetHistoryWindowScope tracks GetWorkflowExecutionHistoryWindow API calls received by service
+	HistoryGetHistoryWindowScope
+	// HistoryGetActivityScheduledEventScope tracks GetActivityScheduledEvent API calls received by service
+	HistoryGetActivityScheduledEventScope
+	// HistoryReconcileCurrentExecutionScope tracks ReconcileCurrentExecution API calls received by service
+	HistoryReconcileCurrentExecutionScope
+	// HistoryGetWorkflowExecutionResultScope tracks GetWorkflowExecutionResult API calls received by service
+	HistoryGetWorkflowExecutionResultScope
+	// HistoryListShardOpenExecutionsScope tracks ListShardOpenExecutions API calls received by service
+	HistoryListShardOpenExecutionsScope
+	// HistoryShardContextScope is the scope used by the context of a shard
+	HistoryShardContextScope
+	// HistoryGetHistoryEventScope tracks GetHistoryEvent API calls received by service
+	HistoryGetHistoryEventScope
+	// HistoryTerminateAndStartScope tracks TerminateAndStart API calls received by service
+	HistoryTerminateAndStartScope
+	// HistoryDescribeActivityScope tracks DescribeActivity API calls received by service
+	HistoryDescribeActivityScope
+	// HistoryBulkTerminateScope tracks BulkTerminateWorkflowExecutions API calls received by service
+	HistoryBulkTerminateScope
+	// HistoryValidateDecisionsScope tracks ValidateDecisions API calls received by service
+	HistoryValidateDecisionsScope
 
 	NumHistoryScopes
 )
@@ -431,7 +501,10 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		PersistenceGetWorkflowExecutionScope:                     {operation: "GetWorkflowExecution"},
 		PersistenceUpdateWorkflowExecutionScope:                  {operation: "UpdateWorkflowExecution"},
 		PersistenceDeleteWorkflowExecutionScope:                  {operation: "DeleteWorkflowExecution"},
+		PersistenceDeleteCurrentWorkflowExecutionScope:           {operation: "DeleteCurrentWorkflowExecution"},
+		PersistenceUpdateCurrentWorkflowExecutionScope:           {operation: "UpdateCurrentWorkflowExecution"},
 		PersistenceGetCurrentExecutionScope:                      {operation: "GetCurrentExecution"},
+		PersistenceListOpenExecutionsScope:                       {operation: "ListOpenExecutions"},
 		PersistenceGetTransferTasksScope:                         {operation: "GetTransferTasks"},
 		PersistenceGetReplicationTasksScope:                      {operation: "GetReplicationTasks"},
 		PersistenceCompleteTransferTaskScope:                     {operation: "CompleteTransferTask"},
@@ -445,6 +518,7 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		PersistenceUpdateTaskListScope:                           {operation: "UpdateTaskList", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
 		PersistenceAppendHistoryEventsScope:                      {operation: "AppendHistoryEvents", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
 		PersistenceGetWorkflowExecutionHistoryScope:              {operation: "GetWorkflowExecutionHistory", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceGetWorkflowExecutionRawHistoryScope:           {operation: "GetWorkflowExecutionRawHistory", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
 		PersistenceDeleteWorkflowExecutionHistoryScope:           {operation: "DeleteWorkflowExecutionHistory", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
 		PersistenceCreateDomainScope:                             {operation: "CreateDomain", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
 		PersistenceGetDomainScope:                                {operation: "GetDomain", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
@@ -558,9 +632,34 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		TimerTaskUserTimerScope:                      {operation: "TimerTaskUserTimer"},
 		TimerTaskWorkflowTimeoutScope:                {operation: "TimerTaskWorkflowTimeout"},
 		TimerTaskDeleteHistoryEvent:                  {operation: "TimerTaskDeleteHistoryEvent"},
+		TimerTaskDecisionBackoffScope:                {operation: "TimerTaskDecisionBackoff"},
 		HistoryEventNotificationScope:                {operation: "HistoryEventNotification"},
 		ReplicatorQueueProcessorScope:                {operation: "ReplicatorQueueProcessor"},
 		ReplicatorTaskHistoryScope:                   {operation: "ReplicatorTaskHistory"},
+		HistoryWorkflowUpdateScope:                   {operation: "WorkflowUpdate"},
+		HistoryResetStickyTaskListByTypeScope:        {operation: "ResetStickyTaskListByWorkflowType"},
+		HistoryGetShardStatsScope:                    {operation: "GetShardStats"},
+		HistoryCacheScope:                            {operation: "HistoryCache"},
+		HistoryGetWorkflowExecutionRawHistoryScope:   {operation: "GetWorkflowExecutionRawHistory"},
+		HistoryForceFailDecisionTaskScope:            {operation: "ForceFailDecisionTask"},
+		HistorySetWorkflowExecutionPausedScope:       {operation: "SetWorkflowExecutionPaused"},
+		HistoryGetQueueLagScope:                      {operation: "GetQueueLag"},
+		HistoryForceDeleteWorkflowExecutionScope:     {operation: "ForceDeleteWorkflowExecution"},
+		HistoryRedriveTransferTasksScope:             {operation: "RedriveTransferTasks"},
+		HistoryPauseTimerProcessingScope:             {operation: "PauseTimerProcessing"},
+		HistoryResumeTimerProcessingScope:            {operation: "ResumeTimerProcessing"},
+		HistorySetWorkflowExecutionOperatorTagsScope: {operation: "SetWorkflowExecutionOperatorTags"},
+		HistoryGetHistoryWindowScope:                 {operation: "GetWorkflowExecutionHistoryWindow"},
+		HistoryGetActivityScheduledEventScope:        {operation: "GetActivityScheduledEvent"},
+		HistoryReconcileCurrentExecutionScope:        {operation: "ReconcileCurrentExecution"},
+		HistoryGetWorkflowExecutionResultScope:       {operation: "GetWorkflowExecutionResult"},
+		HistoryListShardOpenExecutionsScope:          {operation: "ListShardOpenExecutions"},
+		HistoryShardContextScope:                     {operation: "ShardContext"},
+		HistoryGetHistoryEventScope:                  {operation: "GetHistoryEvent"},
+		HistoryTerminateAndStartScope:                {operation: "TerminateAndStart"},
+		HistoryDescribeActivityScope:                 {operation: "DescribeActivity"},
+		HistoryBulkTerminateScope:                    {operation: "BulkTerminateWorkflowExecutions"},
+		HistoryValidateDecisionsScope:                {operation: "ValidateDecisions"},
 	},
 	// Matching Scope Names
 	Matching: {
@@ -591,6 +690,7 @@ const (
 	CadenceErrExecutionAlreadyStartedCounter
 	CadenceErrDomainAlreadyExistsCounter
 	CadenceErrCancellationAlreadyRequestedCounter
+	CadenceErrDomainNotActiveCounter
 	CadenceErrQueryFailedCounter
 	PersistenceRequests
 	PersistenceFailures
@@ -655,6 +755,43 @@ const (
 	HistoryEventNotificationFanoutLatency
 	HistoryEventNotificationInFlightMessageGauge
 	HistoryEventNotificationFailDeliveryCount
+	ConcurrentUpdatesInFlightGauge
+	ConcurrentUpdatesThrottledCounter
+	StaleMutableStatePerDomainCounter
+	WorkflowClosedCounter
+	SyncMatchFirstDecisionFailedCounter
+	ShutdownDrainAbandonedCounter
+	CacheHitCounter
+	CacheMissCounter
+	CacheEvictionCounter
+	CacheFullCounter
+	WorkflowClosedWithPendingActivitiesCounter
+	DeniedBinaryChecksumCounter
+	PayloadOffloadedCounter
+	ActivityScheduleToStartLatency
+	UserTimerFireDelay
+	TransferTaskIDLagGauge
+	TimerLagGauge
+	DomainIsolationInFlightGauge
+	DomainIsolationThrottledCounter
+	RedrivenTransferTasksCounter
+	ActivityDispatchDeadlineExceededCounter
+	TimerProcessingAutoResumedCounter
+	EmptyDecisionCounter
+	CurrentExecutionRepairedCounter
+	BufferedEventsDiscardedCounter
+	ReplicationApplyRetryCounter
+	ReplicationApplyRetriesExhaustedCounter
+	ChildStartRejectedCounter
+	WorkflowFirstDecisionLatency
+	WorkflowTimeoutCounter
+	SignalBackpressureCounter
+	OpenExecutionsGauge
+	OpenExecutionLimitExceededCounter
+	DecisionConflictCounter
+	InvalidStickyTaskListCounter
+	StrongConsistencyReadCounter
+	BulkTerminatedCounter
 )
 
 // Matching metrics enum
@@ -689,6 +826,7 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		CadenceErrExecutionAlreadyStartedCounter:      {metricName: "cadence.errors.execution-already-started", metricType: Counter},
 		CadenceErrDomainAlreadyExistsCounter:          {metricName: "cadence.errors.domain-already-exists", metricType: Counter},
 		CadenceErrCancellationAlreadyRequestedCounter: {metricName: "cadence.errors.cancellation-already-requested", metricType: Counter},
+		CadenceErrDomainNotActiveCounter:              {metricName: "cadence.errors.domain-not-active", metricType: Counter},
 		CadenceErrQueryFailedCounter:                  {metricName: "cadence.errors.query-failed", metricType: Counter},
 		PersistenceRequests:                           {metricName: "persistence.requests", metricType: Counter},
 		PersistenceFailures:                           {metricName: "persistence.errors", metricType: Counter},
@@ -748,6 +886,43 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		HistoryEventNotificationFanoutLatency:        {metricName: "history-event-notification-fanout-latency", metricType: Timer},
 		HistoryEventNotificationInFlightMessageGauge: {metricName: "history-event-notification-inflight-message-gauge", metricType: Gauge},
 		HistoryEventNotificationFailDeliveryCount:    {metricName: "history-event-notification-fail-delivery-count", metricType: Counter},
+		ConcurrentUpdatesInFlightGauge:               {metricName: "concurrent-updates-inflight-gauge", metricType: Gauge},
+		ConcurrentUpdatesThrottledCounter:            {metricName: "concurrent-updates-throttled", metricType: Counter},
+		StaleMutableStatePerDomainCounter:            {metricName: "stale-mutable-state-per-domain", metricType: Counter},
+		WorkflowClosedCounter:                        {metricName: "workflow-closed", metricType: Counter},
+		SyncMatchFirstDecisionFailedCounter:          {metricName: "sync-match-first-decision-failed", metricType: Counter},
+		ShutdownDrainAbandonedCounter:                {metricName: "shutdown-drain-abandoned", metricType: Counter},
+		CacheHitCounter:                              {metricName: "cache-hit", metricType: Counter},
+		CacheMissCounter:                             {metricName: "cache-miss", metricType: Counter},
+		CacheEvictionCounter:                         {metricName: "cache-eviction", metricType: Counter},
+		CacheFullCounter:                             {metricName: "cache-full", metricType: Counter},
+		WorkflowClosedWithPendingActivitiesCounter:   {metricName: "workflow-closed-with-pending-activities", metricType: Counter},
+		DeniedBinaryChecksumCounter:                  {metricName: "denied-binary-checksum", metricType: Counter},
+		PayloadOffloadedCounter:                      {metricName: "payload-offloaded", metricType: Counter},
+		ActivityScheduleToStartLatency:               {metricName: "activity-schedule-to-start-latency", metricType: Timer},
+		UserTimerFireDelay:                           {metricName: "user-timer-fire-delay", metricType: Timer},
+		TransferTaskIDLagGauge:                       {metricName: "transfer-task-id-lag", metricType: Gauge},
+		TimerLagGauge:                                {metricName: "timer-lag-seconds", metricType: Gauge},
+		DomainIsolationInFlightGauge:                 {metricName: "domain-isolation-inflight", metricType: Gauge},
+		DomainIsolationThrottledCounter:              {metricName: "domain-isolation-throttled", metricType: Counter},
+		RedrivenTransferTasksCounter:                 {metricName: "redriven-transfer-tasks", metricType: Counter},
+		ActivityDispatchDeadlineExceededCounter:      {metricName: "activity-dispatch-deadline-exceeded", metricType: Counter},
+		TimerProcessingAutoResumedCounter:            {metricName: "timer-processing-auto-resumed", metricType: Counter},
+		EmptyDecisionCounter:                         {metricName: "empty-decision", metricType: Counter},
+		CurrentExecutionRepairedCounter:              {metricName: "current-execution-repaired", metricType: Counter},
+		BufferedEventsDiscardedCounter:               {metricName: "buffered-events-discarded", metricType: Counter},
+		ReplicationApplyRetryCounter:                 {metricName: "replication-apply-retries", metricType: Counter},
+		ReplicationApplyRetriesExhaustedCounter:      {metricName: "replication-apply-retries-exhausted", metricType: Counter},
+		ChildStartRejectedCounter:                    {metricName: "child-start-rejected", metricType: Counter},
+		WorkflowFirstDecisionLatency:                 {metricName: "workflow-first-decision-latency", metricType: Timer},
+		WorkflowTimeoutCounter:                       {metricName: "workflow-timeout", metricType: Counter},
+		SignalBackpressureCounter:                    {metricName: "signal-backpressure", metricType: Counter},
+		OpenExecutionsGauge:                          {metricName: "open-executions", metricType: Gauge},
+		OpenExecutionLimitExceededCounter:            {metricName: "open-execution-limit-exceeded", metricType: Counter},
+		DecisionConflictCounter:                      {metricName: "decision-conflict", metricType: Counter},
+		InvalidStickyTaskListCounter:                 {metricName: "invalid-sticky-tasklist", metricType: Counter},
+		StrongConsistencyReadCounter:                 {metricName: "strong-consistency-read", metricType: Counter},
+		BulkTerminatedCounter:                        {metricName: "bulk-terminated", metricType: Counter},
 	},
 	Matching: {
 		PollSuccessCounter:            {metricName: "poll.success"},
@@ -212,17 +212,26 @@ func (_m *HistoryClient) RespondActivityTaskFailed(ctx context.Context, failRequ
 }
 
 // RespondDecisionTaskCompleted provides a mock function with given fields: ctx, completeRequest
-func (_m *HistoryClient) RespondDecisionTaskCompleted(ctx context.Context, completeRequest *history.RespondDecisionTaskCompletedRequest, opts ...yarpc.CallOption) error {
+func (_m *HistoryClient) RespondDecisionTaskCompleted(ctx context.Context, completeRequest *history.RespondDecisionTaskCompletedRequest, opts ...yarpc.CallOption) (*history.RespondDecisionTaskCompletedResponse, error) {
 	ret := _m.Called(ctx, completeRequest)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *history.RespondDecisionTaskCompletedRequest) error); ok {
+	var r0 *history.RespondDecisionTaskCompletedResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *history.RespondDecisionTaskCompletedRequest) *history.RespondDecisionTaskCompletedResponse); ok {
 		r0 = rf(ctx, completeRequest)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*history.RespondDecisionTaskCompletedResponse)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *history.RespondDecisionTaskCompletedRequest) error); ok {
+		r1 = rf(ctx, completeRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // RespondDecisionTaskFailed provides a mock function with given fields: ctx, failedRequest
@@ -316,17 +325,26 @@ func (_m *HistoryClient) StartWorkflowExecution(ctx context.Context, startReques
 }
 
 // RequestCancelWorkflowExecution is mock implementation for RequestCancelWorkflowExecution of HistoryEngine
-func (_m *HistoryClient) RequestCancelWorkflowExecution(ctx context.Context, request *history.RequestCancelWorkflowExecutionRequest, opts ...yarpc.CallOption) error {
+func (_m *HistoryClient) RequestCancelWorkflowExecution(ctx context.Context, request *history.RequestCancelWorkflowExecutionRequest, opts ...yarpc.CallOption) (*shared.RequestCancelWorkflowExecutionResponse, error) {
 	ret := _m.Called(request)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*history.RequestCancelWorkflowExecutionRequest) error); ok {
+	var r0 *shared.RequestCancelWorkflowExecutionResponse
+	if rf, ok := ret.Get(0).(func(*history.RequestCancelWorkflowExecutionRequest) *shared.RequestCancelWorkflowExecutionResponse); ok {
 		r0 = rf(request)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shared.RequestCancelWorkflowExecutionResponse)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*history.RequestCancelWorkflowExecutionRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // TerminateWorkflowExecution provides a mock function with given fields: ctx, terminateRequest
@@ -85,4 +85,28 @@ func (_m *HistoryManager) GetWorkflowExecutionHistory(
 	return r0, r1
 }
 
+// GetWorkflowExecutionRawHistory provides a mock function with given fields: request
+func (_m *HistoryManager) GetWorkflowExecutionRawHistory(
+	request *persistence.GetWorkflowExecutionHistoryRequest) (*persistence.GetWorkflowExecutionRawHistoryResponse, error) {
+	ret := _m.Called(request)
+
+	var r0 *persistence.GetWorkflowExecutionRawHistoryResponse
+	if rf, ok := ret.Get(0).(func(*persistence.GetWorkflowExecutionHistoryRequest) *persistence.GetWorkflowExecutionRawHistoryResponse); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.GetWorkflowExecutionRawHistoryResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*persistence.GetWorkflowExecutionHistoryRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 var _ persistence.HistoryManager = (*HistoryManager)(nil)
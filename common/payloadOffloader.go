@@ -0,0 +1,53 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package common
+
+type (
+	// PayloadOffloader moves large payloads, such as activity results, out of
+	// workflow history and into an external store. Offload is invoked
+	// synchronously while a workflow update is being applied, and may be invoked
+	// again for the same payload if the update is retried, so implementations
+	// should be idempotent.
+	PayloadOffloader interface {
+		// Offload stores the payload and returns the reference which is recorded
+		// in history in its place
+		Offload(domainID, workflowID, runID string, payload []byte) ([]byte, error)
+		// Hydrate returns the original payload for a value read from history,
+		// which may or may not be a reference returned by Offload
+		Hydrate(domainID string, payload []byte) ([]byte, error)
+	}
+
+	// noopPayloadOffloader keeps all payloads in history
+	noopPayloadOffloader struct{}
+)
+
+// NewNoopPayloadOffloader returns a payload offloader which leaves payloads unchanged
+func NewNoopPayloadOffloader() PayloadOffloader {
+	return &noopPayloadOffloader{}
+}
+
+func (o *noopPayloadOffloader) Offload(domainID, workflowID, runID string, payload []byte) ([]byte, error) {
+	return payload, nil
+}
+
+func (o *noopPayloadOffloader) Hydrate(domainID string, payload []byte) ([]byte, error) {
+	return payload, nil
+}
@@ -47,6 +47,13 @@ const (
 		`AND first_event_id >= ? ` +
 		`AND first_event_id < ?`
 
+	templateGetWorkflowExecutionRawHistory = `SELECT first_event_id, tx_id, data, data_encoding, data_version FROM events ` +
+		`WHERE domain_id = ? ` +
+		`AND workflow_id = ? ` +
+		`AND run_id = ? ` +
+		`AND first_event_id >= ? ` +
+		`AND first_event_id < ?`
+
 	templateDeleteWorkflowExecutionHistory = `DELETE FROM events ` +
 		`WHERE domain_id = ? ` +
 		`AND workflow_id = ? ` +
@@ -189,6 +196,50 @@ func (h *cassandraHistoryPersistence) GetWorkflowExecutionHistory(request *GetWo
 	return response, nil
 }
 
+func (h *cassandraHistoryPersistence) GetWorkflowExecutionRawHistory(request *GetWorkflowExecutionHistoryRequest) (
+	*GetWorkflowExecutionRawHistoryResponse, error) {
+	execution := request.Execution
+	query := h.session.Query(templateGetWorkflowExecutionRawHistory,
+		request.DomainID,
+		*execution.WorkflowId,
+		*execution.RunId,
+		request.FirstEventID,
+		request.NextEventID)
+
+	iter := query.PageSize(request.PageSize).PageState(request.NextPageToken).Iter()
+	if iter == nil {
+		return nil, &workflow.InternalServiceError{
+			Message: "GetWorkflowExecutionRawHistory operation failed.  Not able to create query iterator.",
+		}
+	}
+
+	batch := &RawHistoryBatch{}
+	response := &GetWorkflowExecutionRawHistoryResponse{}
+	for iter.Scan(&batch.FirstEventID, &batch.TransactionID, &batch.Events.Data, &batch.Events.EncodingType,
+		&batch.Events.Version) {
+		response.Batches = append(response.Batches, batch)
+		batch = &RawHistoryBatch{}
+	}
+
+	nextPageToken := iter.PageState()
+	response.NextPageToken = make([]byte, len(nextPageToken))
+	copy(response.NextPageToken, nextPageToken)
+	if err := iter.Close(); err != nil {
+		return nil, &workflow.InternalServiceError{
+			Message: fmt.Sprintf("GetWorkflowExecutionRawHistory operation failed. Error: %v", err),
+		}
+	}
+
+	if len(response.Batches) == 0 && len(request.NextPageToken) == 0 {
+		return nil, &workflow.EntityNotExistsError{
+			Message: fmt.Sprintf("Workflow execution history not found.  WorkflowId: %v, RunId: %v",
+				*execution.WorkflowId, *execution.RunId),
+		}
+	}
+
+	return response, nil
+}
+
 func (h *cassandraHistoryPersistence) DeleteWorkflowExecutionHistory(
 	request *DeleteWorkflowExecutionHistoryRequest) error {
 	execution := request.Execution
@@ -114,6 +114,41 @@ func (s *historyPersistenceSuite) TestGetHistoryEvents() {
 	s.Equal(events, history[0].Data)
 }
 
+func (s *historyPersistenceSuite) TestGetRawHistoryEvents() {
+	domainID := "5d3e3f34-2a06-4a9a-9e4e-3b0de0d2cf6a"
+	workflowExecution := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("get-raw-history-events-test"),
+		RunId:      common.StringPtr("4c8d8b2e-6f7d-4f02-8a36-0c2f55a5f8a1"),
+	}
+
+	events1 := []byte("event1;event2")
+	serializedHistory := &SerializedHistoryEventBatch{Version: 1, EncodingType: common.EncodingTypeJSON, Data: events1}
+	err0 := s.AppendHistoryEvents(domainID, workflowExecution, 1, 1, 5, serializedHistory, false)
+	s.Nil(err0)
+	events2 := []byte("event3;")
+	serializedHistory = &SerializedHistoryEventBatch{Version: 1, EncodingType: common.EncodingTypeJSON, Data: events2}
+	err0 = s.AppendHistoryEvents(domainID, workflowExecution, 3, 1, 7, serializedHistory, false)
+	s.Nil(err0)
+
+	response, err1 := s.HistoryMgr.GetWorkflowExecutionRawHistory(&GetWorkflowExecutionHistoryRequest{
+		DomainID:     domainID,
+		Execution:    workflowExecution,
+		FirstEventID: 1,
+		NextEventID:  4,
+		PageSize:     10,
+	})
+	s.Nil(err1)
+	s.Equal([]byte{}, response.NextPageToken)
+	s.Equal(2, len(response.Batches))
+	s.Equal(int64(1), response.Batches[0].FirstEventID)
+	s.Equal(int64(5), response.Batches[0].TransactionID)
+	s.Equal(events1, response.Batches[0].Events.Data)
+	s.Equal(int64(3), response.Batches[1].FirstEventID)
+	s.Equal(int64(7), response.Batches[1].TransactionID)
+	s.Equal(common.EncodingTypeJSON, response.Batches[1].Events.EncodingType)
+	s.Equal(events2, response.Batches[1].Events.Data)
+}
+
 func (s *historyPersistenceSuite) TestGetHistoryEventsCompatibility() {
 	domainID := "373de9d6-e41e-42d4-bee9-9e06968e4d0d"
 	workflowExecution := gen.WorkflowExecution{
@@ -109,7 +109,10 @@ const (
 		`transfer_ack_level: ?, ` +
 		`timer_ack_level: ?, ` +
 		`cluster_transfer_ack_level: ?, ` +
-		`cluster_timer_ack_level: ?` +
+		`cluster_timer_ack_level: ?, ` +
+		`open_execution_count: ?, ` +
+		`closed_execution_count: ?, ` +
+		`executions_counted_at: ?` +
 		`}`
 
 	templateWorkflowExecutionType = `{` +
@@ -142,11 +145,24 @@ const (
 		`decision_timestamp: ?, ` +
 		`cancel_requested: ?, ` +
 		`cancel_request_id: ?, ` +
+		`cancel_requested_event_id: ?, ` +
 		`sticky_task_list: ?, ` +
 		`sticky_schedule_to_start_timeout: ?,` +
 		`client_library_version: ?, ` +
 		`client_feature_version: ?, ` +
-		`client_impl: ?` +
+		`client_impl: ?, ` +
+		`binary_checksum: ?, ` +
+		`paused: ?, ` +
+		`key_id: ?, ` +
+		`reset_points: ?, ` +
+		`operator_tags: ?, ` +
+		`generation: ?, ` +
+		`decision_task_list: ?, ` +
+		`depth: ?, ` +
+		`signal_count: ?, ` +
+		`last_signal_name: ?, ` +
+		`last_signal_time: ?, ` +
+		`close_time: ?` +
 		`}`
 
 	templateReplicationStateType = `{` +
@@ -168,7 +184,8 @@ const (
 		`target_child_workflow_only: ?, ` +
 		`task_list: ?, ` +
 		`type: ?, ` +
-		`schedule_id: ?` +
+		`schedule_id: ?, ` +
+		`priority: ?` +
 		`}`
 
 	templateReplicationTaskType = `{` +
@@ -212,7 +229,14 @@ const (
 		`cancel_requested: ?, ` +
 		`cancel_request_id: ?, ` +
 		`last_hb_updated_time: ?, ` +
-		`timer_task_status: ?` +
+		`timer_task_status: ?, ` +
+		`started_identity: ?, ` +
+		`accepted_cancel: ?, ` +
+		`priority: ?, ` +
+		`checkpoint_sequence_number: ?, ` +
+		`checkpoint_payload: ?, ` +
+		`dispatch_deadline: ?, ` +
+		`task_list: ?` +
 		`}`
 
 	templateTimerInfoType = `{` +
@@ -226,6 +250,7 @@ const (
 		`initiated_id: ?, ` +
 		`initiated_event: ?, ` +
 		`started_id: ?, ` +
+		`started_run_id: ?, ` +
 		`started_event: ?, ` +
 		`create_request_id: ?` +
 		`}`
@@ -354,6 +379,11 @@ const (
 		`and visibility_ts = ? ` +
 		`and task_id = ?`
 
+	templateListExecutionsQuery = `SELECT run_id, execution ` +
+		`FROM executions ` +
+		`WHERE shard_id = ? ` +
+		`and type = ?`
+
 	templateUpdateWorkflowExecutionQuery = `UPDATE executions ` +
 		`SET execution = ` + templateWorkflowExecutionType + `, next_event_id = ? ` +
 		`WHERE shard_id = ? ` +
@@ -431,6 +461,17 @@ const (
 		`and task_id = ? ` +
 		`IF next_event_id = ?`
 
+	// templateCreateSignalRequestedQuery is applied in the batch creating the execution, so it carries no condition
+	templateCreateSignalRequestedQuery = `UPDATE executions ` +
+		`SET signal_requested = ? ` +
+		`WHERE shard_id = ? ` +
+		`and type = ? ` +
+		`and domain_id = ? ` +
+		`and workflow_id = ? ` +
+		`and run_id = ? ` +
+		`and visibility_ts = ? ` +
+		`and task_id = ? `
+
 	templateUpdateSignalRequestedQuery = `UPDATE executions ` +
 		`SET signal_requested = signal_requested + ? ` +
 		`WHERE shard_id = ? ` +
@@ -532,6 +573,16 @@ const (
 		`and visibility_ts = ? ` +
 		`and task_id = ? `
 
+	templateDeleteCurrentWorkflowExecutionQuery = `DELETE FROM executions ` +
+		`WHERE shard_id = ? ` +
+		`and type = ? ` +
+		`and domain_id = ? ` +
+		`and workflow_id = ? ` +
+		`and run_id = ? ` +
+		`and visibility_ts = ? ` +
+		`and task_id = ? ` +
+		`IF current_run_id = ? `
+
 	templateDeleteWorkflowExecutionSignalRequestedQuery = `UPDATE executions ` +
 		`SET signal_requested = signal_requested - ? ` +
 		`WHERE shard_id = ? ` +
@@ -741,6 +792,9 @@ func (d *cassandraPersistence) CreateShard(request *CreateShardRequest) error {
 		shardInfo.TimerAckLevel,
 		shardInfo.ClusterTransferAckLevel,
 		shardInfo.ClusterTimerAckLevel,
+		shardInfo.OpenExecutionCount,
+		shardInfo.ClosedExecutionCount,
+		shardInfo.ExecutionsCountedAt,
 		shardInfo.RangeID)
 
 	previous := make(map[string]interface{})
@@ -815,6 +869,9 @@ func (d *cassandraPersistence) UpdateShard(request *UpdateShardRequest) error {
 		shardInfo.TimerAckLevel,
 		shardInfo.ClusterTransferAckLevel,
 		shardInfo.ClusterTimerAckLevel,
+		shardInfo.OpenExecutionCount,
+		shardInfo.ClosedExecutionCount,
+		shardInfo.ExecutionsCountedAt,
 		shardInfo.RangeID,
 		shardInfo.ShardID,
 		rowTypeShard,
@@ -986,6 +1043,14 @@ func (d *cassandraPersistence) CreateWorkflowExecutionWithinBatch(request *Creat
 		initiatedID = request.InitiatedID
 		state = WorkflowStateCreated
 	}
+	startTimestamp := cqlNowTimestamp
+	if !request.StartTimestamp.IsZero() {
+		startTimestamp = common.UnixNanoToCQLTimestamp(request.StartTimestamp.UnixNano())
+	}
+	lastFirstEventID := common.FirstEventID
+	if request.LastFirstEventID != 0 {
+		lastFirstEventID = request.LastFirstEventID
+	}
 
 	if request.ContinueAsNew {
 		batch.Query(templateUpdateCurrentWorkflowExecutionQuery,
@@ -1043,10 +1108,10 @@ func (d *cassandraPersistence) CreateWorkflowExecutionWithinBatch(request *Creat
 			request.ExecutionContext,
 			WorkflowStateCreated,
 			WorkflowCloseStatusNone,
-			common.FirstEventID,
+			lastFirstEventID,
 			request.NextEventID,
 			request.LastProcessedEvent,
-			cqlNowTimestamp,
+			startTimestamp,
 			cqlNowTimestamp,
 			request.RequestID,
 			request.DecisionScheduleID,
@@ -1057,11 +1122,24 @@ func (d *cassandraPersistence) CreateWorkflowExecutionWithinBatch(request *Creat
 			0,
 			false,
 			"",
-			"", // sticky_task_list (no sticky tasklist for new workflow execution)
-			0,  // sticky_schedule_to_start_timeout
-			"", // client_library_version
-			"", // client_feature_version
-			"", // client_impl
+			0,     // cancel_requested_event_id
+			"",    // sticky_task_list (no sticky tasklist for new workflow execution)
+			0,     // sticky_schedule_to_start_timeout
+			"",    // client_library_version
+			"",    // client_feature_version
+			"",    // client_impl
+			"",    // binary_checksum
+			false, // paused
+			request.KeyID,
+			nil, // reset_points
+			nil, // operator_tags
+			request.Generation,
+			request.DecisionTaskList,
+			request.Depth,
+			request.SignalCount,
+			request.LastSignalName,
+			request.LastSignalTimestamp,
+			time.Time{}, // close_time
 			request.NextEventID,
 			defaultVisibilityTimestamp,
 			rowTypeExecutionTaskID)
@@ -1092,10 +1170,10 @@ func (d *cassandraPersistence) CreateWorkflowExecutionWithinBatch(request *Creat
 			request.ExecutionContext,
 			WorkflowStateCreated,
 			WorkflowCloseStatusNone,
-			common.FirstEventID,
+			lastFirstEventID,
 			request.NextEventID,
 			request.LastProcessedEvent,
-			cqlNowTimestamp,
+			startTimestamp,
 			cqlNowTimestamp,
 			request.RequestID,
 			request.DecisionScheduleID,
@@ -1106,11 +1184,24 @@ func (d *cassandraPersistence) CreateWorkflowExecutionWithinBatch(request *Creat
 			0,
 			false,
 			"",
-			"", // sticky_task_list (no sticky tasklist for new workflow execution)
-			0,  // sticky_schedule_to_start_timeout
-			"", // client_library_version
-			"", // client_feature_version
-			"", // client_impl
+			0,     // cancel_requested_event_id
+			"",    // sticky_task_list (no sticky tasklist for new workflow execution)
+			0,     // sticky_schedule_to_start_timeout
+			"",    // client_library_version
+			"",    // client_feature_version
+			"",    // client_impl
+			"",    // binary_checksum
+			false, // paused
+			request.KeyID,
+			nil, // reset_points
+			nil, // operator_tags
+			request.Generation,
+			request.DecisionTaskList,
+			request.Depth,
+			request.SignalCount,
+			request.LastSignalName,
+			request.LastSignalTimestamp,
+			time.Time{}, // close_time
 			request.ReplicationState.CurrentVersion,
 			request.ReplicationState.StartVersion,
 			request.ReplicationState.LastWriteVersion,
@@ -1120,6 +1211,18 @@ func (d *cassandraPersistence) CreateWorkflowExecutionWithinBatch(request *Creat
 			defaultVisibilityTimestamp,
 			rowTypeExecutionTaskID)
 	}
+
+	if len(request.SignalRequestedIDs) > 0 {
+		batch.Query(templateCreateSignalRequestedQuery,
+			request.SignalRequestedIDs,
+			d.shardID,
+			rowTypeExecution,
+			request.DomainID,
+			*request.Execution.WorkflowId,
+			*request.Execution.RunId,
+			defaultVisibilityTimestamp,
+			rowTypeExecutionTaskID)
+	}
 }
 
 func (d *cassandraPersistence) GetWorkflowExecution(request *GetWorkflowExecutionRequest) (
@@ -1255,11 +1358,24 @@ func (d *cassandraPersistence) UpdateWorkflowExecution(request *UpdateWorkflowEx
 			executionInfo.DecisionTimestamp,
 			executionInfo.CancelRequested,
 			executionInfo.CancelRequestID,
+			executionInfo.CancelRequestedEventID,
 			executionInfo.StickyTaskList,
 			executionInfo.StickyScheduleToStartTimeout,
 			executionInfo.ClientLibraryVersion,
 			executionInfo.ClientFeatureVersion,
 			executionInfo.ClientImpl,
+			executionInfo.BinaryChecksum,
+			executionInfo.Paused,
+			executionInfo.KeyID,
+			createResetPointList(executionInfo.ResetPoints),
+			executionInfo.OperatorTags,
+			executionInfo.Generation,
+			executionInfo.DecisionTaskList,
+			executionInfo.Depth,
+			executionInfo.SignalCount,
+			executionInfo.LastSignalName,
+			executionInfo.LastSignalTimestamp,
+			executionInfo.CloseTimestamp,
 			executionInfo.NextEventID,
 			d.shardID,
 			rowTypeExecution,
@@ -1305,11 +1421,24 @@ func (d *cassandraPersistence) UpdateWorkflowExecution(request *UpdateWorkflowEx
 			executionInfo.DecisionTimestamp,
 			executionInfo.CancelRequested,
 			executionInfo.CancelRequestID,
+			executionInfo.CancelRequestedEventID,
 			executionInfo.StickyTaskList,
 			executionInfo.StickyScheduleToStartTimeout,
 			executionInfo.ClientLibraryVersion,
 			executionInfo.ClientFeatureVersion,
 			executionInfo.ClientImpl,
+			executionInfo.BinaryChecksum,
+			executionInfo.Paused,
+			executionInfo.KeyID,
+			createResetPointList(executionInfo.ResetPoints),
+			executionInfo.OperatorTags,
+			executionInfo.Generation,
+			executionInfo.DecisionTaskList,
+			executionInfo.Depth,
+			executionInfo.SignalCount,
+			executionInfo.LastSignalName,
+			executionInfo.LastSignalTimestamp,
+			executionInfo.CloseTimestamp,
 			replicationState.CurrentVersion,
 			replicationState.StartVersion,
 			replicationState.LastWriteVersion,
@@ -1501,6 +1630,77 @@ func (d *cassandraPersistence) DeleteWorkflowExecution(request *DeleteWorkflowEx
 	return nil
 }
 
+func (d *cassandraPersistence) DeleteCurrentWorkflowExecution(request *DeleteCurrentWorkflowExecutionRequest) error {
+	query := d.session.Query(templateDeleteCurrentWorkflowExecutionQuery,
+		d.shardID,
+		rowTypeExecution,
+		request.DomainID,
+		request.WorkflowID,
+		permanentRunID,
+		defaultVisibilityTimestamp,
+		rowTypeExecutionTaskID,
+		request.RunID)
+
+	// the delete is not applied when the current execution belongs to another run, which is left untouched
+	previous := make(map[string]interface{})
+	if _, err := query.MapScanCAS(previous); err != nil {
+		if isThrottlingError(err) {
+			return &workflow.ServiceBusyError{
+				Message: fmt.Sprintf("DeleteCurrentWorkflowExecution operation failed. Error: %v", err),
+			}
+		}
+		return &workflow.InternalServiceError{
+			Message: fmt.Sprintf("DeleteCurrentWorkflowExecution operation failed. Error: %v", err),
+		}
+	}
+
+	return nil
+}
+
+func (d *cassandraPersistence) UpdateCurrentWorkflowExecution(request *UpdateCurrentWorkflowExecutionRequest) error {
+	query := d.session.Query(templateUpdateCurrentWorkflowExecutionQuery,
+		request.RunID,
+		request.RunID,
+		request.StartRequestID,
+		request.State,
+		request.CloseStatus,
+		d.shardID,
+		rowTypeExecution,
+		request.DomainID,
+		request.WorkflowID,
+		permanentRunID,
+		defaultVisibilityTimestamp,
+		rowTypeExecutionTaskID,
+		request.PreviousRunID)
+
+	previous := make(map[string]interface{})
+	applied, err := query.MapScanCAS(previous)
+	if err != nil {
+		if isThrottlingError(err) {
+			return &workflow.ServiceBusyError{
+				Message: fmt.Sprintf("UpdateCurrentWorkflowExecution operation failed. Error: %v", err),
+			}
+		}
+		return &workflow.InternalServiceError{
+			Message: fmt.Sprintf("UpdateCurrentWorkflowExecution operation failed. Error: %v", err),
+		}
+	}
+
+	if !applied {
+		var columns []string
+		for k, v := range previous {
+			columns = append(columns, fmt.Sprintf("%s=%v", k, v))
+		}
+
+		return &ConditionFailedError{
+			Msg: fmt.Sprintf("Failed to update current execution. WorkflowId: %v, PreviousRunId: %v, columns: (%v)",
+				request.WorkflowID, request.PreviousRunID, strings.Join(columns, ",")),
+		}
+	}
+
+	return nil
+}
+
 func (d *cassandraPersistence) GetCurrentExecution(request *GetCurrentExecutionRequest) (*GetCurrentExecutionResponse,
 	error) {
 	query := d.session.Query(templateGetCurrentExecutionQuery,
@@ -1540,6 +1740,62 @@ func (d *cassandraPersistence) GetCurrentExecution(request *GetCurrentExecutionR
 	}, nil
 }
 
+// ListOpenExecutions pages through the execution rows of the shard.  Only the execution info of a row is read, and
+// current execution rows as well as closed executions are left out of the page.
+func (d *cassandraPersistence) ListOpenExecutions(request *ListOpenExecutionsRequest) (*ListOpenExecutionsResponse,
+	error) {
+	query := d.session.Query(templateListExecutionsQuery,
+		d.shardID,
+		rowTypeExecution)
+
+	iter := query.PageSize(request.PageSize).PageState(request.NextPageToken).Iter()
+	if iter == nil {
+		return nil, &workflow.InternalServiceError{
+			Message: "ListOpenExecutions operation failed.  Not able to create query iterator.",
+		}
+	}
+
+	response := &ListOpenExecutionsResponse{}
+	result := make(map[string]interface{})
+	for iter.MapScan(result) {
+		runID := result["run_id"].(gocql.UUID).String()
+		executionInfo := createWorkflowExecutionInfo(result["execution"].(map[string]interface{}))
+		// Reset result map to get it ready for next scan
+		result = make(map[string]interface{})
+
+		if runID == permanentRunID {
+			continue
+		}
+		if executionInfo.State == WorkflowStateCompleted {
+			response.ClosedCount++
+			continue
+		}
+		response.Executions = append(response.Executions, &OpenExecution{
+			DomainID:   executionInfo.DomainID,
+			WorkflowID: executionInfo.WorkflowID,
+			RunID:      runID,
+			StartTime:  executionInfo.StartTimestamp,
+		})
+	}
+
+	nextPageToken := iter.PageState()
+	response.NextPageToken = make([]byte, len(nextPageToken))
+	copy(response.NextPageToken, nextPageToken)
+	if err := iter.Close(); err != nil {
+		if isThrottlingError(err) {
+			return nil, &workflow.ServiceBusyError{
+				Message: fmt.Sprintf("ListOpenExecutions operation failed. Error: %v", err),
+			}
+		}
+
+		return nil, &workflow.InternalServiceError{
+			Message: fmt.Sprintf("ListOpenExecutions operation failed. Error: %v", err),
+		}
+	}
+
+	return response, nil
+}
+
 func (d *cassandraPersistence) GetTransferTasks(request *GetTransferTasksRequest) (*GetTransferTasksResponse, error) {
 
 	// Reading transfer tasks need to be quorum level consistent, otherwise we could loose task
@@ -2058,6 +2314,7 @@ func (d *cassandraPersistence) createTransferTasks(batch *gocql.Batch, transferT
 	for _, task := range transferTasks {
 		var taskList string
 		var scheduleID int64
+		var priority int32
 		targetWorkflowID := transferTaskTransferTargetWorkflowID
 		targetRunID := transferTaskTypeTransferTargetRunID
 		targetChildWorkflowOnly := false
@@ -2067,6 +2324,7 @@ func (d *cassandraPersistence) createTransferTasks(batch *gocql.Batch, transferT
 			targetDomainID = task.(*ActivityTask).DomainID
 			taskList = task.(*ActivityTask).TaskList
 			scheduleID = task.(*ActivityTask).ScheduleID
+			priority = task.(*ActivityTask).Priority
 
 		case TransferTaskTypeDecisionTask:
 			targetDomainID = task.(*DecisionTask).DomainID
@@ -2122,6 +2380,7 @@ func (d *cassandraPersistence) createTransferTasks(batch *gocql.Batch, transferT
 			taskList,
 			task.GetType(),
 			scheduleID,
+			priority,
 			defaultVisibilityTimestamp,
 			task.GetTaskID())
 	}
@@ -2185,6 +2444,9 @@ func (d *cassandraPersistence) createTimerTasks(batch *gocql.Batch, timerTasks [
 			eventID = t.EventID
 			timeoutType = t.TimeoutType
 			attempt = t.ScheduleAttempt
+		case *DecisionBackoffTask:
+			eventID = t.EventID
+			attempt = t.ScheduleAttempt
 		case *ActivityTimeoutTask:
 			eventID = t.EventID
 			timeoutType = t.TimeoutType
@@ -2249,6 +2511,13 @@ func (d *cassandraPersistence) updateActivityInfos(batch *gocql.Batch, activityI
 			a.CancelRequestID,
 			a.LastHeartBeatUpdatedTime,
 			a.TimerTaskStatus,
+			a.StartedIdentity,
+			a.AcceptedCancel,
+			a.Priority,
+			a.CheckpointSequenceNumber,
+			a.CheckpointPayload,
+			a.DispatchDeadline,
+			a.TaskList,
 			d.shardID,
 			rowTypeExecution,
 			domainID,
@@ -2316,6 +2585,7 @@ func (d *cassandraPersistence) updateChildExecutionInfos(batch *gocql.Batch, chi
 			c.InitiatedID,
 			c.InitiatedEvent,
 			c.StartedID,
+			c.StartedRunID,
 			c.StartedEvent,
 			c.CreateRequestID,
 			d.shardID,
@@ -2499,6 +2769,12 @@ func createShardInfo(currentCluster string, result map[string]interface{}) *Shar
 			info.ClusterTransferAckLevel = v.(map[string]int64)
 		case "cluster_timer_ack_level":
 			info.ClusterTimerAckLevel = v.(map[string]time.Time)
+		case "open_execution_count":
+			info.OpenExecutionCount = v.(int64)
+		case "closed_execution_count":
+			info.ClosedExecutionCount = v.(int64)
+		case "executions_counted_at":
+			info.ExecutionsCountedAt = v.(time.Time)
 		}
 	}
 
@@ -2578,6 +2854,8 @@ func createWorkflowExecutionInfo(result map[string]interface{}) *WorkflowExecuti
 			info.CancelRequested = v.(bool)
 		case "cancel_request_id":
 			info.CancelRequestID = v.(string)
+		case "cancel_requested_event_id":
+			info.CancelRequestedEventID = v.(int64)
 		case "sticky_task_list":
 			info.StickyTaskList = v.(string)
 		case "sticky_schedule_to_start_timeout":
@@ -2588,6 +2866,32 @@ func createWorkflowExecutionInfo(result map[string]interface{}) *WorkflowExecuti
 			info.ClientFeatureVersion = v.(string)
 		case "client_impl":
 			info.ClientImpl = v.(string)
+		case "binary_checksum":
+			info.BinaryChecksum = v.(string)
+		case "paused":
+			info.Paused = v.(bool)
+		case "key_id":
+			info.KeyID = v.(string)
+		case "reset_points":
+			for _, point := range v.([]map[string]interface{}) {
+				info.ResetPoints = append(info.ResetPoints, createResetPoint(point))
+			}
+		case "operator_tags":
+			info.OperatorTags = v.(map[string]string)
+		case "generation":
+			info.Generation = v.(int64)
+		case "decision_task_list":
+			info.DecisionTaskList = v.(string)
+		case "depth":
+			info.Depth = int32(v.(int))
+		case "signal_count":
+			info.SignalCount = v.(int64)
+		case "last_signal_name":
+			info.LastSignalName = v.(string)
+		case "last_signal_time":
+			info.LastSignalTimestamp = v.(time.Time)
+		case "close_time":
+			info.CloseTimestamp = v.(time.Time)
 		}
 	}
 
@@ -2651,6 +2955,8 @@ func createTransferTaskInfo(result map[string]interface{}) *TransferTaskInfo {
 			info.TaskType = v.(int)
 		case "schedule_id":
 			info.ScheduleID = v.(int64)
+		case "priority":
+			info.Priority = int32(v.(int))
 		}
 	}
 
@@ -2727,6 +3033,20 @@ func createActivityInfo(result map[string]interface{}) *ActivityInfo {
 			info.LastHeartBeatUpdatedTime = v.(time.Time)
 		case "timer_task_status":
 			info.TimerTaskStatus = int32(v.(int))
+		case "started_identity":
+			info.StartedIdentity = v.(string)
+		case "accepted_cancel":
+			info.AcceptedCancel = v.(bool)
+		case "priority":
+			info.Priority = int32(v.(int))
+		case "checkpoint_sequence_number":
+			info.CheckpointSequenceNumber = v.(int64)
+		case "checkpoint_payload":
+			info.CheckpointPayload = v.([]byte)
+		case "dispatch_deadline":
+			info.DispatchDeadline = int32(v.(int))
+		case "task_list":
+			info.TaskList = v.(string)
 		}
 	}
 
@@ -2760,6 +3080,8 @@ func createChildExecutionInfo(result map[string]interface{}) *ChildExecutionInfo
 			info.InitiatedEvent = v.([]byte)
 		case "started_id":
 			info.StartedID = v.(int64)
+		case "started_run_id":
+			info.StartedRunID = v.(string)
 		case "started_event":
 			info.StartedEvent = v.([]byte)
 		case "create_request_id":
@@ -2887,6 +3209,41 @@ func createReplicationInfoMap(info *ReplicationInfo) map[string]interface{} {
 	return rInfoMap
 }
 
+func createResetPoint(result map[string]interface{}) *ResetPoint {
+	point := &ResetPoint{}
+	for k, v := range result {
+		switch k {
+		case "event_id":
+			point.EventID = v.(int64)
+		case "binary_checksum":
+			point.BinaryChecksum = v.(string)
+		case "resettable":
+			point.Resettable = v.(bool)
+		case "created_time":
+			point.CreatedTime = v.(time.Time)
+		case "safe":
+			point.Safe = v.(bool)
+		}
+	}
+
+	return point
+}
+
+func createResetPointList(points []*ResetPoint) []map[string]interface{} {
+	pointList := make([]map[string]interface{}, 0, len(points))
+	for _, point := range points {
+		pointList = append(pointList, map[string]interface{}{
+			"event_id":        point.EventID,
+			"binary_checksum": point.BinaryChecksum,
+			"resettable":      point.Resettable,
+			"created_time":    point.CreatedTime,
+			"safe":            point.Safe,
+		})
+	}
+
+	return pointList
+}
+
 func isTimeoutError(err error) bool {
 	if err == gocql.ErrTimeoutNoResponse {
 		return true
@@ -2923,6 +3280,9 @@ func GetVisibilityTSFrom(task Task) time.Time {
 
 	case TaskTypeDeleteHistoryEvent:
 		return task.(*DeleteHistoryEventTask).VisibilityTimestamp
+
+	case TaskTypeDecisionBackoff:
+		return task.(*DecisionBackoffTask).VisibilityTimestamp
 	}
 	return time.Time{}
 }
@@ -2944,5 +3304,8 @@ func SetVisibilityTSFrom(task Task, t time.Time) {
 
 	case TaskTypeDeleteHistoryEvent:
 		task.(*DeleteHistoryEventTask).VisibilityTimestamp = t
+
+	case TaskTypeDecisionBackoff:
+		task.(*DecisionBackoffTask).VisibilityTimestamp = t
 	}
 }
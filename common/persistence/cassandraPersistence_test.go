@@ -192,6 +192,9 @@ func (s *cassandraPersistenceSuite) TestUpdateWorkflow() {
 	s.Empty(info0.ClientLibraryVersion)
 	s.Empty(info0.ClientFeatureVersion)
 	s.Empty(info0.ClientImpl)
+	s.Equal(int32(0), info0.Depth)
+	s.Equal(int64(0), info0.SignalCount)
+	s.Empty(info0.LastSignalName)
 
 	log.Infof("Workflow execution last updated: %v", info0.LastUpdatedTimestamp)
 
@@ -206,6 +209,26 @@ func (s *cassandraPersistenceSuite) TestUpdateWorkflow() {
 	updatedInfo.ClientLibraryVersion = "random client library version"
 	updatedInfo.ClientFeatureVersion = "random client feature version"
 	updatedInfo.ClientImpl = "random client impl"
+	updatedInfo.BinaryChecksum = "random binary checksum"
+	updatedInfo.Paused = true
+	updatedInfo.KeyID = "random key id"
+	updatedInfo.ResetPoints = []*ResetPoint{{
+		EventID:        4,
+		BinaryChecksum: "random binary checksum",
+		Resettable:     true,
+		CreatedTime:    time.Unix(0, 1000000),
+		Safe:           true,
+	}}
+	updatedInfo.OperatorTags = map[string]string{"investigating": "oncall"}
+	updatedInfo.Generation = 2
+	updatedInfo.DecisionTaskList = "canary"
+	updatedInfo.Depth = 3
+	updatedInfo.SignalCount = 7
+	updatedInfo.LastSignalName = "random signal name"
+	updatedInfo.LastSignalTimestamp = time.Now()
+	updatedInfo.CancelRequested = true
+	updatedInfo.CancelRequestID = "random cancel request id"
+	updatedInfo.CancelRequestedEventID = 4
 	err2 := s.UpdateWorkflowExecution(updatedInfo, []int64{int64(4)}, nil, int64(3), nil, nil, nil, nil, nil, nil)
 	s.Nil(err2, "No error expected.")
 
@@ -236,6 +259,25 @@ func (s *cassandraPersistenceSuite) TestUpdateWorkflow() {
 	s.Equal(updatedInfo.ClientLibraryVersion, info1.ClientLibraryVersion)
 	s.Equal(updatedInfo.ClientFeatureVersion, info1.ClientFeatureVersion)
 	s.Equal(updatedInfo.ClientImpl, info1.ClientImpl)
+	s.Equal(updatedInfo.BinaryChecksum, info1.BinaryChecksum)
+	s.Equal(updatedInfo.Paused, info1.Paused)
+	s.Equal(updatedInfo.KeyID, info1.KeyID)
+	s.Equal(1, len(info1.ResetPoints))
+	s.Equal(updatedInfo.ResetPoints[0].EventID, info1.ResetPoints[0].EventID)
+	s.Equal(updatedInfo.ResetPoints[0].BinaryChecksum, info1.ResetPoints[0].BinaryChecksum)
+	s.Equal(updatedInfo.ResetPoints[0].Resettable, info1.ResetPoints[0].Resettable)
+	s.Equal(updatedInfo.ResetPoints[0].CreatedTime.UnixNano(), info1.ResetPoints[0].CreatedTime.UnixNano())
+	s.Equal(updatedInfo.ResetPoints[0].Safe, info1.ResetPoints[0].Safe)
+	s.Equal(updatedInfo.OperatorTags, info1.OperatorTags)
+	s.Equal(updatedInfo.Generation, info1.Generation)
+	s.Equal(updatedInfo.DecisionTaskList, info1.DecisionTaskList)
+	s.Equal(updatedInfo.Depth, info1.Depth)
+	s.Equal(updatedInfo.SignalCount, info1.SignalCount)
+	s.Equal(updatedInfo.LastSignalName, info1.LastSignalName)
+	s.Equal(updatedInfo.LastSignalTimestamp.Unix(), info1.LastSignalTimestamp.Unix())
+	s.True(info1.CancelRequested)
+	s.Equal(updatedInfo.CancelRequestID, info1.CancelRequestID)
+	s.Equal(updatedInfo.CancelRequestedEventID, info1.CancelRequestedEventID)
 
 	log.Infof("Workflow execution last updated: %v", info1.LastUpdatedTimestamp)
 
@@ -389,6 +431,140 @@ func (s *cassandraPersistenceSuite) TestGetCurrentWorkflow() {
 	s.Empty(task1, "Expected empty task identifier.")
 }
 
+func (s *cassandraPersistenceSuite) TestListOpenExecutions() {
+	domainID := "1f8bd4c2-4b3f-4bfe-9b4f-6d1b8a3f5c10"
+	openExecution := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("list-open-executions-test-open"),
+		RunId:      common.StringPtr("4e0917f2-6a70-4d7c-b8f4-1c3e9b3e2a51"),
+	}
+	closedExecution := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("list-open-executions-test-closed"),
+		RunId:      common.StringPtr("9a6f3e27-5b1d-4c0e-a2f8-7d4b6c8e1f93"),
+	}
+
+	_, err0 := s.CreateWorkflowExecution(domainID, openExecution, "queue1", "wType", 20, 13, nil, 3, 0, 2, nil)
+	s.Nil(err0, "No error expected.")
+	_, err1 := s.CreateWorkflowExecution(domainID, closedExecution, "queue1", "wType", 20, 13, nil, 3, 0, 2, nil)
+	s.Nil(err1, "No error expected.")
+
+	info, err2 := s.GetWorkflowExecutionInfo(domainID, closedExecution)
+	s.Nil(err2)
+	closedInfo := copyWorkflowExecutionInfo(info.ExecutionInfo)
+	closedInfo.NextEventID = int64(6)
+	closedInfo.LastProcessedEvent = int64(2)
+	closedInfo.State = WorkflowStateCompleted
+	closedInfo.CloseStatus = WorkflowCloseStatusCompleted
+	err3 := s.UpdateWorkflowExecutionAndFinish(closedInfo, int64(3))
+	s.Nil(err3, "No error expected.")
+
+	executions := make(map[string]*OpenExecution)
+	closedCount := 0
+	var nextPageToken []byte
+	for {
+		response, err := s.WorkflowMgr.ListOpenExecutions(&ListOpenExecutionsRequest{
+			PageSize:      2,
+			NextPageToken: nextPageToken,
+		})
+		s.Nil(err, "No error expected.")
+		for _, execution := range response.Executions {
+			executions[execution.RunID] = execution
+		}
+		closedCount += response.ClosedCount
+		nextPageToken = response.NextPageToken
+		if len(nextPageToken) == 0 {
+			break
+		}
+	}
+
+	s.NotContains(executions, permanentRunID)
+	s.NotContains(executions, closedExecution.GetRunId())
+	s.True(closedCount >= 1)
+	s.Contains(executions, openExecution.GetRunId())
+	execution := executions[openExecution.GetRunId()]
+	s.Equal(domainID, execution.DomainID)
+	s.Equal(openExecution.GetWorkflowId(), execution.WorkflowID)
+	s.False(execution.StartTime.IsZero())
+}
+
+func (s *cassandraPersistenceSuite) TestDeleteCurrentWorkflow() {
+	domainID := "4ad5c1b4-04c1-4a0f-9b4c-1e0e1e8f2b6a"
+	workflowExecution := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("delete-current-workflow-test"),
+		RunId:      common.StringPtr("0d6b8a2e-0a7c-4f65-b1a4-1f1c4d2b8e01"),
+	}
+
+	task0, err0 := s.CreateWorkflowExecution(domainID, workflowExecution, "queue1", "wType", 20, 13, nil, 3, 0, 2, nil)
+	s.Nil(err0, "No error expected.")
+	s.NotEmpty(task0, "Expected non empty task identifier.")
+
+	// the current execution is left alone when it belongs to another run
+	err1 := s.WorkflowMgr.DeleteCurrentWorkflowExecution(&DeleteCurrentWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowExecution.GetWorkflowId(),
+		RunID:      "4b1a7c39-5e2d-4d0a-9c1e-6f8a2b3c4d5e",
+	})
+	s.Nil(err1)
+	runID, err2 := s.GetCurrentWorkflowRunID(domainID, workflowExecution.GetWorkflowId())
+	s.Nil(err2)
+	s.Equal(workflowExecution.GetRunId(), runID)
+
+	err3 := s.WorkflowMgr.DeleteCurrentWorkflowExecution(&DeleteCurrentWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowExecution.GetWorkflowId(),
+		RunID:      workflowExecution.GetRunId(),
+	})
+	s.Nil(err3)
+	_, err4 := s.GetCurrentWorkflowRunID(domainID, workflowExecution.GetWorkflowId())
+	s.IsType(&gen.EntityNotExistsError{}, err4)
+
+	// the execution itself is not touched
+	info, err5 := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+	s.Nil(err5)
+	s.Equal(workflowExecution.GetRunId(), info.ExecutionInfo.RunID)
+}
+
+func (s *cassandraPersistenceSuite) TestUpdateCurrentWorkflow() {
+	domainID := "8f3c2a1e-5b7d-4e6f-a9c0-2d4b6e8f1a3c"
+	workflowExecution := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("update-current-workflow-test"),
+		RunId:      common.StringPtr("6a2e9c4b-1d3f-4a5b-8c7d-9e0f1a2b3c4d"),
+	}
+	newRunID := "2c5e7a9b-3d4f-4b6a-9c8e-0f1a2b3c4d5e"
+
+	task0, err0 := s.CreateWorkflowExecution(domainID, workflowExecution, "queue1", "wType", 20, 13, nil, 3, 0, 2, nil)
+	s.Nil(err0, "No error expected.")
+	s.NotEmpty(task0, "Expected non empty task identifier.")
+
+	// the current execution is left alone when it belongs to another run
+	err1 := s.WorkflowMgr.UpdateCurrentWorkflowExecution(&UpdateCurrentWorkflowExecutionRequest{
+		DomainID:       domainID,
+		WorkflowID:     workflowExecution.GetWorkflowId(),
+		RunID:          newRunID,
+		PreviousRunID:  "4b1a7c39-5e2d-4d0a-9c1e-6f8a2b3c4d5e",
+		StartRequestID: uuid.New(),
+		State:          WorkflowStateRunning,
+		CloseStatus:    WorkflowCloseStatusNone,
+	})
+	s.IsType(&ConditionFailedError{}, err1)
+	runID, err2 := s.GetCurrentWorkflowRunID(domainID, workflowExecution.GetWorkflowId())
+	s.Nil(err2)
+	s.Equal(workflowExecution.GetRunId(), runID)
+
+	err3 := s.WorkflowMgr.UpdateCurrentWorkflowExecution(&UpdateCurrentWorkflowExecutionRequest{
+		DomainID:       domainID,
+		WorkflowID:     workflowExecution.GetWorkflowId(),
+		RunID:          newRunID,
+		PreviousRunID:  workflowExecution.GetRunId(),
+		StartRequestID: uuid.New(),
+		State:          WorkflowStateRunning,
+		CloseStatus:    WorkflowCloseStatusNone,
+	})
+	s.Nil(err3)
+	runID, err4 := s.GetCurrentWorkflowRunID(domainID, workflowExecution.GetWorkflowId())
+	s.Nil(err4)
+	s.Equal(newRunID, runID)
+}
+
 func (s *cassandraPersistenceSuite) TestTransferTasks() {
 	domainID := "1eda632b-dde5-4cb2-94fd-5a6f04e6dfcd"
 	workflowExecution := gen.WorkflowExecution{
@@ -933,6 +1109,9 @@ func (s *cassandraPersistenceSuite) TestWorkflowMutableState_Activities() {
 			HeartbeatTimeout:         4,
 			LastHeartBeatUpdatedTime: currentTime,
 			TimerTaskStatus:          1,
+			StartedIdentity:          "worker_identity_1",
+			AcceptedCancel:           true,
+			Priority:                 5,
 		}}
 	err2 := s.UpdateWorkflowExecution(updatedInfo, []int64{int64(4)}, nil, int64(3), nil, nil, activityInfos, nil, nil, nil)
 	s.Nil(err2, "No error expected.")
@@ -956,6 +1135,9 @@ func (s *cassandraPersistenceSuite) TestWorkflowMutableState_Activities() {
 	s.Equal(int32(4), ai.HeartbeatTimeout)
 	s.Equal(currentTime.Unix(), ai.LastHeartBeatUpdatedTime.Unix())
 	s.Equal(int32(1), ai.TimerTaskStatus)
+	s.Equal("worker_identity_1", ai.StartedIdentity)
+	s.True(ai.AcceptedCancel)
+	s.Equal(int32(5), ai.Priority)
 
 	err2 = s.UpdateWorkflowExecution(updatedInfo, nil, nil, int64(5), nil, nil, nil, []int64{1}, nil, nil)
 	s.Nil(err2, "No error expected.")
@@ -1044,6 +1226,7 @@ func (s *cassandraPersistenceSuite) TestWorkflowMutableState_ChildExecutions() {
 			InitiatedID:     1,
 			InitiatedEvent:  []byte("initiated_event_1"),
 			StartedID:       2,
+			StartedRunID:    "started_run_id_1",
 			StartedEvent:    []byte("started_event_1"),
 			CreateRequestID: createRequestID,
 		}}
@@ -1060,6 +1243,7 @@ func (s *cassandraPersistenceSuite) TestWorkflowMutableState_ChildExecutions() {
 	s.Equal(int64(1), ci.InitiatedID)
 	s.Equal([]byte("initiated_event_1"), ci.InitiatedEvent)
 	s.Equal(int64(2), ci.StartedID)
+	s.Equal("started_run_id_1", ci.StartedRunID)
 	s.Equal([]byte("started_event_1"), ci.StartedEvent)
 	s.Equal(createRequestID, ci.CreateRequestID)
 
@@ -1217,6 +1401,41 @@ func (s *cassandraPersistenceSuite) TestWorkflowMutableState_SignalRequested() {
 	s.Equal(0, len(state.SignalRequestedIDs))
 }
 
+func (s *cassandraPersistenceSuite) TestWorkflowMutableState_SignalRequestedOnCreate() {
+	domainID := uuid.New()
+	workflowExecution := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("test-workflow-mutable-signal-requested-on-create-test"),
+		RunId:      common.StringPtr(uuid.New()),
+	}
+
+	signalRequestedIDs := []string{uuid.New(), uuid.New()}
+	_, err0 := s.WorkflowMgr.CreateWorkflowExecution(&CreateWorkflowExecutionRequest{
+		RequestID:                   uuid.New(),
+		DomainID:                    domainID,
+		Execution:                   workflowExecution,
+		TaskList:                    "taskList",
+		WorkflowTypeName:            "wType",
+		WorkflowTimeout:             20,
+		DecisionTimeoutValue:        13,
+		NextEventID:                 3,
+		LastProcessedEvent:          0,
+		RangeID:                     s.ShardInfo.RangeID,
+		DecisionScheduleID:          2,
+		DecisionStartedID:           common.EmptyEventID,
+		DecisionStartToCloseTimeout: 1,
+		SignalRequestedIDs:          signalRequestedIDs,
+	})
+	s.Nil(err0, "No error expected.")
+
+	state, err1 := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+	s.Nil(err1, "No error expected.")
+	s.Equal(2, len(state.SignalRequestedIDs))
+	for _, signalRequestedID := range signalRequestedIDs {
+		_, ok := state.SignalRequestedIDs[signalRequestedID]
+		s.True(ok)
+	}
+}
+
 func (s *cassandraPersistenceSuite) TestWorkflowMutableStateInfo() {
 	domainID := "9ed8818b-3090-4160-9f21-c6b70e64d2dd"
 	workflowExecution := gen.WorkflowExecution{
@@ -1680,14 +1899,16 @@ func (s *cassandraPersistenceSuite) TestCreateGetUpdateGetShard() {
 	currentClusterTimerAck = timestampConvertor(time.Now().Add(-100 * time.Second))
 	alternativeClusterTimerAck = timestampConvertor(time.Now().Add(-200 * time.Second))
 	shardInfo = &ShardInfo{
-		ShardID:             shardID,
-		Owner:               "some random owner",
-		RangeID:             int64(28),
-		StolenSinceRenew:    4,
-		UpdatedAt:           timestampConvertor(time.Now()),
-		ReplicationAckLevel: currentReplicationAck,
-		TransferAckLevel:    currentClusterTransferAck,
-		TimerAckLevel:       currentClusterTimerAck,
+		ShardID:              shardID,
+		Owner:                "some random owner",
+		RangeID:              int64(28),
+		StolenSinceRenew:     4,
+		UpdatedAt:            timestampConvertor(time.Now()),
+		ReplicationAckLevel:  currentReplicationAck,
+		TransferAckLevel:     currentClusterTransferAck,
+		TimerAckLevel:        currentClusterTimerAck,
+		OpenExecutionCount:   5,
+		ClosedExecutionCount: 3,
 		ClusterTransferAckLevel: map[string]int64{
 			cluster.TestCurrentClusterName:     currentClusterTransferAck,
 			cluster.TestAlternativeClusterName: alternativeClusterTransferAck,
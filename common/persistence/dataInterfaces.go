@@ -88,6 +88,7 @@ const (
 	TaskTypeUserTimer
 	TaskTypeWorkflowTimeout
 	TaskTypeDeleteHistoryEvent
+	TaskTypeDecisionBackoff
 )
 
 type (
@@ -133,6 +134,9 @@ type (
 		TimerAckLevel           time.Time // TO BE DEPRECATED IN FAVOR OF ClusteerTimerAckLevel
 		ClusterTransferAckLevel map[string]int64
 		ClusterTimerAckLevel    map[string]time.Time
+		OpenExecutionCount      int64
+		ClosedExecutionCount    int64 // closed executions not yet deleted after retention
+		ExecutionsCountedAt     time.Time
 	}
 
 	// WorkflowExecutionInfo describes a workflow execution
@@ -166,11 +170,29 @@ type (
 		DecisionTimestamp            int64
 		CancelRequested              bool
 		CancelRequestID              string
+		CancelRequestedEventID       int64
 		StickyTaskList               string
 		StickyScheduleToStartTimeout int32
 		ClientLibraryVersion         string
 		ClientFeatureVersion         string
 		ClientImpl                   string
+		BinaryChecksum               string
+		Paused                       bool
+		KeyID                        string
+		Depth                        int32
+		SignalCount                  int64
+		LastSignalName               string
+		LastSignalTimestamp          time.Time
+		CloseTimestamp               time.Time // time of the close event, zero while the execution is running
+		// ResetPoints are the latest completed decisions the workflow execution can be reset to, oldest first
+		ResetPoints []*ResetPoint
+		// OperatorTags are advisory annotations operators attach to the workflow execution
+		OperatorTags map[string]string
+		// Generation is the number of continue-as-new runs before this one in the chain, 0 for a fresh start
+		Generation int64
+		// DecisionTaskList is the task list the decisions of the run are dispatched to, empty until its first decision
+		// is scheduled
+		DecisionTaskList string
 	}
 
 	// ReplicationState represents mutable state information for global domains.
@@ -196,6 +218,7 @@ type (
 		TaskList                string
 		TaskType                int
 		ScheduleID              int64
+		Priority                int32
 	}
 
 	// ReplicationTaskInfo describes the replication task created for replication of history events
@@ -258,6 +281,7 @@ type (
 		DomainID   string
 		TaskList   string
 		ScheduleID int64
+		Priority   int32
 	}
 
 	// DecisionTask identifies a transfer task for decision
@@ -288,6 +312,14 @@ type (
 		TimeoutType         int
 	}
 
+	// DecisionBackoffTask identifies a timer task which dispatches a decision scheduled after a failed attempt.
+	DecisionBackoffTask struct {
+		VisibilityTimestamp time.Time
+		TaskID              int64
+		EventID             int64
+		ScheduleAttempt     int64
+	}
+
 	// WorkflowTimeoutTask identifies a timeout task.
 	WorkflowTimeoutTask struct {
 		VisibilityTimestamp time.Time
@@ -352,6 +384,17 @@ type (
 		LastEventID int64
 	}
 
+	// ResetPoint is a completed decision a workflow execution can be reset to
+	ResetPoint struct {
+		EventID        int64
+		BinaryChecksum string
+		// Resettable is false when there were buffered events the decision did not see
+		Resettable  bool
+		CreatedTime time.Time
+		// Safe is false as well when an activity had been started, resetting there would lose it
+		Safe bool
+	}
+
 	// WorkflowMutableState indicates workflow related state
 	WorkflowMutableState struct {
 		ActivitInfos        map[int64]*ActivityInfo
@@ -384,6 +427,19 @@ type (
 		CancelRequestID          int64
 		LastHeartBeatUpdatedTime time.Time
 		TimerTaskStatus          int32
+		StartedIdentity          string
+		AcceptedCancel           bool
+		Priority                 int32
+		// CheckpointSequenceNumber is the sequence number of the last progress checkpoint reported by heartbeat,
+		// zero when the activity has not reported any checkpoint yet
+		CheckpointSequenceNumber int64
+		CheckpointPayload        []byte
+		// DispatchDeadline is the number of seconds after ScheduledTime the activity may still be dispatched,
+		// zero when the activity has no dispatch deadline
+		DispatchDeadline int32
+		// TaskList is the physical task list the activity is dispatched to, empty for activities scheduled before
+		// it was recorded, which are dispatched to the task list of their scheduled event
+		TaskList string
 	}
 
 	// TimerInfo details - metadata about user timer info.
@@ -399,6 +455,7 @@ type (
 		InitiatedID     int64
 		InitiatedEvent  []byte
 		StartedID       int64
+		StartedRunID    string
 		StartedEvent    []byte
 		CreateRequestID string
 	}
@@ -464,6 +521,23 @@ type (
 		ContinueAsNew               bool
 		PreviousRunID               string
 		ReplicationState            *ReplicationState
+		Depth                       int32
+		SignalCount                 int64
+		LastSignalName              string
+		LastSignalTimestamp         time.Time
+		// StartTimestamp is the start time of the new execution, the time of the write is used when it is not set
+		StartTimestamp time.Time
+		// SignalRequestedIDs are the request ids of signals recorded by the new execution, for deduplication
+		SignalRequestedIDs []string
+		// KeyID is the id of the encryption key the history of the new execution is encrypted at rest with
+		KeyID string
+		// Generation is the continue-as-new generation of the new execution
+		Generation int64
+		// DecisionTaskList is the task list the first decision of the new execution was scheduled on
+		DecisionTaskList string
+		// LastFirstEventID is the id of the first event of the batch appended to the history of the new execution,
+		// the first event id of a history is recorded when it is not set
+		LastFirstEventID int64
 	}
 
 	// CreateWorkflowExecutionResponse is the response to CreateWorkflowExecutionRequest
@@ -496,6 +570,29 @@ type (
 		CloseStatus    int
 	}
 
+	// ListOpenExecutionsRequest is used to page through the open workflow executions of a shard
+	ListOpenExecutionsRequest struct {
+		PageSize      int
+		NextPageToken []byte
+	}
+
+	// ListOpenExecutionsResponse is the response to ListOpenExecutions.  A page can hold fewer than PageSize
+	// executions, listing is done once NextPageToken is empty.  ClosedCount is the number of closed executions left
+	// out of the page.
+	ListOpenExecutionsResponse struct {
+		Executions    []*OpenExecution
+		ClosedCount   int
+		NextPageToken []byte
+	}
+
+	// OpenExecution identifies an open workflow execution of a shard
+	OpenExecution struct {
+		DomainID   string
+		WorkflowID string
+		RunID      string
+		StartTime  time.Time
+	}
+
 	// UpdateWorkflowExecutionRequest is used to update a workflow execution
 	UpdateWorkflowExecutionRequest struct {
 		ExecutionInfo        *WorkflowExecutionInfo
@@ -534,6 +631,26 @@ type (
 		RunID      string
 	}
 
+	// DeleteCurrentWorkflowExecutionRequest is used to delete the current execution record of a workflow, it is only
+	// deleted when it still points to the given run
+	DeleteCurrentWorkflowExecutionRequest struct {
+		DomainID   string
+		WorkflowID string
+		RunID      string
+	}
+
+	// UpdateCurrentWorkflowExecutionRequest is used to point the current execution record of a workflow to another
+	// run, it is only updated when it still points to PreviousRunID
+	UpdateCurrentWorkflowExecutionRequest struct {
+		DomainID       string
+		WorkflowID     string
+		RunID          string
+		PreviousRunID  string
+		StartRequestID string
+		State          int
+		CloseStatus    int
+	}
+
 	// GetTransferTasksRequest is used to read tasks from the transfer task queue
 	GetTransferTasksRequest struct {
 		ReadLevel    int64
@@ -670,6 +787,9 @@ type (
 		TransactionID int64
 		Events        *SerializedHistoryEventBatch
 		Overwrite     bool
+		// KeyID is the id of the encryption key the events are encrypted at rest with, empty when the workflow
+		// execution is not tagged with a key
+		KeyID string
 	}
 
 	// GetWorkflowExecutionHistoryRequest is used to retrieve history of a workflow execution
@@ -695,6 +815,21 @@ type (
 		NextPageToken []byte
 	}
 
+	// RawHistoryBatch is a serialized history batch along with the append transaction which wrote it
+	RawHistoryBatch struct {
+		FirstEventID  int64
+		TransactionID int64
+		Events        SerializedHistoryEventBatch
+	}
+
+	// GetWorkflowExecutionRawHistoryResponse is the response to GetWorkflowExecutionRawHistory
+	GetWorkflowExecutionRawHistoryResponse struct {
+		// Slice of history append transaction batches, in the order they were appended
+		Batches []*RawHistoryBatch
+		// Token to read next page if there are more events beyond page size.
+		NextPageToken []byte
+	}
+
 	// DeleteWorkflowExecutionHistoryRequest is used to delete workflow execution history
 	DeleteWorkflowExecutionHistoryRequest struct {
 		DomainID  string
@@ -800,7 +935,10 @@ type (
 		GetWorkflowExecution(request *GetWorkflowExecutionRequest) (*GetWorkflowExecutionResponse, error)
 		UpdateWorkflowExecution(request *UpdateWorkflowExecutionRequest) error
 		DeleteWorkflowExecution(request *DeleteWorkflowExecutionRequest) error
+		DeleteCurrentWorkflowExecution(request *DeleteCurrentWorkflowExecutionRequest) error
+		UpdateCurrentWorkflowExecution(request *UpdateCurrentWorkflowExecutionRequest) error
 		GetCurrentExecution(request *GetCurrentExecutionRequest) (*GetCurrentExecutionResponse, error)
+		ListOpenExecutions(request *ListOpenExecutionsRequest) (*ListOpenExecutionsResponse, error)
 		GetTransferTasks(request *GetTransferTasksRequest) (*GetTransferTasksResponse, error)
 		CompleteTransferTask(request *CompleteTransferTaskRequest) error
 
@@ -836,6 +974,10 @@ type (
 		// GetWorkflowExecutionHistory retrieves the paginated list of history events for given execution
 		GetWorkflowExecutionHistory(request *GetWorkflowExecutionHistoryRequest) (*GetWorkflowExecutionHistoryResponse,
 			error)
+		// GetWorkflowExecutionRawHistory retrieves the paginated list of history batches for given execution together
+		// with the transaction ids which appended them
+		GetWorkflowExecutionRawHistory(request *GetWorkflowExecutionHistoryRequest) (
+			*GetWorkflowExecutionRawHistoryResponse, error)
 		DeleteWorkflowExecutionHistory(request *DeleteWorkflowExecutionHistoryRequest) error
 	}
 
@@ -955,6 +1097,31 @@ func (d *DecisionTimeoutTask) SetVisibilityTimestamp(t time.Time) {
 	d.VisibilityTimestamp = t
 }
 
+// GetType returns the type of the timer task
+func (d *DecisionBackoffTask) GetType() int {
+	return TaskTypeDecisionBackoff
+}
+
+// GetTaskID returns the sequence ID.
+func (d *DecisionBackoffTask) GetTaskID() int64 {
+	return d.TaskID
+}
+
+// SetTaskID sets the sequence ID.
+func (d *DecisionBackoffTask) SetTaskID(id int64) {
+	d.TaskID = id
+}
+
+// GetVisibilityTimestamp gets the visibility time stamp
+func (d *DecisionBackoffTask) GetVisibilityTimestamp() time.Time {
+	return d.VisibilityTimestamp
+}
+
+// SetVisibilityTimestamp gets the visibility time stamp
+func (d *DecisionBackoffTask) SetVisibilityTimestamp(t time.Time) {
+	d.VisibilityTimestamp = t
+}
+
 // GetType returns the type of the timer task
 func (a *ActivityTimeoutTask) GetType() int {
 	return TaskTypeActivityTimeout
@@ -1100,6 +1267,11 @@ func (t *TransferTaskInfo) GetTaskType() int {
 	return t.TaskType
 }
 
+// GetDomainID returns the domain ID for transfer task
+func (t *TransferTaskInfo) GetDomainID() string {
+	return t.DomainID
+}
+
 // GetTaskID returns the task ID for replication task
 func (t *ReplicationTaskInfo) GetTaskID() int64 {
 	return t.TaskID
@@ -1110,6 +1282,11 @@ func (t *ReplicationTaskInfo) GetTaskType() int {
 	return t.TaskType
 }
 
+// GetDomainID returns the domain ID for replication task
+func (t *ReplicationTaskInfo) GetDomainID() string {
+	return t.DomainID
+}
+
 // NewHistoryEventBatch returns a new instance of HistoryEventBatch
 func NewHistoryEventBatch(version int, events []*workflow.HistoryEvent) *HistoryEventBatch {
 	return &HistoryEventBatch{
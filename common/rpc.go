@@ -43,6 +43,10 @@ const (
 	// ClientImplHeaderName refers to the name of the
 	// header that contains the client implementation
 	ClientImplHeaderName = "cadence-client-name"
+
+	// BinaryChecksumHeaderName refers to the name of the
+	// header that contains the checksum of the worker binary
+	BinaryChecksumHeaderName = "cadence-client-binary-checksum"
 )
 
 type (
@@ -118,3 +118,9 @@ func (s *configSuite) TestGetDurationProperty() {
 	interval := s.cln.GetDurationProperty(key, time.Second)
 	s.Equal(time.Second, interval())
 }
+
+func (s *configSuite) TestGetStringProperty() {
+	key := HistoryDeniedBinaryChecksums
+	checksums := s.cln.GetStringProperty(key, "checksum")
+	s.Equal("checksum", checksums())
+}
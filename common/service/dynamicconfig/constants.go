@@ -45,6 +45,29 @@ var keys = []string{
 	_matchingDomainTaskListRoot + "updateAckInterval",
 	_matchingDomainTaskListRoot + "idleTasklistCheckInterval",
 	_historyRoot + "longPollExpirationInterval",
+	_historyRoot + "enableSyncMatchFirstDecision",
+	_historyRoot + "syncMatchFirstDecisionTimeout",
+	_historyRoot + "cacheInitialSize",
+	_historyRoot + "cacheMaxSize",
+	_historyRoot + "cacheTTL",
+	_historyRoot + "maxLongPollExpirationInterval",
+	_historyRoot + "deniedBinaryChecksums",
+	_historyRoot + "payloadOffloadThreshold",
+	_historyRoot + "workflowTypeRetentionDays",
+	_historyRoot + "minRetentionDays",
+	_historyRoot + "maxRetentionDays",
+	_historyRoot + "enableDomainIsolation",
+	_historyRoot + "domainIsolationWeight",
+	_historyRoot + "minDecisionStartToCloseTimeoutInSecs",
+	_historyRoot + "normalDecisionScheduleToStartTimeoutInSecs",
+	_historyRoot + "decisionCanaryTaskList",
+	_historyRoot + "decisionCanaryPercentage",
+	_historyRoot + "enableDecisionTypeFilter",
+	_historyRoot + "disabledDecisionTypes",
+	_historyRoot + "maxOpenExecutionsPerShard",
+	_historyRoot + "maxScheduleInputSize",
+	_historyRoot + "workflowIDReuseMinInterval",
+	_historyRoot + "shardExecutionRecountInterval",
 }
 
 const (
@@ -66,13 +89,69 @@ const (
 	MatchingIdleTasklistCheckInterval
 	// HistoryLongPollExpirationInterval is the long poll expiration interval in the history service
 	HistoryLongPollExpirationInterval
+	// HistoryEnableSyncMatchFirstDecision is to hand the first decision of a new workflow to matching synchronously
+	HistoryEnableSyncMatchFirstDecision
+	// HistorySyncMatchFirstDecisionTimeout is the timeout of the synchronous hand off of the first decision
+	HistorySyncMatchFirstDecisionTimeout
+	// HistoryCacheInitialSize is the initial size of the workflow execution cache of a history shard
+	HistoryCacheInitialSize
+	// HistoryCacheMaxSize is the maximum number of workflow executions cached by a history shard
+	HistoryCacheMaxSize
+	// HistoryCacheTTL is the time to live of an unused workflow execution cached by a history shard
+	HistoryCacheTTL
+	// HistoryMaxLongPollExpirationInterval is the upper bound of the long poll expiration interval a caller can
+	// request from the history service
+	HistoryMaxLongPollExpirationInterval
+	// HistoryDeniedBinaryChecksums is a comma separated list of worker binary checksums which are not given sticky
+	// decision tasks
+	HistoryDeniedBinaryChecksums
+	// HistoryPayloadOffloadThreshold is the size in bytes above which results and failure details are offloaded
+	// out of history
+	HistoryPayloadOffloadThreshold
+	// HistoryWorkflowTypeRetentionDays overrides the retention of the domain for closed executions of a workflow
+	// type, zero keeps the retention of the domain
+	HistoryWorkflowTypeRetentionDays
+	// HistoryMinRetentionDays is the lower bound of a retention override of a domain
+	HistoryMinRetentionDays
+	// HistoryMaxRetentionDays is the upper bound of a retention override of a domain
+	HistoryMaxRetentionDays
+	// HistoryEnableDomainIsolation holds busy domains to their share of the concurrent work of a shard
+	HistoryEnableDomainIsolation
+	// HistoryDomainIsolationWeight is the weight of a domain when sharing the concurrent work of a shard
+	HistoryDomainIsolationWeight
+	// HistoryMinDecisionStartToCloseTimeoutInSecs is the lower bound of the start to close timeout of decisions
+	HistoryMinDecisionStartToCloseTimeoutInSecs
+	// HistoryNormalDecisionScheduleToStartTimeoutInSecs is the schedule to start timeout of decisions which fell back
+	// from sticky dispatch to the normal task list, zero disables it
+	HistoryNormalDecisionScheduleToStartTimeoutInSecs
+	// HistoryDecisionCanaryTaskList is the task list a share of the runs of a workflow type have their decisions
+	// dispatched to, e.g. to try out new worker code, empty disables it
+	HistoryDecisionCanaryTaskList
+	// HistoryDecisionCanaryPercentage is the percentage of runs of a workflow type whose decisions go to the canary
+	// task list
+	HistoryDecisionCanaryPercentage
+	// HistoryEnableDecisionTypeFilter is to consult HistoryDisabledDecisionTypes when decisions are completed
+	HistoryEnableDecisionTypeFilter
+	// HistoryDisabledDecisionTypes is a comma separated list of decision types, e.g. StartChildWorkflowExecution,
+	// which fail the decisions of a domain making them
+	HistoryDisabledDecisionTypes
+	// HistoryMaxOpenExecutionsPerShard is the number of open workflow executions above which a shard rejects starts
+	HistoryMaxOpenExecutionsPerShard
+	// HistoryMaxScheduleInputSize is the maximum size of the input of an activity or child workflow scheduled by a
+	// decision
+	HistoryMaxScheduleInputSize
+	// HistoryWorkflowIDReuseMinInterval is the minimum time between the close of a run and the start of a new run of
+	// the same workflow id under the allow duplicate reuse policy
+	HistoryWorkflowIDReuseMinInterval
+	// HistoryShardExecutionRecountInterval is the minimum time between two recounts of the executions of a shard
+	HistoryShardExecutionRecountInterval
 )
 
 // Filter represents a filter on the dynamic config key
 type Filter int
 
 func (f Filter) String() string {
-	if f <= unknownFilter || f > TaskListName {
+	if f <= unknownFilter || f > WorkflowTypeName {
 		return filters[unknownFilter]
 	}
 	return filters[f]
@@ -82,6 +161,7 @@ var filters = []string{
 	"unknownFilter",
 	"domainName",
 	"taskListName",
+	"workflowTypeName",
 }
 
 const (
@@ -90,6 +170,8 @@ const (
 	DomainName
 	// TaskListName is the tasklist name
 	TaskListName
+	// WorkflowTypeName is the workflow type name
+	WorkflowTypeName
 )
 
 // FilterOption is used to provide filters for dynamic config keys
@@ -108,3 +190,10 @@ func DomainFilter(name string) FilterOption {
 		filterMap[DomainName] = name
 	}
 }
+
+// WorkflowTypeFilter filters by workflow type name
+func WorkflowTypeFilter(name string) FilterOption {
+	return func(filterMap map[Filter]interface{}) {
+		filterMap[WorkflowTypeName] = name
+	}
+}
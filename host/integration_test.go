@@ -627,15 +627,16 @@ Loop:
 		p.logger.Infof("Completing Decision.  Decisions: %v", decisions)
 		if !respondStickyTaskList {
 			// non sticky tasklist
-			return false, p.engine.RespondDecisionTaskCompleted(createContext(), &workflow.RespondDecisionTaskCompletedRequest{
+			_, err = p.engine.RespondDecisionTaskCompleted(createContext(), &workflow.RespondDecisionTaskCompletedRequest{
 				TaskToken:        response.TaskToken,
 				Identity:         common.StringPtr(p.identity),
 				ExecutionContext: executionCtx,
 				Decisions:        decisions,
 			})
+			return false, err
 		}
 		// sticky tasklist
-		return false, p.engine.RespondDecisionTaskCompleted(
+		_, err = p.engine.RespondDecisionTaskCompleted(
 			createContext(),
 			&workflow.RespondDecisionTaskCompletedRequest{
 				TaskToken:        response.TaskToken,
@@ -651,6 +652,7 @@ Loop:
 			yarpc.WithHeader(common.FeatureVersionHeaderName, "1.0.0"),
 			yarpc.WithHeader(common.ClientImplHeaderName, "go"),
 		)
+		return false, err
 	}
 
 	return false, matching.ErrNoTasks
@@ -3194,7 +3196,7 @@ func (s *integrationSuite) TestExternalRequestCancelWorkflowExecution() {
 	s.logger.Infof("pollAndProcessActivityTask: %v", err)
 	s.Nil(err)
 
-	err = s.engine.RequestCancelWorkflowExecution(createContext(), &workflow.RequestCancelWorkflowExecutionRequest{
+	_, err = s.engine.RequestCancelWorkflowExecution(createContext(), &workflow.RequestCancelWorkflowExecutionRequest{
 		Domain: common.StringPtr(s.domainName),
 		WorkflowExecution: &workflow.WorkflowExecution{
 			WorkflowId: common.StringPtr(id),
@@ -3203,7 +3205,7 @@ func (s *integrationSuite) TestExternalRequestCancelWorkflowExecution() {
 	})
 	s.Nil(err)
 
-	err = s.engine.RequestCancelWorkflowExecution(createContext(), &workflow.RequestCancelWorkflowExecutionRequest{
+	_, err = s.engine.RequestCancelWorkflowExecution(createContext(), &workflow.RequestCancelWorkflowExecutionRequest{
 		Domain: common.StringPtr(s.domainName),
 		WorkflowExecution: &workflow.WorkflowExecution{
 			WorkflowId: common.StringPtr(id),
@@ -36,6 +36,7 @@ struct ParentExecutionInfo {
   15: optional string domain
   20: optional shared.WorkflowExecution execution
   30: optional i64 (js.type = "Long") initiatedId
+  40: optional i32 depth
 }
 
 struct StartWorkflowExecutionRequest {
@@ -44,10 +45,23 @@ struct StartWorkflowExecutionRequest {
   30: optional ParentExecutionInfo parentExecutionInfo
 }
 
+// ReadConsistencyLevel tells whether a read may be served from the cached mutable state of an execution, or the
+// mutable state must be read from persistence first
+enum ReadConsistencyLevel {
+  EVENTUAL,
+  STRONG,
+}
+
 struct GetMutableStateRequest {
   10: optional string domainUUID
   20: optional shared.WorkflowExecution execution
   30: optional i64 (js.type = "Long") expectedNextEventId
+  40: optional i32 waitTimeoutSeconds
+  // bypassCache reads the mutable state straight from persistence and returns right away, refreshCache replaces
+  // the cached mutable state with the one read
+  50: optional bool bypassCache
+  60: optional bool refreshCache
+  70: optional ReadConsistencyLevel consistencyLevel
 }
 
 struct GetMutableStateResponse {
@@ -62,6 +76,19 @@ struct GetMutableStateResponse {
   90: optional string clientImpl
   100: optional bool isWorkflowRunning
   110: optional i32 stickyTaskListScheduleToStartTimeout
+  // only set for requests bypassing the cache
+  120: optional i64 (js.type = "Long") persistedNextEventId
+  130: optional bool staleCacheDetected
+  140: optional bool staleCacheCorrected
+  150: optional i64 (js.type = "Long") startTime
+  // only set once the workflow is closed
+  160: optional i64 (js.type = "Long") closeTime
+  // number of continue-as-new runs before this one
+  170: optional i64 (js.type = "Long") generation
+  // sequence number of the last signal received by the run
+  180: optional i64 (js.type = "Long") lastSignalSequenceNumber
+  // events are buffered which are not in history yet, the next batch of history may skip past nextEventId
+  190: optional bool hasBufferedEvents
 }
 
 struct ResetStickyTaskListRequest {
@@ -69,9 +96,14 @@ struct ResetStickyTaskListRequest {
   20: optional shared.WorkflowExecution execution
 }
 
+enum ResetStickyTaskListSkippedReason {
+  WORKFLOW_NOT_RUNNING,
+  NOT_STICKY,
+}
+
 struct ResetStickyTaskListResponse {
-  // The reason to keep this response is to allow returning
-  // information in the future.
+  10: optional bool stickinessCleared
+  20: optional ResetStickyTaskListSkippedReason skippedReason
 }
 
 struct RespondDecisionTaskCompletedRequest {
@@ -79,6 +111,12 @@ struct RespondDecisionTaskCompletedRequest {
   20: optional shared.RespondDecisionTaskCompletedRequest completeRequest
 }
 
+struct RespondDecisionTaskCompletedResponse {
+  10: optional RecordDecisionTaskStartedResponse startedResponse
+  // scheduled event ids of the activities scheduled by the decisions, keyed by activity id
+  20: optional map<string, i64> activityScheduledEventIds
+}
+
 struct RespondDecisionTaskFailedRequest {
   10: optional string domainUUID
   20: optional shared.RespondDecisionTaskFailedRequest failedRequest
@@ -177,6 +215,12 @@ struct ScheduleDecisionTaskRequest {
 struct DescribeWorkflowExecutionRequest {
   10: optional string domainUUID
   20: optional shared.DescribeWorkflowExecutionRequest request
+  // verbose attaches the raw mutable state of the execution to the response, it is honored only while the cluster
+  // wide EnableClusterVerboseDescribe switch of the history service is on, and never set by frontend
+  30: optional bool verbose
+  40: optional ReadConsistencyLevel consistencyLevel
+  // onlySafeResetPoints drops the reset points which are not safe from the response
+  50: optional bool onlySafeResetPoints
 }
 
 /**
@@ -221,6 +265,7 @@ service HistoryService {
       2: shared.InternalServiceError internalServiceError,
       3: shared.WorkflowExecutionAlreadyStartedError sessionAlreadyExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
+      5: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -250,6 +295,7 @@ service HistoryService {
       2: shared.InternalServiceError internalServiceError,
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
+      5: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -264,6 +310,7 @@ service HistoryService {
       3: EventAlreadyStartedError eventAlreadyStartedError,
       4: shared.EntityNotExistsError entityNotExistError,
       5: ShardOwnershipLostError shardOwnershipLostError,
+      6: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -278,6 +325,7 @@ service HistoryService {
       3: EventAlreadyStartedError eventAlreadyStartedError,
       4: shared.EntityNotExistsError entityNotExistError,
       5: ShardOwnershipLostError shardOwnershipLostError,
+      6: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -287,12 +335,13 @@ service HistoryService {
   * event in the history for that session.  Use the 'taskToken' provided as response of PollForDecisionTask API call
   * for completing the DecisionTask.
   **/
-  void RespondDecisionTaskCompleted(1: RespondDecisionTaskCompletedRequest completeRequest)
+  RespondDecisionTaskCompletedResponse RespondDecisionTaskCompleted(1: RespondDecisionTaskCompletedRequest completeRequest)
     throws (
       1: shared.BadRequestError badRequestError,
       2: shared.InternalServiceError internalServiceError,
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
+      5: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -306,6 +355,7 @@ service HistoryService {
       2: shared.InternalServiceError internalServiceError,
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
+      5: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -321,6 +371,7 @@ service HistoryService {
       2: shared.InternalServiceError internalServiceError,
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
+      5: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -336,6 +387,7 @@ service HistoryService {
       2: shared.InternalServiceError internalServiceError,
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
+      5: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -351,6 +403,7 @@ service HistoryService {
       2: shared.InternalServiceError internalServiceError,
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
+      5: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -366,6 +419,7 @@ service HistoryService {
       2: shared.InternalServiceError internalServiceError,
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
+      5: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -378,6 +432,7 @@ service HistoryService {
       2: shared.InternalServiceError internalServiceError,
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
+      5: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -392,6 +447,7 @@ service HistoryService {
       1: shared.BadRequestError badRequestError,
       2: shared.InternalServiceError internalServiceError,
       3: ShardOwnershipLostError shardOwnershipLostError,
+      4: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -404,6 +460,7 @@ service HistoryService {
       2: shared.InternalServiceError internalServiceError,
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
+      5: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -416,6 +473,7 @@ service HistoryService {
       2: shared.InternalServiceError internalServiceError,
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
+      5: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -424,13 +482,14 @@ service HistoryService {
   * created for the workflow instance so new decisions could be made. It fails with 'EntityNotExistsError' if the workflow is not valid
   * anymore due to completion or doesn't exist.
   **/
-  void RequestCancelWorkflowExecution(1: RequestCancelWorkflowExecutionRequest cancelRequest)
+  shared.RequestCancelWorkflowExecutionResponse RequestCancelWorkflowExecution(1: RequestCancelWorkflowExecutionRequest cancelRequest)
     throws (
       1: shared.BadRequestError badRequestError,
       2: shared.InternalServiceError internalServiceError,
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
       5: shared.CancellationAlreadyRequestedError cancellationAlreadyRequestedError,
+      6: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -445,6 +504,7 @@ service HistoryService {
       2: shared.InternalServiceError internalServiceError,
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
+      5: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -457,6 +517,7 @@ service HistoryService {
       2: shared.InternalServiceError internalServiceError,
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
+      5: shared.DomainNotActiveError domainNotActiveError,
     )
 
   /**
@@ -63,6 +63,7 @@ struct AddActivityTaskRequest {
   40: optional shared.TaskList taskList
   50: optional i64 (js.type = "Long") scheduleId
   60: optional i32 scheduleToStartTimeoutSeconds
+  70: optional i32 priority
 }
 
 struct QueryWorkflowRequest {
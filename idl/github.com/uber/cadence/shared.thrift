@@ -36,6 +36,8 @@ exception WorkflowExecutionAlreadyStartedError {
   10: optional string message
   20: optional string startRequestId
   30: optional string runId
+  40: optional bool running
+  50: optional WorkflowExecutionCloseStatus closeStatus
 }
 
 exception EntityNotExistsError {
@@ -173,6 +175,10 @@ enum DecisionTaskFailedCause {
   WORKFLOW_WORKER_UNHANDLED_FAILURE,
   BAD_SIGNAL_WORKFLOW_EXECUTION_ATTRIBUTES,
   BAD_START_CHILD_EXECUTION_ATTRIBUTES,
+  CHILD_WORKFLOW_DEPTH_EXCEEDED,
+  FORCE_FAILED_BY_OPERATOR,
+  SHARD_OVERLOADED,
+  DECISION_TYPE_DISABLED,
 }
 
 enum CancelExternalWorkflowExecutionFailedCause {
@@ -185,6 +191,7 @@ enum SignalExternalWorkflowExecutionFailedCause {
 
 enum ChildWorkflowExecutionFailedCause {
   WORKFLOW_ALREADY_RUNNING,
+  CANCELED_BEFORE_START,
 }
 
 enum WorkflowExecutionCloseStatus {
@@ -223,6 +230,11 @@ enum TaskListKind {
   STICKY,
 }
 
+enum ActivityFailureType {
+  RETRYABLE,
+  TERMINAL,
+}
+
 struct WorkflowType {
   10: optional string name
 }
@@ -245,6 +257,17 @@ struct WorkflowExecution {
   20: optional string runId
 }
 
+// ResetPoint is a completed decision a workflow execution can be reset to
+struct ResetPoint {
+  10: optional i64 (js.type = "Long") eventId
+  20: optional string binaryChecksum
+  // resettable is false when there were buffered events the decision did not see
+  30: optional bool resettable
+  40: optional i64 (js.type = "Long") createdTime
+  // safe is set when, besides being resettable, no activity had been started and would be lost by the reset
+  50: optional bool safe
+}
+
 struct WorkflowExecutionInfo {
   10: optional WorkflowExecution execution
   20: optional WorkflowType type
@@ -252,6 +275,17 @@ struct WorkflowExecutionInfo {
   40: optional i64 (js.type = "Long") closeTime
   50: optional WorkflowExecutionCloseStatus closeStatus
   60: optional i64 (js.type = "Long") historyLength
+  70: optional i32 depth
+  80: optional i64 (js.type = "Long") signalCount
+  90: optional string lastSignalName
+  100: optional i64 (js.type = "Long") lastSignalTimestamp
+  110: optional string binaryChecksum
+  120: optional bool paused
+  130: optional i64 (js.type = "Long") decisionAttempt
+  140: optional i32 maxDecisionAttempts
+  150: optional list<ResetPoint> resetPoints
+  160: optional map<string, string> operatorTags
+  170: optional i64 (js.type = "Long") generation
 }
 
 struct WorkflowExecutionConfiguration {
@@ -259,6 +293,8 @@ struct WorkflowExecutionConfiguration {
   20: optional i32 executionStartToCloseTimeoutSeconds
   30: optional i32 taskStartToCloseTimeoutSeconds
   40: optional ChildPolicy childPolicy
+  50: optional i32 stickyScheduleToStartTimeoutSeconds
+  60: optional i32 normalScheduleToStartTimeoutSeconds
 }
 
 struct TransientDecisionInfo {
@@ -276,6 +312,9 @@ struct ScheduleActivityTaskDecisionAttributes {
   50: optional i32 scheduleToStartTimeoutSeconds
   55: optional i32 startToCloseTimeoutSeconds
   60: optional i32 heartbeatTimeoutSeconds
+  70: optional i32 priority
+  // the activity times out instead of being dispatched once it waited longer than this, at most scheduleToStart
+  80: optional i32 dispatchDeadlineSeconds
 }
 
 struct RequestCancelActivityTaskDecisionAttributes {
@@ -332,6 +371,7 @@ struct ContinueAsNewWorkflowExecutionDecisionAttributes {
   30: optional binary input
   40: optional i32 executionStartToCloseTimeoutSeconds
   50: optional i32 taskStartToCloseTimeoutSeconds
+  60: optional bool carrySignalMetadata
 }
 
 struct StartChildWorkflowExecutionDecisionAttributes {
@@ -375,6 +415,8 @@ struct WorkflowExecutionStartedEventAttributes {
   52: optional ChildPolicy childPolicy
   54: optional string continuedExecutionRunId
   60: optional string identity
+  // keyId is the id of the encryption key the history of the workflow execution is encrypted at rest with
+  70: optional string keyId
 }
 
 struct WorkflowExecutionCompletedEventAttributes {
@@ -446,6 +488,8 @@ struct ActivityTaskScheduledEventAttributes {
   55: optional i32 startToCloseTimeoutSeconds
   60: optional i32 heartbeatTimeoutSeconds
   90: optional i64 (js.type = "Long") decisionTaskCompletedEventId
+  100: optional i32 priority
+  110: optional i32 dispatchDeadlineSeconds
 }
 
 struct ActivityTaskStartedEventAttributes {
@@ -467,6 +511,8 @@ struct ActivityTaskFailedEventAttributes {
   30: optional i64 (js.type = "Long") scheduledEventId
   40: optional i64 (js.type = "Long") startedEventId
   50: optional string identity
+  // Whether the activity reported the failure as terminal or retryable, not set when it did not classify it
+  60: optional ActivityFailureType failureType
 }
 
 struct ActivityTaskTimedOutEventAttributes {
@@ -542,6 +588,9 @@ struct WorkflowExecutionSignaledEventAttributes {
   10: optional string signalName
   20: optional binary input
   30: optional string identity
+  // order in which the signal was received by the run, starting at 1 unless signal metadata was carried over by
+  // continue-as-new
+  40: optional i64 (js.type = "Long") sequenceNumber
 }
 
 struct WorkflowExecutionTerminatedEventAttributes {
@@ -819,6 +868,8 @@ struct StartWorkflowExecutionRequest {
   90: optional string requestId
   100: optional WorkflowIdReusePolicy workflowIdReusePolicy
   110: optional ChildPolicy childPolicy
+  // keyId is the id of the encryption key the history of the workflow execution is encrypted at rest with
+  120: optional string keyId
 }
 
 struct StartWorkflowExecutionResponse {
@@ -855,6 +906,13 @@ struct RespondDecisionTaskCompletedRequest {
   30: optional binary executionContext
   40: optional string identity
   50: optional StickyExecutionAttributes stickyAttributes
+  60: optional bool returnNewDecisionTask
+}
+
+struct RespondDecisionTaskCompletedResponse {
+  10: optional PollForDecisionTaskResponse decisionTask
+  // scheduled event ids of the activities scheduled by the decisions, keyed by activity id
+  20: optional map<string, i64> activityScheduledEventIds
 }
 
 struct RespondDecisionTaskFailedRequest {
@@ -884,10 +942,17 @@ struct PollForActivityTaskResponse {
   110: optional i32 heartbeatTimeoutSeconds
 }
 
+struct ActivityCheckpoint {
+  10: optional i64 sequenceNumber
+  20: optional binary payload
+}
+
 struct RecordActivityTaskHeartbeatRequest {
   10: optional binary taskToken
   20: optional binary details
   30: optional string identity
+  40: optional bool acceptCancel
+  50: optional ActivityCheckpoint checkpoint
 }
 
 struct RecordActivityTaskHeartbeatByIDRequest {
@@ -897,16 +962,19 @@ struct RecordActivityTaskHeartbeatByIDRequest {
   40: optional string activityID
   50: optional binary details
   60: optional string identity
+  70: optional ActivityCheckpoint checkpoint
 }
 
 struct RecordActivityTaskHeartbeatResponse {
   10: optional bool cancelRequested
+  20: optional ActivityCheckpoint lastCheckpoint
 }
 
 struct RespondActivityTaskCompletedRequest {
   10: optional binary taskToken
   20: optional binary result
   30: optional string identity
+  40: optional string requestId
 }
 
 struct RespondActivityTaskFailedRequest {
@@ -914,6 +982,8 @@ struct RespondActivityTaskFailedRequest {
   20: optional string reason
   30: optional binary details
   40: optional string identity
+  // A terminal failure should not be retried
+  50: optional ActivityFailureType failureType
 }
 
 struct RespondActivityTaskCanceledRequest {
@@ -929,6 +999,7 @@ struct RespondActivityTaskCompletedByIDRequest {
   40: optional string activityID
   50: optional binary result
   60: optional string identity
+  70: optional string requestId
 }
 
 struct RespondActivityTaskFailedByIDRequest {
@@ -939,6 +1010,8 @@ struct RespondActivityTaskFailedByIDRequest {
   50: optional string reason
   60: optional binary details
   70: optional string identity
+  // A terminal failure should not be retried
+  80: optional ActivityFailureType failureType
 }
 
 struct RespondActivityTaskCanceledByIDRequest {
@@ -955,6 +1028,15 @@ struct RequestCancelWorkflowExecutionRequest {
   20: optional WorkflowExecution workflowExecution
   30: optional string identity
   40: optional string requestId
+  50: optional string expectedRunId
+  // succeed with the pending cancel request instead of failing if cancellation was already requested
+  60: optional bool idempotent
+}
+
+struct RequestCancelWorkflowExecutionResponse {
+  // request id and event id of the cancel request pending on the workflow execution
+  10: optional string cancelRequestId
+  20: optional i64 (js.type = "Long") cancelRequestedEventId
 }
 
 struct GetWorkflowExecutionHistoryRequest {
@@ -981,6 +1063,13 @@ struct SignalWorkflowExecutionRequest {
   70: optional binary control
 }
 
+struct BatchedSignal {
+  10: optional string signalName
+  20: optional binary input
+  30: optional binary control
+  40: optional string requestId
+}
+
 struct SignalWithStartWorkflowExecutionRequest {
   10: optional string domain
   20: optional string workflowId
@@ -995,6 +1084,8 @@ struct SignalWithStartWorkflowExecutionRequest {
   110: optional string signalName
   120: optional binary signalInput
   130: optional binary control
+  140: optional list<BatchedSignal> signals
+  150: optional string keyId
 }
 
 struct TerminateWorkflowExecutionRequest {
@@ -1003,6 +1094,8 @@ struct TerminateWorkflowExecutionRequest {
   30: optional string reason
   40: optional binary details
   50: optional string identity
+  60: optional string expectedRunId
+  70: optional bool honorDoNotTerminateTag
 }
 
 struct ListOpenWorkflowExecutionsRequest {
@@ -1067,12 +1160,16 @@ struct PendingActivityInfo {
   30: optional PendingActivityState state
   40: optional binary heartbeatDetails
   50: optional i64 (js.type = "Long") lastHeartbeatTimestamp
+  60: optional string startedWorkerIdentity
+  70: optional bool acceptedCancel
 }
 
 struct DescribeWorkflowExecutionResponse {
   10: optional WorkflowExecutionConfiguration executionConfiguration
   20: optional WorkflowExecutionInfo workflowExecutionInfo
   30: optional list<PendingActivityInfo> pendingActivities
+  // rawExecutionInfo is a JSON dump of internal mutable state fields, only set for verbose describe requests
+  40: optional string rawExecutionInfo
 }
 
 struct DescribeTaskListRequest {
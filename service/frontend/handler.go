@@ -759,9 +759,10 @@ func (wh *WorkflowHandler) RecordActivityTaskHeartbeatByID(
 	}
 
 	req := &gen.RecordActivityTaskHeartbeatRequest{
-		TaskToken: token,
-		Details:   heartbeatRequest.Details,
-		Identity:  heartbeatRequest.Identity,
+		TaskToken:  token,
+		Details:    heartbeatRequest.Details,
+		Identity:   heartbeatRequest.Identity,
+		Checkpoint: heartbeatRequest.Checkpoint,
 	}
 
 	resp, err := wh.history.RecordActivityTaskHeartbeat(ctx, &h.RecordActivityTaskHeartbeatRequest{
@@ -861,6 +862,7 @@ func (wh *WorkflowHandler) RespondActivityTaskCompletedByID(
 		TaskToken: token,
 		Result:    completeRequest.Result,
 		Identity:  completeRequest.Identity,
+		RequestId: completeRequest.RequestId,
 	}
 
 	err = wh.history.RespondActivityTaskCompleted(ctx, &h.RespondActivityTaskCompletedRequest{
@@ -957,10 +959,11 @@ func (wh *WorkflowHandler) RespondActivityTaskFailedByID(
 	}
 
 	req := &gen.RespondActivityTaskFailedRequest{
-		TaskToken: token,
-		Reason:    failedRequest.Reason,
-		Details:   failedRequest.Details,
-		Identity:  failedRequest.Identity,
+		TaskToken:   token,
+		Reason:      failedRequest.Reason,
+		Details:     failedRequest.Details,
+		Identity:    failedRequest.Identity,
+		FailureType: failedRequest.FailureType,
 	}
 
 	err = wh.history.RespondActivityTaskFailed(ctx, &h.RespondActivityTaskFailedRequest{
@@ -1075,38 +1078,76 @@ func (wh *WorkflowHandler) RespondActivityTaskCanceledByID(
 // RespondDecisionTaskCompleted - response to a decision task
 func (wh *WorkflowHandler) RespondDecisionTaskCompleted(
 	ctx context.Context,
-	completeRequest *gen.RespondDecisionTaskCompletedRequest) error {
+	completeRequest *gen.RespondDecisionTaskCompletedRequest) (*gen.RespondDecisionTaskCompletedResponse, error) {
 
 	scope := metrics.FrontendRespondDecisionTaskCompletedScope
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
 	if completeRequest == nil {
-		return wh.error(errRequestNotSet, scope)
+		return nil, wh.error(errRequestNotSet, scope)
 	}
 
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
 	wh.rateLimiter.TryConsume(1)
 
 	if completeRequest.TaskToken == nil {
-		return wh.error(errTaskTokenNotSet, scope)
+		return nil, wh.error(errTaskTokenNotSet, scope)
 	}
 	taskToken, err := wh.tokenSerializer.Deserialize(completeRequest.TaskToken)
 	if err != nil {
-		return wh.error(err, scope)
+		return nil, wh.error(err, scope)
 	}
 	if taskToken.DomainID == "" {
-		return wh.error(errDomainNotSet, scope)
+		return nil, wh.error(errDomainNotSet, scope)
 	}
 
-	err = wh.history.RespondDecisionTaskCompleted(ctx, &h.RespondDecisionTaskCompletedRequest{
+	histResp, err := wh.history.RespondDecisionTaskCompleted(ctx, &h.RespondDecisionTaskCompletedRequest{
 		DomainUUID:      common.StringPtr(taskToken.DomainID),
 		CompleteRequest: completeRequest},
 	)
 	if err != nil {
-		return wh.error(err, scope)
+		return nil, wh.error(err, scope)
 	}
-	return nil
+
+	completedResp := &gen.RespondDecisionTaskCompletedResponse{}
+	if histResp != nil {
+		completedResp.ActivityScheduledEventIds = histResp.ActivityScheduledEventIds
+	}
+	if completeRequest.GetReturnNewDecisionTask() && histResp != nil && histResp.StartedResponse != nil {
+		startedResp := histResp.StartedResponse
+		newTaskToken, err := wh.tokenSerializer.Serialize(&common.TaskToken{
+			DomainID:        taskToken.DomainID,
+			WorkflowID:      taskToken.WorkflowID,
+			RunID:           taskToken.RunID,
+			ScheduleID:      startedResp.GetScheduledEventId(),
+			ScheduleAttempt: startedResp.GetAttempt(),
+		})
+		if err != nil {
+			return nil, wh.error(err, scope)
+		}
+		matchingResp := &m.PollForDecisionTaskResponse{
+			TaskToken: newTaskToken,
+			WorkflowExecution: &gen.WorkflowExecution{
+				WorkflowId: common.StringPtr(taskToken.WorkflowID),
+				RunId:      common.StringPtr(taskToken.RunID),
+			},
+			WorkflowType:           startedResp.WorkflowType,
+			StartedEventId:         startedResp.StartedEventId,
+			Attempt:                common.Int64Ptr(startedResp.GetAttempt()),
+			NextEventId:            startedResp.NextEventId,
+			StickyExecutionEnabled: startedResp.StickyExecutionEnabled,
+			DecisionInfo:           startedResp.DecisionInfo,
+		}
+		if startedResp.GetPreviousStartedEventId() != common.EmptyEventID {
+			matchingResp.PreviousStartedEventId = startedResp.PreviousStartedEventId
+		}
+		completedResp.DecisionTask, err = wh.createPollForDecisionTaskResponse(ctx, taskToken.DomainID, matchingResp)
+		if err != nil {
+			return nil, wh.error(err, scope)
+		}
+	}
+	return completedResp, nil
 }
 
 // RespondDecisionTaskFailed - failed response to a decision task
@@ -1484,9 +1525,14 @@ func (wh *WorkflowHandler) SignalWithStartWorkflowExecution(ctx context.Context,
 		return nil, wh.error(&gen.BadRequestError{Message: "WorkflowId is not set on request."}, scope)
 	}
 
-	if signalWithStartRequest.GetSignalName() == "" {
+	if signalWithStartRequest.GetSignalName() == "" && len(signalWithStartRequest.Signals) == 0 {
 		return nil, wh.error(&gen.BadRequestError{Message: "SignalName is not set on request."}, scope)
 	}
+	for _, signal := range signalWithStartRequest.Signals {
+		if signal == nil || signal.GetSignalName() == "" {
+			return nil, wh.error(&gen.BadRequestError{Message: "SignalName is not set on batched signal."}, scope)
+		}
+	}
 
 	if signalWithStartRequest.WorkflowType == nil || signalWithStartRequest.WorkflowType.GetName() == "" {
 		return nil, wh.error(&gen.BadRequestError{Message: "WorkflowType is not set on request."}, scope)
@@ -1566,42 +1612,42 @@ func (wh *WorkflowHandler) TerminateWorkflowExecution(ctx context.Context,
 // RequestCancelWorkflowExecution - requests to cancel a workflow execution
 func (wh *WorkflowHandler) RequestCancelWorkflowExecution(
 	ctx context.Context,
-	cancelRequest *gen.RequestCancelWorkflowExecutionRequest) error {
+	cancelRequest *gen.RequestCancelWorkflowExecutionRequest) (*gen.RequestCancelWorkflowExecutionResponse, error) {
 
 	scope := metrics.FrontendRequestCancelWorkflowExecutionScope
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
 	if cancelRequest == nil {
-		return wh.error(errRequestNotSet, scope)
+		return nil, wh.error(errRequestNotSet, scope)
 	}
 
 	if ok, _ := wh.rateLimiter.TryConsume(1); !ok {
-		return wh.error(createServiceBusyError(), scope)
+		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
 	if cancelRequest.GetDomain() == "" {
-		return wh.error(errDomainNotSet, scope)
+		return nil, wh.error(errDomainNotSet, scope)
 	}
 
 	if err := wh.validateExecution(cancelRequest.WorkflowExecution, scope); err != nil {
-		return err
+		return nil, err
 	}
 
 	domainID, err := wh.domainCache.GetDomainID(cancelRequest.GetDomain())
 	if err != nil {
-		return wh.error(err, scope)
+		return nil, wh.error(err, scope)
 	}
 
-	err = wh.history.RequestCancelWorkflowExecution(ctx, &h.RequestCancelWorkflowExecutionRequest{
+	resp, err := wh.history.RequestCancelWorkflowExecution(ctx, &h.RequestCancelWorkflowExecutionRequest{
 		DomainUUID:    common.StringPtr(domainID),
 		CancelRequest: cancelRequest,
 	})
 	if err != nil {
-		return wh.error(err, scope)
+		return nil, wh.error(err, scope)
 	}
 
-	return nil
+	return resp, nil
 }
 
 // ListOpenWorkflowExecutions - retrieves info for open workflow executions in a domain
@@ -1993,6 +2039,9 @@ func (wh *WorkflowHandler) getHistory(domainID string, execution gen.WorkflowExe
 		if err1 != nil {
 			return nil, nil, err1
 		}
+		if err1 = wh.hydratePayloads(domainID, history.Events); err1 != nil {
+			return nil, nil, err1
+		}
 		historyEvents = append(historyEvents, history.Events...)
 	}
 
@@ -2007,6 +2056,51 @@ func (wh *WorkflowHandler) getHistory(domainID string, execution gen.WorkflowExe
 	return executionHistory, nextPageToken, nil
 }
 
+// hydratePayloads replaces the results and failure details which the history service offloaded with their
+// original content
+func (wh *WorkflowHandler) hydratePayloads(domainID string, events []*gen.HistoryEvent) error {
+	var err error
+	for _, event := range events {
+		switch event.GetEventType() {
+		case gen.EventTypeActivityTaskCompleted:
+			attributes := event.ActivityTaskCompletedEventAttributes
+			attributes.Result, err = wh.config.PayloadOffloader.Hydrate(domainID, attributes.Result)
+		case gen.EventTypeWorkflowExecutionCompleted:
+			attributes := event.WorkflowExecutionCompletedEventAttributes
+			attributes.Result, err = wh.config.PayloadOffloader.Hydrate(domainID, attributes.Result)
+		case gen.EventTypeWorkflowExecutionFailed:
+			attributes := event.WorkflowExecutionFailedEventAttributes
+			attributes.Details, err = wh.config.PayloadOffloader.Hydrate(domainID, attributes.Details)
+		case gen.EventTypeChildWorkflowExecutionCompleted:
+			attributes := event.ChildWorkflowExecutionCompletedEventAttributes
+			var childDomainID string
+			if childDomainID, err = wh.getChildDomainID(domainID, attributes.GetDomain()); err == nil {
+				attributes.Result, err = wh.config.PayloadOffloader.Hydrate(childDomainID, attributes.Result)
+			}
+		case gen.EventTypeChildWorkflowExecutionFailed:
+			attributes := event.ChildWorkflowExecutionFailedEventAttributes
+			var childDomainID string
+			if childDomainID, err = wh.getChildDomainID(domainID, attributes.GetDomain()); err == nil {
+				attributes.Details, err = wh.config.PayloadOffloader.Hydrate(childDomainID, attributes.Details)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getChildDomainID returns the domain of a child workflow execution, the payloads of a child are offloaded under the
+// domain of the child.  The domain name recorded on the events of a child comes from its initiated event, where it is
+// left empty for a child started in the domain of its parent.
+func (wh *WorkflowHandler) getChildDomainID(parentDomainID string, childDomain string) (string, error) {
+	if childDomain == "" {
+		return parentDomainID, nil
+	}
+	return wh.domainCache.GetDomainID(childDomain)
+}
+
 func (wh *WorkflowHandler) getLoggerForTask(taskToken []byte) bark.Logger {
 	logger := wh.Service.GetLogger()
 	task, err := wh.tokenSerializer.Deserialize(taskToken)
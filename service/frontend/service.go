@@ -36,6 +36,9 @@ type Config struct {
 
 	// Persistence settings
 	HistoryMgrNumConns int
+
+	// Restores payloads which the history service offloaded out of history
+	PayloadOffloader common.PayloadOffloader
 }
 
 // NewConfig returns new service config with default values
@@ -45,6 +48,7 @@ func NewConfig() *Config {
 		DefaultHistoryMaxPageSize:    1000,
 		RPS:                1200, // This limit is based on experimental runs.
 		HistoryMgrNumConns: 10,
+		PayloadOffloader:   common.NewNoopPayloadOffloader(),
 	}
 }
 
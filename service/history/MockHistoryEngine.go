@@ -22,10 +22,12 @@ package history
 
 import (
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	gohistory "github.com/uber/cadence/.gen/go/history"
 	"github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common/persistence"
 )
 
 // MockHistoryEngine is used as mock implementation for HistoryEngine
@@ -182,17 +184,26 @@ func (_m *MockHistoryEngine) RecordActivityTaskStarted(request *gohistory.Record
 }
 
 // RespondDecisionTaskCompleted is mock implementation for RespondDecisionTaskCompleted of HistoryEngine
-func (_m *MockHistoryEngine) RespondDecisionTaskCompleted(ctx context.Context, request *gohistory.RespondDecisionTaskCompletedRequest) error {
+func (_m *MockHistoryEngine) RespondDecisionTaskCompleted(ctx context.Context, request *gohistory.RespondDecisionTaskCompletedRequest) (*gohistory.RespondDecisionTaskCompletedResponse, error) {
 	ret := _m.Called(ctx, request)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*gohistory.RespondDecisionTaskCompletedRequest) error); ok {
+	var r0 *gohistory.RespondDecisionTaskCompletedResponse
+	if rf, ok := ret.Get(0).(func(*gohistory.RespondDecisionTaskCompletedRequest) *gohistory.RespondDecisionTaskCompletedResponse); ok {
 		r0 = rf(request)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gohistory.RespondDecisionTaskCompletedResponse)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*gohistory.RespondDecisionTaskCompletedRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // RespondDecisionTaskFailed is mock implementation for RespondDecisionTaskFailed of HistoryEngine
@@ -275,17 +286,26 @@ func (_m *MockHistoryEngine) RecordActivityTaskHeartbeat(request *gohistory.Reco
 }
 
 // RequestCancelWorkflowExecution is mock implementation for RequestCancelWorkflowExecution of HistoryEngine
-func (_m *MockHistoryEngine) RequestCancelWorkflowExecution(request *gohistory.RequestCancelWorkflowExecutionRequest) error {
+func (_m *MockHistoryEngine) RequestCancelWorkflowExecution(request *gohistory.RequestCancelWorkflowExecutionRequest) (*shared.RequestCancelWorkflowExecutionResponse, error) {
 	ret := _m.Called(request)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*gohistory.RequestCancelWorkflowExecutionRequest) error); ok {
+	var r0 *shared.RequestCancelWorkflowExecutionResponse
+	if rf, ok := ret.Get(0).(func(*gohistory.RequestCancelWorkflowExecutionRequest) *shared.RequestCancelWorkflowExecutionResponse); ok {
 		r0 = rf(request)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shared.RequestCancelWorkflowExecutionResponse)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*gohistory.RequestCancelWorkflowExecutionRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // SignalWorkflowExecution is mock implementation for SignalWorkflowExecution of HistoryEngine
@@ -396,4 +416,370 @@ func (_m *MockHistoryEngine) ReplicateEvents(request *gohistory.ReplicateEventsR
 	return r0
 }
 
+// ValidateDecisions is mock implementation for ValidateDecisions of HistoryEngine
+func (_m *MockHistoryEngine) ValidateDecisions(domainID string,
+	decisions []*shared.Decision) ([]*DecisionValidationResult, error) {
+	ret := _m.Called(domainID, decisions)
+
+	var r0 []*DecisionValidationResult
+	if rf, ok := ret.Get(0).(func(string, []*shared.Decision) []*DecisionValidationResult); ok {
+		r0 = rf(domainID, decisions)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*DecisionValidationResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []*shared.Decision) error); ok {
+		r1 = rf(domainID, decisions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetQueueLag is mock implementation for GetQueueLag of HistoryEngine
+func (_m *MockHistoryEngine) GetQueueLag() *QueueLag {
+	ret := _m.Called()
+
+	var r0 *QueueLag
+	if rf, ok := ret.Get(0).(func() *QueueLag); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*QueueLag)
+		}
+	}
+
+	return r0
+}
+
+// GetShardStats is mock implementation for GetShardStats of HistoryEngine
+func (_m *MockHistoryEngine) GetShardStats() *ShardStats {
+	ret := _m.Called()
+
+	var r0 *ShardStats
+	if rf, ok := ret.Get(0).(func() *ShardStats); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ShardStats)
+		}
+	}
+
+	return r0
+}
+
+// GetWorkflowExecutionRawHistory is mock implementation for GetWorkflowExecutionRawHistory of HistoryEngine
+func (_m *MockHistoryEngine) GetWorkflowExecutionRawHistory(request *RawHistoryRequest) (*RawHistoryResponse, error) {
+	ret := _m.Called(request)
+
+	var r0 *RawHistoryResponse
+	if rf, ok := ret.Get(0).(func(*RawHistoryRequest) *RawHistoryResponse); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*RawHistoryResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*RawHistoryRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkflowExecutionHistoryWindow is mock implementation for GetWorkflowExecutionHistoryWindow of HistoryEngine
+func (_m *MockHistoryEngine) GetWorkflowExecutionHistoryWindow(request *HistoryWindowRequest) (*shared.History, error) {
+	ret := _m.Called(request)
+
+	var r0 *shared.History
+	if rf, ok := ret.Get(0).(func(*HistoryWindowRequest) *shared.History); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shared.History)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*HistoryWindowRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReconcileCurrentExecution is mock implementation for ReconcileCurrentExecution of HistoryEngine
+func (_m *MockHistoryEngine) ReconcileCurrentExecution(domainID string, workflowID string) (bool, error) {
+	ret := _m.Called(domainID, workflowID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string) bool); ok {
+		r0 = rf(domainID, workflowID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(domainID, workflowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHistoryEvent is mock implementation for GetHistoryEvent of HistoryEngine
+func (_m *MockHistoryEngine) GetHistoryEvent(domainID string, execution shared.WorkflowExecution,
+	eventID int64) (*shared.HistoryEvent, error) {
+	ret := _m.Called(domainID, execution, eventID)
+
+	var r0 *shared.HistoryEvent
+	if rf, ok := ret.Get(0).(func(string, shared.WorkflowExecution, int64) *shared.HistoryEvent); ok {
+		r0 = rf(domainID, execution, eventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shared.HistoryEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, shared.WorkflowExecution, int64) error); ok {
+		r1 = rf(domainID, execution, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetActivityScheduledEvent is mock implementation for GetActivityScheduledEvent of HistoryEngine
+func (_m *MockHistoryEngine) GetActivityScheduledEvent(domainID string, execution shared.WorkflowExecution,
+	scheduleID int64) (*shared.ActivityTaskScheduledEventAttributes, error) {
+	ret := _m.Called(domainID, execution, scheduleID)
+
+	var r0 *shared.ActivityTaskScheduledEventAttributes
+	if rf, ok := ret.Get(0).(func(string, shared.WorkflowExecution, int64) *shared.ActivityTaskScheduledEventAttributes); ok {
+		r0 = rf(domainID, execution, scheduleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shared.ActivityTaskScheduledEventAttributes)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, shared.WorkflowExecution, int64) error); ok {
+		r1 = rf(domainID, execution, scheduleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DescribeActivity is mock implementation for DescribeActivity of HistoryEngine
+func (_m *MockHistoryEngine) DescribeActivity(domainID string, execution shared.WorkflowExecution,
+	activityID string) (*ActivityDescription, error) {
+	ret := _m.Called(domainID, execution, activityID)
+
+	var r0 *ActivityDescription
+	if rf, ok := ret.Get(0).(func(string, shared.WorkflowExecution, string) *ActivityDescription); ok {
+		r0 = rf(domainID, execution, activityID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ActivityDescription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, shared.WorkflowExecution, string) error); ok {
+		r1 = rf(domainID, execution, activityID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkflowExecutionResult is mock implementation for GetWorkflowExecutionResult of HistoryEngine
+func (_m *MockHistoryEngine) GetWorkflowExecutionResult(domainID string,
+	execution shared.WorkflowExecution) (*WorkflowExecutionResult, error) {
+	ret := _m.Called(domainID, execution)
+
+	var r0 *WorkflowExecutionResult
+	if rf, ok := ret.Get(0).(func(string, shared.WorkflowExecution) *WorkflowExecutionResult); ok {
+		r0 = rf(domainID, execution)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*WorkflowExecutionResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, shared.WorkflowExecution) error); ok {
+		r1 = rf(domainID, execution)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ForceFailDecisionTask is mock implementation for ForceFailDecisionTask of HistoryEngine
+func (_m *MockHistoryEngine) ForceFailDecisionTask(domainID string, execution shared.WorkflowExecution,
+	identity string) error {
+	ret := _m.Called(domainID, execution, identity)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, shared.WorkflowExecution, string) error); ok {
+		r0 = rf(domainID, execution, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetWorkflowExecutionPaused is mock implementation for SetWorkflowExecutionPaused of HistoryEngine
+func (_m *MockHistoryEngine) SetWorkflowExecutionPaused(domainID string, execution shared.WorkflowExecution,
+	paused bool) error {
+	ret := _m.Called(domainID, execution, paused)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, shared.WorkflowExecution, bool) error); ok {
+		r0 = rf(domainID, execution, paused)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetWorkflowExecutionOperatorTags is mock implementation for SetWorkflowExecutionOperatorTags of HistoryEngine
+func (_m *MockHistoryEngine) SetWorkflowExecutionOperatorTags(domainID string, execution shared.WorkflowExecution,
+	setTags map[string]string, clearTags []string) error {
+	ret := _m.Called(domainID, execution, setTags, clearTags)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, shared.WorkflowExecution, map[string]string, []string) error); ok {
+		r0 = rf(domainID, execution, setTags, clearTags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ForceDeleteWorkflowExecution is mock implementation for ForceDeleteWorkflowExecution of HistoryEngine
+func (_m *MockHistoryEngine) ForceDeleteWorkflowExecution(request *ForceDeleteRequest) error {
+	ret := _m.Called(request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*ForceDeleteRequest) error); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TerminateAndStart is mock implementation for TerminateAndStart of HistoryEngine
+func (_m *MockHistoryEngine) TerminateAndStart(request *TerminateAndStartRequest) (
+	*shared.StartWorkflowExecutionResponse, error) {
+	ret := _m.Called(request)
+
+	var r0 *shared.StartWorkflowExecutionResponse
+	if rf, ok := ret.Get(0).(func(*TerminateAndStartRequest) *shared.StartWorkflowExecutionResponse); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shared.StartWorkflowExecutionResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*TerminateAndStartRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedriveTransferTasks is mock implementation for RedriveTransferTasks of HistoryEngine
+func (_m *MockHistoryEngine) RedriveTransferTasks(domainID string, execution shared.WorkflowExecution) (int, error) {
+	ret := _m.Called(domainID, execution)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string, shared.WorkflowExecution) int); ok {
+		r0 = rf(domainID, execution)
+	} else {
+		r0 = ret.Int(0)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, shared.WorkflowExecution) error); ok {
+		r1 = rf(domainID, execution)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PauseTimerProcessing is mock implementation for PauseTimerProcessing of HistoryEngine
+func (_m *MockHistoryEngine) PauseTimerProcessing(duration time.Duration) time.Time {
+	ret := _m.Called(duration)
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func(time.Duration) time.Time); ok {
+		r0 = rf(duration)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
+// ResumeTimerProcessing is mock implementation for ResumeTimerProcessing of HistoryEngine
+func (_m *MockHistoryEngine) ResumeTimerProcessing() {
+	_m.Called()
+}
+
+// ListOpenExecutions is mock implementation for ListOpenExecutions of HistoryEngine
+func (_m *MockHistoryEngine) ListOpenExecutions(pageSize int,
+	nextPageToken []byte) (*persistence.ListOpenExecutionsResponse, error) {
+	ret := _m.Called(pageSize, nextPageToken)
+
+	var r0 *persistence.ListOpenExecutionsResponse
+	if rf, ok := ret.Get(0).(func(int, []byte) *persistence.ListOpenExecutionsResponse); ok {
+		r0 = rf(pageSize, nextPageToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.ListOpenExecutionsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, []byte) error); ok {
+		r1 = rf(pageSize, nextPageToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 var _ Engine = (*MockHistoryEngine)(nil)
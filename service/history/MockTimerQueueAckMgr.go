@@ -110,6 +110,26 @@ func (_m *MockTimerQueueAckMgr) updateAckLevel() {
 	_m.Called()
 }
 
+func (_m *MockTimerQueueAckMgr) getOldestPendingTimer() (time.Time, bool) {
+	ret := _m.Called()
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
 func (_m *MockTimerQueueAckMgr) isProcessNow(expiryTime time.Time) bool {
 	ret := _m.Called(expiryTime)
 
@@ -0,0 +1,116 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+const (
+	conflictCauseSignal   = "signal"
+	conflictCauseActivity = "activity"
+	conflictCauseTimer    = "timer"
+	conflictCauseUnknown  = "unknown"
+)
+
+type (
+	// decisionConflictSnapshot captures the parts of mutable state other writers change while a decision is in
+	// flight, so that after a conflict the reloaded state can be compared against it to tell which writer most
+	// likely raced with the decision completion.  Only counts are kept to keep the comparison cheap.
+	decisionConflictSnapshot struct {
+		signalCount          int64
+		pendingActivities    int
+		startedActivities    int
+		pendingTimers        int
+		bufferedEventBatches int
+	}
+)
+
+func newDecisionConflictSnapshot(msBuilder *mutableStateBuilder) *decisionConflictSnapshot {
+	return &decisionConflictSnapshot{
+		signalCount:          msBuilder.executionInfo.SignalCount,
+		pendingActivities:    len(msBuilder.pendingActivityInfoIDs),
+		startedActivities:    countStartedActivities(msBuilder),
+		pendingTimers:        len(msBuilder.pendingTimerInfoIDs),
+		bufferedEventBatches: len(msBuilder.bufferedEvents),
+	}
+}
+
+// classify compares the reloaded mutable state against the snapshot and returns the likely cause of the conflict.
+// Events buffered since the snapshot are the most precise signal, so only the newly buffered batches are inspected
+// before falling back to the pending activity and timer counts.
+func (s *decisionConflictSnapshot) classify(msBuilder *mutableStateBuilder) string {
+	if msBuilder.executionInfo.SignalCount > s.signalCount {
+		return conflictCauseSignal
+	}
+
+	if len(msBuilder.bufferedEvents) > s.bufferedEventBatches {
+		for _, bufferedEventBatch := range msBuilder.bufferedEvents[s.bufferedEventBatches:] {
+			eventBatch, err := msBuilder.hBuilder.serializer.Deserialize(bufferedEventBatch)
+			if err != nil {
+				break
+			}
+			for _, event := range eventBatch.Events {
+				if cause, ok := conflictCauseByEventType(event.GetEventType()); ok {
+					return cause
+				}
+			}
+		}
+	}
+
+	if len(msBuilder.pendingActivityInfoIDs) < s.pendingActivities ||
+		countStartedActivities(msBuilder) > s.startedActivities {
+		return conflictCauseActivity
+	}
+
+	if len(msBuilder.pendingTimerInfoIDs) < s.pendingTimers {
+		return conflictCauseTimer
+	}
+
+	return conflictCauseUnknown
+}
+
+func countStartedActivities(msBuilder *mutableStateBuilder) int {
+	startedActivities := 0
+	for _, ai := range msBuilder.pendingActivityInfoIDs {
+		if ai.StartedID != emptyEventID {
+			startedActivities++
+		}
+	}
+	return startedActivities
+}
+
+func conflictCauseByEventType(eventType workflow.EventType) (string, bool) {
+	switch eventType {
+	case workflow.EventTypeWorkflowExecutionSignaled:
+		return conflictCauseSignal, true
+	case workflow.EventTypeActivityTaskStarted,
+		workflow.EventTypeActivityTaskCompleted,
+		workflow.EventTypeActivityTaskFailed,
+		workflow.EventTypeActivityTaskTimedOut,
+		workflow.EventTypeActivityTaskCanceled:
+		return conflictCauseActivity, true
+	case workflow.EventTypeTimerFired:
+		return conflictCauseTimer, true
+	default:
+		return "", false
+	}
+}
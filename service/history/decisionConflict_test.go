@@ -0,0 +1,135 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-common/bark"
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+type (
+	decisionConflictSuite struct {
+		suite.Suite
+		// override suite.Suite.Assertions with require.Assertions; this means that s.NotNil(nil) will stop the test,
+		// not merely log an error
+		*require.Assertions
+		msBuilder          *mutableStateBuilder
+		activityScheduleID int64
+		activityStartedID  int64
+		timerStartedID     int64
+	}
+)
+
+func TestDecisionConflictSuite(t *testing.T) {
+	s := new(decisionConflictSuite)
+	suite.Run(t, s)
+}
+
+func (s *decisionConflictSuite) SetupTest() {
+	// Have to define our overridden assertions in the test setup. If we did it earlier, s.T() will return nil
+	s.Assertions = require.New(s.T())
+
+	execution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("decision-conflict-test-workflow-id"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	s.msBuilder = newMutableStateBuilder(NewConfig(dynamicconfig.NewNopCollection(), 1),
+		bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(s.msBuilder, execution, "wType", tl, []byte("input"), 100, 10, identity)
+	di := addDecisionTaskScheduledEvent(s.msBuilder)
+	startedEvent := addDecisionTaskStartedEvent(s.msBuilder, di.ScheduleID, tl, identity)
+	completedEvent := addDecisionTaskCompletedEvent(s.msBuilder, di.ScheduleID, startedEvent.GetEventId(), nil, identity)
+	activityScheduledEvent, _ := addActivityTaskScheduledEvent(s.msBuilder, completedEvent.GetEventId(), "activity1",
+		"activity_type1", tl, []byte("input1"), 100, 10, 5)
+	activityStartedEvent := addActivityTaskStartedEvent(s.msBuilder, activityScheduledEvent.GetEventId(), tl, identity)
+	timerStartedEvent, _ := addTimerStartedEvent(s.msBuilder, completedEvent.GetEventId(), "timer1", 10)
+	s.activityScheduleID = activityScheduledEvent.GetEventId()
+	s.activityStartedID = activityStartedEvent.GetEventId()
+	s.timerStartedID = timerStartedEvent.GetEventId()
+
+	// a second decision is in flight, so events added from here on are buffered
+	di2 := addDecisionTaskScheduledEvent(s.msBuilder)
+	addDecisionTaskStartedEvent(s.msBuilder, di2.ScheduleID, tl, identity)
+	s.closeUpdateSession()
+}
+
+func (s *decisionConflictSuite) closeUpdateSession() {
+	_, err := s.msBuilder.CloseUpdateSession()
+	s.Nil(err)
+}
+
+func (s *decisionConflictSuite) TestClassify_Unchanged() {
+	snapshot := newDecisionConflictSnapshot(s.msBuilder)
+	s.Equal(conflictCauseUnknown, snapshot.classify(s.msBuilder))
+}
+
+func (s *decisionConflictSuite) TestClassify_Signal() {
+	snapshot := newDecisionConflictSnapshot(s.msBuilder)
+
+	s.msBuilder.AddWorkflowExecutionSignaled(&workflow.SignalWorkflowExecutionRequest{
+		SignalName: common.StringPtr("signal1"),
+	})
+	s.closeUpdateSession()
+
+	s.Equal(conflictCauseSignal, snapshot.classify(s.msBuilder))
+}
+
+func (s *decisionConflictSuite) TestClassify_ActivityCompleted() {
+	snapshot := newDecisionConflictSnapshot(s.msBuilder)
+
+	addActivityTaskCompletedEvent(s.msBuilder, s.activityScheduleID, s.activityStartedID, nil, "testIdentity")
+	s.closeUpdateSession()
+
+	s.Len(s.msBuilder.bufferedEvents, 1)
+	s.Equal(conflictCauseActivity, snapshot.classify(s.msBuilder))
+}
+
+func (s *decisionConflictSuite) TestClassify_TimerFired() {
+	snapshot := newDecisionConflictSnapshot(s.msBuilder)
+
+	addTimerFiredEvent(s.msBuilder, s.timerStartedID, "timer1")
+	s.closeUpdateSession()
+
+	s.Len(s.msBuilder.bufferedEvents, 1)
+	s.Equal(conflictCauseTimer, snapshot.classify(s.msBuilder))
+}
+
+func (s *decisionConflictSuite) TestClassify_FallsBackToPendingCounts() {
+	snapshot := newDecisionConflictSnapshot(s.msBuilder)
+
+	// nothing is buffered, but the activity is gone from the reloaded state
+	s.Nil(s.msBuilder.DeleteActivity(s.activityScheduleID))
+	s.Equal(conflictCauseActivity, snapshot.classify(s.msBuilder))
+
+	snapshot = newDecisionConflictSnapshot(s.msBuilder)
+	s.msBuilder.DeleteUserTimer("timer1")
+	s.Equal(conflictCauseTimer, snapshot.classify(s.msBuilder))
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"sync"
+
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+type (
+	// domainIsolationGroup shares a fixed number of concurrent slots of a shard between domains.  A domain with work
+	// in flight is held to a share of the slots proportional to its weight among all domains with work in flight,
+	// so a domain which is alone on the shard can use every slot, but cannot keep other domains from their share.
+	// Work turned away can be parked with the group, it is handed back once the domain has a slot for it again.
+	domainIsolationGroup struct {
+		shard         ShardContext
+		capacity      int
+		metricsScope  int
+		metricsClient metrics.Client
+
+		sync.Mutex
+		inFlight    map[string]int
+		weights     map[string]int
+		totalWeight int
+		backlog     map[string][]interface{}
+	}
+)
+
+func newDomainIsolationGroup(shard ShardContext, capacity int, metricsScope int) *domainIsolationGroup {
+	return &domainIsolationGroup{
+		shard:         shard,
+		capacity:      capacity,
+		metricsScope:  metricsScope,
+		metricsClient: shard.GetMetricsClient(),
+		inFlight:      make(map[string]int),
+		weights:       make(map[string]int),
+		backlog:       make(map[string][]interface{}),
+	}
+}
+
+// tryAcquire reserves a slot for the domain unless the domain already holds its share of the slots.  The returned
+// function must be called to give the slot back once the work is done.  A nil group does not limit domains.
+func (g *domainIsolationGroup) tryAcquire(domainID string) (func(), bool) {
+	release, ok := g.acquireOrPark(domainID, nil)
+	if !ok {
+		return nil, false
+	}
+	return func() { release() }, true
+}
+
+// acquireOrPark reserves a slot for the domain like tryAcquire does.  When the domain already holds its share of the
+// slots, a non nil task is parked instead of being turned away.  The returned function gives the slot back, unless a
+// task of the domain is parked: that task then takes the slot over and is returned, so that the caller goes on with
+// it.  A parked task is always handed back this way, as a domain which is turned away has work in flight.
+func (g *domainIsolationGroup) acquireOrPark(domainID string, task interface{}) (func() interface{}, bool) {
+	if g == nil || g.capacity <= 0 || !g.shard.GetConfig().EnableDomainIsolation() {
+		return func() interface{} { return nil }, true
+	}
+
+	config := g.shard.GetConfig()
+
+	domainName := getMetricsDomainName(g.shard, domainID)
+	metricsClient := g.metricsClient.Tagged(map[string]string{metrics.DomainTagName: domainName})
+	weight := config.DomainIsolationWeight(dynamicconfig.DomainFilter(domainName))
+	if weight < 1 {
+		weight = 1
+	}
+
+	g.Lock()
+	inFlight := g.inFlight[domainID]
+	totalWeight := g.totalWeight
+	if inFlight == 0 {
+		totalWeight += weight
+	} else {
+		// the weight is fixed while the domain has work in flight, so that it is given back as it was taken
+		weight = g.weights[domainID]
+	}
+	if inFlight >= g.getShare(weight, totalWeight) {
+		if task != nil {
+			g.backlog[domainID] = append(g.backlog[domainID], task)
+		}
+		g.Unlock()
+		metricsClient.IncCounter(g.metricsScope, metrics.DomainIsolationThrottledCounter)
+		return nil, false
+	}
+	if inFlight == 0 {
+		g.weights[domainID] = weight
+		g.totalWeight = totalWeight
+	}
+	inFlight++
+	g.inFlight[domainID] = inFlight
+	g.Unlock()
+
+	metricsClient.UpdateGauge(g.metricsScope, metrics.DomainIsolationInFlightGauge, float64(inFlight))
+	return func() interface{} { return g.release(domainID, metricsClient) }, true
+}
+
+func (g *domainIsolationGroup) release(domainID string, metricsClient metrics.Client) interface{} {
+	g.Lock()
+	inFlight := g.inFlight[domainID] - 1
+	// the slot goes to the next parked task of the domain as long as the domain is within its share, otherwise the
+	// task stays parked for one of the remaining tasks of the domain in flight
+	var next interface{}
+	parked := g.backlog[domainID]
+	if len(parked) > 0 && inFlight < g.getShare(g.weights[domainID], g.totalWeight) {
+		next = parked[0]
+		if len(parked) > 1 {
+			g.backlog[domainID] = parked[1:]
+		} else {
+			delete(g.backlog, domainID)
+		}
+		inFlight++
+	}
+	if inFlight > 0 {
+		g.inFlight[domainID] = inFlight
+	} else {
+		g.totalWeight -= g.weights[domainID]
+		delete(g.inFlight, domainID)
+		delete(g.weights, domainID)
+	}
+	g.Unlock()
+
+	metricsClient.UpdateGauge(g.metricsScope, metrics.DomainIsolationInFlightGauge, float64(inFlight))
+	return next
+}
+
+func (g *domainIsolationGroup) getShare(weight int, totalWeight int) int {
+	share := g.capacity * weight / totalWeight
+	if share < 1 {
+		share = 1
+	}
+	return share
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"os"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-common/bark"
+	"github.com/uber-go/tally"
+	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/cluster"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/mocks"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+type (
+	domainIsolationSuite struct {
+		suite.Suite
+		// override suite.Suite.Assertions with require.Assertions; this means that s.NotNil(nil) will stop the test,
+		// not merely log an error
+		*require.Assertions
+		mockMetadataMgr *mocks.MetadataManager
+		mockShard       *shardContextImpl
+	}
+)
+
+const (
+	noisyDomainID = "noisy-domain-id"
+	quietDomainID = "quiet-domain-id"
+)
+
+func TestDomainIsolationSuite(t *testing.T) {
+	s := new(domainIsolationSuite)
+	suite.Run(t, s)
+}
+
+func (s *domainIsolationSuite) SetupSuite() {
+	if testing.Verbose() {
+		log.SetOutput(os.Stdout)
+	}
+}
+
+func (s *domainIsolationSuite) SetupTest() {
+	// Have to define our overridden assertions in the test setup. If we did it earlier, s.T() will return nil
+	s.Assertions = require.New(s.T())
+	logger := bark.NewLoggerFromLogrus(log.New())
+	s.mockMetadataMgr = &mocks.MetadataManager{}
+	for domainID, domainName := range map[string]string{noisyDomainID: "noisy", quietDomainID: "quiet"} {
+		s.mockMetadataMgr.On("GetDomain", &persistence.GetDomainRequest{ID: domainID}).Return(
+			&persistence.GetDomainResponse{
+				Info:              &persistence.DomainInfo{ID: domainID, Name: domainName},
+				Config:            &persistence.DomainConfig{Retention: 1},
+				ReplicationConfig: &persistence.DomainReplicationConfig{ActiveClusterName: cluster.TestCurrentClusterName},
+			}, nil)
+	}
+	s.mockShard = &shardContextImpl{
+		shardInfo:     &persistence.ShardInfo{ShardID: 0, RangeID: 1},
+		config:        NewConfig(dynamicconfig.NewNopCollection(), 1),
+		logger:        logger,
+		domainCache:   cache.NewDomainCache(s.mockMetadataMgr, &mocks.ClusterMetadata{}, logger),
+		metricsClient: metrics.NewClient(tally.NoopScope, metrics.History),
+	}
+	s.mockShard.config.EnableDomainIsolation = func(...dynamicconfig.FilterOption) bool { return true }
+}
+
+func (s *domainIsolationSuite) TestTryAcquire_Disabled() {
+	s.mockShard.config.EnableDomainIsolation = func(...dynamicconfig.FilterOption) bool { return false }
+	group := newDomainIsolationGroup(s.mockShard, 1, metrics.HistoryWorkflowUpdateScope)
+
+	for i := 0; i < 3; i++ {
+		_, ok := group.tryAcquire(noisyDomainID)
+		s.True(ok)
+	}
+}
+
+func (s *domainIsolationSuite) TestTryAcquire_AloneUsesAllSlots() {
+	group := newDomainIsolationGroup(s.mockShard, 4, metrics.HistoryWorkflowUpdateScope)
+
+	var releases []func()
+	for i := 0; i < 4; i++ {
+		release, ok := group.tryAcquire(noisyDomainID)
+		s.True(ok)
+		releases = append(releases, release)
+	}
+	_, ok := group.tryAcquire(noisyDomainID)
+	s.False(ok)
+
+	for _, release := range releases {
+		release()
+	}
+	s.Empty(group.inFlight)
+	s.Equal(0, group.totalWeight)
+}
+
+func (s *domainIsolationSuite) TestTryAcquire_NoisyDomainHeldToShare() {
+	group := newDomainIsolationGroup(s.mockShard, 4, metrics.HistoryWorkflowUpdateScope)
+
+	var noisyReleases []func()
+	for i := 0; i < 3; i++ {
+		release, ok := group.tryAcquire(noisyDomainID)
+		s.True(ok)
+		noisyReleases = append(noisyReleases, release)
+	}
+
+	// a quiet domain still gets its share even though the noisy one got there first
+	releaseQuiet, ok := group.tryAcquire(quietDomainID)
+	s.True(ok)
+
+	// the noisy domain is now held to half of the slots until it gives enough of them back
+	_, ok = group.tryAcquire(noisyDomainID)
+	s.False(ok)
+	noisyReleases[0]()
+	_, ok = group.tryAcquire(noisyDomainID)
+	s.False(ok)
+	noisyReleases[1]()
+	releaseNoisy, ok := group.tryAcquire(noisyDomainID)
+	s.True(ok)
+
+	releaseNoisy()
+	noisyReleases[2]()
+	releaseQuiet()
+	s.Empty(group.inFlight)
+}
+
+func (s *domainIsolationSuite) TestTryAcquire_Weighted() {
+	s.mockShard.config.DomainIsolationWeight = func(opts ...dynamicconfig.FilterOption) int {
+		filters := make(map[dynamicconfig.Filter]interface{})
+		for _, opt := range opts {
+			opt(filters)
+		}
+		if filters[dynamicconfig.DomainName] == "quiet" {
+			return 3
+		}
+		return 1
+	}
+	group := newDomainIsolationGroup(s.mockShard, 4, metrics.HistoryWorkflowUpdateScope)
+
+	_, ok := group.tryAcquire(noisyDomainID)
+	s.True(ok)
+	for i := 0; i < 3; i++ {
+		_, ok = group.tryAcquire(quietDomainID)
+		s.True(ok)
+	}
+	_, ok = group.tryAcquire(quietDomainID)
+	s.False(ok)
+	_, ok = group.tryAcquire(noisyDomainID)
+	s.False(ok)
+}
+
+func (s *domainIsolationSuite) TestAcquireOrPark_ParkedTaskTakesOverSlot() {
+	group := newDomainIsolationGroup(s.mockShard, 2, metrics.TransferQueueProcessorScope)
+
+	releaseNoisy, ok := group.acquireOrPark(noisyDomainID, "noisy1")
+	s.True(ok)
+	releaseQuiet, ok := group.acquireOrPark(quietDomainID, "quiet1")
+	s.True(ok)
+
+	// the noisy domain is held to its share, its tasks are parked in order
+	_, ok = group.acquireOrPark(noisyDomainID, "noisy2")
+	s.False(ok)
+	_, ok = group.acquireOrPark(noisyDomainID, "noisy3")
+	s.False(ok)
+
+	// each completed task of the noisy domain hands its slot over to the next parked one
+	s.Equal("noisy2", releaseNoisy())
+	s.Equal(1, group.inFlight[noisyDomainID])
+	s.Equal("noisy3", releaseNoisy())
+	s.Nil(releaseNoisy())
+	s.Nil(releaseQuiet())
+	s.Empty(group.inFlight)
+	s.Empty(group.backlog)
+	s.Equal(0, group.totalWeight)
+}
+
+func (s *domainIsolationSuite) TestAcquireOrPark_ParkedTaskWaitsForShare() {
+	group := newDomainIsolationGroup(s.mockShard, 4, metrics.TransferQueueProcessorScope)
+
+	var noisyReleases []func() interface{}
+	for i := 0; i < 3; i++ {
+		release, ok := group.acquireOrPark(noisyDomainID, i)
+		s.True(ok)
+		noisyReleases = append(noisyReleases, release)
+	}
+	releaseQuiet, ok := group.acquireOrPark(quietDomainID, "quiet")
+	s.True(ok)
+	_, ok = group.acquireOrPark(noisyDomainID, "parked")
+	s.False(ok)
+
+	// the noisy domain is above its share of half of the slots, so the parked task waits for another slot to free up
+	s.Nil(noisyReleases[0]())
+	s.Equal("parked", noisyReleases[1]())
+	s.Equal(2, group.inFlight[noisyDomainID])
+	s.Empty(group.backlog)
+
+	// the parked task gives the slot it took over back through the release of the task it took it from
+	s.Nil(noisyReleases[1]())
+	s.Nil(noisyReleases[2]())
+	s.Nil(releaseQuiet())
+	s.Empty(group.inFlight)
+}
@@ -24,6 +24,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/pborman/uuid"
 	"github.com/uber/cadence/.gen/go/health"
@@ -73,6 +74,14 @@ var (
 	errTaskListNotSet          = &gen.BadRequestError{Message: "Tasklist not set."}
 	errWorkflowIDNotSet        = &gen.BadRequestError{Message: "WorkflowId is not set on request."}
 	errRunIDNotValid           = &gen.BadRequestError{Message: "RunID is not valid UUID."}
+	errWorkflowTypeNotSet      = &gen.BadRequestError{Message: "WorkflowType is not set on request."}
+	errActivityIDNotSet        = &gen.BadRequestError{Message: "ActivityID is not set on request."}
+)
+
+const (
+	// resetStickyTaskListBatchThrottleInterval is how long a batched reset waits for the rate limiter before checking
+	// whether the request was cancelled
+	resetStickyTaskListBatchThrottleInterval = time.Second
 )
 
 // NewHandler creates a thrift handler for the history service
@@ -399,7 +408,7 @@ func (h *Handler) RespondActivityTaskCanceled(ctx context.Context,
 
 // RespondDecisionTaskCompleted - records completion of a decision task
 func (h *Handler) RespondDecisionTaskCompleted(ctx context.Context,
-	wrappedRequest *hist.RespondDecisionTaskCompletedRequest) error {
+	wrappedRequest *hist.RespondDecisionTaskCompletedRequest) (*hist.RespondDecisionTaskCompletedResponse, error) {
 	h.startWG.Wait()
 
 	h.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.CadenceRequests)
@@ -407,7 +416,7 @@ func (h *Handler) RespondDecisionTaskCompleted(ctx context.Context,
 	defer sw.Stop()
 
 	if wrappedRequest.GetDomainUUID() == "" {
-		return errDomainNotSet
+		return nil, errDomainNotSet
 	}
 
 	completeRequest := wrappedRequest.CompleteRequest
@@ -415,7 +424,7 @@ func (h *Handler) RespondDecisionTaskCompleted(ctx context.Context,
 	if err0 != nil {
 		err0 = &gen.BadRequestError{Message: fmt.Sprintf("Error deserializing task token. Error: %v", err0)}
 		h.updateErrorMetric(metrics.HistoryRespondDecisionTaskCompletedScope, err0)
-		return err0
+		return nil, err0
 	}
 
 	h.Service.GetLogger().Debugf("RespondDecisionTaskCompleted. DomainID: %v, WorkflowID: %v, RunID: %v, ScheduleID: %v",
@@ -426,22 +435,22 @@ func (h *Handler) RespondDecisionTaskCompleted(ctx context.Context,
 
 	err0 = validateTaskToken(token)
 	if err0 != nil {
-		return err0
+		return nil, err0
 	}
 
 	engine, err1 := h.controller.GetEngine(token.WorkflowID)
 	if err1 != nil {
 		h.updateErrorMetric(metrics.HistoryRespondDecisionTaskCompletedScope, err1)
-		return err1
+		return nil, err1
 	}
 
-	err2 := engine.RespondDecisionTaskCompleted(ctx, wrappedRequest)
+	response, err2 := engine.RespondDecisionTaskCompleted(ctx, wrappedRequest)
 	if err2 != nil {
 		h.updateErrorMetric(metrics.HistoryRespondDecisionTaskCompletedScope, h.convertError(err2))
-		return h.convertError(err2)
+		return nil, h.convertError(err2)
 	}
 
-	return nil
+	return response, nil
 }
 
 // RespondDecisionTaskFailed - failed response to decision task
@@ -578,7 +587,7 @@ func (h *Handler) DescribeWorkflowExecution(ctx context.Context, request *hist.D
 
 // RequestCancelWorkflowExecution - requests cancellation of a workflow
 func (h *Handler) RequestCancelWorkflowExecution(ctx context.Context,
-	request *hist.RequestCancelWorkflowExecutionRequest) error {
+	request *hist.RequestCancelWorkflowExecutionRequest) (*gen.RequestCancelWorkflowExecutionResponse, error) {
 	h.startWG.Wait()
 
 	h.metricsClient.IncCounter(metrics.HistoryRequestCancelWorkflowExecutionScope, metrics.CadenceRequests)
@@ -586,7 +595,7 @@ func (h *Handler) RequestCancelWorkflowExecution(ctx context.Context,
 	defer sw.Stop()
 
 	if request.GetDomainUUID() == "" || request.CancelRequest.GetDomain() == "" {
-		return errDomainNotSet
+		return nil, errDomainNotSet
 	}
 
 	cancelRequest := request.CancelRequest
@@ -599,16 +608,16 @@ func (h *Handler) RequestCancelWorkflowExecution(ctx context.Context,
 	engine, err1 := h.controller.GetEngine(cancelRequest.WorkflowExecution.GetWorkflowId())
 	if err1 != nil {
 		h.updateErrorMetric(metrics.HistoryRequestCancelWorkflowExecutionScope, err1)
-		return err1
+		return nil, err1
 	}
 
-	err2 := engine.RequestCancelWorkflowExecution(request)
+	response, err2 := engine.RequestCancelWorkflowExecution(request)
 	if err2 != nil {
 		h.updateErrorMetric(metrics.HistoryRequestCancelWorkflowExecutionScope, h.convertError(err2))
-		return h.convertError(err2)
+		return nil, h.convertError(err2)
 	}
 
-	return nil
+	return response, nil
 }
 
 // SignalWorkflowExecution is used to send a signal event to running workflow execution.  This results in
@@ -832,6 +841,696 @@ func (h *Handler) ResetStickyTaskList(ctx context.Context, resetRequest *hist.Re
 	return resp, nil
 }
 
+// ForceFailDecisionTask fails the started decision task of a workflow execution and schedules a new one, for operators
+// to unblock an execution whose worker is stuck on a decision
+func (h *Handler) ForceFailDecisionTask(ctx context.Context, domainID string, execution *gen.WorkflowExecution,
+	identity string) error {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryForceFailDecisionTaskScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryForceFailDecisionTaskScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if domainID == "" {
+		return errDomainNotSet
+	}
+	if execution == nil || execution.GetWorkflowId() == "" {
+		return errWorkflowIDNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(execution.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryForceFailDecisionTaskScope, err1)
+		return err1
+	}
+
+	err2 := engine.ForceFailDecisionTask(domainID, *execution, identity)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryForceFailDecisionTaskScope, h.convertError(err2))
+		return h.convertError(err2)
+	}
+	return nil
+}
+
+// SetWorkflowExecutionPaused pauses or resumes the scheduling of decision tasks for a workflow execution, e.g. while
+// a service the workflow depends on is down
+func (h *Handler) SetWorkflowExecutionPaused(ctx context.Context, domainID string, execution *gen.WorkflowExecution,
+	paused bool) error {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistorySetWorkflowExecutionPausedScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistorySetWorkflowExecutionPausedScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if domainID == "" {
+		return errDomainNotSet
+	}
+	if execution == nil || execution.GetWorkflowId() == "" {
+		return errWorkflowIDNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(execution.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistorySetWorkflowExecutionPausedScope, err1)
+		return err1
+	}
+
+	err2 := engine.SetWorkflowExecutionPaused(domainID, *execution, paused)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistorySetWorkflowExecutionPausedScope, h.convertError(err2))
+		return h.convertError(err2)
+	}
+	return nil
+}
+
+// SetWorkflowExecutionOperatorTags sets and clears the advisory tags operators attach to a workflow execution, e.g.
+// to mark an execution under investigation
+func (h *Handler) SetWorkflowExecutionOperatorTags(ctx context.Context, domainID string,
+	execution *gen.WorkflowExecution, setTags map[string]string, clearTags []string) error {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistorySetWorkflowExecutionOperatorTagsScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistorySetWorkflowExecutionOperatorTagsScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if domainID == "" {
+		return errDomainNotSet
+	}
+	if execution == nil || execution.GetWorkflowId() == "" {
+		return errWorkflowIDNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(execution.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistorySetWorkflowExecutionOperatorTagsScope, err1)
+		return err1
+	}
+
+	err2 := engine.SetWorkflowExecutionOperatorTags(domainID, *execution, setTags, clearTags)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistorySetWorkflowExecutionOperatorTagsScope, h.convertError(err2))
+		return h.convertError(err2)
+	}
+	return nil
+}
+
+// ForceDeleteWorkflowExecution deletes a workflow execution whose mutable state is inconsistent and which cannot be
+// closed through the other APIs, so operators can recover a shard without restarting hosts
+func (h *Handler) ForceDeleteWorkflowExecution(ctx context.Context, request *ForceDeleteRequest) error {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryForceDeleteWorkflowExecutionScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryForceDeleteWorkflowExecutionScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if request.DomainID == "" {
+		return errDomainNotSet
+	}
+	if request.Execution.GetWorkflowId() == "" {
+		return errWorkflowIDNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(request.Execution.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryForceDeleteWorkflowExecutionScope, err1)
+		return err1
+	}
+
+	err2 := engine.ForceDeleteWorkflowExecution(request)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryForceDeleteWorkflowExecutionScope, h.convertError(err2))
+		return h.convertError(err2)
+	}
+	return nil
+}
+
+// TerminateAndStart terminates the running execution of a workflow and starts a new run of it in one update, so no
+// other start can take the workflow id in between
+func (h *Handler) TerminateAndStart(ctx context.Context, request *TerminateAndStartRequest) (
+	*gen.StartWorkflowExecutionResponse, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryTerminateAndStartScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryTerminateAndStartScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if request.DomainID == "" {
+		return nil, errDomainNotSet
+	}
+	if request.StartRequest == nil || request.StartRequest.GetWorkflowId() == "" {
+		return nil, errWorkflowIDNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(request.StartRequest.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryTerminateAndStartScope, err1)
+		return nil, err1
+	}
+
+	response, err2 := engine.TerminateAndStart(request)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryTerminateAndStartScope, h.convertError(err2))
+		return nil, h.convertError(err2)
+	}
+	return response, nil
+}
+
+// RedriveTransferTasks writes the transfer tasks of the outstanding work of a workflow execution again, for operators
+// to retrigger activities, child executions, cancels and signals stuck on a downstream failure
+func (h *Handler) RedriveTransferTasks(ctx context.Context, domainID string,
+	execution *gen.WorkflowExecution) (int, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryRedriveTransferTasksScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryRedriveTransferTasksScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if domainID == "" {
+		return 0, errDomainNotSet
+	}
+	if execution == nil || execution.GetWorkflowId() == "" {
+		return 0, errWorkflowIDNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(execution.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryRedriveTransferTasksScope, err1)
+		return 0, err1
+	}
+
+	count, err2 := engine.RedriveTransferTasks(domainID, *execution)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryRedriveTransferTasksScope, h.convertError(err2))
+		return 0, h.convertError(err2)
+	}
+	return count, nil
+}
+
+// GetActivityScheduledEvent returns the attributes a pending activity of a workflow execution was scheduled with
+func (h *Handler) GetActivityScheduledEvent(ctx context.Context, domainID string, execution *gen.WorkflowExecution,
+	scheduleID int64) (*gen.ActivityTaskScheduledEventAttributes, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryGetActivityScheduledEventScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryGetActivityScheduledEventScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if domainID == "" {
+		return nil, errDomainNotSet
+	}
+	if execution == nil || execution.GetWorkflowId() == "" {
+		return nil, errWorkflowIDNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(execution.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryGetActivityScheduledEventScope, err1)
+		return nil, err1
+	}
+
+	attributes, err2 := engine.GetActivityScheduledEvent(domainID, *execution, scheduleID)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryGetActivityScheduledEventScope, h.convertError(err2))
+		return nil, h.convertError(err2)
+	}
+	return attributes, nil
+}
+
+// DescribeActivity returns the state of a single pending activity of a workflow execution
+func (h *Handler) DescribeActivity(ctx context.Context, domainID string, execution *gen.WorkflowExecution,
+	activityID string) (*ActivityDescription, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryDescribeActivityScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryDescribeActivityScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if domainID == "" {
+		return nil, errDomainNotSet
+	}
+	if execution == nil || execution.GetWorkflowId() == "" {
+		return nil, errWorkflowIDNotSet
+	}
+	if activityID == "" {
+		return nil, errActivityIDNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(execution.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryDescribeActivityScope, err1)
+		return nil, err1
+	}
+
+	description, err2 := engine.DescribeActivity(domainID, *execution, activityID)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryDescribeActivityScope, h.convertError(err2))
+		return nil, h.convertError(err2)
+	}
+	return description, nil
+}
+
+// ValidateDecisions validates a list of decisions of a workflow execution the way RespondDecisionTaskCompleted does,
+// without applying them.  The workflow id only routes the request to the shard of the execution, whose state is not
+// looked at.
+func (h *Handler) ValidateDecisions(ctx context.Context, domainID, workflowID string,
+	decisions []*gen.Decision) ([]*DecisionValidationResult, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryValidateDecisionsScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryValidateDecisionsScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if domainID == "" {
+		return nil, errDomainNotSet
+	}
+	if workflowID == "" {
+		return nil, errWorkflowIDNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(workflowID)
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryValidateDecisionsScope, err1)
+		return nil, err1
+	}
+
+	results, err2 := engine.ValidateDecisions(domainID, decisions)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryValidateDecisionsScope, h.convertError(err2))
+		return nil, h.convertError(err2)
+	}
+	return results, nil
+}
+
+// GetHistoryEvent returns a single event of the history of a workflow execution by its event id
+func (h *Handler) GetHistoryEvent(ctx context.Context, domainID string, execution *gen.WorkflowExecution,
+	eventID int64) (*gen.HistoryEvent, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryGetHistoryEventScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryGetHistoryEventScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if domainID == "" {
+		return nil, errDomainNotSet
+	}
+	if execution == nil || execution.GetWorkflowId() == "" {
+		return nil, errWorkflowIDNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(execution.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryGetHistoryEventScope, err1)
+		return nil, err1
+	}
+
+	event, err2 := engine.GetHistoryEvent(domainID, *execution, eventID)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryGetHistoryEventScope, h.convertError(err2))
+		return nil, h.convertError(err2)
+	}
+	return event, nil
+}
+
+// GetWorkflowExecutionResult returns the close status of a closed workflow execution along with the result, failure
+// or details recorded by its close event
+func (h *Handler) GetWorkflowExecutionResult(ctx context.Context, domainID string,
+	execution *gen.WorkflowExecution) (*WorkflowExecutionResult, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryGetWorkflowExecutionResultScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryGetWorkflowExecutionResultScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if domainID == "" {
+		return nil, errDomainNotSet
+	}
+	if execution == nil || execution.GetWorkflowId() == "" {
+		return nil, errWorkflowIDNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(execution.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryGetWorkflowExecutionResultScope, err1)
+		return nil, err1
+	}
+
+	result, err2 := engine.GetWorkflowExecutionResult(domainID, *execution)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryGetWorkflowExecutionResultScope, h.convertError(err2))
+		return nil, h.convertError(err2)
+	}
+	return result, nil
+}
+
+// ReconcileCurrentExecution repairs the current execution record of a workflow left pointing at a run which continued
+// as new, and returns whether a repair was needed
+func (h *Handler) ReconcileCurrentExecution(ctx context.Context, domainID, workflowID string) (bool, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryReconcileCurrentExecutionScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryReconcileCurrentExecutionScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if domainID == "" {
+		return false, errDomainNotSet
+	}
+	if workflowID == "" {
+		return false, errWorkflowIDNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(workflowID)
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryReconcileCurrentExecutionScope, err1)
+		return false, err1
+	}
+
+	repaired, err2 := engine.ReconcileCurrentExecution(domainID, workflowID)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryReconcileCurrentExecutionScope, h.convertError(err2))
+		return false, h.convertError(err2)
+	}
+	return repaired, nil
+}
+
+// PauseTimerProcessing stops firing timers on a shard owned by this host until ResumeTimerProcessing is called or
+// the duration, capped by the configured max pause duration, elapses.  The time at which timer processing resumes on
+// its own is returned.
+func (h *Handler) PauseTimerProcessing(ctx context.Context, shardID int, duration time.Duration) (time.Time, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryPauseTimerProcessingScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryPauseTimerProcessingScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	engine, err := h.controller.getEngineForShard(shardID)
+	if err != nil {
+		h.updateErrorMetric(metrics.HistoryPauseTimerProcessingScope, err)
+		return time.Time{}, err
+	}
+
+	return engine.PauseTimerProcessing(duration), nil
+}
+
+// ResumeTimerProcessing resumes timer processing paused by PauseTimerProcessing on a shard owned by this host
+func (h *Handler) ResumeTimerProcessing(ctx context.Context, shardID int) error {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryResumeTimerProcessingScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryResumeTimerProcessingScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	engine, err := h.controller.getEngineForShard(shardID)
+	if err != nil {
+		h.updateErrorMetric(metrics.HistoryResumeTimerProcessingScope, err)
+		return err
+	}
+
+	engine.ResumeTimerProcessing()
+	return nil
+}
+
+// ListShardOpenExecutions returns a page of the open workflow executions of a shard, so the executions owned by a shard
+// can be compared before and after it is moved to another host
+func (h *Handler) ListShardOpenExecutions(ctx context.Context, shardID int, pageSize int,
+	nextPageToken []byte) (*persistence.ListOpenExecutionsResponse, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryListShardOpenExecutionsScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryListShardOpenExecutionsScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	engine, err := h.controller.getEngineForShard(shardID)
+	if err != nil {
+		h.updateErrorMetric(metrics.HistoryListShardOpenExecutionsScope, err)
+		return nil, err
+	}
+
+	response, err := engine.ListOpenExecutions(pageSize, nextPageToken)
+	if err != nil {
+		h.updateErrorMetric(metrics.HistoryListShardOpenExecutionsScope, h.convertError(err))
+		return nil, h.convertError(err)
+	}
+
+	return response, nil
+}
+
+// ResetStickyTaskListByWorkflowType resets the sticky task list of all running executions of a workflow type in a
+// domain, so that new worker code takes over promptly after a deploy.  Executions are found through visibility and
+// reset at a limited rate, the number of executions which were reset is returned.
+func (h *Handler) ResetStickyTaskListByWorkflowType(ctx context.Context, domainID,
+	workflowTypeName string) (int, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryResetStickyTaskListByTypeScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryResetStickyTaskListByTypeScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if domainID == "" {
+		return 0, errDomainNotSet
+	}
+	if workflowTypeName == "" {
+		return 0, errWorkflowTypeNotSet
+	}
+
+	rateLimiter := common.NewTokenBucket(h.config.ResetStickyTaskListBatchRPS, common.NewRealTimeSource())
+	listRequest := &persistence.ListWorkflowExecutionsByTypeRequest{
+		ListWorkflowExecutionsRequest: persistence.ListWorkflowExecutionsRequest{
+			DomainUUID:        domainID,
+			EarliestStartTime: 0,
+			LatestStartTime:   time.Now().UnixNano(),
+			PageSize:          h.config.ResetStickyTaskListBatchPageSize,
+		},
+		WorkflowTypeName: workflowTypeName,
+	}
+
+	resetCount := 0
+	for {
+		listResponse, err := h.visibilityMgr.ListOpenWorkflowExecutionsByType(listRequest)
+		if err != nil {
+			h.updateErrorMetric(metrics.HistoryResetStickyTaskListByTypeScope, err)
+			return resetCount, err
+		}
+
+		for _, executionInfo := range listResponse.Executions {
+			for !rateLimiter.Consume(1, resetStickyTaskListBatchThrottleInterval) {
+				if err := ctx.Err(); err != nil {
+					return resetCount, err
+				}
+			}
+
+			// the executions are spread over all shards of the cluster, so each one is reset through the history
+			// client which routes the request to the host owning the shard of the execution
+			response, err := h.historyServiceClient.ResetStickyTaskList(ctx, &hist.ResetStickyTaskListRequest{
+				DomainUUID: common.StringPtr(domainID),
+				Execution:  executionInfo.Execution,
+			})
+			if err != nil {
+				if _, ok := err.(*gen.EntityNotExistsError); ok {
+					// execution is gone since it was listed, nothing to reset
+					continue
+				}
+				h.updateErrorMetric(metrics.HistoryResetStickyTaskListByTypeScope, err)
+				return resetCount, err
+			}
+			if response.SkippedReason == nil ||
+				*response.SkippedReason != hist.ResetStickyTaskListSkippedReasonWorkflowNotRunning {
+				resetCount++
+			}
+		}
+
+		if len(listResponse.NextPageToken) == 0 {
+			return resetCount, nil
+		}
+		listRequest.NextPageToken = listResponse.NextPageToken
+	}
+}
+
+// BulkTerminateWorkflowExecutions terminates a list of executions of a domain with the same reason and identity, for
+// cleaning up after an incident.  The executions are resolved by the caller, typically through a visibility query, and
+// a limited number of them is terminated concurrently.  The outcome of every execution is returned in the order of the
+// request, executions which are already closed or gone are reported as such so the request can be retried as a whole.
+func (h *Handler) BulkTerminateWorkflowExecutions(ctx context.Context,
+	request *BulkTerminateRequest) ([]*BulkTerminateResult, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryBulkTerminateScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryBulkTerminateScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if request.DomainID == "" {
+		return nil, errDomainNotSet
+	}
+	for _, execution := range request.Executions {
+		if execution.GetWorkflowId() == "" {
+			return nil, errWorkflowIDNotSet
+		}
+	}
+
+	concurrency := h.config.BulkTerminateConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	domainName := request.DomainID
+	if domainEntry, err := h.controller.domainCache.GetDomainByID(request.DomainID); err == nil &&
+		domainEntry.GetInfo() != nil {
+		domainName = domainEntry.GetInfo().Name
+	}
+	results := make([]*BulkTerminateResult, len(request.Executions))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+Dispatch_Loop:
+	for i, execution := range request.Executions {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			break Dispatch_Loop
+		}
+
+		wg.Add(1)
+		go func(i int, execution gen.WorkflowExecution) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = h.bulkTerminateWorkflowExecution(ctx, request, execution, domainName)
+		}(i, execution)
+	}
+	wg.Wait()
+
+	// executions not dispatched before the caller gave up are reported as failed
+	for i, result := range results {
+		if result == nil {
+			results[i] = &BulkTerminateResult{
+				Execution: request.Executions[i],
+				Outcome:   BulkTerminateOutcomeFailed,
+				Err:       ctx.Err(),
+			}
+		}
+	}
+	return results, ctx.Err()
+}
+
+func (h *Handler) bulkTerminateWorkflowExecution(ctx context.Context, request *BulkTerminateRequest,
+	execution gen.WorkflowExecution, domainName string) *BulkTerminateResult {
+	// the executions are spread over all shards of the cluster, so each one is terminated through the history client
+	// which routes the request to the host owning the shard of the execution
+	err := h.historyServiceClient.TerminateWorkflowExecution(ctx, &hist.TerminateWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(request.DomainID),
+		TerminateRequest: &gen.TerminateWorkflowExecutionRequest{
+			WorkflowExecution: &execution,
+			Reason:            common.StringPtr(request.Reason),
+			Identity:          common.StringPtr(request.Identity),
+		},
+	})
+
+	result := &BulkTerminateResult{Execution: execution, Outcome: getBulkTerminateOutcome(err)}
+	switch result.Outcome {
+	case BulkTerminateOutcomeTerminated:
+		h.metricsClient.Tagged(map[string]string{metrics.DomainTagName: domainName}).IncCounter(
+			metrics.HistoryBulkTerminateScope, metrics.BulkTerminatedCounter)
+	case BulkTerminateOutcomeFailed:
+		h.updateErrorMetric(metrics.HistoryBulkTerminateScope, err)
+		result.Err = err
+	}
+	return result
+}
+
+// getBulkTerminateOutcome maps the error of terminating one execution of a bulk termination to its outcome.  The
+// error comes back from the owning host as a plain EntityNotExistsError, so an execution which is already closed is
+// told apart from one which is gone by the message of ErrWorkflowCompleted.
+func getBulkTerminateOutcome(err error) BulkTerminateOutcome {
+	switch err := err.(type) {
+	case nil:
+		return BulkTerminateOutcomeTerminated
+	case *gen.EntityNotExistsError:
+		if err.Message == ErrWorkflowCompleted.Message {
+			return BulkTerminateOutcomeAlreadyClosed
+		}
+		return BulkTerminateOutcomeNotFound
+	default:
+		return BulkTerminateOutcomeFailed
+	}
+}
+
+// GetShardStats returns a snapshot of the workload of every shard owned by this host.  It only reads counters kept
+// by the shards, so it is cheap enough to be polled periodically to drive shard rebalancing.
+func (h *Handler) GetShardStats(ctx context.Context) []*ShardStats {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryGetShardStatsScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryGetShardStatsScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	return h.controller.shardStats()
+}
+
+// GetQueueLag returns how far the transfer and timer queue processors trail behind for every shard owned by this
+// host, so operators can tell which shards are falling behind
+func (h *Handler) GetQueueLag(ctx context.Context) []*QueueLag {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryGetQueueLagScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryGetQueueLagScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	return h.controller.queueLag()
+}
+
+// GetWorkflowExecutionRawHistory returns the serialized history batches of a workflow execution, for tooling which
+// backfills or reconciles history across clusters
+func (h *Handler) GetWorkflowExecutionRawHistory(ctx context.Context, request *RawHistoryRequest) (*RawHistoryResponse,
+	error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryGetWorkflowExecutionRawHistoryScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryGetWorkflowExecutionRawHistoryScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if request.DomainID == "" {
+		return nil, errDomainNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(request.Execution.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryGetWorkflowExecutionRawHistoryScope, err1)
+		return nil, err1
+	}
+
+	resp, err2 := engine.GetWorkflowExecutionRawHistory(request)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryGetWorkflowExecutionRawHistoryScope, h.convertError(err2))
+		return nil, h.convertError(err2)
+	}
+	return resp, nil
+}
+
+// GetWorkflowExecutionHistoryWindow returns the events around an event of a workflow execution, for triage of a
+// failure whose event is known
+func (h *Handler) GetWorkflowExecutionHistoryWindow(ctx context.Context, request *HistoryWindowRequest) (
+	*gen.History, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryGetHistoryWindowScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryGetHistoryWindowScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if request.DomainID == "" {
+		return nil, errDomainNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(request.Execution.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryGetHistoryWindowScope, err1)
+		return nil, err1
+	}
+
+	resp, err2 := engine.GetWorkflowExecutionHistoryWindow(request)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryGetHistoryWindowScope, h.convertError(err2))
+		return nil, h.convertError(err2)
+	}
+	return resp, nil
+}
+
 // ReplicateEvents is called by processor to replicate history events for passive domains
 func (h *Handler) ReplicateEvents(ctx context.Context, replicateRequest *hist.ReplicateEventsRequest) error {
 	h.startWG.Wait()
@@ -891,6 +1590,8 @@ func (h *Handler) updateErrorMetric(scope int, err error) {
 		h.metricsClient.IncCounter(scope, metrics.CadenceErrEntityNotExistsCounter)
 	case *gen.CancellationAlreadyRequestedError:
 		h.metricsClient.IncCounter(scope, metrics.CadenceErrCancellationAlreadyRequestedCounter)
+	case *gen.DomainNotActiveError:
+		h.metricsClient.IncCounter(scope, metrics.CadenceErrDomainNotActiveCounter)
 	default:
 		h.metricsClient.IncCounter(scope, metrics.CadenceFailures)
 	}
@@ -349,8 +349,8 @@ func (b *historyBuilder) AddMarkerRecordedEvent(decisionCompletedEventID int64,
 }
 
 func (b *historyBuilder) AddWorkflowExecutionSignaledEvent(
-	request *workflow.SignalWorkflowExecutionRequest) *workflow.HistoryEvent {
-	event := b.newWorkflowExecutionSignaledEvent(request)
+	request *workflow.SignalWorkflowExecutionRequest, sequenceNumber int64) *workflow.HistoryEvent {
+	event := b.newWorkflowExecutionSignaledEvent(request, sequenceNumber)
 
 	return b.addEventToHistory(event)
 }
@@ -440,6 +440,7 @@ func (b *historyBuilder) newWorkflowExecutionStartedEvent(
 	attributes.ChildPolicy = request.ChildPolicy
 	attributes.ContinuedExecutionRunId = previousRunID
 	attributes.Identity = common.StringPtr(common.StringDefault(request.Identity))
+	attributes.KeyId = request.KeyId
 	parentInfo := startRequest.ParentExecutionInfo
 	if parentInfo != nil {
 		attributes.ParentWorkflowDomain = parentInfo.Domain
@@ -509,6 +510,7 @@ func (b *historyBuilder) newActivityTaskScheduledEvent(decisionTaskCompletedEven
 	attributes := &workflow.ActivityTaskScheduledEventAttributes{}
 	attributes.ActivityId = common.StringPtr(common.StringDefault(scheduleAttributes.ActivityId))
 	attributes.ActivityType = scheduleAttributes.ActivityType
+	attributes.Domain = scheduleAttributes.Domain
 	attributes.TaskList = scheduleAttributes.TaskList
 	attributes.Input = scheduleAttributes.Input
 	attributes.ScheduleToCloseTimeoutSeconds = common.Int32Ptr(common.Int32Default(scheduleAttributes.ScheduleToCloseTimeoutSeconds))
@@ -516,6 +518,8 @@ func (b *historyBuilder) newActivityTaskScheduledEvent(decisionTaskCompletedEven
 	attributes.StartToCloseTimeoutSeconds = common.Int32Ptr(common.Int32Default(scheduleAttributes.StartToCloseTimeoutSeconds))
 	attributes.HeartbeatTimeoutSeconds = common.Int32Ptr(common.Int32Default(scheduleAttributes.HeartbeatTimeoutSeconds))
 	attributes.DecisionTaskCompletedEventId = common.Int64Ptr(decisionTaskCompletedEventID)
+	attributes.Priority = common.Int32Ptr(common.Int32Default(scheduleAttributes.Priority))
+	attributes.DispatchDeadlineSeconds = scheduleAttributes.DispatchDeadlineSeconds
 	historyEvent.ActivityTaskScheduledEventAttributes = attributes
 
 	return historyEvent
@@ -568,6 +572,7 @@ func (b *historyBuilder) newActivityTaskFailedEvent(scheduleEventID, startedEven
 	attributes.ScheduledEventId = common.Int64Ptr(scheduleEventID)
 	attributes.StartedEventId = common.Int64Ptr(startedEventID)
 	attributes.Identity = common.StringPtr(common.StringDefault(request.Identity))
+	attributes.FailureType = request.FailureType
 	historyEvent.ActivityTaskFailedEventAttributes = attributes
 
 	return historyEvent
@@ -606,12 +611,13 @@ func (b *historyBuilder) newTimeoutWorkflowExecutionEvent() *workflow.HistoryEve
 }
 
 func (b *historyBuilder) newWorkflowExecutionSignaledEvent(
-	request *workflow.SignalWorkflowExecutionRequest) *workflow.HistoryEvent {
+	request *workflow.SignalWorkflowExecutionRequest, sequenceNumber int64) *workflow.HistoryEvent {
 	historyEvent := b.msBuilder.createNewHistoryEvent(workflow.EventTypeWorkflowExecutionSignaled)
 	attributes := &workflow.WorkflowExecutionSignaledEventAttributes{}
 	attributes.SignalName = common.StringPtr(common.StringDefault(request.SignalName))
 	attributes.Input = request.Input
 	attributes.Identity = common.StringPtr(common.StringDefault(request.Identity))
+	attributes.SequenceNumber = common.Int64Ptr(sequenceNumber)
 	historyEvent.WorkflowExecutionSignaledEventAttributes = attributes
 
 	return historyEvent
@@ -194,6 +194,43 @@ func (s *historyBuilderSuite) TestHistoryBuilderDynamicSuccess() {
 	s.Equal(int64(3), s.getPreviousDecisionStartedEventID())
 }
 
+func (s *historyBuilderSuite) TestHistoryBuilderActivityTaskFailedType() {
+	id := "activity-failed-type-historybuilder-test-workflow-id"
+	rid := "activity-failed-type-historybuilder-test-run-id"
+	wt := "activity-failed-type-historybuilder-type"
+	tl := "activity-failed-type-historybuilder-tasklist"
+	identity := "activity-failed-type-historybuilder-worker"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(id),
+		RunId:      common.StringPtr(rid),
+	}
+
+	s.addWorkflowExecutionStartedEvent(we, wt, tl, nil, 60, 10, identity)
+	s.addDecisionTaskScheduledEvent()
+	s.addDecisionTaskStartedEvent(2, tl, identity)
+	s.addDecisionTaskCompletedEvent(2, 3, nil, identity)
+
+	activity1ScheduledEvent, _ := s.addActivityTaskScheduledEvent(4, "activity1", "activity-type", tl, nil, 60, 20, 10)
+	activity2ScheduledEvent, _ := s.addActivityTaskScheduledEvent(4, "activity2", "activity-type", tl, nil, 60, 20, 10)
+	activity1StartedEvent := s.addActivityTaskStartedEvent(activity1ScheduledEvent.GetEventId(), tl, identity)
+	activity2StartedEvent := s.addActivityTaskStartedEvent(activity2ScheduledEvent.GetEventId(), tl, identity)
+
+	activity1FailedEvent := s.msBuilder.AddActivityTaskFailedEvent(activity1ScheduledEvent.GetEventId(),
+		activity1StartedEvent.GetEventId(), &workflow.RespondActivityTaskFailedRequest{
+			Reason:      common.StringPtr("terminal"),
+			Identity:    common.StringPtr(identity),
+			FailureType: workflow.ActivityFailureTypeTerminal.Ptr(),
+		})
+	s.NotNil(activity1FailedEvent)
+	s.Equal(workflow.ActivityFailureTypeTerminal, *activity1FailedEvent.ActivityTaskFailedEventAttributes.FailureType)
+
+	// failures which are not classified by the activity are recorded as such
+	activity2FailedEvent := s.addActivityTaskFailedEvent(activity2ScheduledEvent.GetEventId(),
+		activity2StartedEvent.GetEventId(), "unclassified", nil, identity)
+	s.NotNil(activity2FailedEvent)
+	s.Nil(activity2FailedEvent.ActivityTaskFailedEventAttributes.FailureType)
+}
+
 func (s *historyBuilderSuite) TestHistoryBuilderWorkflowStartFailures() {
 	id := "historybuilder-workflowstart-failures-test-workflow-id"
 	rid := "historybuilder-workflowstart-failures-test-run-id"
@@ -27,6 +27,7 @@ import (
 	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/logging"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
 
 	"github.com/pborman/uuid"
@@ -43,6 +44,7 @@ type (
 		executionManager persistence.ExecutionManager
 		disabled         bool
 		logger           bark.Logger
+		metricsClient    metrics.Client
 		config           *Config
 	}
 )
@@ -61,19 +63,25 @@ var (
 func newHistoryCache(shard ShardContext, logger bark.Logger) *historyCache {
 	opts := &cache.Options{}
 	config := shard.GetConfig()
-	opts.InitialCapacity = config.HistoryCacheInitialSize
-	opts.TTL = config.HistoryCacheTTL
+	opts.InitialCapacity = config.HistoryCacheInitialSize()
+	opts.TTL = config.HistoryCacheTTL()
 	opts.Pin = true
 
-	return &historyCache{
-		Cache:            cache.New(config.HistoryCacheMaxSize, opts),
+	c := &historyCache{
 		shard:            shard,
 		executionManager: shard.GetExecutionManager(),
 		logger: logger.WithFields(bark.Fields{
 			logging.TagWorkflowComponent: logging.TagValueHistoryCacheComponent,
 		}),
-		config: config,
+		metricsClient: shard.GetMetricsClient(),
+		config:        config,
 	}
+	// Pinned contexts are never evicted, so an entry is only removed once every caller has released it and
+	// a later load for the same execution creates a new context which reads mutable state from persistence
+	opts.RemovedFunc = c.onEvicted
+	c.Cache = cache.New(config.HistoryCacheMaxSize(), opts)
+
+	return c
 }
 
 func (c *historyCache) getOrCreateWorkflowExecution(domainID string,
@@ -105,11 +113,17 @@ func (c *historyCache) getOrCreateWorkflowExecution(domainID string,
 
 	key := execution.GetRunId()
 	context, cacheHit := c.Get(key).(*workflowExecutionContext)
-	if !cacheHit {
+	if cacheHit {
+		c.metricsClient.IncCounter(metrics.HistoryCacheScope, metrics.CacheHitCounter)
+	} else {
+		c.metricsClient.IncCounter(metrics.HistoryCacheScope, metrics.CacheMissCounter)
 		// Let's create the workflow execution context
 		context = newWorkflowExecutionContext(domainID, execution, c.shard, c.executionManager, c.logger)
 		elem, err := c.PutIfNotExist(key, context)
 		if err != nil {
+			if err == cache.ErrCacheFull {
+				c.metricsClient.IncCounter(metrics.HistoryCacheScope, metrics.CacheFullCounter)
+			}
 			return nil, nil, err
 		}
 		context = elem.(*workflowExecutionContext)
@@ -133,6 +147,10 @@ func (c *historyCache) getOrCreateWorkflowExecution(domainID string,
 	return context, releaseFunc, nil
 }
 
+func (c *historyCache) onEvicted(value interface{}) {
+	c.metricsClient.IncCounter(metrics.HistoryCacheScope, metrics.CacheEvictionCounter)
+}
+
 func (c *historyCache) getCurrentExecutionWithRetry(
 	request *persistence.GetCurrentExecutionRequest) (*persistence.GetCurrentExecutionResponse, error) {
 	var response *persistence.GetCurrentExecutionResponse
@@ -25,6 +25,7 @@ import (
 	"os"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
@@ -94,7 +95,7 @@ func (s *historyCacheSuite) TearDownTest() {
 }
 
 func (s *historyCacheSuite) TestHistoryCachePinning() {
-	s.mockShard.GetConfig().HistoryCacheMaxSize = 2
+	s.mockShard.GetConfig().HistoryCacheMaxSize = func(...dynamicconfig.FilterOption) int { return 2 }
 	domainID := "test_domain_id"
 	s.cache = newHistoryCache(s.mockShard, s.logger)
 	we := workflow.WorkflowExecution{
@@ -129,7 +130,7 @@ func (s *historyCacheSuite) TestHistoryCachePinning() {
 }
 
 func (s *historyCacheSuite) TestHistoryCacheClear() {
-	s.mockShard.GetConfig().HistoryCacheMaxSize = 20
+	s.mockShard.GetConfig().HistoryCacheMaxSize = func(...dynamicconfig.FilterOption) int { return 20 }
 	domainID := "test_domain_id"
 	s.cache = newHistoryCache(s.mockShard, s.logger)
 	we := workflow.WorkflowExecution{
@@ -160,7 +161,7 @@ func (s *historyCacheSuite) TestHistoryCacheClear() {
 }
 
 func (s *historyCacheSuite) TestHistoryCacheConcurrentAccess() {
-	s.mockShard.GetConfig().HistoryCacheMaxSize = 20
+	s.mockShard.GetConfig().HistoryCacheMaxSize = func(...dynamicconfig.FilterOption) int { return 20 }
 	domainID := "test_domain_id"
 	s.cache = newHistoryCache(s.mockShard, s.logger)
 	we := workflow.WorkflowExecution{
@@ -198,3 +199,50 @@ func (s *historyCacheSuite) TestHistoryCacheConcurrentAccess() {
 	s.Nil(context.msBuilder)
 	release(nil)
 }
+
+func (s *historyCacheSuite) TestHistoryCacheMetrics() {
+	s.mockShard.GetConfig().HistoryCacheMaxSize = func(...dynamicconfig.FilterOption) int { return 2 }
+	scope := tally.NewTestScope("test", nil)
+	s.mockShard.metricsClient = metrics.NewClient(scope, metrics.History)
+	domainID := "test_domain_id"
+	s.cache = newHistoryCache(s.mockShard, s.logger)
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wf-cache-test-metrics"),
+		RunId:      common.StringPtr(uuid.New()),
+	}
+	we2 := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wf-cache-test-metrics"),
+		RunId:      common.StringPtr(uuid.New()),
+	}
+
+	_, release, err := s.cache.getOrCreateWorkflowExecution(domainID, we)
+	s.Nil(err)
+	// The only slot is pinned, so loading another execution fails
+	_, _, err = s.cache.getOrCreateWorkflowExecution(domainID, we2)
+	s.NotNil(err)
+	release(nil)
+
+	_, release, err = s.cache.getOrCreateWorkflowExecution(domainID, we)
+	s.Nil(err)
+	release(nil)
+	// Loading another execution evicts the released one
+	_, release, err = s.cache.getOrCreateWorkflowExecution(domainID, we2)
+	s.Nil(err)
+	release(nil)
+
+	counter := func(name string) int64 {
+		c, ok := scope.Snapshot().Counters()["test."+name+"+operation=HistoryCache"]
+		if !ok {
+			return 0
+		}
+		return c.Value()
+	}
+	s.Equal(int64(1), counter("cache-hit"))
+	s.Equal(int64(3), counter("cache-miss"))
+	s.Equal(int64(1), counter("cache-full"))
+	// Evictions are reported asynchronously by the cache
+	for i := 0; i < 100 && counter("cache-eviction") == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.Equal(int64(1), counter("cache-eviction"))
+}
@@ -21,31 +21,50 @@
 package history
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"go.uber.org/yarpc"
 
 	"github.com/pborman/uuid"
 	"github.com/uber-common/bark"
 	h "github.com/uber/cadence/.gen/go/history"
+	m "github.com/uber/cadence/.gen/go/matching"
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	hc "github.com/uber/cadence/client/history"
 	"github.com/uber/cadence/client/matching"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/logging"
 	"github.com/uber/cadence/common/messaging"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/service/dynamicconfig"
 )
 
 const (
 	conditionalRetryCount                    = 5
 	activityCancelationMsgActivityIDUnknown  = "ACTIVITY_ID_UNKNOWN"
 	activityCancelationMsgActivityNotStarted = "ACTIVITY_ID_NOT_STARTED"
+	activityCancelationMsgWorkflowClosed     = "WORKFLOW_CLOSED"
 	timerCancelationMsgTimerIDUnknown        = "TIMER_ID_UNKNOWN"
+	// doNotTerminateOperatorTag is the operator tag TerminateWorkflowExecution refuses to terminate when asked to
+	doNotTerminateOperatorTag = "do-not-terminate"
+	// historyWindowLookBehind is the number of events first searched for the batch holding the start of a history
+	// window, the search range doubles until the batch is found
+	historyWindowLookBehind = 100
 )
 
 type (
@@ -59,11 +78,86 @@ type (
 		replicator           *historyReplicator
 		replicatorProcessor  queueProcessor
 		historyEventNotifier historyEventNotifier
+		matchingClient       matching.Client
 		tokenSerializer      common.TaskTokenSerializer
 		hSerializerFactory   persistence.HistorySerializerFactory
 		historyCache         *historyCache
 		metricsClient        metrics.Client
 		logger               bark.Logger
+		// updateSemaphore bounds the number of concurrent workflow updates on this shard, nil means unbounded
+		updateSemaphore chan struct{}
+		// updateIsolation holds each domain to its share of the concurrent updates, nil when updates are unbounded
+		updateIsolation *domainIsolationGroup
+		// completedActivityRequests remembers request ids of recent activity completions, nil disables the dedup
+		completedActivityRequests cache.Cache
+		// startedRunIDs remembers the runs created by recent start requests, nil disables the dedup
+		startedRunIDs cache.Cache
+		// decisionBackoffPolicy delays dispatch of decisions retried after failures, nil dispatches them right away
+		decisionBackoffPolicy backoff.RetryPolicy
+		// replicationRetryPolicy paces retries of replicated events which conflicted with another update, nil
+		// disables the retries
+		replicationRetryPolicy backoff.RetryPolicy
+
+		// firstDecisionMatchesLock protects the synchronous hand offs of first decisions to matching, keyed by the
+		// decision they hand off
+		firstDecisionMatchesLock sync.Mutex
+		firstDecisionMatches     map[firstDecisionKey]*firstDecisionMatch
+
+		// drainLock protects the write operation bookkeeping used to drain the engine on Stop
+		drainLock   sync.Mutex
+		draining    bool
+		inFlightOps int
+		drainedCh   chan struct{}
+	}
+
+	// activityCompletionKey identifies a completion request for an activity of a workflow execution
+	activityCompletionKey struct {
+		domainID   string
+		workflowID string
+		runID      string
+		requestID  string
+	}
+
+	// firstDecisionKey identifies the first decision of a workflow execution
+	firstDecisionKey struct {
+		domainID   string
+		workflowID string
+		runID      string
+		scheduleID int64
+	}
+
+	// firstDecisionMatch is a synchronous hand off of a first decision to matching, done is closed once matched is
+	// known
+	firstDecisionMatch struct {
+		key     firstDecisionKey
+		done    chan struct{}
+		matched bool
+	}
+
+	// workflowStartKey identifies a start request for a workflow id
+	workflowStartKey struct {
+		domainID   string
+		workflowID string
+		requestID  string
+	}
+
+	// rawExecutionInfo holds the internal mutable state fields of an execution dumped by verbose describe requests
+	rawExecutionInfo struct {
+		State              int
+		CloseStatus        int
+		LastFirstEventID   int64
+		NextEventID        int64
+		LastProcessedEvent int64
+		DecisionScheduleID int64
+		DecisionStartedID  int64
+		DecisionRequestID  string
+		DecisionTimeout    int32
+		DecisionAttempt    int64
+		DecisionTimestamp  int64
+		CreateRequestID    string
+		StickyTaskList     string
+		HasBufferedEvents  bool
+		ReplicationState   *persistence.ReplicationState
 	}
 
 	// shardContextWrapper wraps ShardContext to notify transferQueueProcessor on new tasks.
@@ -79,6 +173,22 @@ type (
 
 var _ Engine = (*historyEngineImpl)(nil)
 
+// decisionTypeCounters are the counters of the decisions RespondDecisionTaskCompleted receives by decision type
+var decisionTypeCounters = map[workflow.DecisionType]int{
+	workflow.DecisionTypeScheduleActivityTask:                   metrics.DecisionTypeScheduleActivityCounter,
+	workflow.DecisionTypeCompleteWorkflowExecution:              metrics.DecisionTypeCompleteWorkflowCounter,
+	workflow.DecisionTypeFailWorkflowExecution:                  metrics.DecisionTypeFailWorkflowCounter,
+	workflow.DecisionTypeCancelWorkflowExecution:                metrics.DecisionTypeCancelWorkflowCounter,
+	workflow.DecisionTypeStartTimer:                             metrics.DecisionTypeStartTimerCounter,
+	workflow.DecisionTypeRequestCancelActivityTask:              metrics.DecisionTypeCancelActivityCounter,
+	workflow.DecisionTypeCancelTimer:                            metrics.DecisionTypeCancelTimerCounter,
+	workflow.DecisionTypeRecordMarker:                           metrics.DecisionTypeRecordMarkerCounter,
+	workflow.DecisionTypeRequestCancelExternalWorkflowExecution: metrics.DecisionTypeCancelExternalWorkflowCounter,
+	workflow.DecisionTypeSignalExternalWorkflowExecution:        metrics.DecisionTypeSignalExternalWorkflowCounter,
+	workflow.DecisionTypeContinueAsNewWorkflowExecution:         metrics.DecisionTypeContinueAsNewCounter,
+	workflow.DecisionTypeStartChildWorkflowExecution:            metrics.DecisionTypeChildWorkflowCounter,
+}
+
 var (
 	// ErrTaskRetry is the error indicating that the timer / transfer task should be retried.
 	ErrTaskRetry = errors.New("passive task should retry due to condition in mutable state is not met")
@@ -94,12 +204,24 @@ var (
 	ErrActivityTaskNotFound = &workflow.EntityNotExistsError{Message: "Activity task not found."}
 	// ErrWorkflowCompleted is the error to indicate workflow execution already completed
 	ErrWorkflowCompleted = &workflow.EntityNotExistsError{Message: "Workflow execution already completed."}
+	// ErrWorkflowRunning is the error to indicate workflow execution is not closed yet
+	ErrWorkflowRunning = &workflow.BadRequestError{Message: "Workflow execution is still running."}
 	// ErrWorkflowParent is the error to parent execution is given and mismatch
 	ErrWorkflowParent = &workflow.EntityNotExistsError{Message: "Workflow parent does not match."}
 	// ErrDeserializingToken is the error to indicate task token is invalid
 	ErrDeserializingToken = &workflow.BadRequestError{Message: "Error deserializing task token."}
+	// ErrCheckpointOutOfOrder is the error indicating a heartbeat carries a checkpoint which is older than the last
+	// persisted one, or conflicts with it
+	ErrCheckpointOutOfOrder = &workflow.BadRequestError{Message: "Checkpoint sequence number must be greater than the last checkpoint, or equal to it with the same payload."}
 	// ErrCancellationAlreadyRequested is the error indicating cancellation for target workflow is already requested
 	ErrCancellationAlreadyRequested = &workflow.CancellationAlreadyRequestedError{Message: "Cancellation already requested for this workflow execution."}
+	// ErrDoNotTerminateTagged is the error indicating the workflow execution is tagged do-not-terminate by an operator
+	ErrDoNotTerminateTagged = &workflow.BadRequestError{Message: "Workflow execution is tagged do-not-terminate."}
+	// ErrConcurrentUpdateLimitExceeded is the error indicating the shard has too many workflow updates in flight
+	ErrConcurrentUpdateLimitExceeded = &workflow.ServiceBusyError{Message: "Too many concurrent workflow updates on shard."}
+	// ErrDomainUpdateLimitExceeded is the error indicating the domain uses its share of the concurrent workflow updates
+	// on the shard
+	ErrDomainUpdateLimitExceeded = &workflow.ServiceBusyError{Message: "Too many concurrent workflow updates for domain on shard."}
 	// FailedWorkflowCloseState is a set of failed workflow close states, used for start workflow policy
 	// for start workflow execution API
 	FailedWorkflowCloseState = map[int]bool{
@@ -138,6 +260,32 @@ func NewEngineWithShardContext(shard ShardContext, visibilityMgr persistence.Vis
 		}),
 		metricsClient:        shard.GetMetricsClient(),
 		historyEventNotifier: historyEventNotifier,
+		matchingClient:       matching,
+	}
+	if maxUpdates := shard.GetConfig().MaxConcurrentUpdatesPerShard; maxUpdates > 0 {
+		historyEngImpl.updateSemaphore = make(chan struct{}, maxUpdates)
+		historyEngImpl.updateIsolation = newDomainIsolationGroup(shard, maxUpdates, metrics.HistoryWorkflowUpdateScope)
+	}
+	if dedupInterval := shard.GetConfig().ActivityCompletionDedupInterval; dedupInterval > 0 {
+		historyEngImpl.completedActivityRequests = cache.New(shard.GetConfig().ActivityCompletionDedupMaxSize,
+			&cache.Options{TTL: dedupInterval})
+	}
+	if dedupInterval := shard.GetConfig().StartedRunIDDedupInterval; dedupInterval > 0 {
+		historyEngImpl.startedRunIDs = cache.New(shard.GetConfig().StartedRunIDDedupMaxSize,
+			&cache.Options{TTL: dedupInterval})
+	}
+	if initialInterval := shard.GetConfig().DecisionBackoffInitialInterval; initialInterval > 0 {
+		policy := backoff.NewExponentialRetryPolicy(initialInterval)
+		policy.SetMaximumInterval(shard.GetConfig().DecisionBackoffMaxInterval)
+		policy.SetExpirationInterval(backoff.NoInterval)
+		historyEngImpl.decisionBackoffPolicy = policy
+	}
+	if maxRetryCount := shard.GetConfig().ReplicationApplyMaxRetryCount; maxRetryCount > 0 {
+		policy := backoff.NewExponentialRetryPolicy(shard.GetConfig().ReplicationApplyRetryInitialInterval)
+		policy.SetMaximumInterval(shard.GetConfig().ReplicationApplyRetryMaxInterval)
+		policy.SetExpirationInterval(backoff.NoInterval)
+		policy.SetMaximumAttempts(maxRetryCount)
+		historyEngImpl.replicationRetryPolicy = policy
 	}
 	txProcessor := newTransferQueueProcessor(shard, historyEngImpl, visibilityMgr, matching, historyClient, logger)
 	historyEngImpl.timerProcessor = newTimerQueueProcessor(shard, historyEngImpl, logger)
@@ -171,11 +319,14 @@ func (e *historyEngineImpl) Start() {
 	}
 }
 
-// Stop the service.
+// Stop the service.  New write operations are rejected right away and in-flight ones are given up to
+// ShutdownDrainTimeout to complete before the queue processors are stopped.
 func (e *historyEngineImpl) Stop() {
 	logging.LogHistoryEngineShuttingDownEvent(e.logger)
 	defer logging.LogHistoryEngineShutdownEvent(e.logger)
 
+	e.drain(e.shard.GetConfig().ShutdownDrainTimeout)
+
 	e.txProcessor.Stop()
 	e.timerProcessor.Stop()
 	if e.replicatorProcessor != nil {
@@ -190,12 +341,43 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 	if err != nil {
 		return nil, err
 	}
+	if err := e.validateDomainRegistered(domainID); err != nil {
+		return nil, err
+	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return nil, err
+	}
+
+	endOperation, err := e.beginWorkflowUpdate(domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer endOperation()
 
 	request := startRequest.StartRequest
-	err = validateStartWorkflowExecutionRequest(request)
+	err = validateStartWorkflowExecutionRequest(request, e.shard.GetConfig())
 	if err != nil {
 		return nil, err
 	}
+	startRequest, err = e.routeStartRequest(domainID, startRequest)
+	if err != nil {
+		return nil, err
+	}
+	request = startRequest.StartRequest
+
+	// a retry of a start which went through recently is answered without another create attempt, the dedup done on
+	// create by request id still covers retries which are not in the cache
+	startKey := workflowStartKey{
+		domainID:   domainID,
+		workflowID: request.GetWorkflowId(),
+		requestID:  request.GetRequestId(),
+	}
+	if runID, ok := e.getStartedRunID(startKey); ok {
+		return &workflow.StartWorkflowExecutionResponse{RunId: common.StringPtr(runID)}, nil
+	}
+	if err := e.checkOpenExecutionLimit(metrics.HistoryStartWorkflowExecutionScope, domainID); err != nil {
+		return nil, err
+	}
 
 	execution := workflow.WorkflowExecution{
 		WorkflowId: request.WorkflowId,
@@ -205,15 +387,16 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 	var parentExecution *workflow.WorkflowExecution
 	initiatedID := emptyEventID
 	parentDomainID := ""
+	depth := int32(0)
 	parentInfo := startRequest.ParentExecutionInfo
 	if parentInfo != nil {
 		parentDomainID = *parentInfo.DomainUUID
 		parentExecution = parentInfo.Execution
 		initiatedID = *parentInfo.InitiatedId
+		depth = parentInfo.GetDepth() + 1
 	}
 
 	// Generate first decision task event.
-	taskList := request.TaskList.GetName()
 	msBuilder := newMutableStateBuilder(e.shard.GetConfig(), e.logger)
 	startedEvent := msBuilder.AddWorkflowExecutionStartedEvent(execution, startRequest)
 	if startedEvent == nil {
@@ -232,16 +415,19 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 		}
 
 		transferTasks = []persistence.Task{&persistence.DecisionTask{
-			DomainID: domainID, TaskList: taskList, ScheduleID: di.ScheduleID,
+			DomainID: domainID, TaskList: di.Tasklist, ScheduleID: di.ScheduleID,
 		}}
 		decisionScheduleID = di.ScheduleID
 		decisionStartID = di.StartedID
 		decisionTimeout = di.DecisionTimeout
 	}
 
+	// the start time is read once, so the execution and its timeout agree on it
+	startTime := e.shard.GetTimeSource().Now()
+	msBuilder.executionInfo.StartTimestamp = startTime
 	duration := time.Duration(*request.ExecutionStartToCloseTimeoutSeconds) * time.Second
 	timerTasks := []persistence.Task{&persistence.WorkflowTimeoutTask{
-		VisibilityTimestamp: e.shard.GetTimeSource().Now().Add(duration),
+		VisibilityTimestamp: startTime.Add(duration),
 	}}
 	// Serialize the history
 	serializedHistory, serializedError := msBuilder.hBuilder.Serialize()
@@ -260,6 +446,7 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 		TransactionID: 0,
 		FirstEventID:  startedEvent.GetEventId(),
 		Events:        serializedHistory,
+		KeyID:         msBuilder.executionInfo.KeyID,
 	})
 	if err != nil {
 		return nil, err
@@ -307,6 +494,7 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 			ExecutionContext:            nil,
 			NextEventID:                 msBuilder.GetNextEventID(),
 			LastProcessedEvent:          emptyEventID,
+			StartTimestamp:              startTime,
 			TransferTasks:               transferTasks,
 			ReplicationTasks:            replicationTasks,
 			DecisionScheduleID:          decisionScheduleID,
@@ -316,12 +504,16 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 			ContinueAsNew:               !isBrandNew,
 			PreviousRunID:               prevRunID,
 			ReplicationState:            replicationState,
+			Depth:                       depth,
+			KeyID:                       msBuilder.executionInfo.KeyID,
+			DecisionTaskList:            msBuilder.executionInfo.DecisionTaskList,
+			LastFirstEventID:            msBuilder.executionInfo.LastFirstEventID,
 		})
 
 		if err != nil {
 			switch t := err.(type) {
 			case *persistence.WorkflowExecutionAlreadyStartedError:
-				if t.StartRequestID == common.StringDefault(request.RequestId) {
+				if isRetriedStartRequest(request, t) {
 					e.deleteEvents(domainID, execution)
 					return t.RunID, nil
 				}
@@ -342,11 +534,17 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 
 		errFn := func(errMsg string, createRequestID string, workflowID string, runID string) error {
 			msg := fmt.Sprintf(errMsg, workflowID, runID)
-			return &workflow.WorkflowExecutionAlreadyStartedError{
+			startedErr := &workflow.WorkflowExecutionAlreadyStartedError{
 				Message:        common.StringPtr(msg),
 				StartRequestId: common.StringPtr(fmt.Sprintf("%v", createRequestID)),
 				RunId:          common.StringPtr(fmt.Sprintf("%v", runID)),
+				Running:        common.BoolPtr(prevState != persistence.WorkflowStateCompleted),
 			}
+			// let the caller tell a run which is still going apart from one rejected by the reuse policy
+			if prevState == persistence.WorkflowStateCompleted && prevCloseState != persistence.WorkflowCloseStatusNone {
+				startedErr.CloseStatus = getWorkflowExecutionCloseStatus(prevCloseState).Ptr()
+			}
+			return startedErr
 		}
 
 		// here we know there is some information about the prev workflow, i.e. either running right now
@@ -364,7 +562,21 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 				return errFn(msg, prevStartRequestID, execution.GetWorkflowId(), prevRunID)
 			}
 		case workflow.WorkflowIdReusePolicyAllowDuplicate:
-			// as long as workflow not running, so this case has no check
+			// as long as workflow not running, only the minimum interval since the run closed is checked
+			interval := e.shard.GetConfig().WorkflowIDReuseMinInterval(dynamicconfig.DomainFilter(request.GetDomain()))
+			if interval > 0 {
+				closedRecently, err := e.isClosedWithinInterval(domainID, execution.GetWorkflowId(), prevRunID, interval)
+				if err != nil {
+					e.deleteEvents(domainID, execution)
+					return err
+				}
+				if closedRecently {
+					e.deleteEvents(domainID, execution)
+					msg := fmt.Sprintf("Workflow execution closed less than %v ago. WorkflowId: %%v, RunId: %%v. "+
+						"Workflow ID reuse policy: allow duplicate workflow ID after the minimum reuse interval.", interval)
+					return errFn(msg, prevStartRequestID, execution.GetWorkflowId(), prevRunID)
+				}
+			}
 		case workflow.WorkflowIdReusePolicyRejectDuplicate:
 			e.deleteEvents(domainID, execution)
 			msg := "Workflow execution already finished. WorkflowId: %v, RunId: %v. Workflow ID reuse policy: reject duplicate workflow ID."
@@ -377,6 +589,12 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 		return nil
 	}
 
+	var firstDecisionMatch *firstDecisionMatch
+	if decisionScheduleID != emptyEventID &&
+		e.shard.GetConfig().EnableSyncMatchFirstDecision(dynamicconfig.DomainFilter(request.GetDomain())) {
+		firstDecisionMatch = e.beginFirstDecisionMatch(domainID, execution, decisionScheduleID)
+	}
+
 	// try to create the workflow execution
 	isBrandNew := true
 	resultRunID := ""
@@ -389,8 +607,15 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 		}
 	}
 
+	if firstDecisionMatch != nil {
+		e.endFirstDecisionMatch(firstDecisionMatch, err == nil && resultRunID == execution.GetRunId() &&
+			e.syncMatchFirstDecision(domainID, execution, msBuilder.executionInfo.DecisionTaskList,
+				decisionScheduleID, request.GetExecutionStartToCloseTimeoutSeconds()))
+	}
+
 	if err == nil {
 		e.timerProcessor.NotifyNewTimers(e.currentClusterName, timerTasks)
+		e.putStartedRunID(startKey, resultRunID)
 
 		return &workflow.StartWorkflowExecutionResponse{
 			RunId: common.StringPtr(resultRunID),
@@ -399,6 +624,24 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 	return nil, err
 }
 
+// getStartedRunID returns the run created by an earlier start request with the same request id, if it is remembered
+func (e *historyEngineImpl) getStartedRunID(key workflowStartKey) (string, bool) {
+	if key.requestID == "" || e.startedRunIDs == nil {
+		return "", false
+	}
+	if runID, ok := e.startedRunIDs.Get(key).(string); ok {
+		return runID, true
+	}
+	return "", false
+}
+
+func (e *historyEngineImpl) putStartedRunID(key workflowStartKey, runID string) {
+	if key.requestID == "" || e.startedRunIDs == nil {
+		return
+	}
+	e.startedRunIDs.Put(key, runID)
+}
+
 // GetMutableState retrieves the mutable state of the workflow execution
 func (e *historyEngineImpl) GetMutableState(ctx context.Context,
 	request *h.GetMutableStateRequest) (*h.GetMutableStateResponse, error) {
@@ -413,7 +656,12 @@ func (e *historyEngineImpl) GetMutableState(ctx context.Context,
 		RunId:      request.Execution.RunId,
 	}
 
-	response, err := e.getMutableState(domainID, execution)
+	if request.GetBypassCache() {
+		// reads bypassing the cache are meant for consistency checks, they never long poll
+		return e.getPersistedMutableState(domainID, execution, request.GetRefreshCache())
+	}
+
+	response, err := e.getMutableState(domainID, execution, request.GetConsistencyLevel())
 	if err != nil {
 		return nil, err
 	}
@@ -436,7 +684,7 @@ func (e *historyEngineImpl) GetMutableState(ctx context.Context,
 		defer e.historyEventNotifier.UnwatchHistoryEvent(newWorkflowIdentifier(domainID, &execution), subscriberID)
 
 		// check again in case the next event ID is updated
-		response, err = e.getMutableState(domainID, execution)
+		response, err = e.getMutableState(domainID, execution, h.ReadConsistencyLevelEventual)
 		if err != nil {
 			return nil, err
 		}
@@ -445,7 +693,7 @@ func (e *historyEngineImpl) GetMutableState(ctx context.Context,
 			return response, nil
 		}
 
-		timer := time.NewTimer(e.shard.GetConfig().LongPollExpirationInterval())
+		timer := time.NewTimer(e.getLongPollExpirationInterval(request.WaitTimeoutSeconds))
 		defer timer.Stop()
 		for {
 			select {
@@ -467,8 +715,111 @@ func (e *historyEngineImpl) GetMutableState(ctx context.Context,
 	return response, nil
 }
 
-func (e *historyEngineImpl) getMutableState(
-	domainID string, execution workflow.WorkflowExecution) (retResp *h.GetMutableStateResponse, retError error) {
+// validateDomainActive returns a DomainNotActiveError if the domain is a global domain which is active in another
+// cluster.  Workflows of such a domain are only written by replication, any other write would conflict with the
+// events replicated from the active cluster.
+func (e *historyEngineImpl) validateDomainActive(domainID string) error {
+	if !e.shard.GetService().GetClusterMetadata().IsGlobalDomainEnabled() {
+		// all domains are local, and local domains are always active
+		return nil
+	}
+
+	domainEntry, err := e.shard.GetDomainCache().GetDomainByID(domainID)
+	if err != nil {
+		return err
+	}
+	if notActiveErr := domainEntry.GetDomainNotActiveErr(); notActiveErr != nil {
+		return notActiveErr
+	}
+	return nil
+}
+
+// validateDomainRegistered makes sure new executions are only started in domains which exist and are not deprecated,
+// regardless of whether the domain is local or global
+func (e *historyEngineImpl) validateDomainRegistered(domainID string) error {
+	domainEntry, err := e.shard.GetDomainCache().GetDomainByID(domainID)
+	if err != nil {
+		return err
+	}
+
+	info := domainEntry.GetInfo()
+	switch info.Status {
+	case persistence.DomainStatusRegistered:
+		return nil
+	case persistence.DomainStatusDeprecated:
+		return &workflow.BadRequestError{Message: fmt.Sprintf("Domain %v is deprecated.", info.Name)}
+	default:
+		return &workflow.EntityNotExistsError{Message: fmt.Sprintf("Domain %v does not exist.", info.Name)}
+	}
+}
+
+// isBinaryChecksumDenied returns true if the worker binary checksum is on the configured deny list
+func (e *historyEngineImpl) isBinaryChecksumDenied(binaryChecksum string) bool {
+	if binaryChecksum == "" {
+		return false
+	}
+
+	for _, denied := range strings.Split(e.shard.GetConfig().DeniedBinaryChecksums(), ",") {
+		if strings.TrimSpace(denied) == binaryChecksum {
+			return true
+		}
+	}
+	return false
+}
+
+// getDisabledDecisionTypes returns the set of decision type names disabled for the domain, the domain is only
+// looked up while the decision type filter is enabled
+func (e *historyEngineImpl) getDisabledDecisionTypes(domainID string) (map[string]struct{}, error) {
+	config := e.shard.GetConfig()
+	if !config.EnableDecisionTypeFilter() {
+		return nil, nil
+	}
+
+	domainEntry, err := e.shard.GetDomainCache().GetDomainByID(domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	disabled := make(map[string]struct{})
+	for _, decisionType := range strings.Split(config.DisabledDecisionTypes(
+		dynamicconfig.DomainFilter(domainEntry.GetInfo().Name)), ",") {
+		if decisionType = strings.TrimSpace(decisionType); decisionType != "" {
+			disabled[decisionType] = struct{}{}
+		}
+	}
+	return disabled, nil
+}
+
+// offloadPayload hands a payload larger than the configured threshold to the payload offloader and returns the
+// reference to record in history in its place, smaller payloads are returned as is
+func (e *historyEngineImpl) offloadPayload(scope int, domainID string, executionInfo *persistence.WorkflowExecutionInfo,
+	payload []byte) ([]byte, error) {
+	config := e.shard.GetConfig()
+	threshold := config.PayloadOffloadThreshold()
+	if threshold <= 0 || len(payload) <= threshold {
+		return payload, nil
+	}
+
+	e.metricsClient.IncCounter(scope, metrics.PayloadOffloadedCounter)
+	return config.PayloadOffloader.Offload(domainID, executionInfo.WorkflowID, executionInfo.RunID, payload)
+}
+
+// getLongPollExpirationInterval returns the poll time asked for by the caller, capped by the configured maximum, or
+// the default poll time if the caller did not ask for one
+func (e *historyEngineImpl) getLongPollExpirationInterval(waitTimeoutSeconds *int32) time.Duration {
+	if waitTimeoutSeconds == nil || *waitTimeoutSeconds <= 0 {
+		return e.shard.GetConfig().LongPollExpirationInterval()
+	}
+
+	interval := time.Duration(*waitTimeoutSeconds) * time.Second
+	if maxInterval := e.shard.GetConfig().MaxLongPollExpirationInterval(); interval > maxInterval {
+		interval = maxInterval
+	}
+	return interval
+}
+
+func (e *historyEngineImpl) getMutableState(domainID string, execution workflow.WorkflowExecution,
+	consistencyLevel h.ReadConsistencyLevel) (retResp *h.GetMutableStateResponse, retError error) {
 
 	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(domainID, execution)
 	if err0 != nil {
@@ -476,27 +827,79 @@ func (e *historyEngineImpl) getMutableState(
 	}
 	defer func() { release(retError) }()
 
-	msBuilder, err1 := context.loadWorkflowExecution()
+	msBuilder, err1 := e.loadWorkflowExecutionWithConsistency(metrics.HistoryGetMutableStateScope, context,
+		consistencyLevel)
+	if err1 != nil {
+		return nil, err1
+	}
+
+	execution.RunId = context.workflowExecution.RunId
+	return createGetMutableStateResponse(execution, msBuilder), nil
+}
+
+// loadWorkflowExecutionWithConsistency loads the mutable state of an execution for a read.  A strongly consistent read
+// drops the cached mutable state first so it is read from persistence, an eventually consistent one is served from
+// the cache when the execution is cached.
+func (e *historyEngineImpl) loadWorkflowExecutionWithConsistency(scope int, context *workflowExecutionContext,
+	consistencyLevel h.ReadConsistencyLevel) (*mutableStateBuilder, error) {
+	if consistencyLevel == h.ReadConsistencyLevelStrong {
+		e.metricsClient.IncCounter(scope, metrics.StrongConsistencyReadCounter)
+		context.clear()
+	}
+	return context.loadWorkflowExecution()
+}
+
+// getPersistedMutableState returns the mutable state of a workflow execution as read from persistence rather than
+// the cache, along with whether the cached mutable state was stale, so that cache consistency can be audited.  The
+// cached mutable state is replaced with the one read when refreshCache is set.
+func (e *historyEngineImpl) getPersistedMutableState(domainID string, execution workflow.WorkflowExecution,
+	refreshCache bool) (retResp *h.GetMutableStateResponse, retError error) {
+
+	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(domainID, execution)
+	if err0 != nil {
+		return nil, err0
+	}
+	defer func() { release(retError) }()
+
+	msBuilder, stale, err1 := context.reloadWorkflowExecution(refreshCache)
 	if err1 != nil {
 		return nil, err1
 	}
+	if stale {
+		e.emitStaleStateReload(metrics.HistoryGetMutableStateScope, "GetMutableState", msBuilder.executionInfo)
+	}
 
 	execution.RunId = context.workflowExecution.RunId
-	result := &h.GetMutableStateResponse{
+	result := createGetMutableStateResponse(execution, msBuilder)
+	result.PersistedNextEventId = common.Int64Ptr(msBuilder.GetNextEventID())
+	result.StaleCacheDetected = common.BoolPtr(stale)
+	result.StaleCacheCorrected = common.BoolPtr(stale && refreshCache)
+	return result, nil
+}
+
+func createGetMutableStateResponse(execution workflow.WorkflowExecution,
+	msBuilder *mutableStateBuilder) *h.GetMutableStateResponse {
+	response := &h.GetMutableStateResponse{
 		Execution:                            &execution,
 		WorkflowType:                         &workflow.WorkflowType{Name: common.StringPtr(msBuilder.executionInfo.WorkflowTypeName)},
 		LastFirstEventId:                     common.Int64Ptr(msBuilder.GetLastFirstEventID()),
 		NextEventId:                          common.Int64Ptr(msBuilder.GetNextEventID()),
-		TaskList:                             &workflow.TaskList{Name: common.StringPtr(context.msBuilder.executionInfo.TaskList)},
+		TaskList:                             &workflow.TaskList{Name: common.StringPtr(msBuilder.executionInfo.TaskList)},
 		StickyTaskList:                       &workflow.TaskList{Name: common.StringPtr(msBuilder.executionInfo.StickyTaskList)},
 		ClientLibraryVersion:                 common.StringPtr(msBuilder.executionInfo.ClientLibraryVersion),
 		ClientFeatureVersion:                 common.StringPtr(msBuilder.executionInfo.ClientFeatureVersion),
 		ClientImpl:                           common.StringPtr(msBuilder.executionInfo.ClientImpl),
 		IsWorkflowRunning:                    common.BoolPtr(msBuilder.isWorkflowExecutionRunning()),
 		StickyTaskListScheduleToStartTimeout: common.Int32Ptr(msBuilder.executionInfo.StickyScheduleToStartTimeout),
+		StartTime:                            common.Int64Ptr(msBuilder.executionInfo.StartTimestamp.UnixNano()),
+		Generation:                           common.Int64Ptr(msBuilder.executionInfo.Generation),
+		LastSignalSequenceNumber:             common.Int64Ptr(msBuilder.executionInfo.SignalCount),
+		HasBufferedEvents:                    common.BoolPtr(msBuilder.HasBufferedEvents()),
 	}
-
-	return result, nil
+	if !msBuilder.isWorkflowExecutionRunning() {
+		response.CloseTime = common.Int64Ptr(msBuilder.getCloseTimestamp())
+	}
+	return response
 }
 
 // ResetStickyTaskList reset the volatile information in mutable state of a given workflow.
@@ -506,17 +909,31 @@ func (e *historyEngineImpl) getMutableState(
 // 3. ClientLibraryVersion
 // 4. ClientFeatureVersion
 // 5. ClientImpl
+// The response tells whether stickiness was actually cleared, and if not, why the reset was skipped.
 func (e *historyEngineImpl) ResetStickyTaskList(resetRequest *h.ResetStickyTaskListRequest) (*h.ResetStickyTaskListResponse, error) {
 	domainID, err := getDomainUUID(resetRequest.DomainUUID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := e.validateDomainActive(domainID); err != nil {
+		return nil, err
+	}
+
+	var response *h.ResetStickyTaskListResponse
 	err = e.updateWorkflowExecution(domainID, *resetRequest.Execution, false, false,
 		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error) {
+			// the closure is retried on conflicts, so the outcome is decided by the last attempt only
+			response = &h.ResetStickyTaskListResponse{StickinessCleared: common.BoolPtr(false)}
 			if !msBuilder.isWorkflowExecutionRunning() {
+				response.SkippedReason = h.ResetStickyTaskListSkippedReasonWorkflowNotRunning.Ptr()
 				return nil, nil
 			}
+			if !msBuilder.isStickyTaskListEnabled() {
+				response.SkippedReason = h.ResetStickyTaskListSkippedReasonNotSticky.Ptr()
+			} else {
+				response.StickinessCleared = common.BoolPtr(true)
+			}
 			msBuilder.clearStickyness()
 			return nil, nil
 		},
@@ -525,7 +942,7 @@ func (e *historyEngineImpl) ResetStickyTaskList(resetRequest *h.ResetStickyTaskL
 	if err != nil {
 		return nil, err
 	}
-	return &h.ResetStickyTaskListResponse{}, nil
+	return response, nil
 }
 
 // DescribeWorkflowExecution returns information about the specified workflow execution.
@@ -544,7 +961,8 @@ func (e *historyEngineImpl) DescribeWorkflowExecution(
 	}
 	defer func() { release(retError) }()
 
-	msBuilder, err1 := context.loadWorkflowExecution()
+	msBuilder, err1 := e.loadWorkflowExecutionWithConsistency(metrics.HistoryDescribeWorkflowExecutionScope, context,
+		request.GetConsistencyLevel())
 	if err1 != nil {
 		return nil, err1
 	}
@@ -561,13 +979,61 @@ func (e *historyEngineImpl) DescribeWorkflowExecution(
 			Type:          &workflow.WorkflowType{Name: common.StringPtr(msBuilder.executionInfo.WorkflowTypeName)},
 			StartTime:     common.Int64Ptr(msBuilder.executionInfo.StartTimestamp.UnixNano()),
 			HistoryLength: common.Int64Ptr(msBuilder.GetNextEventID() - common.FirstEventID),
+			Depth:         common.Int32Ptr(msBuilder.executionInfo.Depth),
+			SignalCount:   common.Int64Ptr(msBuilder.executionInfo.SignalCount),
 		},
 	}
+	if msBuilder.isStickyTaskListEnabled() {
+		result.ExecutionConfiguration.StickyScheduleToStartTimeoutSeconds = common.Int32Ptr(
+			msBuilder.executionInfo.StickyScheduleToStartTimeout)
+	}
+	if timeout := e.getNormalScheduleToStartTimeout(request.Request.GetDomain()); timeout > 0 {
+		result.ExecutionConfiguration.NormalScheduleToStartTimeoutSeconds = common.Int32Ptr(timeout)
+	}
+	if msBuilder.executionInfo.BinaryChecksum != "" {
+		result.WorkflowExecutionInfo.BinaryChecksum = common.StringPtr(msBuilder.executionInfo.BinaryChecksum)
+	}
+	if msBuilder.isPaused() {
+		result.WorkflowExecutionInfo.Paused = common.BoolPtr(true)
+	}
+	for _, point := range msBuilder.executionInfo.ResetPoints {
+		if request.GetOnlySafeResetPoints() && !point.Safe {
+			continue
+		}
+		result.WorkflowExecutionInfo.ResetPoints = append(result.WorkflowExecutionInfo.ResetPoints,
+			&workflow.ResetPoint{
+				EventId:        common.Int64Ptr(point.EventID),
+				BinaryChecksum: common.StringPtr(point.BinaryChecksum),
+				Resettable:     common.BoolPtr(point.Resettable),
+				CreatedTime:    common.Int64Ptr(point.CreatedTime.UnixNano()),
+				Safe:           common.BoolPtr(point.Safe),
+			})
+	}
+	if len(msBuilder.executionInfo.OperatorTags) > 0 {
+		result.WorkflowExecutionInfo.OperatorTags = make(map[string]string, len(msBuilder.executionInfo.OperatorTags))
+		for key, value := range msBuilder.executionInfo.OperatorTags {
+			result.WorkflowExecutionInfo.OperatorTags[key] = value
+		}
+	}
+	if msBuilder.executionInfo.Generation > 0 {
+		result.WorkflowExecutionInfo.Generation = common.Int64Ptr(msBuilder.executionInfo.Generation)
+	}
+	if msBuilder.executionInfo.DecisionAttempt > 0 {
+		result.WorkflowExecutionInfo.DecisionAttempt = common.Int64Ptr(msBuilder.executionInfo.DecisionAttempt)
+	}
+	if maxAttempts := e.shard.GetConfig().MaxTransientDecisionAttempts; maxAttempts > 0 {
+		result.WorkflowExecutionInfo.MaxDecisionAttempts = common.Int32Ptr(int32(maxAttempts))
+	}
+	if msBuilder.executionInfo.SignalCount > 0 {
+		result.WorkflowExecutionInfo.LastSignalName = common.StringPtr(msBuilder.executionInfo.LastSignalName)
+		result.WorkflowExecutionInfo.LastSignalTimestamp = common.Int64Ptr(
+			msBuilder.executionInfo.LastSignalTimestamp.UnixNano())
+	}
 	if msBuilder.executionInfo.State == persistence.WorkflowStateCompleted {
 		// for closed workflow
 		closeStatus := getWorkflowExecutionCloseStatus(msBuilder.executionInfo.CloseStatus)
 		result.WorkflowExecutionInfo.CloseStatus = &closeStatus
-		result.WorkflowExecutionInfo.CloseTime = common.Int64Ptr(msBuilder.getLastUpdatedTimestamp())
+		result.WorkflowExecutionInfo.CloseTime = common.Int64Ptr(msBuilder.getCloseTimestamp())
 	}
 
 	if len(msBuilder.pendingActivityInfoIDs) > 0 {
@@ -575,12 +1041,7 @@ func (e *historyEngineImpl) DescribeWorkflowExecution(
 			ai := &workflow.PendingActivityInfo{
 				ActivityID: common.StringPtr(pi.ActivityID),
 			}
-			state := workflow.PendingActivityStateScheduled
-			if pi.CancelRequested {
-				state = workflow.PendingActivityStateCancelRequested
-			} else if pi.StartedID != emptyEventID {
-				state = workflow.PendingActivityStateStarted
-			}
+			state := getPendingActivityState(pi)
 			ai.State = &state
 			lastHeartbeatUnixNano := pi.LastHeartBeatUpdatedTime.UnixNano()
 			if lastHeartbeatUnixNano > 0 {
@@ -590,19 +1051,69 @@ func (e *historyEngineImpl) DescribeWorkflowExecution(
 			if scheduledEvent, ok := msBuilder.getHistoryEvent(pi.ScheduledEvent); ok {
 				ai.ActivityType = scheduledEvent.ActivityTaskScheduledEventAttributes.ActivityType
 			}
+			if pi.StartedIdentity != "" {
+				ai.StartedWorkerIdentity = common.StringPtr(pi.StartedIdentity)
+			}
+			if pi.AcceptedCancel {
+				ai.AcceptedCancel = common.BoolPtr(true)
+			}
 			result.PendingActivities = append(result.PendingActivities, ai)
 		}
 	}
 
+	if request.GetVerbose() && e.shard.GetConfig().EnableClusterVerboseDescribe {
+		rawExecutionInfo, err := getRawExecutionInfo(msBuilder)
+		if err != nil {
+			return nil, err
+		}
+		result.RawExecutionInfo = common.StringPtr(rawExecutionInfo)
+	}
+
 	return result, nil
 }
 
+// getRawExecutionInfo returns the internal mutable state fields of an execution as JSON, for diagnosing corrupted
+// state without reading persistence directly.
+func getRawExecutionInfo(msBuilder *mutableStateBuilder) (string, error) {
+	executionInfo := msBuilder.executionInfo
+	data, err := json.Marshal(&rawExecutionInfo{
+		State:              executionInfo.State,
+		CloseStatus:        executionInfo.CloseStatus,
+		LastFirstEventID:   executionInfo.LastFirstEventID,
+		NextEventID:        executionInfo.NextEventID,
+		LastProcessedEvent: executionInfo.LastProcessedEvent,
+		DecisionScheduleID: executionInfo.DecisionScheduleID,
+		DecisionStartedID:  executionInfo.DecisionStartedID,
+		DecisionRequestID:  executionInfo.DecisionRequestID,
+		DecisionTimeout:    executionInfo.DecisionTimeout,
+		DecisionAttempt:    executionInfo.DecisionAttempt,
+		DecisionTimestamp:  executionInfo.DecisionTimestamp,
+		CreateRequestID:    executionInfo.CreateRequestID,
+		StickyTaskList:     executionInfo.StickyTaskList,
+		HasBufferedEvents:  msBuilder.HasBufferedEvents(),
+		ReplicationState:   msBuilder.replicationState,
+	})
+	if err != nil {
+		return "", &workflow.InternalServiceError{Message: fmt.Sprintf("Unable to dump execution info: %v", err)}
+	}
+	return string(data), nil
+}
+
 func (e *historyEngineImpl) RecordDecisionTaskStarted(
 	request *h.RecordDecisionTaskStartedRequest) (retResp *h.RecordDecisionTaskStartedResponse, retError error) {
 	domainID, err := getDomainUUID(request.DomainUUID)
 	if err != nil {
 		return nil, err
 	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return nil, err
+	}
+
+	endOperation, err := e.beginWorkflowUpdate(domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer endOperation()
 
 	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(domainID, *request.WorkflowExecution)
 	if err0 != nil {
@@ -626,7 +1137,8 @@ Update_History_Loop:
 		// First check to see if cache needs to be refreshed as we could potentially have stale workflow execution in
 		// some extreme cassandra failure cases.
 		if !isRunning && scheduleID >= msBuilder.GetNextEventID() {
-			e.metricsClient.IncCounter(metrics.HistoryRecordDecisionTaskStartedScope, metrics.StaleMutableStateCounter)
+			e.emitStaleStateReload(metrics.HistoryRecordDecisionTaskStartedScope, "RecordDecisionTaskStarted",
+				msBuilder.executionInfo)
 			// Reload workflow execution history
 			context.clear()
 			continue Update_History_Loop
@@ -662,6 +1174,7 @@ Update_History_Loop:
 			// Unable to add DecisionTaskStarted event to history
 			return nil, &workflow.InternalServiceError{Message: "Unable to add DecisionTaskStarted event to history."}
 		}
+		firstDecisionLatency, isFirstDecision := getFirstDecisionLatency(msBuilder, di, e.shard.GetTimeSource().Now())
 
 		// Start a timer for the decision task.
 		timeOutTask := tBuilder.AddDecisionTimoutTask(scheduleID, di.Attempt, di.DecisionTimeout)
@@ -685,10 +1198,29 @@ Update_History_Loop:
 			return nil, err3
 		}
 
+		if isFirstDecision {
+			e.metricsClient.Tagged(map[string]string{
+				metrics.DomainTagName:   getMetricsDomainName(e.shard, domainID),
+				metrics.TaskListTagName: msBuilder.executionInfo.TaskList,
+			}).RecordTimer(metrics.HistoryRecordDecisionTaskStartedScope, metrics.WorkflowFirstDecisionLatency,
+				firstDecisionLatency)
+		}
+
 		return e.createRecordDecisionTaskStartedResponse(domainID, msBuilder, di, request.PollRequest.GetIdentity()), nil
 	}
 
-	return nil, ErrMaxAttemptsExceeded
+	return nil, context.newMaxAttemptsExceededError()
+}
+
+// getFirstDecisionLatency returns the time from the start of a workflow execution to the start of its first decision,
+// which grows when the workers polling the task list of the workflow cannot keep up.  Decisions retried after a failure
+// or timeout are left out, as their latency includes the failed attempts and the decision backoff.
+func getFirstDecisionLatency(msBuilder *mutableStateBuilder, di *decisionInfo, now time.Time) (time.Duration, bool) {
+	executionInfo := msBuilder.executionInfo
+	if di.Attempt > 0 || executionInfo.LastProcessedEvent != emptyEventID || executionInfo.StartTimestamp.IsZero() {
+		return 0, false
+	}
+	return now.Sub(executionInfo.StartTimestamp), true
 }
 
 func (e *historyEngineImpl) RecordActivityTaskStarted(
@@ -697,6 +1229,9 @@ func (e *historyEngineImpl) RecordActivityTaskStarted(
 	if err != nil {
 		return nil, err
 	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return nil, err
+	}
 
 	execution := workflow.WorkflowExecution{
 		WorkflowId: request.WorkflowExecution.WorkflowId,
@@ -704,6 +1239,8 @@ func (e *historyEngineImpl) RecordActivityTaskStarted(
 	}
 
 	response := &h.RecordActivityTaskStartedResponse{}
+	var scheduleToStartLatency time.Duration
+	activityStarted := false
 	err = e.updateWorkflowExecution(domainID, execution, false, false,
 		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error) {
 			if !msBuilder.isWorkflowExecutionRunning() {
@@ -717,7 +1254,8 @@ func (e *historyEngineImpl) RecordActivityTaskStarted(
 			// First check to see if cache needs to be refreshed as we could potentially have stale workflow execution in
 			// some extreme cassandra failure cases.
 			if !isRunning && scheduleID >= msBuilder.GetNextEventID() {
-				e.metricsClient.IncCounter(metrics.HistoryRecordActivityTaskStartedScope, metrics.StaleMutableStateCounter)
+				e.emitStaleStateReload(metrics.HistoryRecordActivityTaskStartedScope, "RecordActivityTaskStarted",
+					msBuilder.executionInfo)
 				return nil, ErrStaleState
 			}
 
@@ -739,8 +1277,9 @@ func (e *historyEngineImpl) RecordActivityTaskStarted(
 			response.ScheduledEvent = scheduledEvent
 
 			if ai.StartedID != emptyEventID {
-				// If activity is started as part of the current request scope then return a positive response
-				if ai.RequestID == requestID {
+				// If activity is started as part of the current request scope, or the poller which started it is
+				// retrying, then return a positive response
+				if ai.RequestID == requestID || e.isRetriedActivityStart(ai, request.PollRequest.GetIdentity()) {
 					startedEvent, exists := msBuilder.GetActivityStartedEvent(scheduleID)
 					if !exists {
 						return nil, &workflow.InternalServiceError{Message: "Corrupted workflow execution state."}
@@ -763,6 +1302,8 @@ func (e *historyEngineImpl) RecordActivityTaskStarted(
 				return nil, &workflow.InternalServiceError{Message: "Unable to add ActivityTaskStarted event to history."}
 			}
 			response.StartedEvent = startedEvent
+			scheduleToStartLatency = e.shard.GetTimeSource().Now().Sub(ai.ScheduledTime)
+			activityStarted = true
 
 			// Start a timer for the activity task.
 			timerTasks := []persistence.Task{}
@@ -777,19 +1318,39 @@ func (e *historyEngineImpl) RecordActivityTaskStarted(
 		return nil, err
 	}
 
+	if activityStarted {
+		taskList := response.ScheduledEvent.ActivityTaskScheduledEventAttributes.TaskList.GetName()
+		e.metricsClient.Tagged(map[string]string{
+			metrics.DomainTagName:   getMetricsDomainName(e.shard, domainID),
+			metrics.TaskListTagName: taskList,
+		}).RecordTimer(metrics.HistoryRecordActivityTaskStartedScope, metrics.ActivityScheduleToStartLatency,
+			scheduleToStartLatency)
+	}
+
 	return response, err
 }
 
 // RespondDecisionTaskCompleted completes a decision task
-func (e *historyEngineImpl) RespondDecisionTaskCompleted(ctx context.Context, req *h.RespondDecisionTaskCompletedRequest) (retError error) {
+func (e *historyEngineImpl) RespondDecisionTaskCompleted(ctx context.Context,
+	req *h.RespondDecisionTaskCompletedRequest) (retResp *h.RespondDecisionTaskCompletedResponse, retError error) {
 	domainID, err := getDomainUUID(req.DomainUUID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return nil, err
 	}
+
+	endOperation, err := e.beginWorkflowUpdate(domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer endOperation()
+
 	request := req.CompleteRequest
 	token, err0 := e.tokenSerializer.Deserialize(request.TaskToken)
 	if err0 != nil {
-		return ErrDeserializingToken
+		return nil, ErrDeserializingToken
 	}
 
 	workflowExecution := workflow.WorkflowExecution{
@@ -801,19 +1362,31 @@ func (e *historyEngineImpl) RespondDecisionTaskCompleted(ctx context.Context, re
 	clientLibVersion := call.Header(common.LibraryVersionHeaderName)
 	clientFeatureVersion := call.Header(common.FeatureVersionHeaderName)
 	clientImpl := call.Header(common.ClientImplHeaderName)
+	binaryChecksum := call.Header(common.BinaryChecksumHeaderName)
+
+	disabledDecisionTypes, err := e.getDisabledDecisionTypes(domainID)
+	if err != nil {
+		return nil, err
+	}
 
 	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(domainID, workflowExecution)
 	if err0 != nil {
-		return err0
+		return nil, err0
 	}
 	defer func() { release(retError) }()
 
+	var conflictSnapshot *decisionConflictSnapshot
 Update_History_Loop:
 	for attempt := 0; attempt < conditionalRetryCount; attempt++ {
 		msBuilder, err1 := context.loadWorkflowExecution()
 		if err1 != nil {
-			return err1
+			return nil, err1
 		}
+		if conflictSnapshot != nil {
+			e.emitDecisionConflict(domainID, conflictSnapshot.classify(msBuilder))
+			conflictSnapshot = nil
+		}
+		snapshot := newDecisionConflictSnapshot(msBuilder)
 		tBuilder := e.getTimerBuilder(&context.workflowExecution)
 
 		scheduleID := token.ScheduleID
@@ -822,7 +1395,8 @@ Update_History_Loop:
 		// First check to see if cache needs to be refreshed as we could potentially have stale workflow execution in
 		// some extreme cassandra failure cases.
 		if !isRunning && scheduleID >= msBuilder.GetNextEventID() {
-			e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.StaleMutableStateCounter)
+			e.emitStaleStateReload(metrics.HistoryRespondDecisionTaskCompletedScope, "RespondDecisionTaskCompleted",
+				msBuilder.executionInfo)
 			// Reload workflow execution history
 			context.clear()
 			continue Update_History_Loop
@@ -830,13 +1404,13 @@ Update_History_Loop:
 
 		if !msBuilder.isWorkflowExecutionRunning() || !isRunning || di.Attempt != token.ScheduleAttempt ||
 			di.StartedID == emptyEventID {
-			return &workflow.EntityNotExistsError{Message: "Decision task not found."}
+			return nil, &workflow.EntityNotExistsError{Message: "Decision task not found."}
 		}
 
 		startedID := di.StartedID
 		completedEvent := msBuilder.AddDecisionTaskCompletedEvent(scheduleID, startedID, request)
 		if completedEvent == nil {
-			return &workflow.InternalServiceError{Message: "Unable to add DecisionTaskCompleted event to history."}
+			return nil, &workflow.InternalServiceError{Message: "Unable to add DecisionTaskCompleted event to history."}
 		}
 
 		failDecision := false
@@ -844,61 +1418,60 @@ Update_History_Loop:
 		var err error
 		completedID := *completedEvent.EventId
 		hasUnhandledEvents := msBuilder.HasBufferedEvents()
+		// events a workflow execution targeting itself adds in place are handled by a new decision, but unlike the
+		// events which came in during the decision they do not keep the decision from closing the workflow
+		hasSelfTargetedEvents := false
 		isComplete := false
 		transferTasks := []persistence.Task{}
 		timerTasks := []persistence.Task{}
 		var continueAsNewBuilder *mutableStateBuilder
 		var continueAsNewTimerTasks []persistence.Task
+		var newDecision *decisionInfo
 		hasDecisionScheduleActivityTask := false
+		// scheduled event ids of the activities scheduled by the decisions, returned to the worker
+		var activityScheduledEventIDs map[string]int64
 
-		if request.StickyAttributes == nil || request.StickyAttributes.WorkerTaskList == nil {
+		binaryChecksumDenied := e.isBinaryChecksumDenied(binaryChecksum)
+		if binaryChecksumDenied {
+			e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.DeniedBinaryChecksumCounter)
+		}
+		if !e.isValidStickyAttributes(request.StickyAttributes, msBuilder.executionInfo) || binaryChecksumDenied {
 			e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.CompleteDecisionWithStickyDisabledCounter)
 			msBuilder.executionInfo.StickyTaskList = ""
 			msBuilder.executionInfo.StickyScheduleToStartTimeout = 0
 		} else {
 			e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.CompleteDecisionWithStickyEnabledCounter)
 			msBuilder.executionInfo.StickyTaskList = request.StickyAttributes.WorkerTaskList.GetName()
-			msBuilder.executionInfo.StickyScheduleToStartTimeout = request.StickyAttributes.GetScheduleToStartTimeoutSeconds()
+			msBuilder.executionInfo.StickyScheduleToStartTimeout = e.clampStickyScheduleToStartTimeout(
+				request.StickyAttributes.GetScheduleToStartTimeoutSeconds(), msBuilder.executionInfo)
 		}
 		msBuilder.executionInfo.ClientLibraryVersion = clientLibVersion
 		msBuilder.executionInfo.ClientFeatureVersion = clientFeatureVersion
 		msBuilder.executionInfo.ClientImpl = clientImpl
+		msBuilder.executionInfo.BinaryChecksum = binaryChecksum
+		// a reset to the completed decision loses the activities already started, so such a point is not safe
+		msBuilder.addResetPoint(completedID, binaryChecksum, !hasUnhandledEvents,
+			!hasUnhandledEvents && countStartedActivities(msBuilder) == 0, e.shard.GetTimeSource().Now())
 
 	Process_Decision_Loop:
 		for _, d := range request.Decisions {
-			switch *d.DecisionType {
-			case workflow.DecisionTypeScheduleActivityTask:
-				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-					metrics.DecisionTypeScheduleActivityCounter)
-				targetDomainID := domainID
-				attributes := d.ScheduleActivityTaskDecisionAttributes
-				// First check if we need to use a different target domain to schedule activity
-				if attributes.Domain != nil {
-					// TODO: Error handling for ActivitySchedule failed when domain lookup fails
-					domainEntry, err := e.shard.GetDomainCache().GetDomain(*attributes.Domain)
-					if err != nil {
-						return &workflow.InternalServiceError{Message: "Unable to schedule activity across domain."}
-					}
-					targetDomainID = domainEntry.GetInfo().ID
-				}
-
-				if err = validateActivityScheduleAttributes(attributes); err != nil {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseBadScheduleActivityAttributes
-					break Process_Decision_Loop
+			// Nothing but further completion decisions, which are dropped, may follow a decision closing the workflow
+			if isComplete && !isCompletionDecision(d.GetDecisionType()) {
+				err = &workflow.BadRequestError{
+					Message: fmt.Sprintf("Decision %v follows a decision closing the workflow.", d.GetDecisionType()),
 				}
+				failDecision = true
+				failCause = workflow.DecisionTaskFailedCauseUnhandledDecision
+				break Process_Decision_Loop
+			}
 
-				scheduleEvent, _ := msBuilder.AddActivityTaskScheduledEvent(completedID, attributes)
-				transferTasks = append(transferTasks, &persistence.ActivityTask{
-					DomainID:   targetDomainID,
-					TaskList:   *attributes.TaskList.Name,
-					ScheduleID: *scheduleEvent.EventId,
-				})
-				hasDecisionScheduleActivityTask = true
+			if counter, ok := decisionTypeCounters[d.GetDecisionType()]; ok {
+				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope, counter)
+			}
 
-			case workflow.DecisionTypeCompleteWorkflowExecution:
-				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-					metrics.DecisionTypeCompleteWorkflowCounter)
+			if isCompletionDecision(d.GetDecisionType()) {
+				// If new events came while we are processing the decision, we would fail this and give a chance to
+				// client to process the new event.
 				if hasUnhandledEvents {
 					failDecision = true
 					failCause = workflow.DecisionTaskFailedCauseUnhandledDecision
@@ -912,96 +1485,90 @@ Update_History_Loop:
 					logging.LogMultipleCompletionDecisionsEvent(e.logger, *d.DecisionType)
 					continue Process_Decision_Loop
 				}
-				attributes := d.CompleteWorkflowExecutionDecisionAttributes
-				if err = validateCompleteWorkflowExecutionAttributes(attributes); err != nil {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseBadCompleteWorkflowExecutionAttributes
-					break Process_Decision_Loop
+			}
+
+			var targetDomainID string
+			var validationCause *workflow.DecisionTaskFailedCause
+			if targetDomainID, validationCause, err = e.validateDecision(domainID, msBuilder.executionInfo,
+				disabledDecisionTypes, d); err != nil {
+				if validationCause == nil {
+					return nil, err
 				}
-				if e := msBuilder.AddCompletedWorkflowEvent(completedID, attributes); e == nil {
-					return &workflow.InternalServiceError{Message: "Unable to add complete workflow event."}
+				if *validationCause == workflow.DecisionTaskFailedCauseDecisionTypeDisabled {
+					e.logger.WithFields(bark.Fields{
+						logging.TagDomainID:            domainID,
+						logging.TagWorkflowExecutionID: token.WorkflowID,
+						logging.TagWorkflowRunID:       token.RunID,
+						logging.TagDecisionType:        d.GetDecisionType(),
+					}).Warnf("Failing decision task, decision type %v is disabled for the domain.", d.GetDecisionType())
 				}
-				isComplete = true
-			case workflow.DecisionTypeFailWorkflowExecution:
-				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-					metrics.DecisionTypeFailWorkflowCounter)
-				if hasUnhandledEvents {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseUnhandledDecision
-					break Process_Decision_Loop
+				failDecision = true
+				failCause = *validationCause
+				break Process_Decision_Loop
+			}
+
+			switch *d.DecisionType {
+			case workflow.DecisionTypeScheduleActivityTask:
+				attributes := d.ScheduleActivityTaskDecisionAttributes
+				scheduleEvent, ai := msBuilder.AddActivityTaskScheduledEvent(completedID, attributes)
+				ai.TaskList = e.shard.GetConfig().ActivityTaskListResolver(targetDomainID, attributes.TaskList.GetName())
+				transferTasks = append(transferTasks, &persistence.ActivityTask{
+					DomainID:   targetDomainID,
+					TaskList:   ai.TaskList,
+					ScheduleID: *scheduleEvent.EventId,
+					Priority:   scheduleEvent.ActivityTaskScheduledEventAttributes.GetPriority(),
+				})
+				hasDecisionScheduleActivityTask = true
+				if activityScheduledEventIDs == nil {
+					activityScheduledEventIDs = make(map[string]int64)
 				}
+				activityScheduledEventIDs[ai.ActivityID] = *scheduleEvent.EventId
 
-				// If the decision has more than one completion event than just pick the first one
-				if isComplete {
-					e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-						metrics.MultipleCompletionDecisionsCounter)
-					logging.LogMultipleCompletionDecisionsEvent(e.logger, *d.DecisionType)
-					continue Process_Decision_Loop
+			case workflow.DecisionTypeCompleteWorkflowExecution:
+				attributes := d.CompleteWorkflowExecutionDecisionAttributes
+				result, err := e.offloadPayload(metrics.HistoryRespondDecisionTaskCompletedScope, domainID,
+					msBuilder.executionInfo, attributes.Result)
+				if err != nil {
+					return nil, err
+				}
+				attributes = &workflow.CompleteWorkflowExecutionDecisionAttributes{Result: result}
+				e.cancelPendingActivities(msBuilder, completedID, common.StringDefault(request.Identity))
+				if event := msBuilder.AddCompletedWorkflowEvent(completedID, attributes); event == nil {
+					return nil, &workflow.InternalServiceError{Message: "Unable to add complete workflow event."}
 				}
+				isComplete = true
+			case workflow.DecisionTypeFailWorkflowExecution:
 				attributes := d.FailWorkflowExecutionDecisionAttributes
-				if err = validateFailWorkflowExecutionAttributes(attributes); err != nil {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseBadFailWorkflowExecutionAttributes
-					break Process_Decision_Loop
+				details, err := e.offloadPayload(metrics.HistoryRespondDecisionTaskCompletedScope, domainID,
+					msBuilder.executionInfo, attributes.Details)
+				if err != nil {
+					return nil, err
+				}
+				attributes = &workflow.FailWorkflowExecutionDecisionAttributes{
+					Reason:  attributes.Reason,
+					Details: details,
 				}
-				if e := msBuilder.AddFailWorkflowEvent(completedID, attributes); e == nil {
-					return &workflow.InternalServiceError{Message: "Unable to add fail workflow event."}
+				e.cancelPendingActivities(msBuilder, completedID, common.StringDefault(request.Identity))
+				if event := msBuilder.AddFailWorkflowEvent(completedID, attributes); event == nil {
+					return nil, &workflow.InternalServiceError{Message: "Unable to add fail workflow event."}
 				}
 				isComplete = true
 			case workflow.DecisionTypeCancelWorkflowExecution:
-				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-					metrics.DecisionTypeCancelWorkflowCounter)
-				// If new events came while we are processing the decision, we would fail this and give a chance to client
-				// to process the new event.
-				if hasUnhandledEvents {
+				e.cancelPendingActivities(msBuilder, completedID, common.StringDefault(request.Identity))
+				msBuilder.AddWorkflowExecutionCanceledEvent(completedID, d.CancelWorkflowExecutionDecisionAttributes)
+				isComplete = true
+
+			case workflow.DecisionTypeStartTimer:
+				_, ti := msBuilder.AddTimerStartedEvent(completedID, d.StartTimerDecisionAttributes)
+				if ti == nil {
 					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseUnhandledDecision
+					failCause = workflow.DecisionTaskFailedCauseStartTimerDuplicateID
 					break Process_Decision_Loop
 				}
-
-				// If the decision has more than one completion event than just pick the first one
-				if isComplete {
-					e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-						metrics.MultipleCompletionDecisionsCounter)
-					logging.LogMultipleCompletionDecisionsEvent(e.logger, *d.DecisionType)
-					continue Process_Decision_Loop
-				}
-				attributes := d.CancelWorkflowExecutionDecisionAttributes
-				if err = validateCancelWorkflowExecutionAttributes(attributes); err != nil {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseBadCancelWorkflowExecutionAttributes
-					break Process_Decision_Loop
-				}
-				msBuilder.AddWorkflowExecutionCanceledEvent(completedID, attributes)
-				isComplete = true
-
-			case workflow.DecisionTypeStartTimer:
-				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-					metrics.DecisionTypeStartTimerCounter)
-				attributes := d.StartTimerDecisionAttributes
-				if err = validateTimerScheduleAttributes(attributes); err != nil {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseBadStartTimerAttributes
-					break Process_Decision_Loop
-				}
-				_, ti := msBuilder.AddTimerStartedEvent(completedID, attributes)
-				if ti == nil {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseStartTimerDuplicateID
-					break Process_Decision_Loop
-				}
-				tBuilder.AddUserTimer(ti, context.msBuilder)
+				tBuilder.AddUserTimer(ti, context.msBuilder)
 
 			case workflow.DecisionTypeRequestCancelActivityTask:
-				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-					metrics.DecisionTypeCancelActivityCounter)
-				attributes := d.RequestCancelActivityTaskDecisionAttributes
-				if err = validateActivityCancelAttributes(attributes); err != nil {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseBadRequestCancelActivityAttributes
-					break Process_Decision_Loop
-				}
-				activityID := *attributes.ActivityId
+				activityID := d.RequestCancelActivityTaskDecisionAttributes.GetActivityId()
 				actCancelReqEvent, ai, isRunning := msBuilder.AddActivityTaskCancelRequestedEvent(completedID, activityID,
 					common.StringDefault(request.Identity))
 				if !isRunning {
@@ -1017,98 +1584,86 @@ Update_History_Loop:
 				}
 
 			case workflow.DecisionTypeCancelTimer:
-				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-					metrics.DecisionTypeCancelTimerCounter)
 				attributes := d.CancelTimerDecisionAttributes
-				if err = validateTimerCancelAttributes(attributes); err != nil {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseBadCancelTimerAttributes
-					break Process_Decision_Loop
-				}
 				if msBuilder.AddTimerCanceledEvent(completedID, attributes, common.StringDefault(request.Identity)) == nil {
 					msBuilder.AddCancelTimerFailedEvent(completedID, attributes, common.StringDefault(request.Identity))
 				}
 
 			case workflow.DecisionTypeRecordMarker:
-				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-					metrics.DecisionTypeRecordMarkerCounter)
-				attributes := d.RecordMarkerDecisionAttributes
-				if err = validateRecordMarkerAttributes(attributes); err != nil {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseBadRecordMarkerAttributes
-					break Process_Decision_Loop
-				}
-				msBuilder.AddRecordMarkerEvent(completedID, attributes)
+				msBuilder.AddRecordMarkerEvent(completedID, d.RecordMarkerDecisionAttributes)
 
 			case workflow.DecisionTypeRequestCancelExternalWorkflowExecution:
-				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-					metrics.DecisionTypeCancelExternalWorkflowCounter)
 				attributes := d.RequestCancelExternalWorkflowExecutionDecisionAttributes
-				if err = validateCancelExternalWorkflowExecutionAttributes(attributes); err != nil {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseBadRequestCancelExternalWorkflowExecutionAttributes
-					break Process_Decision_Loop
-				}
-
-				foreignDomainID := ""
-				if attributes.GetDomain() == "" {
-					foreignDomainID = msBuilder.executionInfo.DomainID
-				} else {
-					foreignDomainEntry, err := e.shard.GetDomainCache().GetDomain(attributes.GetDomain())
-					if err != nil {
-						return &workflow.InternalServiceError{
-							Message: fmt.Sprintf("Unable to cancel workflow across domain: %v.", attributes.GetDomain())}
-					}
-					foreignDomainID = foreignDomainEntry.GetInfo().ID
-				}
+				selfTargeted := !attributes.GetChildWorkflowOnly() && isSelfTargeted(msBuilder.executionInfo,
+					targetDomainID, attributes.GetWorkflowId(), attributes.GetRunId())
 
 				cancelRequestID := uuid.New()
 				wfCancelReqEvent, _ := msBuilder.AddRequestCancelExternalWorkflowExecutionInitiatedEvent(completedID,
 					cancelRequestID, attributes)
 				if wfCancelReqEvent == nil {
-					return &workflow.InternalServiceError{Message: "Unable to add external cancel workflow request."}
+					return nil, &workflow.InternalServiceError{Message: "Unable to add external cancel workflow request."}
 				}
 
-				transferTasks = append(transferTasks, &persistence.CancelExecutionTask{
-					TargetDomainID:          foreignDomainID,
-					TargetWorkflowID:        attributes.GetWorkflowId(),
-					TargetRunID:             attributes.GetRunId(),
-					TargetChildWorkflowOnly: attributes.GetChildWorkflowOnly(),
-					InitiatedID:             wfCancelReqEvent.GetEventId(),
-				})
-
-			case workflow.DecisionTypeSignalExternalWorkflowExecution:
-				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-					metrics.DecisionTypeSignalExternalWorkflowCounter)
-
-				attributes := d.SignalExternalWorkflowExecutionDecisionAttributes
-				if err = validateSignalExternalWorkflowExecutionAttributes(attributes); err != nil {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseBadSignalWorkflowExecutionAttributes
-					break Process_Decision_Loop
+				if selfTargeted {
+					// A workflow execution canceling itself is canceled in place, a transfer task would fail the request
+					if err := cancelSelf(msBuilder, wfCancelReqEvent.GetEventId(), cancelRequestID); err != nil {
+						return nil, err
+					}
+					hasSelfTargetedEvents = true
+					continue Process_Decision_Loop
 				}
 
-				foreignDomainID := ""
-				if attributes.GetDomain() == "" {
-					foreignDomainID = msBuilder.executionInfo.DomainID
-				} else {
-					foreignDomainEntry, err := e.shard.GetDomainCache().GetDomain(attributes.GetDomain())
-					if err != nil {
-						return &workflow.InternalServiceError{
-							Message: fmt.Sprintf("Unable to signal workflow across domain: %v.", attributes.GetDomain())}
+				// A child which is not started yet has no run to cancel.  Drop the pending child instead, which turns
+				// the StartChildExecution transfer task for it into a no-op.
+				var childInitiatedEvent *workflow.HistoryEvent
+				if attributes.GetRunId() == "" {
+					childInitiatedEvent = e.getPendingChildInitiatedEvent(msBuilder, targetDomainID,
+						attributes.GetWorkflowId())
+				}
+				if childInitiatedEvent != nil {
+					if msBuilder.AddExternalWorkflowExecutionCancelRequested(wfCancelReqEvent.GetEventId(),
+						targetDomainID, attributes.GetWorkflowId(), "") == nil {
+						return nil, &workflow.InternalServiceError{Message: "Unable to add external cancel requested event."}
 					}
-					foreignDomainID = foreignDomainEntry.GetInfo().ID
+					if msBuilder.AddStartChildWorkflowExecutionFailedEvent(childInitiatedEvent.GetEventId(),
+						workflow.ChildWorkflowExecutionFailedCauseCanceledBeforeStart,
+						childInitiatedEvent.StartChildWorkflowExecutionInitiatedEventAttributes) == nil {
+						return nil, &workflow.InternalServiceError{Message: "Unable to add start child execution failed event."}
+					}
+				} else {
+					transferTasks = append(transferTasks, &persistence.CancelExecutionTask{
+						TargetDomainID:          targetDomainID,
+						TargetWorkflowID:        attributes.GetWorkflowId(),
+						TargetRunID:             attributes.GetRunId(),
+						TargetChildWorkflowOnly: attributes.GetChildWorkflowOnly(),
+						InitiatedID:             wfCancelReqEvent.GetEventId(),
+					})
 				}
 
+			case workflow.DecisionTypeSignalExternalWorkflowExecution:
+				attributes := d.SignalExternalWorkflowExecutionDecisionAttributes
+				selfTargeted := !attributes.GetChildWorkflowOnly() && isSelfTargeted(msBuilder.executionInfo,
+					targetDomainID, attributes.Execution.GetWorkflowId(), attributes.Execution.GetRunId())
+
 				signalRequestID := uuid.New() // for deduplicate
 				wfSignalReqEvent := msBuilder.AddSignalExternalWorkflowExecutionInitiatedEvent(completedID,
 					signalRequestID, attributes)
 				if wfSignalReqEvent == nil {
-					return &workflow.InternalServiceError{Message: "Unable to add external signal workflow request."}
+					return nil, &workflow.InternalServiceError{Message: "Unable to add external signal workflow request."}
+				}
+
+				if selfTargeted {
+					// A workflow execution signaling itself gets the signal in place, a transfer task would fail it
+					if err := signalSelf(msBuilder, wfSignalReqEvent.GetEventId(), signalRequestID,
+						attributes); err != nil {
+						return nil, err
+					}
+					hasSelfTargetedEvents = true
+					continue Process_Decision_Loop
 				}
 
 				transferTasks = append(transferTasks, &persistence.SignalExecutionTask{
-					TargetDomainID:          foreignDomainID,
+					TargetDomainID:          targetDomainID,
 					TargetWorkflowID:        attributes.Execution.GetWorkflowId(),
 					TargetRunID:             attributes.Execution.GetRunId(),
 					TargetChildWorkflowOnly: attributes.GetChildWorkflowOnly(),
@@ -1116,31 +1671,22 @@ Update_History_Loop:
 				})
 
 			case workflow.DecisionTypeContinueAsNewWorkflowExecution:
-				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-					metrics.DecisionTypeContinueAsNewCounter)
-				if hasUnhandledEvents {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseUnhandledDecision
-					break Process_Decision_Loop
-				}
+				attributes := d.ContinueAsNewWorkflowExecutionDecisionAttributes
 
-				// If the decision has more than one completion event than just pick the first one
-				if isComplete {
-					e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-						metrics.MultipleCompletionDecisionsCounter)
-					logging.LogMultipleCompletionDecisionsEvent(e.logger, *d.DecisionType)
+				// A loop workflow which keeps continuing as new past the configured generation is timed out instead
+				maxGenerations := e.shard.GetConfig().MaxContinueAsNewGenerations
+				if maxGenerations > 0 && msBuilder.executionInfo.Generation+1 > maxGenerations {
+					e.cancelPendingActivities(msBuilder, completedID, common.StringDefault(request.Identity))
+					if event := msBuilder.AddTimeoutWorkflowEvent(); event == nil {
+						return nil, &workflow.InternalServiceError{Message: "Unable to add timeout workflow event."}
+					}
+					isComplete = true
 					continue Process_Decision_Loop
 				}
-				attributes := d.ContinueAsNewWorkflowExecutionDecisionAttributes
-				if err = validateContinueAsNewWorkflowExecutionAttributes(msBuilder.executionInfo, attributes); err != nil {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseBadContinueAsNewAttributes
-					break Process_Decision_Loop
-				}
 
 				domainEntry, err := e.shard.GetDomainCache().GetDomainByID(domainID)
 				if err != nil {
-					return err
+					return nil, err
 				}
 				domainName := domainEntry.GetInfo().Name
 
@@ -1150,16 +1696,17 @@ Update_History_Loop:
 					parentDomainID := msBuilder.executionInfo.ParentDomainID
 					parentDomainEntry, err := e.shard.GetDomainCache().GetDomainByID(parentDomainID)
 					if err != nil {
-						return err
+						return nil, err
 					}
 					parentDomainName = parentDomainEntry.GetInfo().Name
 				}
 
+				e.cancelPendingActivities(msBuilder, completedID, common.StringDefault(request.Identity))
 				runID := uuid.New()
 				_, newStateBuilder, err := msBuilder.AddContinueAsNewEvent(completedID, domainID, domainName, runID,
 					parentDomainName, attributes)
 				if err != nil {
-					return err
+					return nil, err
 				}
 
 				// add timer task to new workflow
@@ -1173,26 +1720,7 @@ Update_History_Loop:
 				continueAsNewBuilder = newStateBuilder
 
 			case workflow.DecisionTypeStartChildWorkflowExecution:
-				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
-					metrics.DecisionTypeChildWorkflowCounter)
-				targetDomainID := domainID
 				attributes := d.StartChildWorkflowExecutionDecisionAttributes
-				if err = validateStartChildExecutionAttributes(msBuilder.executionInfo, attributes); err != nil {
-					failDecision = true
-					failCause = workflow.DecisionTaskFailedCauseBadStartChildExecutionAttributes
-					break Process_Decision_Loop
-				}
-
-				// First check if we need to use a different target domain to schedule child execution
-				if attributes.Domain != nil {
-					// TODO: Error handling for DecisionType_StartChildWorkflowExecution failed when domain lookup fails
-					domainEntry, err := e.shard.GetDomainCache().GetDomain(*attributes.Domain)
-					if err != nil {
-						return &workflow.InternalServiceError{Message: "Unable to schedule child execution across domain."}
-					}
-					targetDomainID = domainEntry.GetInfo().ID
-				}
-
 				requestID := uuid.New()
 				initiatedEvent, _ := msBuilder.AddStartChildWorkflowExecutionInitiatedEvent(completedID, requestID, attributes)
 				transferTasks = append(transferTasks, &persistence.StartChildExecutionTask{
@@ -1202,21 +1730,42 @@ Update_History_Loop:
 				})
 
 			default:
-				return &workflow.BadRequestError{Message: fmt.Sprintf("Unknown decision type: %v", *d.DecisionType)}
+				return nil, &workflow.BadRequestError{Message: fmt.Sprintf("Unknown decision type: %v", *d.DecisionType)}
 			}
 		}
 
 		if failDecision {
 			e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.FailedDecisionsCounter)
 			logging.LogDecisionFailedEvent(e.logger, domainID, token.WorkflowID, token.RunID, failCause)
+			var timeoutWorkflow bool
 			var err1 error
-			msBuilder, err1 = e.failDecision(context, scheduleID, startedID, failCause, request)
+			msBuilder, timeoutWorkflow, err1 = e.failDecision(context, scheduleID, startedID, failCause, request)
 			if err1 != nil {
-				return err1
+				return nil, err1
 			}
 			isComplete = false
 			hasUnhandledEvents = true
+			hasSelfTargetedEvents = false
 			continueAsNewBuilder = nil
+			activityScheduledEventIDs = nil
+			if timeoutWorkflow {
+				// the workflow is timed out right away, so there is no point in retrying the decision
+				timerTasks = append(timerTasks, tBuilder.AddWorkflowTimeoutNowTask())
+				hasUnhandledEvents = false
+			}
+		}
+
+		var emptyDecisionBackoff time.Duration
+		if len(request.Decisions) == 0 && !failDecision {
+			if !hasUnhandledEvents {
+				// nothing but the DecisionTaskCompleted event is written for this decision
+				e.metricsClient.Tagged(map[string]string{
+					metrics.WorkflowTypeTagName: msBuilder.executionInfo.WorkflowTypeName,
+				}).IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.EmptyDecisionCounter)
+			} else {
+				// the events which came in are still handled, by a decision which is held back for a while
+				emptyDecisionBackoff = e.shard.GetConfig().EmptyDecisionBackoffInterval
+			}
 		}
 
 		if tt := tBuilder.GetUserTimerTaskIfNeeded(msBuilder); tt != nil {
@@ -1229,25 +1778,47 @@ Update_History_Loop:
 		}
 
 		// Schedule another decision task if new events came in during this decision
-		if hasUnhandledEvents {
+		if (hasUnhandledEvents || (hasSelfTargetedEvents && !isComplete)) && !msBuilder.isPaused() {
 			di := msBuilder.AddDecisionTaskScheduledEvent()
-			transferTasks = append(transferTasks, &persistence.DecisionTask{
-				DomainID:   domainID,
-				TaskList:   di.Tasklist,
-				ScheduleID: di.ScheduleID,
-			})
-			if msBuilder.isStickyTaskListEnabled() {
-				tBuilder := e.getTimerBuilder(&context.workflowExecution)
-				stickyTaskTimeoutTimer := tBuilder.AddScheduleToStartDecisionTimoutTask(di.ScheduleID, di.Attempt,
-					msBuilder.executionInfo.StickyScheduleToStartTimeout)
-				timerTasks = append(timerTasks, stickyTaskTimeoutTimer)
+			backoffInterval := e.getDecisionBackoff(di.Attempt)
+			if backoffInterval < emptyDecisionBackoff {
+				backoffInterval = emptyDecisionBackoff
+			}
+			if backoffInterval > 0 {
+				timerTasks = append(timerTasks, tBuilder.AddDecisionBackoffTask(di.ScheduleID, di.Attempt,
+					backoffInterval))
+			} else if request.GetReturnNewDecisionTask() && !failDecision && msBuilder.isStickyTaskListEnabled() {
+				// The completing worker owns the sticky task list, so start the new decision right away and hand it
+				// back in the response instead of dispatching it through matching.
+				_, newDecision = msBuilder.AddDecisionTaskStartedEvent(di.ScheduleID, uuid.New(),
+					&workflow.PollForDecisionTaskRequest{
+						TaskList: &workflow.TaskList{Name: common.StringPtr(msBuilder.executionInfo.StickyTaskList)},
+						Identity: request.Identity,
+					})
+				if newDecision == nil {
+					return nil, &workflow.InternalServiceError{Message: "Unable to add DecisionTaskStarted event to history."}
+				}
+				timerTasks = append(timerTasks, tBuilder.AddDecisionTimoutTask(newDecision.ScheduleID,
+					newDecision.Attempt, newDecision.DecisionTimeout))
+			} else {
+				transferTasks = append(transferTasks, &persistence.DecisionTask{
+					DomainID:   domainID,
+					TaskList:   di.Tasklist,
+					ScheduleID: di.ScheduleID,
+				})
+				if msBuilder.isStickyTaskListEnabled() {
+					tBuilder := e.getTimerBuilder(&context.workflowExecution)
+					stickyTaskTimeoutTimer := tBuilder.AddScheduleToStartDecisionTimoutTask(di.ScheduleID, di.Attempt,
+						msBuilder.executionInfo.StickyScheduleToStartTimeout)
+					timerTasks = append(timerTasks, stickyTaskTimeoutTimer)
+				}
 			}
 		}
 
 		if isComplete {
-			tranT, timerT, err := e.getDeleteWorkflowTasks(domainID, tBuilder)
+			tranT, timerT, err := e.getDeleteWorkflowTasks(domainID, msBuilder.executionInfo.WorkflowTypeName, tBuilder)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			transferTasks = append(transferTasks, tranT)
 			timerTasks = append(timerTasks, timerT)
@@ -1256,7 +1827,7 @@ Update_History_Loop:
 		// Generate a transaction ID for appending events to history
 		transactionID, err3 := e.shard.GetNextTransferTaskID()
 		if err3 != nil {
-			return err3
+			return nil, err3
 		}
 
 		// We apply the update to execution using optimistic concurrency.  If it fails due to a conflict then reload
@@ -1274,10 +1845,16 @@ Update_History_Loop:
 			if updateErr == ErrConflict {
 				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
 					metrics.ConcurrencyUpdateFailureCounter)
+				conflictSnapshot = snapshot
 				continue Update_History_Loop
 			}
 
-			return updateErr
+			return nil, updateErr
+		}
+
+		if isComplete {
+			emitWorkflowClosed(e.shard, e.metricsClient, metrics.HistoryRespondDecisionTaskCompletedScope, domainID,
+				msBuilder.executionInfo.CloseStatus)
 		}
 
 		// add continueAsNewTimerTask
@@ -1285,10 +1862,65 @@ Update_History_Loop:
 		// Inform timer about the new ones.
 		e.timerProcessor.NotifyNewTimers(e.currentClusterName, timerTasks)
 
-		return err
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &h.RespondDecisionTaskCompletedResponse{ActivityScheduledEventIds: activityScheduledEventIDs}
+		if newDecision != nil {
+			resp.StartedResponse = e.createRecordDecisionTaskStartedResponse(domainID, msBuilder, newDecision,
+				request.GetIdentity())
+		}
+		return resp, nil
+	}
+
+	return nil, context.newMaxAttemptsExceededError()
+}
+
+// isCompletionDecision returns true for the decisions which close the workflow
+func isCompletionDecision(decisionType workflow.DecisionType) bool {
+	switch decisionType {
+	case workflow.DecisionTypeCompleteWorkflowExecution,
+		workflow.DecisionTypeFailWorkflowExecution,
+		workflow.DecisionTypeCancelWorkflowExecution,
+		workflow.DecisionTypeContinueAsNewWorkflowExecution:
+		return true
+	default:
+		return false
 	}
+}
 
-	return ErrMaxAttemptsExceeded
+// cancelPendingActivities records the cancellation of every activity still pending when the decision closes the
+// workflow, so no activity outlives the execution which scheduled it.
+func (e *historyEngineImpl) cancelPendingActivities(msBuilder *mutableStateBuilder, decisionCompletedID int64,
+	identity string) {
+	if len(msBuilder.pendingActivityInfoIDs) == 0 {
+		return
+	}
+	e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
+		metrics.WorkflowClosedWithPendingActivitiesCounter)
+
+	scheduleIDs := make([]int64, 0, len(msBuilder.pendingActivityInfoIDs))
+	for scheduleID := range msBuilder.pendingActivityInfoIDs {
+		scheduleIDs = append(scheduleIDs, scheduleID)
+	}
+	// cancel in schedule order so the recorded history does not depend on map iteration order
+	sort.Slice(scheduleIDs, func(i, j int) bool { return scheduleIDs[i] < scheduleIDs[j] })
+
+	for _, scheduleID := range scheduleIDs {
+		ai, _ := msBuilder.GetActivityInfo(scheduleID)
+		cancelRequestID := ai.CancelRequestID
+		if !ai.CancelRequested {
+			actCancelReqEvent, _, isRunning := msBuilder.AddActivityTaskCancelRequestedEvent(decisionCompletedID,
+				ai.ActivityID, identity)
+			if !isRunning {
+				continue
+			}
+			cancelRequestID = actCancelReqEvent.GetEventId()
+		}
+		msBuilder.AddActivityTaskCanceledEvent(ai.ScheduleID, ai.StartedID, cancelRequestID,
+			[]byte(activityCancelationMsgWorkflowClosed), identity)
+	}
 }
 
 func (e *historyEngineImpl) RespondDecisionTaskFailed(req *h.RespondDecisionTaskFailedRequest) error {
@@ -1296,6 +1928,9 @@ func (e *historyEngineImpl) RespondDecisionTaskFailed(req *h.RespondDecisionTask
 	if err != nil {
 		return err
 	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return err
+	}
 	request := req.FailedRequest
 	token, err0 := e.tokenSerializer.Deserialize(request.TaskToken)
 	if err0 != nil {
@@ -1319,8 +1954,12 @@ func (e *historyEngineImpl) RespondDecisionTaskFailed(req *h.RespondDecisionTask
 				return nil, &workflow.EntityNotExistsError{Message: "Decision task not found."}
 			}
 
+			timeoutWorkflow := msBuilder.shouldTimeoutOnDecisionFailure(di.Attempt)
 			msBuilder.AddDecisionTaskFailedEvent(di.ScheduleID, di.StartedID, request.GetCause(), request.Details,
 				request.GetIdentity())
+			if timeoutWorkflow {
+				return []persistence.Task{tBuilder.AddWorkflowTimeoutNowTask()}, nil
+			}
 
 			return nil, nil
 		})
@@ -1332,6 +1971,9 @@ func (e *historyEngineImpl) RespondActivityTaskCompleted(req *h.RespondActivityT
 	if err != nil {
 		return err
 	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return err
+	}
 	request := req.CompleteRequest
 	token, err0 := e.tokenSerializer.Deserialize(request.TaskToken)
 	if err0 != nil {
@@ -1343,7 +1985,7 @@ func (e *historyEngineImpl) RespondActivityTaskCompleted(req *h.RespondActivityT
 		RunId:      common.StringPtr(token.RunID),
 	}
 
-	return e.updateWorkflowExecution(domainID, workflowExecution, false, true,
+	err = e.updateWorkflowExecution(domainID, workflowExecution, false, true,
 		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error) {
 			if !msBuilder.isWorkflowExecutionRunning() {
 				return nil, ErrWorkflowCompleted
@@ -1361,7 +2003,8 @@ func (e *historyEngineImpl) RespondActivityTaskCompleted(req *h.RespondActivityT
 			// First check to see if cache needs to be refreshed as we could potentially have stale workflow execution in
 			// some extreme cassandra failure cases.
 			if !isRunning && scheduleID >= msBuilder.GetNextEventID() {
-				e.metricsClient.IncCounter(metrics.HistoryRespondActivityTaskCompletedScope, metrics.StaleMutableStateCounter)
+				e.emitStaleStateReload(metrics.HistoryRespondActivityTaskCompletedScope, "RespondActivityTaskCompleted",
+					msBuilder.executionInfo)
 				return nil, ErrStaleState
 			}
 
@@ -1369,14 +2012,44 @@ func (e *historyEngineImpl) RespondActivityTaskCompleted(req *h.RespondActivityT
 				return nil, ErrActivityTaskNotFound
 			}
 
+			result, err := e.offloadPayload(metrics.HistoryRespondActivityTaskCompletedScope, domainID,
+				msBuilder.executionInfo, request.Result)
+			if err != nil {
+				return nil, err
+			}
+			completeRequest := *request
+			completeRequest.Result = result
+
 			startedID := ai.StartedID
-			if msBuilder.AddActivityTaskCompletedEvent(scheduleID, startedID, request) == nil {
+			if msBuilder.AddActivityTaskCompletedEvent(scheduleID, startedID, &completeRequest) == nil {
 				// Unable to add ActivityTaskCompleted event to history
 				return nil, &workflow.InternalServiceError{Message: "Unable to add ActivityTaskCompleted event to history."}
 			}
 
 			return nil, nil
 		})
+
+	requestID := request.GetRequestId()
+	if requestID == "" || e.completedActivityRequests == nil {
+		return err
+	}
+
+	key := activityCompletionKey{
+		domainID:   domainID,
+		workflowID: token.WorkflowID,
+		runID:      token.RunID,
+		requestID:  requestID,
+	}
+	switch err {
+	case nil:
+		e.completedActivityRequests.Put(key, struct{}{})
+	case ErrActivityTaskNotFound, ErrWorkflowCompleted:
+		// a retry of a completion which already went through gets the same result as the original call
+		if e.completedActivityRequests.Get(key) != nil {
+			return nil
+		}
+	}
+	return err
 }
 
 // RespondActivityTaskFailed completes an activity task failure.
@@ -1385,7 +2058,13 @@ func (e *historyEngineImpl) RespondActivityTaskFailed(req *h.RespondActivityTask
 	if err != nil {
 		return err
 	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return err
+	}
 	request := req.FailedRequest
+	if err := validateActivityFailureType(request.FailureType); err != nil {
+		return err
+	}
 	token, err0 := e.tokenSerializer.Deserialize(request.TaskToken)
 	if err0 != nil {
 		return ErrDeserializingToken
@@ -1414,7 +2093,8 @@ func (e *historyEngineImpl) RespondActivityTaskFailed(req *h.RespondActivityTask
 			// First check to see if cache needs to be refreshed as we could potentially have stale workflow execution in
 			// some extreme cassandra failure cases.
 			if !isRunning && scheduleID >= msBuilder.GetNextEventID() {
-				e.metricsClient.IncCounter(metrics.HistoryRespondActivityTaskFailedScope, metrics.StaleMutableStateCounter)
+				e.emitStaleStateReload(metrics.HistoryRespondActivityTaskFailedScope, "RespondActivityTaskFailed",
+					msBuilder.executionInfo)
 				return nil, ErrStaleState
 			}
 
@@ -1438,6 +2118,9 @@ func (e *historyEngineImpl) RespondActivityTaskCanceled(req *h.RespondActivityTa
 	if err != nil {
 		return err
 	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return err
+	}
 	request := req.CancelRequest
 	token, err0 := e.tokenSerializer.Deserialize(request.TaskToken)
 	if err0 != nil {
@@ -1467,7 +2150,8 @@ func (e *historyEngineImpl) RespondActivityTaskCanceled(req *h.RespondActivityTa
 			// First check to see if cache needs to be refreshed as we could potentially have stale workflow execution in
 			// some extreme cassandra failure cases.
 			if !isRunning && scheduleID >= msBuilder.GetNextEventID() {
-				e.metricsClient.IncCounter(metrics.HistoryRespondActivityTaskCanceledScope, metrics.StaleMutableStateCounter)
+				e.emitStaleStateReload(metrics.HistoryRespondActivityTaskCanceledScope, "RespondActivityTaskCanceled",
+					msBuilder.executionInfo)
 				return nil, ErrStaleState
 			}
 
@@ -1496,6 +2180,9 @@ func (e *historyEngineImpl) RecordActivityTaskHeartbeat(
 	if err != nil {
 		return nil, err
 	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return nil, err
+	}
 	request := req.HeartbeatRequest
 	token, err0 := e.tokenSerializer.Deserialize(request.TaskToken)
 	if err0 != nil {
@@ -1508,6 +2195,7 @@ func (e *historyEngineImpl) RecordActivityTaskHeartbeat(
 	}
 
 	var cancelRequested bool
+	var lastCheckpoint *workflow.ActivityCheckpoint
 	err = e.updateWorkflowExecution(domainID, workflowExecution, false, false,
 		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error) {
 			if !msBuilder.isWorkflowExecutionRunning() {
@@ -1527,7 +2215,8 @@ func (e *historyEngineImpl) RecordActivityTaskHeartbeat(
 			// First check to see if cache needs to be refreshed as we could potentially have stale workflow execution in
 			// some extreme cassandra failure cases.
 			if !isRunning && scheduleID >= msBuilder.GetNextEventID() {
-				e.metricsClient.IncCounter(metrics.HistoryRecordActivityTaskHeartbeatScope, metrics.StaleMutableStateCounter)
+				e.emitStaleStateReload(metrics.HistoryRecordActivityTaskHeartbeatScope, "RecordActivityTaskHeartbeat",
+					msBuilder.executionInfo)
 				return nil, ErrStaleState
 			}
 
@@ -1542,8 +2231,33 @@ func (e *historyEngineImpl) RecordActivityTaskHeartbeat(
 			e.logger.Debugf("Activity HeartBeat: scheduleEventID: %v, ActivityInfo: %+v, CancelRequested: %v",
 				scheduleID, ai, cancelRequested)
 
-			// Save progress and last HB reported time.
-			msBuilder.updateActivityProgress(ai, request)
+			// Checkpoints are only accepted in order, so a delayed heartbeat cannot roll back the progress of the
+			// activity.  A heartbeat retried with the last checkpoint is accepted as is.
+			if checkpoint := request.Checkpoint; checkpoint != nil {
+				sequenceNumber := checkpoint.GetSequenceNumber()
+				if sequenceNumber < ai.CheckpointSequenceNumber || (sequenceNumber == ai.CheckpointSequenceNumber &&
+					!bytes.Equal(checkpoint.Payload, ai.CheckpointPayload)) {
+					return nil, ErrCheckpointOutOfOrder
+				}
+			}
+
+			// Save progress and last HB reported time.  The heartbeat timeout slides forward with it: the pending
+			// heartbeat timer finds the activity not expired against the new time when it fires and is re-armed
+			// from it, so an activity which keeps heartbeating in time does not time out.
+			msBuilder.updateActivityProgress(ai, request, tBuilder.timeSource.Now())
+			if ai.CheckpointSequenceNumber > 0 {
+				lastCheckpoint = &workflow.ActivityCheckpoint{
+					SequenceNumber: common.Int64Ptr(ai.CheckpointSequenceNumber),
+					Payload:        ai.CheckpointPayload,
+				}
+			}
+
+			if request.GetAcceptCancel() && cancelRequested && !ai.AcceptedCancel {
+				msBuilder.acceptActivityCancel(ai)
+				if tt := tBuilder.GetActivityTimerTaskIfNeeded(msBuilder); tt != nil {
+					return []persistence.Task{tt}, nil
+				}
+			}
 
 			return nil, nil
 		})
@@ -1552,15 +2266,21 @@ func (e *historyEngineImpl) RecordActivityTaskHeartbeat(
 		return &workflow.RecordActivityTaskHeartbeatResponse{}, err
 	}
 
-	return &workflow.RecordActivityTaskHeartbeatResponse{CancelRequested: common.BoolPtr(cancelRequested)}, nil
+	return &workflow.RecordActivityTaskHeartbeatResponse{
+		CancelRequested: common.BoolPtr(cancelRequested),
+		LastCheckpoint:  lastCheckpoint,
+	}, nil
 }
 
 // RequestCancelWorkflowExecution records request cancellation event for workflow execution
 func (e *historyEngineImpl) RequestCancelWorkflowExecution(
-	req *h.RequestCancelWorkflowExecutionRequest) error {
+	req *h.RequestCancelWorkflowExecutionRequest) (*workflow.RequestCancelWorkflowExecutionResponse, error) {
 	domainID, err := getDomainUUID(req.DomainUUID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return nil, err
 	}
 	request := req.CancelRequest
 	parentExecution := req.ExternalWorkflowExecution
@@ -1570,7 +2290,9 @@ func (e *historyEngineImpl) RequestCancelWorkflowExecution(
 		RunId:      request.WorkflowExecution.RunId,
 	}
 
-	return e.updateWorkflowExecution(domainID, execution, false, true,
+	// the execution info is read once the update is persisted, when the cancel requested event has its id
+	var executionInfo *persistence.WorkflowExecutionInfo
+	err = e.updateWorkflowExecution(domainID, execution, false, true,
 		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error) {
 			if !msBuilder.isWorkflowExecutionRunning() {
 				return nil, ErrWorkflowCompleted
@@ -1585,17 +2307,19 @@ func (e *historyEngineImpl) RequestCancelWorkflowExecution(
 				}
 			}
 
+			if err := validateExpectedRunID(msBuilder, request.GetExpectedRunId()); err != nil {
+				return nil, err
+			}
+
+			executionInfo = msBuilder.executionInfo
 			isCancelRequested, cancelRequestID := msBuilder.isCancelRequested()
 			if isCancelRequested {
-				cancelRequest := req.CancelRequest
-				if cancelRequest.RequestId != nil {
-					requestID := *cancelRequest.RequestId
-					if requestID != "" && cancelRequestID == requestID {
-						return nil, nil
-					}
+				requestID := request.GetRequestId()
+				if (requestID != "" && cancelRequestID == requestID) || request.GetIdempotent() {
+					return nil, nil
 				}
-				// if we consider workflow cancellation idempotent, then this error is redundant
-				// this error maybe useful if this API is invoked by external, not decision from transfer queue
+				// the strict mode is kept for the transfer queue, which tells a cancellation requested by another
+				// workflow apart from its own retries by request id
 				return nil, ErrCancellationAlreadyRequested
 			}
 
@@ -1605,6 +2329,18 @@ func (e *historyEngineImpl) RequestCancelWorkflowExecution(
 
 			return nil, nil
 		})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &workflow.RequestCancelWorkflowExecutionResponse{
+		CancelRequestId: common.StringPtr(executionInfo.CancelRequestID),
+	}
+	// the event id is not known yet while the event is buffered behind a decision in flight
+	if executionInfo.CancelRequestedEventID > 0 {
+		response.CancelRequestedEventId = common.Int64Ptr(executionInfo.CancelRequestedEventID)
+	}
+	return response, nil
 }
 
 func (e *historyEngineImpl) SignalWorkflowExecution(signalRequest *h.SignalWorkflowExecutionRequest) error {
@@ -1612,7 +2348,13 @@ func (e *historyEngineImpl) SignalWorkflowExecution(signalRequest *h.SignalWorkf
 	if err != nil {
 		return err
 	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return err
+	}
 	request := signalRequest.SignalRequest
+	if err = validateSignalInputSize(request.Input, e.shard.GetConfig().MaxSignalInputSize); err != nil {
+		return err
+	}
 	parentExecution := signalRequest.ExternalWorkflowExecution
 	childWorkflowOnly := signalRequest.GetChildWorkflowOnly()
 	execution := workflow.WorkflowExecution{
@@ -1636,10 +2378,15 @@ func (e *historyEngineImpl) SignalWorkflowExecution(signalRequest *h.SignalWorkf
 			}
 
 			// deduplicate by request id for signal decision
-			if requestID := request.GetRequestId(); requestID != "" {
-				if msBuilder.isSignalRequested(requestID) {
-					return nil, nil
-				}
+			requestID := request.GetRequestId()
+			if requestID != "" && msBuilder.isSignalRequested(requestID) {
+				return nil, nil
+			}
+			if err := e.checkBufferedSignalLimit(metrics.HistorySignalWorkflowExecutionScope, domainID,
+				msBuilder); err != nil {
+				return nil, err
+			}
+			if requestID != "" {
 				msBuilder.addSignalRequested(requestID)
 			}
 
@@ -1658,7 +2405,21 @@ func (e *historyEngineImpl) SignalWithStartWorkflowExecution(signalWithStartRequ
 	if err != nil {
 		return nil, err
 	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return nil, err
+	}
+
+	endOperation, err := e.beginWorkflowUpdate(domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer endOperation()
+
 	sRequest := signalWithStartRequest.SignalWithStartRequest
+	signalRequests := getSignalRequests(sRequest)
+	if err = validateSignalWithStartSignals(signalRequests, e.shard.GetConfig()); err != nil {
+		return nil, err
+	}
 	execution := workflow.WorkflowExecution{
 		WorkflowId: sRequest.WorkflowId,
 	}
@@ -1686,14 +2447,23 @@ func (e *historyEngineImpl) SignalWithStartWorkflowExecution(signalWithStartRequ
 				break
 			}
 
-			if msBuilder.AddWorkflowExecutionSignaled(getSignalRequest(sRequest)) == nil {
-				return nil, &workflow.InternalServiceError{Message: "Unable to signal workflow execution."}
+			if err1 = e.checkBufferedSignalLimit(metrics.HistorySignalWithStartWorkflowExecutionScope, domainID,
+				msBuilder); err1 != nil {
+				return nil, err1
+			}
+			signaled, err1 := addWorkflowExecutionSignals(msBuilder, signalRequests)
+			if err1 != nil {
+				return nil, err1
+			}
+			if !signaled {
+				// every signal is a duplicate of one delivered before
+				return &workflow.StartWorkflowExecutionResponse{RunId: context.workflowExecution.RunId}, nil
 			}
 
 			var transferTasks []persistence.Task
 			var timerTasks []persistence.Task
 			// Create a transfer task to schedule a decision task
-			if !msBuilder.HasPendingDecisionTask() {
+			if !msBuilder.HasPendingDecisionTask() && !msBuilder.isPaused() {
 				di := msBuilder.AddDecisionTaskScheduledEvent()
 				transferTasks = append(transferTasks, &persistence.DecisionTask{
 					DomainID:   domainID,
@@ -1725,7 +2495,7 @@ func (e *historyEngineImpl) SignalWithStartWorkflowExecution(signalWithStartRequ
 			return &workflow.StartWorkflowExecutionResponse{RunId: context.workflowExecution.RunId}, nil
 		} // end for Just_Signal_Loop
 		if attempt == conditionalRetryCount {
-			return nil, ErrMaxAttemptsExceeded
+			return nil, context.newMaxAttemptsExceededError()
 		}
 	} else {
 		if _, ok := err0.(*workflow.EntityNotExistsError); ok {
@@ -1738,10 +2508,18 @@ func (e *historyEngineImpl) SignalWithStartWorkflowExecution(signalWithStartRequ
 	// Start workflow and signal
 	startRequest := getStartRequest(domainID, sRequest)
 	request := startRequest.StartRequest
-	err = validateStartWorkflowExecutionRequest(request)
+	err = validateStartWorkflowExecutionRequest(request, e.shard.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	startRequest, err = e.routeStartRequest(domainID, startRequest)
 	if err != nil {
 		return nil, err
 	}
+	request = startRequest.StartRequest
+	if err := e.checkOpenExecutionLimit(metrics.HistorySignalWithStartWorkflowExecutionScope, domainID); err != nil {
+		return nil, err
+	}
 
 	execution = workflow.WorkflowExecution{
 		WorkflowId: request.WorkflowId,
@@ -1749,15 +2527,18 @@ func (e *historyEngineImpl) SignalWithStartWorkflowExecution(signalWithStartRequ
 	}
 
 	// Generate first decision task event.
-	taskList := request.TaskList.GetName()
 	msBuilder := newMutableStateBuilder(e.shard.GetConfig(), e.logger)
 	startedEvent := msBuilder.AddWorkflowExecutionStartedEvent(execution, startRequest)
 	if startedEvent == nil {
 		return nil, &workflow.InternalServiceError{Message: "Failed to add workflow execution started event."}
 	}
 
-	if msBuilder.AddWorkflowExecutionSignaled(getSignalRequest(sRequest)) == nil {
-		return nil, &workflow.InternalServiceError{Message: "Failed to add workflow execution signaled event."}
+	if _, err := addWorkflowExecutionSignals(msBuilder, signalRequests); err != nil {
+		return nil, err
+	}
+	var signalRequestedIDs []string
+	for requestID := range msBuilder.updateSignalRequestedIDs {
+		signalRequestedIDs = append(signalRequestedIDs, requestID)
 	}
 
 	var transferTasks []persistence.Task
@@ -1770,15 +2551,18 @@ func (e *historyEngineImpl) SignalWithStartWorkflowExecution(signalWithStartRequ
 	}
 
 	transferTasks = []persistence.Task{&persistence.DecisionTask{
-		DomainID: domainID, TaskList: taskList, ScheduleID: di.ScheduleID,
+		DomainID: domainID, TaskList: di.Tasklist, ScheduleID: di.ScheduleID,
 	}}
 	decisionScheduleID = di.ScheduleID
 	decisionStartID = di.StartedID
 	decisionTimeout = di.DecisionTimeout
 
+	// the start time is read once, so the execution and its timeout agree on it
+	startTime := e.shard.GetTimeSource().Now()
+	msBuilder.executionInfo.StartTimestamp = startTime
 	duration := time.Duration(*request.ExecutionStartToCloseTimeoutSeconds) * time.Second
 	timerTasks := []persistence.Task{&persistence.WorkflowTimeoutTask{
-		VisibilityTimestamp: e.shard.GetTimeSource().Now().Add(duration),
+		VisibilityTimestamp: startTime.Add(duration),
 	}}
 	// Serialize the history
 	serializedHistory, serializedError := msBuilder.hBuilder.Serialize()
@@ -1797,6 +2581,7 @@ func (e *historyEngineImpl) SignalWithStartWorkflowExecution(signalWithStartRequ
 		TransactionID: 0,
 		FirstEventID:  startedEvent.GetEventId(),
 		Events:        serializedHistory,
+		KeyID:         msBuilder.executionInfo.KeyID,
 	})
 	if err != nil {
 		return nil, err
@@ -1816,6 +2601,7 @@ func (e *historyEngineImpl) SignalWithStartWorkflowExecution(signalWithStartRequ
 			ExecutionContext:            nil,
 			NextEventID:                 msBuilder.GetNextEventID(),
 			LastProcessedEvent:          emptyEventID,
+			StartTimestamp:              startTime,
 			TransferTasks:               transferTasks,
 			DecisionScheduleID:          decisionScheduleID,
 			DecisionStartedID:           decisionStartID,
@@ -1823,12 +2609,19 @@ func (e *historyEngineImpl) SignalWithStartWorkflowExecution(signalWithStartRequ
 			TimerTasks:                  timerTasks,
 			ContinueAsNew:               !isBrandNew,
 			PreviousRunID:               prevRunID,
+			SignalCount:                 msBuilder.executionInfo.SignalCount,
+			LastSignalName:              msBuilder.executionInfo.LastSignalName,
+			LastSignalTimestamp:         msBuilder.executionInfo.LastSignalTimestamp,
+			SignalRequestedIDs:          signalRequestedIDs,
+			KeyID:                       msBuilder.executionInfo.KeyID,
+			DecisionTaskList:            msBuilder.executionInfo.DecisionTaskList,
+			LastFirstEventID:            msBuilder.executionInfo.LastFirstEventID,
 		})
 
 		if err != nil {
 			switch t := err.(type) {
 			case *persistence.WorkflowExecutionAlreadyStartedError:
-				if t.StartRequestID == common.StringDefault(request.RequestId) {
+				if isRetriedStartRequest(request, t) {
 					e.deleteEvents(domainID, execution)
 					return t.RunID, nil
 				}
@@ -1840,8 +2633,20 @@ func (e *historyEngineImpl) SignalWithStartWorkflowExecution(signalWithStartRequ
 		return execution.GetRunId(), nil
 	}
 
+	var firstDecisionMatch *firstDecisionMatch
+	if decisionScheduleID != emptyEventID &&
+		e.shard.GetConfig().EnableSyncMatchFirstDecision(dynamicconfig.DomainFilter(request.GetDomain())) {
+		firstDecisionMatch = e.beginFirstDecisionMatch(domainID, execution, decisionScheduleID)
+	}
+
 	// try to create the workflow execution
 	resultRunID, err := createWorkflow(isBrandNew, prevRunID) // (true, "") or (false, "prevRunID")
+	if firstDecisionMatch != nil {
+		e.endFirstDecisionMatch(firstDecisionMatch, err == nil && resultRunID == execution.GetRunId() &&
+			e.syncMatchFirstDecision(domainID, execution, msBuilder.executionInfo.DecisionTaskList,
+				decisionScheduleID, request.GetExecutionStartToCloseTimeoutSeconds()))
+	}
+
 	if err == nil {
 		e.timerProcessor.NotifyNewTimers(e.currentClusterName, timerTasks)
 
@@ -1858,6 +2663,9 @@ func (e *historyEngineImpl) RemoveSignalMutableState(request *h.RemoveSignalMuta
 	if err != nil {
 		return err
 	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return err
+	}
 	execution := workflow.WorkflowExecution{
 		WorkflowId: request.WorkflowExecution.WorkflowId,
 		RunId:      request.WorkflowExecution.RunId,
@@ -1880,35 +2688,262 @@ func (e *historyEngineImpl) TerminateWorkflowExecution(terminateRequest *h.Termi
 	if err != nil {
 		return err
 	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return err
+	}
 	request := terminateRequest.TerminateRequest
 	execution := workflow.WorkflowExecution{
 		WorkflowId: request.WorkflowExecution.WorkflowId,
 		RunId:      request.WorkflowExecution.RunId,
 	}
 
-	return e.updateWorkflowExecution(domainID, execution, true, false,
+	err = e.updateWorkflowExecution(domainID, execution, true, false,
 		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error) {
 			if !msBuilder.isWorkflowExecutionRunning() {
 				return nil, ErrWorkflowCompleted
 			}
 
+			if err := validateExpectedRunID(msBuilder, request.GetExpectedRunId()); err != nil {
+				return nil, err
+			}
+
+			if _, ok := msBuilder.executionInfo.OperatorTags[doNotTerminateOperatorTag]; ok &&
+				request.GetHonorDoNotTerminateTag() {
+				return nil, ErrDoNotTerminateTagged
+			}
+
+			if err := emitDiscardedBufferedEvents(e.shard, e.metricsClient, e.logger,
+				metrics.HistoryTerminateWorkflowExecutionScope, msBuilder); err != nil {
+				return nil, err
+			}
+
 			if msBuilder.AddWorkflowExecutionTerminatedEvent(request) == nil {
 				return nil, &workflow.InternalServiceError{Message: "Unable to terminate workflow execution."}
 			}
 
 			return nil, nil
 		})
+
+	if err == nil {
+		emitWorkflowClosed(e.shard, e.metricsClient, metrics.HistoryTerminateWorkflowExecutionScope, domainID,
+			persistence.WorkflowCloseStatusTerminated)
+	}
+	return err
 }
 
-// ScheduleDecisionTask schedules a decision if no outstanding decision found
-func (e *historyEngineImpl) ScheduleDecisionTask(scheduleRequest *h.ScheduleDecisionTaskRequest) error {
-	domainID, err := getDomainUUID(scheduleRequest.DomainUUID)
-	if err != nil {
-		return err
+// TerminateAndStart terminates the running execution of a workflow and starts a new run of it in a single update.  The
+// new run is created the way continue-as-new creates one, conditioned on the terminated run still being the current
+// run, so a start racing with the call cannot slip in between the two.
+func (e *historyEngineImpl) TerminateAndStart(request *TerminateAndStartRequest) (
+	retResp *workflow.StartWorkflowExecutionResponse, retError error) {
+	domainID := request.DomainID
+	if err := e.validateDomainRegistered(domainID); err != nil {
+		return nil, err
 	}
-	execution := workflow.WorkflowExecution{
-		WorkflowId: scheduleRequest.WorkflowExecution.WorkflowId,
-		RunId:      scheduleRequest.WorkflowExecution.RunId,
+	if err := e.validateDomainActive(domainID); err != nil {
+		return nil, err
+	}
+	if request.StartRequest == nil {
+		return nil, &workflow.BadRequestError{Message: "StartRequest is not set on request."}
+	}
+	if err := validateStartWorkflowExecutionRequest(request.StartRequest, e.shard.GetConfig()); err != nil {
+		return nil, err
+	}
+	startRequest, err := e.routeStartRequest(domainID, &h.StartWorkflowExecutionRequest{
+		DomainUUID:   common.StringPtr(domainID),
+		StartRequest: request.StartRequest,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// a retry of a call which went through recently is answered with the run it started
+	startKey := workflowStartKey{
+		domainID:   domainID,
+		workflowID: startRequest.StartRequest.GetWorkflowId(),
+		requestID:  startRequest.StartRequest.GetRequestId(),
+	}
+	if runID, ok := e.getStartedRunID(startKey); ok {
+		return &workflow.StartWorkflowExecutionResponse{RunId: common.StringPtr(runID)}, nil
+	}
+	if err := e.checkOpenExecutionLimit(metrics.HistoryTerminateAndStartScope, domainID); err != nil {
+		return nil, err
+	}
+
+	endOperation, err := e.beginWorkflowUpdate(domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer endOperation()
+
+	execution := workflow.WorkflowExecution{
+		WorkflowId: startRequest.StartRequest.WorkflowId,
+		RunId:      common.StringPtr(request.RunID),
+	}
+	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(domainID, execution)
+	if err0 != nil {
+		return nil, err0
+	}
+	defer func() { release(retError) }()
+
+Update_History_Loop:
+	for attempt := 0; attempt < conditionalRetryCount; attempt++ {
+		msBuilder, err1 := context.loadWorkflowExecution()
+		if err1 != nil {
+			return nil, err1
+		}
+		if !msBuilder.isWorkflowExecutionRunning() {
+			// the new run records the request id which started it, so a retry of a call which went through is
+			// answered with the run it started even once it is no longer remembered
+			runID, err := e.getRetriedStartRunID(startKey)
+			if err != nil {
+				return nil, err
+			}
+			if runID == "" {
+				return nil, ErrWorkflowCompleted
+			}
+			return &workflow.StartWorkflowExecutionResponse{RunId: common.StringPtr(runID)}, nil
+		}
+
+		if err := emitDiscardedBufferedEvents(e.shard, e.metricsClient, e.logger,
+			metrics.HistoryTerminateAndStartScope, msBuilder); err != nil {
+			return nil, err
+		}
+		if msBuilder.AddWorkflowExecutionTerminatedEvent(&workflow.TerminateWorkflowExecutionRequest{
+			Reason:   common.StringPtr(request.Reason),
+			Details:  request.Details,
+			Identity: common.StringPtr(request.Identity),
+		}) == nil {
+			return nil, &workflow.InternalServiceError{Message: "Unable to terminate workflow execution."}
+		}
+
+		newStateBuilder, newRunTimerTasks, err := e.addTerminateAndStartRun(domainID, msBuilder, startRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		tBuilder := e.getTimerBuilder(&context.workflowExecution)
+		tranT, timerT, err := e.getDeleteWorkflowTasks(domainID, msBuilder.executionInfo.WorkflowTypeName, tBuilder)
+		if err != nil {
+			return nil, err
+		}
+		transferTasks := []persistence.Task{tranT}
+		timerTasks := []persistence.Task{timerT}
+
+		// Generate a transaction ID for appending events to history
+		transactionID, err2 := e.shard.GetNextTransferTaskID()
+		if err2 != nil {
+			return nil, err2
+		}
+
+		if err := context.continueAsNewWorkflowExecution(nil, newStateBuilder, transferTasks, timerTasks,
+			transactionID); err != nil {
+			if err == ErrConflict {
+				continue Update_History_Loop
+			}
+			return nil, err
+		}
+
+		emitWorkflowClosed(e.shard, e.metricsClient, metrics.HistoryTerminateAndStartScope, domainID,
+			persistence.WorkflowCloseStatusTerminated)
+		e.timerProcessor.NotifyNewTimers(e.currentClusterName, append(timerTasks, newRunTimerTasks...))
+		e.putStartedRunID(startKey, newStateBuilder.executionInfo.RunID)
+		return &workflow.StartWorkflowExecutionResponse{
+			RunId: common.StringPtr(newStateBuilder.executionInfo.RunID),
+		}, nil
+	}
+	return nil, context.newMaxAttemptsExceededError()
+}
+
+// getRetriedStartRunID returns the current run of a workflow if it was started by the request with the given request
+// id, and an empty run id otherwise
+func (e *historyEngineImpl) getRetriedStartRunID(key workflowStartKey) (string, error) {
+	if key.requestID == "" {
+		return "", nil
+	}
+	current, err := e.executionManager.GetCurrentExecution(&persistence.GetCurrentExecutionRequest{
+		DomainID:   key.domainID,
+		WorkflowID: key.workflowID,
+	})
+	if err != nil {
+		if _, ok := err.(*workflow.EntityNotExistsError); ok {
+			return "", nil
+		}
+		return "", err
+	}
+	if current.StartRequestID != key.requestID {
+		return "", nil
+	}
+	e.putStartedRunID(key, current.RunID)
+	return current.RunID, nil
+}
+
+// addTerminateAndStartRun builds the mutable state of the run started by TerminateAndStart, and hands the request
+// creating it to the terminated run so both are written by the same update
+func (e *historyEngineImpl) addTerminateAndStartRun(domainID string, msBuilder *mutableStateBuilder,
+	startRequest *h.StartWorkflowExecutionRequest) (*mutableStateBuilder, []persistence.Task, error) {
+	request := startRequest.StartRequest
+	newExecution := workflow.WorkflowExecution{
+		WorkflowId: request.WorkflowId,
+		RunId:      common.StringPtr(uuid.New()),
+	}
+
+	newStateBuilder := newMutableStateBuilder(e.shard.GetConfig(), e.logger)
+	if newStateBuilder.AddWorkflowExecutionStartedEvent(newExecution, startRequest) == nil {
+		return nil, nil, &workflow.InternalServiceError{Message: "Failed to add workflow execution started event."}
+	}
+	di := newStateBuilder.AddDecisionTaskScheduledEvent()
+	if di == nil {
+		return nil, nil, &workflow.InternalServiceError{Message: "Failed to add decision scheduled event."}
+	}
+
+	startTime := e.shard.GetTimeSource().Now()
+	newStateBuilder.executionInfo.StartTimestamp = startTime
+	timerTasks := []persistence.Task{&persistence.WorkflowTimeoutTask{
+		VisibilityTimestamp: startTime.Add(time.Duration(request.GetExecutionStartToCloseTimeoutSeconds()) * time.Second),
+	}}
+
+	msBuilder.continueAsNew = &persistence.CreateWorkflowExecutionRequest{
+		RequestID:            request.GetRequestId(),
+		DomainID:             domainID,
+		Execution:            newExecution,
+		InitiatedID:          emptyEventID,
+		TaskList:             newStateBuilder.executionInfo.TaskList,
+		WorkflowTypeName:     newStateBuilder.executionInfo.WorkflowTypeName,
+		WorkflowTimeout:      newStateBuilder.executionInfo.WorkflowTimeout,
+		DecisionTimeoutValue: newStateBuilder.executionInfo.DecisionTimeoutValue,
+		NextEventID:          newStateBuilder.GetNextEventID(),
+		LastProcessedEvent:   emptyEventID,
+		StartTimestamp:       startTime,
+		TransferTasks: []persistence.Task{&persistence.DecisionTask{
+			DomainID:   domainID,
+			TaskList:   di.Tasklist,
+			ScheduleID: di.ScheduleID,
+		}},
+		TimerTasks:                  timerTasks,
+		DecisionScheduleID:          di.ScheduleID,
+		DecisionStartedID:           di.StartedID,
+		DecisionStartToCloseTimeout: di.DecisionTimeout,
+		ContinueAsNew:               true,
+		PreviousRunID:               msBuilder.executionInfo.RunID,
+		KeyID:                       newStateBuilder.executionInfo.KeyID,
+		DecisionTaskList:            newStateBuilder.executionInfo.DecisionTaskList,
+	}
+	return newStateBuilder, timerTasks, nil
+}
+
+// ScheduleDecisionTask schedules a decision if no outstanding decision found
+func (e *historyEngineImpl) ScheduleDecisionTask(scheduleRequest *h.ScheduleDecisionTaskRequest) error {
+	domainID, err := getDomainUUID(scheduleRequest.DomainUUID)
+	if err != nil {
+		return err
+	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return err
+	}
+	execution := workflow.WorkflowExecution{
+		WorkflowId: scheduleRequest.WorkflowExecution.WorkflowId,
+		RunId:      scheduleRequest.WorkflowExecution.RunId,
 	}
 
 	return e.updateWorkflowExecution(domainID, execution, false, true,
@@ -1929,6 +2964,9 @@ func (e *historyEngineImpl) RecordChildExecutionCompleted(completionRequest *h.R
 	if err != nil {
 		return err
 	}
+	if err := e.validateDomainActive(domainID); err != nil {
+		return err
+	}
 	execution := workflow.WorkflowExecution{
 		WorkflowId: completionRequest.WorkflowExecution.WorkflowId,
 		RunId:      completionRequest.WorkflowExecution.RunId,
@@ -1949,6 +2987,10 @@ func (e *historyEngineImpl) RecordChildExecutionCompleted(completionRequest *h.R
 			if !isRunning || ci.StartedID == emptyEventID {
 				return nil, &workflow.EntityNotExistsError{Message: "Pending child execution not found."}
 			}
+			// Child executions started before the run id was recorded have an empty StartedRunID
+			if ci.StartedRunID != "" && ci.StartedRunID != completedExecution.GetRunId() {
+				return nil, &workflow.EntityNotExistsError{Message: "Pending child execution not found."}
+			}
 
 			switch *completionEvent.EventType {
 			case workflow.EventTypeWorkflowExecutionCompleted:
@@ -1968,98 +3010,1717 @@ func (e *historyEngineImpl) RecordChildExecutionCompleted(completionRequest *h.R
 				msBuilder.AddChildWorkflowExecutionTimedOutEvent(initiatedID, completedExecution, attributes)
 			}
 
-			return nil, nil
-		})
+			return nil, nil
+		})
+}
+
+func (e *historyEngineImpl) ReplicateEvents(replicateRequest *h.ReplicateEventsRequest) error {
+	endOperation, err := e.beginWorkflowUpdate(replicateRequest.GetDomainUUID())
+	if err != nil {
+		return err
+	}
+	defer endOperation()
+
+	for attempt := 0; ; attempt++ {
+		err = e.replicator.ApplyEvents(replicateRequest)
+		if err == nil || e.replicationRetryPolicy == nil || !isReplicationRetryableError(err) {
+			return err
+		}
+
+		interval := e.replicationRetryPolicy.ComputeNextDelay(0, attempt)
+		if interval < 0 {
+			e.metricsClient.IncCounter(metrics.HistoryReplicateEventsScope,
+				metrics.ReplicationApplyRetriesExhaustedCounter)
+			return err
+		}
+		e.metricsClient.IncCounter(metrics.HistoryReplicateEventsScope, metrics.ReplicationApplyRetryCounter)
+		time.Sleep(interval)
+	}
+}
+
+// isReplicationRetryableError tells whether replicated events failed to apply because the execution was updated
+// concurrently, in which case applying them again on the reloaded mutable state can succeed.  Any other failure is
+// left to the replication task.
+func isReplicationRetryableError(err error) bool {
+	if err == ErrConflict || err == ErrStaleState {
+		return true
+	}
+	_, ok := err.(*persistence.ConditionFailedError)
+	return ok
+}
+
+func (e *historyEngineImpl) updateWorkflowExecution(domainID string, execution workflow.WorkflowExecution,
+	createDeletionTask, createDecisionTask bool,
+	action func(builder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error)) (retError error) {
+
+	endOperation, err := e.beginWorkflowUpdate(domainID)
+	if err != nil {
+		return err
+	}
+	defer endOperation()
+
+	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(domainID, execution)
+	if err0 != nil {
+		return err0
+	}
+	defer func() { release(retError) }()
+
+Update_History_Loop:
+	for attempt := 0; attempt < conditionalRetryCount; attempt++ {
+		msBuilder, err1 := context.loadWorkflowExecution()
+		if err1 != nil {
+			return err1
+		}
+		tBuilder := e.getTimerBuilder(&context.workflowExecution)
+
+		var timerTasks []persistence.Task
+		var err error
+		// conduct caller action
+		if timerTasks, err = action(msBuilder, tBuilder); err != nil {
+			if err == ErrStaleState {
+				// Handler detected that cached workflow mutable could potentially be stale
+				// Reload workflow execution history
+				context.clear()
+				continue Update_History_Loop
+			}
+
+			// Returned error back to the caller
+			return err
+		}
+
+		var transferTasks []persistence.Task
+
+		if createDeletionTask {
+			tranT, timerT, err := e.getDeleteWorkflowTasks(domainID, msBuilder.executionInfo.WorkflowTypeName, tBuilder)
+			if err != nil {
+				return err
+			}
+			transferTasks = append(transferTasks, tranT)
+			timerTasks = append(timerTasks, timerT)
+		}
+
+		if createDecisionTask {
+			// Create a transfer task to schedule a decision task, a paused workflow gets one once it is resumed
+			if !msBuilder.HasPendingDecisionTask() && !msBuilder.isPaused() {
+				di := msBuilder.AddDecisionTaskScheduledEvent()
+				if backoffInterval := e.getDecisionBackoff(di.Attempt); backoffInterval > 0 {
+					// decision is retried after a failure, dispatch it once the backoff fires
+					timerTasks = append(timerTasks, tBuilder.AddDecisionBackoffTask(di.ScheduleID, di.Attempt,
+						backoffInterval))
+				} else {
+					transferTasks = append(transferTasks, &persistence.DecisionTask{
+						DomainID:   domainID,
+						TaskList:   di.Tasklist,
+						ScheduleID: di.ScheduleID,
+					})
+					if msBuilder.isStickyTaskListEnabled() {
+						tBuilder := e.getTimerBuilder(&context.workflowExecution)
+						stickyTaskTimeoutTimer := tBuilder.AddScheduleToStartDecisionTimoutTask(di.ScheduleID, di.Attempt,
+							msBuilder.executionInfo.StickyScheduleToStartTimeout)
+						timerTasks = append(timerTasks, stickyTaskTimeoutTimer)
+					}
+				}
+			}
+		}
+
+		// Generate a transaction ID for appending events to history
+		transactionID, err2 := e.shard.GetNextTransferTaskID()
+		if err2 != nil {
+			return err2
+		}
+
+		// We apply the update to execution using optimistic concurrency.  If it fails due to a conflict then reload
+		// the history and try the operation again.
+		if err := context.updateWorkflowExecution(transferTasks, timerTasks, transactionID); err != nil {
+			if err == ErrConflict {
+				continue Update_History_Loop
+			}
+			return err
+		}
+		e.timerProcessor.NotifyNewTimers(e.currentClusterName, timerTasks)
+		return nil
+	}
+	return context.newMaxAttemptsExceededError()
+}
+
+// beginWriteOperation registers an in-flight write operation on the engine.  The returned func must be called
+// once the operation is done.  Once the engine is draining new operations fail with ShardOwnershipLostError,
+// so that callers retry them against the new owner of the shard.
+func (e *historyEngineImpl) beginWriteOperation() (func(), error) {
+	e.drainLock.Lock()
+	defer e.drainLock.Unlock()
+
+	if e.draining {
+		return nil, &persistence.ShardOwnershipLostError{
+			ShardID: e.shard.GetShardID(),
+			Msg:     "History engine is shutting down.",
+		}
+	}
+
+	e.inFlightOps++
+	return func() {
+		e.drainLock.Lock()
+		defer e.drainLock.Unlock()
+
+		e.inFlightOps--
+		if e.inFlightOps == 0 && e.drainedCh != nil {
+			close(e.drainedCh)
+			e.drainedCh = nil
+		}
+	}, nil
+}
+
+// drain stops the engine from accepting new write operations and waits up to timeout for the in-flight ones
+// to complete.  Operations still running once the timeout elapses are abandoned.
+func (e *historyEngineImpl) drain(timeout time.Duration) {
+	e.drainLock.Lock()
+	e.draining = true
+	if e.inFlightOps == 0 || timeout <= 0 {
+		abandoned := e.inFlightOps
+		e.drainLock.Unlock()
+		e.emitDrainAbandoned(abandoned)
+		return
+	}
+	drainedCh := make(chan struct{})
+	e.drainedCh = drainedCh
+	e.drainLock.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-drainedCh:
+	case <-timer.C:
+		e.drainLock.Lock()
+		abandoned := e.inFlightOps
+		e.drainedCh = nil
+		e.drainLock.Unlock()
+		e.emitDrainAbandoned(abandoned)
+	}
+}
+
+func (e *historyEngineImpl) emitDrainAbandoned(abandoned int) {
+	if abandoned == 0 {
+		return
+	}
+	e.metricsClient.AddCounter(metrics.HistoryWorkflowUpdateScope, metrics.ShutdownDrainAbandonedCounter,
+		int64(abandoned))
+	e.logger.Warnf("History engine stopped with %v write operations in flight.", abandoned)
+}
+
+// beginWorkflowUpdate begins a write operation which updates workflow executions of the given domain, and reserves
+// one of the shard's concurrent update slots for it.  The returned func must be called once the update is done.
+func (e *historyEngineImpl) beginWorkflowUpdate(domainID string) (func(), error) {
+	endOperation, err := e.beginWriteOperation()
+	if err != nil {
+		return nil, err
+	}
+	releasePermit, err := e.acquireUpdatePermit(domainID)
+	if err != nil {
+		endOperation()
+		return nil, err
+	}
+	return func() {
+		releasePermit()
+		endOperation()
+	}, nil
+}
+
+// acquireUpdatePermit reserves one of the shard's concurrent update slots, waiting up to the configured timeout
+// for a slot to free up.  A domain which already uses its share of the slots is turned away right away, so it does
+// not queue up in front of other domains.  The returned function must be called to give the slot back once the
+// update is done.
+func (e *historyEngineImpl) acquireUpdatePermit(domainID string) (func(), error) {
+	if e.updateSemaphore == nil {
+		return func() {}, nil
+	}
+
+	releaseDomainSlot, ok := e.updateIsolation.tryAcquire(domainID)
+	if !ok {
+		return nil, ErrDomainUpdateLimitExceeded
+	}
+
+	select {
+	case e.updateSemaphore <- struct{}{}:
+	default:
+		timer := time.NewTimer(e.shard.GetConfig().ConcurrentUpdateWaitTimeout)
+		defer timer.Stop()
+		select {
+		case e.updateSemaphore <- struct{}{}:
+		case <-timer.C:
+			releaseDomainSlot()
+			e.metricsClient.IncCounter(metrics.HistoryWorkflowUpdateScope, metrics.ConcurrentUpdatesThrottledCounter)
+			return nil, ErrConcurrentUpdateLimitExceeded
+		}
+	}
+
+	e.emitUpdatesInFlightGauge()
+	return func() {
+		<-e.updateSemaphore
+		releaseDomainSlot()
+		e.emitUpdatesInFlightGauge()
+	}, nil
+}
+
+func (e *historyEngineImpl) emitUpdatesInFlightGauge() {
+	e.metricsClient.Tagged(map[string]string{metrics.ShardTagName: strconv.Itoa(e.shard.GetShardID())}).UpdateGauge(
+		metrics.HistoryWorkflowUpdateScope, metrics.ConcurrentUpdatesInFlightGauge, float64(len(e.updateSemaphore)))
+}
+
+// GetShardStats returns a snapshot of the workload managed by the shard of this engine
+func (e *historyEngineImpl) GetShardStats() *ShardStats {
+	return e.shard.GetStats()
+}
+
+// GetQueueLag reports how far the transfer and timer queue processors of the shard trail the tasks generated by the
+// shard.  Standby processors are only reported when global domains are enabled, as they are not running otherwise.  The
+// same lag is emitted as gauges by the queue processors themselves.
+func (e *historyEngineImpl) GetQueueLag() *QueueLag {
+	clusterMetadata := e.shard.GetService().GetClusterMetadata()
+	clusterNames := []string{e.currentClusterName}
+	if clusterMetadata.IsGlobalDomainEnabled() {
+		for clusterName := range clusterMetadata.GetAllClusterFailoverVersions() {
+			if clusterName != e.currentClusterName {
+				clusterNames = append(clusterNames, clusterName)
+			}
+		}
+	}
+
+	lag := &QueueLag{
+		ShardID:           e.shard.GetShardID(),
+		TransferTaskIDLag: make(map[string]int64),
+		TimerLag:          make(map[string]time.Duration),
+	}
+	if pausedUntil, paused := e.timerProcessor.getPausedUntil(); paused {
+		lag.TimerProcessingPausedUntil = pausedUntil
+	}
+	for _, clusterName := range clusterNames {
+		lag.TransferTaskIDLag[clusterName] = getTransferTaskIDLag(e.shard, clusterName)
+		lag.TimerLag[clusterName] = e.timerProcessor.getTimerLag(clusterName)
+	}
+	return lag
+}
+
+// PauseTimerProcessing stops firing the timers of the shard, e.g. during the outage of a dependency, without stopping
+// the engine.  Timers keep accumulating and are fired once processing is resumed, at the latest after the returned time.
+func (e *historyEngineImpl) PauseTimerProcessing(duration time.Duration) time.Time {
+	return e.timerProcessor.pause(duration)
+}
+
+// ResumeTimerProcessing resumes timer processing paused by PauseTimerProcessing
+func (e *historyEngineImpl) ResumeTimerProcessing() {
+	e.timerProcessor.resume()
+}
+
+// ListOpenExecutions returns a page of the open workflow executions stored in the shard, e.g. to take stock of the
+// executions of a shard before it is handed off to another host.  Executions are read straight from persistence
+// without loading their mutable state.
+func (e *historyEngineImpl) ListOpenExecutions(pageSize int, nextPageToken []byte) (
+	*persistence.ListOpenExecutionsResponse, error) {
+	if pageSize <= 0 {
+		return nil, &workflow.BadRequestError{Message: "PageSize must be positive."}
+	}
+
+	return e.executionManager.ListOpenExecutions(&persistence.ListOpenExecutionsRequest{
+		PageSize:      pageSize,
+		NextPageToken: nextPageToken,
+	})
+}
+
+// GetWorkflowExecutionRawHistory returns a page of the serialized history batches of a workflow execution along with
+// its replication state.  Batches are returned as stored, the version filter only decodes a batch to read the
+// version of its events.
+func (e *historyEngineImpl) GetWorkflowExecutionRawHistory(request *RawHistoryRequest) (*RawHistoryResponse, error) {
+	if request.MinVersion != nil && request.MaxVersion != nil && *request.MinVersion > *request.MaxVersion {
+		return nil, &workflow.BadRequestError{Message: "MinVersion is larger than MaxVersion."}
+	}
+
+	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(request.DomainID, request.Execution)
+	if err0 != nil {
+		return nil, err0
+	}
+	msBuilder, err1 := context.loadWorkflowExecution()
+	if err1 != nil {
+		release(err1)
+		return nil, err1
+	}
+	execution := context.workflowExecution
+	nextEventID := msBuilder.GetNextEventID()
+	if request.NextEventID > 0 && request.NextEventID < nextEventID {
+		nextEventID = request.NextEventID
+	}
+	var replicationState *persistence.ReplicationState
+	if msBuilder.replicationState != nil {
+		replicationState = copyReplicationState(msBuilder.replicationState)
+	}
+	// the history is read without holding the lock on the execution
+	release(nil)
+
+	response, err := e.historyMgr.GetWorkflowExecutionRawHistory(&persistence.GetWorkflowExecutionHistoryRequest{
+		DomainID:      request.DomainID,
+		Execution:     execution,
+		FirstEventID:  request.FirstEventID,
+		NextEventID:   nextEventID,
+		PageSize:      request.PageSize,
+		NextPageToken: request.NextPageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	batches := response.Batches
+	if request.MinVersion != nil || request.MaxVersion != nil {
+		batches = make([]*persistence.RawHistoryBatch, 0, len(response.Batches))
+		for _, batch := range response.Batches {
+			inRange, err := e.isRawHistoryBatchInVersionRange(batch, request.MinVersion, request.MaxVersion)
+			if err != nil {
+				return nil, err
+			}
+			if inRange {
+				batches = append(batches, batch)
+			}
+		}
+	}
+
+	return &RawHistoryResponse{
+		Batches:          batches,
+		ReplicationState: replicationState,
+		NextEventID:      nextEventID,
+		NextPageToken:    response.NextPageToken,
+	}, nil
+}
+
+// GetWorkflowExecutionHistoryWindow returns the events of a workflow execution which are at most Radius events away
+// from EventID
+func (e *historyEngineImpl) GetWorkflowExecutionHistoryWindow(request *HistoryWindowRequest) (*workflow.History,
+	error) {
+	if request.Radius < 0 {
+		return nil, &workflow.BadRequestError{Message: "Radius must not be negative."}
+	}
+
+	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(request.DomainID, request.Execution)
+	if err0 != nil {
+		return nil, err0
+	}
+	msBuilder, err1 := context.loadWorkflowExecution()
+	if err1 != nil {
+		release(err1)
+		return nil, err1
+	}
+	execution := context.workflowExecution
+	nextEventID := msBuilder.GetNextEventID()
+	// the history is read without holding the lock on the execution
+	release(nil)
+
+	if request.EventID < common.FirstEventID || request.EventID >= nextEventID {
+		return nil, &workflow.BadRequestError{
+			Message: fmt.Sprintf("Event %v is not in the history of the workflow execution.", request.EventID),
+		}
+	}
+	firstEventID := request.EventID - request.Radius
+	if firstEventID < common.FirstEventID {
+		firstEventID = common.FirstEventID
+	}
+	lastEventID := request.EventID + request.Radius
+	if lastEventID >= nextEventID {
+		lastEventID = nextEventID - 1
+	}
+
+	events, err := e.readHistoryEvents(request.DomainID, execution, firstEventID, lastEventID+1)
+	if err != nil {
+		return nil, err
+	}
+	// batches are keyed by their first event, so the start of the window can be in a batch which starts before it
+	readTo := firstEventID
+	for lookBehind := int64(historyWindowLookBehind); readTo > common.FirstEventID &&
+		(len(events) == 0 || events[0].GetEventId() > firstEventID); lookBehind *= 2 {
+		readFrom := readTo - lookBehind
+		if readFrom < common.FirstEventID {
+			readFrom = common.FirstEventID
+		}
+		previousEvents, err := e.readHistoryEvents(request.DomainID, execution, readFrom, readTo)
+		if err != nil {
+			return nil, err
+		}
+		events = append(previousEvents, events...)
+		readTo = readFrom
+	}
+
+	window := &workflow.History{}
+	for _, event := range events {
+		if event.GetEventId() >= firstEventID && event.GetEventId() <= lastEventID {
+			window.Events = append(window.Events, event)
+		}
+	}
+	return window, nil
+}
+
+// GetHistoryEvent returns a single event of the history of a workflow execution.  Batches are keyed by their first
+// event, so the batches starting shortly before the event are read until the one holding it is found.
+func (e *historyEngineImpl) GetHistoryEvent(domainID string, execution workflow.WorkflowExecution, eventID int64) (
+	*workflow.HistoryEvent, error) {
+	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(domainID, execution)
+	if err0 != nil {
+		return nil, err0
+	}
+	msBuilder, err1 := context.loadWorkflowExecution()
+	if err1 != nil {
+		release(err1)
+		return nil, err1
+	}
+	execution = context.workflowExecution
+	nextEventID := msBuilder.GetNextEventID()
+	// the history is read without holding the lock on the execution
+	release(nil)
+
+	notExistsErr := &workflow.EntityNotExistsError{
+		Message: fmt.Sprintf("Event %v is not in the history of the workflow execution.", eventID),
+	}
+	if eventID < common.FirstEventID || eventID >= nextEventID {
+		return nil, notExistsErr
+	}
+
+	readTo := eventID + 1
+	for lookBehind := int64(historyWindowLookBehind); readTo > common.FirstEventID; lookBehind *= 2 {
+		readFrom := readTo - lookBehind
+		if readFrom < common.FirstEventID {
+			readFrom = common.FirstEventID
+		}
+		events, err := e.readHistoryEvents(domainID, execution, readFrom, readTo)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			if event.GetEventId() == eventID {
+				return event, nil
+			}
+		}
+		if len(events) > 0 {
+			// the last batch starting before the event would hold it
+			return nil, notExistsErr
+		}
+		readTo = readFrom
+	}
+	return nil, notExistsErr
+}
+
+// readHistoryEvents returns the events of the history batches starting in [firstEventID, nextEventID)
+func (e *historyEngineImpl) readHistoryEvents(domainID string, execution workflow.WorkflowExecution,
+	firstEventID, nextEventID int64) ([]*workflow.HistoryEvent, error) {
+	var events []*workflow.HistoryEvent
+	var nextPageToken []byte
+	for {
+		response, err := e.historyMgr.GetWorkflowExecutionHistory(&persistence.GetWorkflowExecutionHistoryRequest{
+			DomainID:      domainID,
+			Execution:     execution,
+			FirstEventID:  firstEventID,
+			NextEventID:   nextEventID,
+			PageSize:      defaultHistoryPageSize,
+			NextPageToken: nextPageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, batch := range response.Events {
+			persistence.SetSerializedHistoryDefaults(&batch)
+			serializer, err := e.hSerializerFactory.Get(batch.EncodingType)
+			if err != nil {
+				return nil, err
+			}
+			history, err := serializer.Deserialize(&batch)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, history.Events...)
+		}
+
+		nextPageToken = response.NextPageToken
+		if len(nextPageToken) == 0 {
+			return events, nil
+		}
+	}
+}
+
+func (e *historyEngineImpl) isRawHistoryBatchInVersionRange(batch *persistence.RawHistoryBatch,
+	minVersion, maxVersion *int64) (bool, error) {
+	serializer, err := e.hSerializerFactory.Get(batch.Events.EncodingType)
+	if err != nil {
+		return false, err
+	}
+	history, err := serializer.Deserialize(&batch.Events)
+	if err != nil {
+		return false, err
+	}
+	if len(history.Events) == 0 {
+		return false, nil
+	}
+
+	// all events of a batch are written by the same transaction, so they share a version
+	version := history.Events[0].GetVersion()
+	if minVersion != nil && version < *minVersion {
+		return false, nil
+	}
+	if maxVersion != nil && version > *maxVersion {
+		return false, nil
+	}
+	return true, nil
+}
+
+func copyReplicationState(source *persistence.ReplicationState) *persistence.ReplicationState {
+	lastReplicationInfo := make(map[string]*persistence.ReplicationInfo, len(source.LastReplicationInfo))
+	for cluster, info := range source.LastReplicationInfo {
+		infoCopy := *info
+		lastReplicationInfo[cluster] = &infoCopy
+	}
+	return &persistence.ReplicationState{
+		CurrentVersion:      source.CurrentVersion,
+		StartVersion:        source.StartVersion,
+		LastWriteVersion:    source.LastWriteVersion,
+		LastWriteEventID:    source.LastWriteEventID,
+		LastReplicationInfo: lastReplicationInfo,
+	}
+}
+
+// ForceFailDecisionTask fails the started decision of a workflow execution and schedules a new one, so an execution
+// whose worker is stuck on a decision makes progress without waiting for the decision to time out
+func (e *historyEngineImpl) ForceFailDecisionTask(domainID string, execution workflow.WorkflowExecution,
+	identity string) error {
+	if err := e.validateDomainActive(domainID); err != nil {
+		return err
+	}
+
+	runID := execution.GetRunId()
+	err := e.updateWorkflowExecution(domainID, execution, false, true,
+		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error) {
+			if !msBuilder.isWorkflowExecutionRunning() {
+				return nil, ErrWorkflowCompleted
+			}
+
+			di, isRunning := msBuilder.GetPendingDecision(msBuilder.executionInfo.DecisionScheduleID)
+			if !isRunning || di.StartedID == emptyEventID {
+				return nil, &workflow.BadRequestError{Message: "No decision task is started."}
+			}
+			runID = msBuilder.executionInfo.RunID
+
+			timeoutWorkflow := msBuilder.shouldTimeoutOnDecisionFailure(di.Attempt)
+			msBuilder.AddDecisionTaskFailedEvent(di.ScheduleID, di.StartedID,
+				workflow.DecisionTaskFailedCauseForceFailedByOperator, nil, identity)
+			if timeoutWorkflow {
+				return []persistence.Task{tBuilder.AddWorkflowTimeoutNowTask()}, nil
+			}
+			return nil, nil
+		})
+
+	// the update is retried on conflicts, so the audit log is only written once it went through
+	if err == nil {
+		logging.LogDecisionForceFailedEvent(e.logger, domainID, execution.GetWorkflowId(), runID, identity)
+	}
+	return err
+}
+
+// SetWorkflowExecutionPaused pauses or resumes a workflow execution.  No decision task is scheduled for a paused
+// execution, events which would have scheduled one are recorded and left for the decision scheduled once the
+// execution is resumed.  A paused execution still times out once its execution timeout is reached.
+func (e *historyEngineImpl) SetWorkflowExecutionPaused(domainID string, execution workflow.WorkflowExecution,
+	paused bool) error {
+	if err := e.validateDomainActive(domainID); err != nil {
+		return err
+	}
+
+	return e.updateWorkflowExecution(domainID, execution, false, !paused,
+		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error) {
+			if !msBuilder.isWorkflowExecutionRunning() {
+				return nil, ErrWorkflowCompleted
+			}
+
+			msBuilder.executionInfo.Paused = paused
+			return nil, nil
+		})
+}
+
+// SetWorkflowExecutionOperatorTags sets and clears the tags operators attach to a running workflow execution.  Tags
+// are advisory, they are returned by DescribeWorkflowExecution and otherwise ignored, except for the do-not-terminate
+// tag which TerminateWorkflowExecution honors when the request asks for it.  Cleared tags are removed before the set
+// ones are applied.
+func (e *historyEngineImpl) SetWorkflowExecutionOperatorTags(domainID string, execution workflow.WorkflowExecution,
+	setTags map[string]string, clearTags []string) error {
+	if err := e.validateDomainActive(domainID); err != nil {
+		return err
+	}
+
+	config := e.shard.GetConfig()
+	for key, value := range setTags {
+		if key == "" {
+			return &workflow.BadRequestError{Message: "Operator tag key is not set."}
+		}
+		if len(key) > config.MaxOperatorTagLength || len(value) > config.MaxOperatorTagLength {
+			return &workflow.BadRequestError{
+				Message: fmt.Sprintf("Operator tag %v is longer than %v.", key, config.MaxOperatorTagLength),
+			}
+		}
+	}
+
+	return e.updateWorkflowExecution(domainID, execution, false, false,
+		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error) {
+			if !msBuilder.isWorkflowExecutionRunning() {
+				return nil, ErrWorkflowCompleted
+			}
+
+			tags := make(map[string]string, len(msBuilder.executionInfo.OperatorTags)+len(setTags))
+			for key, value := range msBuilder.executionInfo.OperatorTags {
+				tags[key] = value
+			}
+			for _, key := range clearTags {
+				delete(tags, key)
+			}
+			for key, value := range setTags {
+				tags[key] = value
+			}
+			if len(tags) > config.MaxOperatorTags {
+				return nil, &workflow.BadRequestError{
+					Message: fmt.Sprintf("Workflow execution cannot have more than %v operator tags.", config.MaxOperatorTags),
+				}
+			}
+
+			msBuilder.executionInfo.OperatorTags = tags
+			return nil, nil
+		})
+}
+
+// ForceDeleteWorkflowExecution deletes the mutable state, current execution record and history of a workflow
+// execution without any of the checks done when an execution is closed, so operators can get rid of executions whose
+// mutable state is inconsistent.  Unless Force is set, only executions which are closed or whose mutable state is gone
+// are deleted, as a running execution or one which failed to load for another reason may well be healthy.
+func (e *historyEngineImpl) ForceDeleteWorkflowExecution(request *ForceDeleteRequest) (retError error) {
+	if !request.Confirm {
+		return &workflow.BadRequestError{Message: "Confirm must be set to force delete a workflow execution."}
+	}
+	domainID := request.DomainID
+	execution := request.Execution
+	if execution.GetRunId() == "" {
+		return &workflow.BadRequestError{Message: "RunId must be set to force delete a workflow execution."}
+	}
+
+	endOperation, err := e.beginWorkflowUpdate(domainID)
+	if err != nil {
+		return err
+	}
+	defer endOperation()
+
+	context, release, err := e.historyCache.getOrCreateWorkflowExecution(domainID, execution)
+	if err != nil {
+		return err
+	}
+	defer func() { release(retError) }()
+
+	isRunning := false
+	msBuilder, err := context.loadWorkflowExecution()
+	if err == nil {
+		isRunning = msBuilder.isWorkflowExecutionRunning()
+		if isRunning && !request.Force {
+			return &workflow.BadRequestError{Message: "Workflow execution is running, set Force to delete it."}
+		}
+	} else if _, ok := err.(*workflow.EntityNotExistsError); !ok && !request.Force {
+		return err
+	}
+
+	if err := e.executionManager.DeleteCurrentWorkflowExecution(&persistence.DeleteCurrentWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: execution.GetWorkflowId(),
+		RunID:      execution.GetRunId(),
+	}); err != nil {
+		return err
+	}
+	if err := e.shard.DeleteWorkflowExecution(&persistence.DeleteWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: execution.GetWorkflowId(),
+		RunID:      execution.GetRunId(),
+	}, isRunning); err != nil {
+		return err
+	}
+	if err := e.historyMgr.DeleteWorkflowExecutionHistory(&persistence.DeleteWorkflowExecutionHistoryRequest{
+		DomainID:  domainID,
+		Execution: execution,
+	}); err != nil {
+		return err
+	}
+
+	context.clear()
+	logging.LogWorkflowForceDeletedEvent(e.logger, domainID, execution.GetWorkflowId(), execution.GetRunId(),
+		request.Identity, isRunning)
+	return nil
+}
+
+// ReconcileCurrentExecution repairs the current execution record of a workflow which is left pointing at a run that
+// continued as new, as happens when the continue-as-new of the run only partially made it to persistence.  The record
+// is moved along the chain of continued runs to the latest run which exists, so once repaired further calls leave it
+// alone.  Whether the record had to be repaired is returned.
+func (e *historyEngineImpl) ReconcileCurrentExecution(domainID, workflowID string) (bool, error) {
+	current, err := e.executionManager.GetCurrentExecution(&persistence.GetCurrentExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	execution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(current.RunID),
+	}
+	response, err := e.executionManager.GetWorkflowExecution(&persistence.GetWorkflowExecutionRequest{
+		DomainID:  domainID,
+		Execution: execution,
+	})
+	if err != nil {
+		return false, err
+	}
+	latest := response.State.ExecutionInfo
+
+	for latest.State == persistence.WorkflowStateCompleted &&
+		latest.CloseStatus == persistence.WorkflowCloseStatusContinuedAsNew {
+		// the continued as new event is the last event of the run
+		event, err := e.readHistoryEvent(domainID, execution, latest.NextEventID-1)
+		if err != nil {
+			return false, err
+		}
+		if event.GetEventType() != workflow.EventTypeWorkflowExecutionContinuedAsNew {
+			return false, &workflow.InternalServiceError{
+				Message: fmt.Sprintf("Last event of run %v is not a continued as new event.", execution.GetRunId()),
+			}
+		}
+
+		newRunID := event.WorkflowExecutionContinuedAsNewEventAttributes.GetNewExecutionRunId()
+		newExecution := workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(workflowID),
+			RunId:      common.StringPtr(newRunID),
+		}
+		response, err := e.executionManager.GetWorkflowExecution(&persistence.GetWorkflowExecutionRequest{
+			DomainID:  domainID,
+			Execution: newExecution,
+		})
+		if err != nil {
+			if _, ok := err.(*workflow.EntityNotExistsError); ok {
+				// the new run was never created, the run is the latest one of the chain
+				break
+			}
+			return false, err
+		}
+		execution = newExecution
+		latest = response.State.ExecutionInfo
+	}
+
+	if latest.RunID == current.RunID {
+		return false, nil
+	}
+
+	if err := e.executionManager.UpdateCurrentWorkflowExecution(&persistence.UpdateCurrentWorkflowExecutionRequest{
+		DomainID:       domainID,
+		WorkflowID:     workflowID,
+		RunID:          latest.RunID,
+		PreviousRunID:  current.RunID,
+		StartRequestID: latest.CreateRequestID,
+		State:          latest.State,
+		CloseStatus:    latest.CloseStatus,
+	}); err != nil {
+		return false, err
+	}
+
+	e.metricsClient.IncCounter(metrics.HistoryReconcileCurrentExecutionScope, metrics.CurrentExecutionRepairedCounter)
+	e.logger.WithFields(bark.Fields{
+		logging.TagDomainID:            domainID,
+		logging.TagWorkflowExecutionID: workflowID,
+		logging.TagWorkflowRunID:       latest.RunID,
+	}).Warnf("Repaired current execution which pointed at run %v.", current.RunID)
+	return true, nil
+}
+
+// RedriveTransferTasks writes the transfer tasks of the outstanding activities, child executions, external cancels and
+// signals of a workflow execution again, so operators can retrigger tasks which got stuck on a downstream failure.
+// No task is written for an activity or child which is started already, and the processors drop tasks for work which
+// is done, so it is safe to call more than once.  The number of tasks written is returned.
+func (e *historyEngineImpl) RedriveTransferTasks(domainID string,
+	execution workflow.WorkflowExecution) (retCount int, retError error) {
+	if err := e.validateDomainActive(domainID); err != nil {
+		return 0, err
+	}
+
+	endOperation, err := e.beginWorkflowUpdate(domainID)
+	if err != nil {
+		return 0, err
+	}
+	defer endOperation()
+
+	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(domainID, execution)
+	if err0 != nil {
+		return 0, err0
+	}
+	defer func() { release(retError) }()
+
+Update_History_Loop:
+	for attempt := 0; attempt < conditionalRetryCount; attempt++ {
+		msBuilder, err1 := context.loadWorkflowExecution()
+		if err1 != nil {
+			return 0, err1
+		}
+		if !msBuilder.isWorkflowExecutionRunning() {
+			return 0, ErrWorkflowCompleted
+		}
+
+		transferTasks, err2 := e.getOutstandingTransferTasks(domainID, context.workflowExecution, msBuilder)
+		if err2 != nil {
+			return 0, err2
+		}
+		if len(transferTasks) == 0 {
+			return 0, nil
+		}
+
+		transactionID, err3 := e.shard.GetNextTransferTaskID()
+		if err3 != nil {
+			return 0, err3
+		}
+
+		if err := context.updateWorkflowExecution(transferTasks, nil, transactionID); err != nil {
+			if err == ErrConflict {
+				continue Update_History_Loop
+			}
+			return 0, err
+		}
+		e.metricsClient.AddCounter(metrics.HistoryRedriveTransferTasksScope, metrics.RedrivenTransferTasksCounter,
+			int64(len(transferTasks)))
+		return len(transferTasks), nil
+	}
+	return 0, context.newMaxAttemptsExceededError()
+}
+
+// GetActivityScheduledEvent returns the attributes an activity was scheduled with, so tooling can inspect the input of
+// an activity in flight without reading the history of the workflow execution
+func (e *historyEngineImpl) GetActivityScheduledEvent(domainID string, execution workflow.WorkflowExecution,
+	scheduleID int64) (retAttributes *workflow.ActivityTaskScheduledEventAttributes, retError error) {
+	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(domainID, execution)
+	if err0 != nil {
+		return nil, err0
+	}
+	defer func() { release(retError) }()
+
+	msBuilder, err1 := context.loadWorkflowExecution()
+	if err1 != nil {
+		return nil, err1
+	}
+
+	if _, ok := msBuilder.GetActivityInfo(scheduleID); !ok {
+		return nil, ErrActivityTaskNotFound
+	}
+	scheduledEvent, ok := msBuilder.GetActivityScheduledEvent(scheduleID)
+	if !ok {
+		return nil, &workflow.InternalServiceError{Message: "Unable to get activity scheduled event."}
+	}
+	return scheduledEvent.ActivityTaskScheduledEventAttributes, nil
+}
+
+// DescribeActivity returns the state of a single pending activity of a workflow execution, for monitoring an activity
+// without describing the whole workflow execution
+func (e *historyEngineImpl) DescribeActivity(domainID string, execution workflow.WorkflowExecution,
+	activityID string) (retDescription *ActivityDescription, retError error) {
+	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(domainID, execution)
+	if err0 != nil {
+		return nil, err0
+	}
+	defer func() { release(retError) }()
+
+	msBuilder, err1 := context.loadWorkflowExecution()
+	if err1 != nil {
+		return nil, err1
+	}
+
+	scheduleID, ok := msBuilder.GetScheduleIDByActivityID(activityID)
+	if !ok {
+		return nil, ErrActivityTaskNotFound
+	}
+	ai, ok := msBuilder.GetActivityInfo(scheduleID)
+	if !ok {
+		return nil, ErrActivityTaskNotFound
+	}
+	scheduledEvent, ok := msBuilder.GetActivityScheduledEvent(scheduleID)
+	if !ok {
+		return nil, &workflow.InternalServiceError{Message: "Unable to get activity scheduled event."}
+	}
+	attributes := scheduledEvent.ActivityTaskScheduledEventAttributes
+
+	description := &ActivityDescription{
+		ActivityID:               ai.ActivityID,
+		ScheduleID:               ai.ScheduleID,
+		ActivityType:             attributes.ActivityType,
+		TaskList:                 attributes.TaskList.GetName(),
+		State:                    getPendingActivityState(ai),
+		ScheduledTime:            ai.ScheduledTime,
+		ScheduleToStartTimeout:   ai.ScheduleToStartTimeout,
+		ScheduleToCloseTimeout:   ai.ScheduleToCloseTimeout,
+		StartToCloseTimeout:      ai.StartToCloseTimeout,
+		HeartbeatTimeout:         ai.HeartbeatTimeout,
+		CheckpointSequenceNumber: ai.CheckpointSequenceNumber,
+	}
+	if ai.TaskList != "" {
+		description.TaskList = ai.TaskList
+	}
+	if ai.StartedID != emptyEventID {
+		description.StartedTime = ai.StartedTime
+		description.StartedIdentity = ai.StartedIdentity
+	}
+	if ai.LastHeartBeatUpdatedTime.UnixNano() > 0 {
+		description.LastHeartbeatTime = ai.LastHeartBeatUpdatedTime
+		description.HeartbeatDetails = ai.Details
+	}
+	return description, nil
+}
+
+// GetWorkflowExecutionResult returns the outcome of a closed workflow execution as recorded by its close event.  The
+// close event is the last event of the history, so only the last batch of the history is read.
+func (e *historyEngineImpl) GetWorkflowExecutionResult(domainID string, execution workflow.WorkflowExecution) (
+	retResult *WorkflowExecutionResult, retError error) {
+	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(domainID, execution)
+	if err0 != nil {
+		return nil, err0
+	}
+	defer func() { release(retError) }()
+
+	msBuilder, err1 := context.loadWorkflowExecution()
+	if err1 != nil {
+		return nil, err1
+	}
+
+	executionInfo := msBuilder.executionInfo
+	if msBuilder.isWorkflowExecutionRunning() {
+		return nil, ErrWorkflowRunning
+	}
+
+	closeEvent, err := e.readLastHistoryEvent(domainID, context.workflowExecution, executionInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WorkflowExecutionResult{
+		RunID:       executionInfo.RunID,
+		CloseStatus: getWorkflowExecutionCloseStatus(executionInfo.CloseStatus),
+	}
+	// the result and failure details of a run may have been offloaded when it closed, they are hydrated the same way
+	// the frontend hydrates them in the history it serves
+	payloadOffloader := e.shard.GetConfig().PayloadOffloader
+	switch closeEvent.GetEventType() {
+	case workflow.EventTypeWorkflowExecutionCompleted:
+		result.Result, err = payloadOffloader.Hydrate(domainID, closeEvent.WorkflowExecutionCompletedEventAttributes.Result)
+		if err != nil {
+			return nil, err
+		}
+	case workflow.EventTypeWorkflowExecutionFailed:
+		attributes := closeEvent.WorkflowExecutionFailedEventAttributes
+		result.Reason = attributes.GetReason()
+		result.Details, err = payloadOffloader.Hydrate(domainID, attributes.Details)
+		if err != nil {
+			return nil, err
+		}
+	case workflow.EventTypeWorkflowExecutionCanceled:
+		result.Details = closeEvent.WorkflowExecutionCanceledEventAttributes.Details
+	case workflow.EventTypeWorkflowExecutionTerminated:
+		attributes := closeEvent.WorkflowExecutionTerminatedEventAttributes
+		result.Reason = attributes.GetReason()
+		result.Details = attributes.Details
+	case workflow.EventTypeWorkflowExecutionContinuedAsNew:
+		result.NewExecutionRunID = closeEvent.WorkflowExecutionContinuedAsNewEventAttributes.GetNewExecutionRunId()
+	case workflow.EventTypeWorkflowExecutionTimedOut:
+		result.TimeoutType = closeEvent.WorkflowExecutionTimedOutEventAttributes.TimeoutType
+	default:
+		return nil, &workflow.InternalServiceError{
+			Message: fmt.Sprintf("Last event of closed run %v is not a close event.", executionInfo.RunID),
+		}
+	}
+	return result, nil
+}
+
+// getOutstandingTransferTasks rebuilds the transfer tasks of the activities and child executions which are not
+// started yet and of the external cancels and signals which are not delivered yet
+func (e *historyEngineImpl) getOutstandingTransferTasks(domainID string, execution workflow.WorkflowExecution,
+	msBuilder *mutableStateBuilder) ([]persistence.Task, error) {
+	var transferTasks []persistence.Task
+	for _, ai := range msBuilder.pendingActivityInfoIDs {
+		if ai.StartedID != emptyEventID {
+			continue
+		}
+		scheduledEvent, ok := msBuilder.getHistoryEvent(ai.ScheduledEvent)
+		if !ok {
+			return nil, &workflow.InternalServiceError{Message: "Unable to get activity scheduled event."}
+		}
+		attributes := scheduledEvent.ActivityTaskScheduledEventAttributes
+		targetDomainID, err := e.getTargetDomainID(domainID, attributes.GetDomain())
+		if err != nil {
+			return nil, err
+		}
+		taskList := ai.TaskList
+		if taskList == "" {
+			taskList = attributes.TaskList.GetName()
+		}
+		transferTasks = append(transferTasks, &persistence.ActivityTask{
+			DomainID:   targetDomainID,
+			TaskList:   taskList,
+			ScheduleID: ai.ScheduleID,
+			Priority:   ai.Priority,
+		})
+	}
+
+	for _, ci := range msBuilder.pendingChildExecutionInfoIDs {
+		if ci.StartedID != emptyEventID {
+			continue
+		}
+		initiatedEvent, ok := msBuilder.getHistoryEvent(ci.InitiatedEvent)
+		if !ok {
+			return nil, &workflow.InternalServiceError{Message: "Unable to get child execution initiated event."}
+		}
+		attributes := initiatedEvent.StartChildWorkflowExecutionInitiatedEventAttributes
+		targetDomainID, err := e.getTargetDomainID(domainID, attributes.GetDomain())
+		if err != nil {
+			return nil, err
+		}
+		transferTasks = append(transferTasks, &persistence.StartChildExecutionTask{
+			TargetDomainID:   targetDomainID,
+			TargetWorkflowID: attributes.GetWorkflowId(),
+			InitiatedID:      ci.InitiatedID,
+		})
+	}
+
+	// the initiated events of external cancels and signals are only kept in history
+	for _, rci := range msBuilder.pendingRequestCancelInfoIDs {
+		initiatedEvent, err := e.readHistoryEvent(domainID, execution, rci.InitiatedID)
+		if err != nil {
+			return nil, err
+		}
+		attributes := initiatedEvent.RequestCancelExternalWorkflowExecutionInitiatedEventAttributes
+		targetDomainID, err := e.getTargetDomainID(domainID, attributes.GetDomain())
+		if err != nil {
+			return nil, err
+		}
+		transferTasks = append(transferTasks, &persistence.CancelExecutionTask{
+			TargetDomainID:          targetDomainID,
+			TargetWorkflowID:        attributes.WorkflowExecution.GetWorkflowId(),
+			TargetRunID:             attributes.WorkflowExecution.GetRunId(),
+			TargetChildWorkflowOnly: attributes.GetChildWorkflowOnly(),
+			InitiatedID:             rci.InitiatedID,
+		})
+	}
+
+	for _, si := range msBuilder.pendingSignalInfoIDs {
+		initiatedEvent, err := e.readHistoryEvent(domainID, execution, si.InitiatedID)
+		if err != nil {
+			return nil, err
+		}
+		attributes := initiatedEvent.SignalExternalWorkflowExecutionInitiatedEventAttributes
+		targetDomainID, err := e.getTargetDomainID(domainID, attributes.GetDomain())
+		if err != nil {
+			return nil, err
+		}
+		transferTasks = append(transferTasks, &persistence.SignalExecutionTask{
+			TargetDomainID:          targetDomainID,
+			TargetWorkflowID:        attributes.WorkflowExecution.GetWorkflowId(),
+			TargetRunID:             attributes.WorkflowExecution.GetRunId(),
+			TargetChildWorkflowOnly: attributes.GetChildWorkflowOnly(),
+			InitiatedID:             si.InitiatedID,
+		})
+	}
+	return transferTasks, nil
+}
+
+// getTargetDomainID resolves the domain named by a decision, an empty name refers to the domain of the workflow
+func (e *historyEngineImpl) getTargetDomainID(domainID, domain string) (string, error) {
+	if domain == "" {
+		return domainID, nil
+	}
+	domainEntry, err := e.shard.GetDomainCache().GetDomain(domain)
+	if err != nil {
+		return "", err
+	}
+	return domainEntry.GetInfo().ID, nil
+}
+
+// readLastHistoryEvent reads the last event of a workflow execution from the batch the last append wrote.
+func (e *historyEngineImpl) readLastHistoryEvent(domainID string, execution workflow.WorkflowExecution,
+	executionInfo *persistence.WorkflowExecutionInfo) (*workflow.HistoryEvent, error) {
+	response, err := e.historyMgr.GetWorkflowExecutionHistory(&persistence.GetWorkflowExecutionHistoryRequest{
+		DomainID:     domainID,
+		Execution:    execution,
+		FirstEventID: executionInfo.LastFirstEventID,
+		NextEventID:  executionInfo.NextEventID,
+		PageSize:     defaultHistoryPageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var lastEvent *workflow.HistoryEvent
+	for _, batch := range response.Events {
+		persistence.SetSerializedHistoryDefaults(&batch)
+		serializer, err := e.hSerializerFactory.Get(batch.EncodingType)
+		if err != nil {
+			return nil, err
+		}
+		history, err := serializer.Deserialize(&batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range history.Events {
+			if event.GetEventId() == executionInfo.NextEventID-1 {
+				lastEvent = event
+			}
+		}
+	}
+	if lastEvent == nil {
+		return nil, &workflow.EntityNotExistsError{
+			Message: fmt.Sprintf("Event %v of run %v not found.", executionInfo.NextEventID-1, execution.GetRunId()),
+		}
+	}
+	return lastEvent, nil
+}
+
+// readHistoryEvent reads a single event of a workflow execution from history.  Events are stored in batches keyed
+// by their first event, so history is read from the start until the batch holding the event.
+func (e *historyEngineImpl) readHistoryEvent(domainID string, execution workflow.WorkflowExecution,
+	eventID int64) (*workflow.HistoryEvent, error) {
+	var nextPageToken []byte
+	for {
+		response, err := e.historyMgr.GetWorkflowExecutionHistory(&persistence.GetWorkflowExecutionHistoryRequest{
+			DomainID:      domainID,
+			Execution:     execution,
+			FirstEventID:  common.FirstEventID,
+			NextEventID:   eventID + 1,
+			PageSize:      defaultHistoryPageSize,
+			NextPageToken: nextPageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, batch := range response.Events {
+			persistence.SetSerializedHistoryDefaults(&batch)
+			serializer, err := e.hSerializerFactory.Get(batch.EncodingType)
+			if err != nil {
+				return nil, err
+			}
+			history, err := serializer.Deserialize(&batch)
+			if err != nil {
+				return nil, err
+			}
+			for _, event := range history.Events {
+				if event.GetEventId() == eventID {
+					return event, nil
+				}
+			}
+		}
+
+		nextPageToken = response.NextPageToken
+		if len(nextPageToken) == 0 {
+			return nil, &workflow.EntityNotExistsError{Message: fmt.Sprintf("History event %v not found.", eventID)}
+		}
+	}
+}
+
+// ValidateDecisions runs the validation done by RespondDecisionTaskCompleted over a list of decisions of a workflow
+// execution of the domain, without loading or updating the execution.  Only target domains referenced by the decisions
+// are resolved.  Checks which depend on the state of the execution, like unhandled events, are not performed.
+func (e *historyEngineImpl) ValidateDecisions(domainID string, decisions []*workflow.Decision) (
+	[]*DecisionValidationResult, error) {
+	disabledDecisionTypes, err := e.getDisabledDecisionTypes(domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	executionInfo := &persistence.WorkflowExecutionInfo{DomainID: domainID}
+	results := make([]*DecisionValidationResult, 0, len(decisions))
+	for _, d := range decisions {
+		if d == nil {
+			return nil, &workflow.BadRequestError{Message: "Decision is not set."}
+		}
+		// validation fills in defaults inherited from the current run, so work on copies of those attributes
+		copied := *d
+		if attributes := copied.ContinueAsNewWorkflowExecutionDecisionAttributes; attributes != nil {
+			attributesCopy := *attributes
+			copied.ContinueAsNewWorkflowExecutionDecisionAttributes = &attributesCopy
+		}
+		if attributes := copied.StartChildWorkflowExecutionDecisionAttributes; attributes != nil {
+			attributesCopy := *attributes
+			copied.StartChildWorkflowExecutionDecisionAttributes = &attributesCopy
+		}
+
+		result := &DecisionValidationResult{}
+		_, failCause, err := e.validateDecision(domainID, executionInfo, disabledDecisionTypes, &copied)
+		if err != nil {
+			if failCause == nil {
+				return nil, err
+			}
+			result.Cause = failCause
+			result.Message = getDecisionFailureMessage(err)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// validateDecision runs the checks a decision has to pass before RespondDecisionTaskCompleted applies it to the
+// execution and resolves the id of the domain the decision targets, which is the domain of the execution unless the
+// decision names another one.  A decision failing the checks returns the cause to fail the decision task with along
+// with the error, any other error is returned without a cause.  The attributes of continue as new and child workflow
+// decisions are filled in with the defaults inherited from the execution.
+func (e *historyEngineImpl) validateDecision(domainID string, executionInfo *persistence.WorkflowExecutionInfo,
+	disabledDecisionTypes map[string]struct{}, d *workflow.Decision) (string, *workflow.DecisionTaskFailedCause, error) {
+	if d.DecisionType == nil {
+		return "", nil, &workflow.BadRequestError{Message: "DecisionType is not set on decision."}
+	}
+	if _, ok := disabledDecisionTypes[d.GetDecisionType().String()]; ok {
+		return "", workflow.DecisionTaskFailedCauseDecisionTypeDisabled.Ptr(), &workflow.BadRequestError{
+			Message: fmt.Sprintf("Decision type %v is disabled.", d.GetDecisionType()),
+		}
+	}
+
+	config := e.shard.GetConfig()
+	targetDomainID := domainID
+	var failCause workflow.DecisionTaskFailedCause
+	var err error
+	switch d.GetDecisionType() {
+	case workflow.DecisionTypeScheduleActivityTask:
+		failCause = workflow.DecisionTaskFailedCauseBadScheduleActivityAttributes
+		attributes := d.ScheduleActivityTaskDecisionAttributes
+		if err = validateActivityScheduleAttributes(attributes, config.MaxTaskListNameLength,
+			config.MaxScheduleInputSize()); err != nil {
+			break
+		}
+		if targetDomainID, err = e.getTargetDomainID(domainID, attributes.GetDomain()); err != nil {
+			return "", nil, &workflow.InternalServiceError{Message: "Unable to schedule activity across domain."}
+		}
+
+	case workflow.DecisionTypeCompleteWorkflowExecution:
+		failCause = workflow.DecisionTaskFailedCauseBadCompleteWorkflowExecutionAttributes
+		err = validateCompleteWorkflowExecutionAttributes(d.CompleteWorkflowExecutionDecisionAttributes)
+
+	case workflow.DecisionTypeFailWorkflowExecution:
+		failCause = workflow.DecisionTaskFailedCauseBadFailWorkflowExecutionAttributes
+		err = validateFailWorkflowExecutionAttributes(d.FailWorkflowExecutionDecisionAttributes)
+
+	case workflow.DecisionTypeCancelWorkflowExecution:
+		failCause = workflow.DecisionTaskFailedCauseBadCancelWorkflowExecutionAttributes
+		err = validateCancelWorkflowExecutionAttributes(d.CancelWorkflowExecutionDecisionAttributes)
+
+	case workflow.DecisionTypeStartTimer:
+		failCause = workflow.DecisionTaskFailedCauseBadStartTimerAttributes
+		err = validateTimerScheduleAttributes(d.StartTimerDecisionAttributes, config.MaxTimerStartToFireTimeout,
+			e.shard.GetTimeSource().Now())
+
+	case workflow.DecisionTypeRequestCancelActivityTask:
+		failCause = workflow.DecisionTaskFailedCauseBadRequestCancelActivityAttributes
+		err = validateActivityCancelAttributes(d.RequestCancelActivityTaskDecisionAttributes)
+
+	case workflow.DecisionTypeCancelTimer:
+		failCause = workflow.DecisionTaskFailedCauseBadCancelTimerAttributes
+		err = validateTimerCancelAttributes(d.CancelTimerDecisionAttributes)
+
+	case workflow.DecisionTypeRecordMarker:
+		failCause = workflow.DecisionTaskFailedCauseBadRecordMarkerAttributes
+		err = validateRecordMarkerAttributes(d.RecordMarkerDecisionAttributes, config.MaxMarkerDetailsSize)
+
+	case workflow.DecisionTypeRequestCancelExternalWorkflowExecution:
+		failCause = workflow.DecisionTaskFailedCauseBadRequestCancelExternalWorkflowExecutionAttributes
+		attributes := d.RequestCancelExternalWorkflowExecutionDecisionAttributes
+		if err = validateCancelExternalWorkflowExecutionAttributes(attributes); err != nil {
+			break
+		}
+		if targetDomainID, err = e.getTargetDomainID(domainID, attributes.GetDomain()); err != nil {
+			return "", nil, &workflow.InternalServiceError{
+				Message: fmt.Sprintf("Unable to cancel workflow across domain: %v.", attributes.GetDomain())}
+		}
+		if config.RejectSelfTargetedExternalDecisions && !attributes.GetChildWorkflowOnly() &&
+			isSelfTargeted(executionInfo, targetDomainID, attributes.GetWorkflowId(), attributes.GetRunId()) {
+			err = &workflow.BadRequestError{Message: "Workflow execution cannot request cancellation of itself."}
+		}
+
+	case workflow.DecisionTypeSignalExternalWorkflowExecution:
+		failCause = workflow.DecisionTaskFailedCauseBadSignalWorkflowExecutionAttributes
+		attributes := d.SignalExternalWorkflowExecutionDecisionAttributes
+		if err = validateSignalExternalWorkflowExecutionAttributes(attributes,
+			config.MaxSignalInputSize); err != nil {
+			break
+		}
+		if targetDomainID, err = e.getTargetDomainID(domainID, attributes.GetDomain()); err != nil {
+			return "", nil, &workflow.InternalServiceError{
+				Message: fmt.Sprintf("Unable to signal workflow across domain: %v.", attributes.GetDomain())}
+		}
+		if config.RejectSelfTargetedExternalDecisions && !attributes.GetChildWorkflowOnly() &&
+			isSelfTargeted(executionInfo, targetDomainID, attributes.Execution.GetWorkflowId(),
+				attributes.Execution.GetRunId()) {
+			err = &workflow.BadRequestError{Message: "Workflow execution cannot signal itself."}
+		}
+
+	case workflow.DecisionTypeContinueAsNewWorkflowExecution:
+		failCause = workflow.DecisionTaskFailedCauseBadContinueAsNewAttributes
+		err = validateContinueAsNewWorkflowExecutionAttributes(executionInfo,
+			d.ContinueAsNewWorkflowExecutionDecisionAttributes)
+
+	case workflow.DecisionTypeStartChildWorkflowExecution:
+		failCause = workflow.DecisionTaskFailedCauseBadStartChildExecutionAttributes
+		attributes := d.StartChildWorkflowExecutionDecisionAttributes
+		if err = validateStartChildExecutionAttributes(executionInfo, attributes, config,
+			e.shard.GetTimeSource().Now()); err != nil {
+			break
+		}
+
+		// Guard against runaway recursion of workflows which start children of themselves
+		if maxDepth := config.MaxChildWorkflowDepth; maxDepth > 0 && executionInfo.Depth+1 > maxDepth {
+			return "", workflow.DecisionTaskFailedCauseChildWorkflowDepthExceeded.Ptr(), &workflow.BadRequestError{
+				Message: fmt.Sprintf("Child workflow exceeds maximum depth of %v.", maxDepth),
+			}
+		}
+
+		// Hold off new children while the shard is backed up, the parent retries the decision after backoff
+		if err = e.checkChildStartAdmission(domainID); err != nil {
+			return "", workflow.DecisionTaskFailedCauseShardOverloaded.Ptr(), err
+		}
+
+		if targetDomainID, err = e.getTargetDomainID(domainID, attributes.GetDomain()); err != nil {
+			return "", nil, &workflow.InternalServiceError{Message: "Unable to schedule child execution across domain."}
+		}
+
+		// A child with the workflow id of its parent in the same domain collides with the current execution of the
+		// parent
+		if targetDomainID == domainID && attributes.GetWorkflowId() == executionInfo.WorkflowID &&
+			!config.AllowSelfReferentialChildWorkflow {
+			err = &workflow.BadRequestError{Message: "Child workflow cannot use the workflow id of its parent."}
+		}
+
+	default:
+		return "", nil, &workflow.BadRequestError{Message: fmt.Sprintf("Unknown decision type: %v", d.GetDecisionType())}
+	}
+
+	if err != nil {
+		return "", failCause.Ptr(), err
+	}
+	return targetDomainID, nil, nil
+}
+
+// getDecisionFailureMessage returns the message of an error a decision failed validation with
+func getDecisionFailureMessage(err error) string {
+	switch err := err.(type) {
+	case *workflow.BadRequestError:
+		return err.Message
+	case *workflow.ServiceBusyError:
+		return err.Message
+	default:
+		return err.Error()
+	}
+}
+
+// emitStaleStateReload records that an API detected cached mutable state as stale and is about to reload it.  Reloads
+// are counted per domain, and a sample of them is logged with the execution so hot spots can be attributed.
+func (e *historyEngineImpl) emitStaleStateReload(scope int, api string, executionInfo *persistence.WorkflowExecutionInfo) {
+	e.metricsClient.IncCounter(scope, metrics.StaleMutableStateCounter)
+
+	domainName := getMetricsDomainName(e.shard, executionInfo.DomainID)
+	e.metricsClient.Tagged(map[string]string{metrics.DomainTagName: domainName}).IncCounter(scope,
+		metrics.StaleMutableStatePerDomainCounter)
+
+	if rand.Float64() < e.shard.GetConfig().StaleStateReloadLogSampleRate {
+		logging.LogStaleMutableStateReloadEvent(e.logger, executionInfo.DomainID, executionInfo.WorkflowID,
+			executionInfo.RunID, api)
+	}
+}
+
+// emitDecisionConflict records the likely cause of a conflict hit while completing a decision, to tell workflows
+// contended by signals apart from those contended by their own activities.
+func (e *historyEngineImpl) emitDecisionConflict(domainID string, cause string) {
+	e.metricsClient.Tagged(map[string]string{
+		metrics.DomainTagName:        getMetricsDomainName(e.shard, domainID),
+		metrics.ConflictCauseTagName: cause,
+	}).IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.DecisionConflictCounter)
+}
+
+// emitDiscardedBufferedEvents records the events still buffered on an execution which is closing with a decision in
+// flight.  Those events are never written to history, so they are counted and a sample of closes is logged with the
+// discarded event types.  Signals cannot be recorded as a marker since no decision completes after the close, so when
+// enabled each dropped signal is logged instead.
+func emitDiscardedBufferedEvents(shard ShardContext, metricsClient metrics.Client, logger bark.Logger, scope int,
+	msBuilder *mutableStateBuilder) error {
+	if !msBuilder.HasBufferedEvents() {
+		return nil
+	}
+
+	bufferedEvents, err := msBuilder.getBufferedEvents()
+	if err != nil {
+		return err
+	}
+	metricsClient.AddCounter(scope, metrics.BufferedEventsDiscardedCounter, int64(len(bufferedEvents)))
+
+	config := shard.GetConfig()
+	executionInfo := msBuilder.executionInfo
+	if rand.Float64() < config.DiscardedBufferedEventsLogSampleRate {
+		eventTypes := make(map[string]int)
+		for _, event := range bufferedEvents {
+			eventTypes[event.GetEventType().String()]++
+		}
+		logging.LogBufferedEventsDiscardedEvent(logger, executionInfo.DomainID, executionInfo.WorkflowID,
+			executionInfo.RunID, len(bufferedEvents), eventTypes)
+	}
+
+	if config.LogDroppedSignals {
+		for _, event := range bufferedEvents {
+			if event.GetEventType() != workflow.EventTypeWorkflowExecutionSignaled {
+				continue
+			}
+			attributes := event.WorkflowExecutionSignaledEventAttributes
+			logging.LogSignalDroppedEvent(logger, executionInfo.DomainID, executionInfo.WorkflowID,
+				executionInfo.RunID, attributes.GetSignalName(), attributes.GetIdentity())
+		}
+	}
+	return nil
+}
+
+// clampStickyScheduleToStartTimeout bounds the sticky schedule to start timeout requested by a worker to the
+// configured range, so a bad worker config cannot keep decisions from being redelivered after the worker dies.
+func (e *historyEngineImpl) clampStickyScheduleToStartTimeout(timeout int32,
+	executionInfo *persistence.WorkflowExecutionInfo) int32 {
+	config := e.shard.GetConfig()
+	clamped := timeout
+	if config.StickyScheduleToStartTimeoutFloorInSecs > 0 && clamped < config.StickyScheduleToStartTimeoutFloorInSecs {
+		clamped = config.StickyScheduleToStartTimeoutFloorInSecs
+	}
+	if config.StickyScheduleToStartTimeoutCeilingInSecs > 0 &&
+		clamped > config.StickyScheduleToStartTimeoutCeilingInSecs {
+		clamped = config.StickyScheduleToStartTimeoutCeilingInSecs
+	}
+
+	if clamped != timeout {
+		logging.LogStickyTimeoutClampedEvent(e.logger, executionInfo.DomainID, executionInfo.WorkflowID,
+			executionInfo.RunID, timeout, clamped)
+	}
+	return clamped
+}
+
+// isValidStickyAttributes returns true if the sticky attributes of a completed decision can be used to make the next
+// decision sticky.  A worker task list name which would make later decisions undeliverable is logged and rejected, so
+// the workflow falls back to its normal task list instead of persisting the name.
+func (e *historyEngineImpl) isValidStickyAttributes(attributes *workflow.StickyExecutionAttributes,
+	executionInfo *persistence.WorkflowExecutionInfo) bool {
+	if attributes == nil || attributes.WorkerTaskList == nil {
+		return false
+	}
+
+	name := attributes.WorkerTaskList.GetName()
+	if err := validateStickyTaskListName(name, e.shard.GetConfig().MaxTaskListNameLength); err != nil {
+		e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.InvalidStickyTaskListCounter)
+		logging.LogInvalidStickyTaskListEvent(e.logger, executionInfo.DomainID, executionInfo.WorkflowID,
+			executionInfo.RunID, err.Error())
+		return false
+	}
+	return true
+}
+
+// emitWorkflowClosed counts a workflow execution which transitioned into a closed state, broken down by domain and
+// close status.  Continue as new is reported under its own close status rather than as a completion.
+func emitWorkflowClosed(shard ShardContext, metricsClient metrics.Client, scope int, domainID string,
+	closeStatus int) {
+	metricsClient.Tagged(map[string]string{
+		metrics.DomainTagName:      getMetricsDomainName(shard, domainID),
+		metrics.CloseStatusTagName: getWorkflowExecutionCloseStatus(closeStatus).String(),
+	}).IncCounter(scope, metrics.WorkflowClosedCounter)
+}
+
+// getMetricsDomainName returns the name of a domain to tag metrics with, falling back to the domain ID when the name
+// cannot be resolved
+func getMetricsDomainName(shard ShardContext, domainID string) string {
+	if domainEntry, err := shard.GetDomainCache().GetDomainByID(domainID); err == nil && domainEntry.GetInfo() != nil {
+		return domainEntry.GetInfo().Name
+	}
+	return domainID
+}
+
+// syncMatchFirstDecision hands the first decision of a new workflow execution to matching right away, so a waiting
+// poller gets it without a round trip through the transfer queue, and returns whether the hand off succeeded.  The
+// transfer task of the decision only dispatches it when the hand off failed or timed out.
+func (e *historyEngineImpl) syncMatchFirstDecision(domainID string, execution workflow.WorkflowExecution,
+	taskList string, scheduleID int64, scheduleToStartTimeout int32) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), e.shard.GetConfig().SyncMatchFirstDecisionTimeout())
+	defer cancel()
+
+	err := e.matchingClient.AddDecisionTask(ctx, &m.AddDecisionTaskRequest{
+		DomainUUID:                    common.StringPtr(domainID),
+		Execution:                     &execution,
+		TaskList:                      &workflow.TaskList{Name: common.StringPtr(taskList)},
+		ScheduleId:                    common.Int64Ptr(scheduleID),
+		ScheduleToStartTimeoutSeconds: common.Int32Ptr(scheduleToStartTimeout),
+	})
+	if err != nil {
+		e.metricsClient.IncCounter(metrics.HistoryStartWorkflowExecutionScope,
+			metrics.SyncMatchFirstDecisionFailedCounter)
+		e.logger.Debugf("Failed to sync match first decision.  WorkflowID: %v, RunID: %v, Error: %v",
+			execution.GetWorkflowId(), execution.GetRunId(), err)
+		return false
+	}
+	return true
+}
+
+// beginFirstDecisionMatch registers the synchronous hand off of the first decision of an execution about to be
+// created.  It is registered ahead of the create, so the transfer task of the decision can not be processed before it.
+func (e *historyEngineImpl) beginFirstDecisionMatch(domainID string, execution workflow.WorkflowExecution,
+	scheduleID int64) *firstDecisionMatch {
+	match := &firstDecisionMatch{
+		key: firstDecisionKey{
+			domainID:   domainID,
+			workflowID: execution.GetWorkflowId(),
+			runID:      execution.GetRunId(),
+			scheduleID: scheduleID,
+		},
+		done: make(chan struct{}),
+	}
+
+	e.firstDecisionMatchesLock.Lock()
+	defer e.firstDecisionMatchesLock.Unlock()
+	if e.firstDecisionMatches == nil {
+		e.firstDecisionMatches = make(map[firstDecisionKey]*firstDecisionMatch)
+	}
+	e.firstDecisionMatches[match.key] = match
+	return match
 }
 
-func (e *historyEngineImpl) ReplicateEvents(replicateRequest *h.ReplicateEventsRequest) error {
-	return e.replicator.ApplyEvents(replicateRequest)
+// endFirstDecisionMatch publishes the outcome of a hand off.  A failed hand off is forgotten right away, a successful
+// one is kept until the transfer task of the decision consumes it.
+func (e *historyEngineImpl) endFirstDecisionMatch(match *firstDecisionMatch, matched bool) {
+	e.firstDecisionMatchesLock.Lock()
+	defer e.firstDecisionMatchesLock.Unlock()
+	match.matched = matched
+	if !matched {
+		delete(e.firstDecisionMatches, match.key)
+	}
+	close(match.done)
 }
 
-func (e *historyEngineImpl) updateWorkflowExecution(domainID string, execution workflow.WorkflowExecution,
-	createDeletionTask, createDecisionTask bool,
-	action func(builder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error)) (retError error) {
+// consumeFirstDecisionMatch waits for the hand off of the decision of a transfer task, if there is one, and returns
+// whether the decision was handed to a poller already.  The outcome is only kept in memory, after a restart of the
+// shard the decision is dispatched again and the duplicate is dropped once the decision is recorded as started.
+func (e *historyEngineImpl) consumeFirstDecisionMatch(domainID string, execution workflow.WorkflowExecution,
+	scheduleID int64) bool {
+	key := firstDecisionKey{
+		domainID:   domainID,
+		workflowID: execution.GetWorkflowId(),
+		runID:      execution.GetRunId(),
+		scheduleID: scheduleID,
+	}
+	e.firstDecisionMatchesLock.Lock()
+	match, ok := e.firstDecisionMatches[key]
+	e.firstDecisionMatchesLock.Unlock()
+	if !ok {
+		return false
+	}
 
-	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(domainID, execution)
-	if err0 != nil {
-		return err0
+	select {
+	case <-match.done:
+	case <-time.After(e.shard.GetConfig().SyncMatchFirstDecisionTimeout()):
 	}
-	defer func() { release(retError) }()
 
-Update_History_Loop:
-	for attempt := 0; attempt < conditionalRetryCount; attempt++ {
-		msBuilder, err1 := context.loadWorkflowExecution()
-		if err1 != nil {
-			return err1
-		}
-		tBuilder := e.getTimerBuilder(&context.workflowExecution)
+	e.firstDecisionMatchesLock.Lock()
+	defer e.firstDecisionMatchesLock.Unlock()
+	delete(e.firstDecisionMatches, key)
+	return match.matched
+}
 
-		var timerTasks []persistence.Task
-		var err error
-		// conduct caller action
-		if timerTasks, err = action(msBuilder, tBuilder); err != nil {
-			if err == ErrStaleState {
-				// Handler detected that cached workflow mutable could potentially be stale
-				// Reload workflow execution history
-				context.clear()
-				continue Update_History_Loop
-			}
+// checkChildStartAdmission returns a ServiceBusyError when the transfer or timer queue of the shard trails further
+// behind than configured, as starting more child workflows would only add to the backlog.
+func (e *historyEngineImpl) checkChildStartAdmission(domainID string) error {
+	config := e.shard.GetConfig()
+	if config.ChildStartMaxTransferTaskBacklog <= 0 && config.ChildStartMaxTimerAckLevelLag <= 0 {
+		return nil
+	}
 
-			// Returned error back to the caller
-			return err
-		}
+	stats := e.shard.GetStats()
+	transferBackedUp := config.ChildStartMaxTransferTaskBacklog > 0 &&
+		stats.TransferTaskBacklog > config.ChildStartMaxTransferTaskBacklog
+	timerBackedUp := config.ChildStartMaxTimerAckLevelLag > 0 &&
+		stats.TimerAckLevelLag > config.ChildStartMaxTimerAckLevelLag
+	if !transferBackedUp && !timerBackedUp {
+		return nil
+	}
 
-		var transferTasks []persistence.Task
+	domainName := getMetricsDomainName(e.shard, domainID)
+	e.metricsClient.Tagged(map[string]string{metrics.DomainTagName: domainName}).IncCounter(
+		metrics.HistoryRespondDecisionTaskCompletedScope, metrics.ChildStartRejectedCounter)
+	return &workflow.ServiceBusyError{
+		Message: fmt.Sprintf("Shard is overloaded, transfer task backlog: %v, timer ack level lag: %v.",
+			stats.TransferTaskBacklog, stats.TimerAckLevelLag),
+	}
+}
 
-		if createDeletionTask {
-			tranT, timerT, err := e.getDeleteWorkflowTasks(domainID, tBuilder)
-			if err != nil {
-				return err
-			}
-			transferTasks = append(transferTasks, tranT)
-			timerTasks = append(timerTasks, timerT)
-		}
+// checkOpenExecutionLimit returns a ServiceBusyError once the shard holds as many open executions as allowed.  The
+// count is approximate, so the limit is a ceiling to shed load rather than a hard bound.
+func (e *historyEngineImpl) checkOpenExecutionLimit(scope int, domainID string) error {
+	limit := e.shard.GetConfig().MaxOpenExecutionsPerShard()
+	if limit <= 0 {
+		return nil
+	}
 
-		if createDecisionTask {
-			// Create a transfer task to schedule a decision task
-			if !msBuilder.HasPendingDecisionTask() {
-				di := msBuilder.AddDecisionTaskScheduledEvent()
-				transferTasks = append(transferTasks, &persistence.DecisionTask{
-					DomainID:   domainID,
-					TaskList:   di.Tasklist,
-					ScheduleID: di.ScheduleID,
-				})
-				if msBuilder.isStickyTaskListEnabled() {
-					tBuilder := e.getTimerBuilder(&context.workflowExecution)
-					stickyTaskTimeoutTimer := tBuilder.AddScheduleToStartDecisionTimoutTask(di.ScheduleID, di.Attempt,
-						msBuilder.executionInfo.StickyScheduleToStartTimeout)
-					timerTasks = append(timerTasks, stickyTaskTimeoutTimer)
-				}
-			}
-		}
+	openExecutionCount := e.shard.GetStats().OpenExecutionCount
+	if openExecutionCount < int64(limit) {
+		return nil
+	}
 
-		// Generate a transaction ID for appending events to history
-		transactionID, err2 := e.shard.GetNextTransferTaskID()
-		if err2 != nil {
-			return err2
-		}
+	domainName := getMetricsDomainName(e.shard, domainID)
+	e.metricsClient.Tagged(map[string]string{metrics.DomainTagName: domainName}).IncCounter(
+		scope, metrics.OpenExecutionLimitExceededCounter)
+	return &workflow.ServiceBusyError{
+		Message: fmt.Sprintf("Shard has too many open workflow executions: %v.", openExecutionCount),
+	}
+}
 
-		// We apply the update to execution using optimistic concurrency.  If it fails due to a conflict then reload
-		// the history and try the operation again.
-		if err := context.updateWorkflowExecution(transferTasks, timerTasks, transactionID); err != nil {
-			if err == ErrConflict {
-				continue Update_History_Loop
-			}
-			return err
+// checkBufferedSignalLimit returns a ServiceBusyError if the workflow has as many signals buffered behind its in
+// flight decision as allowed, so senders back off until the decision completes and the buffer is flushed
+func (e *historyEngineImpl) checkBufferedSignalLimit(scope int, domainID string, msBuilder *mutableStateBuilder) error {
+	maxBufferedSignals := e.shard.GetConfig().MaxBufferedSignals
+	if maxBufferedSignals <= 0 || !msBuilder.HasInFlightDecisionTask() {
+		return nil
+	}
+
+	bufferedEvents, err := msBuilder.getBufferedEvents()
+	if err != nil {
+		return err
+	}
+	bufferedSignals := 0
+	for _, event := range bufferedEvents {
+		if event.GetEventType() == workflow.EventTypeWorkflowExecutionSignaled {
+			bufferedSignals++
 		}
-		e.timerProcessor.NotifyNewTimers(e.currentClusterName, timerTasks)
+	}
+	if bufferedSignals < maxBufferedSignals {
 		return nil
 	}
-	return ErrMaxAttemptsExceeded
+
+	domainName := getMetricsDomainName(e.shard, domainID)
+	e.metricsClient.Tagged(map[string]string{metrics.DomainTagName: domainName}).IncCounter(scope,
+		metrics.SignalBackpressureCounter)
+	return &workflow.ServiceBusyError{
+		Message: fmt.Sprintf("Workflow has %v signals pending its decision, retry after the decision completes.",
+			bufferedSignals),
+	}
+}
+
+// getDecisionBackoff returns how long dispatch of a decision should be delayed based on the number of consecutive
+// failed attempts before it, zero means the decision is dispatched right away.
+func (e *historyEngineImpl) getDecisionBackoff(attempt int64) time.Duration {
+	if e.decisionBackoffPolicy == nil || attempt <= 0 {
+		return 0
+	}
+
+	interval := e.decisionBackoffPolicy.ComputeNextDelay(0, int(attempt-1))
+	if interval < 0 {
+		return 0
+	}
+	return interval
 }
 
 func (e *historyEngineImpl) getDeleteWorkflowTasks(
 	domainID string,
+	workflowTypeName string,
 	tBuilder *timerBuilder,
 ) (persistence.Task, persistence.Task, error) {
 
@@ -2074,13 +4735,43 @@ func (e *historyEngineImpl) getDeleteWorkflowTasks(
 			return nil, nil, err
 		}
 	} else {
-		retentionInDays = domainEntry.GetConfig().Retention
+		retentionInDays = e.getRetentionInDays(domainID, domainEntry.GetInfo().Name, workflowTypeName,
+			domainEntry.GetConfig().Retention)
 	}
 	cleanupTask := tBuilder.createDeleteHistoryEventTimerTask(time.Duration(retentionInDays) * time.Hour * 24)
 
 	return closeTask, cleanupTask, nil
 }
 
+// getRetentionInDays returns the retention of a closed execution.  A retention configured for the workflow type takes
+// precedence over the retention of the domain, unless it is outside of the bounds configured for the domain.
+func (e *historyEngineImpl) getRetentionInDays(domainID, domainName, workflowTypeName string,
+	domainRetentionInDays int32) int32 {
+	config := e.shard.GetConfig()
+	override := config.WorkflowTypeRetentionDays(dynamicconfig.DomainFilter(domainName),
+		dynamicconfig.WorkflowTypeFilter(workflowTypeName))
+	if override == 0 {
+		return domainRetentionInDays
+	}
+
+	minRetentionInDays := config.MinRetentionDays(dynamicconfig.DomainFilter(domainName))
+	maxRetentionInDays := config.MaxRetentionDays(dynamicconfig.DomainFilter(domainName))
+	if override < minRetentionInDays || override > maxRetentionInDays {
+		e.logger.WithFields(bark.Fields{
+			logging.TagDomainID: domainID,
+		}).Warnf("Ignoring retention of %v days for workflow type %v, it is outside of [%v, %v] days.", override,
+			workflowTypeName, minRetentionInDays, maxRetentionInDays)
+		return domainRetentionInDays
+	}
+	return int32(override)
+}
+
+// getNormalScheduleToStartTimeout returns the schedule to start timeout of decisions retried on the normal task list
+// of a domain, zero when they wait for a worker indefinitely
+func (e *historyEngineImpl) getNormalScheduleToStartTimeout(domainName string) int32 {
+	return int32(e.shard.GetConfig().NormalDecisionScheduleToStartTimeoutInSecs(dynamicconfig.DomainFilter(domainName)))
+}
+
 func (e *historyEngineImpl) createRecordDecisionTaskStartedResponse(domainID string, msBuilder *mutableStateBuilder,
 	di *decisionInfo, identity string) *h.RecordDecisionTaskStartedResponse {
 	response := &h.RecordDecisionTaskStartedResponse{}
@@ -2119,22 +4810,122 @@ func (e *historyEngineImpl) deleteEvents(domainID string, execution workflow.Wor
 	})
 }
 
+// failDecision also returns whether the workflow is to be timed out as the failed decision used up its attempts
 func (e *historyEngineImpl) failDecision(context *workflowExecutionContext, scheduleID, startedID int64,
 	cause workflow.DecisionTaskFailedCause, request *workflow.RespondDecisionTaskCompletedRequest) (*mutableStateBuilder,
-	error) {
+	bool, error) {
 	// Clear any updates we have accumulated so far
 	context.clear()
 
 	// Reload workflow execution so we can apply the decision task failure event
 	msBuilder, err := context.loadWorkflowExecution()
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
+	timeoutWorkflow := msBuilder.shouldTimeoutOnDecisionFailure(msBuilder.executionInfo.DecisionAttempt)
 	msBuilder.AddDecisionTaskFailedEvent(scheduleID, startedID, cause, nil, request.GetIdentity())
 
 	// Return new builder back to the caller for further updates
-	return msBuilder, nil
+	return msBuilder, timeoutWorkflow, nil
+}
+
+// isSelfTargeted tells whether a signal or cancellation targets the workflow execution which requests it.  An empty
+// run id targets the current run of the workflow, which is the requesting run.
+func isSelfTargeted(executionInfo *persistence.WorkflowExecutionInfo, targetDomainID, targetWorkflowID,
+	targetRunID string) bool {
+	return targetDomainID == executionInfo.DomainID && targetWorkflowID == executionInfo.WorkflowID &&
+		(targetRunID == "" || targetRunID == executionInfo.RunID)
+}
+
+// cancelSelf records the cancellation a workflow execution requested of itself as both the request made to the
+// execution and its outcome.  A repeated request is completed without a second request, as is done across executions.
+func cancelSelf(msBuilder *mutableStateBuilder, initiatedID int64, cancelRequestID string) error {
+	executionInfo := msBuilder.executionInfo
+	execution := &workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(executionInfo.WorkflowID),
+		RunId:      common.StringPtr(executionInfo.RunID),
+	}
+	if isCancelRequested, _ := msBuilder.isCancelRequested(); !isCancelRequested {
+		if msBuilder.AddWorkflowExecutionCancelRequestedEvent("", &h.RequestCancelWorkflowExecutionRequest{
+			DomainUUID: common.StringPtr(executionInfo.DomainID),
+			CancelRequest: &workflow.RequestCancelWorkflowExecutionRequest{
+				Domain:            common.StringPtr(executionInfo.DomainID),
+				WorkflowExecution: execution,
+				Identity:          common.StringPtr(identityHistoryService),
+				RequestId:         common.StringPtr(cancelRequestID),
+			},
+			ExternalInitiatedEventId:  common.Int64Ptr(initiatedID),
+			ExternalWorkflowExecution: execution,
+		}) == nil {
+			return &workflow.InternalServiceError{Message: "Unable to cancel workflow execution."}
+		}
+	}
+
+	if msBuilder.AddExternalWorkflowExecutionCancelRequested(initiatedID, executionInfo.DomainID,
+		executionInfo.WorkflowID, executionInfo.RunID) == nil {
+		return &workflow.InternalServiceError{Message: "Unable to add external cancel requested event."}
+	}
+	return nil
+}
+
+// signalSelf delivers the signal a workflow execution sent to itself and records it as sent
+func signalSelf(msBuilder *mutableStateBuilder, initiatedID int64, signalRequestID string,
+	attributes *workflow.SignalExternalWorkflowExecutionDecisionAttributes) error {
+	executionInfo := msBuilder.executionInfo
+	if msBuilder.AddWorkflowExecutionSignaled(&workflow.SignalWorkflowExecutionRequest{
+		Domain: common.StringPtr(executionInfo.DomainID),
+		WorkflowExecution: &workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(executionInfo.WorkflowID),
+			RunId:      common.StringPtr(executionInfo.RunID),
+		},
+		SignalName: attributes.SignalName,
+		Input:      attributes.Input,
+		Identity:   common.StringPtr(identityHistoryService),
+		RequestId:  common.StringPtr(signalRequestID),
+		Control:    attributes.Control,
+	}) == nil {
+		return &workflow.InternalServiceError{Message: "Unable to signal workflow execution."}
+	}
+
+	if msBuilder.AddExternalWorkflowExecutionSignaled(initiatedID, executionInfo.DomainID, executionInfo.WorkflowID,
+		executionInfo.RunID, attributes.Control) == nil {
+		return &workflow.InternalServiceError{Message: "Unable to add external signaled event."}
+	}
+	return nil
+}
+
+// getPendingChildInitiatedEvent returns the initiated event of a child execution with the given target domain and
+// workflow id which has not been started yet, or nil if there is no such child.
+func (e *historyEngineImpl) getPendingChildInitiatedEvent(msBuilder *mutableStateBuilder, targetDomainID,
+	workflowID string) *workflow.HistoryEvent {
+	for initiatedID, ci := range msBuilder.pendingChildExecutionInfoIDs {
+		if ci.StartedID != emptyEventID {
+			continue
+		}
+		initiatedEvent, ok := msBuilder.GetChildExecutionInitiatedEvent(initiatedID)
+		if !ok {
+			continue
+		}
+		attributes := initiatedEvent.StartChildWorkflowExecutionInitiatedEventAttributes
+		if attributes.GetWorkflowId() != workflowID {
+			continue
+		}
+
+		childDomainID := msBuilder.executionInfo.DomainID
+		if attributes.Domain != nil {
+			domainEntry, err := e.shard.GetDomainCache().GetDomain(attributes.GetDomain())
+			if err != nil {
+				continue
+			}
+			childDomainID = domainEntry.GetInfo().ID
+		}
+		if childDomainID == targetDomainID {
+			return initiatedEvent
+		}
+	}
+
+	return nil
 }
 
 func (e *historyEngineImpl) getTimerBuilder(we *workflow.WorkflowExecution) *timerBuilder {
@@ -2142,7 +4933,7 @@ func (e *historyEngineImpl) getTimerBuilder(we *workflow.WorkflowExecution) *tim
 		logging.TagWorkflowExecutionID: we.WorkflowId,
 		logging.TagWorkflowRunID:       we.RunId,
 	})
-	return newTimerBuilder(e.shard.GetConfig(), lg, common.NewRealTimeSource())
+	return newTimerBuilder(e.shard.GetConfig(), lg, e.shard.GetTimeSource())
 }
 
 func (s *shardContextWrapper) UpdateWorkflowExecution(request *persistence.UpdateWorkflowExecutionRequest) error {
@@ -2178,7 +4969,8 @@ func (s *shardContextWrapper) NotifyNewHistoryEvent(event *historyEventNotificat
 	return err
 }
 
-func validateActivityScheduleAttributes(attributes *workflow.ScheduleActivityTaskDecisionAttributes) error {
+func validateActivityScheduleAttributes(attributes *workflow.ScheduleActivityTaskDecisionAttributes,
+	maxTaskListNameLength int, maxInputSize int) error {
 	if attributes == nil {
 		return &workflow.BadRequestError{Message: "ScheduleActivityTaskDecisionAttributes is not set on decision."}
 	}
@@ -2186,6 +4978,9 @@ func validateActivityScheduleAttributes(attributes *workflow.ScheduleActivityTas
 	if attributes.TaskList == nil || attributes.TaskList.Name == nil || *attributes.TaskList.Name == "" {
 		return &workflow.BadRequestError{Message: "TaskList is not set on decision."}
 	}
+	if err := validateName("TaskList", attributes.TaskList.GetName(), maxTaskListNameLength); err != nil {
+		return err
+	}
 
 	if attributes.ActivityId == nil || *attributes.ActivityId == "" {
 		return &workflow.BadRequestError{Message: "ActivityId is not set on decision."}
@@ -2207,11 +5002,35 @@ func validateActivityScheduleAttributes(attributes *workflow.ScheduleActivityTas
 	if attributes.HeartbeatTimeoutSeconds == nil || *attributes.HeartbeatTimeoutSeconds < 0 {
 		return &workflow.BadRequestError{Message: "Ac valid HeartbeatTimeoutSeconds is not set on decision."}
 	}
+	if attributes.GetPriority() < 0 {
+		return &workflow.BadRequestError{Message: "Priority cannot be negative."}
+	}
+	if attributes.DispatchDeadlineSeconds != nil && (attributes.GetDispatchDeadlineSeconds() <= 0 ||
+		attributes.GetDispatchDeadlineSeconds() > attributes.GetScheduleToStartTimeoutSeconds()) {
+		return &workflow.BadRequestError{
+			Message: "A valid DispatchDeadlineSeconds must be positive and not exceed ScheduleToStartTimeoutSeconds."}
+	}
+	if err := validateScheduleInputSize("Activity", attributes.Input, maxInputSize); err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func validateTimerScheduleAttributes(attributes *workflow.StartTimerDecisionAttributes) error {
+// validateScheduleInputSize keeps workers from embedding large payloads into the input of what they schedule, as the
+// input is copied into the history and into the task handed to matching
+func validateScheduleInputSize(kind string, input []byte, maxInputSize int) error {
+	if maxInputSize > 0 && len(input) > maxInputSize {
+		return &workflow.BadRequestError{
+			Message: fmt.Sprintf("%v input size of %v bytes exceeds the limit of %v bytes.", kind, len(input),
+				maxInputSize),
+		}
+	}
+	return nil
+}
+
+func validateTimerScheduleAttributes(attributes *workflow.StartTimerDecisionAttributes,
+	maxStartToFireTimeout time.Duration, now time.Time) error {
 	if attributes == nil {
 		return &workflow.BadRequestError{Message: "StartTimerDecisionAttributes is not set on decision."}
 	}
@@ -2221,9 +5040,32 @@ func validateTimerScheduleAttributes(attributes *workflow.StartTimerDecisionAttr
 	if attributes.StartToFireTimeoutSeconds == nil || *attributes.StartToFireTimeoutSeconds <= 0 {
 		return &workflow.BadRequestError{Message: "A valid StartToFireTimeoutSeconds is not set on decision."}
 	}
+	startToFireTimeout := attributes.GetStartToFireTimeoutSeconds()
+	if startToFireTimeout > maxTimerStartToFireTimeoutSeconds(now) {
+		return &workflow.BadRequestError{Message: fmt.Sprintf(
+			"StartToFireTimeoutSeconds %v overflows the expiry time of the timer.", startToFireTimeout)}
+	}
+	if maxStartToFireTimeout > 0 && time.Duration(startToFireTimeout)*time.Second > maxStartToFireTimeout {
+		return &workflow.BadRequestError{Message: fmt.Sprintf(
+			"StartToFireTimeoutSeconds %v exceeds the limit of %v seconds.", startToFireTimeout,
+			int64(maxStartToFireTimeout/time.Second))}
+	}
 	return nil
 }
 
+// validateActivityFailureType checks the optional classification of an activity failure reported by a worker
+func validateActivityFailureType(failureType *workflow.ActivityFailureType) error {
+	if failureType == nil {
+		return nil
+	}
+	switch *failureType {
+	case workflow.ActivityFailureTypeRetryable, workflow.ActivityFailureTypeTerminal:
+		return nil
+	default:
+		return &workflow.BadRequestError{Message: fmt.Sprintf("Unknown FailureType %v.", int32(*failureType))}
+	}
+}
+
 func validateActivityCancelAttributes(attributes *workflow.RequestCancelActivityTaskDecisionAttributes) error {
 	if attributes == nil {
 		return &workflow.BadRequestError{Message: "RequestCancelActivityTaskDecisionAttributes is not set on decision."}
@@ -2244,13 +5086,19 @@ func validateTimerCancelAttributes(attributes *workflow.CancelTimerDecisionAttri
 	return nil
 }
 
-func validateRecordMarkerAttributes(attributes *workflow.RecordMarkerDecisionAttributes) error {
+func validateRecordMarkerAttributes(attributes *workflow.RecordMarkerDecisionAttributes, maxDetailsSize int) error {
 	if attributes == nil {
 		return &workflow.BadRequestError{Message: "RecordMarkerDecisionAttributes is not set on decision."}
 	}
 	if attributes.MarkerName == nil || *attributes.MarkerName == "" {
 		return &workflow.BadRequestError{Message: "MarkerName is not set on decision."}
 	}
+	if maxDetailsSize > 0 && len(attributes.Details) > maxDetailsSize {
+		return &workflow.BadRequestError{
+			Message: fmt.Sprintf("Marker details size of %v bytes exceeds the limit of %v bytes.",
+				len(attributes.Details), maxDetailsSize),
+		}
+	}
 	return nil
 }
 
@@ -2293,7 +5141,8 @@ func validateCancelExternalWorkflowExecutionAttributes(attributes *workflow.Requ
 	return nil
 }
 
-func validateSignalExternalWorkflowExecutionAttributes(attributes *workflow.SignalExternalWorkflowExecutionDecisionAttributes) error {
+func validateSignalExternalWorkflowExecutionAttributes(attributes *workflow.SignalExternalWorkflowExecutionDecisionAttributes,
+	maxInputSize int) error {
 	if attributes == nil {
 		return &workflow.BadRequestError{Message: "SignalExternalWorkflowExecutionDecisionAttributes is not set on decision."}
 	}
@@ -2314,6 +5163,17 @@ func validateSignalExternalWorkflowExecutionAttributes(attributes *workflow.Sign
 		return &workflow.BadRequestError{Message: "Input is not set on decision."}
 	}
 
+	return validateSignalInputSize(attributes.Input, maxInputSize)
+}
+
+// validateSignalInputSize checks the input of a signal against the configured limit, a zero limit is not enforced
+func validateSignalInputSize(input []byte, maxInputSize int) error {
+	if maxInputSize > 0 && len(input) > maxInputSize {
+		return &workflow.BadRequestError{
+			Message: fmt.Sprintf("Signal input size of %v bytes exceeds the limit of %v bytes.", len(input), maxInputSize),
+		}
+	}
+
 	return nil
 }
 
@@ -2347,7 +5207,7 @@ func validateContinueAsNewWorkflowExecutionAttributes(executionInfo *persistence
 }
 
 func validateStartChildExecutionAttributes(parentInfo *persistence.WorkflowExecutionInfo,
-	attributes *workflow.StartChildWorkflowExecutionDecisionAttributes) error {
+	attributes *workflow.StartChildWorkflowExecutionDecisionAttributes, config *Config, now time.Time) error {
 	if attributes == nil {
 		return &workflow.BadRequestError{Message: "StartChildWorkflowExecutionDecisionAttributes is not set on decision."}
 	}
@@ -2359,20 +5219,38 @@ func validateStartChildExecutionAttributes(parentInfo *persistence.WorkflowExecu
 	if attributes.WorkflowType == nil || attributes.WorkflowType.GetName() == "" {
 		return &workflow.BadRequestError{Message: "Required field WorkflowType is not set on decision."}
 	}
+	if err := validateName("WorkflowType", attributes.WorkflowType.GetName(),
+		config.MaxWorkflowTypeNameLength); err != nil {
+		return err
+	}
 
 	if attributes.ChildPolicy == nil {
 		return &workflow.BadRequestError{Message: "Required field ChildPolicy is not set on decision."}
 	}
 
+	if err := validateScheduleInputSize("Child workflow", attributes.Input, config.MaxScheduleInputSize()); err != nil {
+		return err
+	}
+
 	// Inherit tasklist from parent workflow execution if not provided on decision
 	if attributes.TaskList == nil || attributes.TaskList.GetName() == "" {
 		attributes.TaskList = &workflow.TaskList{Name: common.StringPtr(parentInfo.TaskList)}
+	} else if err := validateName("TaskList", attributes.TaskList.GetName(),
+		config.MaxTaskListNameLength); err != nil {
+		return err
 	}
 
 	// Inherit workflow timeout from parent workflow execution if not provided on decision
-	if attributes.GetExecutionStartToCloseTimeoutSeconds() <= 0 {
+	inheritedTimeout := attributes.GetExecutionStartToCloseTimeoutSeconds() <= 0
+	if inheritedTimeout {
 		attributes.ExecutionStartToCloseTimeoutSeconds = common.Int32Ptr(parentInfo.WorkflowTimeout)
 	}
+	if config.LimitChildTimeoutToParent {
+		if err := limitChildTimeoutToParent(parentInfo, attributes, inheritedTimeout, config.ChildTimeoutExcessRatio,
+			now); err != nil {
+			return err
+		}
+	}
 
 	// Inherit decision task timeout from parent workflow execution if not provided on decision
 	if attributes.GetTaskStartToCloseTimeoutSeconds() <= 0 {
@@ -2382,7 +5260,78 @@ func validateStartChildExecutionAttributes(parentInfo *persistence.WorkflowExecu
 	return nil
 }
 
-func validateStartWorkflowExecutionRequest(request *workflow.StartWorkflowExecutionRequest) error {
+// limitChildTimeoutToParent clamps the execution timeout of a child to the time remaining before its parent times out,
+// as the parent cannot wait on the child any longer than that.  A timeout inherited from the parent is always clamped,
+// while one requested on the decision is rejected when it exceeds the remaining time more than excessRatio times.
+func limitChildTimeoutToParent(parentInfo *persistence.WorkflowExecutionInfo,
+	attributes *workflow.StartChildWorkflowExecutionDecisionAttributes, inheritedTimeout bool, excessRatio float64,
+	now time.Time) error {
+	if parentInfo.WorkflowTimeout <= 0 || parentInfo.StartTimestamp.IsZero() {
+		return nil
+	}
+
+	deadline := parentInfo.StartTimestamp.Add(time.Duration(parentInfo.WorkflowTimeout) * time.Second)
+	remaining := int32(deadline.Sub(now) / time.Second)
+	if remaining < 1 {
+		remaining = 1
+	}
+
+	timeout := attributes.GetExecutionStartToCloseTimeoutSeconds()
+	if timeout <= remaining {
+		return nil
+	}
+	if !inheritedTimeout && float64(timeout) > float64(remaining)*excessRatio {
+		return &workflow.BadRequestError{
+			Message: fmt.Sprintf("ExecutionStartToCloseTimeoutSeconds of %v exceeds the %v seconds remaining for "+
+				"the parent workflow execution.", timeout, remaining),
+		}
+	}
+	attributes.ExecutionStartToCloseTimeoutSeconds = common.Int32Ptr(remaining)
+	return nil
+}
+
+// validateExpectedRunID makes sure the loaded run is the one the caller expects to act on, so that a caller racing
+// with continue-as-new does not end up applying its request to the wrong run.
+func validateExpectedRunID(msBuilder *mutableStateBuilder, expectedRunID string) error {
+	if expectedRunID != "" && expectedRunID != msBuilder.executionInfo.RunID {
+		return &workflow.EntityNotExistsError{
+			Message: fmt.Sprintf("Workflow execution run id %v does not match expected run id %v.",
+				msBuilder.executionInfo.RunID, expectedRunID),
+		}
+	}
+	return nil
+}
+
+// routeStartRequest returns the start request of a new workflow execution with the task list picked by the configured
+// StartTaskListRouter, or the request as is when no router is configured.  The routed task list is validated the same
+// way as the one given by the caller, as it is used for the decisions and activities of the execution alike.
+func (e *historyEngineImpl) routeStartRequest(domainID string, startRequest *h.StartWorkflowExecutionRequest) (
+	*h.StartWorkflowExecutionRequest, error) {
+	config := e.shard.GetConfig()
+	router := config.StartTaskListRouter
+	if router == nil {
+		return startRequest, nil
+	}
+
+	taskList := router(domainID, startRequest.StartRequest)
+	if taskList == "" {
+		return nil, &workflow.BadRequestError{Message: "Routed task list is empty."}
+	}
+	if err := validateName("TaskList", taskList, config.MaxTaskListNameLength); err != nil {
+		return nil, err
+	}
+
+	request := *startRequest.StartRequest
+	request.TaskList = &workflow.TaskList{
+		Name: common.StringPtr(taskList),
+		Kind: startRequest.StartRequest.TaskList.Kind,
+	}
+	routedRequest := *startRequest
+	routedRequest.StartRequest = &request
+	return &routedRequest, nil
+}
+
+func validateStartWorkflowExecutionRequest(request *workflow.StartWorkflowExecutionRequest, config *Config) error {
 	if request.ExecutionStartToCloseTimeoutSeconds == nil || request.GetExecutionStartToCloseTimeoutSeconds() <= 0 {
 		return &workflow.BadRequestError{Message: "Missing or invalid ExecutionStartToCloseTimeoutSeconds."}
 	}
@@ -2392,9 +5341,80 @@ func validateStartWorkflowExecutionRequest(request *workflow.StartWorkflowExecut
 	if request.TaskList == nil || request.TaskList.Name == nil || request.TaskList.GetName() == "" {
 		return &workflow.BadRequestError{Message: "Missing Tasklist."}
 	}
+	if err := validateName("WorkflowType", request.WorkflowType.GetName(), config.MaxWorkflowTypeNameLength); err != nil {
+		return err
+	}
+	return validateName("TaskList", request.TaskList.GetName(), config.MaxTaskListNameLength)
+}
+
+// validateName makes sure a workflow type or task list name given by a caller fits in maxLength bytes and is made of
+// printable characters only, as those names end up in indexes and logs.  A zero maxLength disables the length check.
+func validateName(field, name string, maxLength int) error {
+	if maxLength > 0 && len(name) > maxLength {
+		return &workflow.BadRequestError{
+			Message: fmt.Sprintf("%v name length of %v bytes exceeds the limit of %v bytes.", field, len(name), maxLength),
+		}
+	}
+	if !utf8.ValidString(name) {
+		return &workflow.BadRequestError{Message: fmt.Sprintf("%v name is not valid UTF-8.", field)}
+	}
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return &workflow.BadRequestError{
+				Message: fmt.Sprintf("%v name contains the non printable character %q.", field, r),
+			}
+		}
+	}
 	return nil
 }
 
+func validateStickyTaskListName(name string, maxLength int) error {
+	if strings.TrimSpace(name) == "" {
+		return &workflow.BadRequestError{Message: "StickyTaskList name is not set."}
+	}
+	return validateName("StickyTaskList", name, maxLength)
+}
+
+// isClosedWithinInterval returns true if the closed run of a workflow closed less than the interval ago.  The last
+// update of a closed run is its close, and a run whose mutable state is already gone closed long enough ago.
+func (e *historyEngineImpl) isClosedWithinInterval(domainID, workflowID, runID string,
+	interval time.Duration) (bool, error) {
+	response, err := e.executionManager.GetWorkflowExecution(&persistence.GetWorkflowExecutionRequest{
+		DomainID: domainID,
+		Execution: workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(workflowID),
+			RunId:      common.StringPtr(runID),
+		},
+	})
+	if err != nil {
+		if _, ok := err.(*workflow.EntityNotExistsError); ok {
+			return false, nil
+		}
+		return false, err
+	}
+
+	closeTime := response.State.ExecutionInfo.LastUpdatedTimestamp
+	return e.shard.GetTimeSource().Now().Sub(closeTime) < interval, nil
+}
+
+// isRetriedActivityStart returns true if the activity was started a moment ago by the same poller.  Matching retries
+// a start whose response got lost with a new request id, so such a start is most likely a retry rather than a
+// conflicting start by another poller.
+func (e *historyEngineImpl) isRetriedActivityStart(ai *persistence.ActivityInfo, identity string) bool {
+	window := e.shard.GetConfig().ActivityStartedRetryWindow
+	return window > 0 && identity != "" && identity == ai.StartedIdentity &&
+		e.shard.GetTimeSource().Now().Sub(ai.StartedTime) <= window
+}
+
+// isRetriedStartRequest returns true if the run currently owning the workflow id was created by the same start
+// request, i.e. the caller is retrying a start which already went through. Such a retry resolves to the existing run
+// whether or not that run has completed, so WorkflowIdReusePolicy is only applied to starts with a different request
+// id. Starts without a request id are never treated as retries.
+func isRetriedStartRequest(request *workflow.StartWorkflowExecutionRequest,
+	err *persistence.WorkflowExecutionAlreadyStartedError) bool {
+	return request.GetRequestId() != "" && request.GetRequestId() == err.StartRequestID
+}
+
 func getDomainUUID(domainUUID *string) (string, error) {
 	if domainUUID == nil {
 		return "", &workflow.BadRequestError{Message: "Missing domain UUID."}
@@ -2402,6 +5422,16 @@ func getDomainUUID(domainUUID *string) (string, error) {
 	return *domainUUID, nil
 }
 
+func getPendingActivityState(ai *persistence.ActivityInfo) workflow.PendingActivityState {
+	if ai.CancelRequested {
+		return workflow.PendingActivityStateCancelRequested
+	}
+	if ai.StartedID != emptyEventID {
+		return workflow.PendingActivityStateStarted
+	}
+	return workflow.PendingActivityStateScheduled
+}
+
 func getScheduleID(activityID string, msBuilder *mutableStateBuilder) (int64, error) {
 	if activityID == "" {
 		return 0, &workflow.BadRequestError{Message: "Neither ActivityID nor ScheduleID is provided"}
@@ -2413,19 +5443,85 @@ func getScheduleID(activityID string, msBuilder *mutableStateBuilder) (int64, er
 	return scheduleID, nil
 }
 
-func getSignalRequest(request *workflow.SignalWithStartWorkflowExecutionRequest) *workflow.SignalWorkflowExecutionRequest {
-	req := &workflow.SignalWorkflowExecutionRequest{
-		Domain: request.Domain,
-		WorkflowExecution: &workflow.WorkflowExecution{
-			WorkflowId: request.WorkflowId,
-		},
-		SignalName: request.SignalName,
-		Input:      request.SignalInput,
-		RequestId:  request.RequestId,
-		Identity:   request.Identity,
-		Control:    request.Control,
+// getSignalRequests returns the signals delivered by a SignalWithStartWorkflowExecution request, the single signal set
+// on the request followed by the batched ones.  Only batched signals carry a request id to deduplicate them, the
+// request id of the request itself belongs to the start.
+func getSignalRequests(request *workflow.SignalWithStartWorkflowExecutionRequest) []*workflow.SignalWorkflowExecutionRequest {
+	execution := &workflow.WorkflowExecution{
+		WorkflowId: request.WorkflowId,
+	}
+	var requests []*workflow.SignalWorkflowExecutionRequest
+	if request.SignalName != nil || len(request.Signals) == 0 {
+		requests = append(requests, &workflow.SignalWorkflowExecutionRequest{
+			Domain:            request.Domain,
+			WorkflowExecution: execution,
+			SignalName:        request.SignalName,
+			Input:             request.SignalInput,
+			Identity:          request.Identity,
+			Control:           request.Control,
+		})
+	}
+	for _, signal := range request.Signals {
+		requests = append(requests, &workflow.SignalWorkflowExecutionRequest{
+			Domain:            request.Domain,
+			WorkflowExecution: execution,
+			SignalName:        signal.SignalName,
+			Input:             signal.Input,
+			RequestId:         signal.RequestId,
+			Identity:          request.Identity,
+			Control:           signal.Control,
+		})
+	}
+	return requests
+}
+
+func validateSignalWithStartSignals(requests []*workflow.SignalWorkflowExecutionRequest, config *Config) error {
+	if config.MaxSignalWithStartSignals > 0 && len(requests) > config.MaxSignalWithStartSignals {
+		return &workflow.BadRequestError{
+			Message: fmt.Sprintf("Number of signals %v exceeds the limit of %v.", len(requests),
+				config.MaxSignalWithStartSignals),
+		}
+	}
+
+	totalSize := 0
+	for _, request := range requests {
+		if err := validateSignalInputSize(request.Input, config.MaxSignalInputSize); err != nil {
+			return err
+		}
+		if requestID := request.GetRequestId(); requestID != "" && uuid.Parse(requestID) == nil {
+			return &workflow.BadRequestError{Message: fmt.Sprintf("Signal request id %v is not a valid UUID.", requestID)}
+		}
+		totalSize += len(request.Input)
+	}
+	if config.MaxSignalWithStartInputSize > 0 && totalSize > config.MaxSignalWithStartInputSize {
+		return &workflow.BadRequestError{
+			Message: fmt.Sprintf("Total signal input size of %v bytes exceeds the limit of %v bytes.", totalSize,
+				config.MaxSignalWithStartInputSize),
+		}
+	}
+
+	return nil
+}
+
+// addWorkflowExecutionSignals records the signals on the mutable state in order, signals whose request id was seen
+// before are skipped.  It returns whether any signal was recorded.
+func addWorkflowExecutionSignals(msBuilder *mutableStateBuilder,
+	requests []*workflow.SignalWorkflowExecutionRequest) (bool, error) {
+	signaled := false
+	for _, request := range requests {
+		if requestID := request.GetRequestId(); requestID != "" {
+			if msBuilder.isSignalRequested(requestID) {
+				continue
+			}
+			msBuilder.addSignalRequested(requestID)
+		}
+
+		if msBuilder.AddWorkflowExecutionSignaled(request) == nil {
+			return false, &workflow.InternalServiceError{Message: "Unable to signal workflow execution."}
+		}
+		signaled = true
 	}
-	return req
+	return signaled, nil
 }
 
 func getStartRequest(domainID string,
@@ -2442,6 +5538,7 @@ func getStartRequest(domainID string,
 		Identity:                            request.Identity,
 		RequestId:                           request.RequestId,
 		WorkflowIdReusePolicy:               &policy,
+		KeyId:                               request.KeyId,
 	}
 
 	startRequest := &h.StartWorkflowExecutionRequest{
@@ -24,8 +24,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pborman/uuid"
 
@@ -37,6 +40,7 @@ import (
 
 	"github.com/uber-go/tally"
 	h "github.com/uber/cadence/.gen/go/history"
+	m "github.com/uber/cadence/.gen/go/matching"
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/cache"
@@ -146,6 +150,7 @@ func (s *engine2Suite) SetupTest() {
 		metricsClient:      metrics.NewClient(tally.NoopScope, metrics.History),
 		tokenSerializer:    common.NewJSONTaskTokenSerializer(),
 		hSerializerFactory: persistence.NewHistorySerializerFactory(),
+		matchingClient:     s.mockMatchingClient,
 	}
 	h.txProcessor = newTransferQueueProcessor(mockShard, h, s.mockVisibilityMgr, s.mockMatchingClient, s.mockHistoryClient, s.logger)
 	h.timerProcessor = newTimerQueueProcessor(mockShard, h, s.logger)
@@ -532,6 +537,9 @@ func (s *engine2Suite) TestRecordActivityTaskStartedIfNoExecution() {
 }
 
 func (s *engine2Suite) TestRecordActivityTaskStartedSuccess() {
+	scope := tally.NewTestScope("test", nil)
+	s.historyEngine.metricsClient = metrics.NewClient(scope, metrics.History)
+
 	workflowExecution := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
 		RunId:      common.StringPtr(validRunID),
@@ -553,6 +561,10 @@ func (s *engine2Suite) TestRecordActivityTaskStartedSuccess() {
 	gwmsResponse1 := &persistence.GetWorkflowExecutionResponse{State: ms1}
 
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse1, nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: "domainId", Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
@@ -574,6 +586,118 @@ func (s *engine2Suite) TestRecordActivityTaskStartedSuccess() {
 	s.Equal(scheduledEvent, response.ScheduledEvent)
 	s.Equal(*scheduledEvent.EventId+1, *response.StartedEvent.EventId)
 	s.Equal("reqId", *response.StartedEvent.ActivityTaskStartedEventAttributes.RequestId)
+
+	executionBuilder := s.getBuilder("domainId", workflowExecution)
+	ai, ok := executionBuilder.GetActivityInfo(*scheduledEvent.EventId)
+	s.True(ok)
+	s.Equal(identity, ai.StartedIdentity)
+
+	timer := scope.Snapshot().Timers()["test.activity-schedule-to-start-latency+domain=domainName,"+
+		"operation=RecordActivityTaskStarted,tasklist=testTaskList"]
+	s.NotNil(timer)
+	s.Equal(1, len(timer.Values()))
+}
+
+func (s *engine2Suite) TestRecordActivityTaskStartedStaleState() {
+	scope := tally.NewTestScope("test", nil)
+	s.historyEngine.metricsClient = metrics.NewClient(scope, metrics.History)
+
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+
+	identity := "testIdentity"
+	tl := "testTaskList"
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, true)
+	// cached state which does not know about the activity yet
+	staleResponse := &persistence.GetWorkflowExecutionResponse{State: createMutableState(msBuilder)}
+
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, int64(2), int64(3), nil, identity)
+	scheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId, "activity1_id",
+		"activity_type1", tl, []byte("input1"), 100, 10, 5)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: createMutableState(msBuilder)}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(staleResponse, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: "domainId", Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	response, err := s.historyEngine.RecordActivityTaskStarted(&h.RecordActivityTaskStartedRequest{
+		DomainUUID:        common.StringPtr("domainId"),
+		WorkflowExecution: &workflowExecution,
+		ScheduleId:        scheduledEvent.EventId,
+		TaskId:            common.Int64Ptr(100),
+		RequestId:         common.StringPtr("reqId"),
+		PollRequest: &workflow.PollForActivityTaskRequest{
+			TaskList: &workflow.TaskList{
+				Name: common.StringPtr(tl),
+			},
+			Identity: common.StringPtr(identity),
+		},
+	})
+	s.Nil(err)
+	s.Equal(scheduledEvent, response.ScheduledEvent)
+
+	counter := scope.Snapshot().Counters()["test.stale-mutable-state-per-domain+domain=domainName,operation=RecordActivityTaskStarted"]
+	s.NotNil(counter)
+	s.Equal(int64(1), counter.Value())
+}
+
+func (s *engine2Suite) TestRecordActivityTaskStartedRetried() {
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+
+	identity := "testIdentity"
+	tl := "testTaskList"
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, true)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, int64(2), int64(3), nil, identity)
+	scheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId, "activity1_id",
+		"activity_type1", tl, []byte("input1"), 100, 10, 5)
+	startedEvent := addActivityTaskStartedEvent(msBuilder, *scheduledEvent.EventId, tl, identity)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: createMutableState(msBuilder)}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Times(2)
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	startRequest := func(requestID, identity string) *h.RecordActivityTaskStartedRequest {
+		return &h.RecordActivityTaskStartedRequest{
+			DomainUUID:        common.StringPtr("domainId"),
+			WorkflowExecution: &workflowExecution,
+			ScheduleId:        scheduledEvent.EventId,
+			TaskId:            common.Int64Ptr(100),
+			RequestId:         common.StringPtr(requestID),
+			PollRequest: &workflow.PollForActivityTaskRequest{
+				TaskList: &workflow.TaskList{Name: common.StringPtr(tl)},
+				Identity: common.StringPtr(identity),
+			},
+		}
+	}
+
+	// matching retrying the start on behalf of the same poller gets the existing started event
+	response, err := s.historyEngine.RecordActivityTaskStarted(startRequest("retriedReqId", identity))
+	s.Nil(err)
+	s.Equal(scheduledEvent, response.ScheduledEvent)
+	s.Equal(startedEvent.GetEventId(), response.StartedEvent.GetEventId())
+
+	// a start by another poller is a genuine conflict
+	_, err = s.historyEngine.RecordActivityTaskStarted(startRequest("otherReqId", "otherIdentity"))
+	s.IsType(&h.EventAlreadyStartedError{}, err)
+
+	// so is a start by the same poller once the retry window is disabled
+	activityStartedRetryWindow := s.config.ActivityStartedRetryWindow
+	defer func() { s.config.ActivityStartedRetryWindow = activityStartedRetryWindow }()
+	s.config.ActivityStartedRetryWindow = 0
+	_, err = s.historyEngine.RecordActivityTaskStarted(startRequest("retriedReqId", identity))
+	s.IsType(&h.EventAlreadyStartedError{}, err)
 }
 
 func (s *engine2Suite) TestRequestCancelWorkflowExecutionSuccess() {
@@ -594,7 +718,7 @@ func (s *engine2Suite) TestRequestCancelWorkflowExecutionSuccess() {
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.historyEngine.RequestCancelWorkflowExecution(&h.RequestCancelWorkflowExecutionRequest{
+	resp, err := s.historyEngine.RequestCancelWorkflowExecution(&h.RequestCancelWorkflowExecutionRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CancelRequest: &workflow.RequestCancelWorkflowExecutionRequest{
 			WorkflowExecution: &workflow.WorkflowExecution{
@@ -605,6 +729,65 @@ func (s *engine2Suite) TestRequestCancelWorkflowExecutionSuccess() {
 		},
 	})
 	s.Nil(err)
+	s.Equal(int64(3), resp.GetCancelRequestedEventId())
+
+	executionBuilder := s.getBuilder(domainID, workflowExecution)
+	s.Equal(int64(4), executionBuilder.GetNextEventID())
+}
+
+func (s *engine2Suite) TestRequestCancelWorkflowExecutionAlreadyRequested() {
+	domainID := "domainId"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+
+	identity := "testIdentity"
+	tl := "testTaskList"
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, false)
+	ms1 := createMutableState(msBuilder)
+	gwmsResponse1 := &persistence.GetWorkflowExecutionResponse{State: ms1}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse1, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Times(3)
+
+	requestCancel := func(requestID string, idempotent bool) (*workflow.RequestCancelWorkflowExecutionResponse, error) {
+		return s.historyEngine.RequestCancelWorkflowExecution(&h.RequestCancelWorkflowExecutionRequest{
+			DomainUUID: common.StringPtr(domainID),
+			CancelRequest: &workflow.RequestCancelWorkflowExecutionRequest{
+				WorkflowExecution: &workflow.WorkflowExecution{
+					WorkflowId: workflowExecution.WorkflowId,
+					RunId:      workflowExecution.RunId,
+				},
+				Identity:   common.StringPtr("identity"),
+				RequestId:  common.StringPtr(requestID),
+				Idempotent: common.BoolPtr(idempotent),
+			},
+		})
+	}
+
+	resp, err := requestCancel("requestID", false)
+	s.Nil(err)
+	s.Equal("requestID", resp.GetCancelRequestId())
+	s.Equal(int64(3), resp.GetCancelRequestedEventId())
+
+	// a retry with the same request id succeeds
+	resp, err = requestCancel("requestID", false)
+	s.Nil(err)
+	s.Equal("requestID", resp.GetCancelRequestId())
+	s.Equal(int64(3), resp.GetCancelRequestedEventId())
+
+	// another request id succeeds with the pending cancel request in the idempotent mode
+	resp, err = requestCancel("otherRequestID", true)
+	s.Nil(err)
+	s.Equal("requestID", resp.GetCancelRequestId())
+	s.Equal(int64(3), resp.GetCancelRequestedEventId())
+
+	// and fails in the strict one
+	_, err = requestCancel("otherRequestID", false)
+	s.IsType(&workflow.CancellationAlreadyRequestedError{}, err)
 
 	executionBuilder := s.getBuilder(domainID, workflowExecution)
 	s.Equal(int64(4), executionBuilder.GetNextEventID())
@@ -627,7 +810,7 @@ func (s *engine2Suite) TestRequestCancelWorkflowExecutionFail() {
 
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse1, nil).Once()
 
-	err := s.historyEngine.RequestCancelWorkflowExecution(&h.RequestCancelWorkflowExecutionRequest{
+	_, err := s.historyEngine.RequestCancelWorkflowExecution(&h.RequestCancelWorkflowExecutionRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CancelRequest: &workflow.RequestCancelWorkflowExecutionRequest{
 			WorkflowExecution: &workflow.WorkflowExecution{
@@ -641,6 +824,312 @@ func (s *engine2Suite) TestRequestCancelWorkflowExecutionFail() {
 	s.IsType(&workflow.EntityNotExistsError{}, err)
 }
 
+func (s *engine2Suite) TestRequestCancelWorkflowExecutionExpectedRunIDMismatch() {
+	domainID := "domainId"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+
+	identity := "testIdentity"
+	tl := "testTaskList"
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, false)
+	ms1 := createMutableState(msBuilder)
+	gwmsResponse1 := &persistence.GetWorkflowExecutionResponse{State: ms1}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse1, nil).Once()
+
+	_, err := s.historyEngine.RequestCancelWorkflowExecution(&h.RequestCancelWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CancelRequest: &workflow.RequestCancelWorkflowExecutionRequest{
+			WorkflowExecution: &workflow.WorkflowExecution{
+				WorkflowId: workflowExecution.WorkflowId,
+				RunId:      workflowExecution.RunId,
+			},
+			Identity:      common.StringPtr("identity"),
+			ExpectedRunId: common.StringPtr(uuid.New()),
+		},
+	})
+	s.NotNil(err)
+	s.IsType(&workflow.EntityNotExistsError{}, err)
+}
+
+func (s *engine2Suite) TestTerminateWorkflowExecutionExpectedRunID() {
+	domainID := "domainId"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+
+	identity := "testIdentity"
+	tl := "testTaskList"
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, false)
+	ms1 := createMutableState(msBuilder)
+	gwmsResponse1 := &persistence.GetWorkflowExecutionResponse{State: ms1}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse1, nil).Once()
+
+	// mismatching run id must not terminate the workflow
+	err := s.historyEngine.TerminateWorkflowExecution(&h.TerminateWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		TerminateRequest: &workflow.TerminateWorkflowExecutionRequest{
+			WorkflowExecution: &workflow.WorkflowExecution{
+				WorkflowId: workflowExecution.WorkflowId,
+				RunId:      workflowExecution.RunId,
+			},
+			Identity:      common.StringPtr("identity"),
+			ExpectedRunId: common.StringPtr(uuid.New()),
+		},
+	})
+	s.NotNil(err)
+	s.IsType(&workflow.EntityNotExistsError{}, err)
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	err = s.historyEngine.TerminateWorkflowExecution(&h.TerminateWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		TerminateRequest: &workflow.TerminateWorkflowExecutionRequest{
+			WorkflowExecution: &workflow.WorkflowExecution{
+				WorkflowId: workflowExecution.WorkflowId,
+				RunId:      workflowExecution.RunId,
+			},
+			Identity:      common.StringPtr("identity"),
+			ExpectedRunId: workflowExecution.RunId,
+		},
+	})
+	s.Nil(err)
+
+	executionBuilder := s.getBuilder(domainID, workflowExecution)
+	s.False(executionBuilder.isWorkflowExecutionRunning())
+}
+
+func (s *engine2Suite) TestTerminateWorkflowExecutionBulkTerminateOutcome() {
+	domainID := "domainId"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+
+	identity := "testIdentity"
+	tl := "testTaskList"
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, false)
+	ms1 := createMutableState(msBuilder)
+	gwmsResponse1 := &persistence.GetWorkflowExecutionResponse{State: ms1}
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse1, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	terminateRequest := func(execution workflow.WorkflowExecution) *h.TerminateWorkflowExecutionRequest {
+		return &h.TerminateWorkflowExecutionRequest{
+			DomainUUID: common.StringPtr(domainID),
+			TerminateRequest: &workflow.TerminateWorkflowExecutionRequest{
+				WorkflowExecution: &execution,
+				Reason:            common.StringPtr("cleanup"),
+				Identity:          common.StringPtr(identity),
+			},
+		}
+	}
+
+	err := s.historyEngine.TerminateWorkflowExecution(terminateRequest(workflowExecution))
+	s.Equal(BulkTerminateOutcomeTerminated, getBulkTerminateOutcome(err))
+	executionBuilder := s.getBuilder(domainID, workflowExecution)
+	s.False(executionBuilder.isWorkflowExecutionRunning())
+	s.Equal(persistence.WorkflowCloseStatusTerminated, executionBuilder.executionInfo.CloseStatus)
+
+	// terminating the closed execution again leaves it untouched, the error only keeps its message once it crossed
+	// hosts
+	err = s.historyEngine.TerminateWorkflowExecution(terminateRequest(workflowExecution))
+	s.Equal(BulkTerminateOutcomeAlreadyClosed, getBulkTerminateOutcome(err))
+	s.Equal(BulkTerminateOutcomeAlreadyClosed, getBulkTerminateOutcome(
+		&workflow.EntityNotExistsError{Message: err.(*workflow.EntityNotExistsError).Message}))
+
+	// an execution which does not exist is reported as such
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil,
+		&workflow.EntityNotExistsError{}).Once()
+	err = s.historyEngine.TerminateWorkflowExecution(terminateRequest(workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId-missing"),
+		RunId:      common.StringPtr(validRunID),
+	}))
+	s.Equal(BulkTerminateOutcomeNotFound, getBulkTerminateOutcome(err))
+
+	s.Equal(BulkTerminateOutcomeFailed, getBulkTerminateOutcome(&workflow.InternalServiceError{}))
+}
+
+func (s *engine2Suite) TestTerminateAndStart() {
+	domainID := "domainId"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+
+	identity := "testIdentity"
+	tl := "testTaskList"
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, false)
+	ms1 := createMutableState(msBuilder)
+	gwmsResponse1 := &persistence.GetWorkflowExecutionResponse{State: ms1}
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	// the current run is terminated when no run id is given
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(
+		&persistence.GetCurrentExecutionResponse{RunID: validRunID}, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse1, nil).Once()
+	// the history of the new run and the terminated event of the current run
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Twice()
+	var newRunID string
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(
+		func(request *persistence.UpdateWorkflowExecutionRequest) bool {
+			startRequest := request.ContinueAsNew
+			if startRequest == nil || !startRequest.ContinueAsNew || startRequest.PreviousRunID != validRunID {
+				return false
+			}
+			newRunID = startRequest.Execution.GetRunId()
+			return request.ExecutionInfo.CloseStatus == persistence.WorkflowCloseStatusTerminated
+		})).Return(nil).Once()
+
+	resp, err := s.historyEngine.TerminateAndStart(&TerminateAndStartRequest{
+		DomainID: domainID,
+		Reason:   "restart",
+		Identity: identity,
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          workflowExecution.WorkflowId,
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr(tl)},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(100),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(10),
+			Identity:                            common.StringPtr(identity),
+			RequestId:                           common.StringPtr(uuid.New()),
+		},
+	})
+	s.Nil(err)
+	s.Equal(newRunID, resp.GetRunId())
+	s.NotEqual(validRunID, resp.GetRunId())
+
+	executionBuilder := s.getBuilder(domainID, workflowExecution)
+	s.False(executionBuilder.isWorkflowExecutionRunning())
+	s.Equal(persistence.WorkflowCloseStatusTerminated, executionBuilder.executionInfo.CloseStatus)
+}
+
+func (s *engine2Suite) TestTerminateAndStart_ConcurrentStartRejected() {
+	domainID := "domainId"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+
+	identity := "testIdentity"
+	tl := "testTaskList"
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, false)
+	ms1 := createMutableState(msBuilder)
+	gwmsResponse1 := &persistence.GetWorkflowExecutionResponse{State: ms1}
+	// another caller closed the run and started the next one in the meantime
+	ms2 := createMutableState(msBuilder)
+	ms2.ExecutionInfo.State = persistence.WorkflowStateCompleted
+	ms2.ExecutionInfo.CloseStatus = persistence.WorkflowCloseStatusTerminated
+	gwmsResponse2 := &persistence.GetWorkflowExecutionResponse{State: ms2}
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse1, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse2, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Twice()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(
+		&persistence.ConditionFailedError{Msg: "current run changed"}).Once()
+	// the history appended for the run which was not created is deleted
+	s.mockHistoryMgr.On("DeleteWorkflowExecutionHistory", mock.MatchedBy(
+		func(request *persistence.DeleteWorkflowExecutionHistoryRequest) bool {
+			return request.Execution.GetRunId() != validRunID
+		})).Return(nil).Once()
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(
+		&persistence.GetCurrentExecutionResponse{RunID: uuid.New(), StartRequestID: uuid.New()}, nil).Once()
+
+	_, err := s.historyEngine.TerminateAndStart(&TerminateAndStartRequest{
+		DomainID: domainID,
+		RunID:    validRunID,
+		Identity: identity,
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          workflowExecution.WorkflowId,
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr(tl)},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(100),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(10),
+			Identity:                            common.StringPtr(identity),
+			RequestId:                           common.StringPtr(uuid.New()),
+		},
+	})
+	s.Equal(ErrWorkflowCompleted, err)
+}
+
+func (s *engine2Suite) TestTerminateAndStart_RetriedRequest() {
+	domainID := "domainId"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+
+	identity := "testIdentity"
+	tl := "testTaskList"
+	requestID := uuid.New()
+	newRunID := uuid.New()
+	s.historyEngine.startedRunIDs = cache.New(10, &cache.Options{TTL: time.Minute})
+
+	// the run was terminated by an earlier attempt of the call, which started the current run
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, false)
+	ms := createMutableState(msBuilder)
+	ms.ExecutionInfo.State = persistence.WorkflowStateCompleted
+	ms.ExecutionInfo.CloseStatus = persistence.WorkflowCloseStatusTerminated
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(
+		&persistence.GetCurrentExecutionResponse{RunID: newRunID, StartRequestID: requestID}, nil).Once()
+
+	request := &TerminateAndStartRequest{
+		DomainID: domainID,
+		RunID:    validRunID,
+		Identity: identity,
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          workflowExecution.WorkflowId,
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr(tl)},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(100),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(10),
+			Identity:                            common.StringPtr(identity),
+			RequestId:                           common.StringPtr(requestID),
+		},
+	}
+	resp, err := s.historyEngine.TerminateAndStart(request)
+	s.Nil(err)
+	s.Equal(newRunID, resp.GetRunId())
+
+	// further retries are answered from the remembered start
+	resp, err = s.historyEngine.TerminateAndStart(request)
+	s.Nil(err)
+	s.Equal(newRunID, resp.GetRunId())
+}
+
 func (s *engine2Suite) createExecutionStartedState(we workflow.WorkflowExecution, tl, identity string,
 	startDecision bool) *mutableStateBuilder {
 	msBuilder := newMutableStateBuilder(s.config, s.logger)
@@ -699,7 +1188,7 @@ func (s *engine2Suite) TestRespondDecisionTaskCompletedRecordMarkerDecision() {
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.historyEngine.RespondDecisionTaskCompleted(context.Background(), &h.RespondDecisionTaskCompletedRequest{
+	_, err := s.historyEngine.RespondDecisionTaskCompleted(context.Background(), &h.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken:        taskToken,
@@ -709,24 +1198,454 @@ func (s *engine2Suite) TestRespondDecisionTaskCompletedRecordMarkerDecision() {
 		},
 	})
 	s.Nil(err)
-	executionBuilder := s.getBuilder(domainID, we)
-	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
-	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
-	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
-	s.False(executionBuilder.HasPendingDecisionTask())
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
+	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
+	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
+	s.False(executionBuilder.HasPendingDecisionTask())
+}
+
+func (s *engine2Suite) TestRespondDecisionTaskCompletedBadRecordMarkerDecision() {
+	domainID := "domainId"
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	maxMarkerDetailsSize := s.config.MaxMarkerDetailsSize
+	defer func() { s.config.MaxMarkerDetailsSize = maxMarkerDetailsSize }()
+	s.config.MaxMarkerDetailsSize = 10
+
+	testCases := []struct {
+		name    string
+		details []byte
+	}{
+		{name: "", details: []byte("details")},
+		{name: "marker name", details: []byte("details larger than the limit")},
+	}
+
+	for i, tc := range testCases {
+		we := workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(fmt.Sprintf("wId-%v", i)),
+			RunId:      common.StringPtr(validRunID),
+		}
+		taskToken, _ := json.Marshal(&common.TaskToken{
+			WorkflowID: we.GetWorkflowId(),
+			RunID:      we.GetRunId(),
+			ScheduleID: 2,
+		})
+
+		msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+		addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+		di := addDecisionTaskScheduledEvent(msBuilder)
+		addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+		for attempt := 0; attempt < 2; attempt++ {
+			ms := createMutableState(msBuilder)
+			gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+			s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+		}
+
+		var appendRequest *persistence.AppendHistoryEventsRequest
+		s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			appendRequest = args.Get(0).(*persistence.AppendHistoryEventsRequest)
+		}).Once()
+		s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+		_, err := s.historyEngine.RespondDecisionTaskCompleted(context.Background(), &h.RespondDecisionTaskCompletedRequest{
+			DomainUUID: common.StringPtr(domainID),
+			CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+				TaskToken: taskToken,
+				Decisions: []*workflow.Decision{{
+					DecisionType: common.DecisionTypePtr(workflow.DecisionTypeRecordMarker),
+					RecordMarkerDecisionAttributes: &workflow.RecordMarkerDecisionAttributes{
+						MarkerName: common.StringPtr(tc.name),
+						Details:    tc.details,
+					},
+				}},
+				Identity: &identity,
+			},
+		})
+		s.IsType(&workflow.BadRequestError{}, err)
+
+		s.NotNil(appendRequest)
+		serializer, _ := s.historyEngine.hSerializerFactory.Get(appendRequest.Events.EncodingType)
+		history, err := serializer.Deserialize(appendRequest.Events)
+		s.Nil(err)
+		s.Equal(workflow.EventTypeDecisionTaskFailed, history.Events[0].GetEventType())
+		s.Equal(workflow.DecisionTaskFailedCauseBadRecordMarkerAttributes,
+			history.Events[0].DecisionTaskFailedEventAttributes.GetCause())
+		executionBuilder := s.getBuilder(domainID, we)
+		s.True(executionBuilder.HasPendingDecisionTask())
+	}
+}
+
+func (s *engine2Suite) TestStartWorkflowExecution_BrandNew() {
+	domainID := "domainId"
+	workflowID := "workflowID"
+	workflowType := "workflowType"
+	taskList := "testTaskList"
+	identity := "testIdentity"
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	var createRequest *persistence.CreateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Run(func(args mock.Arguments) {
+		createRequest = args.Get(0).(*persistence.CreateWorkflowExecutionRequest)
+	}).Once()
+
+	resp, err := s.historyEngine.StartWorkflowExecution(&h.StartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:       common.StringPtr(domainID),
+			WorkflowId:   common.StringPtr(workflowID),
+			WorkflowType: &workflow.WorkflowType{Name: common.StringPtr(workflowType)},
+			TaskList:     &workflow.TaskList{Name: common.StringPtr(taskList)},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr(identity),
+		},
+	})
+	s.Nil(err)
+	s.NotNil(resp.RunId)
+
+	// the start time is persisted with the execution and its timeout is derived from it
+	s.False(createRequest.StartTimestamp.IsZero())
+	s.Equal(1, len(createRequest.TimerTasks))
+	s.Equal(createRequest.StartTimestamp.Add(time.Second),
+		createRequest.TimerTasks[0].(*persistence.WorkflowTimeoutTask).VisibilityTimestamp)
+}
+
+func (s *engine2Suite) TestStartWorkflowExecution_KeyID() {
+	domainID := "domainId"
+	keyID := "testKeyID"
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.MatchedBy(func(request *persistence.AppendHistoryEventsRequest) bool {
+		return request.KeyID == keyID
+	})).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.MatchedBy(func(request *persistence.CreateWorkflowExecutionRequest) bool {
+		return request.KeyID == keyID
+	})).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+
+	resp, err := s.historyEngine.StartWorkflowExecution(&h.StartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          common.StringPtr("workflowID"),
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("workflowType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr("testIdentity"),
+			KeyId:                               common.StringPtr(keyID),
+		},
+	})
+	s.Nil(err)
+	s.NotNil(resp.RunId)
+}
+
+func (s *engine2Suite) TestGetShardStats() {
+	domainID := "domainId"
+	workflowID := "workflowID"
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Twice()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Twice()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("DeleteWorkflowExecution", mock.Anything).Return(nil).Twice()
+
+	resp, err := s.historyEngine.StartWorkflowExecution(&h.StartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          common.StringPtr(workflowID),
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("workflowType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr("testIdentity"),
+		},
+	})
+	s.Nil(err)
+	stats := s.historyEngine.GetShardStats()
+	s.Equal(int64(1), stats.OpenExecutionCount)
+	s.Equal(int64(0), stats.ClosedExecutionCount)
+
+	// closing the execution moves it to the closed count until it is deleted after retention
+	err = s.historyEngine.shard.UpdateWorkflowExecution(&persistence.UpdateWorkflowExecutionRequest{
+		ExecutionInfo:   &persistence.WorkflowExecutionInfo{DomainID: domainID, WorkflowID: workflowID, RunID: resp.GetRunId()},
+		FinishExecution: true,
+	})
+	s.Nil(err)
+	stats = s.historyEngine.GetShardStats()
+	s.Equal(int64(0), stats.OpenExecutionCount)
+	s.Equal(int64(1), stats.ClosedExecutionCount)
+
+	err = s.historyEngine.shard.DeleteWorkflowExecution(&persistence.DeleteWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowID,
+		RunID:      resp.GetRunId(),
+	}, false)
+	s.Nil(err)
+	stats = s.historyEngine.GetShardStats()
+	s.Equal(int64(0), stats.OpenExecutionCount)
+	s.Equal(int64(0), stats.ClosedExecutionCount)
+
+	// deleting an execution which is still running takes it off the open count
+	resp, err = s.historyEngine.StartWorkflowExecution(&h.StartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          common.StringPtr(workflowID),
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("workflowType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr("testIdentity"),
+		},
+	})
+	s.Nil(err)
+	err = s.historyEngine.shard.DeleteWorkflowExecution(&persistence.DeleteWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowID,
+		RunID:      resp.GetRunId(),
+	}, true)
+	s.Nil(err)
+	stats = s.historyEngine.GetShardStats()
+	s.Equal(int64(0), stats.OpenExecutionCount)
+	s.Equal(int64(0), stats.ClosedExecutionCount)
+}
+
+func (s *engine2Suite) TestRecountExecutionsAppliesConcurrentChanges() {
+	shard := s.historyEngine.shard.(*shardContextImpl)
+	shard.shardInfo.OpenExecutionCount = 7
+	shard.shardInfo.ClosedExecutionCount = 7
+	shard.recountDeltas = &executionCountDeltas{}
+
+	// an execution is started while the executions stored in the shard are paged through
+	s.mockExecutionMgr.On("ListOpenExecutions", mock.Anything).Return(func(
+		request *persistence.ListOpenExecutionsRequest) *persistence.ListOpenExecutionsResponse {
+		shard.Lock()
+		shard.updateExecutionCountsLocked(1, 0)
+		shard.Unlock()
+		return &persistence.ListOpenExecutionsResponse{
+			Executions:  []*persistence.OpenExecution{{}, {}},
+			ClosedCount: 3,
+		}
+	}, nil).Once()
+
+	shard.recountExecutions()
+	stats := s.historyEngine.GetShardStats()
+	s.Equal(int64(3), stats.OpenExecutionCount)
+	s.Equal(int64(3), stats.ClosedExecutionCount)
+	s.Nil(shard.recountDeltas)
+	countedAt := shard.shardInfo.ExecutionsCountedAt
+	s.False(countedAt.IsZero())
+
+	// the counts are kept if they cannot be recounted
+	shard.recountDeltas = &executionCountDeltas{}
+	s.mockExecutionMgr.On("ListOpenExecutions", mock.Anything).Return(nil,
+		&workflow.InternalServiceError{Message: "ListOpenExecutions failed"}).Once()
+	shard.recountExecutions()
+	stats = s.historyEngine.GetShardStats()
+	s.Equal(int64(3), stats.OpenExecutionCount)
+	s.Equal(int64(3), stats.ClosedExecutionCount)
+	s.Equal(countedAt, shard.shardInfo.ExecutionsCountedAt)
+}
+
+func (s *engine2Suite) TestDeleteHistoryEventRetried() {
+	shard := s.historyEngine.shard.(*shardContextImpl)
+	shard.shardInfo.ClosedExecutionCount = 2
+	timerProcessor := s.historyEngine.timerProcessor.(*timerQueueProcessorImpl).activeTimerProcessor
+	task := &persistence.TimerTaskInfo{
+		DomainID:   "deleteHistoryEventDomainID",
+		WorkflowID: "delete-history-event-retried",
+		RunID:      validRunID,
+		TaskType:   persistence.TaskTypeDeleteHistoryEvent,
+	}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: &persistence.WorkflowMutableState{}}, nil).Once()
+	s.mockExecutionMgr.On("DeleteWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockHistoryMgr.On("DeleteWorkflowExecutionHistory", mock.Anything).Return(
+		errors.New("DeleteWorkflowExecutionHistory failed")).Once()
+	s.NotNil(timerProcessor.timerQueueProcessorBase.processDeleteHistoryEvent(task))
+	s.Equal(int64(1), s.historyEngine.GetShardStats().ClosedExecutionCount)
+
+	// the execution is gone when the task is retried, it is not taken off the closed count again
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil,
+		&workflow.EntityNotExistsError{Message: "Workflow execution not found"}).Once()
+	s.mockHistoryMgr.On("DeleteWorkflowExecutionHistory", mock.Anything).Return(nil).Once()
+	s.Nil(timerProcessor.timerQueueProcessorBase.processDeleteHistoryEvent(task))
+	s.Equal(int64(1), s.historyEngine.GetShardStats().ClosedExecutionCount)
+}
+
+func (s *engine2Suite) TestStartWorkflowExecution_OpenExecutionLimitExceeded() {
+	maxOpenExecutions := s.config.MaxOpenExecutionsPerShard
+	defer func() { s.config.MaxOpenExecutionsPerShard = maxOpenExecutions }()
+	s.config.MaxOpenExecutionsPerShard = func(opts ...dynamicconfig.FilterOption) int { return 1 }
+
+	domainID := "domainId"
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+
+	startWorkflow := func(workflowID string) error {
+		_, err := s.historyEngine.StartWorkflowExecution(&h.StartWorkflowExecutionRequest{
+			DomainUUID: common.StringPtr(domainID),
+			StartRequest: &workflow.StartWorkflowExecutionRequest{
+				Domain:                              common.StringPtr(domainID),
+				WorkflowId:                          common.StringPtr(workflowID),
+				WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("workflowType")},
+				TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+				ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+				TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+				Identity:                            common.StringPtr("testIdentity"),
+				RequestId:                           common.StringPtr(uuid.New()),
+			},
+		})
+		return err
+	}
+
+	s.Nil(startWorkflow("workflowID1"))
+	s.Equal(int64(1), s.historyEngine.GetShardStats().OpenExecutionCount)
+
+	err := startWorkflow("workflowID2")
+	s.IsType(&workflow.ServiceBusyError{}, err)
+
+	// a signal with start of a new execution is rejected as well
+	notExistErr := &workflow.EntityNotExistsError{Message: "Workflow not exist"}
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(nil, notExistErr).Once()
+	_, err = s.historyEngine.SignalWithStartWorkflowExecution(&h.SignalWithStartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalWithStartRequest: &workflow.SignalWithStartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          common.StringPtr("workflowID3"),
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("workflowType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr("testIdentity"),
+			SignalName:                          common.StringPtr("signalName"),
+		},
+	})
+	s.IsType(&workflow.ServiceBusyError{}, err)
+}
+
+func (s *engine2Suite) TestGetQueueLag() {
+	domainID := "domainId"
+
+	lag := s.historyEngine.GetQueueLag()
+	s.Equal(0, lag.ShardID)
+	s.Equal(int64(0), lag.TransferTaskIDLag[cluster.TestCurrentClusterName])
+	s.Equal(time.Duration(0), lag.TimerLag[cluster.TestCurrentClusterName])
+	// standby clusters are not reported when global domains are disabled
+	s.Len(lag.TransferTaskIDLag, 1)
+	s.Len(lag.TimerLag, 1)
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+
+	_, err := s.historyEngine.StartWorkflowExecution(&h.StartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          common.StringPtr("workflowID"),
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("workflowType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr("testIdentity"),
+		},
+	})
+	s.Nil(err)
+
+	// the transfer tasks of the new workflow are not processed as the queue processors are not started
+	lag = s.historyEngine.GetQueueLag()
+	s.True(lag.TransferTaskIDLag[cluster.TestCurrentClusterName] > 0)
+	s.Equal(time.Duration(0), lag.TimerLag[cluster.TestCurrentClusterName])
+}
+
+func (s *engine2Suite) TestStartWorkflowExecution_ChildDepth() {
+	domainID := "domainId"
+	workflowID := "workflowID"
+	workflowType := "workflowType"
+	taskList := "testTaskList"
+	identity := "testIdentity"
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.MatchedBy(func(request *persistence.CreateWorkflowExecutionRequest) bool {
+		return request.Depth == 3
+	})).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+
+	resp, err := s.historyEngine.StartWorkflowExecution(&h.StartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:       common.StringPtr(domainID),
+			WorkflowId:   common.StringPtr(workflowID),
+			WorkflowType: &workflow.WorkflowType{Name: common.StringPtr(workflowType)},
+			TaskList:     &workflow.TaskList{Name: common.StringPtr(taskList)},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr(identity),
+		},
+		ParentExecutionInfo: &h.ParentExecutionInfo{
+			DomainUUID: common.StringPtr(domainID),
+			Execution: &workflow.WorkflowExecution{
+				WorkflowId: common.StringPtr("parentWorkflowID"),
+				RunId:      common.StringPtr(validRunID),
+			},
+			InitiatedId: common.Int64Ptr(5),
+			Depth:       common.Int32Ptr(2),
+		},
+	})
+	s.Nil(err)
+	s.NotNil(resp.RunId)
 }
 
-func (s *engine2Suite) TestStartWorkflowExecution_BrandNew() {
+func (s *engine2Suite) TestStartWorkflowExecution_SyncMatchFirstDecision() {
 	domainID := "domainId"
 	workflowID := "workflowID"
 	workflowType := "workflowType"
 	taskList := "testTaskList"
 	identity := "testIdentity"
 
+	enableSyncMatch := s.config.EnableSyncMatchFirstDecision
+	defer func() { s.config.EnableSyncMatchFirstDecision = enableSyncMatch }()
+	s.config.EnableSyncMatchFirstDecision = func(opts ...dynamicconfig.FilterOption) bool { return true }
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+	// a failed hand off must not fail the start, the transfer task still dispatches the decision
+	s.mockMatchingClient.On("AddDecisionTask", mock.Anything, mock.MatchedBy(func(request *m.AddDecisionTaskRequest) bool {
+		return request.GetDomainUUID() == domainID && request.Execution.GetWorkflowId() == workflowID &&
+			request.TaskList.GetName() == taskList && request.GetScheduleId() == int64(2)
+	})).Return(errors.New("no poller")).Once()
 
-	resp, err := s.historyEngine.StartWorkflowExecution(&h.StartWorkflowExecutionRequest{
+	startRequest := &h.StartWorkflowExecutionRequest{
 		DomainUUID: common.StringPtr(domainID),
 		StartRequest: &workflow.StartWorkflowExecutionRequest{
 			Domain:       common.StringPtr(domainID),
@@ -737,9 +1656,87 @@ func (s *engine2Suite) TestStartWorkflowExecution_BrandNew() {
 			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
 			Identity:                            common.StringPtr(identity),
 		},
-	})
+	}
+	resp, err := s.historyEngine.StartWorkflowExecution(startRequest)
+	s.Nil(err)
+	s.NotNil(resp.RunId)
+	execution := workflow.WorkflowExecution{WorkflowId: common.StringPtr(workflowID), RunId: resp.RunId}
+	s.False(s.historyEngine.consumeFirstDecisionMatch(domainID, execution, int64(2)))
+
+	// a decision handed to a poller is skipped once by its transfer task
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+	s.mockMatchingClient.On("AddDecisionTask", mock.Anything, mock.Anything).Return(nil).Once()
+	startRequest.StartRequest.WorkflowId = common.StringPtr(workflowID + "-matched")
+	resp, err = s.historyEngine.StartWorkflowExecution(startRequest)
+	s.Nil(err)
+	execution = workflow.WorkflowExecution{WorkflowId: common.StringPtr(workflowID + "-matched"), RunId: resp.RunId}
+	s.True(s.historyEngine.consumeFirstDecisionMatch(domainID, execution, int64(2)))
+	s.False(s.historyEngine.consumeFirstDecisionMatch(domainID, execution, int64(2)))
+}
+
+func (s *engine2Suite) TestStartWorkflowExecution_TaskListRouter() {
+	domainID := "domainId"
+	workflowType := "workflowType"
+	taskList := "testTaskList"
+	identity := "testIdentity"
+
+	startTaskListRouter := s.config.StartTaskListRouter
+	defer func() { s.config.StartTaskListRouter = startTaskListRouter }()
+	s.config.StartTaskListRouter = func(domainID string, request *workflow.StartWorkflowExecutionRequest) string {
+		if strings.HasPrefix(request.GetWorkflowId(), "tenantA-") {
+			return "tenantA-" + request.TaskList.GetName()
+		}
+		if strings.HasPrefix(request.GetWorkflowId(), "tenantC-") {
+			return strings.Repeat("c", s.config.MaxTaskListNameLength+1)
+		}
+		return ""
+	}
+
+	var createRequest *persistence.CreateWorkflowExecutionRequest
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(
+		&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Run(func(args mock.Arguments) {
+		createRequest = args.Get(0).(*persistence.CreateWorkflowExecutionRequest)
+	}).Once()
+
+	newStartRequest := func(workflowID string) *h.StartWorkflowExecutionRequest {
+		return &h.StartWorkflowExecutionRequest{
+			DomainUUID: common.StringPtr(domainID),
+			StartRequest: &workflow.StartWorkflowExecutionRequest{
+				Domain:                              common.StringPtr(domainID),
+				WorkflowId:                          common.StringPtr(workflowID),
+				WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr(workflowType)},
+				TaskList:                            &workflow.TaskList{Name: common.StringPtr(taskList)},
+				ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+				TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+				Identity:                            common.StringPtr(identity),
+			},
+		}
+	}
+
+	startRequest := newStartRequest("tenantA-workflowID")
+	resp, err := s.historyEngine.StartWorkflowExecution(startRequest)
 	s.Nil(err)
 	s.NotNil(resp.RunId)
+	s.NotNil(createRequest)
+	s.Equal("tenantA-"+taskList, createRequest.TaskList)
+	s.Equal(1, len(createRequest.TransferTasks))
+	s.Equal("tenantA-"+taskList, createRequest.TransferTasks[0].(*persistence.DecisionTask).TaskList)
+	// the request of the caller is left as is
+	s.Equal(taskList, startRequest.StartRequest.TaskList.GetName())
+
+	// a router which does not resolve a task list fails the start
+	_, err = s.historyEngine.StartWorkflowExecution(newStartRequest("tenantB-workflowID"))
+	s.IsType(&workflow.BadRequestError{}, err)
+
+	// the routed task list is held to the same limits as the one of the caller
+	_, err = s.historyEngine.StartWorkflowExecution(newStartRequest("tenantC-workflowID"))
+	s.IsType(&workflow.BadRequestError{}, err)
 }
 
 func (s *engine2Suite) TestStartWorkflowExecution_StillRunning_Dedup() {
@@ -751,6 +1748,10 @@ func (s *engine2Suite) TestStartWorkflowExecution_StillRunning_Dedup() {
 	identity := "testIdentity"
 	requestID := "requestID"
 
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockShardManager.On("UpdateShard", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(nil, &persistence.WorkflowExecutionAlreadyStartedError{
@@ -779,6 +1780,42 @@ func (s *engine2Suite) TestStartWorkflowExecution_StillRunning_Dedup() {
 	s.Equal(runID, resp.GetRunId())
 }
 
+func (s *engine2Suite) TestStartWorkflowExecution_StartedRunIDCached() {
+	domainID := "domainId"
+	requestID := "requestID"
+	s.historyEngine.startedRunIDs = cache.New(10, &cache.Options{TTL: time.Minute})
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(
+		&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+
+	startRequest := &h.StartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          common.StringPtr("workflowID"),
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("workflowType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr("testIdentity"),
+			RequestId:                           common.StringPtr(requestID),
+		},
+	}
+	resp, err := s.historyEngine.StartWorkflowExecution(startRequest)
+	s.Nil(err)
+	s.NotEmpty(resp.GetRunId())
+
+	// the retry is answered from the cache, without appending history or creating the execution again
+	retryResp, err := s.historyEngine.StartWorkflowExecution(startRequest)
+	s.Nil(err)
+	s.Equal(resp.GetRunId(), retryResp.GetRunId())
+}
+
 func (s *engine2Suite) TestStartWorkflowExecution_StillRunning_NonDeDup() {
 	domainID := "domainId"
 	workflowID := "workflowID"
@@ -787,6 +1824,10 @@ func (s *engine2Suite) TestStartWorkflowExecution_StillRunning_NonDeDup() {
 	taskList := "testTaskList"
 	identity := "testIdentity"
 
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockShardManager.On("UpdateShard", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(nil, &persistence.WorkflowExecutionAlreadyStartedError{
@@ -811,9 +1852,12 @@ func (s *engine2Suite) TestStartWorkflowExecution_StillRunning_NonDeDup() {
 			RequestId:                           common.StringPtr("newRequestID"),
 		},
 	})
-	if _, ok := err.(*workflow.WorkflowExecutionAlreadyStartedError); !ok {
+	startedErr, ok := err.(*workflow.WorkflowExecutionAlreadyStartedError)
+	if !ok {
 		s.Fail("return err is not *shared.WorkflowExecutionAlreadyStartedError")
 	}
+	s.True(startedErr.GetRunning())
+	s.Nil(startedErr.CloseStatus)
 	s.Nil(resp)
 }
 
@@ -833,6 +1877,10 @@ func (s *engine2Suite) TestStartWorkflowExecution_NotRunning_PrevSuccess() {
 
 	expecedErrs := []bool{true, false, true}
 
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Times(len(expecedErrs))
 	s.mockShardManager.On("UpdateShard", mock.Anything).Return(nil).Times(len(expecedErrs))
 	s.mockExecutionMgr.On(
@@ -872,9 +1920,104 @@ func (s *engine2Suite) TestStartWorkflowExecution_NotRunning_PrevSuccess() {
 		})
 
 		if expecedErrs[index] {
-			if _, ok := err.(*workflow.WorkflowExecutionAlreadyStartedError); !ok {
+			startedErr, ok := err.(*workflow.WorkflowExecutionAlreadyStartedError)
+			if !ok {
 				s.Fail("return err is not *shared.WorkflowExecutionAlreadyStartedError")
 			}
+			s.False(startedErr.GetRunning())
+			s.Equal(workflow.WorkflowExecutionCloseStatusCompleted, startedErr.GetCloseStatus())
+			s.Nil(resp)
+		} else {
+			s.Nil(err)
+			s.NotNil(resp)
+		}
+	}
+}
+
+func (s *engine2Suite) TestStartWorkflowExecution_NotRunning_ReuseMinInterval() {
+	domainID := "domainId"
+	workflowID := "workflowID"
+	runID := "runID"
+	workflowType := "workflowType"
+	taskList := "testTaskList"
+	identity := "testIdentity"
+
+	originalInterval := s.config.WorkflowIDReuseMinInterval
+	defer func() { s.config.WorkflowIDReuseMinInterval = originalInterval }()
+	s.config.WorkflowIDReuseMinInterval = func(opts ...dynamicconfig.FilterOption) time.Duration { return time.Hour }
+
+	testCases := []struct {
+		closedAgo   time.Duration
+		expectedErr bool
+	}{
+		{closedAgo: time.Minute, expectedErr: true},
+		{closedAgo: 2 * time.Hour, expectedErr: false},
+	}
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Times(len(testCases))
+	s.mockShardManager.On("UpdateShard", mock.Anything).Return(nil).Times(len(testCases))
+	s.mockExecutionMgr.On(
+		"CreateWorkflowExecution",
+		mock.MatchedBy(func(request *persistence.CreateWorkflowExecutionRequest) bool { return request.ContinueAsNew == false }),
+	).Return(nil, &persistence.WorkflowExecutionAlreadyStartedError{
+		Msg:            "random message",
+		StartRequestID: "oldRequestID",
+		RunID:          runID,
+		State:          persistence.WorkflowStateCompleted,
+		CloseStatus:    persistence.WorkflowCloseStatusCompleted,
+	}).Times(len(testCases))
+
+	for _, tc := range testCases {
+		s.mockExecutionMgr.On("GetWorkflowExecution", &persistence.GetWorkflowExecutionRequest{
+			DomainID: domainID,
+			Execution: workflow.WorkflowExecution{
+				WorkflowId: common.StringPtr(workflowID),
+				RunId:      common.StringPtr(runID),
+			},
+		}).Return(&persistence.GetWorkflowExecutionResponse{State: &persistence.WorkflowMutableState{
+			ExecutionInfo: &persistence.WorkflowExecutionInfo{
+				DomainID:             domainID,
+				WorkflowID:           workflowID,
+				RunID:                runID,
+				State:                persistence.WorkflowStateCompleted,
+				CloseStatus:          persistence.WorkflowCloseStatusCompleted,
+				LastUpdatedTimestamp: time.Now().Add(-tc.closedAgo),
+			},
+		}}, nil).Once()
+		if tc.expectedErr {
+			s.mockHistoryMgr.On("DeleteWorkflowExecutionHistory", mock.Anything).Return(nil).Once()
+		} else {
+			s.mockExecutionMgr.On(
+				"CreateWorkflowExecution",
+				mock.MatchedBy(func(request *persistence.CreateWorkflowExecutionRequest) bool { return request.ContinueAsNew == true }),
+			).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+		}
+
+		policy := workflow.WorkflowIdReusePolicyAllowDuplicate
+		resp, err := s.historyEngine.StartWorkflowExecution(&h.StartWorkflowExecutionRequest{
+			DomainUUID: common.StringPtr(domainID),
+			StartRequest: &workflow.StartWorkflowExecutionRequest{
+				Domain:                              common.StringPtr(domainID),
+				WorkflowId:                          common.StringPtr(workflowID),
+				WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr(workflowType)},
+				TaskList:                            &workflow.TaskList{Name: common.StringPtr(taskList)},
+				ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+				TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+				Identity:                            common.StringPtr(identity),
+				RequestId:                           common.StringPtr("newRequestID"),
+				WorkflowIdReusePolicy:               &policy,
+			},
+		})
+
+		if tc.expectedErr {
+			startedErr, ok := err.(*workflow.WorkflowExecutionAlreadyStartedError)
+			s.True(ok)
+			s.False(startedErr.GetRunning())
+			s.Equal(runID, startedErr.GetRunId())
 			s.Nil(resp)
 		} else {
 			s.Nil(err)
@@ -883,6 +2026,96 @@ func (s *engine2Suite) TestStartWorkflowExecution_NotRunning_PrevSuccess() {
 	}
 }
 
+func (s *engine2Suite) TestStartWorkflowExecution_NotRunning_RetriedStart() {
+	domainID := "domainId"
+	workflowID := "workflowID"
+	runID := "runID"
+	workflowType := "workflowType"
+	taskList := "testTaskList"
+	identity := "testIdentity"
+	requestID := "requestID"
+
+	// a retried start returns the completed run it created, regardless of the reuse policy
+	options := []workflow.WorkflowIdReusePolicy{
+		workflow.WorkflowIdReusePolicyAllowDuplicateFailedOnly,
+		workflow.WorkflowIdReusePolicyAllowDuplicate,
+		workflow.WorkflowIdReusePolicyRejectDuplicate,
+	}
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Times(len(options))
+	s.mockShardManager.On("UpdateShard", mock.Anything).Return(nil).Times(len(options))
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(nil, &persistence.WorkflowExecutionAlreadyStartedError{
+		Msg:            "random message",
+		StartRequestID: requestID,
+		RunID:          runID,
+		State:          persistence.WorkflowStateCompleted,
+		CloseStatus:    persistence.WorkflowCloseStatusCompleted,
+	}).Times(len(options))
+	s.mockHistoryMgr.On("DeleteWorkflowExecutionHistory", mock.Anything).Return(nil).Times(len(options))
+
+	for _, option := range options {
+		resp, err := s.historyEngine.StartWorkflowExecution(&h.StartWorkflowExecutionRequest{
+			DomainUUID: common.StringPtr(domainID),
+			StartRequest: &workflow.StartWorkflowExecutionRequest{
+				Domain:                              common.StringPtr(domainID),
+				WorkflowId:                          common.StringPtr(workflowID),
+				WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr(workflowType)},
+				TaskList:                            &workflow.TaskList{Name: common.StringPtr(taskList)},
+				ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+				TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+				Identity:                            common.StringPtr(identity),
+				RequestId:                           common.StringPtr(requestID),
+				WorkflowIdReusePolicy:               &option,
+			},
+		})
+		s.Nil(err)
+		s.Equal(runID, resp.GetRunId())
+	}
+}
+
+func (s *engine2Suite) TestStartWorkflowExecution_StillRunning_EmptyRequestIDNotDeduped() {
+	domainID := "domainId"
+	workflowID := "workflowID"
+	runID := "runID"
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockShardManager.On("UpdateShard", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(nil, &persistence.WorkflowExecutionAlreadyStartedError{
+		Msg:            "random message",
+		StartRequestID: "",
+		RunID:          runID,
+		State:          persistence.WorkflowStateRunning,
+		CloseStatus:    persistence.WorkflowCloseStatusNone,
+	}).Once()
+	s.mockHistoryMgr.On("DeleteWorkflowExecutionHistory", mock.Anything).Return(nil).Once()
+
+	resp, err := s.historyEngine.StartWorkflowExecution(&h.StartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          common.StringPtr(workflowID),
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("workflowType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr("testIdentity"),
+		},
+	})
+	s.Nil(resp)
+	startedErr, ok := err.(*workflow.WorkflowExecutionAlreadyStartedError)
+	s.True(ok)
+	s.True(startedErr.GetRunning())
+	s.Equal(runID, startedErr.GetRunId())
+}
+
 func (s *engine2Suite) TestStartWorkflowExecution_NotRunning_PrevFail() {
 	domainID := "domainId"
 	workflowID := "workflowID"
@@ -904,8 +2137,18 @@ func (s *engine2Suite) TestStartWorkflowExecution_NotRunning_PrevFail() {
 		persistence.WorkflowCloseStatusTerminated,
 		persistence.WorkflowCloseStatusTimedOut,
 	}
+	expectedCloseStatuses := []workflow.WorkflowExecutionCloseStatus{
+		workflow.WorkflowExecutionCloseStatusFailed,
+		workflow.WorkflowExecutionCloseStatusCanceled,
+		workflow.WorkflowExecutionCloseStatusTerminated,
+		workflow.WorkflowExecutionCloseStatusTimedOut,
+	}
 	runIDs := []string{"1", "2", "3", "4"}
 
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
 	for i, closeState := range closeStates {
 
 		s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Times(len(expecedErrs))
@@ -948,9 +2191,12 @@ func (s *engine2Suite) TestStartWorkflowExecution_NotRunning_PrevFail() {
 			})
 
 			if expecedErrs[j] {
-				if _, ok := err.(*workflow.WorkflowExecutionAlreadyStartedError); !ok {
+				startedErr, ok := err.(*workflow.WorkflowExecutionAlreadyStartedError)
+				if !ok {
 					s.Fail("return err is not *shared.WorkflowExecutionAlreadyStartedError")
 				}
+				s.False(startedErr.GetRunning())
+				s.Equal(expectedCloseStatuses[i], startedErr.GetCloseStatus())
 				s.Nil(resp)
 			} else {
 				s.Nil(err)
@@ -1035,6 +2281,57 @@ func (s *engine2Suite) TestSignalWithStartWorkflowExecution_WorkflowNotExist() {
 	s.NotNil(resp.GetRunId())
 }
 
+func (s *engine2Suite) TestSignalWithStartWorkflowExecution_KeyID() {
+	domainID := "domainId"
+	workflowID := "wId"
+	keyID := "testKeyID"
+	sRequest := &h.SignalWithStartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalWithStartRequest: &workflow.SignalWithStartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          common.StringPtr(workflowID),
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("workflowType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr("testIdentity"),
+			SignalName:                          common.StringPtr("my signal name"),
+			KeyId:                               common.StringPtr(keyID),
+		},
+	}
+	matchKeyID := mock.MatchedBy(func(request *persistence.AppendHistoryEventsRequest) bool {
+		return request.KeyID == keyID
+	})
+
+	// the workflow is started with the key id of the request
+	notExistErr := &workflow.EntityNotExistsError{Message: "Workflow not exist"}
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(nil, notExistErr).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", matchKeyID).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.MatchedBy(func(request *persistence.CreateWorkflowExecutionRequest) bool {
+		return request.KeyID == keyID
+	})).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+
+	resp, err := s.historyEngine.SignalWithStartWorkflowExecution(sRequest)
+	s.Nil(err)
+	s.NotNil(resp.GetRunId())
+
+	// the signal of a running workflow is appended with the key id of the workflow
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	msBuilder.executionInfo.KeyID = keyID
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: validRunID}
+
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", matchKeyID).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	resp, err = s.historyEngine.SignalWithStartWorkflowExecution(sRequest)
+	s.Nil(err)
+	s.Equal(validRunID, resp.GetRunId())
+}
+
 func (s *engine2Suite) TestSignalWithStartWorkflowExecution_WorkflowNotRunning() {
 	sRequest := &h.SignalWithStartWorkflowExecutionRequest{}
 	_, err := s.historyEngine.SignalWithStartWorkflowExecution(sRequest)
@@ -1080,6 +2377,133 @@ func (s *engine2Suite) TestSignalWithStartWorkflowExecution_WorkflowNotRunning()
 	s.NotEqual(runID, resp.GetRunId())
 }
 
+func (s *engine2Suite) TestSignalWithStartWorkflowExecution_JustSignal_Batched() {
+	domainID := "domainId"
+	workflowID := "wId"
+	runID := validRunID
+	deliveredRequestID := uuid.New()
+	newRequestID := uuid.New()
+	sRequest := &h.SignalWithStartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalWithStartRequest: &workflow.SignalWithStartWorkflowExecutionRequest{
+			Domain:     common.StringPtr(domainID),
+			WorkflowId: common.StringPtr(workflowID),
+			Identity:   common.StringPtr("testIdentity"),
+			Signals: []*workflow.BatchedSignal{
+				{SignalName: common.StringPtr("signal1"), RequestId: common.StringPtr(deliveredRequestID)},
+				{SignalName: common.StringPtr("signal2"), RequestId: common.StringPtr(newRequestID)},
+				{SignalName: common.StringPtr("signal3")},
+			},
+		},
+	}
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	ms := createMutableState(msBuilder)
+	ms.SignalRequestedIDs = map[string]struct{}{deliveredRequestID: {}}
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: runID}
+
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+
+	// the signal delivered before is skipped, the others are recorded in a single update
+	resp, err := s.historyEngine.SignalWithStartWorkflowExecution(sRequest)
+	s.Nil(err)
+	s.Equal(runID, resp.GetRunId())
+	s.Equal(int64(2), updateRequest.ExecutionInfo.SignalCount)
+	s.Equal("signal3", updateRequest.ExecutionInfo.LastSignalName)
+	s.Equal([]string{newRequestID}, updateRequest.UpsertSignalRequestedIDs)
+
+	// delivering only duplicates does not update the execution
+	sRequest.SignalWithStartRequest.Signals = sRequest.SignalWithStartRequest.Signals[:2]
+	resp, err = s.historyEngine.SignalWithStartWorkflowExecution(sRequest)
+	s.Nil(err)
+	s.Equal(runID, resp.GetRunId())
+}
+
+func (s *engine2Suite) TestSignalWithStartWorkflowExecution_WorkflowNotExist_Batched() {
+	domainID := "domainId"
+	requestID := uuid.New()
+	sRequest := &h.SignalWithStartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalWithStartRequest: &workflow.SignalWithStartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          common.StringPtr("wId"),
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("workflowType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr("testIdentity"),
+			SignalName:                          common.StringPtr("signal1"),
+			Signals: []*workflow.BatchedSignal{
+				{SignalName: common.StringPtr("signal2"), RequestId: common.StringPtr(requestID)},
+				{SignalName: common.StringPtr("signal3"), RequestId: common.StringPtr(requestID)},
+			},
+		},
+	}
+
+	notExistErr := &workflow.EntityNotExistsError{Message: "Workflow not exist"}
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(nil, notExistErr).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	var createRequest *persistence.CreateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(
+		&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Run(func(args mock.Arguments) {
+		createRequest = args.Get(0).(*persistence.CreateWorkflowExecutionRequest)
+	}).Once()
+
+	resp, err := s.historyEngine.SignalWithStartWorkflowExecution(sRequest)
+	s.Nil(err)
+	s.NotNil(resp.GetRunId())
+	s.Equal(int64(2), createRequest.SignalCount)
+	s.Equal("signal2", createRequest.LastSignalName)
+	s.Equal([]string{requestID}, createRequest.SignalRequestedIDs)
+}
+
+func (s *engine2Suite) TestSignalWithStartWorkflowExecution_BatchedSignalLimits() {
+	domainID := "domainId"
+	newRequest := func(signals []*workflow.BatchedSignal) *h.SignalWithStartWorkflowExecutionRequest {
+		return &h.SignalWithStartWorkflowExecutionRequest{
+			DomainUUID: common.StringPtr(domainID),
+			SignalWithStartRequest: &workflow.SignalWithStartWorkflowExecutionRequest{
+				Domain:     common.StringPtr(domainID),
+				WorkflowId: common.StringPtr("wId"),
+				SignalName: common.StringPtr("signal"),
+				Signals:    signals,
+			},
+		}
+	}
+
+	maxSignals := s.config.MaxSignalWithStartSignals
+	maxInputSize := s.config.MaxSignalWithStartInputSize
+	defer func() {
+		s.config.MaxSignalWithStartSignals = maxSignals
+		s.config.MaxSignalWithStartInputSize = maxInputSize
+	}()
+	s.config.MaxSignalWithStartSignals = 2
+	s.config.MaxSignalWithStartInputSize = 10
+
+	_, err := s.historyEngine.SignalWithStartWorkflowExecution(newRequest([]*workflow.BatchedSignal{
+		{SignalName: common.StringPtr("signal1")},
+		{SignalName: common.StringPtr("signal2")},
+	}))
+	s.IsType(&workflow.BadRequestError{}, err)
+
+	_, err = s.historyEngine.SignalWithStartWorkflowExecution(newRequest([]*workflow.BatchedSignal{
+		{SignalName: common.StringPtr("signal1"), Input: []byte("12345678901")},
+	}))
+	s.IsType(&workflow.BadRequestError{}, err)
+
+	_, err = s.historyEngine.SignalWithStartWorkflowExecution(newRequest([]*workflow.BatchedSignal{
+		{SignalName: common.StringPtr("signal1"), RequestId: common.StringPtr("not a uuid")},
+	}))
+	s.IsType(&workflow.BadRequestError{}, err)
+}
+
 func (s *engine2Suite) getBuilder(domainID string, we workflow.WorkflowExecution) *mutableStateBuilder {
 	context, release, err := s.historyEngine.historyCache.getOrCreateWorkflowExecution(domainID, we)
 	if err != nil {
@@ -30,6 +30,14 @@ import (
 	"github.com/uber/cadence/common/persistence"
 )
 
+// Outcomes of the executions of a BulkTerminateRequest
+const (
+	BulkTerminateOutcomeTerminated BulkTerminateOutcome = iota
+	BulkTerminateOutcomeAlreadyClosed
+	BulkTerminateOutcomeNotFound
+	BulkTerminateOutcomeFailed
+)
+
 type (
 	workflowIdentifier struct {
 		domainID   string
@@ -57,13 +65,13 @@ type (
 			request *h.DescribeWorkflowExecutionRequest) (*workflow.DescribeWorkflowExecutionResponse, error)
 		RecordDecisionTaskStarted(request *h.RecordDecisionTaskStartedRequest) (*h.RecordDecisionTaskStartedResponse, error)
 		RecordActivityTaskStarted(request *h.RecordActivityTaskStartedRequest) (*h.RecordActivityTaskStartedResponse, error)
-		RespondDecisionTaskCompleted(ctx context.Context, request *h.RespondDecisionTaskCompletedRequest) error
+		RespondDecisionTaskCompleted(ctx context.Context, request *h.RespondDecisionTaskCompletedRequest) (*h.RespondDecisionTaskCompletedResponse, error)
 		RespondDecisionTaskFailed(request *h.RespondDecisionTaskFailedRequest) error
 		RespondActivityTaskCompleted(request *h.RespondActivityTaskCompletedRequest) error
 		RespondActivityTaskFailed(request *h.RespondActivityTaskFailedRequest) error
 		RespondActivityTaskCanceled(request *h.RespondActivityTaskCanceledRequest) error
 		RecordActivityTaskHeartbeat(request *h.RecordActivityTaskHeartbeatRequest) (*workflow.RecordActivityTaskHeartbeatResponse, error)
-		RequestCancelWorkflowExecution(request *h.RequestCancelWorkflowExecutionRequest) error
+		RequestCancelWorkflowExecution(request *h.RequestCancelWorkflowExecutionRequest) (*workflow.RequestCancelWorkflowExecutionResponse, error)
 		SignalWorkflowExecution(request *h.SignalWorkflowExecutionRequest) error
 		SignalWithStartWorkflowExecution(request *h.SignalWithStartWorkflowExecutionRequest) (
 			*workflow.StartWorkflowExecutionResponse, error)
@@ -72,6 +80,175 @@ type (
 		ScheduleDecisionTask(request *h.ScheduleDecisionTaskRequest) error
 		RecordChildExecutionCompleted(request *h.RecordChildExecutionCompletedRequest) error
 		ReplicateEvents(request *h.ReplicateEventsRequest) error
+		ValidateDecisions(domainID string, decisions []*workflow.Decision) ([]*DecisionValidationResult, error)
+		GetShardStats() *ShardStats
+		GetQueueLag() *QueueLag
+		GetWorkflowExecutionRawHistory(request *RawHistoryRequest) (*RawHistoryResponse, error)
+		GetWorkflowExecutionHistoryWindow(request *HistoryWindowRequest) (*workflow.History, error)
+		GetHistoryEvent(domainID string, execution workflow.WorkflowExecution, eventID int64) (
+			*workflow.HistoryEvent, error)
+		ForceFailDecisionTask(domainID string, execution workflow.WorkflowExecution, identity string) error
+		SetWorkflowExecutionPaused(domainID string, execution workflow.WorkflowExecution, paused bool) error
+		SetWorkflowExecutionOperatorTags(domainID string, execution workflow.WorkflowExecution,
+			setTags map[string]string, clearTags []string) error
+		ForceDeleteWorkflowExecution(request *ForceDeleteRequest) error
+		TerminateAndStart(request *TerminateAndStartRequest) (*workflow.StartWorkflowExecutionResponse, error)
+		ReconcileCurrentExecution(domainID, workflowID string) (bool, error)
+		RedriveTransferTasks(domainID string, execution workflow.WorkflowExecution) (int, error)
+		GetActivityScheduledEvent(domainID string, execution workflow.WorkflowExecution, scheduleID int64) (
+			*workflow.ActivityTaskScheduledEventAttributes, error)
+		DescribeActivity(domainID string, execution workflow.WorkflowExecution, activityID string) (
+			*ActivityDescription, error)
+		GetWorkflowExecutionResult(domainID string, execution workflow.WorkflowExecution) (
+			*WorkflowExecutionResult, error)
+		PauseTimerProcessing(duration time.Duration) time.Time
+		ResumeTimerProcessing()
+		ListOpenExecutions(pageSize int, nextPageToken []byte) (*persistence.ListOpenExecutionsResponse, error)
+	}
+
+	// RawHistoryRequest is used to read the history of a workflow execution as it is stored, so it can be
+	// re-applied on another cluster
+	RawHistoryRequest struct {
+		DomainID  string
+		Execution workflow.WorkflowExecution
+		// FirstEventID is the first event to read, inclusive
+		FirstEventID int64
+		// NextEventID is the event to stop reading at, exclusive, zero reads up to the end of the history
+		NextEventID int64
+		// MinVersion and MaxVersion bound the failover version of returned batches, inclusive, nil leaves the
+		// range open on that side
+		MinVersion    *int64
+		MaxVersion    *int64
+		PageSize      int
+		NextPageToken []byte
+	}
+
+	// HistoryWindowRequest is used to read the events around an event of interest without paging through the
+	// history from its start
+	HistoryWindowRequest struct {
+		DomainID  string
+		Execution workflow.WorkflowExecution
+		// EventID is the event at the center of the window
+		EventID int64
+		// Radius is the number of events returned on each side of EventID, the window is cut at the ends of the
+		// history
+		Radius int64
+	}
+
+	// ForceDeleteRequest is used by operators to delete a workflow execution which cannot be closed through the
+	// normal APIs
+	ForceDeleteRequest struct {
+		DomainID string
+		// Execution must carry the exact run id, the current run is never resolved for a delete
+		Execution workflow.WorkflowExecution
+		// Confirm must be set for the delete to go ahead
+		Confirm bool
+		// Force allows deleting a running execution whose mutable state can still be loaded
+		Force    bool
+		Identity string
+	}
+
+	// TerminateAndStartRequest is used by operators to replace the running execution of a workflow with a new run
+	TerminateAndStartRequest struct {
+		DomainID string
+		// RunID of the run to terminate, the current run is terminated when it is empty
+		RunID    string
+		Reason   string
+		Details  []byte
+		Identity string
+		// StartRequest starts the new run, its workflow id is the one of the terminated run
+		StartRequest *workflow.StartWorkflowExecutionRequest
+	}
+
+	// WorkflowExecutionResult is the outcome of a closed workflow execution, only the fields recorded by its close
+	// event are set
+	WorkflowExecutionResult struct {
+		RunID       string
+		CloseStatus workflow.WorkflowExecutionCloseStatus
+		// Result of a completed execution
+		Result []byte
+		// Reason of a failed or terminated execution
+		Reason string
+		// Details of a failed, canceled or terminated execution
+		Details []byte
+		// NewExecutionRunID is the run an execution continued as new to
+		NewExecutionRunID string
+		// TimeoutType of a timed out execution
+		TimeoutType *workflow.TimeoutType
+	}
+
+	// BulkTerminateRequest is used to terminate a list of executions of a domain, resolved by the caller through
+	// visibility, with the same reason and identity
+	BulkTerminateRequest struct {
+		DomainID   string
+		Executions []workflow.WorkflowExecution
+		Reason     string
+		Identity   string
+	}
+
+	// BulkTerminateOutcome tells what became of a single execution of a BulkTerminateRequest
+	BulkTerminateOutcome int
+
+	// BulkTerminateResult is the outcome of terminating a single execution of a BulkTerminateRequest, Err is only set
+	// for executions which failed to terminate
+	BulkTerminateResult struct {
+		Execution workflow.WorkflowExecution
+		Outcome   BulkTerminateOutcome
+		Err       error
+	}
+
+	// ActivityDescription is the state of a single pending activity of a workflow execution
+	ActivityDescription struct {
+		ActivityID   string
+		ScheduleID   int64
+		ActivityType *workflow.ActivityType
+		// TaskList is the task list the activity is dispatched to
+		TaskList               string
+		State                  workflow.PendingActivityState
+		ScheduledTime          time.Time
+		ScheduleToStartTimeout int32
+		ScheduleToCloseTimeout int32
+		StartToCloseTimeout    int32
+		HeartbeatTimeout       int32
+		// StartedTime and StartedIdentity are only set once the activity is started
+		StartedTime     time.Time
+		StartedIdentity string
+		// LastHeartbeatTime and HeartbeatDetails are only set once the activity reported a heartbeat
+		LastHeartbeatTime time.Time
+		HeartbeatDetails  []byte
+		// CheckpointSequenceNumber is the sequence number of the last progress checkpoint reported by heartbeat
+		CheckpointSequenceNumber int64
+	}
+
+	// RawHistoryResponse is the response to RawHistoryRequest
+	RawHistoryResponse struct {
+		Batches []*persistence.RawHistoryBatch
+		// ReplicationState of the execution when the history was read, nil for executions which are not replicated
+		ReplicationState *persistence.ReplicationState
+		// NextEventID is the event the read stopped at
+		NextEventID   int64
+		NextPageToken []byte
+	}
+
+	// QueueLag shows how far the queue processors of a shard trail the tasks generated by the shard, keyed by the
+	// cluster the processor works for
+	QueueLag struct {
+		ShardID int
+		// TransferTaskIDLag is the distance between the last transfer task id handed out by the shard and the ack
+		// level of the transfer queue processor
+		TransferTaskIDLag map[string]int64
+		// TimerLag is how long the oldest timer task loaded by the timer queue processor has been due, zero when no
+		// loaded timer task is overdue
+		TimerLag map[string]time.Duration
+		// TimerProcessingPausedUntil is the time at which paused timer processing resumes on its own, zero when
+		// timer processing is not paused
+		TimerProcessingPausedUntil time.Time
+	}
+
+	// DecisionValidationResult is the outcome of validating a single decision, a nil Cause means the decision is valid
+	DecisionValidationResult struct {
+		Cause   *workflow.DecisionTaskFailedCause
+		Message string
 	}
 
 	// EngineFactory is used to create an instance of sharded history engine
@@ -100,6 +277,7 @@ type (
 	queueTaskInfo interface {
 		GetTaskID() int64
 		GetTaskType() int
+		GetDomainID() string
 	}
 
 	processor interface {
@@ -121,6 +299,10 @@ type (
 		common.Daemon
 		NotifyNewTimers(clusterName string, timerTask []persistence.Task)
 		SetCurrentTime(clusterName string, currentTime time.Time)
+		getTimerLag(clusterName string) time.Duration
+		pause(duration time.Duration) time.Time
+		resume()
+		getPausedUntil() (time.Time, bool)
 	}
 
 	timerProcessor interface {
@@ -135,6 +317,7 @@ type (
 		completeTimerTask(timerTask *persistence.TimerTaskInfo)
 		getAckLevel() TimerSequenceID
 		updateAckLevel()
+		getOldestPendingTimer() (time.Time, bool)
 	}
 
 	historyEventNotifier interface {
@@ -24,15 +24,20 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/uber/cadence/.gen/go/history"
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/cluster"
+	"github.com/uber/cadence/common/logging"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/mocks"
 	"github.com/uber/cadence/common/persistence"
@@ -47,6 +52,8 @@ import (
 	"github.com/uber-go/tally"
 	"github.com/uber/cadence/common/messaging"
 	"github.com/uber/cadence/common/service"
+	"go.uber.org/yarpc/api/encoding"
+	"go.uber.org/yarpc/api/transport"
 )
 
 type (
@@ -208,6 +215,196 @@ func (s *engineSuite) TestGetMutableStateSync() {
 	s.Equal(int64(4), response.GetNextEventId())
 }
 
+func (s *engineSuite) TestGetMutableState_BypassCache() {
+	ctx := context.Background()
+	domainID := "domainId"
+	execution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("test-get-mutable-state-bypass-cache"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tasklist := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, execution, "wType", tasklist, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tasklist, identity)
+	cachedState := createMutableState(msBuilder)
+	// the execution moved on in persistence behind the back of the cache
+	addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, di.StartedID, nil, identity)
+	persistedState := createMutableState(msBuilder)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: cachedState}, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: persistedState}, nil).Times(3)
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(nil, &workflow.EntityNotExistsError{})
+
+	response, err := s.mockHistoryEngine.GetMutableState(ctx, &history.GetMutableStateRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Execution:  &execution,
+	})
+	s.Nil(err)
+	s.Equal(int64(4), response.GetNextEventId())
+	s.Nil(response.PersistedNextEventId)
+
+	// a read bypassing the cache reports the discrepancy but leaves the cache alone
+	response, err = s.mockHistoryEngine.GetMutableState(ctx, &history.GetMutableStateRequest{
+		DomainUUID:  common.StringPtr(domainID),
+		Execution:   &execution,
+		BypassCache: common.BoolPtr(true),
+	})
+	s.Nil(err)
+	s.Equal(int64(5), response.GetNextEventId())
+	s.Equal(int64(5), response.GetPersistedNextEventId())
+	s.True(response.GetStaleCacheDetected())
+	s.False(response.GetStaleCacheCorrected())
+	s.Equal(int64(4), s.getBuilder(domainID, execution).GetNextEventID())
+
+	// refreshing the cache corrects the discrepancy
+	response, err = s.mockHistoryEngine.GetMutableState(ctx, &history.GetMutableStateRequest{
+		DomainUUID:   common.StringPtr(domainID),
+		Execution:    &execution,
+		BypassCache:  common.BoolPtr(true),
+		RefreshCache: common.BoolPtr(true),
+	})
+	s.Nil(err)
+	s.Equal(int64(5), response.GetPersistedNextEventId())
+	s.True(response.GetStaleCacheDetected())
+	s.True(response.GetStaleCacheCorrected())
+	s.Equal(int64(5), s.getBuilder(domainID, execution).GetNextEventID())
+
+	response, err = s.mockHistoryEngine.GetMutableState(ctx, &history.GetMutableStateRequest{
+		DomainUUID:   common.StringPtr(domainID),
+		Execution:    &execution,
+		BypassCache:  common.BoolPtr(true),
+		RefreshCache: common.BoolPtr(true),
+	})
+	s.Nil(err)
+	s.False(response.GetStaleCacheDetected())
+	s.False(response.GetStaleCacheCorrected())
+}
+
+func (s *engineSuite) TestGetMutableState_ConsistencyLevel() {
+	ctx := context.Background()
+	domainID := "domainId"
+	execution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("test-get-mutable-state-consistency-level"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tasklist := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, execution, "wType", tasklist, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tasklist, identity)
+	cachedState := createMutableState(msBuilder)
+	// the execution moved on in persistence behind the back of the cache
+	addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, di.StartedID, nil, identity)
+	persistedState := createMutableState(msBuilder)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: cachedState}, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: persistedState}, nil).Once()
+
+	getNextEventID := func(consistencyLevel *history.ReadConsistencyLevel) int64 {
+		response, err := s.mockHistoryEngine.GetMutableState(ctx, &history.GetMutableStateRequest{
+			DomainUUID:       common.StringPtr(domainID),
+			Execution:        &execution,
+			ConsistencyLevel: consistencyLevel,
+		})
+		s.Nil(err)
+		return response.GetNextEventId()
+	}
+
+	// eventually consistent reads, the default, are served from the cache
+	s.Equal(int64(4), getNextEventID(nil))
+	s.Equal(int64(4), getNextEventID(history.ReadConsistencyLevelEventual.Ptr()))
+	// a strongly consistent read goes to persistence and refreshes the cache
+	s.Equal(int64(5), getNextEventID(history.ReadConsistencyLevelStrong.Ptr()))
+	s.Equal(int64(5), getNextEventID(history.ReadConsistencyLevelEventual.Ptr()))
+	s.Equal(int64(5), s.getBuilder(domainID, execution).GetNextEventID())
+}
+
+func (s *engineSuite) TestGetMutableState_StartAndCloseTime() {
+	ctx := context.Background()
+	domainID := "domainId"
+	tasklist := "testTaskList"
+	identity := "testIdentity"
+	startTime := time.Now().Add(-time.Hour)
+	closeTime := startTime.Add(time.Minute)
+
+	for _, closed := range []bool{false, true} {
+		execution := workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(fmt.Sprintf("test-get-mutable-state-times-%v", closed)),
+			RunId:      common.StringPtr(validRunID),
+		}
+		msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+		addWorkflowExecutionStartedEvent(msBuilder, execution, "wType", tasklist, []byte("input"), 100, 200, identity)
+		di := addDecisionTaskScheduledEvent(msBuilder)
+		addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tasklist, identity)
+		msBuilder.executionInfo.StartTimestamp = startTime
+		if closed {
+			msBuilder.executionInfo.State = persistence.WorkflowStateCompleted
+			msBuilder.executionInfo.CloseStatus = persistence.WorkflowCloseStatusCompleted
+			msBuilder.executionInfo.CloseTimestamp = closeTime
+			// the execution is updated after it closed, e.g. by the transfer task recording its close
+			msBuilder.executionInfo.LastUpdatedTimestamp = closeTime.Add(time.Minute)
+		}
+		ms := createMutableState(msBuilder)
+		gweResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gweResponse, nil).Once()
+
+		response, err := s.mockHistoryEngine.GetMutableState(ctx, &history.GetMutableStateRequest{
+			DomainUUID: common.StringPtr(domainID),
+			Execution:  &execution,
+		})
+		s.Nil(err)
+		s.Equal(startTime.UnixNano(), response.GetStartTime())
+		if closed {
+			s.Equal(closeTime.UnixNano(), response.GetCloseTime())
+		} else {
+			s.Nil(response.CloseTime)
+		}
+	}
+}
+
+func (s *engineSuite) TestGetMutableState_HasBufferedEvents() {
+	ctx := context.Background()
+	domainID := "domainId"
+	tasklist := "testTaskList"
+	identity := "testIdentity"
+
+	for _, buffered := range []bool{false, true} {
+		execution := workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(fmt.Sprintf("test-get-mutable-state-buffered-%v", buffered)),
+			RunId:      common.StringPtr(validRunID),
+		}
+		msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+		addWorkflowExecutionStartedEvent(msBuilder, execution, "wType", tasklist, []byte("input"), 100, 200, identity)
+		di := addDecisionTaskScheduledEvent(msBuilder)
+		addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tasklist, identity)
+		if buffered {
+			// signals received while the decision is in flight are buffered
+			msBuilder.AddWorkflowExecutionSignaled(&workflow.SignalWorkflowExecutionRequest{
+				SignalName: common.StringPtr("signal"),
+				Identity:   common.StringPtr(identity),
+			})
+		}
+		ms := createMutableState(msBuilder)
+		gweResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gweResponse, nil).Once()
+
+		response, err := s.mockHistoryEngine.GetMutableState(ctx, &history.GetMutableStateRequest{
+			DomainUUID: common.StringPtr(domainID),
+			Execution:  &execution,
+		})
+		s.Nil(err)
+		s.Equal(buffered, response.GetHasBufferedEvents())
+		s.Equal(msBuilder.GetNextEventID(), response.GetNextEventId())
+	}
+}
+
 func (s *engineSuite) TestGetMutableState_InvalidRunID() {
 	ctx := context.Background()
 	domainID := "domainId"
@@ -332,12 +529,144 @@ func (s *engineSuite) TestGetMutableStateLongPollTimeout() {
 	s.Equal(int64(4), *response.NextEventId)
 }
 
+func (s *engineSuite) TestGetWorkflowExecutionRawHistory() {
+	domainID := "domainId"
+	execution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("test-get-workflow-execution-raw-history"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tasklist := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, execution, "wType", tasklist, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tasklist, identity)
+	ms := createMutableState(msBuilder)
+	ms.ReplicationState = &persistence.ReplicationState{
+		CurrentVersion:      20,
+		StartVersion:        10,
+		LastWriteVersion:    20,
+		LastWriteEventID:    3,
+		LastReplicationInfo: map[string]*persistence.ReplicationInfo{},
+	}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: ms}, nil).Once()
+
+	serializer := persistence.NewJSONHistorySerializer()
+	newBatch := func(firstEventID, txID, version int64) *persistence.RawHistoryBatch {
+		event := &workflow.HistoryEvent{
+			EventId:   common.Int64Ptr(firstEventID),
+			Version:   common.Int64Ptr(version),
+			EventType: common.EventTypePtr(workflow.EventTypeMarkerRecorded),
+		}
+		serialized, err := serializer.Serialize(persistence.NewHistoryEventBatch(persistence.GetDefaultHistoryVersion(),
+			[]*workflow.HistoryEvent{event}))
+		s.Nil(err)
+		return &persistence.RawHistoryBatch{FirstEventID: firstEventID, TransactionID: txID, Events: *serialized}
+	}
+	batch1 := newBatch(1, 101, 10)
+	batch2 := newBatch(2, 102, 20)
+	batch3 := newBatch(3, 103, 30)
+	s.mockHistoryMgr.On("GetWorkflowExecutionRawHistory", &persistence.GetWorkflowExecutionHistoryRequest{
+		DomainID:      domainID,
+		Execution:     execution,
+		FirstEventID:  common.FirstEventID,
+		NextEventID:   int64(4),
+		PageSize:      10,
+		NextPageToken: []byte("token"),
+	}).Return(&persistence.GetWorkflowExecutionRawHistoryResponse{
+		Batches:       []*persistence.RawHistoryBatch{batch1, batch2, batch3},
+		NextPageToken: []byte("next-token"),
+	}, nil).Once()
+
+	response, err := s.mockHistoryEngine.GetWorkflowExecutionRawHistory(&RawHistoryRequest{
+		DomainID:      domainID,
+		Execution:     execution,
+		FirstEventID:  common.FirstEventID,
+		NextEventID:   100,
+		MinVersion:    common.Int64Ptr(15),
+		MaxVersion:    common.Int64Ptr(30),
+		PageSize:      10,
+		NextPageToken: []byte("token"),
+	})
+	s.Nil(err)
+	s.Equal([]*persistence.RawHistoryBatch{batch2, batch3}, response.Batches)
+	s.Equal(int64(4), response.NextEventID)
+	s.Equal([]byte("next-token"), response.NextPageToken)
+	s.NotNil(response.ReplicationState)
+	s.Equal(int64(20), response.ReplicationState.CurrentVersion)
+}
+
+func (s *engineSuite) TestGetWorkflowExecutionRawHistory_InvalidVersionRange() {
+	_, err := s.mockHistoryEngine.GetWorkflowExecutionRawHistory(&RawHistoryRequest{
+		DomainID: "domainId",
+		Execution: workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr("test-get-workflow-execution-raw-history"),
+			RunId:      common.StringPtr(validRunID),
+		},
+		MinVersion: common.Int64Ptr(2),
+		MaxVersion: common.Int64Ptr(1),
+	})
+	s.IsType(&workflow.BadRequestError{}, err)
+}
+
+func (s *engineSuite) TestGetMutableStateLongPollWaitTimeout() {
+	domainID := "domainId"
+	execution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("test-get-workflow-execution-event-id"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tasklist := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, execution, "wType", tasklist, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tasklist, identity)
+	ms := createMutableState(msBuilder)
+	gweResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	// right now the next event ID is 4
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gweResponse, nil).Once()
+
+	maxLongPollExpirationInterval := s.config.MaxLongPollExpirationInterval
+	defer func() { s.config.MaxLongPollExpirationInterval = maxLongPollExpirationInterval }()
+	s.config.MaxLongPollExpirationInterval = func(...dynamicconfig.FilterOption) time.Duration {
+		return time.Second
+	}
+
+	// the requested wait time is capped by the configured maximum, well below the default poll time
+	start := time.Now()
+	response, err := s.mockHistoryEngine.GetMutableState(context.Background(), &history.GetMutableStateRequest{
+		DomainUUID:          common.StringPtr(domainID),
+		Execution:           &execution,
+		ExpectedNextEventId: common.Int64Ptr(5),
+		WaitTimeoutSeconds:  common.Int32Ptr(60),
+	})
+	s.Nil(err)
+	s.Equal(int64(4), response.GetNextEventId())
+	s.True(time.Since(start) < s.config.LongPollExpirationInterval())
+
+	// cancellation of the caller still ends the poll before the requested wait time
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	_, err = s.mockHistoryEngine.GetMutableState(ctx, &history.GetMutableStateRequest{
+		DomainUUID:          common.StringPtr(domainID),
+		Execution:           &execution,
+		ExpectedNextEventId: common.Int64Ptr(5),
+		WaitTimeoutSeconds:  common.Int32Ptr(1),
+	})
+	s.Equal(context.DeadlineExceeded, err)
+	s.True(time.Since(start) < time.Second)
+}
+
 func (s *engineSuite) TestRespondDecisionTaskCompletedInvalidToken() {
 	domainID := "domainId"
 	invalidToken, _ := json.Marshal("bad token")
 	identity := "testIdentity"
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken:        invalidToken,
@@ -362,7 +691,7 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedIfNoExecution() {
 
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil, &workflow.EntityNotExistsError{}).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken: taskToken,
@@ -384,7 +713,7 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedIfGetExecutionFailed() {
 
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil, errors.New("FAILED")).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken: taskToken,
@@ -422,7 +751,7 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedUpdateExecutionFailed() {
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(errors.New("FAILED")).Once()
 	s.mockShardManager.On("UpdateShard", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken: taskToken,
@@ -458,7 +787,7 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedIfTaskCompleted() {
 
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken: taskToken,
@@ -492,7 +821,7 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedIfTaskNotStarted() {
 
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken: taskToken,
@@ -578,7 +907,7 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedConflictOnUpdate() {
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken:        taskToken,
@@ -651,10 +980,10 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedMaxAttemptsExceeded() {
 		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
 		s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 		s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(
-			&persistence.ConditionFailedError{}).Once()
+			&persistence.ConditionFailedError{Msg: "Request Condition: 4, Actual Value: 6"}).Once()
 	}
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken:        taskToken,
@@ -665,6 +994,15 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedMaxAttemptsExceeded() {
 	})
 	s.NotNil(err)
 	s.Equal(ErrMaxAttemptsExceeded, err)
+
+	context, release, err := s.mockHistoryEngine.historyCache.getOrCreateWorkflowExecution(domainID, we)
+	s.Nil(err)
+	defer release(nil)
+	lastConflict := context.getLastConflict()
+	s.NotNil(lastConflict)
+	s.Equal(logging.TagValueStoreOperationUpdateWorkflowExecution, lastConflict.operation)
+	s.Equal(msBuilder.GetNextEventID(), lastConflict.condition)
+	s.Equal("Request Condition: 4, Actual Value: 6", lastConflict.details)
 }
 
 func (s *engineSuite) TestRespondDecisionTaskCompletedCompleteWorkflowFailed() {
@@ -727,7 +1065,7 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedCompleteWorkflowFailed() {
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken:        taskToken,
@@ -811,7 +1149,7 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedFailWorkflowFailed() {
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken:        taskToken,
@@ -881,7 +1219,7 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedBadDecisionAttributes() {
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken:        taskToken,
@@ -894,301 +1232,430 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedBadDecisionAttributes() {
 	s.IsType(&workflow.BadRequestError{}, err)
 }
 
-func (s *engineSuite) TestRespondDecisionTaskCompletedSingleActivityScheduledDecision() {
+func (s *engineSuite) TestRespondDecisionTaskCompletedFailedDecisionBackoff() {
+	policy := backoff.NewExponentialRetryPolicy(time.Second)
+	policy.SetExpirationInterval(backoff.NoInterval)
+	s.mockHistoryEngine.decisionBackoffPolicy = policy
+
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
 		RunId:      common.StringPtr(validRunID),
 	}
 	tl := "testTaskList"
-	taskToken, _ := json.Marshal(&common.TaskToken{
-		WorkflowID: "wId",
-		RunID:      we.GetRunId(),
-		ScheduleID: 2,
-	})
 	identity := "testIdentity"
-	executionContext := []byte("context")
-	input := []byte("input")
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
-	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 25, 200, identity)
 	di := addDecisionTaskScheduledEvent(msBuilder)
 	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
 
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: di.ScheduleID,
+	})
+
+	// Decision with nil attributes
 	decisions := []*workflow.Decision{{
-		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeScheduleActivityTask),
-		ScheduleActivityTaskDecisionAttributes: &workflow.ScheduleActivityTaskDecisionAttributes{
-			ActivityId:   common.StringPtr("activity1"),
-			ActivityType: &workflow.ActivityType{Name: common.StringPtr("activity_type1")},
-			TaskList:     &workflow.TaskList{Name: &tl},
-			Input:        input,
-			ScheduleToCloseTimeoutSeconds: common.Int32Ptr(100),
-			ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
-			StartToCloseTimeoutSeconds:    common.Int32Ptr(50),
-			HeartbeatTimeoutSeconds:       common.Int32Ptr(5),
-		},
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeCompleteWorkflowExecution),
 	}}
 
-	ms := createMutableState(msBuilder)
-	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	for i := 0; i < 2; i++ {
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	}
 
-	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
-	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
-			TaskToken:        taskToken,
-			Decisions:        decisions,
-			ExecutionContext: executionContext,
-			Identity:         &identity,
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
 		},
 	})
-	s.Nil(err, s.printHistory(msBuilder))
-	executionBuilder := s.getBuilder(domainID, we)
-	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
-	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
-	s.Equal(executionContext, executionBuilder.executionInfo.ExecutionContext)
-	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
-	s.False(executionBuilder.HasPendingDecisionTask())
+	s.IsType(&workflow.BadRequestError{}, err)
 
-	activity1Attributes := s.getActivityScheduledEvent(executionBuilder, int64(5)).ActivityTaskScheduledEventAttributes
-	s.Equal("activity1", *activity1Attributes.ActivityId)
-	s.Equal("activity_type1", *activity1Attributes.ActivityType.Name)
-	s.Equal(int64(4), *activity1Attributes.DecisionTaskCompletedEventId)
-	s.Equal(tl, *activity1Attributes.TaskList.Name)
-	s.Equal(input, activity1Attributes.Input)
-	s.Equal(int32(100), *activity1Attributes.ScheduleToCloseTimeoutSeconds)
-	s.Equal(int32(10), *activity1Attributes.ScheduleToStartTimeoutSeconds)
-	s.Equal(int32(50), *activity1Attributes.StartToCloseTimeoutSeconds)
-	s.Equal(int32(5), *activity1Attributes.HeartbeatTimeoutSeconds)
+	s.NotNil(updateRequest)
+	s.Equal(0, len(updateRequest.TransferTasks))
+	s.Equal(1, len(updateRequest.TimerTasks))
+	backoffTask, ok := updateRequest.TimerTasks[0].(*persistence.DecisionBackoffTask)
+	s.True(ok)
+	s.Equal(int64(1), backoffTask.ScheduleAttempt)
+
+	executionBuilder := s.getBuilder(domainID, we)
+	s.True(executionBuilder.HasPendingDecisionTask())
+	pendingDecision, ok := executionBuilder.GetPendingDecision(backoffTask.EventID)
+	s.True(ok)
+	s.Equal(int64(1), pendingDecision.Attempt)
+	s.Equal(emptyEventID, pendingDecision.StartedID)
 }
 
-func (s *engineSuite) TestRespondDecisionTaskCompletedCompleteWorkflowSuccess() {
+func (s *engineSuite) TestRespondDecisionTaskCompletedChildWorkflowDepthExceeded() {
+	maxDepth := s.config.MaxChildWorkflowDepth
+	defer func() { s.config.MaxChildWorkflowDepth = maxDepth }()
+	s.config.MaxChildWorkflowDepth = 64
+
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
 		RunId:      common.StringPtr(validRunID),
 	}
 	tl := "testTaskList"
-	taskToken, _ := json.Marshal(&common.TaskToken{
-		WorkflowID: *we.WorkflowId,
-		RunID:      *we.RunId,
-		ScheduleID: 2,
-	})
 	identity := "testIdentity"
-	executionContext := []byte("context")
-	workflowResult := []byte("success")
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
-	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 25, 200, identity)
 	di := addDecisionTaskScheduledEvent(msBuilder)
 	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	msBuilder.executionInfo.Depth = s.config.MaxChildWorkflowDepth
+
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: di.ScheduleID,
+	})
 
 	decisions := []*workflow.Decision{{
-		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeCompleteWorkflowExecution),
-		CompleteWorkflowExecutionDecisionAttributes: &workflow.CompleteWorkflowExecutionDecisionAttributes{
-			Result: workflowResult,
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeStartChildWorkflowExecution),
+		StartChildWorkflowExecutionDecisionAttributes: &workflow.StartChildWorkflowExecutionDecisionAttributes{
+			WorkflowId:   common.StringPtr("child-wId"),
+			WorkflowType: &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:     &workflow.TaskList{Name: common.StringPtr(tl)},
+			ChildPolicy:  common.ChildPolicyPtr(workflow.ChildPolicyTerminate),
 		},
 	}}
 
-	ms := createMutableState(msBuilder)
-	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	for i := 0; i < 2; i++ {
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	}
 
-	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
-	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
-	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
-		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
-			TaskToken:        taskToken,
-			Decisions:        decisions,
-			ExecutionContext: executionContext,
-			Identity:         &identity,
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
 		},
 	})
-	s.Nil(err, s.printHistory(msBuilder))
+	s.IsType(&workflow.BadRequestError{}, err)
+
+	s.NotNil(updateRequest)
+	s.Equal(0, len(updateRequest.UpsertChildExecutionInfos))
+	for _, task := range updateRequest.TransferTasks {
+		_, ok := task.(*persistence.StartChildExecutionTask)
+		s.False(ok)
+	}
 	executionBuilder := s.getBuilder(domainID, we)
-	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
-	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
-	s.Equal(executionContext, executionBuilder.executionInfo.ExecutionContext)
-	s.Equal(persistence.WorkflowStateCompleted, executionBuilder.executionInfo.State)
-	s.False(executionBuilder.HasPendingDecisionTask())
+	s.True(executionBuilder.HasPendingDecisionTask())
+	s.Equal(0, len(executionBuilder.pendingChildExecutionInfoIDs))
 }
 
-func (s *engineSuite) TestRespondDecisionTaskCompletedFailWorkflowSuccess() {
+func (s *engineSuite) TestRespondDecisionTaskCompletedChildStartShardOverloaded() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
 		RunId:      common.StringPtr(validRunID),
 	}
 	tl := "testTaskList"
-	taskToken, _ := json.Marshal(&common.TaskToken{
-		WorkflowID: *we.WorkflowId,
-		RunID:      *we.RunId,
-		ScheduleID: 2,
-	})
 	identity := "testIdentity"
-	executionContext := []byte("context")
-	details := []byte("fail workflow details")
-	reason := "fail workflow reason"
+
+	maxBacklog := s.config.ChildStartMaxTransferTaskBacklog
+	defer func() { s.config.ChildStartMaxTransferTaskBacklog = maxBacklog }()
+	s.config.ChildStartMaxTransferTaskBacklog = 50
+	shard := s.mockHistoryEngine.shard
+	s.Nil(shard.UpdateTransferAckLevel(shard.GetTransferMaxReadLevel() - 100))
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
-	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 25, 200, identity)
 	di := addDecisionTaskScheduledEvent(msBuilder)
 	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
 
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: di.ScheduleID,
+	})
+
 	decisions := []*workflow.Decision{{
-		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeFailWorkflowExecution),
-		FailWorkflowExecutionDecisionAttributes: &workflow.FailWorkflowExecutionDecisionAttributes{
-			Reason:  &reason,
-			Details: details,
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeStartChildWorkflowExecution),
+		StartChildWorkflowExecutionDecisionAttributes: &workflow.StartChildWorkflowExecutionDecisionAttributes{
+			WorkflowId:   common.StringPtr("child-wId"),
+			WorkflowType: &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:     &workflow.TaskList{Name: common.StringPtr(tl)},
+			ChildPolicy:  common.ChildPolicyPtr(workflow.ChildPolicyTerminate),
 		},
 	}}
 
-	ms := createMutableState(msBuilder)
-	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	for i := 0; i < 2; i++ {
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	}
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(nil, &workflow.EntityNotExistsError{})
 
-	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
-	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
-	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
-		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
-			TaskToken:        taskToken,
-			Decisions:        decisions,
-			ExecutionContext: executionContext,
-			Identity:         &identity,
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
 		},
 	})
-	s.Nil(err, s.printHistory(msBuilder))
+	s.IsType(&workflow.ServiceBusyError{}, err)
+
+	s.NotNil(updateRequest)
+	s.Equal(0, len(updateRequest.UpsertChildExecutionInfos))
 	executionBuilder := s.getBuilder(domainID, we)
-	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
-	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
-	s.Equal(executionContext, executionBuilder.executionInfo.ExecutionContext)
-	s.Equal(persistence.WorkflowStateCompleted, executionBuilder.executionInfo.State)
-	s.False(executionBuilder.HasPendingDecisionTask())
+	s.True(executionBuilder.HasPendingDecisionTask())
+	s.Equal(0, len(executionBuilder.pendingChildExecutionInfoIDs))
 }
 
-func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowSuccess() {
+func (s *engineSuite) TestRespondDecisionTaskCompletedSelfReferentialChildWorkflow() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
 		RunId:      common.StringPtr(validRunID),
 	}
 	tl := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 25, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
 	taskToken, _ := json.Marshal(&common.TaskToken{
 		WorkflowID: *we.WorkflowId,
 		RunID:      *we.RunId,
-		ScheduleID: 2,
+		ScheduleID: di.ScheduleID,
+	})
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeStartChildWorkflowExecution),
+		StartChildWorkflowExecutionDecisionAttributes: &workflow.StartChildWorkflowExecutionDecisionAttributes{
+			WorkflowId:   we.WorkflowId,
+			WorkflowType: &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:     &workflow.TaskList{Name: common.StringPtr(tl)},
+			ChildPolicy:  common.ChildPolicyPtr(workflow.ChildPolicyTerminate),
+		},
+	}}
+
+	for i := 0; i < 2; i++ {
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	}
+
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
+		},
 	})
+	s.IsType(&workflow.BadRequestError{}, err)
+
+	s.NotNil(updateRequest)
+	s.Equal(0, len(updateRequest.UpsertChildExecutionInfos))
+	executionBuilder := s.getBuilder(domainID, we)
+	s.True(executionBuilder.HasPendingDecisionTask())
+	s.Equal(0, len(executionBuilder.pendingChildExecutionInfoIDs))
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedSameWorkflowIDChildInOtherDomain() {
+	domainID := "domainId"
+	targetDomainID := "targetDomainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
 	identity := "testIdentity"
-	executionContext := []byte("context")
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
-	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 25, 200, identity)
 	di := addDecisionTaskScheduledEvent(msBuilder)
 	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
 
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: di.ScheduleID,
+	})
+
 	decisions := []*workflow.Decision{{
-		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeSignalExternalWorkflowExecution),
-		SignalExternalWorkflowExecutionDecisionAttributes: &workflow.SignalExternalWorkflowExecutionDecisionAttributes{
-			Domain: common.StringPtr(domainID),
-			Execution: &workflow.WorkflowExecution{
-				WorkflowId: we.WorkflowId,
-				RunId:      we.RunId,
-			},
-			SignalName: common.StringPtr("signal"),
-			Input:      []byte("test input"),
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeStartChildWorkflowExecution),
+		StartChildWorkflowExecutionDecisionAttributes: &workflow.StartChildWorkflowExecutionDecisionAttributes{
+			Domain:       common.StringPtr("targetDomain"),
+			WorkflowId:   we.WorkflowId,
+			WorkflowType: &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:     &workflow.TaskList{Name: common.StringPtr(tl)},
+			ChildPolicy:  common.ChildPolicyPtr(workflow.ChildPolicyTerminate),
 		},
 	}}
 
 	ms := createMutableState(msBuilder)
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
-	domainInfo := &persistence.DomainInfo{
-		ID:   domainID,
-		Name: domainID,
-	}
-
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: targetDomainID, Name: "targetDomain"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
-	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
-	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
-		&persistence.GetDomainResponse{
-			Config: &persistence.DomainConfig{Retention: 1},
-			Info:   domainInfo,
-		}, nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
-			TaskToken:        taskToken,
-			Decisions:        decisions,
-			ExecutionContext: executionContext,
-			Identity:         &identity,
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
 		},
 	})
-	s.Nil(err, s.printHistory(msBuilder))
-	executionBuilder := s.getBuilder(domainID, we)
-	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
-	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
-	s.Equal(executionContext, executionBuilder.executionInfo.ExecutionContext)
+	s.Nil(err)
+
+	s.NotNil(updateRequest)
+	s.Equal(1, len(updateRequest.UpsertChildExecutionInfos))
+	startChildTasks := 0
+	for _, task := range updateRequest.TransferTasks {
+		if startChildTask, ok := task.(*persistence.StartChildExecutionTask); ok {
+			startChildTasks++
+			s.Equal(targetDomainID, startChildTask.TargetDomainID)
+			s.Equal(*we.WorkflowId, startChildTask.TargetWorkflowID)
+		}
+	}
+	s.Equal(1, startChildTasks)
 }
 
-func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowFailed() {
+func (s *engineSuite) TestRespondDecisionTaskCompletedCancelPendingChildWorkflow() {
 	domainID := "domainId"
+	targetDomainID := "targetDomainId"
 	we := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
-		RunId:      common.StringPtr("invalid run id"),
+		RunId:      common.StringPtr(validRunID),
 	}
 	tl := "testTaskList"
-	taskToken, _ := json.Marshal(&common.TaskToken{
-		WorkflowID: we.GetWorkflowId(),
-		RunID:      we.GetRunId(),
-		ScheduleID: 2,
-	})
 	identity := "testIdentity"
-	executionContext := []byte("context")
+	childWorkflowID := "child-wId"
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
-	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 25, 200, identity)
 	di := addDecisionTaskScheduledEvent(msBuilder)
-	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		*decisionStartedEvent.EventId, nil, identity)
+	childInitiatedEvent, _ := addStartChildWorkflowExecutionInitiatedEvent(msBuilder,
+		*decisionCompletedEvent.EventId, uuid.New(), "targetDomain", childWorkflowID, "childType", tl, nil, 100, 10)
+	di2 := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di2.ScheduleID, tl, identity)
+
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: di2.ScheduleID,
+	})
 
 	decisions := []*workflow.Decision{{
-		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeSignalExternalWorkflowExecution),
-		SignalExternalWorkflowExecutionDecisionAttributes: &workflow.SignalExternalWorkflowExecutionDecisionAttributes{
-			Domain: common.StringPtr(domainID),
-			Execution: &workflow.WorkflowExecution{
-				WorkflowId: we.WorkflowId,
-				RunId:      we.RunId,
-			},
-			SignalName: common.StringPtr("signal"),
-			Input:      []byte("test input"),
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeRequestCancelExternalWorkflowExecution),
+		RequestCancelExternalWorkflowExecutionDecisionAttributes: &workflow.RequestCancelExternalWorkflowExecutionDecisionAttributes{
+			Domain:            common.StringPtr("targetDomain"),
+			WorkflowId:        common.StringPtr(childWorkflowID),
+			ChildWorkflowOnly: common.BoolPtr(true),
 		},
 	}}
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: targetDomainID, Name: "targetDomain"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+
+	var appendRequest *persistence.AppendHistoryEventsRequest
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		appendRequest = args.Get(0).(*persistence.AppendHistoryEventsRequest)
+	}).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
-			TaskToken:        taskToken,
-			Decisions:        decisions,
-			ExecutionContext: executionContext,
-			Identity:         &identity,
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
 		},
 	})
+	s.Nil(err)
 
-	s.EqualError(err, "BadRequestError{Message: RunID is not valid UUID.}")
+	// the pending child is dropped instead of sending a cancel request to a run which does not exist
+	s.NotNil(updateRequest)
+	s.NotNil(updateRequest.DeleteChildExecutionInfo)
+	s.Equal(*childInitiatedEvent.EventId, *updateRequest.DeleteChildExecutionInfo)
+	s.Equal(0, len(updateRequest.UpsertRequestCancelInfos))
+	for _, task := range updateRequest.TransferTasks {
+		_, isCancelTask := task.(*persistence.CancelExecutionTask)
+		s.False(isCancelTask)
+	}
+
+	s.NotNil(appendRequest)
+	serializer, _ := s.mockHistoryEngine.hSerializerFactory.Get(appendRequest.Events.EncodingType)
+	history, err := serializer.Deserialize(appendRequest.Events)
+	s.Nil(err)
+	eventTypes := make(map[workflow.EventType]*workflow.HistoryEvent)
+	for _, event := range history.Events {
+		eventTypes[event.GetEventType()] = event
+	}
+	s.Contains(eventTypes, workflow.EventTypeRequestCancelExternalWorkflowExecutionInitiated)
+	cancelRequestedEvent, ok := eventTypes[workflow.EventTypeExternalWorkflowExecutionCancelRequested]
+	s.True(ok)
+	s.Equal(childWorkflowID,
+		cancelRequestedEvent.ExternalWorkflowExecutionCancelRequestedEventAttributes.WorkflowExecution.GetWorkflowId())
+	startFailedEvent, ok := eventTypes[workflow.EventTypeStartChildWorkflowExecutionFailed]
+	s.True(ok)
+	s.Equal(workflow.ChildWorkflowExecutionFailedCauseCanceledBeforeStart,
+		startFailedEvent.StartChildWorkflowExecutionFailedEventAttributes.GetCause())
+	s.Equal(*childInitiatedEvent.EventId,
+		startFailedEvent.StartChildWorkflowExecutionFailedEventAttributes.GetInitiatedEventId())
 }
 
-func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowFailed_UnKnownDomain() {
+func (s *engineSuite) TestRespondDecisionTaskCompletedSingleActivityScheduledDecision() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
@@ -1196,12 +1663,13 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowFail
 	}
 	tl := "testTaskList"
 	taskToken, _ := json.Marshal(&common.TaskToken{
-		WorkflowID: *we.WorkflowId,
-		RunID:      *we.RunId,
+		WorkflowID: "wId",
+		RunID:      we.GetRunId(),
 		ScheduleID: 2,
 	})
 	identity := "testIdentity"
 	executionContext := []byte("context")
+	input := []byte("input")
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
 	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
@@ -1209,15 +1677,16 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowFail
 	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
 
 	decisions := []*workflow.Decision{{
-		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeSignalExternalWorkflowExecution),
-		SignalExternalWorkflowExecutionDecisionAttributes: &workflow.SignalExternalWorkflowExecutionDecisionAttributes{
-			Domain: common.StringPtr(domainID),
-			Execution: &workflow.WorkflowExecution{
-				WorkflowId: we.WorkflowId,
-				RunId:      we.RunId,
-			},
-			SignalName: common.StringPtr("signal"),
-			Input:      []byte("test input"),
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeScheduleActivityTask),
+		ScheduleActivityTaskDecisionAttributes: &workflow.ScheduleActivityTaskDecisionAttributes{
+			ActivityId:   common.StringPtr("activity1"),
+			ActivityType: &workflow.ActivityType{Name: common.StringPtr("activity_type1")},
+			TaskList:     &workflow.TaskList{Name: &tl},
+			Input:        input,
+			ScheduleToCloseTimeoutSeconds: common.Int32Ptr(100),
+			ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+			StartToCloseTimeoutSeconds:    common.Int32Ptr(50),
+			HeartbeatTimeoutSeconds:       common.Int32Ptr(5),
 		},
 	}}
 
@@ -1225,10 +1694,10 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowFail
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
 
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
-	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
-		nil, errors.New("get foreign domain error")).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken:        taskToken,
@@ -1237,146 +1706,278 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowFail
 			Identity:         &identity,
 		},
 	})
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
+	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
+	s.Equal(executionContext, executionBuilder.executionInfo.ExecutionContext)
+	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
+	s.False(executionBuilder.HasPendingDecisionTask())
 
-	s.EqualError(err, "InternalServiceError{Message: Unable to signal workflow across domain: domainId.}")
+	activity1Attributes := s.getActivityScheduledEvent(executionBuilder, int64(5)).ActivityTaskScheduledEventAttributes
+	s.Equal("activity1", *activity1Attributes.ActivityId)
+	s.Equal("activity_type1", *activity1Attributes.ActivityType.Name)
+	s.Equal(int64(4), *activity1Attributes.DecisionTaskCompletedEventId)
+	s.Equal(tl, *activity1Attributes.TaskList.Name)
+	s.Equal(input, activity1Attributes.Input)
+	s.Equal(int32(100), *activity1Attributes.ScheduleToCloseTimeoutSeconds)
+	s.Equal(int32(10), *activity1Attributes.ScheduleToStartTimeoutSeconds)
+	s.Equal(int32(50), *activity1Attributes.StartToCloseTimeoutSeconds)
+	s.Equal(int32(5), *activity1Attributes.HeartbeatTimeoutSeconds)
 }
 
-func (s *engineSuite) TestRespondActivityTaskCompletedInvalidToken() {
+func (s *engineSuite) TestRespondDecisionTaskCompletedReturnsActivityScheduledEventIDs() {
 	domainID := "domainId"
-	invalidToken, _ := json.Marshal("bad token")
-	identity := "testIdentity"
-
-	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
-		DomainUUID: common.StringPtr(domainID),
-		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
-			TaskToken: invalidToken,
-			Result:    nil,
-			Identity:  &identity,
-		},
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: "wId",
+		RunID:      we.GetRunId(),
+		ScheduleID: 2,
 	})
+	identity := "testIdentity"
 
-	s.NotNil(err)
-	s.IsType(&workflow.BadRequestError{}, err)
-}
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
 
-func (s *engineSuite) TestRespondActivityTaskCompletedIfNoExecution() {
-	domainID := "domainId"
-	taskToken, _ := json.Marshal(&common.TaskToken{
-		WorkflowID: "wId",
-		RunID:      validRunID,
-		ScheduleID: 2,
-	})
-	identity := "testIdentity"
+	scheduleActivity := func(activityID string) *workflow.Decision {
+		return &workflow.Decision{
+			DecisionType: common.DecisionTypePtr(workflow.DecisionTypeScheduleActivityTask),
+			ScheduleActivityTaskDecisionAttributes: &workflow.ScheduleActivityTaskDecisionAttributes{
+				ActivityId:                    common.StringPtr(activityID),
+				ActivityType:                  &workflow.ActivityType{Name: common.StringPtr("activity_type1")},
+				TaskList:                      &workflow.TaskList{Name: &tl},
+				ScheduleToCloseTimeoutSeconds: common.Int32Ptr(100),
+				ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+				StartToCloseTimeoutSeconds:    common.Int32Ptr(50),
+				HeartbeatTimeoutSeconds:       common.Int32Ptr(5),
+			},
+		}
+	}
+	decisions := []*workflow.Decision{
+		scheduleActivity("activity1"),
+		{
+			DecisionType: common.DecisionTypePtr(workflow.DecisionTypeStartTimer),
+			StartTimerDecisionAttributes: &workflow.StartTimerDecisionAttributes{
+				TimerId:                   common.StringPtr("timer"),
+				StartToFireTimeoutSeconds: common.Int64Ptr(10),
+			},
+		},
+		scheduleActivity("activity2"),
+	}
 
-	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil, &workflow.EntityNotExistsError{}).Once()
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+	resp, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
-		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken: taskToken,
+			Decisions: decisions,
 			Identity:  &identity,
 		},
 	})
-	s.NotNil(err)
-	s.IsType(&workflow.EntityNotExistsError{}, err)
+	s.Nil(err, s.printHistory(msBuilder))
+	s.Equal(map[string]int64{"activity1": 5, "activity2": 7}, resp.ActivityScheduledEventIds)
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal("activity2", *s.getActivityScheduledEvent(executionBuilder, 7).ActivityTaskScheduledEventAttributes.ActivityId)
 }
 
-func (s *engineSuite) TestRespondActivityTaskCompletedIfNoRunID() {
+func (s *engineSuite) TestRespondDecisionTaskCompletedActivityScheduledWithPriority() {
 	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
 	taskToken, _ := json.Marshal(&common.TaskToken{
 		WorkflowID: "wId",
+		RunID:      we.GetRunId(),
 		ScheduleID: 2,
 	})
 	identity := "testIdentity"
 
-	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(nil, &workflow.EntityNotExistsError{}).Once()
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
 
-	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
-		DomainUUID: common.StringPtr(domainID),
-		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
-			TaskToken: taskToken,
-			Identity:  &identity,
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeScheduleActivityTask),
+		ScheduleActivityTaskDecisionAttributes: &workflow.ScheduleActivityTaskDecisionAttributes{
+			ActivityId:                    common.StringPtr("activity1"),
+			ActivityType:                  &workflow.ActivityType{Name: common.StringPtr("activity_type1")},
+			TaskList:                      &workflow.TaskList{Name: &tl},
+			ScheduleToCloseTimeoutSeconds: common.Int32Ptr(100),
+			ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+			StartToCloseTimeoutSeconds:    common.Int32Ptr(50),
+			HeartbeatTimeoutSeconds:       common.Int32Ptr(5),
+			Priority:                      common.Int32Ptr(3),
 		},
-	})
-	s.NotNil(err)
-	s.IsType(&workflow.EntityNotExistsError{}, err)
-}
+	}}
 
-func (s *engineSuite) TestRespondActivityTaskCompletedIfGetExecutionFailed() {
-	domainID := "domainId"
-	taskToken, _ := json.Marshal(&common.TaskToken{
-		WorkflowID: "wId",
-		RunID:      validRunID,
-		ScheduleID: 2,
-	})
-	identity := "testIdentity"
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
 
-	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil, errors.New("FAILED")).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(func(request *persistence.UpdateWorkflowExecutionRequest) bool {
+		for _, task := range request.TransferTasks {
+			if activityTask, ok := task.(*persistence.ActivityTask); ok {
+				return activityTask.Priority == 3
+			}
+		}
+		return false
+	})).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
-		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken: taskToken,
+			Decisions: decisions,
 			Identity:  &identity,
 		},
 	})
-	s.EqualError(err, "FAILED")
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int32(3), s.getActivityScheduledEvent(executionBuilder, int64(5)).ActivityTaskScheduledEventAttributes.GetPriority())
+	ai, ok := executionBuilder.GetActivityInfo(int64(5))
+	s.True(ok)
+	s.Equal(int32(3), ai.Priority)
 }
 
-func (s *engineSuite) TestRespondActivityTaskCompletedIfNoAIdProvided() {
+func (s *engineSuite) TestRespondDecisionTaskCompletedActivityTaskListResolved() {
 	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
 	taskToken, _ := json.Marshal(&common.TaskToken{
 		WorkflowID: "wId",
-		ScheduleID: common.EmptyEventID,
+		RunID:      we.GetRunId(),
+		ScheduleID: 2,
 	})
 	identity := "testIdentity"
 
+	activityTaskListResolver := s.config.ActivityTaskListResolver
+	defer func() { s.config.ActivityTaskListResolver = activityTaskListResolver }()
+	s.config.ActivityTaskListResolver = func(domainID string, taskList string) string {
+		return taskList + "-partition-2"
+	}
+
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeScheduleActivityTask),
+		ScheduleActivityTaskDecisionAttributes: &workflow.ScheduleActivityTaskDecisionAttributes{
+			ActivityId:                    common.StringPtr("activity1"),
+			ActivityType:                  &workflow.ActivityType{Name: common.StringPtr("activity_type1")},
+			TaskList:                      &workflow.TaskList{Name: &tl},
+			ScheduleToCloseTimeoutSeconds: common.Int32Ptr(100),
+			ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+			StartToCloseTimeoutSeconds:    common.Int32Ptr(50),
+			HeartbeatTimeoutSeconds:       common.Int32Ptr(5),
+		},
+	}}
+
 	ms := createMutableState(msBuilder)
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
-	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: validRunID}
 
-	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(func(request *persistence.UpdateWorkflowExecutionRequest) bool {
+		for _, task := range request.TransferTasks {
+			if activityTask, ok := task.(*persistence.ActivityTask); ok {
+				return activityTask.TaskList == "testTaskList-partition-2"
+			}
+		}
+		return false
+	})).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
-		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken: taskToken,
+			Decisions: decisions,
 			Identity:  &identity,
 		},
 	})
-	s.EqualError(err, "BadRequestError{Message: Neither ActivityID nor ScheduleID is provided}")
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(tl, s.getActivityScheduledEvent(executionBuilder, int64(5)).ActivityTaskScheduledEventAttributes.TaskList.GetName())
+	ai, ok := executionBuilder.GetActivityInfo(int64(5))
+	s.True(ok)
+	s.Equal("testTaskList-partition-2", ai.TaskList)
 }
 
-func (s *engineSuite) TestRespondActivityTaskCompletedIfNoAidFound() {
+func (s *engineSuite) TestRespondDecisionTaskCompletedActivityScheduledNegativePriority() {
 	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
 	taskToken, _ := json.Marshal(&common.TaskToken{
 		WorkflowID: "wId",
-		ScheduleID: common.EmptyEventID,
-		ActivityID: "aid",
+		RunID:      we.GetRunId(),
+		ScheduleID: 2,
 	})
 	identity := "testIdentity"
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeScheduleActivityTask),
+		ScheduleActivityTaskDecisionAttributes: &workflow.ScheduleActivityTaskDecisionAttributes{
+			ActivityId:                    common.StringPtr("activity1"),
+			ActivityType:                  &workflow.ActivityType{Name: common.StringPtr("activity_type1")},
+			TaskList:                      &workflow.TaskList{Name: &tl},
+			ScheduleToCloseTimeoutSeconds: common.Int32Ptr(100),
+			ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+			StartToCloseTimeoutSeconds:    common.Int32Ptr(50),
+			HeartbeatTimeoutSeconds:       common.Int32Ptr(5),
+			Priority:                      common.Int32Ptr(-1),
+		},
+	}}
+
 	ms := createMutableState(msBuilder)
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
-	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: validRunID}
 
-	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
-		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken: taskToken,
+			Decisions: decisions,
 			Identity:  &identity,
 		},
 	})
-	s.EqualError(err, "BadRequestError{Message: No such activityID: aid\n}")
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	_, ok := executionBuilder.GetActivityInfo(int64(5))
+	s.False(ok)
+	s.True(executionBuilder.HasPendingDecisionTask())
 }
 
-func (s *engineSuite) TestRespondActivityTaskCompletedUpdateExecutionFailed() {
+func (s *engineSuite) TestRespondDecisionTaskCompletedActivityScheduledWithDispatchDeadline() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
@@ -1384,46 +1985,55 @@ func (s *engineSuite) TestRespondActivityTaskCompletedUpdateExecutionFailed() {
 	}
 	tl := "testTaskList"
 	taskToken, _ := json.Marshal(&common.TaskToken{
-		WorkflowID: *we.WorkflowId,
-		RunID:      *we.RunId,
-		ScheduleID: 5,
+		WorkflowID: "wId",
+		RunID:      we.GetRunId(),
+		ScheduleID: 2,
 	})
 	identity := "testIdentity"
-	activityID := "activity1_id"
-	activityType := "activity_type1"
-	activityInput := []byte("input1")
-	activityResult := []byte("activity result")
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
 	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
 	di := addDecisionTaskScheduledEvent(msBuilder)
-	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
-	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
-		*decisionStartedEvent.EventId, nil, identity)
-	activityScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId, activityID,
-		activityType, tl, activityInput, 100, 10, 5)
-	addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeScheduleActivityTask),
+		ScheduleActivityTaskDecisionAttributes: &workflow.ScheduleActivityTaskDecisionAttributes{
+			ActivityId:                    common.StringPtr("activity1"),
+			ActivityType:                  &workflow.ActivityType{Name: common.StringPtr("activity_type1")},
+			TaskList:                      &workflow.TaskList{Name: &tl},
+			ScheduleToCloseTimeoutSeconds: common.Int32Ptr(100),
+			ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+			StartToCloseTimeoutSeconds:    common.Int32Ptr(50),
+			HeartbeatTimeoutSeconds:       common.Int32Ptr(5),
+			DispatchDeadlineSeconds:       common.Int32Ptr(5),
+		},
+	}}
 
 	ms := createMutableState(msBuilder)
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
 
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
-	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(errors.New("FAILED")).Once()
-	s.mockShardManager.On("UpdateShard", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
-		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken: taskToken,
-			Result:    activityResult,
+			Decisions: decisions,
 			Identity:  &identity,
 		},
 	})
-	s.EqualError(err, "FAILED")
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int32(5), s.getActivityScheduledEvent(executionBuilder, int64(5)).ActivityTaskScheduledEventAttributes.GetDispatchDeadlineSeconds())
+	ai, ok := executionBuilder.GetActivityInfo(int64(5))
+	s.True(ok)
+	s.Equal(int32(5), ai.DispatchDeadline)
 }
 
-func (s *engineSuite) TestRespondActivityTaskCompletedIfTaskCompleted() {
+func (s *engineSuite) TestRespondDecisionTaskCompletedActivityScheduledDispatchDeadlineExceedsScheduleToStart() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
@@ -1431,47 +2041,54 @@ func (s *engineSuite) TestRespondActivityTaskCompletedIfTaskCompleted() {
 	}
 	tl := "testTaskList"
 	taskToken, _ := json.Marshal(&common.TaskToken{
-		WorkflowID: *we.WorkflowId,
-		RunID:      *we.RunId,
-		ScheduleID: 5,
+		WorkflowID: "wId",
+		RunID:      we.GetRunId(),
+		ScheduleID: 2,
 	})
 	identity := "testIdentity"
-	activityID := "activity1_id"
-	activityType := "activity_type1"
-	activityInput := []byte("input1")
-	activityResult := []byte("activity result")
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
 	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
 	di := addDecisionTaskScheduledEvent(msBuilder)
-	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
-	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
-		*decisionStartedEvent.EventId, nil, identity)
-	activityScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId, activityID,
-		activityType, tl, activityInput, 100, 10, 5)
-	activityStartedEvent := addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
-	addActivityTaskCompletedEvent(msBuilder, *activityScheduledEvent.EventId, *activityStartedEvent.EventId,
-		activityResult, identity)
-	addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeScheduleActivityTask),
+		ScheduleActivityTaskDecisionAttributes: &workflow.ScheduleActivityTaskDecisionAttributes{
+			ActivityId:                    common.StringPtr("activity1"),
+			ActivityType:                  &workflow.ActivityType{Name: common.StringPtr("activity_type1")},
+			TaskList:                      &workflow.TaskList{Name: &tl},
+			ScheduleToCloseTimeoutSeconds: common.Int32Ptr(100),
+			ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+			StartToCloseTimeoutSeconds:    common.Int32Ptr(50),
+			HeartbeatTimeoutSeconds:       common.Int32Ptr(5),
+			DispatchDeadlineSeconds:       common.Int32Ptr(15),
+		},
+	}}
 
 	ms := createMutableState(msBuilder)
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
 
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
-		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken: taskToken,
-			Result:    activityResult,
+			Decisions: decisions,
 			Identity:  &identity,
 		},
 	})
-	s.NotNil(err)
-	s.IsType(&workflow.EntityNotExistsError{}, err)
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	_, ok := executionBuilder.GetActivityInfo(int64(5))
+	s.False(ok)
+	s.True(executionBuilder.HasPendingDecisionTask())
 }
 
-func (s *engineSuite) TestRespondActivityTaskCompletedIfTaskNotStarted() {
+func (s *engineSuite) TestRespondDecisionTaskCompletedCompleteWorkflowSuccess() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
@@ -1481,27 +2098,2836 @@ func (s *engineSuite) TestRespondActivityTaskCompletedIfTaskNotStarted() {
 	taskToken, _ := json.Marshal(&common.TaskToken{
 		WorkflowID: *we.WorkflowId,
 		RunID:      *we.RunId,
-		ScheduleID: 5,
+		ScheduleID: 2,
 	})
 	identity := "testIdentity"
-	activityID := "activity1_id"
-	activityType := "activity_type1"
-	activityInput := []byte("input1")
-	activityResult := []byte("activity result")
+	executionContext := []byte("context")
+	workflowResult := []byte("success")
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
 	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
 	di := addDecisionTaskScheduledEvent(msBuilder)
-	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
-	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
-		*decisionStartedEvent.EventId, nil, identity)
-	addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId, activityID,
-		activityType, tl, activityInput, 100, 10, 5)
-
-	ms := createMutableState(msBuilder)
-	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
 
-	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeCompleteWorkflowExecution),
+		CompleteWorkflowExecutionDecisionAttributes: &workflow.CompleteWorkflowExecutionDecisionAttributes{
+			Result: workflowResult,
+		},
+	}}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken:        taskToken,
+			Decisions:        decisions,
+			ExecutionContext: executionContext,
+			Identity:         &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
+	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
+	s.Equal(executionContext, executionBuilder.executionInfo.ExecutionContext)
+	s.Equal(persistence.WorkflowStateCompleted, executionBuilder.executionInfo.State)
+	s.False(executionBuilder.HasPendingDecisionTask())
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedScheduleActivityAfterCompleteWorkflow() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeCompleteWorkflowExecution),
+		CompleteWorkflowExecutionDecisionAttributes: &workflow.CompleteWorkflowExecutionDecisionAttributes{
+			Result: []byte("success"),
+		},
+	}, {
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeScheduleActivityTask),
+		ScheduleActivityTaskDecisionAttributes: &workflow.ScheduleActivityTaskDecisionAttributes{
+			ActivityId:                    common.StringPtr("activity1"),
+			ActivityType:                  &workflow.ActivityType{Name: common.StringPtr("activity_type1")},
+			TaskList:                      &workflow.TaskList{Name: &tl},
+			Input:                         []byte("input1"),
+			ScheduleToCloseTimeoutSeconds: common.Int32Ptr(100),
+			ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+			StartToCloseTimeoutSeconds:    common.Int32Ptr(50),
+			HeartbeatTimeoutSeconds:       common.Int32Ptr(5),
+		},
+	}}
+
+	// the decision is failed on a reloaded mutable state
+	for i := 0; i < 2; i++ {
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	}
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
+		},
+	})
+	s.IsType(&workflow.BadRequestError{}, err)
+
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
+	s.Equal(0, len(executionBuilder.pendingActivityInfoIDs))
+	// only the decision failure is recorded, the retried decision is transient
+	s.Equal(int64(5), executionBuilder.executionInfo.NextEventID)
+	s.True(executionBuilder.HasPendingDecisionTask())
+	s.Equal(int64(1), executionBuilder.executionInfo.DecisionAttempt)
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedCompleteWorkflowCancelsPendingActivities() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		decisionStartedEvent.GetEventId(), nil, identity)
+	activity1ScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, decisionCompletedEvent.GetEventId(),
+		"activity1", "activity_type1", tl, nil, 100, 10, 5)
+	activity2ScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, decisionCompletedEvent.GetEventId(),
+		"activity2", "activity_type1", tl, nil, 100, 10, 5)
+	addActivityTaskStartedEvent(msBuilder, activity1ScheduledEvent.GetEventId(), tl, identity)
+	di = addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: we.GetWorkflowId(),
+		RunID:      we.GetRunId(),
+		ScheduleID: di.ScheduleID,
+	})
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeCompleteWorkflowExecution),
+		CompleteWorkflowExecutionDecisionAttributes: &workflow.CompleteWorkflowExecutionDecisionAttributes{
+			Result: []byte("success"),
+		},
+	}}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	scope := tally.NewTestScope("test", nil)
+	s.mockHistoryEngine.metricsClient = metrics.NewClient(scope, metrics.History)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(persistence.WorkflowStateCompleted, executionBuilder.executionInfo.State)
+	// decision completed, cancel requested and canceled for both activities, workflow completed
+	s.Equal(int64(16), executionBuilder.executionInfo.NextEventID)
+	_, isRunning := executionBuilder.GetActivityInfo(activity1ScheduledEvent.GetEventId())
+	s.False(isRunning)
+	_, isRunning = executionBuilder.GetActivityInfo(activity2ScheduledEvent.GetEventId())
+	s.False(isRunning)
+
+	counter := scope.Snapshot().Counters()["test.workflow-closed-with-pending-activities+operation=RespondDecisionTaskCompleted"]
+	s.NotNil(counter)
+	s.Equal(int64(1), counter.Value())
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedStickyTimeoutClamped() {
+	floor := s.config.StickyScheduleToStartTimeoutFloorInSecs
+	ceiling := s.config.StickyScheduleToStartTimeoutCeilingInSecs
+	defer func() {
+		s.config.StickyScheduleToStartTimeoutFloorInSecs = floor
+		s.config.StickyScheduleToStartTimeoutCeilingInSecs = ceiling
+	}()
+	s.config.StickyScheduleToStartTimeoutFloorInSecs = 1
+	s.config.StickyScheduleToStartTimeoutCeilingInSecs = 60
+
+	domainID := "domainId"
+	tl := "testTaskList"
+	stickyTl := "stickyTaskList"
+	identity := "testIdentity"
+
+	testCases := []struct {
+		requested int32
+		expected  int32
+	}{
+		{requested: 0, expected: s.config.StickyScheduleToStartTimeoutFloorInSecs},
+		{requested: 10, expected: 10},
+		{requested: 3600, expected: s.config.StickyScheduleToStartTimeoutCeilingInSecs},
+	}
+
+	for i, tc := range testCases {
+		we := workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(fmt.Sprintf("wId-%v", i)),
+			RunId:      common.StringPtr(validRunID),
+		}
+		taskToken, _ := json.Marshal(&common.TaskToken{
+			WorkflowID: *we.WorkflowId,
+			RunID:      *we.RunId,
+			ScheduleID: 2,
+		})
+
+		msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+		addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+		di := addDecisionTaskScheduledEvent(msBuilder)
+		addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+		s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+		s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+		_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+			DomainUUID: common.StringPtr(domainID),
+			CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+				TaskToken: taskToken,
+				Identity:  &identity,
+				StickyAttributes: &workflow.StickyExecutionAttributes{
+					WorkerTaskList:                &workflow.TaskList{Name: common.StringPtr(stickyTl)},
+					ScheduleToStartTimeoutSeconds: common.Int32Ptr(tc.requested),
+				},
+			},
+		})
+		s.Nil(err, s.printHistory(msBuilder))
+		executionBuilder := s.getBuilder(domainID, we)
+		s.Equal(stickyTl, executionBuilder.executionInfo.StickyTaskList)
+		s.Equal(tc.expected, executionBuilder.executionInfo.StickyScheduleToStartTimeout)
+	}
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedInvalidStickyTaskList() {
+	domainID := "domainId"
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	stickyTaskLists := []string{
+		"",
+		"   ",
+		strings.Repeat("a", s.config.MaxTaskListNameLength+1),
+		"sticky\x00TaskList",
+	}
+
+	for i, stickyTl := range stickyTaskLists {
+		we := workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(fmt.Sprintf("wId-%v", i)),
+			RunId:      common.StringPtr(validRunID),
+		}
+		taskToken, _ := json.Marshal(&common.TaskToken{
+			WorkflowID: *we.WorkflowId,
+			RunID:      *we.RunId,
+			ScheduleID: 2,
+		})
+
+		msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+		addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+		di := addDecisionTaskScheduledEvent(msBuilder)
+		addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+		msBuilder.executionInfo.StickyTaskList = "previousStickyTaskList"
+		msBuilder.executionInfo.StickyScheduleToStartTimeout = 10
+
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+		s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+		s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+		_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+			DomainUUID: common.StringPtr(domainID),
+			CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+				TaskToken: taskToken,
+				Identity:  &identity,
+				StickyAttributes: &workflow.StickyExecutionAttributes{
+					WorkerTaskList:                &workflow.TaskList{Name: common.StringPtr(stickyTl)},
+					ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+				},
+			},
+		})
+		s.Nil(err, s.printHistory(msBuilder))
+		executionBuilder := s.getBuilder(domainID, we)
+		s.Equal("", executionBuilder.executionInfo.StickyTaskList)
+		s.Equal(int32(0), executionBuilder.executionInfo.StickyScheduleToStartTimeout)
+		s.False(executionBuilder.isStickyTaskListEnabled())
+	}
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedDeniedBinaryChecksum() {
+	domainID := "domainId"
+	tl := "testTaskList"
+	stickyTl := "stickyTaskList"
+	identity := "testIdentity"
+
+	deniedBinaryChecksums := s.config.DeniedBinaryChecksums
+	defer func() { s.config.DeniedBinaryChecksums = deniedBinaryChecksums }()
+	s.config.DeniedBinaryChecksums = func(...dynamicconfig.FilterOption) string {
+		return "bad-checksum-1, bad-checksum-2"
+	}
+
+	testCases := []struct {
+		binaryChecksum string
+		stickyTaskList string
+	}{
+		{binaryChecksum: "good-checksum", stickyTaskList: stickyTl},
+		{binaryChecksum: "bad-checksum-2", stickyTaskList: ""},
+	}
+
+	for i, tc := range testCases {
+		we := workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(fmt.Sprintf("wId-%v", i)),
+			RunId:      common.StringPtr(validRunID),
+		}
+		taskToken, _ := json.Marshal(&common.TaskToken{
+			WorkflowID: *we.WorkflowId,
+			RunID:      *we.RunId,
+			ScheduleID: 2,
+		})
+
+		msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+		addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+		di := addDecisionTaskScheduledEvent(msBuilder)
+		addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+		s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+		s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+		ctx, call := encoding.NewInboundCall(context.Background())
+		err := call.ReadFromRequest(&transport.Request{
+			Headers: transport.NewHeaders().With(common.BinaryChecksumHeaderName, tc.binaryChecksum),
+		})
+		s.Nil(err)
+
+		_, err = s.mockHistoryEngine.RespondDecisionTaskCompleted(ctx, &history.RespondDecisionTaskCompletedRequest{
+			DomainUUID: common.StringPtr(domainID),
+			CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+				TaskToken: taskToken,
+				Identity:  &identity,
+				StickyAttributes: &workflow.StickyExecutionAttributes{
+					WorkerTaskList:                &workflow.TaskList{Name: common.StringPtr(stickyTl)},
+					ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+				},
+			},
+		})
+		s.Nil(err, s.printHistory(msBuilder))
+		executionBuilder := s.getBuilder(domainID, we)
+		s.Equal(tc.binaryChecksum, executionBuilder.executionInfo.BinaryChecksum)
+		s.Equal(tc.stickyTaskList, executionBuilder.executionInfo.StickyTaskList)
+
+		describeResponse, err := s.mockHistoryEngine.DescribeWorkflowExecution(&history.DescribeWorkflowExecutionRequest{
+			DomainUUID: common.StringPtr(domainID),
+			Request:    &workflow.DescribeWorkflowExecutionRequest{Execution: &we},
+		})
+		s.Nil(err)
+		s.Equal(tc.binaryChecksum, describeResponse.WorkflowExecutionInfo.GetBinaryChecksum())
+	}
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedRecordsResetPoint() {
+	domainID := "domainId"
+	tl := "testTaskList"
+	identity := "testIdentity"
+	binaryChecksum := "checksum-3"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+
+	maxResetPoints := s.config.MaxResetPoints
+	defer func() { s.config.MaxResetPoints = maxResetPoints }()
+	s.config.MaxResetPoints = 2
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	msBuilder.executionInfo.ResetPoints = []*persistence.ResetPoint{
+		{EventID: 1, BinaryChecksum: "checksum-1", Resettable: true},
+		{EventID: 2, BinaryChecksum: "checksum-2", Resettable: false},
+	}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	ctx, call := encoding.NewInboundCall(context.Background())
+	err := call.ReadFromRequest(&transport.Request{
+		Headers: transport.NewHeaders().With(common.BinaryChecksumHeaderName, binaryChecksum),
+	})
+	s.Nil(err)
+
+	_, err = s.mockHistoryEngine.RespondDecisionTaskCompleted(ctx, &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+
+	describeResponse, err := s.mockHistoryEngine.DescribeWorkflowExecution(&history.DescribeWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Request:    &workflow.DescribeWorkflowExecutionRequest{Execution: &we},
+	})
+	s.Nil(err)
+
+	// the oldest reset point is dropped to make room for the completed decision
+	resetPoints := describeResponse.WorkflowExecutionInfo.ResetPoints
+	s.Equal(2, len(resetPoints))
+	s.Equal(int64(2), resetPoints[0].GetEventId())
+	s.Equal("checksum-2", resetPoints[0].GetBinaryChecksum())
+	s.False(resetPoints[0].GetResettable())
+	s.Equal(int64(4), resetPoints[1].GetEventId())
+	s.Equal(binaryChecksum, resetPoints[1].GetBinaryChecksum())
+	s.True(resetPoints[1].GetResettable())
+	s.True(resetPoints[1].GetSafe())
+	s.NotZero(resetPoints[1].GetCreatedTime())
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedResetPointUnsafeWithStartedActivity() {
+	domainID := "domainId"
+	tl := "testTaskList"
+	identity := "testIdentity"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		*decisionStartedEvent.EventId, nil, identity)
+	msBuilder.executionInfo.ResetPoints = []*persistence.ResetPoint{
+		{EventID: *decisionCompletedEvent.EventId, Resettable: true, Safe: true},
+	}
+	activityScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId,
+		"activity1", "activity_type1", tl, []byte("input"), 100, 10, 5)
+	addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
+	di2 := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di2.ScheduleID, tl, identity)
+
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: di2.ScheduleID,
+	})
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(),
+		&history.RespondDecisionTaskCompletedRequest{
+			DomainUUID: common.StringPtr(domainID),
+			CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+				TaskToken: taskToken,
+				Identity:  &identity,
+			},
+		})
+	s.Nil(err, s.printHistory(msBuilder))
+
+	// the activity started before the completed decision would be lost by a reset there
+	describeResponse, err := s.mockHistoryEngine.DescribeWorkflowExecution(&history.DescribeWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Request:    &workflow.DescribeWorkflowExecutionRequest{Execution: &we},
+	})
+	s.Nil(err)
+	resetPoints := describeResponse.WorkflowExecutionInfo.ResetPoints
+	s.Equal(2, len(resetPoints))
+	s.True(resetPoints[0].GetSafe())
+	s.True(resetPoints[1].GetResettable())
+	s.False(resetPoints[1].GetSafe())
+
+	describeResponse, err = s.mockHistoryEngine.DescribeWorkflowExecution(&history.DescribeWorkflowExecutionRequest{
+		DomainUUID:          common.StringPtr(domainID),
+		Request:             &workflow.DescribeWorkflowExecutionRequest{Execution: &we},
+		OnlySafeResetPoints: common.BoolPtr(true),
+	})
+	s.Nil(err)
+	resetPoints = describeResponse.WorkflowExecutionInfo.ResetPoints
+	s.Equal(1, len(resetPoints))
+	s.Equal(*decisionCompletedEvent.EventId, resetPoints[0].GetEventId())
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedEmptyDecisionBackoff() {
+	domainID := "domainId"
+	tl := "testTaskList"
+	identity := "testIdentity"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+
+	emptyDecisionBackoff := s.config.EmptyDecisionBackoffInterval
+	defer func() { s.config.EmptyDecisionBackoffInterval = emptyDecisionBackoff }()
+	s.config.EmptyDecisionBackoffInterval = 5 * time.Second
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	msBuilder.AddWorkflowExecutionSignaled(&workflow.SignalWorkflowExecutionRequest{
+		SignalName: common.StringPtr("signal"),
+		Identity:   common.StringPtr(identity),
+	})
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+
+	// the signal schedules a decision, which is only dispatched once the backoff elapses
+	for _, task := range updateRequest.TransferTasks {
+		_, ok := task.(*persistence.DecisionTask)
+		s.False(ok)
+	}
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(7), executionBuilder.GetNextEventID())
+	s.True(executionBuilder.HasPendingDecisionTask())
+	di, ok := executionBuilder.GetPendingDecision(6)
+	s.True(ok)
+	var backoffTask *persistence.DecisionBackoffTask
+	for _, task := range updateRequest.TimerTasks {
+		if t, ok := task.(*persistence.DecisionBackoffTask); ok {
+			backoffTask = t
+		}
+	}
+	s.NotNil(backoffTask)
+	s.Equal(di.ScheduleID, backoffTask.EventID)
+	s.Equal(di.Attempt, backoffTask.ScheduleAttempt)
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedReturnNewDecisionTask() {
+	domainID := "domainId"
+	tl := "testTaskList"
+	stickyTl := "stickyTaskList"
+	identity := "testIdentity"
+
+	testCases := []struct {
+		stickyAttributes *workflow.StickyExecutionAttributes
+		returnedInline   bool
+	}{
+		{
+			stickyAttributes: &workflow.StickyExecutionAttributes{
+				WorkerTaskList:                &workflow.TaskList{Name: common.StringPtr(stickyTl)},
+				ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+			},
+			returnedInline: true,
+		},
+		{
+			stickyAttributes: nil,
+			returnedInline:   false,
+		},
+	}
+
+	for i, tc := range testCases {
+		we := workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(fmt.Sprintf("wId-%v", i)),
+			RunId:      common.StringPtr(validRunID),
+		}
+		taskToken, _ := json.Marshal(&common.TaskToken{
+			WorkflowID: *we.WorkflowId,
+			RunID:      *we.RunId,
+			ScheduleID: 2,
+		})
+
+		msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+		addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+		di := addDecisionTaskScheduledEvent(msBuilder)
+		addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+		// signal arriving while the decision is in flight gets buffered and requires a new decision
+		msBuilder.AddWorkflowExecutionSignaled(&workflow.SignalWorkflowExecutionRequest{
+			SignalName: common.StringPtr("signal"),
+			Identity:   common.StringPtr(identity),
+		})
+
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+		var updateRequest *persistence.UpdateWorkflowExecutionRequest
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+		s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+		s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+		}).Once()
+
+		resp, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+			DomainUUID: common.StringPtr(domainID),
+			CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+				TaskToken:             taskToken,
+				Identity:              &identity,
+				StickyAttributes:      tc.stickyAttributes,
+				ReturnNewDecisionTask: common.BoolPtr(true),
+			},
+		})
+		s.Nil(err, s.printHistory(msBuilder))
+		s.NotNil(resp)
+		s.NotNil(updateRequest)
+
+		hasDecisionTransferTask := false
+		for _, task := range updateRequest.TransferTasks {
+			if _, ok := task.(*persistence.DecisionTask); ok {
+				hasDecisionTransferTask = true
+			}
+		}
+		s.Equal(!tc.returnedInline, hasDecisionTransferTask)
+
+		executionBuilder := s.getBuilder(domainID, we)
+		newDecision, ok := executionBuilder.GetPendingDecision(executionBuilder.executionInfo.DecisionScheduleID)
+		s.True(ok)
+		if !tc.returnedInline {
+			s.Nil(resp.StartedResponse)
+			s.Equal(emptyEventID, newDecision.StartedID)
+			continue
+		}
+
+		s.NotNil(resp.StartedResponse)
+		s.Equal(newDecision.ScheduleID, resp.StartedResponse.GetScheduledEventId())
+		s.Equal(newDecision.StartedID, resp.StartedResponse.GetStartedEventId())
+		s.Equal(int64(3), resp.StartedResponse.GetPreviousStartedEventId())
+		s.True(resp.StartedResponse.GetStickyExecutionEnabled())
+		s.Equal("wType", resp.StartedResponse.WorkflowType.GetName())
+	}
+}
+
+func (s *engineSuite) TestForceFailDecisionTask() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+	operator := "testOperator"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+
+	var appendRequest *persistence.AppendHistoryEventsRequest
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		appendRequest = args.Get(0).(*persistence.AppendHistoryEventsRequest)
+	}).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	err := s.mockHistoryEngine.ForceFailDecisionTask(domainID, we, operator)
+	s.Nil(err)
+
+	s.NotNil(appendRequest)
+	serializer, _ := s.mockHistoryEngine.hSerializerFactory.Get(appendRequest.Events.EncodingType)
+	history, err := serializer.Deserialize(appendRequest.Events)
+	s.Nil(err)
+	s.Equal(1, len(history.Events))
+	failedEvent := history.Events[0]
+	s.Equal(workflow.EventTypeDecisionTaskFailed, failedEvent.GetEventType())
+	s.Equal(workflow.DecisionTaskFailedCauseForceFailedByOperator,
+		failedEvent.DecisionTaskFailedEventAttributes.GetCause())
+	s.Equal(operator, failedEvent.DecisionTaskFailedEventAttributes.GetIdentity())
+
+	// a new decision is scheduled for the execution
+	executionBuilder := s.getBuilder(domainID, we)
+	s.True(executionBuilder.HasPendingDecisionTask())
+	newDi, ok := executionBuilder.GetPendingDecision(executionBuilder.executionInfo.DecisionScheduleID)
+	s.True(ok)
+	s.Equal(emptyEventID, newDi.StartedID)
+	s.Equal(int64(1), newDi.Attempt)
+
+	// the new decision is not started, so there is nothing to fail
+	err = s.mockHistoryEngine.ForceFailDecisionTask(domainID, we, operator)
+	s.IsType(&workflow.BadRequestError{}, err)
+}
+
+func (s *engineSuite) TestForceFailDecisionTask_AttemptsExhausted() {
+	maxAttempts := s.config.MaxTransientDecisionAttempts
+	timeoutWorkflow := s.config.TimeoutWorkflowOnMaxDecisionAttempts
+	defer func() {
+		s.config.MaxTransientDecisionAttempts = maxAttempts
+		s.config.TimeoutWorkflowOnMaxDecisionAttempts = timeoutWorkflow
+	}()
+	s.config.MaxTransientDecisionAttempts = 3
+	s.config.TimeoutWorkflowOnMaxDecisionAttempts = true
+
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+	operator := "testOperator"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	// fail the decision twice, only the first failure is recorded in history
+	for i := 0; i < 2; i++ {
+		di := addDecisionTaskScheduledEvent(msBuilder)
+		addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+		msBuilder.AddDecisionTaskFailedEvent(di.ScheduleID, di.ScheduleID+1,
+			workflow.DecisionTaskFailedCauseUnhandledDecision, nil, identity)
+	}
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	s.Equal(int64(2), di.Attempt)
+	s.Equal(int64(5), di.ScheduleID)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+
+	describeResponse, err := s.mockHistoryEngine.DescribeWorkflowExecution(&history.DescribeWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Request:    &workflow.DescribeWorkflowExecutionRequest{Execution: &we},
+	})
+	s.Nil(err)
+	s.Equal(int64(2), describeResponse.WorkflowExecutionInfo.GetDecisionAttempt())
+	s.Equal(int32(3), describeResponse.WorkflowExecutionInfo.GetMaxDecisionAttempts())
+
+	var appendRequest *persistence.AppendHistoryEventsRequest
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		appendRequest = args.Get(0).(*persistence.AppendHistoryEventsRequest)
+	}).Once()
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+
+	err = s.mockHistoryEngine.ForceFailDecisionTask(domainID, we, operator)
+	s.Nil(err)
+
+	// the last attempt is recorded in history with its transient scheduled and started events
+	serializer, _ := s.mockHistoryEngine.hSerializerFactory.Get(appendRequest.Events.EncodingType)
+	history, err := serializer.Deserialize(appendRequest.Events)
+	s.Nil(err)
+	s.True(len(history.Events) >= 3)
+	s.Equal(workflow.EventTypeDecisionTaskScheduled, history.Events[0].GetEventType())
+	s.Equal(int64(5), history.Events[0].GetEventId())
+	s.Equal(int64(2), history.Events[0].DecisionTaskScheduledEventAttributes.GetAttempt())
+	s.Equal(workflow.EventTypeDecisionTaskStarted, history.Events[1].GetEventType())
+	s.Equal(workflow.EventTypeDecisionTaskFailed, history.Events[2].GetEventType())
+	s.Equal(int64(5), history.Events[2].DecisionTaskFailedEventAttributes.GetScheduledEventId())
+	s.Equal(int64(6), history.Events[2].DecisionTaskFailedEventAttributes.GetStartedEventId())
+
+	// the workflow is timed out right away
+	hasTimeoutTask := false
+	for _, task := range updateRequest.TimerTasks {
+		if _, ok := task.(*persistence.WorkflowTimeoutTask); ok {
+			hasTimeoutTask = true
+		}
+	}
+	s.True(hasTimeoutTask)
+
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(0), executionBuilder.executionInfo.DecisionAttempt)
+}
+
+func (s *engineSuite) TestGetWorkflowExecutionHistoryWindow() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	completedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, di.ScheduleID+1, nil, identity)
+	addActivityTaskScheduledEvent(msBuilder, completedEvent.GetEventId(), "activity1", "activity_type1", tl, nil,
+		100, 10, 5)
+
+	// all events are in a single batch starting before the window
+	serializedHistory, err := msBuilder.hBuilder.Serialize()
+	s.Nil(err)
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.MatchedBy(
+		func(request *persistence.GetWorkflowExecutionHistoryRequest) bool {
+			return request.FirstEventID == 3 && request.NextEventID == 6
+		})).Return(&persistence.GetWorkflowExecutionHistoryResponse{}, nil).Once()
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.MatchedBy(
+		func(request *persistence.GetWorkflowExecutionHistoryRequest) bool {
+			return request.FirstEventID == common.FirstEventID && request.NextEventID == 3
+		})).Return(&persistence.GetWorkflowExecutionHistoryResponse{
+		Events: []persistence.SerializedHistoryEventBatch{*serializedHistory},
+	}, nil).Once()
+
+	window, err := s.mockHistoryEngine.GetWorkflowExecutionHistoryWindow(&HistoryWindowRequest{
+		DomainID:  domainID,
+		Execution: we,
+		EventID:   4,
+		Radius:    1,
+	})
+	s.Nil(err)
+	s.Equal(3, len(window.Events))
+	s.Equal(int64(3), window.Events[0].GetEventId())
+	s.Equal(int64(4), window.Events[1].GetEventId())
+	s.Equal(int64(5), window.Events[2].GetEventId())
+
+	// the center has to be in the history
+	_, err = s.mockHistoryEngine.GetWorkflowExecutionHistoryWindow(&HistoryWindowRequest{
+		DomainID:  domainID,
+		Execution: we,
+		EventID:   msBuilder.GetNextEventID(),
+		Radius:    1,
+	})
+	s.IsType(&workflow.BadRequestError{}, err)
+}
+
+func (s *engineSuite) TestGetHistoryEvent() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	completedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, di.ScheduleID+1, nil, identity)
+	addActivityTaskScheduledEvent(msBuilder, completedEvent.GetEventId(), "activity1", "activity_type1", tl, nil,
+		100, 10, 5)
+
+	// all events are in a single batch starting before the event
+	serializedHistory, err := msBuilder.hBuilder.Serialize()
+	s.Nil(err)
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.MatchedBy(
+		func(request *persistence.GetWorkflowExecutionHistoryRequest) bool {
+			return request.FirstEventID == common.FirstEventID && request.NextEventID == 5
+		})).Return(&persistence.GetWorkflowExecutionHistoryResponse{
+		Events: []persistence.SerializedHistoryEventBatch{*serializedHistory},
+	}, nil).Once()
+
+	event, err := s.mockHistoryEngine.GetHistoryEvent(domainID, we, completedEvent.GetEventId())
+	s.Nil(err)
+	s.Equal(completedEvent.GetEventId(), event.GetEventId())
+	s.Equal(workflow.EventTypeDecisionTaskCompleted, event.GetEventType())
+
+	// the event has to be in the history
+	_, err = s.mockHistoryEngine.GetHistoryEvent(domainID, we, msBuilder.GetNextEventID())
+	s.IsType(&workflow.EntityNotExistsError{}, err)
+}
+
+func (s *engineSuite) TestGetActivityScheduledEvent() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	completedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, di.ScheduleID+1, nil, identity)
+	scheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, completedEvent.GetEventId(), "activity1",
+		"activity_type1", tl, []byte("activity input"), 100, 10, 5)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+
+	attributes, err := s.mockHistoryEngine.GetActivityScheduledEvent(domainID, we, scheduledEvent.GetEventId())
+	s.Nil(err)
+	s.Equal("activity1", attributes.GetActivityId())
+	s.Equal("activity_type1", attributes.ActivityType.GetName())
+	s.Equal([]byte("activity input"), attributes.Input)
+
+	_, err = s.mockHistoryEngine.GetActivityScheduledEvent(domainID, we, completedEvent.GetEventId())
+	s.Equal(ErrActivityTaskNotFound, err)
+}
+
+func (s *engineSuite) TestDescribeActivity() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	activityTl := "activityTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	completedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, di.ScheduleID+1, nil, identity)
+	scheduledEvent1, _ := addActivityTaskScheduledEvent(msBuilder, completedEvent.GetEventId(), "activity1",
+		"activity_type1", activityTl, []byte("input1"), 100, 10, 5)
+	scheduledEvent2, _ := addActivityTaskScheduledEvent(msBuilder, completedEvent.GetEventId(), "activity2",
+		"activity_type2", activityTl, []byte("input2"), 100, 10, 5)
+	addActivityTaskStartedEvent(msBuilder, scheduledEvent2.GetEventId(), activityTl, identity)
+	ai2, _ := msBuilder.GetActivityInfo(scheduledEvent2.GetEventId())
+	ai2.Details = []byte("progress")
+	ai2.LastHeartBeatUpdatedTime = time.Now()
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+
+	description, err := s.mockHistoryEngine.DescribeActivity(domainID, we, "activity1")
+	s.Nil(err)
+	s.Equal(scheduledEvent1.GetEventId(), description.ScheduleID)
+	s.Equal("activity_type1", description.ActivityType.GetName())
+	s.Equal(activityTl, description.TaskList)
+	s.Equal(workflow.PendingActivityStateScheduled, description.State)
+	s.Equal(int32(100), description.ScheduleToCloseTimeout)
+	s.Equal(int32(10), description.ScheduleToStartTimeout)
+	s.Equal(int32(5), description.HeartbeatTimeout)
+	s.True(description.StartedTime.IsZero())
+	s.True(description.LastHeartbeatTime.IsZero())
+
+	description, err = s.mockHistoryEngine.DescribeActivity(domainID, we, "activity2")
+	s.Nil(err)
+	s.Equal(scheduledEvent2.GetEventId(), description.ScheduleID)
+	s.Equal(workflow.PendingActivityStateStarted, description.State)
+	s.Equal(identity, description.StartedIdentity)
+	s.False(description.StartedTime.IsZero())
+	s.False(description.LastHeartbeatTime.IsZero())
+	s.Equal([]byte("progress"), description.HeartbeatDetails)
+
+	_, err = s.mockHistoryEngine.DescribeActivity(domainID, we, "activity3")
+	s.Equal(ErrActivityTaskNotFound, err)
+}
+
+func (s *engineSuite) TestRedriveTransferTasks() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	completedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, di.ScheduleID+1, nil, identity)
+	scheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, completedEvent.GetEventId(), "activity1",
+		"activity_type1", tl, nil, 100, 10, 5)
+	startedActivityEvent, _ := addActivityTaskScheduledEvent(msBuilder, completedEvent.GetEventId(), "activity2",
+		"activity_type1", tl, nil, 100, 10, 5)
+	addActivityTaskStartedEvent(msBuilder, startedActivityEvent.GetEventId(), tl, identity)
+	crossDomainEvent, _ := msBuilder.AddActivityTaskScheduledEvent(completedEvent.GetEventId(),
+		&workflow.ScheduleActivityTaskDecisionAttributes{
+			Domain:                        common.StringPtr("targetDomain"),
+			ActivityId:                    common.StringPtr("activity3"),
+			ActivityType:                  &workflow.ActivityType{Name: common.StringPtr("activity_type1")},
+			TaskList:                      &workflow.TaskList{Name: common.StringPtr("targetTaskList")},
+			ScheduleToCloseTimeoutSeconds: common.Int32Ptr(100),
+			ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+			StartToCloseTimeoutSeconds:    common.Int32Ptr(1),
+			HeartbeatTimeoutSeconds:       common.Int32Ptr(5),
+		})
+	signalEvent := addRequestSignalInitiatedEvent(msBuilder, completedEvent.GetEventId(), uuid.New(), "",
+		"target-wId", "", "signal", nil, nil)
+
+	serializedHistory, err := msBuilder.hBuilder.Serialize()
+	s.Nil(err)
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionHistoryResponse{
+			Events: []persistence.SerializedHistoryEventBatch{*serializedHistory},
+		}, nil).Once()
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+	s.mockMetadataMgr.On("GetDomain", &persistence.GetDomainRequest{Name: "targetDomain"}).Return(
+		&persistence.GetDomainResponse{
+			Info:   &persistence.DomainInfo{ID: "targetDomainId", Name: "targetDomain"},
+			Config: &persistence.DomainConfig{Retention: 1},
+		}, nil)
+
+	count, err := s.mockHistoryEngine.RedriveTransferTasks(domainID, we)
+	s.Nil(err)
+	s.Equal(3, count)
+
+	// the started activity is left alone, the cross domain one is dispatched to its own domain
+	s.Equal(3, len(updateRequest.TransferTasks))
+	for _, task := range updateRequest.TransferTasks {
+		switch t := task.(type) {
+		case *persistence.ActivityTask:
+			switch t.ScheduleID {
+			case scheduledEvent.GetEventId():
+				s.Equal(domainID, t.DomainID)
+				s.Equal(tl, t.TaskList)
+			case crossDomainEvent.GetEventId():
+				s.Equal("targetDomainId", t.DomainID)
+				s.Equal("targetTaskList", t.TaskList)
+			default:
+				s.Fail("unexpected activity task", "%v", t.ScheduleID)
+			}
+		case *persistence.SignalExecutionTask:
+			s.Equal(domainID, t.TargetDomainID)
+			s.Equal("target-wId", t.TargetWorkflowID)
+			s.Equal(signalEvent.GetEventId(), t.InitiatedID)
+		default:
+			s.Fail("unexpected transfer task", "%T", task)
+		}
+	}
+}
+
+func (s *engineSuite) TestSetWorkflowExecutionPaused() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 5,
+	})
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		*decisionStartedEvent.EventId, nil, identity)
+	activityScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId,
+		"activity1_id", "activity_type1", tl, []byte("input1"), 100, 10, 5)
+	addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Times(2)
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Times(3)
+
+	err := s.mockHistoryEngine.SetWorkflowExecutionPaused(domainID, we, true)
+	s.Nil(err)
+
+	// the completion of the activity is recorded, but no decision is scheduled for it
+	err = s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+			TaskToken: taskToken,
+			Result:    []byte("activity result"),
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err)
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(8), executionBuilder.GetNextEventID())
+	s.False(executionBuilder.HasPendingDecisionTask())
+
+	describeResponse, err := s.mockHistoryEngine.DescribeWorkflowExecution(&history.DescribeWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Request:    &workflow.DescribeWorkflowExecutionRequest{Execution: &we},
+	})
+	s.Nil(err)
+	s.True(describeResponse.WorkflowExecutionInfo.GetPaused())
+
+	// resuming the execution schedules a decision for the events recorded while it was paused
+	err = s.mockHistoryEngine.SetWorkflowExecutionPaused(domainID, we, false)
+	s.Nil(err)
+	executionBuilder = s.getBuilder(domainID, we)
+	s.True(executionBuilder.HasPendingDecisionTask())
+	s.Equal(int64(8), executionBuilder.executionInfo.DecisionScheduleID)
+
+	describeResponse, err = s.mockHistoryEngine.DescribeWorkflowExecution(&history.DescribeWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Request:    &workflow.DescribeWorkflowExecutionRequest{Execution: &we},
+	})
+	s.Nil(err)
+	s.Nil(describeResponse.WorkflowExecutionInfo.Paused)
+}
+
+func (s *engineSuite) TestDescribeWorkflowExecution_ScheduleToStartTimeouts() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	normalTimeout := s.config.NormalDecisionScheduleToStartTimeoutInSecs
+	defer func() { s.config.NormalDecisionScheduleToStartTimeoutInSecs = normalTimeout }()
+	s.config.NormalDecisionScheduleToStartTimeoutInSecs = func(opts ...dynamicconfig.FilterOption) int { return 30 }
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	msBuilder.executionInfo.StickyTaskList = "stickyTaskList"
+	msBuilder.executionInfo.StickyScheduleToStartTimeout = 5
+	addDecisionTaskScheduledEvent(msBuilder)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+
+	describeResponse, err := s.mockHistoryEngine.DescribeWorkflowExecution(&history.DescribeWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Request: &workflow.DescribeWorkflowExecutionRequest{
+			Domain:    common.StringPtr("domainName"),
+			Execution: &we,
+		},
+	})
+	s.Nil(err)
+	s.Equal(int32(5), describeResponse.ExecutionConfiguration.GetStickyScheduleToStartTimeoutSeconds())
+	s.Equal(int32(30), describeResponse.ExecutionConfiguration.GetNormalScheduleToStartTimeoutSeconds())
+}
+
+func (s *engineSuite) TestDescribeWorkflowExecution_ConsistencyLevel() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tasklist := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tasklist, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tasklist, identity)
+	cachedState := createMutableState(msBuilder)
+	addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, di.StartedID, nil, identity)
+	persistedState := createMutableState(msBuilder)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: cachedState}, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: persistedState}, nil).Once()
+
+	getHistoryLength := func(consistencyLevel *history.ReadConsistencyLevel) int64 {
+		describeResponse, err := s.mockHistoryEngine.DescribeWorkflowExecution(&history.DescribeWorkflowExecutionRequest{
+			DomainUUID: common.StringPtr(domainID),
+			Request: &workflow.DescribeWorkflowExecutionRequest{
+				Domain:    common.StringPtr("domainName"),
+				Execution: &we,
+			},
+			ConsistencyLevel: consistencyLevel,
+		})
+		s.Nil(err)
+		return describeResponse.WorkflowExecutionInfo.GetHistoryLength()
+	}
+
+	s.Equal(int64(3), getHistoryLength(nil))
+	s.Equal(int64(3), getHistoryLength(history.ReadConsistencyLevelEventual.Ptr()))
+	s.Equal(int64(4), getHistoryLength(history.ReadConsistencyLevelStrong.Ptr()))
+	s.Equal(int64(4), getHistoryLength(history.ReadConsistencyLevelEventual.Ptr()))
+}
+
+func (s *engineSuite) TestDescribeWorkflowExecution_Verbose() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+
+	enableClusterVerboseDescribe := s.config.EnableClusterVerboseDescribe
+	defer func() { s.config.EnableClusterVerboseDescribe = enableClusterVerboseDescribe }()
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", "testTaskList", []byte("input"), 100, 200, "identity")
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+
+	request := &history.DescribeWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Request: &workflow.DescribeWorkflowExecutionRequest{
+			Domain:    common.StringPtr("domainName"),
+			Execution: &we,
+		},
+		Verbose: common.BoolPtr(true),
+	}
+
+	// verbose requests are ignored unless enabled for the cluster
+	s.config.EnableClusterVerboseDescribe = false
+	describeResponse, err := s.mockHistoryEngine.DescribeWorkflowExecution(request)
+	s.Nil(err)
+	s.Nil(describeResponse.RawExecutionInfo)
+
+	s.config.EnableClusterVerboseDescribe = true
+	describeResponse, err = s.mockHistoryEngine.DescribeWorkflowExecution(request)
+	s.Nil(err)
+	var rawInfo rawExecutionInfo
+	s.Nil(json.Unmarshal([]byte(describeResponse.GetRawExecutionInfo()), &rawInfo))
+	s.Equal(msBuilder.GetNextEventID(), rawInfo.NextEventID)
+	s.Equal(di.ScheduleID, rawInfo.DecisionScheduleID)
+	s.Equal(emptyEventID, rawInfo.DecisionStartedID)
+	s.Equal(persistence.WorkflowStateCreated, rawInfo.State)
+}
+
+func (s *engineSuite) TestSetWorkflowExecutionOperatorTags() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	identity := "testIdentity"
+
+	maxOperatorTags := s.config.MaxOperatorTags
+	defer func() { s.config.MaxOperatorTags = maxOperatorTags }()
+	s.config.MaxOperatorTags = 2
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", "testTaskList", []byte("input"), 100, 200, identity)
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Times(2)
+
+	err := s.mockHistoryEngine.SetWorkflowExecutionOperatorTags(domainID, we, map[string]string{
+		"investigating":           "oncall",
+		doNotTerminateOperatorTag: "",
+	}, nil)
+	s.Nil(err)
+
+	describeResponse, err := s.mockHistoryEngine.DescribeWorkflowExecution(&history.DescribeWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Request:    &workflow.DescribeWorkflowExecutionRequest{Execution: &we},
+	})
+	s.Nil(err)
+	s.Equal(map[string]string{"investigating": "oncall", doNotTerminateOperatorTag: ""},
+		describeResponse.WorkflowExecutionInfo.OperatorTags)
+
+	// every rejected update below drops the cached mutable state, so it is reloaded with the tags set above
+	ms = createMutableState(s.getBuilder(domainID, we))
+	gwmsResponse = &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Times(3)
+
+	err = s.mockHistoryEngine.TerminateWorkflowExecution(&history.TerminateWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		TerminateRequest: &workflow.TerminateWorkflowExecutionRequest{
+			Domain:                 common.StringPtr(domainID),
+			WorkflowExecution:      &we,
+			Identity:               common.StringPtr(identity),
+			HonorDoNotTerminateTag: common.BoolPtr(true),
+		},
+	})
+	s.Equal(ErrDoNotTerminateTagged, err)
+
+	// neither a tag beyond the limit nor a too long one is accepted
+	err = s.mockHistoryEngine.SetWorkflowExecutionOperatorTags(domainID, we, map[string]string{"extra": ""}, nil)
+	s.IsType(&workflow.BadRequestError{}, err)
+	err = s.mockHistoryEngine.SetWorkflowExecutionOperatorTags(domainID, we, map[string]string{
+		"investigating": strings.Repeat("a", s.config.MaxOperatorTagLength+1),
+	}, nil)
+	s.IsType(&workflow.BadRequestError{}, err)
+
+	err = s.mockHistoryEngine.SetWorkflowExecutionOperatorTags(domainID, we, nil,
+		[]string{doNotTerminateOperatorTag})
+	s.Nil(err)
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(map[string]string{"investigating": "oncall"}, executionBuilder.executionInfo.OperatorTags)
+	s.True(executionBuilder.isWorkflowExecutionRunning())
+}
+
+func (s *engineSuite) TestForceDeleteWorkflowExecution() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	identity := "testIdentity"
+
+	err := s.mockHistoryEngine.ForceDeleteWorkflowExecution(&ForceDeleteRequest{
+		DomainID:  domainID,
+		Execution: we,
+		Force:     true,
+		Identity:  identity,
+	})
+	s.IsType(&workflow.BadRequestError{}, err)
+
+	err = s.mockHistoryEngine.ForceDeleteWorkflowExecution(&ForceDeleteRequest{
+		DomainID:  domainID,
+		Execution: workflow.WorkflowExecution{WorkflowId: we.WorkflowId},
+		Confirm:   true,
+		Force:     true,
+		Identity:  identity,
+	})
+	s.IsType(&workflow.BadRequestError{}, err)
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", "testTaskList", []byte("input"), 100, 200, identity)
+	addDecisionTaskScheduledEvent(msBuilder)
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Twice()
+
+	// a running execution which loads fine is only deleted when forced
+	err = s.mockHistoryEngine.ForceDeleteWorkflowExecution(&ForceDeleteRequest{
+		DomainID:  domainID,
+		Execution: we,
+		Confirm:   true,
+		Identity:  identity,
+	})
+	s.IsType(&workflow.BadRequestError{}, err)
+
+	s.mockExecutionMgr.On("DeleteCurrentWorkflowExecution", &persistence.DeleteCurrentWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: we.GetWorkflowId(),
+		RunID:      we.GetRunId(),
+	}).Return(nil).Once()
+	s.mockExecutionMgr.On("DeleteWorkflowExecution", &persistence.DeleteWorkflowExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: we.GetWorkflowId(),
+		RunID:      we.GetRunId(),
+	}).Return(nil).Once()
+	s.mockHistoryMgr.On("DeleteWorkflowExecutionHistory", &persistence.DeleteWorkflowExecutionHistoryRequest{
+		DomainID:  domainID,
+		Execution: we,
+	}).Return(nil).Once()
+	err = s.mockHistoryEngine.ForceDeleteWorkflowExecution(&ForceDeleteRequest{
+		DomainID:  domainID,
+		Execution: we,
+		Confirm:   true,
+		Force:     true,
+		Identity:  identity,
+	})
+	s.Nil(err)
+
+	// the deleted execution is not served from the cache anymore
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil,
+		&workflow.EntityNotExistsError{Message: "Workflow execution not found."}).Once()
+	_, err = s.mockHistoryEngine.GetMutableState(context.Background(), &history.GetMutableStateRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Execution:  &we,
+	})
+	s.IsType(&workflow.EntityNotExistsError{}, err)
+}
+
+func (s *engineSuite) TestForceDeleteWorkflowExecution_LoadFailure() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+
+	// mutable state which fails to load may belong to a healthy execution, so it is only deleted when forced
+	loadErr := errors.New("corrupted mutable state")
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil, loadErr).Twice()
+	err := s.mockHistoryEngine.ForceDeleteWorkflowExecution(&ForceDeleteRequest{
+		DomainID:  domainID,
+		Execution: we,
+		Confirm:   true,
+	})
+	s.Equal(loadErr, err)
+
+	s.mockExecutionMgr.On("DeleteCurrentWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("DeleteWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockHistoryMgr.On("DeleteWorkflowExecutionHistory", mock.Anything).Return(nil).Once()
+	err = s.mockHistoryEngine.ForceDeleteWorkflowExecution(&ForceDeleteRequest{
+		DomainID:  domainID,
+		Execution: we,
+		Confirm:   true,
+		Force:     true,
+	})
+	s.Nil(err)
+
+	// executions whose mutable state is already gone need no force
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil,
+		&workflow.EntityNotExistsError{Message: "Workflow execution not found."}).Once()
+	s.mockExecutionMgr.On("DeleteCurrentWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("DeleteWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockHistoryMgr.On("DeleteWorkflowExecutionHistory", mock.Anything).Return(nil).Once()
+	err = s.mockHistoryEngine.ForceDeleteWorkflowExecution(&ForceDeleteRequest{
+		DomainID:  domainID,
+		Execution: we,
+		Confirm:   true,
+	})
+	s.Nil(err)
+}
+
+func (s *engineSuite) TestReconcileCurrentExecution() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	newRunID := uuid.New()
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	completedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, di.ScheduleID+1, nil, identity)
+	_, newBuilder, err := msBuilder.AddContinueAsNewEvent(completedEvent.GetEventId(), domainID, "domainName",
+		newRunID, "", &workflow.ContinueAsNewWorkflowExecutionDecisionAttributes{})
+	s.Nil(err)
+	serializedHistory, err := msBuilder.hBuilder.Serialize()
+	s.Nil(err)
+
+	isRun := func(runID string) interface{} {
+		return mock.MatchedBy(func(request *persistence.GetWorkflowExecutionRequest) bool {
+			return request.Execution.GetRunId() == runID
+		})
+	}
+
+	// the current execution still points at the run which continued as new
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(&persistence.GetCurrentExecutionResponse{
+		RunID:       validRunID,
+		State:       persistence.WorkflowStateCompleted,
+		CloseStatus: persistence.WorkflowCloseStatusContinuedAsNew,
+	}, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", isRun(validRunID)).Return(
+		&persistence.GetWorkflowExecutionResponse{State: createMutableState(msBuilder)}, nil).Once()
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionHistoryResponse{
+			Events: []persistence.SerializedHistoryEventBatch{*serializedHistory},
+		}, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", isRun(newRunID)).Return(
+		&persistence.GetWorkflowExecutionResponse{State: createMutableState(newBuilder)}, nil).Once()
+	s.mockExecutionMgr.On("UpdateCurrentWorkflowExecution", mock.MatchedBy(
+		func(request *persistence.UpdateCurrentWorkflowExecutionRequest) bool {
+			return request.RunID == newRunID && request.PreviousRunID == validRunID &&
+				request.State == newBuilder.executionInfo.State
+		})).Return(nil).Once()
+
+	repaired, err := s.mockHistoryEngine.ReconcileCurrentExecution(domainID, we.GetWorkflowId())
+	s.Nil(err)
+	s.True(repaired)
+
+	// once repaired the current execution is left alone
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(&persistence.GetCurrentExecutionResponse{
+		RunID: newRunID,
+		State: newBuilder.executionInfo.State,
+	}, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", isRun(newRunID)).Return(
+		&persistence.GetWorkflowExecutionResponse{State: createMutableState(newBuilder)}, nil).Once()
+
+	repaired, err = s.mockHistoryEngine.ReconcileCurrentExecution(domainID, we.GetWorkflowId())
+	s.Nil(err)
+	s.False(repaired)
+}
+
+func (s *engineSuite) TestGetWorkflowExecutionResult() {
+	offloader := &testPayloadOffloader{payloads: [][]byte{[]byte("failDetails")}}
+	payloadOffloader := s.config.PayloadOffloader
+	defer func() { s.config.PayloadOffloader = payloadOffloader }()
+	s.config.PayloadOffloader = offloader
+
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	completedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, di.ScheduleID+1, nil, identity)
+	failEvent := msBuilder.AddFailWorkflowEvent(completedEvent.GetEventId(),
+		&workflow.FailWorkflowExecutionDecisionAttributes{
+			Reason:  common.StringPtr("failReason"),
+			Details: []byte("offloaded-0"),
+		})
+	s.NotNil(failEvent)
+	serializedHistory, err := msBuilder.hBuilder.Serialize()
+	s.Nil(err)
+	msBuilder.executionInfo.LastFirstEventID = common.FirstEventID
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: createMutableState(msBuilder)}, nil).Once()
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.MatchedBy(
+		func(request *persistence.GetWorkflowExecutionHistoryRequest) bool {
+			return request.FirstEventID == common.FirstEventID && request.NextEventID == failEvent.GetEventId()+1
+		})).Return(&persistence.GetWorkflowExecutionHistoryResponse{
+		Events: []persistence.SerializedHistoryEventBatch{*serializedHistory},
+	}, nil).Once()
+
+	result, err := s.mockHistoryEngine.GetWorkflowExecutionResult(domainID, we)
+	s.Nil(err)
+	s.Equal(validRunID, result.RunID)
+	s.Equal(workflow.WorkflowExecutionCloseStatusFailed, result.CloseStatus)
+	s.Equal("failReason", result.Reason)
+	s.Equal([]byte("failDetails"), result.Details)
+	s.Nil(result.Result)
+
+	// the closed execution is answered from the cache on later calls
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionHistoryResponse{
+			Events: []persistence.SerializedHistoryEventBatch{*serializedHistory},
+		}, nil).Once()
+	result, err = s.mockHistoryEngine.GetWorkflowExecutionResult(domainID, we)
+	s.Nil(err)
+	s.Equal("failReason", result.Reason)
+}
+
+func (s *engineSuite) TestGetWorkflowExecutionResult_Running() {
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", "testTaskList", []byte("input"), 100, 200,
+		"testIdentity")
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: createMutableState(msBuilder)}, nil).Once()
+
+	_, err := s.mockHistoryEngine.GetWorkflowExecutionResult("domainId", we)
+	s.Equal(ErrWorkflowRunning, err)
+}
+
+func (s *engineSuite) TestGetWorkflowExecutionResult_NotExists() {
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil,
+		&workflow.EntityNotExistsError{Message: "Workflow execution not found."}).Once()
+
+	_, err := s.mockHistoryEngine.GetWorkflowExecutionResult("domainId", we)
+	s.IsType(&workflow.EntityNotExistsError{}, err)
+}
+
+func (s *engineSuite) TestGetFirstDecisionLatency() {
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+	now := time.Now()
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	msBuilder.executionInfo.StartTimestamp = now.Add(-5 * time.Second)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	di, _ = msBuilder.GetPendingDecision(di.ScheduleID)
+
+	latency, ok := getFirstDecisionLatency(msBuilder, di, now)
+	s.True(ok)
+	s.Equal(5*time.Second, latency)
+
+	// a retried first decision is not reported
+	retried := *di
+	retried.Attempt = 1
+	_, ok = getFirstDecisionLatency(msBuilder, &retried, now)
+	s.False(ok)
+
+	addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, di.StartedID, nil, identity)
+	di = addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	di, _ = msBuilder.GetPendingDecision(di.ScheduleID)
+	_, ok = getFirstDecisionLatency(msBuilder, di, now)
+	s.False(ok)
+}
+
+func (s *engineSuite) TestListOpenExecutions() {
+	response := &persistence.ListOpenExecutionsResponse{
+		Executions: []*persistence.OpenExecution{{
+			DomainID:   "domainId",
+			WorkflowID: "wId",
+			RunID:      validRunID,
+			StartTime:  time.Now(),
+		}},
+		NextPageToken: []byte("token2"),
+	}
+	s.mockExecutionMgr.On("ListOpenExecutions", &persistence.ListOpenExecutionsRequest{
+		PageSize:      10,
+		NextPageToken: []byte("token1"),
+	}).Return(response, nil).Once()
+
+	resp, err := s.mockHistoryEngine.ListOpenExecutions(10, []byte("token1"))
+	s.Nil(err)
+	s.Equal(response, resp)
+
+	_, err = s.mockHistoryEngine.ListOpenExecutions(0, nil)
+	s.IsType(&workflow.BadRequestError{}, err)
+}
+
+func (s *engineSuite) TestReplicateEventsRetriesConflicts() {
+	s.mockHistoryEngine.replicator = newHistoryReplicator(s.mockHistoryEngine.shard, s.mockHistoryEngine.historyCache,
+		s.mockHistoryEngine.shard.GetDomainCache(), s.mockHistoryMgr, s.logger)
+	policy := backoff.NewExponentialRetryPolicy(time.Millisecond)
+	policy.SetMaximumAttempts(2)
+	s.mockHistoryEngine.replicationRetryPolicy = policy
+
+	request := &history.ReplicateEventsRequest{
+		DomainUUID: common.StringPtr(uuid.New()),
+		WorkflowExecution: &workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr("wId"),
+			RunId:      common.StringPtr(validRunID),
+		},
+		History: &workflow.History{Events: []*workflow.HistoryEvent{{
+			EventId:   common.Int64Ptr(5),
+			EventType: common.EventTypePtr(workflow.EventTypeWorkflowExecutionSignaled),
+		}}},
+	}
+	conflict := &persistence.ConditionFailedError{Msg: "conflict"}
+
+	// a conflict is retried while errors the replication task has to handle are handed back as is
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil, conflict).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil,
+		&workflow.EntityNotExistsError{Message: "Workflow execution not found."}).Once()
+	err := s.mockHistoryEngine.ReplicateEvents(request)
+	s.IsType(&workflow.EntityNotExistsError{}, err)
+
+	// the conflict is handed back once the retries are used up
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil, conflict).Times(3)
+	err = s.mockHistoryEngine.ReplicateEvents(request)
+	s.Equal(conflict, err)
+}
+
+func (s *engineSuite) TestResetStickyTaskList() {
+	domainID := "domainId"
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	testCases := []struct {
+		workflowID     string
+		state          int
+		stickyTaskList string
+		cleared        bool
+		skippedReason  *history.ResetStickyTaskListSkippedReason
+	}{
+		{
+			workflowID:     "wId-sticky",
+			state:          persistence.WorkflowStateRunning,
+			stickyTaskList: "stickyTaskList",
+			cleared:        true,
+		},
+		{
+			workflowID:    "wId-notSticky",
+			state:         persistence.WorkflowStateRunning,
+			skippedReason: history.ResetStickyTaskListSkippedReasonNotSticky.Ptr(),
+		},
+		{
+			workflowID:     "wId-completed",
+			state:          persistence.WorkflowStateCompleted,
+			stickyTaskList: "stickyTaskList",
+			skippedReason:  history.ResetStickyTaskListSkippedReasonWorkflowNotRunning.Ptr(),
+		},
+	}
+
+	for _, tc := range testCases {
+		we := workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(tc.workflowID),
+			RunId:      common.StringPtr(validRunID),
+		}
+
+		msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+		addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+		msBuilder.executionInfo.StickyTaskList = tc.stickyTaskList
+		msBuilder.executionInfo.State = tc.state
+
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+		s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+		response, err := s.mockHistoryEngine.ResetStickyTaskList(&history.ResetStickyTaskListRequest{
+			DomainUUID: common.StringPtr(domainID),
+			Execution:  &we,
+		})
+		s.Nil(err)
+		s.Equal(tc.cleared, response.GetStickinessCleared(), tc.workflowID)
+		s.Equal(tc.skippedReason, response.SkippedReason, tc.workflowID)
+	}
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedWorkflowClosedCounter() {
+	domainID := "domainId"
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	scope := tally.NewTestScope("test", nil)
+	s.mockHistoryEngine.metricsClient = metrics.NewClient(scope, metrics.History)
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+
+	testCases := []struct {
+		workflowID string
+		decision   *workflow.Decision
+		closeState string
+	}{
+		{
+			workflowID: "wId-complete",
+			decision: &workflow.Decision{
+				DecisionType: common.DecisionTypePtr(workflow.DecisionTypeCompleteWorkflowExecution),
+				CompleteWorkflowExecutionDecisionAttributes: &workflow.CompleteWorkflowExecutionDecisionAttributes{
+					Result: []byte("success"),
+				},
+			},
+			closeState: "COMPLETED",
+		},
+		{
+			workflowID: "wId-continue-as-new",
+			decision: &workflow.Decision{
+				DecisionType: common.DecisionTypePtr(workflow.DecisionTypeContinueAsNewWorkflowExecution),
+				ContinueAsNewWorkflowExecutionDecisionAttributes: &workflow.ContinueAsNewWorkflowExecutionDecisionAttributes{
+					Input: []byte("input"),
+				},
+			},
+			closeState: "CONTINUED_AS_NEW",
+		},
+	}
+
+	for _, tc := range testCases {
+		we := workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(tc.workflowID),
+			RunId:      common.StringPtr(validRunID),
+		}
+		taskToken, _ := json.Marshal(&common.TaskToken{
+			WorkflowID: *we.WorkflowId,
+			RunID:      *we.RunId,
+			ScheduleID: 2,
+		})
+
+		msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+		addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+		di := addDecisionTaskScheduledEvent(msBuilder)
+		addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+		s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil)
+		s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+		_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+			DomainUUID: common.StringPtr(domainID),
+			CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+				TaskToken: taskToken,
+				Decisions: []*workflow.Decision{tc.decision},
+				Identity:  &identity,
+			},
+		})
+		s.Nil(err, s.printHistory(msBuilder))
+
+		counter := scope.Snapshot().Counters()["test.workflow-closed+close_status="+tc.closeState+
+			",domain=domainName,operation=RespondDecisionTaskCompleted"]
+		s.NotNil(counter)
+		s.Equal(int64(1), counter.Value())
+	}
+}
+
+func (s *engineSuite) TestGetMutableStateAfterContinueAsNew() {
+	domainID := "domainId"
+	tl := "testTaskList"
+	identity := "testIdentity"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	appendRequests := make(map[string]*persistence.AppendHistoryEventsRequest)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		request := args.Get(0).(*persistence.AppendHistoryEventsRequest)
+		appendRequests[request.Execution.GetRunId()] = request
+	}).Times(2)
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: []*workflow.Decision{{
+				DecisionType: common.DecisionTypePtr(workflow.DecisionTypeContinueAsNewWorkflowExecution),
+				ContinueAsNewWorkflowExecutionDecisionAttributes: &workflow.ContinueAsNewWorkflowExecutionDecisionAttributes{
+					Input: []byte("input"),
+				},
+			}},
+			Identity: &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	s.Equal(2, len(appendRequests))
+
+	// the last batch of the continued run starts with the decision completed event
+	response, err := s.mockHistoryEngine.GetMutableState(context.Background(), &history.GetMutableStateRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Execution:  &we,
+	})
+	s.Nil(err)
+	s.Equal(appendRequests[validRunID].FirstEventID, response.GetLastFirstEventId())
+	s.Equal(int64(4), response.GetLastFirstEventId())
+	s.Equal(int64(6), response.GetNextEventId())
+
+	// the history of the new run starts with its own first event, which is persisted as its last batch
+	newRunCreate := updateRequest.ContinueAsNew
+	s.NotNil(newRunCreate)
+	s.Equal(appendRequests[newRunCreate.Execution.GetRunId()].FirstEventID, newRunCreate.LastFirstEventID)
+	s.Equal(common.FirstEventID, newRunCreate.LastFirstEventID)
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedContinueAsNewKeepsKeyID() {
+	domainID := "domainId"
+	tl := "testTaskList"
+	identity := "testIdentity"
+	keyID := "testKeyID"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	msBuilder.executionInfo.KeyID = keyID
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	var appendRequests []*persistence.AppendHistoryEventsRequest
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		appendRequests = append(appendRequests, args.Get(0).(*persistence.AppendHistoryEventsRequest))
+	}).Times(2)
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: []*workflow.Decision{{
+				DecisionType: common.DecisionTypePtr(workflow.DecisionTypeContinueAsNewWorkflowExecution),
+				ContinueAsNewWorkflowExecutionDecisionAttributes: &workflow.ContinueAsNewWorkflowExecutionDecisionAttributes{
+					Input: []byte("input"),
+				},
+			}},
+			Identity: &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+
+	// the history of the continued run as well as the one of the new run is appended with the key id
+	s.Equal(2, len(appendRequests))
+	for _, request := range appendRequests {
+		s.Equal(keyID, request.KeyID)
+	}
+	s.NotNil(updateRequest.ContinueAsNew)
+	s.Equal(keyID, updateRequest.ContinueAsNew.KeyID)
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedFailWorkflowSuccess() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+	executionContext := []byte("context")
+	details := []byte("fail workflow details")
+	reason := "fail workflow reason"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeFailWorkflowExecution),
+		FailWorkflowExecutionDecisionAttributes: &workflow.FailWorkflowExecutionDecisionAttributes{
+			Reason:  &reason,
+			Details: details,
+		},
+	}}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken:        taskToken,
+			Decisions:        decisions,
+			ExecutionContext: executionContext,
+			Identity:         &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
+	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
+	s.Equal(executionContext, executionBuilder.executionInfo.ExecutionContext)
+	s.Equal(persistence.WorkflowStateCompleted, executionBuilder.executionInfo.State)
+	s.False(executionBuilder.HasPendingDecisionTask())
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowSuccess() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+	executionContext := []byte("context")
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeSignalExternalWorkflowExecution),
+		SignalExternalWorkflowExecutionDecisionAttributes: &workflow.SignalExternalWorkflowExecutionDecisionAttributes{
+			Domain: common.StringPtr(domainID),
+			// another run of the same workflow is signaled through a transfer task
+			Execution: &workflow.WorkflowExecution{
+				WorkflowId: we.WorkflowId,
+				RunId:      common.StringPtr(uuid.New()),
+			},
+			SignalName: common.StringPtr("signal"),
+			Input:      []byte("test input"),
+		},
+	}}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	domainInfo := &persistence.DomainInfo{
+		ID:   domainID,
+		Name: domainID,
+	}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(func(request *persistence.UpdateWorkflowExecutionRequest) bool {
+		for _, task := range request.TransferTasks {
+			if _, ok := task.(*persistence.SignalExecutionTask); ok {
+				return true
+			}
+		}
+		return false
+	})).Return(nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{
+			Config: &persistence.DomainConfig{Retention: 1},
+			Info:   domainInfo,
+		}, nil).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken:        taskToken,
+			Decisions:        decisions,
+			ExecutionContext: executionContext,
+			Identity:         &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
+	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
+	s.Equal(executionContext, executionBuilder.executionInfo.ExecutionContext)
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowSelf() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeSignalExternalWorkflowExecution),
+		SignalExternalWorkflowExecutionDecisionAttributes: &workflow.SignalExternalWorkflowExecutionDecisionAttributes{
+			Execution: &workflow.WorkflowExecution{
+				WorkflowId: we.WorkflowId,
+				RunId:      we.RunId,
+			},
+			SignalName: common.StringPtr("signal"),
+			Input:      []byte("test input"),
+		},
+	}}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(func(request *persistence.UpdateWorkflowExecutionRequest) bool {
+		for _, task := range request.TransferTasks {
+			if _, ok := task.(*persistence.SignalExecutionTask); ok {
+				return false
+			}
+		}
+		return true
+	})).Return(nil).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	// initiated, signaled and external signaled events followed by a decision for the signal
+	s.Equal(int64(9), executionBuilder.executionInfo.NextEventID)
+	s.Equal(int64(1), executionBuilder.executionInfo.SignalCount)
+	s.Equal("signal", executionBuilder.executionInfo.LastSignalName)
+	s.Empty(executionBuilder.pendingSignalInfoIDs)
+	s.True(executionBuilder.HasPendingDecisionTask())
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowSelfThenCompleteWorkflow() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeSignalExternalWorkflowExecution),
+		SignalExternalWorkflowExecutionDecisionAttributes: &workflow.SignalExternalWorkflowExecutionDecisionAttributes{
+			Execution: &workflow.WorkflowExecution{
+				WorkflowId: we.WorkflowId,
+				RunId:      we.RunId,
+			},
+			SignalName: common.StringPtr("signal"),
+			Input:      []byte("test input"),
+		},
+	}, {
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeCompleteWorkflowExecution),
+		CompleteWorkflowExecutionDecisionAttributes: &workflow.CompleteWorkflowExecutionDecisionAttributes{
+			Result: []byte("success"),
+		},
+	}}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	// the events the workflow execution added to itself do not keep it from completing
+	s.Equal(persistence.WorkflowStateCompleted, executionBuilder.executionInfo.State)
+	s.Equal(persistence.WorkflowCloseStatusCompleted, executionBuilder.executionInfo.CloseStatus)
+	s.Equal(int64(1), executionBuilder.executionInfo.SignalCount)
+	s.False(executionBuilder.HasPendingDecisionTask())
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowSelfRejected() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+
+	rejectSelfTargeted := s.config.RejectSelfTargetedExternalDecisions
+	defer func() { s.config.RejectSelfTargetedExternalDecisions = rejectSelfTargeted }()
+	s.config.RejectSelfTargetedExternalDecisions = true
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeSignalExternalWorkflowExecution),
+		SignalExternalWorkflowExecutionDecisionAttributes: &workflow.SignalExternalWorkflowExecutionDecisionAttributes{
+			// an empty run id targets the current run
+			Execution: &workflow.WorkflowExecution{
+				WorkflowId: we.WorkflowId,
+			},
+			SignalName: common.StringPtr("signal"),
+		},
+	}}
+
+	// the decision is failed on a reloaded mutable state
+	for i := 0; i < 2; i++ {
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	}
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(0), executionBuilder.executionInfo.SignalCount)
+	s.Empty(executionBuilder.pendingSignalInfoIDs)
+	s.Equal(int64(1), executionBuilder.executionInfo.DecisionAttempt)
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedDisabledDecisionType() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+
+	enableDecisionTypeFilter := s.config.EnableDecisionTypeFilter
+	disabledDecisionTypes := s.config.DisabledDecisionTypes
+	defer func() {
+		s.config.EnableDecisionTypeFilter = enableDecisionTypeFilter
+		s.config.DisabledDecisionTypes = disabledDecisionTypes
+	}()
+	s.config.EnableDecisionTypeFilter = func(opts ...dynamicconfig.FilterOption) bool { return true }
+	s.config.DisabledDecisionTypes = func(opts ...dynamicconfig.FilterOption) string {
+		return "RequestCancelExternalWorkflowExecution, StartChildWorkflowExecution"
+	}
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{
+		{
+			DecisionType: common.DecisionTypePtr(workflow.DecisionTypeStartTimer),
+			StartTimerDecisionAttributes: &workflow.StartTimerDecisionAttributes{
+				TimerId:                   common.StringPtr("timer"),
+				StartToFireTimeoutSeconds: common.Int64Ptr(10),
+			},
+		},
+		{
+			DecisionType: common.DecisionTypePtr(workflow.DecisionTypeStartChildWorkflowExecution),
+			StartChildWorkflowExecutionDecisionAttributes: &workflow.StartChildWorkflowExecutionDecisionAttributes{
+				Domain:       common.StringPtr("domain"),
+				WorkflowId:   common.StringPtr("child"),
+				WorkflowType: &workflow.WorkflowType{Name: common.StringPtr("childType")},
+				TaskList:     &workflow.TaskList{Name: common.StringPtr(tl)},
+			},
+		},
+	}
+
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domain"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+	// the decision is failed on a reloaded mutable state
+	for i := 0; i < 2; i++ {
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	}
+	var appendRequest *persistence.AppendHistoryEventsRequest
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		appendRequest = args.Get(0).(*persistence.AppendHistoryEventsRequest)
+	}).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+
+	// the enabled timer decision of the batch is dropped along with the disabled one
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Empty(executionBuilder.pendingTimerInfoIDs)
+	s.Empty(executionBuilder.pendingChildExecutionInfoIDs)
+	s.Equal(int64(1), executionBuilder.executionInfo.DecisionAttempt)
+
+	s.NotNil(appendRequest)
+	serializer, _ := s.mockHistoryEngine.hSerializerFactory.Get(appendRequest.Events.EncodingType)
+	history, err := serializer.Deserialize(appendRequest.Events)
+	s.Nil(err)
+	s.Equal(1, len(history.Events))
+	s.Equal(workflow.EventTypeDecisionTaskFailed, history.Events[0].GetEventType())
+	s.Equal(workflow.DecisionTaskFailedCauseDecisionTypeDisabled,
+		history.Events[0].DecisionTaskFailedEventAttributes.GetCause())
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedCancelExternalWorkflowSelf() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeRequestCancelExternalWorkflowExecution),
+		RequestCancelExternalWorkflowExecutionDecisionAttributes: &workflow.RequestCancelExternalWorkflowExecutionDecisionAttributes{
+			WorkflowId: we.WorkflowId,
+			RunId:      we.RunId,
+		},
+	}}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(func(request *persistence.UpdateWorkflowExecutionRequest) bool {
+		for _, task := range request.TransferTasks {
+			if _, ok := task.(*persistence.CancelExecutionTask); ok {
+				return false
+			}
+		}
+		return true
+	})).Return(nil).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	// initiated, cancel requested and external cancel requested events followed by a decision for the request
+	s.Equal(int64(9), executionBuilder.executionInfo.NextEventID)
+	isCancelRequested, _ := executionBuilder.isCancelRequested()
+	s.True(isCancelRequested)
+	s.Empty(executionBuilder.pendingRequestCancelInfoIDs)
+	s.True(executionBuilder.HasPendingDecisionTask())
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedCancelExternalWorkflowSelfThenCompleteWorkflow() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeRequestCancelExternalWorkflowExecution),
+		RequestCancelExternalWorkflowExecutionDecisionAttributes: &workflow.RequestCancelExternalWorkflowExecutionDecisionAttributes{
+			WorkflowId: we.WorkflowId,
+			RunId:      we.RunId,
+		},
+	}, {
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeCompleteWorkflowExecution),
+		CompleteWorkflowExecutionDecisionAttributes: &workflow.CompleteWorkflowExecutionDecisionAttributes{
+			Result: []byte("success"),
+		},
+	}}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	// the events the workflow execution added to itself do not keep it from completing
+	s.Equal(persistence.WorkflowStateCompleted, executionBuilder.executionInfo.State)
+	s.Equal(persistence.WorkflowCloseStatusCompleted, executionBuilder.executionInfo.CloseStatus)
+	isCancelRequested, _ := executionBuilder.isCancelRequested()
+	s.True(isCancelRequested)
+	s.False(executionBuilder.HasPendingDecisionTask())
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowFailed() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr("invalid run id"),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: we.GetWorkflowId(),
+		RunID:      we.GetRunId(),
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+	executionContext := []byte("context")
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeSignalExternalWorkflowExecution),
+		SignalExternalWorkflowExecutionDecisionAttributes: &workflow.SignalExternalWorkflowExecutionDecisionAttributes{
+			Domain: common.StringPtr(domainID),
+			Execution: &workflow.WorkflowExecution{
+				WorkflowId: we.WorkflowId,
+				RunId:      we.RunId,
+			},
+			SignalName: common.StringPtr("signal"),
+			Input:      []byte("test input"),
+		},
+	}}
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken:        taskToken,
+			Decisions:        decisions,
+			ExecutionContext: executionContext,
+			Identity:         &identity,
+		},
+	})
+
+	s.EqualError(err, "BadRequestError{Message: RunID is not valid UUID.}")
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowFailed_UnKnownDomain() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+	executionContext := []byte("context")
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeSignalExternalWorkflowExecution),
+		SignalExternalWorkflowExecutionDecisionAttributes: &workflow.SignalExternalWorkflowExecutionDecisionAttributes{
+			Domain: common.StringPtr(domainID),
+			Execution: &workflow.WorkflowExecution{
+				WorkflowId: we.WorkflowId,
+				RunId:      we.RunId,
+			},
+			SignalName: common.StringPtr("signal"),
+			Input:      []byte("test input"),
+		},
+	}}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		nil, errors.New("get foreign domain error")).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken:        taskToken,
+			Decisions:        decisions,
+			ExecutionContext: executionContext,
+			Identity:         &identity,
+		},
+	})
+
+	s.EqualError(err, "InternalServiceError{Message: Unable to signal workflow across domain: domainId.}")
+}
+
+func (s *engineSuite) TestRespondActivityTaskCompletedInvalidToken() {
+	domainID := "domainId"
+	invalidToken, _ := json.Marshal("bad token")
+	identity := "testIdentity"
+
+	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+			TaskToken: invalidToken,
+			Result:    nil,
+			Identity:  &identity,
+		},
+	})
+
+	s.NotNil(err)
+	s.IsType(&workflow.BadRequestError{}, err)
+}
+
+func (s *engineSuite) TestRespondActivityTaskCompletedIfNoExecution() {
+	domainID := "domainId"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: "wId",
+		RunID:      validRunID,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil, &workflow.EntityNotExistsError{}).Once()
+
+	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+			TaskToken: taskToken,
+			Identity:  &identity,
+		},
+	})
+	s.NotNil(err)
+	s.IsType(&workflow.EntityNotExistsError{}, err)
+}
+
+func (s *engineSuite) TestRespondActivityTaskCompletedIfNoRunID() {
+	domainID := "domainId"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: "wId",
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(nil, &workflow.EntityNotExistsError{}).Once()
+
+	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+			TaskToken: taskToken,
+			Identity:  &identity,
+		},
+	})
+	s.NotNil(err)
+	s.IsType(&workflow.EntityNotExistsError{}, err)
+}
+
+func (s *engineSuite) TestRespondActivityTaskCompletedIfGetExecutionFailed() {
+	domainID := "domainId"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: "wId",
+		RunID:      validRunID,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil, errors.New("FAILED")).Once()
+
+	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+			TaskToken: taskToken,
+			Identity:  &identity,
+		},
+	})
+	s.EqualError(err, "FAILED")
+}
+
+func (s *engineSuite) TestRespondActivityTaskCompletedIfNoAIdProvided() {
+	domainID := "domainId"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: "wId",
+		ScheduleID: common.EmptyEventID,
+	})
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: validRunID}
+
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+
+	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+			TaskToken: taskToken,
+			Identity:  &identity,
+		},
+	})
+	s.EqualError(err, "BadRequestError{Message: Neither ActivityID nor ScheduleID is provided}")
+}
+
+func (s *engineSuite) TestRespondActivityTaskCompletedIfNoAidFound() {
+	domainID := "domainId"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: "wId",
+		ScheduleID: common.EmptyEventID,
+		ActivityID: "aid",
+	})
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: validRunID}
+
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+
+	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+			TaskToken: taskToken,
+			Identity:  &identity,
+		},
+	})
+	s.EqualError(err, "BadRequestError{Message: No such activityID: aid\n}")
+}
+
+func (s *engineSuite) TestRespondActivityTaskCompletedUpdateExecutionFailed() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 5,
+	})
+	identity := "testIdentity"
+	activityID := "activity1_id"
+	activityType := "activity_type1"
+	activityInput := []byte("input1")
+	activityResult := []byte("activity result")
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		*decisionStartedEvent.EventId, nil, identity)
+	activityScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId, activityID,
+		activityType, tl, activityInput, 100, 10, 5)
+	addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(errors.New("FAILED")).Once()
+	s.mockShardManager.On("UpdateShard", mock.Anything).Return(nil).Once()
+
+	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+			TaskToken: taskToken,
+			Result:    activityResult,
+			Identity:  &identity,
+		},
+	})
+	s.EqualError(err, "FAILED")
+}
+
+func (s *engineSuite) TestRespondActivityTaskCompletedIfTaskCompleted() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 5,
+	})
+	identity := "testIdentity"
+	activityID := "activity1_id"
+	activityType := "activity_type1"
+	activityInput := []byte("input1")
+	activityResult := []byte("activity result")
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		*decisionStartedEvent.EventId, nil, identity)
+	activityScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId, activityID,
+		activityType, tl, activityInput, 100, 10, 5)
+	activityStartedEvent := addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
+	addActivityTaskCompletedEvent(msBuilder, *activityScheduledEvent.EventId, *activityStartedEvent.EventId,
+		activityResult, identity)
+	addDecisionTaskScheduledEvent(msBuilder)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+
+	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+			TaskToken: taskToken,
+			Result:    activityResult,
+			Identity:  &identity,
+		},
+	})
+	s.NotNil(err)
+	s.IsType(&workflow.EntityNotExistsError{}, err)
+}
+
+func (s *engineSuite) TestRespondActivityTaskCompletedIfTaskNotStarted() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 5,
+	})
+	identity := "testIdentity"
+	activityID := "activity1_id"
+	activityType := "activity_type1"
+	activityInput := []byte("input1")
+	activityResult := []byte("activity result")
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		*decisionStartedEvent.EventId, nil, identity)
+	addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId, activityID,
+		activityType, tl, activityInput, 100, 10, 5)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
 
 	err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
@@ -1690,6 +5116,133 @@ func (s *engineSuite) TestRespondActivityTaskCompletedSuccess() {
 	s.Equal(emptyEventID, di.StartedID)
 }
 
+func (s *engineSuite) TestRespondActivityTaskCompletedDuplicateRequestID() {
+	s.mockHistoryEngine.completedActivityRequests = cache.New(10, &cache.Options{TTL: time.Minute})
+
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 5,
+	})
+	identity := "testIdentity"
+	requestID := uuid.New()
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		*decisionStartedEvent.EventId, nil, identity)
+	activityScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId,
+		"activity1_id", "activity_type1", tl, []byte("input1"), 100, 10, 5)
+	addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	completeRequest := &history.RespondActivityTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+			TaskToken: taskToken,
+			Result:    []byte("activity result"),
+			Identity:  &identity,
+			RequestId: common.StringPtr(requestID),
+		},
+	}
+	err := s.mockHistoryEngine.RespondActivityTaskCompleted(completeRequest)
+	s.Nil(err)
+
+	// retried completion with the same request id succeeds without another update
+	err = s.mockHistoryEngine.RespondActivityTaskCompleted(completeRequest)
+	s.Nil(err)
+	s.mockExecutionMgr.AssertNumberOfCalls(s.T(), "UpdateWorkflowExecution", 1)
+}
+
+func (s *engineSuite) TestRespondActivityTaskCompletedOffloadsLargeResult() {
+	offloader := &testPayloadOffloader{}
+	payloadOffloader := s.config.PayloadOffloader
+	payloadOffloadThreshold := s.config.PayloadOffloadThreshold
+	defer func() {
+		s.config.PayloadOffloader = payloadOffloader
+		s.config.PayloadOffloadThreshold = payloadOffloadThreshold
+	}()
+	s.config.PayloadOffloader = offloader
+	s.config.PayloadOffloadThreshold = func(...dynamicconfig.FilterOption) int { return 10 }
+
+	testCases := []struct {
+		result   []byte
+		recorded []byte
+	}{
+		{result: []byte("small"), recorded: []byte("small")},
+		{result: []byte("a rather large activity result"), recorded: []byte("offloaded-0")},
+	}
+
+	for i, tc := range testCases {
+		domainID := "domainId"
+		we := workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(fmt.Sprintf("wId-%v", i)),
+			RunId:      common.StringPtr(validRunID),
+		}
+		tl := "testTaskList"
+		taskToken, _ := json.Marshal(&common.TaskToken{
+			WorkflowID: *we.WorkflowId,
+			RunID:      *we.RunId,
+			ScheduleID: 5,
+		})
+		identity := "testIdentity"
+
+		msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+		addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+		di := addDecisionTaskScheduledEvent(msBuilder)
+		decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+		decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+			*decisionStartedEvent.EventId, nil, identity)
+		activityScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId,
+			"activity1_id", "activity_type1", tl, []byte("input1"), 100, 10, 5)
+		addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
+
+		ms := createMutableState(msBuilder)
+		gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+		var recorded []byte
+		s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+		s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			request := args.Get(0).(*persistence.AppendHistoryEventsRequest)
+			batch, err := persistence.NewJSONHistorySerializer().Deserialize(request.Events)
+			s.Nil(err)
+			for _, event := range batch.Events {
+				if event.GetEventType() == workflow.EventTypeActivityTaskCompleted {
+					recorded = event.ActivityTaskCompletedEventAttributes.Result
+				}
+			}
+		}).Once()
+		s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+		err := s.mockHistoryEngine.RespondActivityTaskCompleted(&history.RespondActivityTaskCompletedRequest{
+			DomainUUID: common.StringPtr(domainID),
+			CompleteRequest: &workflow.RespondActivityTaskCompletedRequest{
+				TaskToken: taskToken,
+				Result:    tc.result,
+				Identity:  &identity,
+			},
+		})
+		s.Nil(err)
+		s.Equal(tc.recorded, recorded)
+	}
+	s.Equal(1, len(offloader.payloads))
+	s.Equal(testCases[1].result, offloader.payloads[0])
+}
+
 func (s *engineSuite) TestRespondActivityTaskCompletedByIdSuccess() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
@@ -2206,6 +5759,24 @@ func (s *engineSuite) TestRespondActivityTaskFailedSuccess() {
 	s.Equal(emptyEventID, di.StartedID)
 }
 
+func (s *engineSuite) TestRespondActivityTaskFailedUnknownFailureType() {
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: "wId",
+		RunID:      validRunID,
+		ScheduleID: 5,
+	})
+
+	err := s.mockHistoryEngine.RespondActivityTaskFailed(&history.RespondActivityTaskFailedRequest{
+		DomainUUID: common.StringPtr("domainId"),
+		FailedRequest: &workflow.RespondActivityTaskFailedRequest{
+			TaskToken:   taskToken,
+			Reason:      common.StringPtr("failed"),
+			FailureType: workflow.ActivityFailureType(5).Ptr(),
+		},
+	})
+	s.EqualError(err, "BadRequestError{Message: Unknown FailureType 5.}")
+}
+
 func (s *engineSuite) TestRespondActivityTaskFailedByIDSuccess() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
@@ -2240,35 +5811,216 @@ func (s *engineSuite) TestRespondActivityTaskFailedByIDSuccess() {
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
 	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: *we.RunId}
 
-	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	err := s.mockHistoryEngine.RespondActivityTaskFailed(&history.RespondActivityTaskFailedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		FailedRequest: &workflow.RespondActivityTaskFailedRequest{
+			TaskToken: taskToken,
+			Reason:    &failReason,
+			Details:   failDetails,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err)
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(9), executionBuilder.executionInfo.NextEventID)
+	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
+	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
+
+	s.True(executionBuilder.HasPendingDecisionTask())
+	di, ok := executionBuilder.GetPendingDecision(int64(8))
+	s.True(ok)
+	s.Equal(int32(200), di.DecisionTimeout)
+	s.Equal(int64(8), di.ScheduleID)
+	s.Equal(emptyEventID, di.StartedID)
+}
+
+func (s *engineSuite) TestRecordActivityTaskHeartBeatSuccess_NoTimer() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 5,
+	})
+	identity := "testIdentity"
+	activityID := "activity1_id"
+	activityType := "activity_type1"
+	activityInput := []byte("input1")
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		*decisionStartedEvent.EventId, nil, identity)
+	activityScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId, activityID,
+		activityType, tl, activityInput, 100, 10, 0)
+	addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
+
+	// No HeartBeat timer running.
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	detais := []byte("details")
+
+	_, err := s.mockHistoryEngine.RecordActivityTaskHeartbeat(&history.RecordActivityTaskHeartbeatRequest{
+		DomainUUID: common.StringPtr(domainID),
+		HeartbeatRequest: &workflow.RecordActivityTaskHeartbeatRequest{
+			TaskToken: taskToken,
+			Identity:  &identity,
+			Details:   detais,
+		},
+	})
+	s.Nil(err)
+}
+
+func (s *engineSuite) TestRecordActivityTaskHeartBeat_Checkpoint() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 5,
+	})
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		*decisionStartedEvent.EventId, nil, identity)
+	activityScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId,
+		"activity1_id", "activity_type1", tl, []byte("input1"), 100, 10, 0)
+	addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Times(3)
+
+	heartbeat := func(checkpoint *workflow.ActivityCheckpoint) (*workflow.RecordActivityTaskHeartbeatResponse, error) {
+		return s.mockHistoryEngine.RecordActivityTaskHeartbeat(&history.RecordActivityTaskHeartbeatRequest{
+			DomainUUID: common.StringPtr(domainID),
+			HeartbeatRequest: &workflow.RecordActivityTaskHeartbeatRequest{
+				TaskToken:  taskToken,
+				Identity:   &identity,
+				Checkpoint: checkpoint,
+			},
+		})
+	}
+
+	resp, err := heartbeat(&workflow.ActivityCheckpoint{
+		SequenceNumber: common.Int64Ptr(2),
+		Payload:        []byte("checkpoint2"),
+	})
+	s.Nil(err)
+	s.Equal(int64(2), resp.LastCheckpoint.GetSequenceNumber())
+	s.Equal([]byte("checkpoint2"), resp.LastCheckpoint.Payload)
+	s.Equal(1, len(updateRequest.UpsertActivityInfos))
+	s.Equal(int64(2), updateRequest.UpsertActivityInfos[0].CheckpointSequenceNumber)
+	s.Equal([]byte("checkpoint2"), updateRequest.UpsertActivityInfos[0].CheckpointPayload)
+
+	// a heartbeat retried with the persisted checkpoint is accepted
+	resp, err = heartbeat(&workflow.ActivityCheckpoint{
+		SequenceNumber: common.Int64Ptr(2),
+		Payload:        []byte("checkpoint2"),
+	})
+	s.Nil(err)
+	s.Equal(int64(2), resp.LastCheckpoint.GetSequenceNumber())
+	s.Equal([]byte("checkpoint2"), resp.LastCheckpoint.Payload)
+
+	// a checkpoint which is older than the persisted one, or conflicts with it, is rejected without updating the
+	// workflow
+	_, err = heartbeat(&workflow.ActivityCheckpoint{
+		SequenceNumber: common.Int64Ptr(2),
+		Payload:        []byte("stale"),
+	})
+	s.Equal(ErrCheckpointOutOfOrder, err)
+	_, err = heartbeat(&workflow.ActivityCheckpoint{
+		SequenceNumber: common.Int64Ptr(1),
+		Payload:        []byte("stale"),
+	})
+	s.Equal(ErrCheckpointOutOfOrder, err)
+
+	// heartbeats without a checkpoint keep the last one
+	resp, err = heartbeat(nil)
+	s.Nil(err)
+	s.Equal(int64(2), resp.LastCheckpoint.GetSequenceNumber())
+	s.Equal([]byte("checkpoint2"), resp.LastCheckpoint.Payload)
+}
+
+func (s *engineSuite) TestRecordActivityTaskHeartBeatSuccess_TimerRunning() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 5,
+	})
+	identity := "testIdentity"
+	activityID := "activity1_id"
+	activityType := "activity_type1"
+	activityInput := []byte("input1")
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		*decisionStartedEvent.EventId, nil, identity)
+	activityScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId, activityID,
+		activityType, tl, activityInput, 100, 10, 1)
+	addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	// HeartBeat timer running.
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
-	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondActivityTaskFailed(&history.RespondActivityTaskFailedRequest{
+	detais := []byte("details")
+
+	_, err := s.mockHistoryEngine.RecordActivityTaskHeartbeat(&history.RecordActivityTaskHeartbeatRequest{
 		DomainUUID: common.StringPtr(domainID),
-		FailedRequest: &workflow.RespondActivityTaskFailedRequest{
+		HeartbeatRequest: &workflow.RecordActivityTaskHeartbeatRequest{
 			TaskToken: taskToken,
-			Reason:    &failReason,
-			Details:   failDetails,
 			Identity:  &identity,
+			Details:   detais,
 		},
 	})
 	s.Nil(err)
 	executionBuilder := s.getBuilder(domainID, we)
-	s.Equal(int64(9), executionBuilder.executionInfo.NextEventID)
+	s.Equal(int64(7), executionBuilder.executionInfo.NextEventID)
 	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
 	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
-
-	s.True(executionBuilder.HasPendingDecisionTask())
-	di, ok := executionBuilder.GetPendingDecision(int64(8))
-	s.True(ok)
-	s.Equal(int32(200), di.DecisionTimeout)
-	s.Equal(int64(8), di.ScheduleID)
-	s.Equal(emptyEventID, di.StartedID)
+	s.False(executionBuilder.HasPendingDecisionTask())
 }
 
-func (s *engineSuite) TestRecordActivityTaskHeartBeatSuccess_NoTimer() {
+func (s *engineSuite) TestRecordActivityTaskHeartBeat_SlidesHeartbeatTimeout() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
@@ -2281,9 +6033,7 @@ func (s *engineSuite) TestRecordActivityTaskHeartBeatSuccess_NoTimer() {
 		ScheduleID: 5,
 	})
 	identity := "testIdentity"
-	activityID := "activity1_id"
-	activityType := "activity_type1"
-	activityInput := []byte("input1")
+	heartbeatTimeout := 10 * time.Second
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
 	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
@@ -2291,30 +6041,53 @@ func (s *engineSuite) TestRecordActivityTaskHeartBeatSuccess_NoTimer() {
 	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
 	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
 		*decisionStartedEvent.EventId, nil, identity)
-	activityScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId, activityID,
-		activityType, tl, activityInput, 100, 10, 0)
+	activityScheduledEvent, ai := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId,
+		"activity1_id", "activity_type1", tl, []byte("input1"), 1000, 10, int32(heartbeatTimeout.Seconds()))
 	addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
 
-	// No HeartBeat timer running.
+	// the original heartbeat deadline passed already, a heartbeat made in time before it slid the deadline forward
+	now := time.Now()
+	ai.StartToCloseTimeout = 1000
+	ai.StartedTime = now.Add(-15 * time.Second)
+	ai.LastHeartBeatUpdatedTime = now.Add(-7 * time.Second)
+	originalDeadline := ai.StartedTime.Add(heartbeatTimeout)
+	slidDeadline := ai.LastHeartBeatUpdatedTime.Add(heartbeatTimeout)
+
 	ms := createMutableState(msBuilder)
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	detais := []byte("details")
-
 	_, err := s.mockHistoryEngine.RecordActivityTaskHeartbeat(&history.RecordActivityTaskHeartbeatRequest{
 		DomainUUID: common.StringPtr(domainID),
 		HeartbeatRequest: &workflow.RecordActivityTaskHeartbeatRequest{
 			TaskToken: taskToken,
 			Identity:  &identity,
-			Details:   detais,
+			Details:   []byte("details"),
 		},
 	})
 	s.Nil(err)
+
+	executionBuilder := s.getBuilder(domainID, we)
+	updatedInfo, ok := executionBuilder.GetActivityInfo(5)
+	s.True(ok)
+	s.False(updatedInfo.LastHeartBeatUpdatedTime.Before(now))
+
+	tBuilder := newTimerBuilder(s.config, bark.NewLoggerFromLogrus(log.New()), common.NewRealTimeSource())
+	var heartbeatTimer *timerDetails
+	for _, td := range tBuilder.GetActivityTimers(executionBuilder) {
+		if td.TimeoutType == workflow.TimeoutTypeHeartbeat {
+			heartbeatTimer = td
+		}
+	}
+	s.NotNil(heartbeatTimer)
+	s.Equal(updatedInfo.LastHeartBeatUpdatedTime.Add(heartbeatTimeout), heartbeatTimer.TimerSequenceID.VisibilityTimestamp)
+	// neither the timer armed for the original deadline nor the one re-armed for the slid deadline times it out
+	s.False(tBuilder.IsTimerExpired(heartbeatTimer, originalDeadline))
+	s.False(tBuilder.IsTimerExpired(heartbeatTimer, slidDeadline))
 }
 
-func (s *engineSuite) TestRecordActivityTaskHeartBeatSuccess_TimerRunning() {
+func (s *engineSuite) TestRecordActivityTaskHeartBeat_AcceptCancel() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
@@ -2340,30 +6113,56 @@ func (s *engineSuite) TestRecordActivityTaskHeartBeatSuccess_TimerRunning() {
 	activityScheduledEvent, _ := addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId, activityID,
 		activityType, tl, activityInput, 100, 10, 1)
 	addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
+	msBuilder.AddActivityTaskCancelRequestedEvent(*decisionCompletedEvent.EventId, activityID, identity)
 
 	ms := createMutableState(msBuilder)
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
 
-	// HeartBeat timer running.
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
-	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
-
-	detais := []byte("details")
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+		updateRequest = args.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	})
 
-	_, err := s.mockHistoryEngine.RecordActivityTaskHeartbeat(&history.RecordActivityTaskHeartbeatRequest{
+	response, err := s.mockHistoryEngine.RecordActivityTaskHeartbeat(&history.RecordActivityTaskHeartbeatRequest{
 		DomainUUID: common.StringPtr(domainID),
 		HeartbeatRequest: &workflow.RecordActivityTaskHeartbeatRequest{
-			TaskToken: taskToken,
-			Identity:  &identity,
-			Details:   detais,
+			TaskToken:    taskToken,
+			Identity:     &identity,
+			Details:      []byte("cleaning up"),
+			AcceptCancel: common.BoolPtr(true),
 		},
 	})
 	s.Nil(err)
+	s.True(response.GetCancelRequested())
+	s.Equal(1, len(updateRequest.TimerTasks))
+	s.IsType(&persistence.ActivityTimeoutTask{}, updateRequest.TimerTasks[0])
+
 	executionBuilder := s.getBuilder(domainID, we)
-	s.Equal(int64(7), executionBuilder.executionInfo.NextEventID)
-	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
-	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
-	s.False(executionBuilder.HasPendingDecisionTask())
+	ai, ok := executionBuilder.GetActivityInfo(5)
+	s.True(ok)
+	s.True(ai.AcceptedCancel)
+	s.True(ai.CancelRequested)
+
+	// the close timeouts of the activity are extended by the grace period
+	tBuilder := s.mockHistoryEngine.getTimerBuilder(&we)
+	grace := time.Duration(s.config.ActivityAcceptedCancelGraceInSecs) * time.Second
+	for _, td := range tBuilder.GetActivityTimers(executionBuilder) {
+		switch td.TimeoutType {
+		case workflow.TimeoutTypeStartToClose:
+			s.Equal(ai.StartedTime.Add(time.Second+grace), td.TimerSequenceID.VisibilityTimestamp)
+		case workflow.TimeoutTypeScheduleToClose:
+			s.Equal(ai.ScheduledTime.Add(100*time.Second+grace), td.TimerSequenceID.VisibilityTimestamp)
+		}
+	}
+
+	describeResponse, err := s.mockHistoryEngine.DescribeWorkflowExecution(&history.DescribeWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Request:    &workflow.DescribeWorkflowExecutionRequest{Execution: &we},
+	})
+	s.Nil(err)
+	s.Equal(1, len(describeResponse.PendingActivities))
+	s.True(describeResponse.PendingActivities[0].GetAcceptedCancel())
 }
 
 func (s *engineSuite) TestRecordActivityTaskHeartBeatByIDSuccess() {
@@ -2680,7 +6479,7 @@ func (s *engineSuite) TestRequestCancel_RespondDecisionTaskCompleted_NotSchedule
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken:        taskToken,
@@ -2739,7 +6538,7 @@ func (s *engineSuite) TestRequestCancel_RespondDecisionTaskCompleted_Scheduled()
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken:        taskToken,
@@ -2800,7 +6599,7 @@ func (s *engineSuite) TestRequestCancel_RespondDecisionTaskCompleted_NoHeartBeat
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken:        taskToken,
@@ -2892,9 +6691,208 @@ func (s *engineSuite) TestRequestCancel_RespondDecisionTaskCompleted_Success() {
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
 
 	decisions := []*workflow.Decision{{
-		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeRequestCancelActivityTask),
-		RequestCancelActivityTaskDecisionAttributes: &workflow.RequestCancelActivityTaskDecisionAttributes{
-			ActivityId: common.StringPtr(activityID),
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeRequestCancelActivityTask),
+		RequestCancelActivityTaskDecisionAttributes: &workflow.RequestCancelActivityTaskDecisionAttributes{
+			ActivityId: common.StringPtr(activityID),
+		},
+	}}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken:        taskToken,
+			Decisions:        decisions,
+			ExecutionContext: []byte("context"),
+			Identity:         &identity,
+		},
+	})
+	s.Nil(err)
+
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(11), executionBuilder.executionInfo.NextEventID)
+	s.Equal(int64(8), executionBuilder.executionInfo.LastProcessedEvent)
+	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
+	s.False(executionBuilder.HasPendingDecisionTask())
+
+	// Try recording activity heartbeat
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	activityTaskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: "wId",
+		RunID:      we.GetRunId(),
+		ScheduleID: 5,
+	})
+
+	hbResponse, err := s.mockHistoryEngine.RecordActivityTaskHeartbeat(&history.RecordActivityTaskHeartbeatRequest{
+		DomainUUID: common.StringPtr(domainID),
+		HeartbeatRequest: &workflow.RecordActivityTaskHeartbeatRequest{
+			TaskToken: activityTaskToken,
+			Identity:  &identity,
+			Details:   []byte("details"),
+		},
+	})
+	s.Nil(err)
+	s.NotNil(hbResponse)
+	s.True(*hbResponse.CancelRequested)
+
+	// Try cancelling the request.
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	err = s.mockHistoryEngine.RespondActivityTaskCanceled(&history.RespondActivityTaskCanceledRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CancelRequest: &workflow.RespondActivityTaskCanceledRequest{
+			TaskToken: activityTaskToken,
+			Identity:  &identity,
+			Details:   []byte("details"),
+		},
+	})
+	s.Nil(err)
+
+	executionBuilder = s.getBuilder(domainID, we)
+	s.Equal(int64(13), executionBuilder.executionInfo.NextEventID)
+	s.Equal(int64(8), executionBuilder.executionInfo.LastProcessedEvent)
+	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
+	s.True(executionBuilder.HasPendingDecisionTask())
+}
+
+func (s *engineSuite) TestStarTimer_DuplicateTimerID() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+	timerID := "t1"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeStartTimer),
+		StartTimerDecisionAttributes: &workflow.StartTimerDecisionAttributes{
+			TimerId:                   common.StringPtr(timerID),
+			StartToFireTimeoutSeconds: common.Int64Ptr(1),
+		},
+	}}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken:        taskToken,
+			Decisions:        decisions,
+			ExecutionContext: []byte("context"),
+			Identity:         &identity,
+		},
+	})
+	s.Nil(err)
+
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
+
+	// Try to add the same timer ID again.
+	di2 := addDecisionTaskScheduledEvent(executionBuilder)
+	addDecisionTaskStartedEvent(executionBuilder, di2.ScheduleID, tl, identity)
+	taskToken2, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: di2.ScheduleID,
+	})
+
+	ms2 := createMutableState(executionBuilder)
+	gwmsResponse2 := &persistence.GetWorkflowExecutionResponse{State: ms2}
+
+	decisionFailedEvent := false
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse2, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
+		req := arguments.Get(0).(*persistence.AppendHistoryEventsRequest)
+		hs := persistence.NewJSONHistorySerializer()
+		h, err := hs.Deserialize(req.Events)
+		if err != nil {
+			panic(err)
+		}
+		decTaskIndex := len(h.Events) - 1
+		if decTaskIndex >= 0 && *h.Events[decTaskIndex].EventType == workflow.EventTypeDecisionTaskFailed {
+			decisionFailedEvent = true
+		}
+	}).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	_, err = s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken:        taskToken2,
+			Decisions:        decisions,
+			ExecutionContext: []byte("context"),
+			Identity:         &identity,
+		},
+	})
+	s.Nil(err)
+
+	s.True(decisionFailedEvent)
+	executionBuilder = s.getBuilder(domainID, we)
+	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
+	s.True(executionBuilder.HasPendingDecisionTask())
+	di3, ok := executionBuilder.GetPendingDecision(executionBuilder.executionInfo.NextEventID)
+	s.True(ok, "DI.ScheduleID: %v, ScheduleID: %v, StartedID: %v", di2.ScheduleID,
+		executionBuilder.executionInfo.DecisionScheduleID, executionBuilder.executionInfo.DecisionStartedID)
+	s.Equal(executionBuilder.executionInfo.NextEventID, di3.ScheduleID)
+	s.Equal(int64(1), di3.Attempt)
+}
+
+func (s *engineSuite) TestUserTimer_RespondDecisionTaskCompleted() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 6,
+	})
+	identity := "testIdentity"
+	timerID := "t1"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	// Verify cancel timer with a start event.
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		*decisionStartedEvent.EventId, nil, identity)
+	addTimerStartedEvent(msBuilder, *decisionCompletedEvent.EventId, timerID, 10)
+	di2 := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di2.ScheduleID, tl, identity)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeCancelTimer),
+		CancelTimerDecisionAttributes: &workflow.CancelTimerDecisionAttributes{
+			TimerId: common.StringPtr(timerID),
 		},
 	}}
 
@@ -2902,7 +6900,7 @@ func (s *engineSuite) TestRequestCancel_RespondDecisionTaskCompleted_Success() {
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken:        taskToken,
@@ -2914,54 +6912,13 @@ func (s *engineSuite) TestRequestCancel_RespondDecisionTaskCompleted_Success() {
 	s.Nil(err)
 
 	executionBuilder := s.getBuilder(domainID, we)
-	s.Equal(int64(11), executionBuilder.executionInfo.NextEventID)
-	s.Equal(int64(8), executionBuilder.executionInfo.LastProcessedEvent)
+	s.Equal(int64(10), executionBuilder.executionInfo.NextEventID)
+	s.Equal(int64(7), executionBuilder.executionInfo.LastProcessedEvent)
 	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
 	s.False(executionBuilder.HasPendingDecisionTask())
-
-	// Try recording activity heartbeat
-	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
-
-	activityTaskToken, _ := json.Marshal(&common.TaskToken{
-		WorkflowID: "wId",
-		RunID:      we.GetRunId(),
-		ScheduleID: 5,
-	})
-
-	hbResponse, err := s.mockHistoryEngine.RecordActivityTaskHeartbeat(&history.RecordActivityTaskHeartbeatRequest{
-		DomainUUID: common.StringPtr(domainID),
-		HeartbeatRequest: &workflow.RecordActivityTaskHeartbeatRequest{
-			TaskToken: activityTaskToken,
-			Identity:  &identity,
-			Details:   []byte("details"),
-		},
-	})
-	s.Nil(err)
-	s.NotNil(hbResponse)
-	s.True(*hbResponse.CancelRequested)
-
-	// Try cancelling the request.
-	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
-	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
-
-	err = s.mockHistoryEngine.RespondActivityTaskCanceled(&history.RespondActivityTaskCanceledRequest{
-		DomainUUID: common.StringPtr(domainID),
-		CancelRequest: &workflow.RespondActivityTaskCanceledRequest{
-			TaskToken: activityTaskToken,
-			Identity:  &identity,
-			Details:   []byte("details"),
-		},
-	})
-	s.Nil(err)
-
-	executionBuilder = s.getBuilder(domainID, we)
-	s.Equal(int64(13), executionBuilder.executionInfo.NextEventID)
-	s.Equal(int64(8), executionBuilder.executionInfo.LastProcessedEvent)
-	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
-	s.True(executionBuilder.HasPendingDecisionTask())
 }
 
-func (s *engineSuite) TestStarTimer_DuplicateTimerID() {
+func (s *engineSuite) TestCancelTimer_RespondDecisionTaskCompleted_NoStartTimer() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
@@ -2977,7 +6934,7 @@ func (s *engineSuite) TestStarTimer_DuplicateTimerID() {
 	timerID := "t1"
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
-
+	// Verify cancel timer with a start event.
 	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
 	di := addDecisionTaskScheduledEvent(msBuilder)
 	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
@@ -2986,10 +6943,9 @@ func (s *engineSuite) TestStarTimer_DuplicateTimerID() {
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
 
 	decisions := []*workflow.Decision{{
-		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeStartTimer),
-		StartTimerDecisionAttributes: &workflow.StartTimerDecisionAttributes{
-			TimerId:                   common.StringPtr(timerID),
-			StartToFireTimeoutSeconds: common.Int64Ptr(1),
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeCancelTimer),
+		CancelTimerDecisionAttributes: &workflow.CancelTimerDecisionAttributes{
+			TimerId: common.StringPtr(timerID),
 		},
 	}}
 
@@ -2997,7 +6953,7 @@ func (s *engineSuite) TestStarTimer_DuplicateTimerID() {
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
 			TaskToken:        taskToken,
@@ -3009,117 +6965,383 @@ func (s *engineSuite) TestStarTimer_DuplicateTimerID() {
 	s.Nil(err)
 
 	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
+	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
 	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
+	s.False(executionBuilder.HasPendingDecisionTask())
+}
 
-	// Try to add the same timer ID again.
-	di2 := addDecisionTaskScheduledEvent(executionBuilder)
-	addDecisionTaskStartedEvent(executionBuilder, di2.ScheduleID, tl, identity)
-	taskToken2, _ := json.Marshal(&common.TaskToken{
-		WorkflowID: *we.WorkflowId,
-		RunID:      *we.RunId,
-		ScheduleID: di2.ScheduleID,
+func (s *engineSuite) TestRecordChildExecutionCompleted_RunIDMismatch() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	tl := "testTaskList"
+	identity := "testIdentity"
+	childWorkflowID := "child workflowID"
+	childRunID := uuid.New()
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	startedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	completedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, startedEvent.GetEventId(), nil, identity)
+	initiatedEvent, _ := addStartChildWorkflowExecutionInitiatedEvent(msBuilder, completedEvent.GetEventId(), uuid.New(),
+		domainID, childWorkflowID, "child wType", tl, nil, 100, 200)
+	addChildWorkflowExecutionStartedEvent(msBuilder, initiatedEvent.GetEventId(), domainID, childWorkflowID, childRunID,
+		"child wType")
+
+	completionRequest := func(runID string) *history.RecordChildExecutionCompletedRequest {
+		return &history.RecordChildExecutionCompletedRequest{
+			DomainUUID:        common.StringPtr(domainID),
+			WorkflowExecution: &we,
+			InitiatedId:       common.Int64Ptr(initiatedEvent.GetEventId()),
+			CompletedExecution: &workflow.WorkflowExecution{
+				WorkflowId: common.StringPtr(childWorkflowID),
+				RunId:      common.StringPtr(runID),
+			},
+			CompletionEvent: &workflow.HistoryEvent{
+				EventType: common.EventTypePtr(workflow.EventTypeWorkflowExecutionCompleted),
+				WorkflowExecutionCompletedEventAttributes: &workflow.WorkflowExecutionCompletedEventAttributes{
+					Result: []byte("child result"),
+				},
+			},
+		}
+	}
+
+	// A late completion from a superseded run of the child must not close the pending child
+	ms := createMutableState(msBuilder)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(&persistence.GetWorkflowExecutionResponse{State: ms}, nil).Once()
+	err := s.mockHistoryEngine.RecordChildExecutionCompleted(completionRequest(uuid.New()))
+	s.IsType(&workflow.EntityNotExistsError{}, err)
+
+	ms = createMutableState(msBuilder)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(&persistence.GetWorkflowExecutionResponse{State: ms}, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+	err = s.mockHistoryEngine.RecordChildExecutionCompleted(completionRequest(childRunID))
+	s.Nil(err)
+}
+
+func (s *engineSuite) TestSignalWorkflowExecution() {
+	signalRequest := &history.SignalWorkflowExecutionRequest{}
+	err := s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
+	s.EqualError(err, "BadRequestError{Message: Missing domain UUID.}")
+
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	identity := "testIdentity"
+	signalName := "my signal name"
+	input := []byte("test input")
+	signalRequest = &history.SignalWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalRequest: &workflow.SignalWorkflowExecutionRequest{
+			Domain:            common.StringPtr(domainID),
+			WorkflowExecution: &we,
+			Identity:          common.StringPtr(identity),
+			SignalName:        common.StringPtr(signalName),
+			Input:             input,
+		},
+	}
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	err = s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
+	s.Nil(err)
+
+	describeResponse, err := s.mockHistoryEngine.DescribeWorkflowExecution(&history.DescribeWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Request:    &workflow.DescribeWorkflowExecutionRequest{Execution: &we},
 	})
+	s.Nil(err)
+	s.Equal(int64(1), describeResponse.WorkflowExecutionInfo.GetSignalCount())
+	s.Equal(signalName, describeResponse.WorkflowExecutionInfo.GetLastSignalName())
+	s.NotZero(describeResponse.WorkflowExecutionInfo.GetLastSignalTimestamp())
+}
 
-	ms2 := createMutableState(executionBuilder)
-	gwmsResponse2 := &persistence.GetWorkflowExecutionResponse{State: ms2}
+func (s *engineSuite) TestStartWorkflowExecution_DomainNotRegistered() {
+	identity := "testIdentity"
 
-	decisionFailedEvent := false
-	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse2, nil).Once()
-	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
-		req := arguments.Get(0).(*persistence.AppendHistoryEventsRequest)
-		hs := persistence.NewJSONHistorySerializer()
-		h, err := hs.Deserialize(req.Events)
-		if err != nil {
-			panic(err)
+	testCases := []struct {
+		globalDomainEnabled bool
+		getDomainErr        error
+		status              int
+		expectedErr         error
+	}{
+		{
+			globalDomainEnabled: false,
+			getDomainErr:        &workflow.EntityNotExistsError{Message: "domain not found"},
+			expectedErr:         &workflow.EntityNotExistsError{},
+		},
+		{
+			globalDomainEnabled: false,
+			status:              persistence.DomainStatusDeprecated,
+			expectedErr:         &workflow.BadRequestError{},
+		},
+		{
+			globalDomainEnabled: true,
+			getDomainErr:        &workflow.EntityNotExistsError{Message: "domain not found"},
+			expectedErr:         &workflow.EntityNotExistsError{},
+		},
+		{
+			globalDomainEnabled: true,
+			status:              persistence.DomainStatusDeprecated,
+			expectedErr:         &workflow.BadRequestError{},
+		},
+	}
+
+	shard := s.mockHistoryEngine.shard.(*shardContextWrapper).ShardContext.(*shardContextImpl)
+	for i, tc := range testCases {
+		domainID := fmt.Sprintf("domainId-%v", i)
+		shard.service = service.NewTestService(cluster.GetTestClusterMetadata(tc.globalDomainEnabled, true),
+			s.mockMessagingClient, s.mockMetricClient, s.logger)
+		if tc.getDomainErr != nil {
+			s.mockMetadataMgr.On("GetDomain", &persistence.GetDomainRequest{ID: domainID}).Return(nil, tc.getDomainErr).Once()
+		} else {
+			s.mockMetadataMgr.On("GetDomain", &persistence.GetDomainRequest{ID: domainID}).Return(&persistence.GetDomainResponse{
+				Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName", Status: tc.status},
+				Config: &persistence.DomainConfig{Retention: 1},
+				ReplicationConfig: &persistence.DomainReplicationConfig{
+					ActiveClusterName: cluster.TestCurrentClusterName,
+					Clusters: []*persistence.ClusterReplicationConfig{
+						{ClusterName: cluster.TestCurrentClusterName},
+					},
+				},
+				IsGlobalDomain: tc.globalDomainEnabled,
+			}, nil).Once()
 		}
-		decTaskIndex := len(h.Events) - 1
-		if decTaskIndex >= 0 && *h.Events[decTaskIndex].EventType == workflow.EventTypeDecisionTaskFailed {
-			decisionFailedEvent = true
+
+		// no history is appended and no execution is created for a domain which cannot accept new workflows
+		_, err := s.mockHistoryEngine.StartWorkflowExecution(&history.StartWorkflowExecutionRequest{
+			DomainUUID: common.StringPtr(domainID),
+			StartRequest: &workflow.StartWorkflowExecutionRequest{
+				Domain:                              common.StringPtr("domainName"),
+				WorkflowId:                          common.StringPtr("wId"),
+				WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("wType")},
+				TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+				ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+				TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+				Identity:                            common.StringPtr(identity),
+				RequestId:                           common.StringPtr(uuid.New()),
+			},
+		})
+		s.IsType(tc.expectedErr, err)
+	}
+}
+
+func (s *engineSuite) TestGetDeleteWorkflowTasks_WorkflowTypeRetention() {
+	domainID := "domainId"
+	domainName := "domainName"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+
+	workflowTypeRetentionDays := s.config.WorkflowTypeRetentionDays
+	defer func() { s.config.WorkflowTypeRetentionDays = workflowTypeRetentionDays }()
+	s.config.WorkflowTypeRetentionDays = func(opts ...dynamicconfig.FilterOption) int {
+		filters := make(map[dynamicconfig.Filter]interface{})
+		for _, opt := range opts {
+			opt(filters)
 		}
-	}).Once()
-	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+		if filters[dynamicconfig.DomainName] != domainName {
+			return 0
+		}
+		switch filters[dynamicconfig.WorkflowTypeName] {
+		case "wType-short":
+			return 2
+		case "wType-outOfBounds":
+			return 1000
+		}
+		return 0
+	}
+
+	s.mockMetadataMgr.On("GetDomain", &persistence.GetDomainRequest{ID: domainID}).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: domainName},
+		Config: &persistence.DomainConfig{Retention: 7},
+		ReplicationConfig: &persistence.DomainReplicationConfig{
+			ActiveClusterName: cluster.TestCurrentClusterName,
+			Clusters: []*persistence.ClusterReplicationConfig{
+				{ClusterName: cluster.TestCurrentClusterName},
+			},
+		},
+	}, nil)
+
+	testCases := []struct {
+		workflowTypeName string
+		retentionInDays  int
+	}{
+		{workflowTypeName: "wType-short", retentionInDays: 2},
+		{workflowTypeName: "wType", retentionInDays: 7},
+		// overrides outside of the bounds of the domain fall back to the retention of the domain
+		{workflowTypeName: "wType-outOfBounds", retentionInDays: 7},
+	}
+	for _, tc := range testCases {
+		tBuilder := s.mockHistoryEngine.getTimerBuilder(&we)
+		closeTask, cleanupTask, err := s.mockHistoryEngine.getDeleteWorkflowTasks(domainID, tc.workflowTypeName, tBuilder)
+		s.Nil(err)
+		s.IsType(&persistence.CloseExecutionTask{}, closeTask)
+		expectedExpiry := time.Now().Add(time.Duration(tc.retentionInDays) * 24 * time.Hour)
+		s.WithinDuration(expectedExpiry, cleanupTask.(*persistence.DeleteHistoryEventTask).VisibilityTimestamp,
+			time.Minute, tc.workflowTypeName)
+	}
+}
+
+func (s *engineSuite) TestWriteAPIsRejectedForStandbyDomain() {
+	domainID := "domainId"
+	domainName := "domainName"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	identity := "testIdentity"
 
-	err = s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	shard := s.mockHistoryEngine.shard.(*shardContextWrapper).ShardContext.(*shardContextImpl)
+	shard.service = service.NewTestService(cluster.GetTestClusterMetadata(true, true), s.mockMessagingClient,
+		s.mockMetricClient, s.logger)
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: domainName},
+		Config: &persistence.DomainConfig{Retention: 1},
+		ReplicationConfig: &persistence.DomainReplicationConfig{
+			ActiveClusterName: cluster.TestAlternativeClusterName,
+			Clusters: []*persistence.ClusterReplicationConfig{
+				{ClusterName: cluster.TestCurrentClusterName},
+				{ClusterName: cluster.TestAlternativeClusterName},
+			},
+		},
+		IsGlobalDomain: true,
+	}, nil)
+
+	expectedErr := &workflow.DomainNotActiveError{
+		Message: fmt.Sprintf("Domain: %s is active in cluster: %s, while current cluster %s is a standby cluster.",
+			domainName, cluster.TestAlternativeClusterName, cluster.TestCurrentClusterName),
+		DomainName:     domainName,
+		CurrentCluster: cluster.TestCurrentClusterName,
+		ActiveCluster:  cluster.TestAlternativeClusterName,
+	}
+
+	_, err := s.mockHistoryEngine.StartWorkflowExecution(&history.StartWorkflowExecutionRequest{
 		DomainUUID: common.StringPtr(domainID),
-		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
-			TaskToken:        taskToken2,
-			Decisions:        decisions,
-			ExecutionContext: []byte("context"),
-			Identity:         &identity,
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          we.WorkflowId,
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr(identity),
+			RequestId:                           common.StringPtr(uuid.New()),
+		},
+	})
+	s.Equal(expectedErr, err)
+
+	err = s.mockHistoryEngine.SignalWorkflowExecution(&history.SignalWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalRequest: &workflow.SignalWorkflowExecutionRequest{
+			Domain:            common.StringPtr(domainID),
+			WorkflowExecution: &we,
+			Identity:          common.StringPtr(identity),
+			SignalName:        common.StringPtr("my signal name"),
+		},
+	})
+	s.Equal(expectedErr, err)
+
+	err = s.mockHistoryEngine.TerminateWorkflowExecution(&history.TerminateWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		TerminateRequest: &workflow.TerminateWorkflowExecutionRequest{
+			Domain:            common.StringPtr(domainID),
+			WorkflowExecution: &we,
+			Identity:          common.StringPtr(identity),
 		},
 	})
+	s.Equal(expectedErr, err)
+
+	// reads are still served by the standby cluster
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", "testTaskList", []byte("input"), 100, 200, identity)
+	ms := createMutableState(msBuilder)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: ms}, nil).Once()
+	_, err = s.mockHistoryEngine.DescribeWorkflowExecution(&history.DescribeWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Request:    &workflow.DescribeWorkflowExecutionRequest{Execution: &we},
+	})
 	s.Nil(err)
+}
 
-	s.True(decisionFailedEvent)
-	executionBuilder = s.getBuilder(domainID, we)
-	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
-	s.True(executionBuilder.HasPendingDecisionTask())
-	di3, ok := executionBuilder.GetPendingDecision(executionBuilder.executionInfo.NextEventID)
-	s.True(ok, "DI.ScheduleID: %v, ScheduleID: %v, StartedID: %v", di2.ScheduleID,
-		executionBuilder.executionInfo.DecisionScheduleID, executionBuilder.executionInfo.DecisionStartedID)
-	s.Equal(executionBuilder.executionInfo.NextEventID, di3.ScheduleID)
-	s.Equal(int64(1), di3.Attempt)
+func (s *engineSuite) TestSignalWorkflowExecution_InputTooLarge() {
+	domainID := "domainId"
+	signalRequest := &history.SignalWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalRequest: &workflow.SignalWorkflowExecutionRequest{
+			Domain: common.StringPtr(domainID),
+			WorkflowExecution: &workflow.WorkflowExecution{
+				WorkflowId: common.StringPtr("wId"),
+				RunId:      common.StringPtr(validRunID),
+			},
+			Identity:   common.StringPtr("testIdentity"),
+			SignalName: common.StringPtr("my signal name"),
+			Input:      make([]byte, s.config.MaxSignalInputSize+1),
+		},
+	}
+
+	err := s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
+	s.EqualError(err, fmt.Sprintf("BadRequestError{Message: Signal input size of %v bytes exceeds the limit of %v bytes.}",
+		s.config.MaxSignalInputSize+1, s.config.MaxSignalInputSize))
 }
 
-func (s *engineSuite) TestUserTimer_RespondDecisionTaskCompleted() {
+// Test signal decision by adding request ID
+func (s *engineSuite) TestSignalWorkflowExecution_DuplicateRequest() {
+	signalRequest := &history.SignalWorkflowExecutionRequest{}
+	err := s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
+	s.EqualError(err, "BadRequestError{Message: Missing domain UUID.}")
+
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
-		WorkflowId: common.StringPtr("wId"),
+		WorkflowId: common.StringPtr("wId2"),
 		RunId:      common.StringPtr(validRunID),
 	}
-	tl := "testTaskList"
-	taskToken, _ := json.Marshal(&common.TaskToken{
-		WorkflowID: *we.WorkflowId,
-		RunID:      *we.RunId,
-		ScheduleID: 6,
-	})
-	identity := "testIdentity"
-	timerID := "t1"
+	identity := "testIdentity2"
+	signalName := "my signal name 2"
+	input := []byte("test input 2")
+	requestID := uuid.New()
+	signalRequest = &history.SignalWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalRequest: &workflow.SignalWorkflowExecutionRequest{
+			Domain:            common.StringPtr(domainID),
+			WorkflowExecution: &we,
+			Identity:          common.StringPtr(identity),
+			SignalName:        common.StringPtr(signalName),
+			Input:             input,
+			RequestId:         common.StringPtr(requestID),
+		},
+	}
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
-	// Verify cancel timer with a start event.
-	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
-	di := addDecisionTaskScheduledEvent(msBuilder)
-	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
-	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
-		*decisionStartedEvent.EventId, nil, identity)
-	addTimerStartedEvent(msBuilder, *decisionCompletedEvent.EventId, timerID, 10)
-	di2 := addDecisionTaskScheduledEvent(msBuilder)
-	addDecisionTaskStartedEvent(msBuilder, di2.ScheduleID, tl, identity)
-
 	ms := createMutableState(msBuilder)
+	// assume duplicate request id
+	ms.SignalRequestedIDs = make(map[string]struct{})
+	ms.SignalRequestedIDs[requestID] = struct{}{}
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
 
-	decisions := []*workflow.Decision{{
-		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeCancelTimer),
-		CancelTimerDecisionAttributes: &workflow.CancelTimerDecisionAttributes{
-			TimerId: common.StringPtr(timerID),
-		},
-	}}
-
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
-	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
-		DomainUUID: common.StringPtr(domainID),
-		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
-			TaskToken:        taskToken,
-			Decisions:        decisions,
-			ExecutionContext: []byte("context"),
-			Identity:         &identity,
-		},
-	})
+	err = s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
 	s.Nil(err)
-
-	executionBuilder := s.getBuilder(domainID, we)
-	s.Equal(int64(10), executionBuilder.executionInfo.NextEventID)
-	s.Equal(int64(7), executionBuilder.executionInfo.LastProcessedEvent)
-	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
-	s.False(executionBuilder.HasPendingDecisionTask())
 }
 
-func (s *engineSuite) TestCancelTimer_RespondDecisionTaskCompleted_NoStartTimer() {
+func (s *engineSuite) TestSignalWorkflowExecution_BufferedSignalLimitExceeded() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
@@ -3132,53 +7354,81 @@ func (s *engineSuite) TestCancelTimer_RespondDecisionTaskCompleted_NoStartTimer(
 		ScheduleID: 2,
 	})
 	identity := "testIdentity"
-	timerID := "t1"
+	signalRequest := &history.SignalWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalRequest: &workflow.SignalWorkflowExecutionRequest{
+			Domain:            common.StringPtr(domainID),
+			WorkflowExecution: &we,
+			Identity:          common.StringPtr(identity),
+			SignalName:        common.StringPtr("signal"),
+		},
+	}
+
+	maxBufferedSignals := s.config.MaxBufferedSignals
+	defer func() { s.config.MaxBufferedSignals = maxBufferedSignals }()
+	s.config.MaxBufferedSignals = 2
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
-	// Verify cancel timer with a start event.
 	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
 	di := addDecisionTaskScheduledEvent(msBuilder)
 	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
-
 	ms := createMutableState(msBuilder)
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
 
-	decisions := []*workflow.Decision{{
-		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeCancelTimer),
-		CancelTimerDecisionAttributes: &workflow.CancelTimerDecisionAttributes{
-			TimerId: common.StringPtr(timerID),
-		},
-	}}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(nil, &workflow.EntityNotExistsError{})
+	// signals are buffered behind the decision in flight, only mutable state is updated
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Twice()
+	for i := 0; i < 2; i++ {
+		err := s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
+		s.Nil(err)
+	}
 
+	// the rejection drops the cached mutable state, which is reloaded along with the buffered signals
+	ms = createMutableState(s.getBuilder(domainID, we))
+	gwmsResponse = &persistence.GetWorkflowExecutionResponse{State: ms}
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+
+	err := s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
+	s.IsType(&workflow.ServiceBusyError{}, err)
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(2), executionBuilder.executionInfo.SignalCount)
+
+	// completing the decision flushes the buffered signals
 	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
-
-	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+	_, err = s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
 		DomainUUID: common.StringPtr(domainID),
 		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
-			TaskToken:        taskToken,
-			Decisions:        decisions,
-			ExecutionContext: []byte("context"),
-			Identity:         &identity,
+			TaskToken: taskToken,
+			Decisions: []*workflow.Decision{{
+				DecisionType: common.DecisionTypePtr(workflow.DecisionTypeStartTimer),
+				StartTimerDecisionAttributes: &workflow.StartTimerDecisionAttributes{
+					TimerId:                   common.StringPtr("timer"),
+					StartToFireTimeoutSeconds: common.Int64Ptr(10),
+				},
+			}},
+			Identity: &identity,
 		},
 	})
 	s.Nil(err)
 
-	executionBuilder := s.getBuilder(domainID, we)
-	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
-	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
-	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
-	s.False(executionBuilder.HasPendingDecisionTask())
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+	err = s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
+	s.Nil(err)
+	executionBuilder = s.getBuilder(domainID, we)
+	s.Equal(int64(3), executionBuilder.executionInfo.SignalCount)
+	s.False(executionBuilder.HasBufferedEvents())
 }
 
-func (s *engineSuite) TestSignalWorkflowExecution() {
+func (s *engineSuite) TestSignalWorkflowExecution_Failed() {
 	signalRequest := &history.SignalWorkflowExecutionRequest{}
 	err := s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
 	s.EqualError(err, "BadRequestError{Message: Missing domain UUID.}")
 
 	domainID := "domainId"
-	we := workflow.WorkflowExecution{
+	we := &workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
 		RunId:      common.StringPtr(validRunID),
 	}
@@ -3189,7 +7439,7 @@ func (s *engineSuite) TestSignalWorkflowExecution() {
 		DomainUUID: common.StringPtr(domainID),
 		SignalRequest: &workflow.SignalWorkflowExecutionRequest{
 			Domain:            common.StringPtr(domainID),
-			WorkflowExecution: &we,
+			WorkflowExecution: we,
 			Identity:          common.StringPtr(identity),
 			SignalName:        common.StringPtr(signalName),
 			Input:             input,
@@ -3198,90 +7448,139 @@ func (s *engineSuite) TestSignalWorkflowExecution() {
 
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
 	ms := createMutableState(msBuilder)
+	ms.ExecutionInfo.State = persistence.WorkflowStateCompleted
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
 
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
-	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
-	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
 	err = s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
-	s.Nil(err)
+	s.EqualError(err, "EntityNotExistsError{Message: Workflow execution already completed.}")
 }
 
-// Test signal decision by adding request ID
-func (s *engineSuite) TestSignalWorkflowExecution_DuplicateRequest() {
-	signalRequest := &history.SignalWorkflowExecutionRequest{}
-	err := s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
-	s.EqualError(err, "BadRequestError{Message: Missing domain UUID.}")
-
+func (s *engineSuite) TestSignalWorkflowExecution_ConcurrentUpdateLimitExceeded() {
 	domainID := "domainId"
-	we := workflow.WorkflowExecution{
-		WorkflowId: common.StringPtr("wId2"),
+	we := &workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
 		RunId:      common.StringPtr(validRunID),
 	}
-	identity := "testIdentity2"
-	signalName := "my signal name 2"
-	input := []byte("test input 2")
-	requestID := uuid.New()
-	signalRequest = &history.SignalWorkflowExecutionRequest{
+	signalRequest := &history.SignalWorkflowExecutionRequest{
 		DomainUUID: common.StringPtr(domainID),
 		SignalRequest: &workflow.SignalWorkflowExecutionRequest{
 			Domain:            common.StringPtr(domainID),
-			WorkflowExecution: &we,
-			Identity:          common.StringPtr(identity),
-			SignalName:        common.StringPtr(signalName),
-			Input:             input,
-			RequestId:         common.StringPtr(requestID),
+			WorkflowExecution: we,
+			Identity:          common.StringPtr("testIdentity"),
+			SignalName:        common.StringPtr("my signal name"),
+			Input:             []byte("test input"),
 		},
 	}
 
+	// occupy the only update slot on the shard
+	s.mockHistoryEngine.updateSemaphore = make(chan struct{}, 1)
+	s.mockHistoryEngine.updateSemaphore <- struct{}{}
+
+	err := s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
+	s.Equal(ErrConcurrentUpdateLimitExceeded, err)
+
+	// once the slot is released the update goes through
+	<-s.mockHistoryEngine.updateSemaphore
 	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
 	ms := createMutableState(msBuilder)
-	// assume duplicate request id
-	ms.SignalRequestedIDs = make(map[string]struct{})
-	ms.SignalRequestedIDs[requestID] = struct{}{}
 	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
 
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
 	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
 
 	err = s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
 	s.Nil(err)
+	s.Equal(0, len(s.mockHistoryEngine.updateSemaphore))
 }
 
-func (s *engineSuite) TestSignalWorkflowExecution_Failed() {
-	signalRequest := &history.SignalWorkflowExecutionRequest{}
-	err := s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
-	s.EqualError(err, "BadRequestError{Message: Missing domain UUID.}")
+func (s *engineSuite) TestWriteAPIs_ConcurrentUpdateLimitExceeded() {
+	domainID := "domainId"
+	// occupy the only update slot on the shard
+	s.mockHistoryEngine.updateSemaphore = make(chan struct{}, 1)
+	s.mockHistoryEngine.updateSemaphore <- struct{}{}
+	defer func() { s.mockHistoryEngine.updateSemaphore = nil }()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domainName"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+
+	_, err := s.mockHistoryEngine.StartWorkflowExecution(&history.StartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:                              common.StringPtr(domainID),
+			WorkflowId:                          common.StringPtr("wId"),
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(100),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(10),
+			Identity:                            common.StringPtr("testIdentity"),
+		},
+	})
+	s.Equal(ErrConcurrentUpdateLimitExceeded, err)
+
+	_, err = s.mockHistoryEngine.RecordDecisionTaskStarted(&history.RecordDecisionTaskStartedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		WorkflowExecution: &workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr("wId"),
+			RunId:      common.StringPtr(validRunID),
+		},
+		ScheduleId: common.Int64Ptr(2),
+		TaskId:     common.Int64Ptr(100),
+		RequestId:  common.StringPtr("reqId"),
+		PollRequest: &workflow.PollForDecisionTaskRequest{
+			TaskList: &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			Identity: common.StringPtr("testIdentity"),
+		},
+	})
+	s.Equal(ErrConcurrentUpdateLimitExceeded, err)
+	s.Equal(0, s.mockHistoryEngine.inFlightOps)
+}
 
+func (s *engineSuite) TestSignalWorkflowExecution_EngineDraining() {
 	domainID := "domainId"
 	we := &workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr("wId"),
 		RunId:      common.StringPtr(validRunID),
 	}
-	identity := "testIdentity"
-	signalName := "my signal name"
-	input := []byte("test input")
-	signalRequest = &history.SignalWorkflowExecutionRequest{
+	signalRequest := &history.SignalWorkflowExecutionRequest{
 		DomainUUID: common.StringPtr(domainID),
 		SignalRequest: &workflow.SignalWorkflowExecutionRequest{
 			Domain:            common.StringPtr(domainID),
 			WorkflowExecution: we,
-			Identity:          common.StringPtr(identity),
-			SignalName:        common.StringPtr(signalName),
-			Input:             input,
+			Identity:          common.StringPtr("testIdentity"),
+			SignalName:        common.StringPtr("my signal name"),
+			Input:             []byte("test input"),
 		},
 	}
+	scope := tally.NewTestScope("test", nil)
+	s.mockHistoryEngine.metricsClient = metrics.NewClient(scope, metrics.History)
 
-	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
-	ms := createMutableState(msBuilder)
-	ms.ExecutionInfo.State = persistence.WorkflowStateCompleted
-	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
-
-	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
-
+	// an operation which completes within the timeout is waited for
+	endOperation, err := s.mockHistoryEngine.beginWriteOperation()
+	s.Nil(err)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		endOperation()
+	}()
+	s.mockHistoryEngine.drain(time.Minute)
+	s.Equal(0, s.mockHistoryEngine.inFlightOps)
+	s.Nil(scope.Snapshot().Counters()["test.shutdown-drain-abandoned+operation=WorkflowUpdate"])
+
+	// new write operations are rejected once the engine is draining
 	err = s.mockHistoryEngine.SignalWorkflowExecution(signalRequest)
-	s.EqualError(err, "EntityNotExistsError{Message: Workflow execution already completed.}")
+	s.IsType(&persistence.ShardOwnershipLostError{}, err)
+
+	// an operation still running once the timeout elapses is abandoned
+	s.mockHistoryEngine.draining = false
+	_, err = s.mockHistoryEngine.beginWriteOperation()
+	s.Nil(err)
+	s.mockHistoryEngine.drain(10 * time.Millisecond)
+	counter := scope.Snapshot().Counters()["test.shutdown-drain-abandoned+operation=WorkflowUpdate"]
+	s.NotNil(counter)
+	s.Equal(int64(1), counter.Value())
 }
 
 func (s *engineSuite) TestRemoveSignalMutableState() {
@@ -3311,32 +7610,334 @@ func (s *engineSuite) TestRemoveSignalMutableState() {
 	s.Nil(err)
 }
 
+func (s *engineSuite) TestValidateRecordMarkerAttributes() {
+	var attributes *workflow.RecordMarkerDecisionAttributes
+	err := validateRecordMarkerAttributes(attributes, 10)
+	s.EqualError(err, "BadRequestError{Message: RecordMarkerDecisionAttributes is not set on decision.}")
+
+	attributes = &workflow.RecordMarkerDecisionAttributes{MarkerName: common.StringPtr("")}
+	err = validateRecordMarkerAttributes(attributes, 10)
+	s.EqualError(err, "BadRequestError{Message: MarkerName is not set on decision.}")
+
+	attributes.MarkerName = common.StringPtr("marker name")
+	attributes.Details = []byte("0123456789a")
+	err = validateRecordMarkerAttributes(attributes, 10)
+	s.EqualError(err, "BadRequestError{Message: Marker details size of 11 bytes exceeds the limit of 10 bytes.}")
+
+	// a zero limit is not enforced
+	err = validateRecordMarkerAttributes(attributes, 0)
+	s.Nil(err)
+
+	attributes.Details = []byte("0123456789")
+	err = validateRecordMarkerAttributes(attributes, 10)
+	s.Nil(err)
+}
+
+func (s *engineSuite) TestValidateTimerScheduleAttributes_StartToFireTimeout() {
+	now := time.Unix(1500000000, 0)
+	maxTimeout := maxTimerStartToFireTimeoutSeconds(now)
+	s.Equal(int64(7723372036), maxTimeout)
+
+	attributes := &workflow.StartTimerDecisionAttributes{
+		TimerId:                   common.StringPtr("timer1"),
+		StartToFireTimeoutSeconds: common.Int64Ptr(maxTimeout),
+	}
+	// a zero limit only rejects timeouts overflowing the expiry time
+	err := validateTimerScheduleAttributes(attributes, 0, now)
+	s.Nil(err)
+
+	attributes.StartToFireTimeoutSeconds = common.Int64Ptr(maxTimeout + 1)
+	err = validateTimerScheduleAttributes(attributes, 0, now)
+	s.EqualError(err, "BadRequestError{Message: StartToFireTimeoutSeconds 7723372037 overflows the expiry time of the timer.}")
+
+	attributes.StartToFireTimeoutSeconds = common.Int64Ptr(math.MaxInt64)
+	err = validateTimerScheduleAttributes(attributes, time.Hour, now)
+	s.EqualError(err, "BadRequestError{Message: StartToFireTimeoutSeconds 9223372036854775807 overflows the expiry time of the timer.}")
+
+	attributes.StartToFireTimeoutSeconds = common.Int64Ptr(3601)
+	err = validateTimerScheduleAttributes(attributes, time.Hour, now)
+	s.EqualError(err, "BadRequestError{Message: StartToFireTimeoutSeconds 3601 exceeds the limit of 3600 seconds.}")
+
+	attributes.StartToFireTimeoutSeconds = common.Int64Ptr(3600)
+	err = validateTimerScheduleAttributes(attributes, time.Hour, now)
+	s.Nil(err)
+}
+
 func (s *engineSuite) TestValidateSignalExternalWorkflowExecutionAttributes() {
 	var attributes *workflow.SignalExternalWorkflowExecutionDecisionAttributes
-	err := validateSignalExternalWorkflowExecutionAttributes(attributes)
+	err := validateSignalExternalWorkflowExecutionAttributes(attributes, s.config.MaxSignalInputSize)
 	s.EqualError(err, "BadRequestError{Message: SignalExternalWorkflowExecutionDecisionAttributes is not set on decision.}")
 
 	attributes = &workflow.SignalExternalWorkflowExecutionDecisionAttributes{}
-	err = validateSignalExternalWorkflowExecutionAttributes(attributes)
+	err = validateSignalExternalWorkflowExecutionAttributes(attributes, s.config.MaxSignalInputSize)
 	s.EqualError(err, "BadRequestError{Message: Execution is nil on decision.}")
 
 	attributes.Execution = &workflow.WorkflowExecution{}
 	attributes.Execution.WorkflowId = common.StringPtr("workflow-id")
-	err = validateSignalExternalWorkflowExecutionAttributes(attributes)
+	err = validateSignalExternalWorkflowExecutionAttributes(attributes, s.config.MaxSignalInputSize)
 	s.EqualError(err, "BadRequestError{Message: SignalName is not set on decision.}")
 
 	attributes.Execution.RunId = common.StringPtr("run-id")
-	err = validateSignalExternalWorkflowExecutionAttributes(attributes)
+	err = validateSignalExternalWorkflowExecutionAttributes(attributes, s.config.MaxSignalInputSize)
 	s.EqualError(err, "BadRequestError{Message: Invalid RunId set on decision.}")
 	attributes.Execution.RunId = common.StringPtr(validRunID)
 
 	attributes.SignalName = common.StringPtr("my signal name")
-	err = validateSignalExternalWorkflowExecutionAttributes(attributes)
+	err = validateSignalExternalWorkflowExecutionAttributes(attributes, s.config.MaxSignalInputSize)
 	s.EqualError(err, "BadRequestError{Message: Input is not set on decision.}")
 
 	attributes.Input = []byte("test input")
-	err = validateSignalExternalWorkflowExecutionAttributes(attributes)
+	err = validateSignalExternalWorkflowExecutionAttributes(attributes, s.config.MaxSignalInputSize)
+	s.Nil(err)
+
+	err = validateSignalExternalWorkflowExecutionAttributes(attributes, 4)
+	s.EqualError(err, "BadRequestError{Message: Signal input size of 10 bytes exceeds the limit of 4 bytes.}")
+}
+
+func (s *engineSuite) TestValidateStartWorkflowExecutionRequest_NameLimits() {
+	config := NewConfig(dynamicconfig.NewNopCollection(), 1)
+	config.MaxWorkflowTypeNameLength = 10
+	config.MaxTaskListNameLength = 8
+	request := &workflow.StartWorkflowExecutionRequest{
+		WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr(strings.Repeat("w", 10))},
+		TaskList:                            &workflow.TaskList{Name: common.StringPtr(strings.Repeat("t", 8))},
+		ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(100),
+		TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(10),
+	}
+	s.Nil(validateStartWorkflowExecutionRequest(request, config))
+
+	request.WorkflowType.Name = common.StringPtr(strings.Repeat("w", 11))
+	err := validateStartWorkflowExecutionRequest(request, config)
+	s.EqualError(err, "BadRequestError{Message: WorkflowType name length of 11 bytes exceeds the limit of 10 bytes.}")
+
+	request.WorkflowType.Name = common.StringPtr("wType\n")
+	err = validateStartWorkflowExecutionRequest(request, config)
+	s.EqualError(err, "BadRequestError{Message: WorkflowType name contains the non printable character '\\n'.}")
+
+	request.WorkflowType.Name = common.StringPtr("wType")
+	request.TaskList.Name = common.StringPtr(strings.Repeat("t", 9))
+	err = validateStartWorkflowExecutionRequest(request, config)
+	s.EqualError(err, "BadRequestError{Message: TaskList name length of 9 bytes exceeds the limit of 8 bytes.}")
+
+	request.TaskList.Name = common.StringPtr("tl\xff")
+	err = validateStartWorkflowExecutionRequest(request, config)
+	s.EqualError(err, "BadRequestError{Message: TaskList name is not valid UTF-8.}")
+
+	// a zero limit disables the length check
+	config.MaxTaskListNameLength = 0
+	request.TaskList.Name = common.StringPtr(strings.Repeat("t", 9))
+	s.Nil(validateStartWorkflowExecutionRequest(request, config))
+}
+
+func (s *engineSuite) TestValidateDecisionAttributes_TaskListLimits() {
+	config := NewConfig(dynamicconfig.NewNopCollection(), 1)
+	config.MaxWorkflowTypeNameLength = 10
+	config.MaxTaskListNameLength = 8
+
+	activityAttributes := &workflow.ScheduleActivityTaskDecisionAttributes{
+		TaskList:                      &workflow.TaskList{Name: common.StringPtr(strings.Repeat("t", 8))},
+		ActivityId:                    common.StringPtr("activity1"),
+		ActivityType:                  &workflow.ActivityType{Name: common.StringPtr("aType")},
+		StartToCloseTimeoutSeconds:    common.Int32Ptr(10),
+		ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+		ScheduleToCloseTimeoutSeconds: common.Int32Ptr(20),
+		HeartbeatTimeoutSeconds:       common.Int32Ptr(0),
+	}
+	s.Nil(validateActivityScheduleAttributes(activityAttributes, config.MaxTaskListNameLength, 0))
+	activityAttributes.TaskList.Name = common.StringPtr(strings.Repeat("t", 9))
+	err := validateActivityScheduleAttributes(activityAttributes, config.MaxTaskListNameLength, 0)
+	s.EqualError(err, "BadRequestError{Message: TaskList name length of 9 bytes exceeds the limit of 8 bytes.}")
+
+	parentInfo := &persistence.WorkflowExecutionInfo{TaskList: "parentTaskList", WorkflowTimeout: 100,
+		DecisionTimeoutValue: 10}
+	childAttributes := &workflow.StartChildWorkflowExecutionDecisionAttributes{
+		WorkflowId:   common.StringPtr("child-wId"),
+		WorkflowType: &workflow.WorkflowType{Name: common.StringPtr(strings.Repeat("w", 10))},
+		TaskList:     &workflow.TaskList{Name: common.StringPtr(strings.Repeat("t", 8))},
+		ChildPolicy:  common.ChildPolicyPtr(workflow.ChildPolicyTerminate),
+	}
+	s.Nil(validateStartChildExecutionAttributes(parentInfo, childAttributes, config, time.Now()))
+
+	childAttributes.TaskList.Name = common.StringPtr(strings.Repeat("t", 9))
+	err = validateStartChildExecutionAttributes(parentInfo, childAttributes, config, time.Now())
+	s.EqualError(err, "BadRequestError{Message: TaskList name length of 9 bytes exceeds the limit of 8 bytes.}")
+
+	childAttributes.TaskList.Name = common.StringPtr("childTL")
+	childAttributes.WorkflowType.Name = common.StringPtr(strings.Repeat("w", 11))
+	err = validateStartChildExecutionAttributes(parentInfo, childAttributes, config, time.Now())
+	s.EqualError(err, "BadRequestError{Message: WorkflowType name length of 11 bytes exceeds the limit of 10 bytes.}")
+
+	// the task list inherited from the parent was validated when the parent started
+	childAttributes.WorkflowType.Name = common.StringPtr("childType")
+	childAttributes.TaskList = nil
+	s.Nil(validateStartChildExecutionAttributes(parentInfo, childAttributes, config, time.Now()))
+	s.Equal("parentTaskList", childAttributes.TaskList.GetName())
+}
+
+func (s *engineSuite) TestValidateDecisionAttributes_InputSizeLimits() {
+	config := NewConfig(dynamicconfig.NewNopCollection(), 1)
+	config.MaxScheduleInputSize = func(opts ...dynamicconfig.FilterOption) int { return 4 }
+
+	activityAttributes := &workflow.ScheduleActivityTaskDecisionAttributes{
+		TaskList:                      &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+		ActivityId:                    common.StringPtr("activity1"),
+		ActivityType:                  &workflow.ActivityType{Name: common.StringPtr("aType")},
+		StartToCloseTimeoutSeconds:    common.Int32Ptr(10),
+		ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+		ScheduleToCloseTimeoutSeconds: common.Int32Ptr(20),
+		HeartbeatTimeoutSeconds:       common.Int32Ptr(0),
+		Input:                         []byte("1234"),
+	}
+	s.Nil(validateActivityScheduleAttributes(activityAttributes, config.MaxTaskListNameLength,
+		config.MaxScheduleInputSize()))
+	activityAttributes.Input = []byte("12345")
+	err := validateActivityScheduleAttributes(activityAttributes, config.MaxTaskListNameLength,
+		config.MaxScheduleInputSize())
+	s.EqualError(err, "BadRequestError{Message: Activity input size of 5 bytes exceeds the limit of 4 bytes.}")
+
+	parentInfo := &persistence.WorkflowExecutionInfo{TaskList: "parentTaskList", WorkflowTimeout: 100,
+		DecisionTimeoutValue: 10}
+	childAttributes := &workflow.StartChildWorkflowExecutionDecisionAttributes{
+		WorkflowId:   common.StringPtr("child-wId"),
+		WorkflowType: &workflow.WorkflowType{Name: common.StringPtr("childType")},
+		ChildPolicy:  common.ChildPolicyPtr(workflow.ChildPolicyTerminate),
+		Input:        []byte("1234"),
+	}
+	s.Nil(validateStartChildExecutionAttributes(parentInfo, childAttributes, config, time.Now()))
+	childAttributes.Input = []byte("12345")
+	err = validateStartChildExecutionAttributes(parentInfo, childAttributes, config, time.Now())
+	s.EqualError(err, "BadRequestError{Message: Child workflow input size of 5 bytes exceeds the limit of 4 bytes.}")
+
+	// a zero limit disables the size check
+	config.MaxScheduleInputSize = func(opts ...dynamicconfig.FilterOption) int { return 0 }
+	s.Nil(validateStartChildExecutionAttributes(parentInfo, childAttributes, config, time.Now()))
+}
+
+func (s *engineSuite) TestValidateStartChildExecutionAttributes_ParentRemainingTime() {
+	config := NewConfig(dynamicconfig.NewNopCollection(), 1)
+	config.LimitChildTimeoutToParent = true
+	config.ChildTimeoutExcessRatio = 2
+	now := time.Now()
+	// the parent has 40 seconds left before it times out
+	parentInfo := &persistence.WorkflowExecutionInfo{TaskList: "parentTaskList", WorkflowTimeout: 100,
+		DecisionTimeoutValue: 10, StartTimestamp: now.Add(-60 * time.Second)}
+	validate := func(timeout int32) (int32, error) {
+		childAttributes := &workflow.StartChildWorkflowExecutionDecisionAttributes{
+			WorkflowId:                          common.StringPtr("child-wId"),
+			WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("childType")},
+			ChildPolicy:                         common.ChildPolicyPtr(workflow.ChildPolicyTerminate),
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(timeout),
+		}
+		err := validateStartChildExecutionAttributes(parentInfo, childAttributes, config, now)
+		return childAttributes.GetExecutionStartToCloseTimeoutSeconds(), err
+	}
+
+	testCases := []struct {
+		requested int32
+		expected  int32
+	}{
+		{requested: 30, expected: 30},
+		{requested: 40, expected: 40},
+		{requested: 41, expected: 40},
+		{requested: 80, expected: 40},
+		// inherited from the parent
+		{requested: 0, expected: 40},
+	}
+	for _, tc := range testCases {
+		timeout, err := validate(tc.requested)
+		s.Nil(err)
+		s.Equal(tc.expected, timeout)
+	}
+
+	_, err := validate(81)
+	s.EqualError(err, "BadRequestError{Message: ExecutionStartToCloseTimeoutSeconds of 81 exceeds the 40 seconds "+
+		"remaining for the parent workflow execution.}")
+
+	// a parent past its timeout still lets an inherited timeout through with the minimum
+	parentInfo.StartTimestamp = now.Add(-200 * time.Second)
+	timeout, err := validate(0)
+	s.Nil(err)
+	s.Equal(int32(1), timeout)
+
+	config.LimitChildTimeoutToParent = false
+	timeout, err = validate(81)
+	s.Nil(err)
+	s.Equal(int32(81), timeout)
+}
+
+func (s *engineSuite) TestValidateDecisions() {
+	domainID := "domainId"
+	decisions := []*workflow.Decision{
+		{
+			DecisionType: common.DecisionTypePtr(workflow.DecisionTypeStartTimer),
+			StartTimerDecisionAttributes: &workflow.StartTimerDecisionAttributes{
+				TimerId:                   common.StringPtr("timer1"),
+				StartToFireTimeoutSeconds: common.Int64Ptr(1),
+			},
+		},
+		{
+			DecisionType: common.DecisionTypePtr(workflow.DecisionTypeScheduleActivityTask),
+			ScheduleActivityTaskDecisionAttributes: &workflow.ScheduleActivityTaskDecisionAttributes{
+				ActivityId: common.StringPtr("activity1"),
+			},
+		},
+	}
+
+	results, err := s.mockHistoryEngine.ValidateDecisions(domainID, decisions)
+	s.Nil(err)
+	s.Equal(2, len(results))
+	s.Nil(results[0].Cause)
+	s.Equal(workflow.DecisionTaskFailedCauseBadScheduleActivityAttributes, *results[1].Cause)
+	s.Equal("TaskList is not set on decision.", results[1].Message)
+
+	_, err = s.mockHistoryEngine.ValidateDecisions(domainID, []*workflow.Decision{{}})
+	s.IsType(&workflow.BadRequestError{}, err)
+
+	// an unknown target domain fails the request as it fails RespondDecisionTaskCompleted
+	s.mockMetadataMgr.On("GetDomain", &persistence.GetDomainRequest{Name: "unknown-domain"}).Return(
+		nil, &workflow.EntityNotExistsError{}).Once()
+	_, err = s.mockHistoryEngine.ValidateDecisions(domainID, []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeSignalExternalWorkflowExecution),
+		SignalExternalWorkflowExecutionDecisionAttributes: &workflow.SignalExternalWorkflowExecutionDecisionAttributes{
+			Domain: common.StringPtr("unknown-domain"),
+			Execution: &workflow.WorkflowExecution{
+				WorkflowId: common.StringPtr("wId"),
+			},
+			SignalName: common.StringPtr("signal"),
+			Input:      []byte("input"),
+		},
+	}})
+	s.IsType(&workflow.InternalServiceError{}, err)
+}
+
+func (s *engineSuite) TestValidateDecisions_DisabledDecisionType() {
+	domainID := "domainId"
+	enableDecisionTypeFilter := s.config.EnableDecisionTypeFilter
+	disabledDecisionTypes := s.config.DisabledDecisionTypes
+	defer func() {
+		s.config.EnableDecisionTypeFilter = enableDecisionTypeFilter
+		s.config.DisabledDecisionTypes = disabledDecisionTypes
+	}()
+	s.config.EnableDecisionTypeFilter = func(opts ...dynamicconfig.FilterOption) bool { return true }
+	s.config.DisabledDecisionTypes = func(opts ...dynamicconfig.FilterOption) string {
+		return "StartTimer"
+	}
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info:   &persistence.DomainInfo{ID: domainID, Name: "domain"},
+		Config: &persistence.DomainConfig{Retention: 1},
+	}, nil)
+
+	results, err := s.mockHistoryEngine.ValidateDecisions(domainID, []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeStartTimer),
+		StartTimerDecisionAttributes: &workflow.StartTimerDecisionAttributes{
+			TimerId:                   common.StringPtr("timer1"),
+			StartToFireTimeoutSeconds: common.Int64Ptr(1),
+		},
+	}})
 	s.Nil(err)
+	s.Equal(1, len(results))
+	s.Equal(workflow.DecisionTaskFailedCauseDecisionTypeDisabled, *results[0].Cause)
+	s.Equal("Decision type StartTimer is disabled.", results[0].Message)
 }
 
 func (s *engineSuite) getBuilder(domainID string, we workflow.WorkflowExecution) *mutableStateBuilder {
@@ -3554,6 +8155,15 @@ func addStartChildWorkflowExecutionInitiatedEvent(builder *mutableStateBuilder,
 	createRequestID, domain, workflowID, workflowType, tasklist string, input []byte,
 	executionStartToCloseTimeout, taskStartToCloseTimeout int32) (*workflow.HistoryEvent,
 	*persistence.ChildExecutionInfo) {
+	return addStartChildWorkflowExecutionInitiatedEventWithPolicy(builder, decisionCompletedID, createRequestID, domain,
+		workflowID, workflowType, tasklist, input, executionStartToCloseTimeout, taskStartToCloseTimeout,
+		workflow.ChildPolicyTerminate)
+}
+
+func addStartChildWorkflowExecutionInitiatedEventWithPolicy(builder *mutableStateBuilder, decisionCompletedID int64,
+	createRequestID, domain, workflowID, workflowType, tasklist string, input []byte,
+	executionStartToCloseTimeout, taskStartToCloseTimeout int32, childPolicy workflow.ChildPolicy) (
+	*workflow.HistoryEvent, *persistence.ChildExecutionInfo) {
 	return builder.AddStartChildWorkflowExecutionInitiatedEvent(decisionCompletedID, createRequestID,
 		&workflow.StartChildWorkflowExecutionDecisionAttributes{
 			Domain:       common.StringPtr(domain),
@@ -3563,7 +8173,7 @@ func addStartChildWorkflowExecutionInitiatedEvent(builder *mutableStateBuilder,
 			Input:        input,
 			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(executionStartToCloseTimeout),
 			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(taskStartToCloseTimeout),
-			ChildPolicy:                         common.ChildPolicyPtr(workflow.ChildPolicyTerminate),
+			ChildPolicy:                         common.ChildPolicyPtr(childPolicy),
 			Control:                             nil,
 		})
 }
@@ -3654,31 +8264,47 @@ func copyWorkflowExecutionInfo(sourceInfo *persistence.WorkflowExecutionInfo) *p
 		CloseStatus:          sourceInfo.CloseStatus,
 		NextEventID:          sourceInfo.NextEventID,
 		LastProcessedEvent:   sourceInfo.LastProcessedEvent,
+		StartTimestamp:       sourceInfo.StartTimestamp,
 		LastUpdatedTimestamp: sourceInfo.LastUpdatedTimestamp,
 		CreateRequestID:      sourceInfo.CreateRequestID,
 		DecisionScheduleID:   sourceInfo.DecisionScheduleID,
 		DecisionStartedID:    sourceInfo.DecisionStartedID,
 		DecisionRequestID:    sourceInfo.DecisionRequestID,
 		DecisionTimeout:      sourceInfo.DecisionTimeout,
+		BinaryChecksum:       sourceInfo.BinaryChecksum,
+		Paused:               sourceInfo.Paused,
+		KeyID:                sourceInfo.KeyID,
+		ResetPoints:          sourceInfo.ResetPoints,
+		OperatorTags:         sourceInfo.OperatorTags,
+		Generation:           sourceInfo.Generation,
+		Depth:                sourceInfo.Depth,
+		SignalCount:          sourceInfo.SignalCount,
+		LastSignalName:       sourceInfo.LastSignalName,
+		LastSignalTimestamp:  sourceInfo.LastSignalTimestamp,
 	}
 }
 
 func copyActivityInfo(sourceInfo *persistence.ActivityInfo) *persistence.ActivityInfo {
 	return &persistence.ActivityInfo{
-		ScheduleID:             sourceInfo.ScheduleID,
-		ScheduledEvent:         sourceInfo.ScheduledEvent,
-		StartedID:              sourceInfo.StartedID,
-		StartedEvent:           sourceInfo.StartedEvent,
-		ActivityID:             sourceInfo.ActivityID,
-		RequestID:              sourceInfo.RequestID,
-		Details:                sourceInfo.Details,
-		ScheduleToStartTimeout: sourceInfo.ScheduleToStartTimeout,
-		ScheduleToCloseTimeout: sourceInfo.ScheduleToCloseTimeout,
-		StartToCloseTimeout:    sourceInfo.StartToCloseTimeout,
-		HeartbeatTimeout:       sourceInfo.HeartbeatTimeout,
-		CancelRequested:        sourceInfo.CancelRequested,
-		CancelRequestID:        sourceInfo.CancelRequestID,
-		TimerTaskStatus:        sourceInfo.TimerTaskStatus,
+		ScheduleID:               sourceInfo.ScheduleID,
+		ScheduledEvent:           sourceInfo.ScheduledEvent,
+		StartedID:                sourceInfo.StartedID,
+		StartedEvent:             sourceInfo.StartedEvent,
+		StartedTime:              sourceInfo.StartedTime,
+		ActivityID:               sourceInfo.ActivityID,
+		RequestID:                sourceInfo.RequestID,
+		Details:                  sourceInfo.Details,
+		ScheduleToStartTimeout:   sourceInfo.ScheduleToStartTimeout,
+		ScheduleToCloseTimeout:   sourceInfo.ScheduleToCloseTimeout,
+		StartToCloseTimeout:      sourceInfo.StartToCloseTimeout,
+		HeartbeatTimeout:         sourceInfo.HeartbeatTimeout,
+		LastHeartBeatUpdatedTime: sourceInfo.LastHeartBeatUpdatedTime,
+		CancelRequested:          sourceInfo.CancelRequested,
+		CancelRequestID:          sourceInfo.CancelRequestID,
+		TimerTaskStatus:          sourceInfo.TimerTaskStatus,
+		StartedIdentity:          sourceInfo.StartedIdentity,
+		AcceptedCancel:           sourceInfo.AcceptedCancel,
+		Priority:                 sourceInfo.Priority,
 	}
 }
 
@@ -3714,6 +8340,7 @@ func copyChildInfo(sourceInfo *persistence.ChildExecutionInfo) *persistence.Chil
 	result := &persistence.ChildExecutionInfo{
 		InitiatedID:     sourceInfo.InitiatedID,
 		StartedID:       sourceInfo.StartedID,
+		StartedRunID:    sourceInfo.StartedRunID,
 		CreateRequestID: sourceInfo.CreateRequestID,
 	}
 
@@ -3721,3 +8348,21 @@ func copyChildInfo(sourceInfo *persistence.ChildExecutionInfo) *persistence.Chil
 	copy(result.StartedEvent, sourceInfo.StartedEvent)
 	return result
 }
+
+type testPayloadOffloader struct {
+	payloads [][]byte
+}
+
+func (o *testPayloadOffloader) Offload(domainID, workflowID, runID string, payload []byte) ([]byte, error) {
+	o.payloads = append(o.payloads, payload)
+	return []byte(fmt.Sprintf("offloaded-%v", len(o.payloads)-1)), nil
+}
+
+func (o *testPayloadOffloader) Hydrate(domainID string, payload []byte) ([]byte, error) {
+	for i, offloaded := range o.payloads {
+		if string(payload) == fmt.Sprintf("offloaded-%v", i) {
+			return offloaded, nil
+		}
+	}
+	return payload, nil
+}
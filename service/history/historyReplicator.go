@@ -22,6 +22,7 @@ package history
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pborman/uuid"
 	"github.com/uber-common/bark"
@@ -234,7 +235,7 @@ func (r *historyReplicator) ApplyEvents(request *h.ReplicateEventsRequest) (retE
 			// No mutable state action is needed
 
 		case shared.EventTypeWorkflowExecutionSignaled:
-			// No mutable state action is needed
+			msBuilder.ReplicateWorkflowExecutionSignaled(event)
 
 		case shared.EventTypeWorkflowExecutionCancelRequested:
 			msBuilder.ReplicateWorkflowExecutionCancelRequestedEvent(event)
@@ -346,6 +347,7 @@ func (r *historyReplicator) ApplyEvents(request *h.ReplicateEventsRequest) (retE
 			TransactionID: transactionID,
 			FirstEventID:  firstEvent.GetEventId(),
 			Events:        serializedHistory,
+			KeyID:         msBuilder.executionInfo.KeyID,
 		})
 		if err != nil {
 			return err
@@ -354,6 +356,8 @@ func (r *historyReplicator) ApplyEvents(request *h.ReplicateEventsRequest) (retE
 		nextEventID := lastEvent.GetEventId() + 1
 		msBuilder.executionInfo.NextEventID = nextEventID
 		msBuilder.executionInfo.LastFirstEventID = firstEvent.GetEventId()
+		// the standby run starts at the time of its started event
+		msBuilder.executionInfo.StartTimestamp = time.Unix(0, firstEvent.GetTimestamp())
 
 		failoverVersion := request.GetVersion()
 		replicationState := &persistence.ReplicationState{
@@ -378,6 +382,7 @@ func (r *historyReplicator) ApplyEvents(request *h.ReplicateEventsRequest) (retE
 				ExecutionContext:            nil,
 				NextEventID:                 msBuilder.GetNextEventID(),
 				LastProcessedEvent:          emptyEventID,
+				StartTimestamp:              msBuilder.executionInfo.StartTimestamp,
 				TransferTasks:               nil, // TODO: Generate transfer task
 				DecisionScheduleID:          decisionScheduleID,
 				DecisionStartedID:           decisionStartID,
@@ -386,6 +391,7 @@ func (r *historyReplicator) ApplyEvents(request *h.ReplicateEventsRequest) (retE
 				ContinueAsNew:               !isBrandNew,
 				PreviousRunID:               prevRunID,
 				ReplicationState:            replicationState,
+				KeyID:                       msBuilder.executionInfo.KeyID,
 			})
 
 			if err != nil {
@@ -118,6 +118,11 @@ func newTestShardContext(shardInfo *persistence.ShardInfo, transferSequenceNumbe
 	}
 }
 
+// GetShardID test implementation
+func (s *TestShardContext) GetShardID() int {
+	return s.shardInfo.ShardID
+}
+
 // GetService test implementation
 func (s *TestShardContext) GetService() service.Service {
 	return s.service
@@ -260,6 +265,12 @@ func (s *TestShardContext) UpdateWorkflowExecution(request *persistence.UpdateWo
 	return s.executionMgr.UpdateWorkflowExecution(request)
 }
 
+// DeleteWorkflowExecution test implementation
+func (s *TestShardContext) DeleteWorkflowExecution(request *persistence.DeleteWorkflowExecutionRequest,
+	isRunning bool) error {
+	return s.executionMgr.DeleteWorkflowExecution(request)
+}
+
 // AppendHistoryEvents test implementation
 func (s *TestShardContext) AppendHistoryEvents(request *persistence.AppendHistoryEventsRequest) error {
 	return s.historyMgr.AppendHistoryEvents(request)
@@ -301,6 +312,19 @@ func (s *TestShardContext) GetTimeSource() common.TimeSource {
 	return common.NewRealTimeSource()
 }
 
+// GetStats test implementation
+func (s *TestShardContext) GetStats() *ShardStats {
+	s.RLock()
+	defer s.RUnlock()
+	return &ShardStats{
+		ShardID:              s.shardInfo.ShardID,
+		OpenExecutionCount:   s.shardInfo.OpenExecutionCount,
+		ClosedExecutionCount: s.shardInfo.ClosedExecutionCount,
+		TransferTaskBacklog:  s.GetTransferMaxReadLevel() - s.shardInfo.TransferAckLevel,
+		TimerAckLevelLag:     time.Now().Sub(s.shardInfo.TimerAckLevel),
+	}
+}
+
 // SetCurrentTime test implementation
 func (s *TestShardContext) SetCurrentTime(cluster string, currentTime time.Time) {
 	s.Lock()
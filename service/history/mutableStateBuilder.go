@@ -30,7 +30,9 @@ import (
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/logging"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/service/dynamicconfig"
 
+	farm "github.com/dgryski/go-farm"
 	"github.com/pborman/uuid"
 	"github.com/uber-common/bark"
 )
@@ -77,6 +79,10 @@ type (
 		eventSerializer  historyEventSerializer
 		config           *Config
 		logger           bark.Logger
+
+		// the too short decision timeout was logged since the mutable state was loaded, it is not persisted so the
+		// timeout is logged once per load of the mutable state rather than once per workflow execution
+		decisionTimeoutClampLogged bool
 	}
 
 	mutableStateSessionUpdates struct {
@@ -396,8 +402,11 @@ func (e *mutableStateBuilder) assignEventIDToBufferedEvents() {
 			scheduledIDToStartedID[initiatedID] = eventID
 			if ci, ok := e.GetChildExecutionInfo(initiatedID); ok {
 				ci.StartedID = eventID
+				ci.StartedRunID = attributes.WorkflowExecution.GetRunId()
 				e.updateChildExecutionInfos[ci] = struct{}{}
 			}
+		case workflow.EventTypeWorkflowExecutionCancelRequested:
+			e.executionInfo.CancelRequestedEventID = eventID
 		case workflow.EventTypeActivityTaskCompleted:
 			attributes := event.ActivityTaskCompletedEventAttributes
 			if startedID, ok := scheduledIDToStartedID[attributes.GetScheduledEventId()]; ok {
@@ -451,6 +460,11 @@ func (e *mutableStateBuilder) isStickyTaskListEnabled() bool {
 	return len(e.executionInfo.StickyTaskList) > 0
 }
 
+// isPaused returns true if no new decision task may be scheduled for the workflow execution
+func (e *mutableStateBuilder) isPaused() bool {
+	return e.executionInfo.Paused
+}
+
 func (e *mutableStateBuilder) createNewHistoryEvent(eventType workflow.EventType) *workflow.HistoryEvent {
 	eventID := e.executionInfo.NextEventID
 	if e.shouldBufferEvent(eventType) {
@@ -532,6 +546,15 @@ func (e *mutableStateBuilder) getWorkflowType() *workflow.WorkflowType {
 	return wType
 }
 
+// getCloseTimestamp returns the time of the close event of the workflow execution, executions which closed before the
+// close time was persisted fall back to the time they were last updated
+func (e *mutableStateBuilder) getCloseTimestamp() int64 {
+	if e.executionInfo.CloseTimestamp.IsZero() {
+		return e.getLastUpdatedTimestamp()
+	}
+	return e.executionInfo.CloseTimestamp.UnixNano()
+}
+
 func (e *mutableStateBuilder) getLastUpdatedTimestamp() int64 {
 	lastUpdated := e.executionInfo.LastUpdatedTimestamp.UnixNano()
 	if e.executionInfo.StartTimestamp.UnixNano() >= lastUpdated {
@@ -646,6 +669,10 @@ func (e *mutableStateBuilder) DeletePendingChildExecution(initiatedEventID int64
 
 // DeletePendingRequestCancel deletes details about a RequestCancelInfo.
 func (e *mutableStateBuilder) DeletePendingRequestCancel(initiatedEventID int64) {
+	if ri, ok := e.pendingRequestCancelInfoIDs[initiatedEventID]; ok {
+		// request could be initiated within the same update, no need to write it out before deleting it
+		delete(e.updateRequestCancelInfos, ri)
+	}
 	delete(e.pendingRequestCancelInfoIDs, initiatedEventID)
 	e.deleteRequestCancelInfo = common.Int64Ptr(initiatedEventID)
 }
@@ -681,9 +708,21 @@ func (e *mutableStateBuilder) hasParentExecution() bool {
 }
 
 func (e *mutableStateBuilder) updateActivityProgress(ai *persistence.ActivityInfo,
-	request *workflow.RecordActivityTaskHeartbeatRequest) {
+	request *workflow.RecordActivityTaskHeartbeatRequest, now time.Time) {
 	ai.Details = request.Details
-	ai.LastHeartBeatUpdatedTime = time.Now()
+	ai.LastHeartBeatUpdatedTime = now
+	if checkpoint := request.Checkpoint; checkpoint != nil {
+		ai.CheckpointSequenceNumber = checkpoint.GetSequenceNumber()
+		ai.CheckpointPayload = checkpoint.Payload
+	}
+	e.updateActivityInfos[ai] = struct{}{}
+}
+
+// acceptActivityCancel records that the activity acknowledged the cancel request while it is still cleaning up.
+// The close timers of the activity are marked as not created so they are recreated for the extended expiry.
+func (e *mutableStateBuilder) acceptActivityCancel(ai *persistence.ActivityInfo) {
+	ai.AcceptedCancel = true
+	ai.TimerTaskStatus &^= TimerTaskStatusCreatedScheduleToClose | TimerTaskStatusCreatedStartToClose
 	e.updateActivityInfos[ai] = struct{}{}
 }
 
@@ -746,6 +785,7 @@ func (e *mutableStateBuilder) GetPendingDecision(scheduleEventID int64) (*decisi
 		DecisionTimeout: e.executionInfo.DecisionTimeout,
 		Attempt:         e.executionInfo.DecisionAttempt,
 		Timestamp:       e.executionInfo.DecisionTimestamp,
+		Tasklist:        e.getDecisionTaskList(),
 	}
 	if scheduleEventID == di.ScheduleID {
 		return di, true
@@ -775,6 +815,40 @@ func (e *mutableStateBuilder) HasBufferedEvents() bool {
 	return false
 }
 
+// getBufferedEvents returns the events buffered while the current decision is in flight, those already persisted
+// followed by those added in this update session.
+func (e *mutableStateBuilder) getBufferedEvents() ([]*workflow.HistoryEvent, error) {
+	var bufferedEvents []*workflow.HistoryEvent
+	read := func(bufferedEventBatch *persistence.SerializedHistoryEventBatch) error {
+		eventBatch, err := e.hBuilder.serializer.Deserialize(bufferedEventBatch)
+		if err != nil {
+			logging.LogHistoryDeserializationErrorEvent(e.logger, err, "Unable to deserialize buffered events.")
+			return err
+		}
+		bufferedEvents = append(bufferedEvents, eventBatch.Events...)
+		return nil
+	}
+
+	for _, bufferedEventBatch := range e.bufferedEvents {
+		if err := read(bufferedEventBatch); err != nil {
+			return nil, err
+		}
+	}
+	if e.updateBufferedEvents != nil {
+		if err := read(e.updateBufferedEvents); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, event := range e.hBuilder.history {
+		if event.GetEventId() == bufferedEventID {
+			bufferedEvents = append(bufferedEvents, event)
+		}
+	}
+
+	return bufferedEvents, nil
+}
+
 // UpdateDecision updates a decision task.
 func (e *mutableStateBuilder) UpdateDecision(di *decisionInfo) {
 	e.executionInfo.DecisionScheduleID = di.ScheduleID
@@ -821,6 +895,7 @@ func (e *mutableStateBuilder) clearStickyness() {
 	e.executionInfo.ClientLibraryVersion = ""
 	e.executionInfo.ClientFeatureVersion = ""
 	e.executionInfo.ClientImpl = ""
+	e.executionInfo.BinaryChecksum = ""
 }
 
 // GetLastFirstEventID returns last first event ID
@@ -911,6 +986,10 @@ func (e *mutableStateBuilder) AddWorkflowExecutionStartedEventForContinueAsNew(d
 		Input:    attributes.Input,
 		Identity: nil,
 	}
+	// the new run is encrypted with the key of the run it continues
+	if keyID := previousExecutionState.executionInfo.KeyID; keyID != "" {
+		createRequest.KeyId = common.StringPtr(keyID)
+	}
 
 	req := &h.StartWorkflowExecutionRequest{
 		DomainUUID:          common.StringPtr(domainID),
@@ -961,6 +1040,7 @@ func (e *mutableStateBuilder) ReplicateWorkflowExecutionStartedEvent(domainID st
 	e.executionInfo.WorkflowTypeName = event.WorkflowType.GetName()
 	e.executionInfo.WorkflowTimeout = event.GetExecutionStartToCloseTimeoutSeconds()
 	e.executionInfo.DecisionTimeoutValue = event.GetTaskStartToCloseTimeoutSeconds()
+	e.executionInfo.KeyID = event.GetKeyId()
 
 	e.executionInfo.State = persistence.WorkflowStateCreated
 	e.executionInfo.CloseStatus = persistence.WorkflowCloseStatusNone
@@ -983,6 +1063,28 @@ func (e *mutableStateBuilder) ReplicateWorkflowExecutionStartedEvent(domainID st
 	}
 }
 
+// addResetPoint records a completed decision as a point the workflow execution can be reset to, only the newest
+// MaxResetPoints are kept
+func (e *mutableStateBuilder) addResetPoint(completedEventID int64, binaryChecksum string, resettable, safe bool,
+	createdTime time.Time) {
+	maxResetPoints := e.config.MaxResetPoints
+	if maxResetPoints <= 0 {
+		return
+	}
+
+	points := append(e.executionInfo.ResetPoints, &persistence.ResetPoint{
+		EventID:        completedEventID,
+		BinaryChecksum: binaryChecksum,
+		Resettable:     resettable,
+		CreatedTime:    createdTime,
+		Safe:           safe,
+	})
+	if len(points) > maxResetPoints {
+		points = points[len(points)-maxResetPoints:]
+	}
+	e.executionInfo.ResetPoints = points
+}
+
 func (e *mutableStateBuilder) AddDecisionTaskScheduledEvent() *decisionInfo {
 	if e.HasPendingDecisionTask() {
 		logging.LogInvalidHistoryActionEvent(e.logger, logging.TagValueActionDecisionTaskScheduled, e.GetNextEventID(),
@@ -991,11 +1093,16 @@ func (e *mutableStateBuilder) AddDecisionTaskScheduledEvent() *decisionInfo {
 	}
 
 	// Tasklist and decision timeout should already be set from workflow execution started event
-	taskList := e.executionInfo.TaskList
+	if e.executionInfo.DecisionTaskList == "" && e.isSchedulingFirstDecision() {
+		// The run keeps the task list picked for its first decision, so it does not move between a canary task list
+		// and its own one half way through
+		e.executionInfo.DecisionTaskList = e.routeDecisionTaskList()
+	}
+	taskList := e.getDecisionTaskList()
 	if e.isStickyTaskListEnabled() {
 		taskList = e.executionInfo.StickyTaskList
 	}
-	startToCloseTimeoutSeconds := e.executionInfo.DecisionTimeoutValue
+	startToCloseTimeoutSeconds := e.getDecisionStartToCloseTimeout()
 
 	// Flush any buffered events before creating the decision, otherwise it will result in invalid IDs for transient
 	// decision and will cause in timeout processing to not work for transient decisions
@@ -1015,6 +1122,62 @@ func (e *mutableStateBuilder) AddDecisionTaskScheduledEvent() *decisionInfo {
 	return e.ReplicateDecisionTaskScheduledEvent(scheduleID, taskList, startToCloseTimeoutSeconds)
 }
 
+// getDecisionTaskList returns the task list the decisions of the run are dispatched to when it has no sticky worker,
+// which is the one picked when its first decision was scheduled.  Runs which had decisions before a pick was recorded
+// stay on their own task list.
+func (e *mutableStateBuilder) getDecisionTaskList() string {
+	if e.executionInfo.DecisionTaskList == "" {
+		return e.executionInfo.TaskList
+	}
+	return e.executionInfo.DecisionTaskList
+}
+
+// isSchedulingFirstDecision returns true if no decision of the run has completed or failed yet
+func (e *mutableStateBuilder) isSchedulingFirstDecision() bool {
+	return e.executionInfo.LastProcessedEvent == emptyEventID && e.executionInfo.DecisionAttempt == 0
+}
+
+// routeDecisionTaskList sends the configured percentage of runs of a workflow type to the canary task list, if any,
+// and the others to the task list of the run
+func (e *mutableStateBuilder) routeDecisionTaskList() string {
+	taskList := e.executionInfo.TaskList
+	filters := []dynamicconfig.FilterOption{
+		dynamicconfig.TaskListFilter(taskList),
+		dynamicconfig.WorkflowTypeFilter(e.executionInfo.WorkflowTypeName),
+	}
+	canaryTaskList := e.config.DecisionCanaryTaskList(filters...)
+	if canaryTaskList == "" || !isCanaryRun(e.executionInfo.RunID, e.config.DecisionCanaryPercentage(filters...)) {
+		return taskList
+	}
+	return canaryTaskList
+}
+
+// isCanaryRun returns true if the run is among the given percentage of runs, by a hash of its run ID so that the
+// answer is the same every time it is asked
+func isCanaryRun(runID string, percentage int) bool {
+	return int(farm.Fingerprint32([]byte(runID))%100) < percentage
+}
+
+// getDecisionStartToCloseTimeout returns the decision timeout of the workflow execution raised to the configured
+// minimum, so that workers asking for a too short timeout do not end up in a storm of timed out decisions.  Raising
+// the timeout is logged once per loaded mutable state.
+func (e *mutableStateBuilder) getDecisionStartToCloseTimeout() int32 {
+	timeout := e.executionInfo.DecisionTimeoutValue
+	minTimeout := int32(e.config.MinDecisionStartToCloseTimeoutInSecs())
+	if timeout >= minTimeout {
+		return timeout
+	}
+
+	if !e.decisionTimeoutClampLogged {
+		e.decisionTimeoutClampLogged = true
+		e.logger.WithFields(bark.Fields{
+			logging.TagWorkflowExecutionID: e.executionInfo.WorkflowID,
+			logging.TagWorkflowRunID:       e.executionInfo.RunID,
+		}).Warnf("Decision timeout of %v seconds is raised to the minimum of %v seconds.", timeout, minTimeout)
+	}
+	return minTimeout
+}
+
 func (e *mutableStateBuilder) ReplicateDecisionTaskScheduledEvent(scheduleID int64, taskList string,
 	startToCloseTimeoutSeconds int32) *decisionInfo {
 	di := &decisionInfo{
@@ -1082,6 +1245,7 @@ func (e *mutableStateBuilder) ReplicateDecisionTaskStartedEvent(di *decisionInfo
 		DecisionTimeout: di.DecisionTimeout,
 		Attempt:         di.Attempt,
 		Timestamp:       timestamp,
+		Tasklist:        di.Tasklist,
 	}
 
 	e.UpdateDecision(di)
@@ -1090,8 +1254,7 @@ func (e *mutableStateBuilder) ReplicateDecisionTaskStartedEvent(di *decisionInfo
 
 func (e *mutableStateBuilder) createTransientDecisionEvents(di *decisionInfo, identity string) (*workflow.HistoryEvent,
 	*workflow.HistoryEvent) {
-	tasklist := e.executionInfo.TaskList
-	scheduledEvent := newDecisionTaskScheduledEventWithInfo(di.ScheduleID, di.Timestamp, tasklist, di.DecisionTimeout,
+	scheduledEvent := newDecisionTaskScheduledEventWithInfo(di.ScheduleID, di.Timestamp, di.Tasklist, di.DecisionTimeout,
 		di.Attempt)
 	startedEvent := newDecisionTaskStartedEventWithInfo(di.StartedID, di.Timestamp, di.ScheduleID, di.RequestID,
 		identity)
@@ -1122,7 +1285,7 @@ func (e *mutableStateBuilder) AddDecisionTaskCompletedEvent(scheduleEventID, sta
 	e.BeforeAddDecisionTaskCompletedEvent()
 	if di.Attempt > 0 {
 		// Create corresponding DecisionTaskSchedule and DecisionTaskStarted events for decisions we have been retrying
-		scheduledEvent := e.hBuilder.AddDecisionTaskScheduledEvent(e.executionInfo.TaskList, di.DecisionTimeout, di.Attempt)
+		scheduledEvent := e.hBuilder.AddDecisionTaskScheduledEvent(di.Tasklist, di.DecisionTimeout, di.Attempt)
 		startedEvent := e.hBuilder.AddDecisionTaskStartedEvent(scheduledEvent.GetEventId(), di.RequestID,
 			request.GetIdentity())
 		startedEventID = startedEvent.GetEventId()
@@ -1151,12 +1314,20 @@ func (e *mutableStateBuilder) AddDecisionTaskTimedOutEvent(scheduleEventID int64
 	}
 
 	var event *workflow.HistoryEvent
-	// Avoid creating new history events when decisions are continuously timing out
-	if dt.Attempt == 0 {
+	attemptsExhausted := e.isDecisionAttemptCapReached(dt.Attempt)
+	// Avoid creating new history events when decisions are continuously timing out, unless the transient attempts
+	// are exhausted
+	if dt.Attempt == 0 || attemptsExhausted {
+		if dt.Attempt > 0 {
+			scheduleEventID, startedEventID = e.addTransientDecisionEvents(dt, "")
+		}
 		event = e.hBuilder.AddDecisionTaskTimedOutEvent(scheduleEventID, startedEventID, workflow.TimeoutTypeStartToClose)
 	}
 
 	e.ReplicateDecisionTaskTimedOutEvent(scheduleEventID, startedEventID)
+	if attemptsExhausted {
+		e.executionInfo.DecisionAttempt = 0
+	}
 	return event
 }
 
@@ -1181,6 +1352,23 @@ func (e *mutableStateBuilder) AddDecisionTaskScheduleToStartTimeoutEvent(schedul
 	return event
 }
 
+// AddRetriedDecisionScheduleToStartTimeout fails a retried decision which was not picked up from the normal task list
+// in time, so it is retried as the next attempt.  Retried decisions are only kept in mutable state, and so is their
+// timeout.
+func (e *mutableStateBuilder) AddRetriedDecisionScheduleToStartTimeout(scheduleEventID int64) bool {
+	if e.executionInfo.DecisionScheduleID != scheduleEventID || e.executionInfo.DecisionStartedID > 0 ||
+		e.executionInfo.DecisionAttempt == 0 {
+		logging.LogInvalidHistoryActionEvent(e.logger, logging.TagValueActionDecisionTaskTimedOut, e.GetNextEventID(),
+			fmt.Sprintf("{DecisionScheduleID: %v, DecisionStartedID: %v, DecisionAttempt: %v, ScheduleEventID: %v}",
+				e.executionInfo.DecisionScheduleID, e.executionInfo.DecisionStartedID, e.executionInfo.DecisionAttempt,
+				scheduleEventID))
+		return false
+	}
+
+	e.ReplicateDecisionTaskTimedOutEvent(scheduleEventID, emptyEventID)
+	return true
+}
+
 func (e *mutableStateBuilder) AddDecisionTaskFailedEvent(scheduleEventID int64,
 	startedEventID int64, cause workflow.DecisionTaskFailedCause, details []byte,
 	identity string) *workflow.HistoryEvent {
@@ -1194,15 +1382,43 @@ func (e *mutableStateBuilder) AddDecisionTaskFailedEvent(scheduleEventID int64,
 	}
 
 	var event *workflow.HistoryEvent
-	// Only emit DecisionTaskFailedEvent for the very first time
-	if dt.Attempt == 0 {
+	attemptsExhausted := e.isDecisionAttemptCapReached(dt.Attempt)
+	// Only emit DecisionTaskFailedEvent for the very first time, or once the transient attempts are exhausted
+	if dt.Attempt == 0 || attemptsExhausted {
+		if dt.Attempt > 0 {
+			scheduleEventID, startedEventID = e.addTransientDecisionEvents(dt, identity)
+		}
 		event = e.hBuilder.AddDecisionTaskFailedEvent(scheduleEventID, startedEventID, cause, details, identity)
 	}
 
 	e.ReplicateDecisionTaskFailedEvent(scheduleEventID, startedEventID)
+	if attemptsExhausted {
+		// the failure is in history now, so the decision retried next starts over with persisted events
+		e.executionInfo.DecisionAttempt = 0
+	}
 	return event
 }
 
+// isDecisionAttemptCapReached returns true if a failure of the given decision attempt uses up the transient attempts
+// allowed by MaxTransientDecisionAttempts
+func (e *mutableStateBuilder) isDecisionAttemptCapReached(attempt int64) bool {
+	maxAttempts := e.config.MaxTransientDecisionAttempts
+	return maxAttempts > 0 && attempt+1 >= int64(maxAttempts)
+}
+
+// shouldTimeoutOnDecisionFailure returns true if the workflow is to be timed out when the given decision attempt fails
+func (e *mutableStateBuilder) shouldTimeoutOnDecisionFailure(attempt int64) bool {
+	return e.config.TimeoutWorkflowOnMaxDecisionAttempts && e.isDecisionAttemptCapReached(attempt)
+}
+
+// addTransientDecisionEvents writes the scheduled and started events of a retried decision, which are only kept in
+// mutable state so far, to history and returns their event IDs
+func (e *mutableStateBuilder) addTransientDecisionEvents(di *decisionInfo, identity string) (int64, int64) {
+	scheduledEvent := e.hBuilder.AddDecisionTaskScheduledEvent(di.Tasklist, di.DecisionTimeout, di.Attempt)
+	startedEvent := e.hBuilder.AddDecisionTaskStartedEvent(scheduledEvent.GetEventId(), di.RequestID, identity)
+	return scheduledEvent.GetEventId(), startedEvent.GetEventId()
+}
+
 func (e *mutableStateBuilder) ReplicateDecisionTaskFailedEvent(scheduleID, startedID int64) {
 	e.FailDecision()
 }
@@ -1275,6 +1491,8 @@ func (e *mutableStateBuilder) ReplicateActivityTaskScheduledEvent(
 		CancelRequestID:          emptyEventID,
 		LastHeartBeatUpdatedTime: time.Time{},
 		TimerTaskStatus:          TimerTaskStatusNone,
+		Priority:                 attributes.GetPriority(),
+		DispatchDeadline:         attributes.GetDispatchDeadlineSeconds(),
 	}
 
 	e.pendingActivityInfoIDs[scheduleEventID] = ai
@@ -1306,6 +1524,7 @@ func (e *mutableStateBuilder) ReplicateActivityTaskStartedEvent(event *workflow.
 	ai.StartedID = event.GetEventId()
 	ai.RequestID = attributes.GetRequestId()
 	ai.StartedTime = time.Unix(0, event.GetTimestamp())
+	ai.StartedIdentity = attributes.GetIdentity()
 	e.updateActivityInfos[ai] = struct{}{}
 }
 
@@ -1465,6 +1684,7 @@ func (e *mutableStateBuilder) AddCompletedWorkflowEvent(decisionCompletedEventID
 func (e *mutableStateBuilder) ReplicateWorkflowExecutionCompletedEvent(event *workflow.HistoryEvent) {
 	e.executionInfo.State = persistence.WorkflowStateCompleted
 	e.executionInfo.CloseStatus = persistence.WorkflowCloseStatusCompleted
+	e.executionInfo.CloseTimestamp = time.Unix(0, event.GetTimestamp())
 	e.writeCompletionEventToMutableState(event)
 }
 
@@ -1485,6 +1705,7 @@ func (e *mutableStateBuilder) AddFailWorkflowEvent(decisionCompletedEventID int6
 func (e *mutableStateBuilder) ReplicateWorkflowExecutionFailedEvent(event *workflow.HistoryEvent) {
 	e.executionInfo.State = persistence.WorkflowStateCompleted
 	e.executionInfo.CloseStatus = persistence.WorkflowCloseStatusFailed
+	e.executionInfo.CloseTimestamp = time.Unix(0, event.GetTimestamp())
 	e.writeCompletionEventToMutableState(event)
 }
 
@@ -1504,6 +1725,7 @@ func (e *mutableStateBuilder) AddTimeoutWorkflowEvent() *workflow.HistoryEvent {
 func (e *mutableStateBuilder) ReplicateWorkflowExecutionTimedoutEvent(event *workflow.HistoryEvent) {
 	e.executionInfo.State = persistence.WorkflowStateCompleted
 	e.executionInfo.CloseStatus = persistence.WorkflowCloseStatusTimedOut
+	e.executionInfo.CloseTimestamp = time.Unix(0, event.GetTimestamp())
 	e.writeCompletionEventToMutableState(event)
 }
 
@@ -1527,6 +1749,10 @@ func (e *mutableStateBuilder) AddWorkflowExecutionCancelRequestedEvent(cause str
 
 func (e *mutableStateBuilder) ReplicateWorkflowExecutionCancelRequestedEvent(event *workflow.HistoryEvent) {
 	e.executionInfo.CancelRequested = true
+	// a buffered event is given its id once it is flushed
+	if event.GetEventId() != bufferedEventID {
+		e.executionInfo.CancelRequestedEventID = event.GetEventId()
+	}
 }
 
 func (e *mutableStateBuilder) AddWorkflowExecutionCanceledEvent(decisionTaskCompletedEventID int64,
@@ -1545,6 +1771,7 @@ func (e *mutableStateBuilder) AddWorkflowExecutionCanceledEvent(decisionTaskComp
 func (e *mutableStateBuilder) ReplicateWorkflowExecutionCanceledEvent(event *workflow.HistoryEvent) {
 	e.executionInfo.State = persistence.WorkflowStateCompleted
 	e.executionInfo.CloseStatus = persistence.WorkflowCloseStatusCanceled
+	e.executionInfo.CloseTimestamp = time.Unix(0, event.GetTimestamp())
 	e.writeCompletionEventToMutableState(event)
 }
 
@@ -1713,9 +1940,8 @@ func (e *mutableStateBuilder) ReplicateTimerStartedEvent(event *workflow.History
 	timerID := attributes.GetTimerId()
 
 	startToFireTimeout := attributes.GetStartToFireTimeoutSeconds()
-	fireTimeout := time.Duration(startToFireTimeout) * time.Second
 	// TODO: Time skew need to be taken in to account.
-	expiryTime := time.Now().Add(fireTimeout)
+	expiryTime := getTimerExpiryTime(time.Now(), startToFireTimeout)
 	ti := &persistence.TimerInfo{
 		TimerID:    timerID,
 		ExpiryTime: expiryTime,
@@ -1806,6 +2032,7 @@ func (e *mutableStateBuilder) AddWorkflowExecutionTerminatedEvent(
 func (e *mutableStateBuilder) ReplicateWorkflowExecutionTerminatedEvent(event *workflow.HistoryEvent) {
 	e.executionInfo.State = persistence.WorkflowStateCompleted
 	e.executionInfo.CloseStatus = persistence.WorkflowCloseStatusTerminated
+	e.executionInfo.CloseTimestamp = time.Unix(0, event.GetTimestamp())
 	e.writeCompletionEventToMutableState(event)
 }
 
@@ -1817,8 +2044,17 @@ func (e *mutableStateBuilder) AddWorkflowExecutionSignaled(
 		return nil
 	}
 
-	// No MutableState operation needed for signal
-	return e.hBuilder.AddWorkflowExecutionSignaledEvent(request)
+	// signals are numbered in the order they are added to the run, which holds for buffered signals as well
+	event := e.hBuilder.AddWorkflowExecutionSignaledEvent(request, e.executionInfo.SignalCount+1)
+	e.ReplicateWorkflowExecutionSignaled(event)
+	return event
+}
+
+// ReplicateWorkflowExecutionSignaled keeps track of the number of signals delivered to the execution and the last one
+func (e *mutableStateBuilder) ReplicateWorkflowExecutionSignaled(event *workflow.HistoryEvent) {
+	e.executionInfo.SignalCount++
+	e.executionInfo.LastSignalName = event.WorkflowExecutionSignaledEventAttributes.GetSignalName()
+	e.executionInfo.LastSignalTimestamp = time.Unix(0, event.GetTimestamp())
 }
 
 func (e *mutableStateBuilder) AddContinueAsNewEvent(decisionCompletedEventID int64, domainID, domainName, newRunID string,
@@ -1865,6 +2101,16 @@ func (e *mutableStateBuilder) AddContinueAsNewEvent(decisionCompletedEventID int
 	e.ReplicateWorkflowExecutionContinuedAsNewEvent(domainID, domainName, continueAsNewEvent, startedEvent, di,
 		newStateBuilder)
 
+	// Signal metadata is reset for the new run unless the decision asks to carry it over
+	if attributes.GetCarrySignalMetadata() {
+		newStateBuilder.executionInfo.SignalCount = e.executionInfo.SignalCount
+		newStateBuilder.executionInfo.LastSignalName = e.executionInfo.LastSignalName
+		newStateBuilder.executionInfo.LastSignalTimestamp = e.executionInfo.LastSignalTimestamp
+		e.continueAsNew.SignalCount = e.executionInfo.SignalCount
+		e.continueAsNew.LastSignalName = e.executionInfo.LastSignalName
+		e.continueAsNew.LastSignalTimestamp = e.executionInfo.LastSignalTimestamp
+	}
+
 	return continueAsNewEvent, newStateBuilder, nil
 }
 
@@ -1883,6 +2129,10 @@ func (e *mutableStateBuilder) ReplicateWorkflowExecutionContinuedAsNewEvent(doma
 
 	e.executionInfo.State = persistence.WorkflowStateCompleted
 	e.executionInfo.CloseStatus = persistence.WorkflowCloseStatusContinuedAsNew
+	e.executionInfo.CloseTimestamp = time.Unix(0, continueAsNewEvent.GetTimestamp())
+	// every run has its own start time, the new run starts at the time of its started event
+	newStateBuilder.executionInfo.StartTimestamp = time.Unix(0, startedEvent.GetTimestamp())
+	newStateBuilder.executionInfo.Generation = e.executionInfo.Generation + 1
 
 	parentDomainID := ""
 	var parentExecution *workflow.WorkflowExecution
@@ -1910,9 +2160,10 @@ func (e *mutableStateBuilder) ReplicateWorkflowExecutionContinuedAsNewEvent(doma
 		ExecutionContext:     nil,
 		NextEventID:          newStateBuilder.GetNextEventID(),
 		LastProcessedEvent:   emptyEventID,
+		StartTimestamp:       newStateBuilder.executionInfo.StartTimestamp,
 		TransferTasks: []persistence.Task{&persistence.DecisionTask{
 			DomainID:   domainID,
-			TaskList:   newStateBuilder.executionInfo.TaskList,
+			TaskList:   di.Tasklist,
 			ScheduleID: di.ScheduleID,
 		}},
 		DecisionScheduleID:          di.ScheduleID,
@@ -1920,6 +2171,10 @@ func (e *mutableStateBuilder) ReplicateWorkflowExecutionContinuedAsNewEvent(doma
 		DecisionStartToCloseTimeout: di.DecisionTimeout,
 		ContinueAsNew:               true,
 		PreviousRunID:               prevRunID,
+		Depth:                       e.executionInfo.Depth,
+		KeyID:                       newStateBuilder.executionInfo.KeyID,
+		Generation:                  newStateBuilder.executionInfo.Generation,
+		DecisionTaskList:            newStateBuilder.executionInfo.DecisionTaskList,
 	}
 }
 
@@ -1984,6 +2239,7 @@ func (e *mutableStateBuilder) ReplicateChildWorkflowExecutionStartedEvent(event
 	}
 
 	ci.StartedID = event.GetEventId()
+	ci.StartedRunID = attributes.WorkflowExecution.GetRunId()
 	ci.StartedEvent = startedEvent
 	e.updateChildExecutionInfos[ci] = struct{}{}
 
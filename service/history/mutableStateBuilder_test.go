@@ -24,10 +24,12 @@ import (
 	"os"
 	"testing"
 
+	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
 	"github.com/uber-common/bark"
 	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/service/dynamicconfig"
 )
 
@@ -138,3 +140,209 @@ OtherEventsLoop:
 	s.Equal(len(workflow.DecisionType_Values())+1, len(decisionEvents),
 		"This assertaion will be broken a new decision is added and no corresponding logic added to shouldBufferEvent()")
 }
+
+func (s *mutableStateSuite) TestSignalMetadataAcrossContinueAsNew() {
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	addWorkflowExecutionStartedEvent(s.msBuilder, we, "wType", "testTaskList", []byte("input"), 100, 200, "identity")
+	for _, signalName := range []string{"signal1", "signal2"} {
+		event := s.msBuilder.AddWorkflowExecutionSignaled(&workflow.SignalWorkflowExecutionRequest{
+			SignalName: common.StringPtr(signalName),
+		})
+		s.NotNil(event)
+	}
+	s.Equal(int64(2), s.msBuilder.executionInfo.SignalCount)
+	s.Equal("signal2", s.msBuilder.executionInfo.LastSignalName)
+	s.False(s.msBuilder.executionInfo.LastSignalTimestamp.IsZero())
+
+	testCases := []struct {
+		carry               bool
+		expectedSignalCount int64
+		expectedSignalName  string
+	}{
+		{carry: false, expectedSignalCount: 0, expectedSignalName: ""},
+		{carry: true, expectedSignalCount: 2, expectedSignalName: "signal2"},
+	}
+	for _, tc := range testCases {
+		_, newBuilder, err := s.msBuilder.AddContinueAsNewEvent(common.EmptyEventID, "domainId", "domainName",
+			uuid.New(), "", &workflow.ContinueAsNewWorkflowExecutionDecisionAttributes{
+				CarrySignalMetadata: common.BoolPtr(tc.carry),
+			})
+		s.Nil(err)
+		s.Equal(tc.expectedSignalCount, newBuilder.executionInfo.SignalCount)
+		s.Equal(tc.expectedSignalName, newBuilder.executionInfo.LastSignalName)
+		s.Equal(tc.expectedSignalCount, s.msBuilder.continueAsNew.SignalCount)
+		s.Equal(tc.expectedSignalName, s.msBuilder.continueAsNew.LastSignalName)
+	}
+}
+
+func (s *mutableStateSuite) TestSignalSequenceNumber() {
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	addWorkflowExecutionStartedEvent(s.msBuilder, we, "wType", "testTaskList", []byte("input"), 100, 200, "identity")
+	addSignal := func(signalName string) *workflow.HistoryEvent {
+		event := s.msBuilder.AddWorkflowExecutionSignaled(&workflow.SignalWorkflowExecutionRequest{
+			SignalName: common.StringPtr(signalName),
+		})
+		s.NotNil(event)
+		return event
+	}
+
+	event := addSignal("signal1")
+	s.Equal(int64(1), event.WorkflowExecutionSignaledEventAttributes.GetSequenceNumber())
+
+	// signals buffered while a decision is in flight are numbered as they arrive
+	di := addDecisionTaskScheduledEvent(s.msBuilder)
+	addDecisionTaskStartedEvent(s.msBuilder, di.ScheduleID, "testTaskList", "identity")
+	for _, expected := range []int64{2, 3} {
+		event := addSignal("signal")
+		s.Equal(bufferedEventID, event.GetEventId())
+		s.Equal(expected, event.WorkflowExecutionSignaledEventAttributes.GetSequenceNumber())
+	}
+	s.Equal(int64(3), s.msBuilder.executionInfo.SignalCount)
+}
+
+func (s *mutableStateSuite) TestGenerationAcrossContinueAsNew() {
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	addWorkflowExecutionStartedEvent(s.msBuilder, we, "wType", "testTaskList", []byte("input"), 100, 200, "identity")
+	s.Equal(int64(0), s.msBuilder.executionInfo.Generation)
+
+	builder := s.msBuilder
+	for _, expectedGeneration := range []int64{1, 2} {
+		_, newBuilder, err := builder.AddContinueAsNewEvent(common.EmptyEventID, "domainId", "domainName",
+			uuid.New(), "", &workflow.ContinueAsNewWorkflowExecutionDecisionAttributes{})
+		s.Nil(err)
+		s.Equal(expectedGeneration, newBuilder.executionInfo.Generation)
+		s.Equal(expectedGeneration, builder.continueAsNew.Generation)
+		builder = newBuilder
+	}
+}
+
+func (s *mutableStateSuite) TestGetBufferedEvents() {
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	addWorkflowExecutionStartedEvent(s.msBuilder, we, "wType", "testTaskList", []byte("input"), 100, 200, "identity")
+	di := addDecisionTaskScheduledEvent(s.msBuilder)
+	addDecisionTaskStartedEvent(s.msBuilder, di.ScheduleID, "testTaskList", "identity")
+	s.False(s.msBuilder.HasBufferedEvents())
+
+	addSignal := func(signalName string) {
+		event := s.msBuilder.AddWorkflowExecutionSignaled(&workflow.SignalWorkflowExecutionRequest{
+			SignalName: common.StringPtr(signalName),
+		})
+		s.NotNil(event)
+		s.Equal(bufferedEventID, event.GetEventId())
+	}
+
+	// one buffered event persisted by a previous update, one pending persistence and one added in this session
+	addSignal("signal1")
+	_, err := s.msBuilder.CloseUpdateSession()
+	s.Nil(err)
+	addSignal("signal2")
+	s.Nil(s.msBuilder.FlushBufferedEvents())
+	addSignal("signal3")
+	s.True(s.msBuilder.HasBufferedEvents())
+
+	bufferedEvents, err := s.msBuilder.getBufferedEvents()
+	s.Nil(err)
+	s.Equal(3, len(bufferedEvents))
+	for i, signalName := range []string{"signal1", "signal2", "signal3"} {
+		s.Equal(workflow.EventTypeWorkflowExecutionSignaled, bufferedEvents[i].GetEventType())
+		s.Equal(signalName, bufferedEvents[i].WorkflowExecutionSignaledEventAttributes.GetSignalName())
+	}
+}
+
+func (s *mutableStateSuite) TestAddDecisionTaskScheduledEventRaisesDecisionTimeout() {
+	s.msBuilder.config.MinDecisionStartToCloseTimeoutInSecs = func(...dynamicconfig.FilterOption) int { return 5 }
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	addWorkflowExecutionStartedEvent(s.msBuilder, we, "wType", "testTaskList", []byte("input"), 100, 1, "identity")
+
+	di := s.msBuilder.AddDecisionTaskScheduledEvent()
+	s.NotNil(di)
+	s.Equal(int32(s.msBuilder.config.MinDecisionStartToCloseTimeoutInSecs()), di.DecisionTimeout)
+	s.Equal(int32(1), s.msBuilder.executionInfo.DecisionTimeoutValue)
+	s.True(s.msBuilder.decisionTimeoutClampLogged)
+
+	s.msBuilder.executionInfo.DecisionTimeoutValue = 200
+	s.Equal(int32(200), s.msBuilder.getDecisionStartToCloseTimeout())
+}
+
+func (s *mutableStateSuite) TestIsCanaryRun() {
+	canaryRuns := 0
+	for i := 0; i < 1000; i++ {
+		runID := uuid.New()
+		isCanary := isCanaryRun(runID, 20)
+		s.Equal(isCanary, isCanaryRun(runID, 20))
+		s.False(isCanaryRun(runID, 0))
+		s.True(isCanaryRun(runID, 100))
+		if isCanary {
+			canaryRuns++
+		}
+	}
+	s.InDelta(200, canaryRuns, 60)
+}
+
+func (s *mutableStateSuite) TestAddDecisionTaskScheduledEventCanaryTaskList() {
+	canaryPercentage := 100
+	s.msBuilder.config.DecisionCanaryTaskList = func(opts ...dynamicconfig.FilterOption) string {
+		return "testTaskList-canary"
+	}
+	s.msBuilder.config.DecisionCanaryPercentage = func(opts ...dynamicconfig.FilterOption) int {
+		return canaryPercentage
+	}
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	addWorkflowExecutionStartedEvent(s.msBuilder, we, "wType", "testTaskList", []byte("input"), 100, 10, "identity")
+
+	di := s.msBuilder.AddDecisionTaskScheduledEvent()
+	s.NotNil(di)
+	s.Equal("testTaskList-canary", di.Tasklist)
+	s.Equal("testTaskList-canary", s.msBuilder.executionInfo.DecisionTaskList)
+	s.Equal("testTaskList", s.msBuilder.executionInfo.TaskList)
+
+	// the run stays on the task list picked for its first decision, also for the events of a retried decision
+	canaryPercentage = 0
+	s.msBuilder.FailDecision()
+	di = s.msBuilder.AddDecisionTaskScheduledEvent()
+	s.NotNil(di)
+	s.Equal("testTaskList-canary", di.Tasklist)
+	di, ok := s.msBuilder.GetPendingDecision(di.ScheduleID)
+	s.True(ok)
+	scheduledEvent, _ := s.msBuilder.createTransientDecisionEvents(di, "identity")
+	s.Equal("testTaskList-canary", scheduledEvent.DecisionTaskScheduledEventAttributes.TaskList.GetName())
+}
+
+func (s *mutableStateSuite) TestAddDecisionTaskScheduledEventCanaryTaskListAfterFirstDecision() {
+	s.msBuilder.config.DecisionCanaryTaskList = func(opts ...dynamicconfig.FilterOption) string {
+		return "testTaskList-canary"
+	}
+	s.msBuilder.config.DecisionCanaryPercentage = func(opts ...dynamicconfig.FilterOption) int {
+		return 100
+	}
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr(validRunID),
+	}
+	addWorkflowExecutionStartedEvent(s.msBuilder, we, "wType", "testTaskList", []byte("input"), 100, 10, "identity")
+
+	// a run which had decisions before a task list was picked for it is not moved to the canary task list
+	s.msBuilder.executionInfo.LastProcessedEvent = 3
+	di := s.msBuilder.AddDecisionTaskScheduledEvent()
+	s.NotNil(di)
+	s.Equal("testTaskList", di.Tasklist)
+	s.Equal("", s.msBuilder.executionInfo.DecisionTaskList)
+}
@@ -55,6 +55,8 @@ type (
 		metricsClient metrics.Client
 		rateLimiter   common.TokenBucket // Read rate limiter
 		ackMgr        queueAckMgr
+		// domainIsolation holds each domain to its share of the task workers
+		domainIsolation *domainIsolationGroup
 
 		// worker coroutines notification
 		workerNotificationChans []chan struct{}
@@ -88,6 +90,7 @@ func newQueueProcessorBase(shard ShardContext, options *QueueProcessorOptions, p
 		metricsClient:           shard.GetMetricsClient(),
 		logger:                  logger,
 		ackMgr:                  queueAckMgr,
+		domainIsolation:         newDomainIsolationGroup(shard, options.WorkerCount, options.MetricScope),
 	}
 
 	return p
@@ -215,11 +218,25 @@ func (p *queueProcessorBase) taskWorker(tasksCh <-chan queueTaskInfo, notificati
 				return
 			}
 
-			p.processWithRetry(notificationChan, task)
+			p.processWithDomainIsolation(notificationChan, task)
 		}
 	}
 }
 
+// processWithDomainIsolation processes the task once its domain has a worker slot.  A task of a domain which already
+// uses its share of the workers is parked, so that this worker moves on to other tasks, and is picked up by the worker
+// of the next task of the domain to complete.
+func (p *queueProcessorBase) processWithDomainIsolation(notificationChan <-chan struct{}, task queueTaskInfo) {
+	releaseDomainSlot, ok := p.domainIsolation.acquireOrPark(task.GetDomainID(), task)
+	if !ok {
+		return
+	}
+	for task != nil {
+		p.processWithRetry(notificationChan, task)
+		task, _ = releaseDomainSlot().(queueTaskInfo)
+	}
+}
+
 func (p *queueProcessorBase) retryTasks() {
 	for _, workerNotificationChan := range p.workerNotificationChans {
 		select {
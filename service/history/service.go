@@ -23,6 +23,8 @@ package history
 import (
 	"time"
 
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	hc "github.com/uber/cadence/client/history"
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
@@ -30,14 +32,28 @@ import (
 	"github.com/uber/cadence/common/service/dynamicconfig"
 )
 
+// TaskListRouter picks the task list of a new workflow execution from its start request, e.g. to put the
+// workflows of each tenant on a task list of its own.  It returns the task list to use in place of the one on the
+// request.
+type TaskListRouter func(domainID string, request *workflow.StartWorkflowExecutionRequest) string
+
+// ActivityTaskListResolver maps the task list an activity is scheduled on to the physical task list it is dispatched
+// to, e.g. to spread the activities of a busy task list over several partitions by their depth.
+type ActivityTaskListResolver func(domainID string, taskList string) string
+
+// identityActivityTaskListResolver dispatches activities to the task list they are scheduled on.
+func identityActivityTaskListResolver(domainID string, taskList string) string {
+	return taskList
+}
+
 // Config represents configuration for cadence-history service
 type Config struct {
 	NumberOfShards int
 
-	// HistoryCache settings
-	HistoryCacheInitialSize int
-	HistoryCacheMaxSize     int
-	HistoryCacheTTL         time.Duration
+	// HistoryCache settings, read when the cache of a shard is created
+	HistoryCacheInitialSize dynamicconfig.IntPropertyFn
+	HistoryCacheMaxSize     dynamicconfig.IntPropertyFn
+	HistoryCacheTTL         dynamicconfig.DurationPropertyFn
 
 	// ShardController settings
 	RangeSizeBits        uint
@@ -58,6 +74,15 @@ type Config struct {
 	TimerProcessorForceUpdateInterval            time.Duration
 	TimerProcessorCompleteTimerInterval          time.Duration
 	TimerProcessorMaxPollInterval                time.Duration
+	// Maximum number of user timers fired by a single update of a workflow execution, expired timers beyond it are
+	// fired by a follow up timer task, a zero TimerProcessorMaxUserTimersPerUpdate disables the limit
+	TimerProcessorMaxUserTimersPerUpdate int
+	// Timer processing paused by an operator resumes on its own after TimerProcessorMaxPauseDuration.  Timers which
+	// became due meanwhile are fired one batch per TimerProcessorResumeBatchInterval on resume, unless
+	// TimerProcessorFireOverdueTimersOnResume is set
+	TimerProcessorMaxPauseDuration          time.Duration
+	TimerProcessorResumeBatchInterval       time.Duration
+	TimerProcessorFireOverdueTimersOnResume bool
 
 	// TransferQueueProcessor settings
 	TransferTaskBatchSize                              int
@@ -79,25 +104,193 @@ type Config struct {
 	ReplicatorProcessorForceUpdateInterval time.Duration
 	ReplicatorTaskWorkerCount              int
 	ReplicatorTaskMaxRetryCount            int
+	// Retries of replicated events which failed to apply on a conflicting update before the failure is handed back to
+	// the replication task, a zero ReplicationApplyMaxRetryCount hands it back right away
+	ReplicationApplyMaxRetryCount        int
+	ReplicationApplyRetryInitialInterval time.Duration
+	ReplicationApplyRetryMaxInterval     time.Duration
 
 	// Persistence settings
 	ExecutionMgrNumConns int
 	HistoryMgrNumConns   int
 
+	// Workflow update concurrency settings, a zero MaxConcurrentUpdatesPerShard disables the limit
+	MaxConcurrentUpdatesPerShard int
+	ConcurrentUpdateWaitTimeout  time.Duration
+
+	// Domain isolation settings.  When enabled, a domain with work in flight is held to a share of the concurrent
+	// workflow updates and queue task workers of a shard proportional to its weight among the domains with work in
+	// flight, so a noisy domain cannot starve the quiet ones.  Queue tasks of a throttled domain are parked without
+	// holding a worker until a task of the domain completes.
+	EnableDomainIsolation dynamicconfig.BoolPropertyFn
+	DomainIsolationWeight dynamicconfig.IntPropertyFn
+
+	// Maximum size of the input of a signal, a zero MaxSignalInputSize disables the limit
+	MaxSignalInputSize int
+	// Maximum number of signals buffered for a workflow while its decision is in flight, further signals are
+	// rejected until the decision completes.  A zero MaxBufferedSignals disables the limit.
+	MaxBufferedSignals int
+	// Maximum size of the details of a marker, a zero MaxMarkerDetailsSize disables the limit
+	MaxMarkerDetailsSize int
+	// Maximum start to fire timeout of a user timer, a zero MaxTimerStartToFireTimeout only rejects timeouts whose
+	// expiry time cannot be represented
+	MaxTimerStartToFireTimeout time.Duration
+	// Maximum length in bytes of workflow type and task list names, a zero limit disables the check
+	MaxWorkflowTypeNameLength int
+	MaxTaskListNameLength     int
+	// Maximum number of signals delivered by a SignalWithStartWorkflowExecution and their total input size, zero
+	// disables the respective limit
+	MaxSignalWithStartSignals   int
+	MaxSignalWithStartInputSize int
+
+	// Time the engine waits for in-flight writes to complete when it is stopped, zero stops it right away
+	ShutdownDrainTimeout time.Duration
+
+	// Activity completion dedup settings, a zero ActivityCompletionDedupInterval disables the dedup
+	ActivityCompletionDedupInterval time.Duration
+	ActivityCompletionDedupMaxSize  int
+	// Start request dedup settings, a zero StartedRunIDDedupInterval disables the dedup
+	StartedRunIDDedupInterval time.Duration
+	StartedRunIDDedupMaxSize  int
+
+	// Time added to the start to close and schedule to close timeouts of an activity which accepted cancellation
+	ActivityAcceptedCancelGraceInSecs int32
+
+	// Window after the start of an activity in which a start request from the same poller is treated as a retry by
+	// matching and answered with the existing started event, a zero ActivityStartedRetryWindow disables this
+	ActivityStartedRetryWindow time.Duration
+
+	// Backoff applied before dispatching a decision retried after failures, a zero
+	// DecisionBackoffInitialInterval dispatches retried decisions right away
+	DecisionBackoffInitialInterval time.Duration
+	DecisionBackoffMaxInterval     time.Duration
+	// Attempts of a decision retried without recording its failures in history, the failure of the last one is
+	// recorded and optionally times out the workflow, a zero MaxTransientDecisionAttempts disables the cap
+	MaxTransientDecisionAttempts         int
+	TimeoutWorkflowOnMaxDecisionAttempts bool
+	// Number of completed decisions kept as reset points of a workflow execution, the oldest are dropped first,
+	// a zero MaxResetPoints disables recording reset points
+	MaxResetPoints int
+	// Bounds of the advisory tags operators attach to a workflow execution, the length applies to keys and values
+	MaxOperatorTags      int
+	MaxOperatorTagLength int
+	// Delay the decision scheduled for events which came in during a decision completed without any decisions, so a
+	// broken worker cannot spin on empty decisions.  Zero schedules the decision right away.
+	EmptyDecisionBackoffInterval time.Duration
+
+	// Fraction of stale mutable state reloads which are logged
+	StaleStateReloadLogSampleRate float64
+	// Fraction of updates which gave up after conflicting on every attempt that are logged with the last conflict
+	UpdateConflictLogSampleRate float64
+	// Fraction of workflow closes which discarded buffered events that are logged with the discarded event types
+	DiscardedBufferedEventsLogSampleRate float64
+	// Log every signal discarded on workflow close with its name, regardless of the sample rate above
+	LogDroppedSignals bool
+
+	// Range accepted for the sticky schedule to start timeout requested by workers, a zero bound is not enforced
+	StickyScheduleToStartTimeoutFloorInSecs   int32
+	StickyScheduleToStartTimeoutCeilingInSecs int32
+
+	// Batched ResetStickyTaskList settings
+	ResetStickyTaskListBatchRPS      int
+	ResetStickyTaskListBatchPageSize int
+	// Number of executions of a bulk termination which are terminated concurrently
+	BulkTerminateConcurrency int
+
+	// Maximum depth of a child workflow below its root workflow, a zero MaxChildWorkflowDepth disables the limit
+	MaxChildWorkflowDepth int32
+	// Maximum continue-as-new generation of a workflow chain, a zero MaxContinueAsNewGenerations disables the limit
+	MaxContinueAsNewGenerations int64
+	// Cluster wide switch honoring verbose DescribeWorkflowExecution requests, which attach the raw mutable state of
+	// the execution. It is not scoped to a caller: while enabled any client reaching the history service directly gets
+	// the raw state, so only turn it on for the duration of an incident
+	EnableClusterVerboseDescribe bool
+	// Fail StartChildWorkflowExecution decisions while the transfer task backlog or the timer ack level lag of the
+	// shard exceeds these bounds, a zero bound is not enforced
+	ChildStartMaxTransferTaskBacklog int64
+	ChildStartMaxTimerAckLevelLag    time.Duration
+	// Allow a workflow to start a child with its own workflow id in its own domain
+	AllowSelfReferentialChildWorkflow bool
+	// Fail decisions which signal or request cancellation of the workflow execution making them, instead of applying
+	// them to the execution in place
+	RejectSelfTargetedExternalDecisions bool
+	// Limit the execution timeout of a child workflow to the time remaining before its parent times out.  Requested
+	// timeouts up to ChildTimeoutExcessRatio times the remaining time are clamped, larger ones fail the decision.
+	LimitChildTimeoutToParent bool
+	ChildTimeoutExcessRatio   float64
+
 	// Time to hold a poll request before returning an empty response
 	// right now only used by GetMutableState
 	LongPollExpirationInterval dynamicconfig.DurationPropertyFn
+	// Upper bound of the poll time a caller can ask for, it has to stay below the timeout of the history client
+	// so a poll returns before the call times out
+	MaxLongPollExpirationInterval dynamicconfig.DurationPropertyFn
+
+	// Hand the first decision of a new workflow execution to matching synchronously, so a waiting poller
+	// picks it up without waiting on the transfer queue
+	EnableSyncMatchFirstDecision  dynamicconfig.BoolPropertyFn
+	SyncMatchFirstDecisionTimeout dynamicconfig.DurationPropertyFn
+
+	// Comma separated checksums of worker binaries which must not be given sticky decision tasks, a workflow
+	// whose last decision was completed by one of them goes back to its normal task list
+	DeniedBinaryChecksums dynamicconfig.StringPropertyFn
+
+	// Activity results and workflow results and failure details larger than PayloadOffloadThreshold bytes are
+	// handed to PayloadOffloader and only the returned reference is kept in history, zero disables offloading
+	PayloadOffloader        common.PayloadOffloader
+	PayloadOffloadThreshold dynamicconfig.IntPropertyFn
+
+	// Retention in days of closed executions of a workflow type, keyed by domain and workflow type, zero keeps the
+	// retention of the domain.  Overrides outside of the bounds of the domain are ignored.
+	WorkflowTypeRetentionDays dynamicconfig.IntPropertyFn
+	MinRetentionDays          dynamicconfig.IntPropertyFn
+	MaxRetentionDays          dynamicconfig.IntPropertyFn
+
+	// Router consulted for the task list of new workflow executions, nil starts them on the task list of the request
+	StartTaskListRouter TaskListRouter
+	// Resolver of the physical task list scheduled activities are dispatched to
+	ActivityTaskListResolver ActivityTaskListResolver
+
+	// Lower bound of the start to close timeout of scheduled decisions, shorter decision timeouts of workflow
+	// executions are raised to it.  A zero MinDecisionStartToCloseTimeoutInSecs disables the bound.
+	MinDecisionStartToCloseTimeoutInSecs dynamicconfig.IntPropertyFn
+	// Schedule to start timeout of decisions which fell back from sticky dispatch to the normal task list of a domain,
+	// which bounds the wait of decisions after their sticky worker went away, zero disables it
+	NormalDecisionScheduleToStartTimeoutInSecs dynamicconfig.IntPropertyFn
+
+	// Canary task list and the percentage of new runs, keyed by task list and workflow type, whose decisions are
+	// dispatched to it instead of the task list of the run, e.g. to roll out new worker code gradually
+	DecisionCanaryTaskList   dynamicconfig.StringPropertyFn
+	DecisionCanaryPercentage dynamicconfig.IntPropertyFn
+
+	// Comma separated decision types, keyed by domain, which fail the decision task completing them, e.g. to hold
+	// back a feature during an incident.  Only consulted while EnableDecisionTypeFilter is set.
+	EnableDecisionTypeFilter dynamicconfig.BoolPropertyFn
+	DisabledDecisionTypes    dynamicconfig.StringPropertyFn
+
+	// Maximum number of open workflow executions of a shard, new executions are rejected with a ServiceBusyError
+	// beyond it.  A zero MaxOpenExecutionsPerShard disables the limit.
+	MaxOpenExecutionsPerShard dynamicconfig.IntPropertyFn
+
+	// Maximum size of the input of an activity or child workflow scheduled by a decision, larger inputs fail the
+	// decision.  A zero MaxScheduleInputSize disables the limit.
+	MaxScheduleInputSize dynamicconfig.IntPropertyFn
+
+	// Minimum time after a run closed before a start may reuse its workflow id under the allow duplicate reuse
+	// policy, resolved per domain.  A zero interval lets a closed run be reused right away.
+	WorkflowIDReuseMinInterval dynamicconfig.DurationPropertyFn
+
+	// Minimum time between two recounts of the executions stored in a shard, a shard acquired sooner after the last
+	// recount keeps its persisted counts.  A zero ShardExecutionRecountInterval disables the recount.
+	ShardExecutionRecountInterval dynamicconfig.DurationPropertyFn
 }
 
 // NewConfig returns new service config with default values
 func NewConfig(dc *dynamicconfig.Collection, numberOfShards int) *Config {
 	return &Config{
-		NumberOfShards:                                     numberOfShards,
-		HistoryCacheInitialSize:                            128,
-		HistoryCacheMaxSize:                                512,
-		HistoryCacheTTL:                                    time.Hour,
-		RangeSizeBits:                                      20, // 20 bits for sequencer, 2^20 sequence number for any range
-		AcquireShardInterval:                               time.Minute,
+		NumberOfShards:       numberOfShards,
+		RangeSizeBits:        20, // 20 bits for sequencer, 2^20 sequence number for any range
+		AcquireShardInterval: time.Minute,
 		DefaultScheduleToStartActivityTimeoutInSecs:        10,
 		DefaultScheduleToCloseActivityTimeoutInSecs:        10,
 		DefaultStartToCloseActivityTimeoutInSecs:           10,
@@ -110,6 +303,10 @@ func NewConfig(dc *dynamicconfig.Collection, numberOfShards int) *Config {
 		TimerProcessorForceUpdateInterval:                  10 * time.Minute,
 		TimerProcessorCompleteTimerInterval:                1 * time.Second,
 		TimerProcessorMaxPollInterval:                      60 * time.Second,
+		TimerProcessorMaxUserTimersPerUpdate:               0,
+		TimerProcessorMaxPauseDuration:                     30 * time.Minute,
+		TimerProcessorResumeBatchInterval:                  time.Second,
+		TimerProcessorFireOverdueTimersOnResume:            false,
 		TransferTaskBatchSize:                              10,
 		TransferProcessorMaxPollRPS:                        100,
 		TransferProcessorMaxPollInterval:                   60 * time.Second,
@@ -127,12 +324,129 @@ func NewConfig(dc *dynamicconfig.Collection, numberOfShards int) *Config {
 		ReplicatorProcessorForceUpdateInterval:             10 * time.Minute,
 		ReplicatorTaskWorkerCount:                          10,
 		ReplicatorTaskMaxRetryCount:                        100,
+		ReplicationApplyMaxRetryCount:                      3,
+		ReplicationApplyRetryInitialInterval:               50 * time.Millisecond,
+		ReplicationApplyRetryMaxInterval:                   time.Second,
 		ExecutionMgrNumConns:                               100,
 		HistoryMgrNumConns:                                 100,
+		MaxConcurrentUpdatesPerShard:                       0,
+		ConcurrentUpdateWaitTimeout:                        100 * time.Millisecond,
+		ShutdownDrainTimeout:                               5 * time.Second,
+		MaxSignalInputSize:                                 256 * 1024,
+		MaxBufferedSignals:                                 0,
+		MaxMarkerDetailsSize:                               256 * 1024,
+		MaxTimerStartToFireTimeout:                         100 * 365 * 24 * time.Hour,
+		MaxWorkflowTypeNameLength:                          1000,
+		MaxTaskListNameLength:                              1000,
+		MaxSignalWithStartSignals:                          0,
+		MaxSignalWithStartInputSize:                        1024 * 1024,
+		ActivityCompletionDedupInterval:                    time.Minute,
+		ActivityCompletionDedupMaxSize:                     10000,
+		StartedRunIDDedupInterval:                          time.Minute,
+		StartedRunIDDedupMaxSize:                           10000,
+		ActivityAcceptedCancelGraceInSecs:                  60,
+		ActivityStartedRetryWindow:                         10 * time.Second,
+		DecisionBackoffInitialInterval:                     0,
+		DecisionBackoffMaxInterval:                         time.Minute,
+		MaxTransientDecisionAttempts:                       0,
+		TimeoutWorkflowOnMaxDecisionAttempts:               false,
+		MaxResetPoints:                                     20,
+		MaxOperatorTags:                                    10,
+		MaxOperatorTagLength:                               256,
+		EmptyDecisionBackoffInterval:                       0,
+		StaleStateReloadLogSampleRate:                      0.01,
+		UpdateConflictLogSampleRate:                        0.1,
+		DiscardedBufferedEventsLogSampleRate:               0.1,
+		LogDroppedSignals:                                  false,
+		StickyScheduleToStartTimeoutFloorInSecs:            0,
+		StickyScheduleToStartTimeoutCeilingInSecs:          0,
+		ResetStickyTaskListBatchRPS:                        100,
+		ResetStickyTaskListBatchPageSize:                   100,
+		BulkTerminateConcurrency:                           10,
+		MaxChildWorkflowDepth:                              0,
+		MaxContinueAsNewGenerations:                        0,
+		LimitChildTimeoutToParent:                          false,
+		ChildStartMaxTransferTaskBacklog:                   0,
+		ChildStartMaxTimerAckLevelLag:                      0,
+		EnableClusterVerboseDescribe:                       false,
+		ChildTimeoutExcessRatio:                            2,
+		AllowSelfReferentialChildWorkflow:                  false,
+		RejectSelfTargetedExternalDecisions:                false,
+		PayloadOffloader:                                   common.NewNoopPayloadOffloader(),
+		ActivityTaskListResolver:                           identityActivityTaskListResolver,
+		HistoryCacheInitialSize: dc.GetIntProperty(
+			dynamicconfig.HistoryCacheInitialSize, 128,
+		),
+		HistoryCacheMaxSize: dc.GetIntProperty(
+			dynamicconfig.HistoryCacheMaxSize, 512,
+		),
+		HistoryCacheTTL: dc.GetDurationProperty(
+			dynamicconfig.HistoryCacheTTL, time.Hour,
+		),
 		// history client: client/history/client.go set the client timeout 30s
 		LongPollExpirationInterval: dc.GetDurationProperty(
 			dynamicconfig.HistoryLongPollExpirationInterval, time.Second*20,
 		),
+		MaxLongPollExpirationInterval: dc.GetDurationProperty(
+			dynamicconfig.HistoryMaxLongPollExpirationInterval, hc.DefaultTimeout-time.Second*5,
+		),
+		EnableSyncMatchFirstDecision: dc.GetBoolProperty(
+			dynamicconfig.HistoryEnableSyncMatchFirstDecision, false,
+		),
+		SyncMatchFirstDecisionTimeout: dc.GetDurationProperty(
+			dynamicconfig.HistorySyncMatchFirstDecisionTimeout, time.Millisecond*200,
+		),
+		DeniedBinaryChecksums: dc.GetStringProperty(
+			dynamicconfig.HistoryDeniedBinaryChecksums, "",
+		),
+		PayloadOffloadThreshold: dc.GetIntProperty(
+			dynamicconfig.HistoryPayloadOffloadThreshold, 0,
+		),
+		WorkflowTypeRetentionDays: dc.GetIntProperty(
+			dynamicconfig.HistoryWorkflowTypeRetentionDays, 0,
+		),
+		MinRetentionDays: dc.GetIntProperty(
+			dynamicconfig.HistoryMinRetentionDays, 1,
+		),
+		MaxRetentionDays: dc.GetIntProperty(
+			dynamicconfig.HistoryMaxRetentionDays, 90,
+		),
+		EnableDomainIsolation: dc.GetBoolProperty(
+			dynamicconfig.HistoryEnableDomainIsolation, false,
+		),
+		DomainIsolationWeight: dc.GetIntProperty(
+			dynamicconfig.HistoryDomainIsolationWeight, 1,
+		),
+		MinDecisionStartToCloseTimeoutInSecs: dc.GetIntProperty(
+			dynamicconfig.HistoryMinDecisionStartToCloseTimeoutInSecs, 0,
+		),
+		NormalDecisionScheduleToStartTimeoutInSecs: dc.GetIntProperty(
+			dynamicconfig.HistoryNormalDecisionScheduleToStartTimeoutInSecs, 0,
+		),
+		DecisionCanaryTaskList: dc.GetStringProperty(
+			dynamicconfig.HistoryDecisionCanaryTaskList, "",
+		),
+		DecisionCanaryPercentage: dc.GetIntProperty(
+			dynamicconfig.HistoryDecisionCanaryPercentage, 0,
+		),
+		EnableDecisionTypeFilter: dc.GetBoolProperty(
+			dynamicconfig.HistoryEnableDecisionTypeFilter, false,
+		),
+		DisabledDecisionTypes: dc.GetStringProperty(
+			dynamicconfig.HistoryDisabledDecisionTypes, "",
+		),
+		MaxOpenExecutionsPerShard: dc.GetIntProperty(
+			dynamicconfig.HistoryMaxOpenExecutionsPerShard, 0,
+		),
+		MaxScheduleInputSize: dc.GetIntProperty(
+			dynamicconfig.HistoryMaxScheduleInputSize, 0,
+		),
+		WorkflowIDReuseMinInterval: dc.GetDurationProperty(
+			dynamicconfig.HistoryWorkflowIDReuseMinInterval, 0,
+		),
+		ShardExecutionRecountInterval: dc.GetDurationProperty(
+			dynamicconfig.HistoryShardExecutionRecountInterval, 24*time.Hour,
+		),
 	}
 }
 
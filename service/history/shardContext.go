@@ -22,6 +22,7 @@ package history
 
 import (
 	"fmt"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -41,6 +42,7 @@ import (
 type (
 	// ShardContext represents a history engine shard
 	ShardContext interface {
+		GetShardID() int
 		GetService() service.Service
 		GetExecutionManager() persistence.ExecutionManager
 		GetHistoryManager() persistence.HistoryManager
@@ -60,6 +62,7 @@ type (
 		CreateWorkflowExecution(request *persistence.CreateWorkflowExecutionRequest) (
 			*persistence.CreateWorkflowExecutionResponse, error)
 		UpdateWorkflowExecution(request *persistence.UpdateWorkflowExecutionRequest) error
+		DeleteWorkflowExecution(request *persistence.DeleteWorkflowExecutionRequest, isRunning bool) error
 		AppendHistoryEvents(request *persistence.AppendHistoryEventsRequest) error
 		NotifyNewHistoryEvent(event *historyEventNotification) error
 		GetConfig() *Config
@@ -68,6 +71,19 @@ type (
 		GetTimeSource() common.TimeSource
 		SetCurrentTime(cluster string, currentTime time.Time)
 		GetCurrentTime(cluster string) time.Time
+		GetStats() *ShardStats
+	}
+
+	// ShardStats is a cheap snapshot of the workload managed by a shard.  Execution counts are maintained
+	// incrementally and persisted with the shard info, so they are approximate.
+	ShardStats struct {
+		ShardID              int
+		OpenExecutionCount   int64
+		ClosedExecutionCount int64 // closed executions not yet deleted after retention
+		// TransferTaskBacklog is the distance between the transfer task ack level and the max read level
+		TransferTaskBacklog int64
+		// TimerAckLevelLag is how far the timer task ack level trails the current time
+		TimerAckLevelLag time.Duration
 	}
 
 	shardContextImpl struct {
@@ -90,11 +106,26 @@ type (
 		maxTransferSequenceNumber int64
 		transferMaxReadLevel      int64
 		standbyClusterCurrentTime map[string]time.Time
+		// changes made to the execution counts while they are recounted, nil when no recount is running
+		recountDeltas *executionCountDeltas
+	}
+
+	// executionCountDeltas are the changes made to the execution counts of a shard since a recount started
+	executionCountDeltas struct {
+		open   int64
+		closed int64
 	}
 )
 
+// executionCountPageSize is the page size used to recount the executions of a shard when it is acquired
+const executionCountPageSize = 1000
+
 var _ ShardContext = (*shardContextImpl)(nil)
 
+func (s *shardContextImpl) GetShardID() int {
+	return s.shardID
+}
+
 func (s *shardContextImpl) GetService() service.Service {
 	return s.service
 }
@@ -283,6 +314,8 @@ Create_Loop:
 					}
 				}
 			}
+		} else {
+			s.updateExecutionCountsLocked(1, 0)
 		}
 
 		return response, err
@@ -379,6 +412,13 @@ Update_Loop:
 					}
 				}
 			}
+		} else {
+			if request.FinishExecution {
+				s.updateExecutionCountsLocked(-1, 1)
+			}
+			if request.ContinueAsNew != nil {
+				s.updateExecutionCountsLocked(1, 0)
+			}
 		}
 
 		return err
@@ -387,6 +427,38 @@ Update_Loop:
 	return ErrMaxAttemptsExceeded
 }
 
+// DeleteWorkflowExecution deletes the execution and takes it off the open or the closed execution count of the shard,
+// depending on isRunning
+func (s *shardContextImpl) DeleteWorkflowExecution(request *persistence.DeleteWorkflowExecutionRequest,
+	isRunning bool) error {
+	// No need to lock context here, as deleting an execution does not depend on the range
+	if err := s.executionManager.DeleteWorkflowExecution(request); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if isRunning {
+		s.updateExecutionCountsLocked(-1, 0)
+	} else {
+		s.updateExecutionCountsLocked(0, -1)
+	}
+	return nil
+}
+
+func (s *shardContextImpl) GetStats() *ShardStats {
+	s.RLock()
+	defer s.RUnlock()
+
+	return &ShardStats{
+		ShardID:              s.shardID,
+		OpenExecutionCount:   s.shardInfo.OpenExecutionCount,
+		ClosedExecutionCount: s.shardInfo.ClosedExecutionCount,
+		TransferTaskBacklog:  s.transferMaxReadLevel - s.shardInfo.TransferAckLevel,
+		TimerAckLevelLag:     time.Now().Sub(s.shardInfo.TimerAckLevel),
+	}
+}
+
 func (s *shardContextImpl) AppendHistoryEvents(request *persistence.AppendHistoryEventsRequest) error {
 	// No need to lock context here, as we can write concurrently to append history events
 	currentRangeID := atomic.LoadInt64(&s.rangeID)
@@ -523,6 +595,29 @@ func (s *shardContextImpl) updateShardInfoLocked() error {
 	return err
 }
 
+// updateExecutionCountsLocked adjusts the execution counts of the shard, they are persisted with the next
+// update of the shard info.  Counts never go below zero as they are only approximate.
+func (s *shardContextImpl) updateExecutionCountsLocked(openDelta, closedDelta int64) {
+	if s.recountDeltas != nil {
+		s.recountDeltas.open += openDelta
+		s.recountDeltas.closed += closedDelta
+	}
+	s.shardInfo.OpenExecutionCount += openDelta
+	if s.shardInfo.OpenExecutionCount < 0 {
+		s.shardInfo.OpenExecutionCount = 0
+	}
+	s.shardInfo.ClosedExecutionCount += closedDelta
+	if s.shardInfo.ClosedExecutionCount < 0 {
+		s.shardInfo.ClosedExecutionCount = 0
+	}
+	s.emitOpenExecutionsGaugeLocked()
+}
+
+func (s *shardContextImpl) emitOpenExecutionsGaugeLocked() {
+	s.metricsClient.Tagged(map[string]string{metrics.ShardTagName: strconv.Itoa(s.shardInfo.ShardID)}).UpdateGauge(
+		metrics.HistoryShardContextScope, metrics.OpenExecutionsGauge, float64(s.shardInfo.OpenExecutionCount))
+}
+
 func (s *shardContextImpl) allocateTimerIDsLocked(timerTasks []persistence.Task) error {
 	// assign IDs for the timer tasks. They need to be assigned under shard lock.
 	for _, task := range timerTasks {
@@ -620,10 +715,67 @@ func acquireShard(shardID int, svc service.Service, shardManager persistence.Sha
 	if err1 != nil {
 		return nil, err1
 	}
+	context.emitOpenExecutionsGaugeLocked()
+
+	recountInterval := config.ShardExecutionRecountInterval()
+	if recountInterval > 0 && time.Now().Sub(shardInfo.ExecutionsCountedAt) >= recountInterval {
+		context.recountDeltas = &executionCountDeltas{}
+		go context.recountExecutions()
+	}
 
 	return context, nil
 }
 
+// recountExecutions seeds the execution counts of the shard from the executions stored in it.  The counts persisted
+// with the shard info miss the executions created, closed or deleted since the last update of the shard, and are
+// missing altogether for shards written before the counts existed.  Paging through the executions takes a while on a
+// large shard, so it is done in the background and the changes made to the counts meanwhile are applied on top of
+// the recount once it is done.  The recounted counts are persisted with the next update of the shard info, along with
+// the time of the recount which keeps the shard from being recounted again before ShardExecutionRecountInterval.
+func (s *shardContextImpl) recountExecutions() {
+	openExecutionCount, closedExecutionCount, err := countExecutions(s.executionManager)
+
+	s.Lock()
+	defer s.Unlock()
+	deltas := s.recountDeltas
+	s.recountDeltas = nil
+	if err != nil {
+		s.logger.Warnf("Failed to recount executions, keeping counts open: %v, closed: %v: %v",
+			s.shardInfo.OpenExecutionCount, s.shardInfo.ClosedExecutionCount, err)
+		return
+	}
+
+	// the executions created or closed while paging may be counted by both the recount and the deltas, the counts
+	// are only approximate either way
+	s.shardInfo.OpenExecutionCount = 0
+	s.shardInfo.ClosedExecutionCount = 0
+	s.updateExecutionCountsLocked(openExecutionCount+deltas.open, closedExecutionCount+deltas.closed)
+	s.shardInfo.ExecutionsCountedAt = time.Now()
+}
+
+// countExecutions pages through the workflow executions stored in a shard, and returns the number of open and closed
+// executions
+func countExecutions(executionMgr persistence.ExecutionManager) (int64, int64, error) {
+	openCount := int64(0)
+	closedCount := int64(0)
+	var nextPageToken []byte
+	for {
+		response, err := executionMgr.ListOpenExecutions(&persistence.ListOpenExecutionsRequest{
+			PageSize:      executionCountPageSize,
+			NextPageToken: nextPageToken,
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+		openCount += int64(len(response.Executions))
+		closedCount += int64(response.ClosedCount)
+		if len(response.NextPageToken) == 0 {
+			return openCount, closedCount, nil
+		}
+		nextPageToken = response.NextPageToken
+	}
+}
+
 func copyShardInfo(shardInfo *persistence.ShardInfo) *persistence.ShardInfo {
 	clusterTransferAckLevel := make(map[string]int64)
 	for k, v := range shardInfo.ClusterTransferAckLevel {
@@ -643,6 +795,9 @@ func copyShardInfo(shardInfo *persistence.ShardInfo) *persistence.ShardInfo {
 		TimerAckLevel:           shardInfo.TimerAckLevel,
 		ClusterTransferAckLevel: clusterTransferAckLevel,
 		ClusterTimerAckLevel:    clusterTimerAckLevel,
+		OpenExecutionCount:      shardInfo.OpenExecutionCount,
+		ClosedExecutionCount:    shardInfo.ClosedExecutionCount,
+		ExecutionsCountedAt:     shardInfo.ExecutionsCountedAt,
 	}
 
 	return shardInfoCopy
@@ -359,6 +359,42 @@ func (c *shardController) numShards() int {
 	return nShards
 }
 
+// shardStats returns the stats of all shards with a running engine on this host
+func (c *shardController) shardStats() []*ShardStats {
+	c.RLock()
+	items := make([]*historyShardsItem, 0, len(c.historyShards))
+	for _, item := range c.historyShards {
+		items = append(items, item)
+	}
+	c.RUnlock()
+
+	stats := make([]*ShardStats, 0, len(items))
+	for _, item := range items {
+		if engine := item.getEngine(); engine != nil {
+			stats = append(stats, engine.GetShardStats())
+		}
+	}
+	return stats
+}
+
+// queueLag returns the queue lag of all shards with a running engine on this host
+func (c *shardController) queueLag() []*QueueLag {
+	c.RLock()
+	items := make([]*historyShardsItem, 0, len(c.historyShards))
+	for _, item := range c.historyShards {
+		items = append(items, item)
+	}
+	c.RUnlock()
+
+	lags := make([]*QueueLag, 0, len(items))
+	for _, item := range items {
+		if engine := item.getEngine(); engine != nil {
+			lags = append(lags, engine.GetQueueLag())
+		}
+	}
+	return lags
+}
+
 func (i *historyShardsItem) getEngine() Engine {
 	i.RLock()
 	defer i.RUnlock()
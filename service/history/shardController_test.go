@@ -116,6 +116,8 @@ func (s *shardControllerSuite) TestAcquireShardSuccess() {
 		if hostID == 0 {
 			myShards = append(myShards, shardID)
 			mockExecutionMgr := &mmocks.ExecutionManager{}
+			mockExecutionMgr.On("ListOpenExecutions", mock.Anything).Return(
+				&persistence.ListOpenExecutionsResponse{}, nil)
 			s.mockExecutionMgrFactory.On("CreateExecutionManager", mock.Anything).Return(mockExecutionMgr, nil).Once()
 			mockEngine := &MockHistoryEngine{}
 			mockEngine.On("Start").Return().Once()
@@ -204,6 +206,8 @@ func (s *shardControllerSuite) TestAcquireShardRenewSuccess() {
 
 	for shardID := 0; shardID < numShards; shardID++ {
 		mockExecutionMgr := &mmocks.ExecutionManager{}
+		mockExecutionMgr.On("ListOpenExecutions", mock.Anything).Return(
+			&persistence.ListOpenExecutionsResponse{}, nil)
 		s.mockExecutionMgrFactory.On("CreateExecutionManager", mock.Anything).Return(mockExecutionMgr, nil).Once()
 		mockEngine := &MockHistoryEngine{}
 		mockEngine.On("Start").Return().Once()
@@ -277,6 +281,8 @@ func (s *shardControllerSuite) TestAcquireShardRenewLookupFailed() {
 
 	for shardID := 0; shardID < numShards; shardID++ {
 		mockExecutionMgr := &mmocks.ExecutionManager{}
+		mockExecutionMgr.On("ListOpenExecutions", mock.Anything).Return(
+			&persistence.ListOpenExecutionsResponse{}, nil)
 		s.mockExecutionMgrFactory.On("CreateExecutionManager", mock.Anything).Return(mockExecutionMgr, nil).Once()
 		mockEngine := &MockHistoryEngine{}
 		mockEngine.On("Start").Return().Once()
@@ -571,6 +577,8 @@ func (s *shardControllerSuite) setupMocksForAcquireShard(shardID int, mockEngine
 	alternativeClusterTimerAck := time.Now().Add(-200 * time.Second)
 
 	mockExecutionMgr := &mmocks.ExecutionManager{}
+	mockExecutionMgr.On("ListOpenExecutions", mock.Anything).Return(
+		&persistence.ListOpenExecutionsResponse{}, nil)
 	mockExecutionMgr.On("Close").Return()
 	s.mockExecutionMgrFactory.On("CreateExecutionManager", shardID).Return(mockExecutionMgr, nil).Once()
 	mockEngine.On("Start").Return().Once()
@@ -22,6 +22,7 @@ package history
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"sync/atomic"
 	"time"
@@ -152,6 +153,28 @@ func (tb *timerBuilder) AddScheduleToStartDecisionTimoutTask(scheduleID, schedul
 	return timeOutTask
 }
 
+// AddDecisionBackoffTask - Add a task which dispatches a retried decision once the backoff elapses.
+func (tb *timerBuilder) AddDecisionBackoffTask(scheduleID, scheduleAttempt int64,
+	backoff time.Duration) *persistence.DecisionBackoffTask {
+	backoffTask := &persistence.DecisionBackoffTask{
+		VisibilityTimestamp: tb.timeSource.Now().Add(backoff),
+		EventID:             scheduleID,
+		ScheduleAttempt:     scheduleAttempt,
+	}
+	tb.logger.Debugf("Adding Decision Backoff: with backoff: %v, EventID: %v, Attempt: %v",
+		backoff, scheduleID, scheduleAttempt)
+	return backoffTask
+}
+
+// AddWorkflowTimeoutNowTask - Add a task which times out the workflow right away.
+func (tb *timerBuilder) AddWorkflowTimeoutNowTask() *persistence.WorkflowTimeoutTask {
+	timeoutTask := &persistence.WorkflowTimeoutTask{
+		VisibilityTimestamp: tb.timeSource.Now(),
+	}
+	tb.logger.Debugf("Adding Workflow Timeout: at %v", timeoutTask.VisibilityTimestamp)
+	return timeoutTask
+}
+
 // TODO this function is only used by tiemr queue processor test
 func (tb *timerBuilder) AddScheduleToCloseActivityTimeout(
 	ai *persistence.ActivityInfo) (*persistence.ActivityTimeoutTask, error) {
@@ -274,7 +297,13 @@ func (tb *timerBuilder) loadActivityTimers(msBuilder *mutableStateBuilder) {
 	tb.activityTimers = make(timers, 0, len(msBuilder.pendingActivityInfoIDs))
 	for _, v := range msBuilder.pendingActivityInfoIDs {
 		if v.ScheduleID != emptyEventID {
-			scheduleToCloseExpiry := v.ScheduledTime.Add(time.Duration(v.ScheduleToCloseTimeout) * time.Second)
+			// an activity which accepted cancellation is given extra time to clean up before it is timed out
+			var cancelGrace time.Duration
+			if v.AcceptedCancel {
+				cancelGrace = time.Duration(tb.config.ActivityAcceptedCancelGraceInSecs) * time.Second
+			}
+			scheduleToCloseExpiry := v.ScheduledTime.Add(time.Duration(v.ScheduleToCloseTimeout)*time.Second +
+				cancelGrace)
 			td := &timerDetails{
 				TimerSequenceID: TimerSequenceID{VisibilityTimestamp: scheduleToCloseExpiry},
 				ActivityID:      v.ScheduleID,
@@ -285,7 +314,7 @@ func (tb *timerBuilder) loadActivityTimers(msBuilder *mutableStateBuilder) {
 			tb.activityTimers = append(tb.activityTimers, td)
 
 			if v.StartedID != emptyEventID {
-				startToCloseExpiry := v.StartedTime.Add(time.Duration(v.StartToCloseTimeout) * time.Second)
+				startToCloseExpiry := v.StartedTime.Add(time.Duration(v.StartToCloseTimeout)*time.Second + cancelGrace)
 				td := &timerDetails{
 					TimerSequenceID: TimerSequenceID{VisibilityTimestamp: startToCloseExpiry},
 					ActivityID:      v.ScheduleID,
@@ -440,3 +469,18 @@ func getActivityTimerStatus(timeoutType w.TimeoutType) int32 {
 	}
 	panic("invalid timeout type")
 }
+
+// maxTimerStartToFireTimeoutSeconds returns the largest start to fire timeout of a timer started at now whose expiry
+// time still fits into the nanoseconds since epoch of a time.Time.
+func maxTimerStartToFireTimeoutSeconds(now time.Time) int64 {
+	return (math.MaxInt64 - now.UnixNano()) / int64(time.Second)
+}
+
+// getTimerExpiryTime returns the expiry time of a timer started at now, saturated at the latest time which can be
+// represented, so a replicated timer with an overflowing timeout never fires instead of firing in the past.
+func getTimerExpiryTime(now time.Time, startToFireTimeoutSeconds int64) time.Time {
+	if startToFireTimeoutSeconds > maxTimerStartToFireTimeoutSeconds(now) {
+		return time.Unix(0, math.MaxInt64)
+	}
+	return now.Add(time.Duration(startToFireTimeoutSeconds) * time.Second)
+}
@@ -230,6 +230,22 @@ func (t *timerQueueAckMgrImpl) getAckLevel() TimerSequenceID {
 	return t.ackLevel
 }
 
+// getOldestPendingTimer returns the visibility timestamp of the earliest loaded timer task which is not processed yet
+func (t *timerQueueAckMgrImpl) getOldestPendingTimer() (time.Time, bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	var oldest time.Time
+	found := false
+	for sequenceID, acked := range t.outstandingTasks {
+		if !acked && (!found || sequenceID.VisibilityTimestamp.Before(oldest)) {
+			oldest = sequenceID.VisibilityTimestamp
+			found = true
+		}
+	}
+	return oldest, found
+}
+
 func (t *timerQueueAckMgrImpl) updateAckLevel() {
 	t.metricsClient.IncCounter(metrics.TimerQueueProcessorScope, metrics.AckLevelUpdateCounter)
 
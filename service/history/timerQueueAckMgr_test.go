@@ -518,6 +518,9 @@ func (s *timerQueueAckMgrSuite) TestReadCompleteUpdateTimerTasks() {
 	s.True(s.timerQueueAckMgr.outstandingTasks[timerSequenceID1])
 	s.timerQueueAckMgr.updateAckLevel()
 	s.Equal(timer1.VisibilityTimestamp, s.mockShard.GetTimerClusterAckLevel(s.clusterName))
+	oldestPendingTimer, ok := s.timerQueueAckMgr.getOldestPendingTimer()
+	s.True(ok)
+	s.Equal(timer2.VisibilityTimestamp, oldestPendingTimer)
 
 	// there will be no call to update shard
 	timerSequenceID3 := TimerSequenceID{VisibilityTimestamp: timer3.VisibilityTimestamp, TaskID: timer3.TaskID}
@@ -534,6 +537,8 @@ func (s *timerQueueAckMgrSuite) TestReadCompleteUpdateTimerTasks() {
 	s.True(s.timerQueueAckMgr.outstandingTasks[timerSequenceID2])
 	s.timerQueueAckMgr.updateAckLevel()
 	s.Equal(timer3.VisibilityTimestamp, s.mockShard.GetTimerClusterAckLevel(s.clusterName))
+	_, ok = s.timerQueueAckMgr.getOldestPendingTimer()
+	s.False(ok)
 }
 
 // Tests for failover ack manager
@@ -182,6 +182,10 @@ func (t *timerQueueActiveProcessorImpl) process(timerTask *persistence.TimerTask
 	case persistence.TaskTypeDeleteHistoryEvent:
 		scope = metrics.TimerTaskDeleteHistoryEvent
 		err = t.timerQueueProcessorBase.processDeleteHistoryEvent(timerTask)
+
+	case persistence.TaskTypeDecisionBackoff:
+		scope = metrics.TimerTaskDecisionBackoffScope
+		err = t.processDecisionBackoff(timerTask)
 	}
 
 	if err != nil {
@@ -226,8 +230,11 @@ Update_History_Loop:
 		}
 
 		var timerTasks []persistence.Task
+		var fireDelays []time.Duration
 		scheduleNewDecision := false
+		maxTimersPerUpdate := t.shard.GetConfig().TimerProcessorMaxUserTimersPerUpdate
 
+		// user timers are sorted by expiry time, so the earliest timers are fired first
 	ExpireUserTimers:
 		for _, td := range tBuilder.GetUserTimers(msBuilder) {
 			hasTimer, ti := tBuilder.GetUserTimer(td.TimerID)
@@ -237,10 +244,26 @@ Update_History_Loop:
 			}
 
 			if isExpired := tBuilder.IsTimerExpired(td, task.VisibilityTimestamp); isExpired {
+				if maxTimersPerUpdate > 0 && len(fireDelays) >= maxTimersPerUpdate {
+					// Leave the remaining expired timers to a new timer task.  It is due now instead of at the
+					// expiry of the timer, as timer tasks before the read level of the queue are not loaded.
+					nextTask := &persistence.UserTimerTask{
+						VisibilityTimestamp: t.shard.GetCurrentTime(t.currentClusterName),
+						EventID:             ti.StartedID,
+					}
+					timerTasks = []persistence.Task{nextTask}
+
+					ti.TaskID = TimerTaskStatusCreated
+					msBuilder.UpdateUserTimer(ti.TimerID, ti)
+					break ExpireUserTimers
+				}
+
 				// Add TimerFired event to history.
 				if msBuilder.AddTimerFiredEvent(ti.StartedID, ti.TimerID) == nil {
 					return errFailedToAddTimerFiredEvent
 				}
+				// the time the timer ran on top of its configured duration
+				fireDelays = append(fireDelays, t.shard.GetTimeSource().Now().Sub(ti.ExpiryTime))
 
 				scheduleNewDecision = !msBuilder.HasPendingDecisionTask()
 			} else {
@@ -252,7 +275,6 @@ Update_History_Loop:
 					// Update the task ID tracking the corresponding timer task.
 					ti.TaskID = nextTask.GetTaskID()
 					msBuilder.UpdateUserTimer(ti.TimerID, ti)
-					defer t.notifyNewTimers(timerTasks)
 				}
 
 				// Done!
@@ -262,15 +284,26 @@ Update_History_Loop:
 
 		// We apply the update to execution using optimistic concurrency.  If it fails due to a conflict than reload
 		// the history and try the operation again.
-		err := t.updateWorkflowExecution(context, msBuilder, scheduleNewDecision, false, timerTasks, nil)
+		err := t.updateWorkflowExecution(context, msBuilder, scheduleNewDecision, false, false, timerTasks, nil)
 		if err != nil {
 			if err == ErrConflict {
 				continue Update_History_Loop
 			}
+			return err
 		}
-		return err
+
+		if len(fireDelays) > 0 {
+			metricsClient := t.metricsClient.Tagged(map[string]string{
+				metrics.DomainTagName:   getMetricsDomainName(t.shard, msBuilder.executionInfo.DomainID),
+				metrics.TaskListTagName: msBuilder.executionInfo.TaskList,
+			})
+			for _, fireDelay := range fireDelays {
+				metricsClient.RecordTimer(metrics.TimerTaskUserTimerScope, metrics.UserTimerFireDelay, fireDelay)
+			}
+		}
+		return nil
 	}
-	return ErrMaxAttemptsExceeded
+	return context.newMaxAttemptsExceededError()
 }
 
 func (t *timerQueueActiveProcessorImpl) processActivityTimeout(timerTask *persistence.TimerTaskInfo) (retError error) {
@@ -403,7 +436,7 @@ Update_History_Loop:
 			// We apply the update to execution using optimistic concurrency.  If it fails due to a conflict than reload
 			// the history and try the operation again.
 			scheduleNewDecision := updateHistory && !msBuilder.HasPendingDecisionTask()
-			err := t.updateWorkflowExecution(context, msBuilder, scheduleNewDecision, false, timerTasks, nil)
+			err := t.updateWorkflowExecution(context, msBuilder, scheduleNewDecision, false, false, timerTasks, nil)
 			if err != nil {
 				if err == ErrConflict {
 					continue Update_History_Loop
@@ -416,7 +449,7 @@ Update_History_Loop:
 
 		return nil
 	}
-	return ErrMaxAttemptsExceeded
+	return context.newMaxAttemptsExceededError()
 }
 
 func (t *timerQueueActiveProcessorImpl) processDecisionTimeout(task *persistence.TimerTaskInfo) (retError error) {
@@ -450,35 +483,63 @@ Update_History_Loop:
 		}
 
 		scheduleNewDecision := false
+		isStickyFallback := false
+		var timerTasks []persistence.Task
 		switch task.TimeoutType {
 		case int(workflow.TimeoutTypeStartToClose):
 			t.metricsClient.IncCounter(metrics.TimerTaskDecisionTimeoutScope, metrics.StartToCloseTimeoutCounter)
 			if isPending && di.Attempt == task.ScheduleAttempt && msBuilder.isWorkflowExecutionRunning() {
+				timeoutWorkflow := msBuilder.shouldTimeoutOnDecisionFailure(di.Attempt)
 				// Add a decision task timeout event.
 				msBuilder.AddDecisionTaskTimedOutEvent(scheduleID, di.StartedID)
-				scheduleNewDecision = true
+				if timeoutWorkflow {
+					// the decision used up its attempts, time out the workflow instead of retrying the decision
+					tBuilder := t.historyService.getTimerBuilder(&context.workflowExecution)
+					timerTasks = append(timerTasks, tBuilder.AddWorkflowTimeoutNowTask())
+				} else {
+					scheduleNewDecision = true
+				}
 			}
 		case int(workflow.TimeoutTypeScheduleToStart):
 			t.metricsClient.IncCounter(metrics.TimerTaskDecisionTimeoutScope, metrics.ScheduleToStartTimeoutCounter)
-			// decision schedule to start timeout only apply to sticky decision
-			// check if scheduled decision still pending and not started yet
+			// decision schedule to start timeout applies to sticky decisions and to decisions which fell back from
+			// sticky dispatch to the normal task list, check if scheduled decision still pending and not started yet
 			if isPending && di.Attempt == task.ScheduleAttempt && msBuilder.isWorkflowExecutionRunning() &&
-				di.StartedID == emptyEventID && msBuilder.isStickyTaskListEnabled() {
-				timeoutEvent := msBuilder.AddDecisionTaskScheduleToStartTimeoutEvent(scheduleID)
-				if timeoutEvent == nil {
-					// Unable to add DecisionTaskTimedout event to history
-					return &workflow.InternalServiceError{Message: "Unable to add DecisionTaskScheduleToStartTimeout event to history."}
-				}
+				di.StartedID == emptyEventID {
+				if msBuilder.isStickyTaskListEnabled() {
+					timeoutEvent := msBuilder.AddDecisionTaskScheduleToStartTimeoutEvent(scheduleID)
+					if timeoutEvent == nil {
+						// Unable to add DecisionTaskTimedout event to history
+						return &workflow.InternalServiceError{Message: "Unable to add DecisionTaskScheduleToStartTimeout event to history."}
+					}
+
+					// reschedule decision, which will be on its original task list
+					scheduleNewDecision = true
+					isStickyFallback = true
+				} else if di.Attempt > 0 {
+					timeoutWorkflow := msBuilder.shouldTimeoutOnDecisionFailure(di.Attempt)
+					if !msBuilder.AddRetriedDecisionScheduleToStartTimeout(scheduleID) {
+						return &workflow.InternalServiceError{Message: "Unable to time out retried decision."}
+					}
 
-				// reschedule decision, which will be on its original task list
-				scheduleNewDecision = true
+					if timeoutWorkflow {
+						// the fallback used up the attempts of the decision, time out the workflow instead of retrying
+						tBuilder := t.historyService.getTimerBuilder(&context.workflowExecution)
+						timerTasks = append(timerTasks, tBuilder.AddWorkflowTimeoutNowTask())
+					} else {
+						// retry the decision, which stays on the normal task list
+						scheduleNewDecision = true
+						isStickyFallback = true
+					}
+				}
 			}
 		}
 
-		if scheduleNewDecision {
+		if scheduleNewDecision || len(timerTasks) > 0 {
 			// We apply the update to execution using optimistic concurrency.  If it fails due to a conflict than reload
 			// the history and try the operation again.
-			err := t.updateWorkflowExecution(context, msBuilder, scheduleNewDecision, false, nil, nil)
+			err := t.updateWorkflowExecution(context, msBuilder, scheduleNewDecision, isStickyFallback, false,
+				timerTasks, nil)
 			if err != nil {
 				if err == ErrConflict {
 					continue Update_History_Loop
@@ -490,7 +551,82 @@ Update_History_Loop:
 		return nil
 
 	}
-	return ErrMaxAttemptsExceeded
+	return context.newMaxAttemptsExceededError()
+}
+
+func (t *timerQueueActiveProcessorImpl) processDecisionBackoff(task *persistence.TimerTaskInfo) (retError error) {
+	t.metricsClient.IncCounter(metrics.TimerTaskDecisionBackoffScope, metrics.TaskRequests)
+	sw := t.metricsClient.StartTimer(metrics.TimerTaskDecisionBackoffScope, metrics.TaskLatency)
+	defer sw.Stop()
+
+	context, release, err0 := t.cache.getOrCreateWorkflowExecution(t.timerQueueProcessorBase.getDomainIDAndWorkflowExecution(task))
+	if err0 != nil {
+		return err0
+	}
+	defer func() { release(retError) }()
+
+Update_History_Loop:
+	for attempt := 0; attempt < conditionalRetryCount; attempt++ {
+		msBuilder, err1 := context.loadWorkflowExecution()
+		if err1 != nil {
+			return err1
+		}
+
+		scheduleID := task.EventID
+		di, isPending := msBuilder.GetPendingDecision(scheduleID)
+
+		// First check to see if cache needs to be refreshed as we could potentially have stale workflow execution in
+		// some extreme cassandra failure cases.
+		if !isPending && scheduleID >= msBuilder.GetNextEventID() {
+			t.metricsClient.IncCounter(metrics.TimerQueueProcessorScope, metrics.StaleMutableStateCounter)
+			// Reload workflow execution history
+			context.clear()
+			continue Update_History_Loop
+		}
+
+		// decision was already dispatched or superseded by another attempt
+		if !isPending || di.Attempt != task.ScheduleAttempt || di.StartedID != emptyEventID ||
+			!msBuilder.isWorkflowExecutionRunning() {
+			return nil
+		}
+
+		transferTasks := []persistence.Task{&persistence.DecisionTask{
+			DomainID:   msBuilder.executionInfo.DomainID,
+			TaskList:   di.Tasklist,
+			ScheduleID: di.ScheduleID,
+		}}
+		var timerTasks []persistence.Task
+		if msBuilder.isStickyTaskListEnabled() {
+			tBuilder := t.historyService.getTimerBuilder(&context.workflowExecution)
+			stickyTaskTimeoutTimer := tBuilder.AddScheduleToStartDecisionTimoutTask(di.ScheduleID, di.Attempt,
+				msBuilder.executionInfo.StickyScheduleToStartTimeout)
+			timerTasks = append(timerTasks, stickyTaskTimeoutTimer)
+		}
+
+		// Generate a transaction ID for appending events to history
+		transactionID, err2 := t.historyService.shard.GetNextTransferTaskID()
+		if err2 != nil {
+			return err2
+		}
+
+		// We apply the update to execution using optimistic concurrency.  If it fails due to a conflict than reload
+		// the history and try the operation again.
+		err := context.updateWorkflowExecution(transferTasks, timerTasks, transactionID)
+		if err != nil {
+			if err == ErrConflict {
+				continue Update_History_Loop
+			}
+			if isShardOwnershiptLostError(err) {
+				// Shard is stolen.  Stop timer processing to reduce duplicates
+				t.timerQueueProcessorBase.Stop()
+			}
+			return err
+		}
+
+		t.notifyNewTimers(timerTasks)
+		return nil
+	}
+	return context.newMaxAttemptsExceededError()
 }
 
 func (t *timerQueueActiveProcessorImpl) processWorkflowTimeout(task *persistence.TimerTaskInfo) (retError error) {
@@ -515,6 +651,11 @@ Update_History_Loop:
 			return nil
 		}
 
+		if err := emitDiscardedBufferedEvents(t.shard, t.metricsClient, t.logger,
+			metrics.TimerTaskWorkflowTimeoutScope, msBuilder); err != nil {
+			return err
+		}
+
 		if e := msBuilder.AddTimeoutWorkflowEvent(); e == nil {
 			// If we failed to add the event that means the workflow is already completed.
 			// we drop this timeout event.
@@ -523,27 +664,34 @@ Update_History_Loop:
 
 		// We apply the update to execution using optimistic concurrency.  If it fails due to a conflict than reload
 		// the history and try the operation again.
-		err := t.updateWorkflowExecution(context, msBuilder, false, true, nil, nil)
+		err := t.updateWorkflowExecution(context, msBuilder, false, false, true, nil, nil)
 		if err != nil {
 			if err == ErrConflict {
 				continue Update_History_Loop
 			}
+			return err
 		}
-		return err
+		emitWorkflowClosed(t.shard, t.metricsClient, metrics.TimerTaskWorkflowTimeoutScope,
+			msBuilder.executionInfo.DomainID, persistence.WorkflowCloseStatusTimedOut)
+		t.metricsClient.Tagged(map[string]string{
+			metrics.DomainTagName: getMetricsDomainName(t.shard, msBuilder.executionInfo.DomainID),
+		}).IncCounter(metrics.TimerTaskWorkflowTimeoutScope, metrics.WorkflowTimeoutCounter)
+		return nil
 	}
-	return ErrMaxAttemptsExceeded
+	return context.newMaxAttemptsExceededError()
 }
 
 func (t *timerQueueActiveProcessorImpl) updateWorkflowExecution(
 	context *workflowExecutionContext,
 	msBuilder *mutableStateBuilder,
 	scheduleNewDecision bool,
+	isStickyFallback bool,
 	createDeletionTask bool,
 	timerTasks []persistence.Task,
 	clearTimerTask persistence.Task,
 ) error {
 	var transferTasks []persistence.Task
-	if scheduleNewDecision {
+	if scheduleNewDecision && !msBuilder.isPaused() {
 		// Schedule a new decision.
 		di := msBuilder.AddDecisionTaskScheduledEvent()
 		transferTasks = []persistence.Task{&persistence.DecisionTask{
@@ -556,14 +704,19 @@ func (t *timerQueueActiveProcessorImpl) updateWorkflowExecution(
 			stickyTaskTimeoutTimer := tBuilder.AddScheduleToStartDecisionTimoutTask(di.ScheduleID, di.Attempt,
 				msBuilder.executionInfo.StickyScheduleToStartTimeout)
 			timerTasks = append(timerTasks, stickyTaskTimeoutTimer)
+		} else if isStickyFallback {
+			if normalTimer := t.getNormalScheduleToStartTimer(context, msBuilder, di); normalTimer != nil {
+				timerTasks = append(timerTasks, normalTimer)
+			}
 		}
 	}
 
 	if createDeletionTask {
 		tBuilder := t.historyService.getTimerBuilder(&context.workflowExecution)
-		tranT, timerT, err := t.historyService.getDeleteWorkflowTasks(msBuilder.executionInfo.DomainID, tBuilder)
+		tranT, timerT, err := t.historyService.getDeleteWorkflowTasks(msBuilder.executionInfo.DomainID,
+			msBuilder.executionInfo.WorkflowTypeName, tBuilder)
 		if err != nil {
-			return nil
+			return err
 		}
 		transferTasks = append(transferTasks, tranT)
 		timerTasks = append(timerTasks, timerT)
@@ -585,3 +738,28 @@ func (t *timerQueueActiveProcessorImpl) updateWorkflowExecution(
 	t.notifyNewTimers(timerTasks)
 	return err
 }
+
+// getNormalScheduleToStartTimer returns the schedule to start timer of a decision which fell back from sticky dispatch
+// to the normal task list as its sticky worker went away.  Each time the timer fires, the decision is retried as the
+// next transient attempt.  Once the attempts allowed by MaxTransientDecisionAttempts are used up, the workflow is timed
+// out if TimeoutWorkflowOnMaxDecisionAttempts is set, otherwise the last attempt waits for a worker without a timer.
+// There is no timer either when the domain has no normal schedule to start timeout.
+func (t *timerQueueActiveProcessorImpl) getNormalScheduleToStartTimer(context *workflowExecutionContext,
+	msBuilder *mutableStateBuilder, di *decisionInfo) persistence.Task {
+	if di.Attempt == 0 ||
+		(msBuilder.isDecisionAttemptCapReached(di.Attempt) && !msBuilder.shouldTimeoutOnDecisionFailure(di.Attempt)) {
+		return nil
+	}
+
+	domainEntry, err := t.historyService.shard.GetDomainCache().GetDomainByID(msBuilder.executionInfo.DomainID)
+	if err != nil {
+		return nil
+	}
+	timeout := t.historyService.getNormalScheduleToStartTimeout(domainEntry.GetInfo().Name)
+	if timeout <= 0 {
+		return nil
+	}
+
+	tBuilder := t.historyService.getTimerBuilder(&context.workflowExecution)
+	return tBuilder.AddScheduleToStartDecisionTimoutTask(di.ScheduleID, di.Attempt, timeout)
+}
@@ -22,11 +22,13 @@ package history
 
 import (
 	"fmt"
+	"strconv"
 	"sync/atomic"
 	"time"
 
 	"github.com/uber-common/bark"
 	"github.com/uber/cadence/common/logging"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
 )
 
@@ -147,6 +149,68 @@ func (t *timerQueueProcessorImpl) getTimerFiredCount(clusterName string) uint64
 	return standbyTimerProcessor.getTimerFiredCount()
 }
 
+func (t *timerQueueProcessorImpl) getOldestPendingTimer(clusterName string) (time.Time, bool) {
+	if clusterName == t.currentClusterName {
+		return t.activeTimerProcessor.timerQueueProcessorBase.getOldestPendingTimer()
+	}
+
+	standbyTimerProcessor, ok := t.standbyTimerProcessors[clusterName]
+	if !ok {
+		return time.Time{}, false
+	}
+	return standbyTimerProcessor.timerQueueProcessorBase.getOldestPendingTimer()
+}
+
+// getTimerLag returns for how long the oldest loaded timer of the cluster has been due without being processed
+func (t *timerQueueProcessorImpl) getTimerLag(clusterName string) time.Duration {
+	oldest, ok := t.getOldestPendingTimer(clusterName)
+	if !ok {
+		return 0
+	}
+	if delay := t.shard.GetCurrentTime(clusterName).Sub(oldest); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// emitQueueLag reports the lag of the running timer processors as gauges tagged with the shard and cluster
+func (t *timerQueueProcessorImpl) emitQueueLag() {
+	clusterNames := []string{t.currentClusterName}
+	if t.isGlobalDomainEnabled {
+		for clusterName := range t.standbyTimerProcessors {
+			clusterNames = append(clusterNames, clusterName)
+		}
+	}
+
+	for _, clusterName := range clusterNames {
+		t.shard.GetMetricsClient().Tagged(map[string]string{
+			metrics.ShardTagName:   strconv.Itoa(t.shard.GetShardID()),
+			metrics.ClusterTagName: clusterName,
+		}).UpdateGauge(metrics.TimerQueueProcessorScope, metrics.TimerLagGauge, t.getTimerLag(clusterName).Seconds())
+	}
+}
+
+// pause stops firing timers of the shard, for the active as well as the standby clusters, and returns the time at
+// which timer processing resumes on its own
+func (t *timerQueueProcessorImpl) pause(duration time.Duration) time.Time {
+	pausedUntil := t.activeTimerProcessor.timerQueueProcessorBase.pause(duration)
+	for _, standbyTimerProcessor := range t.standbyTimerProcessors {
+		standbyTimerProcessor.timerQueueProcessorBase.pause(duration)
+	}
+	return pausedUntil
+}
+
+func (t *timerQueueProcessorImpl) resume() {
+	t.activeTimerProcessor.timerQueueProcessorBase.resume()
+	for _, standbyTimerProcessor := range t.standbyTimerProcessors {
+		standbyTimerProcessor.timerQueueProcessorBase.resume()
+	}
+}
+
+func (t *timerQueueProcessorImpl) getPausedUntil() (time.Time, bool) {
+	return t.activeTimerProcessor.timerQueueProcessorBase.getPausedUntil()
+}
+
 func (t *timerQueueProcessorImpl) completeTimersLoop() {
 	timer := time.NewTimer(t.config.TimerProcessorCompleteTimerInterval)
 	defer timer.Stop()
@@ -168,6 +232,7 @@ func (t *timerQueueProcessorImpl) completeTimersLoop() {
 					break CompleteLoop
 				}
 			}
+			t.emitQueueLag()
 			timer.Reset(t.config.TimerProcessorCompleteTimerInterval)
 		}
 	}
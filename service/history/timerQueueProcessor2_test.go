@@ -96,7 +96,7 @@ func (s *timerQueueProcessor2Suite) SetupTest() {
 	s.mockClusterMetadata = &mocks.ClusterMetadata{}
 	// ack manager will use the domain information
 	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
-		// only thing used is the replication config
+		Info:   &persistence.DomainInfo{ID: testDomainActiveID, Name: testDomainActiveName},
 		Config: &persistence.DomainConfig{Retention: 1},
 		ReplicationConfig: &persistence.DomainReplicationConfig{
 			ActiveClusterName: cluster.TestCurrentClusterName,
@@ -289,3 +289,205 @@ func (s *timerQueueProcessor2Suite) TestWorkflowTimeout() {
 	<-waitCh
 	s.mockHistoryEngine.timerProcessor.(*timerQueueProcessorImpl).activeTimerProcessor.Stop()
 }
+
+func (s *timerQueueProcessor2Suite) TestPauseResumeTimerProcessing() {
+	timerProcessor := s.mockHistoryEngine.timerProcessor
+
+	_, paused := timerProcessor.getPausedUntil()
+	s.False(paused)
+
+	before := time.Now()
+	pausedUntil := timerProcessor.pause(time.Minute)
+	s.False(pausedUntil.Before(before.Add(time.Minute)))
+	actualPausedUntil, paused := timerProcessor.getPausedUntil()
+	s.True(paused)
+	s.Equal(pausedUntil, actualPausedUntil)
+
+	// the pause is capped by the max pause duration
+	pausedUntil = timerProcessor.pause(0)
+	s.False(pausedUntil.After(time.Now().Add(s.config.TimerProcessorMaxPauseDuration)))
+	s.True(pausedUntil.After(before.Add(time.Minute)))
+
+	timerProcessor.resume()
+	_, paused = timerProcessor.getPausedUntil()
+	s.False(paused)
+	processorBase := timerProcessor.(*timerQueueProcessorImpl).activeTimerProcessor.timerQueueProcessorBase
+	s.Equal(int32(1), processorBase.catchingUp)
+	s.Equal(1, len(processorBase.resumeCh))
+
+	// a resume nobody waited for does not end the next pause
+	timerProcessor.pause(time.Minute)
+	s.Equal(0, len(processorBase.resumeCh))
+	_, paused = timerProcessor.getPausedUntil()
+	s.True(paused)
+	timerProcessor.resume()
+}
+
+func (s *timerQueueProcessor2Suite) TestDecisionScheduleToStartTimeout_FallbackToNormalTaskList() {
+	normalTimeout := s.config.NormalDecisionScheduleToStartTimeoutInSecs
+	defer func() { s.config.NormalDecisionScheduleToStartTimeoutInSecs = normalTimeout }()
+	s.config.NormalDecisionScheduleToStartTimeoutInSecs = func(...dynamicconfig.FilterOption) int { return 30 }
+
+	domainID := testDomainActiveID
+	we := workflow.WorkflowExecution{WorkflowId: common.StringPtr("decision-falls-back-to-normal-test"),
+		RunId: common.StringPtr(validRunID)}
+	taskList := "decision-falls-back-to-normal"
+
+	builder := newMutableStateBuilder(s.config, s.logger)
+	startRequest := &workflow.StartWorkflowExecutionRequest{
+		WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("wType")},
+		TaskList:                            common.TaskListPtr(workflow.TaskList{Name: common.StringPtr(taskList)}),
+		ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(200),
+		TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(10),
+	}
+	builder.AddWorkflowExecutionStartedEvent(we, &history.StartWorkflowExecutionRequest{
+		DomainUUID:   common.StringPtr(domainID),
+		StartRequest: startRequest,
+	})
+	builder.executionInfo.StickyTaskList = "sticky-" + taskList
+	builder.executionInfo.StickyScheduleToStartTimeout = 5
+	di := addDecisionTaskScheduledEvent(builder)
+
+	waitCh := make(chan struct{})
+
+	mockTS := &mockTimeSource{currTime: time.Now()}
+
+	// the sticky worker did not pick up the decision in time
+	timerTask := &persistence.TimerTaskInfo{
+		DomainID:            domainID,
+		WorkflowID:          "wid",
+		RunID:               validRunID,
+		TaskID:              int64(100),
+		TaskType:            persistence.TaskTypeDecisionTimeout,
+		TimeoutType:         int(workflow.TimeoutTypeScheduleToStart),
+		VisibilityTimestamp: mockTS.Now(),
+		EventID:             di.ScheduleID,
+		ScheduleAttempt:     di.Attempt}
+	timerIndexResponse := &persistence.GetTimerIndexTasksResponse{Timers: []*persistence.TimerTaskInfo{timerTask}}
+
+	s.mockExecutionMgr.On("GetTimerIndexTasks", mock.Anything).Return(timerIndexResponse, nil).Once()
+	s.mockExecutionMgr.On("GetTimerIndexTasks", mock.Anything).Return(
+		&persistence.GetTimerIndexTasksResponse{Timers: []*persistence.TimerTaskInfo{}}, nil)
+
+	ms := createMutableState(builder)
+	wfResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(wfResponse, nil).Once()
+
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
+		updateRequest = arguments.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+		// Done.
+		waitCh <- struct{}{}
+	}).Once()
+
+	dispatchTime := time.Now()
+	// Start timer Processor.
+	s.mockHistoryEngine.timerProcessor.(*timerQueueProcessorImpl).activeTimerProcessor.Start()
+
+	s.mockHistoryEngine.timerProcessor.NotifyNewTimers(cluster.TestCurrentClusterName, []persistence.Task{&persistence.DecisionTimeoutTask{
+		VisibilityTimestamp: timerTask.VisibilityTimestamp,
+		EventID:             timerTask.EventID,
+		TimeoutType:         timerTask.TimeoutType,
+	}})
+
+	<-waitCh
+	s.mockHistoryEngine.timerProcessor.(*timerQueueProcessorImpl).activeTimerProcessor.Stop()
+
+	// the decision is retried on the normal task list, bounded by the normal schedule to start timeout
+	s.Equal("", updateRequest.ExecutionInfo.StickyTaskList)
+	s.Equal(int64(1), updateRequest.ExecutionInfo.DecisionAttempt)
+	s.Equal(1, len(updateRequest.TimerTasks))
+	normalTimer, ok := updateRequest.TimerTasks[0].(*persistence.DecisionTimeoutTask)
+	s.True(ok)
+	s.Equal(int(workflow.TimeoutTypeScheduleToStart), normalTimer.TimeoutType)
+	s.Equal(updateRequest.ExecutionInfo.DecisionScheduleID, normalTimer.EventID)
+	s.Equal(int64(1), normalTimer.ScheduleAttempt)
+	s.False(normalTimer.VisibilityTimestamp.Before(dispatchTime.Add(30 * time.Second)))
+	s.True(normalTimer.VisibilityTimestamp.Before(time.Now().Add(31 * time.Second)))
+}
+
+func (s *timerQueueProcessor2Suite) TestDecisionScheduleToStartTimeout_FallbackUsesUpAttempts() {
+	normalTimeout := s.config.NormalDecisionScheduleToStartTimeoutInSecs
+	maxAttempts := s.config.MaxTransientDecisionAttempts
+	timeoutWorkflow := s.config.TimeoutWorkflowOnMaxDecisionAttempts
+	defer func() {
+		s.config.NormalDecisionScheduleToStartTimeoutInSecs = normalTimeout
+		s.config.MaxTransientDecisionAttempts = maxAttempts
+		s.config.TimeoutWorkflowOnMaxDecisionAttempts = timeoutWorkflow
+	}()
+	s.config.NormalDecisionScheduleToStartTimeoutInSecs = func(...dynamicconfig.FilterOption) int { return 30 }
+	s.config.MaxTransientDecisionAttempts = 2
+	s.config.TimeoutWorkflowOnMaxDecisionAttempts = true
+
+	domainID := testDomainActiveID
+	we := workflow.WorkflowExecution{WorkflowId: common.StringPtr("decision-fallback-uses-up-attempts-test"),
+		RunId: common.StringPtr(validRunID)}
+	taskList := "decision-fallback-uses-up-attempts"
+
+	builder := newMutableStateBuilder(s.config, s.logger)
+	startRequest := &workflow.StartWorkflowExecutionRequest{
+		WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("wType")},
+		TaskList:                            common.TaskListPtr(workflow.TaskList{Name: common.StringPtr(taskList)}),
+		ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(200),
+		TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(10),
+	}
+	builder.AddWorkflowExecutionStartedEvent(we, &history.StartWorkflowExecutionRequest{
+		DomainUUID:   common.StringPtr(domainID),
+		StartRequest: startRequest,
+	})
+	// the decision already fell back from its sticky worker to the normal task list once
+	builder.executionInfo.DecisionAttempt = 1
+	di := addDecisionTaskScheduledEvent(builder)
+
+	waitCh := make(chan struct{})
+
+	mockTS := &mockTimeSource{currTime: time.Now()}
+
+	// no worker picked up the decision from the normal task list in time either
+	timerTask := &persistence.TimerTaskInfo{
+		DomainID:            domainID,
+		WorkflowID:          "wid",
+		RunID:               validRunID,
+		TaskID:              int64(100),
+		TaskType:            persistence.TaskTypeDecisionTimeout,
+		TimeoutType:         int(workflow.TimeoutTypeScheduleToStart),
+		VisibilityTimestamp: mockTS.Now(),
+		EventID:             di.ScheduleID,
+		ScheduleAttempt:     di.Attempt}
+	timerIndexResponse := &persistence.GetTimerIndexTasksResponse{Timers: []*persistence.TimerTaskInfo{timerTask}}
+
+	s.mockExecutionMgr.On("GetTimerIndexTasks", mock.Anything).Return(timerIndexResponse, nil).Once()
+	s.mockExecutionMgr.On("GetTimerIndexTasks", mock.Anything).Return(
+		&persistence.GetTimerIndexTasksResponse{Timers: []*persistence.TimerTaskInfo{}}, nil)
+
+	ms := createMutableState(builder)
+	wfResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(wfResponse, nil).Once()
+
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
+		updateRequest = arguments.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+		// Done.
+		waitCh <- struct{}{}
+	}).Once()
+
+	// Start timer Processor.
+	s.mockHistoryEngine.timerProcessor.(*timerQueueProcessorImpl).activeTimerProcessor.Start()
+
+	s.mockHistoryEngine.timerProcessor.NotifyNewTimers(cluster.TestCurrentClusterName, []persistence.Task{&persistence.DecisionTimeoutTask{
+		VisibilityTimestamp: timerTask.VisibilityTimestamp,
+		EventID:             timerTask.EventID,
+		TimeoutType:         timerTask.TimeoutType,
+	}})
+
+	<-waitCh
+	s.mockHistoryEngine.timerProcessor.(*timerQueueProcessorImpl).activeTimerProcessor.Stop()
+
+	// the decision is not retried again, the workflow is timed out instead
+	s.Equal(emptyEventID, updateRequest.ExecutionInfo.DecisionScheduleID)
+	s.Empty(updateRequest.TransferTasks)
+	s.Equal(1, len(updateRequest.TimerTasks))
+	_, ok := updateRequest.TimerTasks[0].(*persistence.WorkflowTimeoutTask)
+	s.True(ok)
+}
@@ -40,6 +40,8 @@ var (
 	errTimerTaskNotFound          = errors.New("Timer task not found")
 	errFailedToAddTimeoutEvent    = errors.New("Failed to add timeout event")
 	errFailedToAddTimerFiredEvent = errors.New("Failed to add timer fired event")
+	errTimerProcessingPaused      = errors.New("Timer processing paused")
+	errTimerProcessorShutdown     = errors.New("Timer processor is shutting down")
 	emptyTime                     = time.Time{}
 	maxTimestamp                  = time.Unix(0, math.MaxInt64)
 )
@@ -61,6 +63,8 @@ type (
 		timerFiredCount  uint64
 		timerProcessor   timerProcessor
 		timerQueueAckMgr timerQueueAckMgr
+		// domainIsolation holds each domain to its share of the task workers
+		domainIsolation *domainIsolationGroup
 
 		// worker coroutines notification
 		workerNotificationChans []chan struct{}
@@ -69,6 +73,13 @@ type (
 		newTimerCh  chan struct{}
 		newTimeLock sync.Mutex
 		newTime     time.Time
+
+		// pausing, a zero pausedUntil means timer processing is not paused
+		pauseLock   sync.Mutex
+		pausedUntil time.Time
+		resumeCh    chan struct{}
+		// catchingUp is set on resume, while it is set overdue timers are fanned out one batch at a time
+		catchingUp int32
 	}
 )
 
@@ -95,6 +106,9 @@ func newTimerQueueProcessorBase(shard ShardContext, historyService *historyEngin
 		timerQueueAckMgr:        timerQueueAckMgr,
 		workerNotificationChans: workerNotificationChans,
 		newTimerCh:              make(chan struct{}, 1),
+		resumeCh:                make(chan struct{}, 1),
+		domainIsolation: newDomainIsolationGroup(shard, shard.GetConfig().TimerProcessorTaskWorkerCount,
+			metrics.TimerQueueProcessorScope),
 	}
 
 	return base
@@ -168,31 +182,50 @@ func (t *timerQueueProcessorBase) processTaskWorker(workerWG *sync.WaitGroup, no
 				return
 			}
 
-		UpdateFailureLoop:
-			for attempt := 1; attempt <= t.config.TimerProcessorUpdateFailureRetryCount; {
+			t.processWithDomainIsolation(notificationChan, task)
+		}
+	}
+}
 
-				// clear the existing notification
-				select {
-				case <-notificationChan:
-				default:
-				}
+// processWithDomainIsolation processes the timer once its domain has a worker slot.  A timer of a domain which already
+// uses its share of the workers is parked, so that this worker moves on to other timers, and is picked up by the worker
+// of the next timer of the domain to complete.
+func (t *timerQueueProcessorBase) processWithDomainIsolation(notificationChan chan struct{},
+	task *persistence.TimerTaskInfo) {
+	releaseDomainSlot, ok := t.domainIsolation.acquireOrPark(task.DomainID, task)
+	if !ok {
+		return
+	}
+	for task != nil {
+		t.processWithRetry(notificationChan, task)
+		task, _ = releaseDomainSlot().(*persistence.TimerTaskInfo)
+	}
+}
 
-				err := t.timerProcessor.process(task)
-				if err != nil {
-					if err == ErrTaskRetry {
-						<-notificationChan
-					} else {
-						// We will retry until we don't find the timer task any more.
-						t.logger.Infof("Failed to process timer: %v; %v.", task, err)
-						backoff := time.Duration(attempt * 100)
-						time.Sleep(backoff * time.Millisecond)
-						attempt++
-					}
-				} else {
-					atomic.AddUint64(&t.timerFiredCount, 1)
-					break UpdateFailureLoop
-				}
+func (t *timerQueueProcessorBase) processWithRetry(notificationChan chan struct{}, task *persistence.TimerTaskInfo) {
+UpdateFailureLoop:
+	for attempt := 1; attempt <= t.config.TimerProcessorUpdateFailureRetryCount; {
+
+		// clear the existing notification
+		select {
+		case <-notificationChan:
+		default:
+		}
+
+		err := t.timerProcessor.process(task)
+		if err != nil {
+			if err == ErrTaskRetry {
+				<-notificationChan
+			} else {
+				// We will retry until we don't find the timer task any more.
+				t.logger.Infof("Failed to process timer: %v; %v.", task, err)
+				backoff := time.Duration(attempt * 100)
+				time.Sleep(backoff * time.Millisecond)
+				attempt++
 			}
+		} else {
+			atomic.AddUint64(&t.timerFiredCount, 1)
+			break UpdateFailureLoop
 		}
 	}
 }
@@ -224,6 +257,8 @@ func (t *timerQueueProcessorBase) notifyNewTimers(timerTasks []persistence.Task,
 		case persistence.TaskTypeDeleteHistoryEvent:
 			t.metricsClient.IncCounter(metrics.TimerTaskDeleteHistoryEvent, counterType)
 			// TODO add default
+		case persistence.TaskTypeDecisionBackoff:
+			t.metricsClient.IncCounter(metrics.TimerTaskDecisionBackoffScope, counterType)
 		}
 	}
 
@@ -277,6 +312,9 @@ continueProcessor:
 				// forced timer scan
 				pollTimer.Reset(t.config.TimerProcessorMaxPollInterval)
 
+			case <-t.resumeCh:
+				// timer processing resumed, read the timers which were held back
+
 			case <-updateAckChan:
 				t.timerQueueAckMgr.updateAckLevel()
 				continue continueProcessor
@@ -304,10 +342,25 @@ continueProcessor:
 			}
 		}
 
-		var err error
-		nextKeyTask, err = t.readAndFanoutTimerTasks()
-		if err != nil {
-			return err
+		for {
+			if t.isPaused() {
+				if !t.waitWhilePaused(updateAckChan) {
+					return nil
+				}
+			}
+
+			var err error
+			nextKeyTask, err = t.readAndFanoutTimerTasks()
+			if err == errTimerProcessorShutdown {
+				return nil
+			}
+			if err != errTimerProcessingPaused {
+				if err != nil {
+					return err
+				}
+				break
+			}
+			// processing was paused in the middle of a batch, the rest of it is read once processing resumes
 		}
 
 		if nextKeyTask != nil {
@@ -333,8 +386,102 @@ func (t *timerQueueProcessorBase) readAndFanoutTimerTasks() (*persistence.TimerT
 		}
 
 		if !moreTasks {
+			atomic.StoreInt32(&t.catchingUp, 0)
 			return lookAheadTask, nil
 		}
+
+		if atomic.LoadInt32(&t.catchingUp) == 1 {
+			// spread the timers which became due while processing was paused instead of firing them all at once
+			select {
+			case <-t.shutdownCh:
+				return nil, errTimerProcessorShutdown
+			case <-time.After(t.config.TimerProcessorResumeBatchInterval):
+			}
+		}
+		if t.isPaused() {
+			return nil, errTimerProcessingPaused
+		}
+	}
+}
+
+// pause stops handing timers to the task workers until resume is called or the duration elapses, timers which are
+// already handed out are still processed.  The duration is capped by TimerProcessorMaxPauseDuration, the time at
+// which processing resumes on its own is returned.
+func (t *timerQueueProcessorBase) pause(duration time.Duration) time.Time {
+	if duration <= 0 || duration > t.config.TimerProcessorMaxPauseDuration {
+		duration = t.config.TimerProcessorMaxPauseDuration
+	}
+
+	t.pauseLock.Lock()
+	defer t.pauseLock.Unlock()
+	// drop the token of an earlier resume which nobody waited for, otherwise this pause would end right away
+	select {
+	case <-t.resumeCh:
+	default:
+	}
+	t.pausedUntil = time.Now().Add(duration)
+	t.logger.Infof("Timer processing paused until %v.", t.pausedUntil)
+	return t.pausedUntil
+}
+
+func (t *timerQueueProcessorBase) resume() {
+	t.pauseLock.Lock()
+	wasPaused := !t.pausedUntil.IsZero()
+	t.pausedUntil = time.Time{}
+	t.pauseLock.Unlock()
+
+	if !wasPaused {
+		return
+	}
+	if !t.config.TimerProcessorFireOverdueTimersOnResume {
+		atomic.StoreInt32(&t.catchingUp, 1)
+	}
+	select {
+	case t.resumeCh <- struct{}{}:
+	default:
+	}
+	t.logger.Info("Timer processing resumed.")
+}
+
+// getPausedUntil returns the time at which paused timer processing resumes on its own, and false when timer
+// processing is not paused
+func (t *timerQueueProcessorBase) getPausedUntil() (time.Time, bool) {
+	t.pauseLock.Lock()
+	defer t.pauseLock.Unlock()
+	if t.pausedUntil.IsZero() {
+		return time.Time{}, false
+	}
+	return t.pausedUntil, true
+}
+
+func (t *timerQueueProcessorBase) isPaused() bool {
+	_, paused := t.getPausedUntil()
+	return paused
+}
+
+// waitWhilePaused blocks until timer processing is resumed, either explicitly or once the pause expires, the ack
+// level keeps being updated meanwhile.  It returns false if the processor is shut down while paused.
+func (t *timerQueueProcessorBase) waitWhilePaused(updateAckChan <-chan time.Time) bool {
+	for {
+		pausedUntil, paused := t.getPausedUntil()
+		if !paused {
+			return true
+		}
+
+		autoResumeTimer := time.NewTimer(pausedUntil.Sub(time.Now()))
+		select {
+		case <-t.shutdownCh:
+			autoResumeTimer.Stop()
+			return false
+		case <-t.resumeCh:
+		case <-autoResumeTimer.C:
+			t.logger.Warn("Timer processing was paused for too long, resuming.")
+			t.metricsClient.IncCounter(metrics.TimerQueueProcessorScope, metrics.TimerProcessingAutoResumedCounter)
+			t.resume()
+		case <-updateAckChan:
+			t.timerQueueAckMgr.updateAckLevel()
+		}
+		autoResumeTimer.Stop()
 	}
 }
 
@@ -351,6 +498,10 @@ func (t *timerQueueProcessorBase) getTimerFiredCount() uint64 {
 	return atomic.LoadUint64(&t.timerFiredCount)
 }
 
+func (t *timerQueueProcessorBase) getOldestPendingTimer() (time.Time, bool) {
+	return t.timerQueueAckMgr.getOldestPendingTimer()
+}
+
 func (t *timerQueueProcessorBase) getDomainIDAndWorkflowExecution(task *persistence.TimerTaskInfo) (string, workflow.WorkflowExecution) {
 	return task.DomainID, workflow.WorkflowExecution{
 		WorkflowId: common.StringPtr(task.WorkflowID),
@@ -363,12 +514,26 @@ func (t *timerQueueProcessorBase) processDeleteHistoryEvent(task *persistence.Ti
 	sw := t.metricsClient.StartTimer(metrics.TimerTaskDeleteHistoryEvent, metrics.TaskLatency)
 	defer sw.Stop()
 
+	domainID, workflowExecution := t.getDomainIDAndWorkflowExecution(task)
 	op := func() error {
-		return t.executionManager.DeleteWorkflowExecution(&persistence.DeleteWorkflowExecutionRequest{
+		// the execution is already gone when this task, or the delete below, is retried, it must only be taken off
+		// the closed execution count of the shard once
+		_, err := t.shard.GetExecutionManager().GetWorkflowExecution(&persistence.GetWorkflowExecutionRequest{
+			DomainID:  domainID,
+			Execution: workflowExecution,
+		})
+		if err != nil {
+			if _, ok := err.(*workflow.EntityNotExistsError); ok {
+				return nil
+			}
+			return err
+		}
+
+		return t.shard.DeleteWorkflowExecution(&persistence.DeleteWorkflowExecutionRequest{
 			DomainID:   task.DomainID,
 			WorkflowID: task.WorkflowID,
 			RunID:      task.RunID,
-		})
+		}, false)
 	}
 
 	err := backoff.Retry(op, persistenceOperationRetryPolicy, common.IsPersistenceTransientError)
@@ -376,7 +541,6 @@ func (t *timerQueueProcessorBase) processDeleteHistoryEvent(task *persistence.Ti
 		return err
 	}
 
-	domainID, workflowExecution := t.getDomainIDAndWorkflowExecution(task)
 	op = func() error {
 		return t.historyService.historyMgr.DeleteWorkflowExecutionHistory(
 			&persistence.DeleteWorkflowExecutionHistoryRequest{
@@ -400,6 +564,8 @@ func (t *timerQueueProcessorBase) getTimerTaskType(taskType int) string {
 		return "WorkflowTimeout"
 	case persistence.TaskTypeDeleteHistoryEvent:
 		return "DeleteHistoryEvent"
+	case persistence.TaskTypeDecisionBackoff:
+		return "DecisionBackoff"
 	}
 	return "UnKnown"
 }
@@ -891,6 +891,50 @@ func (s *timerQueueProcessorSuite) TestTimerUserTimers_SameExpiry() {
 	s.Equal(0, len(builder.pendingTimerInfoIDs))
 }
 
+func (s *timerQueueProcessorSuite) TestTimerUserTimers_MaxTimersPerUpdate() {
+	domainID := testDomainActiveID
+	workflowExecution := workflow.WorkflowExecution{WorkflowId: common.StringPtr("user-timer-max-per-update-test"),
+		RunId: common.StringPtr(validRunID)}
+
+	taskList := "user-timer-max-per-update-queue"
+	s.createExecutionWithTimers(domainID, workflowExecution, taskList, "identity", []int32{})
+
+	config := s.ShardContext.GetConfig()
+	maxTimersPerUpdate := config.TimerProcessorMaxUserTimersPerUpdate
+	defer func() { config.TimerProcessorMaxUserTimersPerUpdate = maxTimersPerUpdate }()
+	config.TimerProcessorMaxUserTimersPerUpdate = 1
+
+	// Three timers, fired one per update.
+	p := newTimerQueueProcessor(s.ShardContext, s.engineImpl, s.logger).(*timerQueueProcessorImpl)
+	p.Start()
+
+	state, err := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+	s.Nil(err)
+	builder := newMutableStateBuilder(s.ShardContext.GetConfig(), s.logger)
+	builder.Load(state)
+	condition := state.ExecutionInfo.NextEventID
+
+	tBuilder := newTimerBuilder(s.ShardContext.GetConfig(), s.logger, &mockTimeSource{currTime: time.Now().Add(-1 * time.Second)})
+	timerIDs := []string{"tid1", "tid2", "tid3"}
+	for _, timerID := range timerIDs {
+		_, ti := builder.AddTimerStartedEvent(emptyEventID,
+			&workflow.StartTimerDecisionAttributes{TimerId: common.StringPtr(timerID), StartToFireTimeoutSeconds: common.Int64Ptr(1)})
+		tBuilder.AddUserTimer(ti, builder)
+	}
+	timerTasks := []persistence.Task{tBuilder.GetUserTimerTaskIfNeeded(builder)}
+
+	s.updateHistoryAndTimers(builder, timerTasks, condition)
+	p.NotifyNewTimers(cluster.TestCurrentClusterName, timerTasks)
+
+	s.waitForTimerTasksToProcess(p)
+	// the first timer task fires one timer and each of the others is fired by a follow up task
+	s.Equal(uint64(len(timerIDs)), p.getTimerFiredCount(cluster.TestCurrentClusterName))
+	for _, timerID := range timerIDs {
+		running := s.checkTimedOutEventForUserTimer(domainID, workflowExecution, timerID)
+		s.False(running)
+	}
+}
+
 func (s *timerQueueProcessorSuite) TestTimersOnClosedWorkflow() {
 	domainID := testDomainActiveID
 	workflowExecution := workflow.WorkflowExecution{WorkflowId: common.StringPtr("closed-workflow-test-desicion-timer"),
@@ -153,6 +153,10 @@ func (t *timerQueueStandbyProcessorImpl) process(timerTask *persistence.TimerTas
 	case persistence.TaskTypeDeleteHistoryEvent:
 		scope = metrics.TimerTaskDeleteHistoryEvent
 		err = t.timerQueueProcessorBase.processDeleteHistoryEvent(timerTask)
+
+	case persistence.TaskTypeDecisionBackoff:
+		// decision backoff only delays the dispatch of a decision to matching, which is done by the active cluster
+		scope = metrics.TimerTaskDecisionBackoffScope
 	}
 
 	if err != nil {
@@ -321,5 +325,5 @@ Process_Loop:
 
 		return fn(msBuilder)
 	}
-	return ErrMaxAttemptsExceeded
+	return context.newMaxAttemptsExceededError()
 }
@@ -664,3 +664,25 @@ func (s *timerQueueStandbyProcessorSuite) TestProcessWorkflowTimeout_Success() {
 
 	s.Nil(s.timerQueueStandbyProcessor.process(timerTask))
 }
+
+func (s *timerQueueStandbyProcessorSuite) TestReadAndFanoutTimerTasks_PausedMidBatch() {
+	processorBase := s.timerQueueStandbyProcessor.timerQueueProcessorBase
+	processorBase.timerQueueAckMgr = s.mocktimerQueueAckMgr
+
+	timerTask := &persistence.TimerTaskInfo{
+		DomainID:            "some random domain ID",
+		WorkflowID:          "some random workflow ID",
+		RunID:               uuid.New(),
+		TaskID:              int64(100),
+		TaskType:            persistence.TaskTypeUserTimer,
+		VisibilityTimestamp: time.Now(),
+	}
+	s.mocktimerQueueAckMgr.On("readTimerTasks").Return([]*persistence.TimerTaskInfo{timerTask}, nil, true, nil).Once()
+
+	processorBase.pause(time.Minute)
+	defer processorBase.resume()
+	nextKeyTask, err := processorBase.readAndFanoutTimerTasks()
+	s.Equal(errTimerProcessingPaused, err)
+	s.Nil(nextKeyTask)
+	s.Equal(1, len(processorBase.tasksCh))
+}
@@ -39,7 +39,11 @@ import (
 	"github.com/uber/cadence/common/persistence"
 )
 
-const identityHistoryService = "history-service"
+const (
+	identityHistoryService = "history-service"
+	// reason recorded on children terminated by the child policy of their closed parent
+	childPolicyTerminateReason = "parent workflow execution closed"
+)
 
 type (
 	maxReadAckLevel func() int64
@@ -63,10 +67,18 @@ type (
 		*queueProcessorBase
 		queueAckMgr
 	}
+
+	// childPolicyTask is a started child of a closed workflow execution with the child policy to apply to it
+	childPolicyTask struct {
+		domainID  string
+		execution workflow.WorkflowExecution
+		policy    workflow.ChildPolicy
+	}
 )
 
 var (
-	errUnknownTransferTask = errors.New("Unknown transfer task")
+	errUnknownTransferTask  = errors.New("Unknown transfer task")
+	errPendingChildNotFound = &workflow.EntityNotExistsError{Message: "Pending child execution not found."}
 )
 
 func newTransferQueueActiveProcessor(shard ShardContext, historyService *historyEngineImpl, visibilityMgr persistence.VisibilityManager,
@@ -311,6 +323,12 @@ func (t *transferQueueActiveProcessorImpl) processActivityTask(task *persistence
 	}
 
 	if ai, found := msBuilder.GetActivityInfo(task.ScheduleID); found {
+		if ai.StartedID == emptyEventID && ai.DispatchDeadline > 0 &&
+			t.shard.GetTimeSource().Now().After(ai.ScheduledTime.Add(time.Duration(ai.DispatchDeadline)*time.Second)) {
+			// the activity waited too long to be dispatched, time it out instead of handing it to matching
+			t.metricsClient.IncCounter(metrics.TransferTaskActivityScope, metrics.ActivityDispatchDeadlineExceededCounter)
+			return t.recordActivityDispatchDeadlineExceeded(task, context)
+		}
 		timeout = ai.ScheduleToStartTimeout
 	} else {
 		logging.LogDuplicateTransferTaskEvent(t.logger, persistence.TransferTaskTypeActivityTask, task.TaskID, task.ScheduleID)
@@ -327,6 +345,7 @@ func (t *transferQueueActiveProcessorImpl) processActivityTask(task *persistence
 			TaskList:                      taskList,
 			ScheduleId:                    &task.ScheduleID,
 			ScheduleToStartTimeoutSeconds: common.Int32Ptr(timeout),
+			Priority:                      common.Int32Ptr(task.Priority),
 		})
 	}
 
@@ -377,16 +396,21 @@ func (t *transferQueueActiveProcessorImpl) processDecisionTask(task *persistence
 	// release the context lock since we no longer need mutable state builder and
 	// the rest of logic is making RPC call, which takes time.
 	release(nil)
-	err = t.matchingClient.AddDecisionTask(nil, &m.AddDecisionTaskRequest{
-		DomainUUID:                    common.StringPtr(domainID),
-		Execution:                     &execution,
-		TaskList:                      taskList,
-		ScheduleId:                    &task.ScheduleID,
-		ScheduleToStartTimeoutSeconds: common.Int32Ptr(timeout),
-	})
+	// a first decision handed to a poller when the execution was started is not dispatched again, matching would
+	// only persist the duplicate
+	if task.ScheduleID != firstEventID+1 ||
+		!t.historyService.consumeFirstDecisionMatch(domainID, execution, task.ScheduleID) {
+		err = t.matchingClient.AddDecisionTask(nil, &m.AddDecisionTaskRequest{
+			DomainUUID:                    common.StringPtr(domainID),
+			Execution:                     &execution,
+			TaskList:                      taskList,
+			ScheduleId:                    &task.ScheduleID,
+			ScheduleToStartTimeoutSeconds: common.Int32Ptr(timeout),
+		})
 
-	if err != nil {
-		return err
+		if err != nil {
+			return err
+		}
 	}
 
 	if task.ScheduleID == firstEventID+1 {
@@ -445,6 +469,10 @@ func (t *transferQueueActiveProcessorImpl) processCloseExecution(task *persisten
 	workflowCloseTimestamp := msBuilder.getLastUpdatedTimestamp()
 	workflowCloseStatus := getWorkflowExecutionCloseStatus(msBuilder.executionInfo.CloseStatus)
 	workflowHistoryLength := msBuilder.GetNextEventID()
+	childPolicyTasks, err := t.getChildPolicyTasks(msBuilder)
+	if err != nil {
+		return err
+	}
 
 	// release the context lock since we no longer need mutable state builder and
 	// the rest of logic is making RPC call, which takes time.
@@ -475,6 +503,12 @@ func (t *transferQueueActiveProcessorImpl) processCloseExecution(task *persisten
 		return err
 	}
 
+	for _, child := range childPolicyTasks {
+		if err = t.applyChildPolicy(child); err != nil {
+			return err
+		}
+	}
+
 	// Record closing in visibility store
 	retentionSeconds := int64(0)
 	domainEntry, err := t.shard.GetDomainCache().GetDomainByID(task.DomainID)
@@ -500,6 +534,98 @@ func (t *transferQueueActiveProcessorImpl) processCloseExecution(task *persisten
 	})
 }
 
+// getChildPolicyTasks returns the started children of a closed workflow execution, which are to be closed according
+// to their child policy.  The new run of a workflow which continued as new does not take over the children of the run,
+// so they are handled as if the workflow closed.  Children which are not started yet are not returned, as their start
+// is dropped once the parent is closed.
+func (t *transferQueueActiveProcessorImpl) getChildPolicyTasks(msBuilder *mutableStateBuilder) ([]childPolicyTask,
+	error) {
+	var childPolicyTasks []childPolicyTask
+	for initiatedID, ci := range msBuilder.pendingChildExecutionInfoIDs {
+		if ci.StartedID == emptyEventID {
+			continue
+		}
+		initiatedEvent, ok := msBuilder.GetChildExecutionInitiatedEvent(initiatedID)
+		if !ok {
+			continue
+		}
+		attributes := initiatedEvent.StartChildWorkflowExecutionInitiatedEventAttributes
+		if attributes.GetChildPolicy() == workflow.ChildPolicyAbandon {
+			continue
+		}
+
+		childDomainID := msBuilder.executionInfo.DomainID
+		if attributes.Domain != nil {
+			domainEntry, err := t.shard.GetDomainCache().GetDomain(attributes.GetDomain())
+			if err != nil {
+				if _, ok := err.(*workflow.EntityNotExistsError); ok {
+					// the domain of the child is gone and so is the child
+					continue
+				}
+				return nil, err
+			}
+			childDomainID = domainEntry.GetInfo().ID
+		}
+		childPolicyTasks = append(childPolicyTasks, childPolicyTask{
+			domainID: childDomainID,
+			execution: workflow.WorkflowExecution{
+				WorkflowId: attributes.WorkflowId,
+				RunId:      common.StringPtr(ci.StartedRunID),
+			},
+			policy: attributes.GetChildPolicy(),
+		})
+	}
+	return childPolicyTasks, nil
+}
+
+// applyChildPolicy terminates or requests the cancellation of a child of a closed workflow execution, a child which
+// is already closed or being canceled is left as is.  Only transient errors are returned, a child the policy cannot be
+// applied to otherwise, e.g. as its domain is active in another cluster, is left as is too rather than failing the
+// close of its parent forever.
+func (t *transferQueueActiveProcessorImpl) applyChildPolicy(task childPolicyTask) error {
+	var op func() error
+	switch task.policy {
+	case workflow.ChildPolicyTerminate:
+		op = func() error {
+			return t.historyClient.TerminateWorkflowExecution(nil, &h.TerminateWorkflowExecutionRequest{
+				DomainUUID: common.StringPtr(task.domainID),
+				TerminateRequest: &workflow.TerminateWorkflowExecutionRequest{
+					Domain:            common.StringPtr(task.domainID),
+					WorkflowExecution: &task.execution,
+					Reason:            common.StringPtr(childPolicyTerminateReason),
+					Identity:          common.StringPtr(identityHistoryService),
+				},
+			})
+		}
+	case workflow.ChildPolicyRequestCancel:
+		op = func() error {
+			_, err := t.historyClient.RequestCancelWorkflowExecution(nil, &h.RequestCancelWorkflowExecutionRequest{
+				DomainUUID: common.StringPtr(task.domainID),
+				CancelRequest: &workflow.RequestCancelWorkflowExecutionRequest{
+					Domain:            common.StringPtr(task.domainID),
+					WorkflowExecution: &task.execution,
+					Identity:          common.StringPtr(identityHistoryService),
+				},
+			})
+			return err
+		}
+	default:
+		return nil
+	}
+
+	err := backoff.Retry(op, persistenceOperationRetryPolicy, common.IsPersistenceTransientError)
+	switch err.(type) {
+	case nil, *workflow.EntityNotExistsError, *workflow.CancellationAlreadyRequestedError:
+		return nil
+	}
+	if common.IsPersistenceTransientError(err) {
+		return err
+	}
+	t.logger.Warnf("Failed to apply child policy to child workflow execution.  WorkflowID: %v, RunID: %v, Error: %v",
+		task.execution.GetWorkflowId(), task.execution.GetRunId(), err)
+	return nil
+}
+
 func (t *transferQueueActiveProcessorImpl) processCancelExecution(task *persistence.TransferTaskInfo) (retError error) {
 	t.metricsClient.IncCounter(metrics.TransferTaskCancelExecutionScope, metrics.TaskRequests)
 	sw := t.metricsClient.StartTimer(metrics.TransferTaskCancelExecutionScope, metrics.TaskLatency)
@@ -582,7 +708,8 @@ func (t *transferQueueActiveProcessorImpl) processCancelExecution(task *persiste
 	}
 
 	op := func() error {
-		return t.historyClient.RequestCancelWorkflowExecution(nil, cancelRequest)
+		_, err := t.historyClient.RequestCancelWorkflowExecution(nil, cancelRequest)
+		return err
 	}
 
 	err = backoff.Retry(op, persistenceOperationRetryPolicy, common.IsPersistenceTransientError)
@@ -831,6 +958,7 @@ func (t *transferQueueActiveProcessorImpl) processStartChildExecution(task *pers
 						RunId:      common.StringPtr(task.RunID),
 					},
 					InitiatedId: common.Int64Ptr(initiatedEventID),
+					Depth:       common.Int32Ptr(msBuilder.executionInfo.Depth),
 				},
 			}
 
@@ -854,6 +982,16 @@ func (t *transferQueueActiveProcessorImpl) processStartChildExecution(task *pers
 			// Child execution is successfully started, record ChildExecutionStartedEvent in parent execution
 			err = t.recordChildExecutionStarted(task, context, attributes, *startResponse.RunId)
 
+			if err == errPendingChildNotFound {
+				// Parent dropped the pending child, e.g. by canceling it, while the start was in flight.  Nothing
+				// tracks the run which just got started, so cancel it.
+				t.requestCancelUntrackedChild(targetDomainID, targetDomain, ci.CreateRequestID,
+					&workflow.WorkflowExecution{
+						WorkflowId: attributes.WorkflowId,
+						RunId:      startResponse.RunId,
+					})
+				return nil
+			}
 			if err != nil {
 				return err
 			}
@@ -902,7 +1040,7 @@ func (t *transferQueueActiveProcessorImpl) recordChildExecutionStarted(task *per
 			initiatedEventID := task.ScheduleID
 			ci, isRunning := msBuilder.GetChildExecutionInfo(initiatedEventID)
 			if !isRunning || ci.StartedID != emptyEventID {
-				return &workflow.EntityNotExistsError{Message: "Pending child execution not found."}
+				return errPendingChildNotFound
 			}
 
 			msBuilder.AddChildWorkflowExecutionStartedEvent(domain,
@@ -915,6 +1053,36 @@ func (t *transferQueueActiveProcessorImpl) recordChildExecutionStarted(task *per
 		})
 }
 
+func (t *transferQueueActiveProcessorImpl) requestCancelUntrackedChild(targetDomainID, targetDomain, requestID string,
+	execution *workflow.WorkflowExecution) {
+
+	cancelRequest := &h.RequestCancelWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(targetDomainID),
+		CancelRequest: &workflow.RequestCancelWorkflowExecutionRequest{
+			Domain:            common.StringPtr(targetDomain),
+			WorkflowExecution: execution,
+			Identity:          common.StringPtr(identityHistoryService),
+			// Reuse the request ID of the child start to dedupe RequestCancelWorkflowExecution calls
+			RequestId: common.StringPtr(requestID),
+		},
+	}
+
+	op := func() error {
+		_, err := t.historyClient.RequestCancelWorkflowExecution(nil, cancelRequest)
+		return err
+	}
+
+	if err := backoff.Retry(op, persistenceOperationRetryPolicy, common.IsPersistenceTransientError); err != nil {
+		switch err.(type) {
+		case *workflow.CancellationAlreadyRequestedError, *workflow.EntityNotExistsError:
+			// child is already canceled or closed
+		default:
+			t.logger.Warnf("Failed to cancel untracked child workflow execution.  WorkflowID: %v, RunID: %v, Error: %v",
+				execution.GetWorkflowId(), execution.GetRunId(), err)
+		}
+	}
+}
+
 func (t *transferQueueActiveProcessorImpl) recordStartChildExecutionFailed(task *persistence.TransferTaskInfo,
 	context *workflowExecutionContext,
 	initiatedAttributes *workflow.StartChildWorkflowExecutionInitiatedEventAttributes) error {
@@ -938,6 +1106,29 @@ func (t *transferQueueActiveProcessorImpl) recordStartChildExecutionFailed(task
 		})
 }
 
+func (t *transferQueueActiveProcessorImpl) recordActivityDispatchDeadlineExceeded(task *persistence.TransferTaskInfo,
+	context *workflowExecutionContext) error {
+
+	return t.updateWorkflowExecution(task.DomainID, context, true,
+		func(msBuilder *mutableStateBuilder) error {
+			if !msBuilder.isWorkflowExecutionRunning() {
+				return &workflow.EntityNotExistsError{Message: "Workflow execution already completed."}
+			}
+
+			ai, isRunning := msBuilder.GetActivityInfo(task.ScheduleID)
+			if !isRunning || ai.StartedID != emptyEventID {
+				return &workflow.EntityNotExistsError{Message: "Pending activity not found."}
+			}
+
+			if msBuilder.AddActivityTaskTimedOutEvent(task.ScheduleID, emptyEventID,
+				workflow.TimeoutTypeScheduleToStart, nil) == nil {
+				return &workflow.InternalServiceError{Message: "Unable to add ActivityTaskTimedOut event to history."}
+			}
+
+			return nil
+		})
+}
+
 // createFirstDecisionTask is used by StartChildExecution transfer task to create the first decision task for
 // child execution.
 func (t *transferQueueActiveProcessorImpl) createFirstDecisionTask(domainID string,
@@ -1084,7 +1275,7 @@ Update_History_Loop:
 
 		if createDecisionTask {
 			// Create a transfer task to schedule a decision task
-			if !msBuilder.HasPendingDecisionTask() {
+			if !msBuilder.HasPendingDecisionTask() && !msBuilder.isPaused() {
 				di := msBuilder.AddDecisionTaskScheduledEvent()
 				transferTasks = append(transferTasks, &persistence.DecisionTask{
 					DomainID:   domainID,
@@ -1124,7 +1315,7 @@ Update_History_Loop:
 		return nil
 	}
 
-	return ErrMaxAttemptsExceeded
+	return context.newMaxAttemptsExceededError()
 }
 
 func (t *transferQueueActiveProcessorImpl) SignalExecutionWithRetry(signalRequest *h.SignalWorkflowExecutionRequest) error {
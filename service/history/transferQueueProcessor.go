@@ -22,6 +22,7 @@ package history
 
 import (
 	"fmt"
+	"strconv"
 	"sync/atomic"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/uber/cadence/client/history"
 	"github.com/uber/cadence/client/matching"
 	"github.com/uber/cadence/common/logging"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
 )
 
@@ -158,11 +160,40 @@ func (t *transferQueueProcessorImpl) completeTransferLoop() {
 					break CompleteLoop
 				}
 			}
+			t.emitQueueLag()
 			timer.Reset(t.config.TransferProcessorCompleteTransferInterval)
 		}
 	}
 }
 
+// emitQueueLag reports the task id lag of the running transfer processors as gauges tagged with the shard and cluster
+func (t *transferQueueProcessorImpl) emitQueueLag() {
+	clusterNames := []string{t.currentClusterName}
+	if t.isGlobalDomainEnabled {
+		for clusterName := range t.standbyTaskProcessors {
+			clusterNames = append(clusterNames, clusterName)
+		}
+	}
+
+	for _, clusterName := range clusterNames {
+		t.shard.GetMetricsClient().Tagged(map[string]string{
+			metrics.ShardTagName:   strconv.Itoa(t.shard.GetShardID()),
+			metrics.ClusterTagName: clusterName,
+		}).UpdateGauge(metrics.TransferQueueProcessorScope, metrics.TransferTaskIDLagGauge,
+			float64(getTransferTaskIDLag(t.shard, clusterName)))
+	}
+}
+
+// getTransferTaskIDLag returns by how many task ids the transfer processor of the cluster trails the tasks generated by
+// the shard
+func getTransferTaskIDLag(shard ShardContext, clusterName string) int64 {
+	lag := shard.GetTransferMaxReadLevel() - shard.GetTransferClusterAckLevel(clusterName)
+	if lag < 0 {
+		return 0
+	}
+	return lag
+}
+
 func (t *transferQueueProcessorImpl) completeTransfer() error {
 	lowerAckLevel := t.shard.GetTransferAckLevel()
 	upperAckLevel := t.activeTaskProcessor.queueAckMgr.getAckLevel()
@@ -31,6 +31,7 @@ import (
 	"github.com/pborman/uuid"
 	"github.com/uber-common/bark"
 	"github.com/uber-go/tally"
+	h "github.com/uber/cadence/.gen/go/history"
 	m "github.com/uber/cadence/.gen/go/matching"
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
@@ -136,6 +137,35 @@ workerPump:
 	}
 }
 
+func (s *transferQueueProcessorSuite) TestSingleDecisionTaskHandedOffOnStart() {
+	domainID := testDomainActiveID
+	workflowExecution := workflow.WorkflowExecution{WorkflowId: common.StringPtr("handed-off-decisiontask-test"),
+		RunId: common.StringPtr("0d00698f-08e1-4d36-a3e2-3bf109f5d2d7")}
+	taskList := "handed-off-decisiontask-queue"
+	match := s.processor.historyService.beginFirstDecisionMatch(domainID, workflowExecution, firstEventID+1)
+	task0, err0 := s.CreateWorkflowExecution(domainID, workflowExecution, taskList, "wType", 20, 10, nil, 3, 0, 2, nil)
+	s.Nil(err0, "No error expected.")
+	s.NotEmpty(task0, "Expected non empty task identifier.")
+	s.processor.historyService.endFirstDecisionMatch(match, true)
+
+	tasksCh := make(chan queueTaskInfo, 10)
+	s.processor.processBatch(tasksCh)
+workerPump:
+	for {
+		select {
+		case t := <-tasksCh:
+			task := t.(*persistence.TransferTaskInfo)
+			s.mockVisibilityMgr.On("RecordWorkflowExecutionStarted", mock.Anything).Once().Return(nil)
+			s.processor.processWithRetry(nil, task)
+		default:
+			break workerPump
+		}
+	}
+	// the decision reached a poller already, so it is not dispatched again
+	s.mockMatching.AssertNotCalled(s.T(), "AddDecisionTask", mock.Anything, mock.Anything)
+	s.mockVisibilityMgr.AssertExpectations(s.T())
+}
+
 func (s *transferQueueProcessorSuite) TestManyTransferTasks() {
 	domainID := testDomainActiveID
 	workflowExecution := workflow.WorkflowExecution{WorkflowId: common.StringPtr("many-transfertasks-test"),
@@ -362,7 +392,7 @@ workerPump:
 			} else if task.TaskType == persistence.TransferTaskTypeCancelExecution {
 				s.logger.Infof("TransferTaskTypeCancelExecution. TargetDomain: %v, TargetWorkflowID: %v, TargetRunID: %v",
 					task.TargetDomainID, task.TargetWorkflowID, task.TargetRunID)
-				s.mockHistoryClient.On("RequestCancelWorkflowExecution", mock.Anything, mock.Anything).Return(nil).Once()
+				s.mockHistoryClient.On("RequestCancelWorkflowExecution", mock.Anything, mock.Anything).Return(nil, nil).Once()
 			}
 			s.processor.processWithRetry(nil, task)
 		default:
@@ -425,7 +455,7 @@ workerPump:
 				}
 			} else if task.TaskType == persistence.TransferTaskTypeCancelExecution {
 				s.mockHistoryClient.On("RequestCancelWorkflowExecution", mock.Anything, mock.Anything).
-					Return(&workflow.EntityNotExistsError{}).Once()
+					Return(nil, &workflow.EntityNotExistsError{}).Once()
 			}
 			s.processor.processWithRetry(nil, task)
 		default:
@@ -659,6 +689,258 @@ workerPump:
 	}
 }
 
+func (s *transferQueueProcessorSuite) TestStartChildExecutionTransferTasksChildCanceledBeforeStarted() {
+	domain := testDomainActiveName
+	domainID := testDomainActiveID
+	workflowID := "start-child-execution-transfertasks-child-canceled-test"
+	runID := "8b3a8f0e-0f4a-4e4b-9d1f-55d5ad0c7e33"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+	taskList := "start-child-execution-transfertasks-child-canceled-queue"
+	identity := "start-child-execution-transfertasks-child-canceled-test"
+
+	tasksCh := s.createChildExecutionState(domain, domainID, workflowExecution, taskList, identity)
+	childRunID := "2e0d1a5c-7a39-4c51-b4b6-0f9e4a3c8d21"
+workerPump:
+	for {
+		select {
+		case t := <-tasksCh:
+			task := t.(*persistence.TransferTaskInfo)
+			if task.TaskType == persistence.TransferTaskTypeStartChildExecution {
+				// parent cancels the pending child while the start request is in flight
+				s.mockHistoryClient.On("StartWorkflowExecution", mock.Anything, mock.Anything).Once().Return(
+					&workflow.StartWorkflowExecutionResponse{
+						RunId: common.StringPtr(childRunID),
+					}, nil).Run(func(args mock.Arguments) {
+					state, err := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+					s.Nil(err)
+					updatedInfo := copyWorkflowExecutionInfo(state.ExecutionInfo)
+					updatedInfo.NextEventID++
+					err = s.WorkflowMgr.UpdateWorkflowExecution(&persistence.UpdateWorkflowExecutionRequest{
+						ExecutionInfo:            updatedInfo,
+						Condition:                state.ExecutionInfo.NextEventID,
+						DeleteChildExecutionInfo: common.Int64Ptr(task.ScheduleID),
+						RangeID:                  s.ShardInfo.RangeID,
+					})
+					s.Nil(err)
+				})
+				s.mockHistoryClient.On("RequestCancelWorkflowExecution", mock.Anything,
+					mock.MatchedBy(func(request *h.RequestCancelWorkflowExecutionRequest) bool {
+						return request.CancelRequest.WorkflowExecution.GetWorkflowId() == task.TargetWorkflowID &&
+							request.CancelRequest.WorkflowExecution.GetRunId() == childRunID
+					})).Once().Return(nil, nil)
+			}
+			s.processor.processWithRetry(nil, task)
+		default:
+			break workerPump
+		}
+	}
+
+	// the started child is not recorded in the parent
+	state, err := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+	s.Nil(err)
+	s.Equal(0, len(state.ChildExecutionInfos))
+}
+
+func (s *transferQueueProcessorSuite) TestCloseExecutionTransferTaskTimedOutTerminatesChildren() {
+	domain := testDomainActiveName
+	domainID := testDomainActiveID
+	workflowID := "close-execution-transfertasks-timedout-test"
+	runID := "5f0c3b9e-3d7b-4d0c-9a1f-2b6e7c8d9e01"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+	taskList := "close-execution-transfertasks-timedout-queue"
+	identity := "close-execution-transfertasks-timedout-test"
+	_, err0 := s.CreateWorkflowExecution(domainID, workflowExecution, taskList, "wType", 20, 10, nil, 3, 0, 2, nil)
+	s.Nil(err0, "No error expected.")
+
+	builder := newMutableStateBuilder(s.ShardContext.GetConfig(), s.logger)
+	info1, _ := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+	builder.Load(info1)
+	startedEvent := addDecisionTaskStartedEvent(builder, int64(2), taskList, identity)
+	completedEvent := addDecisionTaskCompletedEvent(builder, int64(2), *startedEvent.EventId, nil, identity)
+	children := map[string]string{
+		"close-execution-transfertasks-timedout-child1": "0c7d2c0e-4b5a-4f4e-8a61-3e2f1d0c9b81",
+		"close-execution-transfertasks-timedout-child2": "7a9e5d3c-1b2f-4e6d-9c8b-6f5a4e3d2c12",
+	}
+	for childWorkflowID, childRunID := range children {
+		initiatedEvent, _ := addStartChildWorkflowExecutionInitiatedEvent(builder, *completedEvent.EventId, uuid.New(),
+			domain, childWorkflowID, "child-workflow-type", taskList, nil, int32(100), int32(10))
+		addChildWorkflowExecutionStartedEvent(builder, *initiatedEvent.EventId, domain, childWorkflowID, childRunID,
+			"child-workflow-type")
+	}
+	s.NotNil(builder.AddTimeoutWorkflowEvent())
+
+	updatedInfo := copyWorkflowExecutionInfo(builder.executionInfo)
+	err1 := s.UpdateWorkflowExecutionForChildExecutionsInitiated(updatedInfo, int64(3),
+		[]persistence.Task{&persistence.CloseExecutionTask{TaskID: s.GetNextSequenceNumber()}},
+		convertUpdateChildExecutionInfos(builder.updateChildExecutionInfos))
+	s.Nil(err1, "No error expected.")
+
+	tasksCh := make(chan queueTaskInfo, 10)
+	s.processor.processBatch(tasksCh)
+workerPump:
+	for {
+		select {
+		case t := <-tasksCh:
+			task := t.(*persistence.TransferTaskInfo)
+			if task.TaskType == persistence.TransferTaskTypeDecisionTask {
+				s.mockMatching.On("AddDecisionTask", mock.Anything, mock.Anything).Once().Return(nil)
+				s.mockVisibilityMgr.On("RecordWorkflowExecutionStarted", mock.Anything).Once().Return(nil)
+			} else if task.TaskType == persistence.TransferTaskTypeCloseExecution {
+				for childWorkflowID, childRunID := range children {
+					childExecution := workflow.WorkflowExecution{
+						WorkflowId: common.StringPtr(childWorkflowID),
+						RunId:      common.StringPtr(childRunID),
+					}
+					s.mockHistoryClient.On("TerminateWorkflowExecution", mock.Anything,
+						mock.MatchedBy(func(request *h.TerminateWorkflowExecutionRequest) bool {
+							return request.GetDomainUUID() == domainID &&
+								request.TerminateRequest.WorkflowExecution.Equals(&childExecution)
+						})).Once().Return(nil)
+				}
+				s.mockVisibilityMgr.On("RecordWorkflowExecutionClosed", mock.Anything).Once().Return(nil)
+			}
+			s.processor.processWithRetry(nil, task)
+		default:
+			break workerPump
+		}
+	}
+}
+
+func (s *transferQueueProcessorSuite) TestCloseExecutionTransferTaskCompletedChildDomainNotActive() {
+	domain := testDomainActiveName
+	domainID := testDomainActiveID
+	workflowID := "close-execution-transfertasks-completed-test"
+	runID := "2d4f6b8a-0c1e-4a3b-8d5f-7e9a1c3b5d07"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+	taskList := "close-execution-transfertasks-completed-queue"
+	identity := "close-execution-transfertasks-completed-test"
+	_, err0 := s.CreateWorkflowExecution(domainID, workflowExecution, taskList, "wType", 20, 10, nil, 3, 0, 2, nil)
+	s.Nil(err0, "No error expected.")
+
+	builder := newMutableStateBuilder(s.ShardContext.GetConfig(), s.logger)
+	info1, _ := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+	builder.Load(info1)
+	startedEvent := addDecisionTaskStartedEvent(builder, int64(2), taskList, identity)
+	completedEvent := addDecisionTaskCompletedEvent(builder, int64(2), *startedEvent.EventId, nil, identity)
+	childWorkflowID := "close-execution-transfertasks-completed-child"
+	childRunID := "8f1a3c5e-7b9d-4f2a-9c4e-6a8b0d2f4a18"
+	initiatedEvent, _ := addStartChildWorkflowExecutionInitiatedEvent(builder, *completedEvent.EventId, uuid.New(),
+		domain, childWorkflowID, "child-workflow-type", taskList, nil, int32(100), int32(10))
+	addChildWorkflowExecutionStartedEvent(builder, *initiatedEvent.EventId, domain, childWorkflowID, childRunID,
+		"child-workflow-type")
+	s.NotNil(addCompleteWorkflowEvent(builder, *completedEvent.EventId, nil))
+
+	updatedInfo := copyWorkflowExecutionInfo(builder.executionInfo)
+	err1 := s.UpdateWorkflowExecutionForChildExecutionsInitiated(updatedInfo, int64(3),
+		[]persistence.Task{&persistence.CloseExecutionTask{TaskID: s.GetNextSequenceNumber()}},
+		convertUpdateChildExecutionInfos(builder.updateChildExecutionInfos))
+	s.Nil(err1, "No error expected.")
+
+	childExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(childWorkflowID),
+		RunId:      common.StringPtr(childRunID),
+	}
+	tasksCh := make(chan queueTaskInfo, 10)
+	s.processor.processBatch(tasksCh)
+workerPump:
+	for {
+		select {
+		case t := <-tasksCh:
+			task := t.(*persistence.TransferTaskInfo)
+			if task.TaskType == persistence.TransferTaskTypeDecisionTask {
+				s.mockMatching.On("AddDecisionTask", mock.Anything, mock.Anything).Once().Return(nil)
+				s.mockVisibilityMgr.On("RecordWorkflowExecutionStarted", mock.Anything).Once().Return(nil)
+			} else if task.TaskType == persistence.TransferTaskTypeCloseExecution {
+				// the child policy applies to completed workflows too, and a child it cannot be applied to does not
+				// keep the close of the parent from being recorded
+				s.mockHistoryClient.On("TerminateWorkflowExecution", mock.Anything,
+					mock.MatchedBy(func(request *h.TerminateWorkflowExecutionRequest) bool {
+						return request.GetDomainUUID() == domainID &&
+							request.TerminateRequest.WorkflowExecution.Equals(&childExecution)
+					})).Once().Return(&workflow.DomainNotActiveError{Message: "domain not active"})
+				s.mockVisibilityMgr.On("RecordWorkflowExecutionClosed", mock.Anything).Once().Return(nil)
+			}
+			s.processor.processWithRetry(nil, task)
+		default:
+			break workerPump
+		}
+	}
+}
+
+func (s *transferQueueProcessorSuite) TestCloseExecutionTransferTaskContinuedAsNewCancelsChildren() {
+	domain := testDomainActiveName
+	domainID := testDomainActiveID
+	workflowID := "close-execution-transfertasks-continueasnew-test"
+	runID := "9b1e4f2a-6c3d-4e8b-a5f7-1d2c3b4a5e06"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+	taskList := "close-execution-transfertasks-continueasnew-queue"
+	identity := "close-execution-transfertasks-continueasnew-test"
+	_, err0 := s.CreateWorkflowExecution(domainID, workflowExecution, taskList, "wType", 20, 10, nil, 3, 0, 2, nil)
+	s.Nil(err0, "No error expected.")
+
+	builder := newMutableStateBuilder(s.ShardContext.GetConfig(), s.logger)
+	info1, _ := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+	builder.Load(info1)
+	startedEvent := addDecisionTaskStartedEvent(builder, int64(2), taskList, identity)
+	completedEvent := addDecisionTaskCompletedEvent(builder, int64(2), *startedEvent.EventId, nil, identity)
+	childWorkflowID := "close-execution-transfertasks-continueasnew-child"
+	childRunID := "3e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c63"
+	initiatedEvent, _ := addStartChildWorkflowExecutionInitiatedEventWithPolicy(builder, *completedEvent.EventId,
+		uuid.New(), domain, childWorkflowID, "child-workflow-type", taskList, nil, int32(100), int32(10),
+		workflow.ChildPolicyRequestCancel)
+	addChildWorkflowExecutionStartedEvent(builder, *initiatedEvent.EventId, domain, childWorkflowID, childRunID,
+		"child-workflow-type")
+	_, _, err := builder.AddContinueAsNewEvent(*completedEvent.EventId, domainID, domain, uuid.New(), "",
+		&workflow.ContinueAsNewWorkflowExecutionDecisionAttributes{})
+	s.Nil(err)
+
+	updatedInfo := copyWorkflowExecutionInfo(builder.executionInfo)
+	err1 := s.UpdateWorkflowExecutionForChildExecutionsInitiated(updatedInfo, int64(3),
+		[]persistence.Task{&persistence.CloseExecutionTask{TaskID: s.GetNextSequenceNumber()}},
+		convertUpdateChildExecutionInfos(builder.updateChildExecutionInfos))
+	s.Nil(err1, "No error expected.")
+
+	childExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(childWorkflowID),
+		RunId:      common.StringPtr(childRunID),
+	}
+	tasksCh := make(chan queueTaskInfo, 10)
+	s.processor.processBatch(tasksCh)
+workerPump:
+	for {
+		select {
+		case t := <-tasksCh:
+			task := t.(*persistence.TransferTaskInfo)
+			if task.TaskType == persistence.TransferTaskTypeDecisionTask {
+				s.mockMatching.On("AddDecisionTask", mock.Anything, mock.Anything).Once().Return(nil)
+				s.mockVisibilityMgr.On("RecordWorkflowExecutionStarted", mock.Anything).Once().Return(nil)
+			} else if task.TaskType == persistence.TransferTaskTypeCloseExecution {
+				s.mockHistoryClient.On("RequestCancelWorkflowExecution", mock.Anything,
+					mock.MatchedBy(func(request *h.RequestCancelWorkflowExecutionRequest) bool {
+						return request.GetDomainUUID() == domainID &&
+							request.CancelRequest.WorkflowExecution.Equals(&childExecution)
+					})).Once().Return(nil, nil)
+				s.mockVisibilityMgr.On("RecordWorkflowExecutionClosed", mock.Anything).Once().Return(nil)
+			}
+			s.processor.processWithRetry(nil, task)
+		default:
+			break workerPump
+		}
+	}
+}
+
 func (s *transferQueueProcessorSuite) createChildExecutionState(domain, domainID string,
 	workflowExecution workflow.WorkflowExecution, taskList, identity string) chan queueTaskInfo {
 	_, err0 := s.CreateWorkflowExecution(domainID, workflowExecution, taskList, "wType", 20, 10, nil, 3, 0, 2, nil)
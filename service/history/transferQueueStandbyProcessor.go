@@ -337,7 +337,7 @@ Process_Loop:
 
 		return fn(msBuilder)
 	}
-	return ErrMaxAttemptsExceeded
+	return context.newMaxAttemptsExceededError()
 }
 
 func (t *transferQueueStandbyProcessorImpl) getDomainIDAndWorkflowExecution(transferTask *persistence.TransferTaskInfo) (string, workflow.WorkflowExecution) {
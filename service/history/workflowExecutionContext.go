@@ -22,6 +22,7 @@ package history
 
 import (
 	"fmt"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -51,6 +52,15 @@ type (
 		msBuilder       *mutableStateBuilder
 		updateCondition int64
 		deleteTimerTask persistence.Task
+		lastConflict    *updateConflict
+	}
+
+	// updateConflict describes a conditional write of a workflow execution which failed
+	updateConflict struct {
+		operation   string
+		condition   int64
+		nextEventID int64
+		details     string
 	}
 )
 
@@ -102,6 +112,26 @@ func (c *workflowExecutionContext) loadWorkflowExecution() (*mutableStateBuilder
 	return msBuilder, nil
 }
 
+// reloadWorkflowExecution reads the mutable state of the execution straight from persistence, bypassing the cached
+// copy.  The cached copy is replaced with the one read when refresh is set, and kept otherwise.  It also returns
+// whether the cached copy disagreed with persistence on the next event ID.
+func (c *workflowExecutionContext) reloadWorkflowExecution(refresh bool) (*mutableStateBuilder, bool, error) {
+	cachedBuilder := c.msBuilder
+	cachedCondition := c.updateCondition
+	c.msBuilder = nil
+	msBuilder, err := c.loadWorkflowExecution()
+	if err != nil || !refresh {
+		c.msBuilder = cachedBuilder
+		c.updateCondition = cachedCondition
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	stale := cachedBuilder != nil && cachedBuilder.GetNextEventID() != msBuilder.GetNextEventID()
+	return msBuilder, stale, nil
+}
+
 func (c *workflowExecutionContext) updateWorkflowExecutionWithContext(context []byte, transferTasks []persistence.Task,
 	timerTasks []persistence.Task, transactionID int64) error {
 	c.msBuilder.executionInfo.ExecutionContext = context
@@ -183,9 +213,11 @@ func (c *workflowExecutionContext) updateHelper(builder *historyBuilder, transfe
 			TransactionID: transactionID,
 			FirstEventID:  *firstEvent.EventId,
 			Events:        serializedHistory,
+			KeyID:         c.msBuilder.executionInfo.KeyID,
 		}); err0 != nil {
 			switch err0.(type) {
 			case *persistence.ConditionFailedError:
+				c.recordConflict(logging.TagValueStoreOperationAppendHistoryEvents, err0)
 				return ErrConflict
 			}
 
@@ -255,6 +287,7 @@ func (c *workflowExecutionContext) updateHelper(builder *historyBuilder, transfe
 	}); err1 != nil {
 		switch err1.(type) {
 		case *persistence.ConditionFailedError:
+			c.recordConflict(logging.TagValueStoreOperationUpdateWorkflowExecution, err1)
 			return ErrConflict
 		}
 
@@ -293,14 +326,32 @@ func (c *workflowExecutionContext) continueAsNewWorkflowExecution(context []byte
 	}
 
 	err2 := c.updateWorkflowExecutionWithContext(context, transferTasks, timerTasks, transactionID)
-
-	if err2 != nil {
-		// TODO: Delete new execution if update fails due to conflict or shard being lost
+	if err2 == ErrConflict {
+		// the new run is not created when the update of the run it continues is rejected, and a retry starts yet
+		// another run, so the history appended for it is deleted rather than left behind
+		c.deleteNewRunHistory(newStateBuilder)
 	}
 
 	return err2
 }
 
+// deleteNewRunHistory deletes the history appended for a new run which was not created, failures are only logged as
+// the history of a run which does not exist is never read
+func (c *workflowExecutionContext) deleteNewRunHistory(newStateBuilder *mutableStateBuilder) {
+	newExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(newStateBuilder.executionInfo.WorkflowID),
+		RunId:      common.StringPtr(newStateBuilder.executionInfo.RunID),
+	}
+	if err := c.shard.GetHistoryManager().DeleteWorkflowExecutionHistory(
+		&persistence.DeleteWorkflowExecutionHistoryRequest{
+			DomainID:  newStateBuilder.executionInfo.DomainID,
+			Execution: newExecution,
+		}); err != nil {
+		c.logger.Warnf("Failed to delete history of new run which was not created.  WorkflowID: %v, RunID: %v, Error: %v",
+			newExecution.GetWorkflowId(), newExecution.GetRunId(), err)
+	}
+}
+
 func (c *workflowExecutionContext) continueAsNewWorkflowExecutionHelper(context []byte, newStateBuilder *mutableStateBuilder,
 	transferTasks []persistence.Task, timerTasks []persistence.Task, transactionID int64) error {
 
@@ -320,13 +371,23 @@ func (c *workflowExecutionContext) continueAsNewWorkflowExecutionHelper(context
 		return serializedError
 	}
 
-	return c.shard.AppendHistoryEvents(&persistence.AppendHistoryEventsRequest{
+	err := c.shard.AppendHistoryEvents(&persistence.AppendHistoryEventsRequest{
 		DomainID:      domainID,
 		Execution:     newExecution,
 		TransactionID: transactionID,
 		FirstEventID:  *firstEvent.EventId,
 		Events:        serializedHistory,
+		KeyID:         newStateBuilder.executionInfo.KeyID,
 	})
+	if err != nil {
+		return err
+	}
+	// the new run is created by the update of the run it continues, which has to persist its last batch as well
+	newStateBuilder.executionInfo.LastFirstEventID = *firstEvent.EventId
+	if c.msBuilder != nil && c.msBuilder.continueAsNew != nil {
+		c.msBuilder.continueAsNew.LastFirstEventID = *firstEvent.EventId
+	}
+	return nil
 }
 
 func (c *workflowExecutionContext) getWorkflowExecutionWithRetry(
@@ -359,3 +420,33 @@ func (c *workflowExecutionContext) updateWorkflowExecutionWithRetry(
 func (c *workflowExecutionContext) clear() {
 	c.msBuilder = nil
 }
+
+func (c *workflowExecutionContext) recordConflict(operation string, err error) {
+	c.lastConflict = &updateConflict{
+		operation:   operation,
+		condition:   c.updateCondition,
+		nextEventID: c.msBuilder.executionInfo.NextEventID,
+		details:     err.Error(),
+	}
+}
+
+// getLastConflict returns the last conditional write of the execution which failed, nil if none did
+func (c *workflowExecutionContext) getLastConflict() *updateConflict {
+	return c.lastConflict
+}
+
+// newMaxAttemptsExceededError returns ErrMaxAttemptsExceeded for an update of the execution which kept conflicting
+// until it ran out of attempts.  A sample of them is logged with the last conflict, which stays available from
+// getLastConflict.
+func (c *workflowExecutionContext) newMaxAttemptsExceededError() error {
+	if c.lastConflict != nil && rand.Float64() < c.shard.GetConfig().UpdateConflictLogSampleRate {
+		logging.LogUpdateConflictsExceededEvent(c.logger, c.domainID, c.workflowExecution.GetWorkflowId(),
+			c.workflowExecution.GetRunId(), c.lastConflict.String())
+	}
+	return ErrMaxAttemptsExceeded
+}
+
+func (u *updateConflict) String() string {
+	return fmt.Sprintf("{Operation: %v, Condition: %v, NextEventID: %v, Details: %v}", u.operation, u.condition,
+		u.nextEventID, u.details)
+}